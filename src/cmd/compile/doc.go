@@ -40,8 +40,28 @@ Flags:
 		Print assembly listing to standard output (code only).
 	-S -S
 		Print assembly listing to standard output (code and data).
+	-S=3
+		Print assembly listing to standard output (code and data), with
+		each instruction's machine encoding and relocations, under the
+		source line it was compiled from.
 	-V
 		Print compiler version and exit.
+	-Wall
+		Enable all warnings. Warnings report code that is valid but
+		likely wrong, as "file:line:col: warning: message [-Wcategory]".
+		Each category is enabled by its own flag, listed below.
+	-Wconversion
+		Warn about suspicious conversions, such as of an int to a string.
+	-Werror
+		Report enabled warnings as errors.
+	-Wpragma
+		Warn about misused compiler directives, such as unknown //go:
+		directives, which the compiler ignores.
+	-Wsymcollide
+		Warn about exported names that are distinct in Go but collide
+		when case is ignored or compatibility characters are replaced
+		by the characters they stand for, as on case-insensitive file
+		systems.
 	-asmhdr file
 		Write assembly header to file.
 	-asan
@@ -56,15 +76,42 @@ Flags:
 		Assume package has no non-Go components.
 	-cpuprofile file
 		Write a CPU profile for the compilation to file.
+	-diagstream file
+		Write each error and warning to file as soon as it is reported,
+		as a line with the JSON object -jsondiag would print, so that
+		an editor can show the errors of a large package while it is
+		being compiled. The diagnostics come in the order they are
+		reported and -maxerrors does not apply. File is typically a
+		named pipe the editor reads from; if it is "-", the compiler
+		writes the stream to standard output in place of its other
+		diagnostics.
 	-dynlink
 		Allow references to Go symbols in shared libraries (experimental).
 	-e
-		Remove the limit on the number of errors reported (default limit is 10).
+		Remove the limit on the number of errors reported; same as -maxerrors=0.
+	-errcatalog
+		Print the catalog of error codes as a JSON array and exit.
+		Each entry has the fields code, name and summary.
+	-errcodes
+		Append the error code, as in "undefined: x [E0002]", to each
+		error message of a class listed in the catalog. Codes are
+		stable across releases; message texts are not.
+	-errsummary
+		After the errors, print how many there were in the package and
+		in each file, as in "3 errors in pkg/foo: 2 in a.go, 1 in b.go".
 	-goversion string
 		Specify required go tool version of the runtime.
 		Exits when the runtime go version does not match goversion.
 	-h
 		Halt with a stack trace at the first error detected.
+	-hyperlinks url
+		Make the positions of errors hyperlinks to url, in which {file}
+		is replaced by the absolute path of the file, and {line} and
+		{col} by the line and column, as in
+		-hyperlinks=vscode://file{file}:{line}:{col} (default
+		file://{file}). Set it to "" for plain positions. Ignored unless
+		the compiler writes to a terminal known to show OSC 8
+		hyperlinks.
 	-importcfg file
 		Read import configuration from file.
 		In the file, set importmap, packagefile to specify import resolution.
@@ -74,6 +121,23 @@ Flags:
 	-installsuffix suffix
 		Look for packages in $GOROOT/pkg/$GOOS_$GOARCH_suffix
 		instead of $GOROOT/pkg/$GOOS_$GOARCH.
+	-instlimit n
+		Report an error if the package instantiates generic functions
+		and methods more than n times, or never if n is 0 (the default).
+		The error shows the chain of instantiations, from a reference
+		in non-generic code, that led to the last one.
+	-jsondiag
+		Print errors and warnings to standard output as JSON objects,
+		one per line, with the fields pos (file, line, col), end (the
+		position just past the source text at pos, if known),
+		severity ("error" or "warning"), code (see -errcodes), message
+		and related (notes pointing at other source positions, each
+		with a pos and a message). Warnings also have the field category,
+		their -W category. For some errors, such as unused imports
+		and variables, the field fixes lists suggested fixes, each
+		with a message and edits that replace the text from pos to
+		end with new_text; edit positions ignore //line directives.
+		With go build, pass it as -gcflags=-jsondiag.
 	-l
 		Disable inlining.
 	-lang version
@@ -86,7 +150,17 @@ Flags:
 		linker and compiler input.
 	-m
 		Print optimization decisions. Higher values or repetition
-		produce more detail.
+		produce more detail. -d=escreport reports, instead of just
+		that a value escapes to the heap, the chain of flows that
+		makes it escape; -d=escreport=2 reports it as JSON.
+	-maxerrors n
+		Stop after reporting n errors, or never if n is 0 (default 10).
+		The last line then says "too many errors". The errors found
+		while compiling functions, which happens concurrently with -c,
+		are only counted once all functions are compiled; the first n
+		of them in source order are reported, and the last line says
+		how many were left out, as in
+		"x.go:12:2: too many errors; 7 more not shown".
 	-memprofile file
 		Write memory profile for the compilation to file.
 	-memprofilerate rate
@@ -104,18 +178,57 @@ Flags:
 		and diagnose imports that would cause a circular dependency.
 	-pack
 		Write a package (archive) file rather than an object file
+	-pgoprofile file
+		Read a CPU profile of the program, as written by runtime/pprof,
+		from file. Profile-guided optimizations use it to lay out the
+		basic blocks of each function: the successor of a branch that
+		ran more often falls through, and blocks that did not run are
+		moved to the end. -d=pgolayout reports the chosen layouts.
+		They also inline the calls of the hottest call sites, which
+		account for -d=pgoinlinecdf percent (default 99) of the call
+		samples, if their callees cost up to -d=pgoinlinebudgetmult
+		times (default 25) the usual inlining budget. -d=pgoinline
+		reports the hot call sites and the values used.
+	-quiet
+		Report only the first error in each file, and a summary as
+		with -errsummary.
 	-race
 		Compile with race detector enabled.
+	-related
+		After each error, print the other positions it involves, such
+		as the declaration of a mismatched type, on indented "see also"
+		lines. Without it, they appear only with -jsondiag and -sarif.
 	-s
 		Warn about composite literals that can be simplified.
+	-sarif file
+		Also write the errors and warnings to file as a SARIF 2.1.0 log,
+		for code scanning tools. Its rules describe the error codes
+		(see -errcatalog) and the warning categories (see -Wall), and
+		its results carry related locations and suggested fixes as
+		with -jsondiag. If file is a directory, the log is written to
+		a file in it named for the package path, escaped as a URL
+		path element, and ".sarif", so that with go build,
+		-gcflags=all=-sarif=dir writes a log for each package.
 	-shared
 		Generate code that can be linked into a shared library.
+	-snippets
+		Below each error, show its source line, with a caret under the
+		error position and, where the compiler knows where the name or
+		literal there ends, tildes under the rest of it. Ignored unless
+		the compiler writes to a terminal, as it does not under go build.
 	-spectre list
 		Enable spectre mitigations in list (all, index, ret).
 	-traceprofile file
 		Write an execution trace to file.
 	-trimpath prefix
 		Remove prefix from recorded source file paths.
+	-typesnapshot file
+		Write the named types declared at package level to file, for
+		analysis tools run with go build -toolexec. The encoding is
+		documented in cmd/compile/internal/types/encode.go. It refers
+		to the predeclared types by their index in a table of the
+		compiler, so it is only meaningful to tools built from the
+		same Go release.
 
 Flags related to debugging information:
 
@@ -232,6 +345,29 @@ It specifies that calls to the function should not be inlined, overriding
 the compiler's usual optimization rules. This is typically only needed
 for special runtime functions or when debugging the compiler.
 
+	//go:inlinebudget n
+
+The //go:inlinebudget directive must be followed by a function declaration.
+It specifies that the function can be inlined if its inlining cost is at most
+n, instead of the usual budget of 80, for small functions that are just over
+the budget; n can be at most 320. It cannot be combined with //go:noinline or
+//go:cold. The -m flag reports the cost of the functions that can be inlined,
+and when the directive has no effect, because the function cannot be inlined
+anyway or is within the usual budget.
+
+	//go:stencil
+
+The //go:stencil directive must be followed by the declaration of a generic
+function or of a method of a generic type. Normally, the compiler shares the
+code of the function between type arguments of the same underlying type, such
+as int and a defined type with underlying type int, and between all pointer
+type arguments, and passes the code a dictionary that describes the actual
+type arguments. The directive specifies that calls of the function with
+concrete type arguments use code generated for those type arguments alone.
+Calls from the code of another generic function with arguments that depend on
+its type parameters still share code. This increases code size, so it should
+be used only for performance-critical functions.
+
 	//go:norace
 
 The //go:norace directive must be followed by a function declaration.
@@ -246,6 +382,40 @@ It specifies that the function must omit its usual stack overflow check.
 This is most commonly used by low-level runtime code invoked
 at times when it is unsafe for the calling goroutine to be preempted.
 
+	//go:likely
+	//go:unlikely
+
+The //go:likely and //go:unlikely directives must be followed by an if
+statement. They specify that the condition of the statement is usually true,
+or usually false, as for error paths and assertion failures. The compiler
+lays out and allocates registers for the code of the usual branch first.
+Without a profile (see -pgoprofile), the compiler guesses the likely branch,
+so these are only needed where it guesses wrong in performance-critical code.
+
+	//go:unroll n
+
+The //go:unroll directive must be followed by a for statement. It specifies
+that the loop should be unrolled n times, to run n iterations of its body at
+a time, or completely if it runs at most n iterations; n of 1 keeps the loop
+from being unrolled. Only counted loops whose body is a single block after
+bounds check elimination are unrolled; -d=unroll reports which are.
+
+	//go:cold
+
+The //go:cold directive must be followed by a function declaration.
+It specifies that the function is rarely called, as for one that reports an
+error. The function is not inlined, branches that call it are unlikely and
+laid out at the end of the calling function, and the linker places it after
+the other functions of the program, away from the hot code. With a profile
+(see -pgoprofile), functions in the stack of some of its samples, but at
+most one in a thousand, are also placed there, and branches that call them
+are unlikely. Functions in no sample are not, as the profile may just not
+cover them.
+
+Cold blocks stay in the function that contains them: the compiler does not
+outline them into functions of their own. To move rarely executed code out of
+a hot function, put it in a separate function marked //go:cold.
+
 	//go:linkname localname [importpath.name]
 
 This special directive does not apply to the Go code that follows it.
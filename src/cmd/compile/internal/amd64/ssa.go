@@ -248,7 +248,9 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssa.OpAMD64RORQ, ssa.OpAMD64RORL, ssa.OpAMD64RORW, ssa.OpAMD64RORB,
 		ssa.OpAMD64ADDSS, ssa.OpAMD64ADDSD, ssa.OpAMD64SUBSS, ssa.OpAMD64SUBSD,
 		ssa.OpAMD64MULSS, ssa.OpAMD64MULSD, ssa.OpAMD64DIVSS, ssa.OpAMD64DIVSD,
-		ssa.OpAMD64PXOR,
+		ssa.OpAMD64PXOR, ssa.OpAMD64PAND, ssa.OpAMD64POR,
+		ssa.OpAMD64PADDB, ssa.OpAMD64PADDW, ssa.OpAMD64PADDL, ssa.OpAMD64PADDQ,
+		ssa.OpAMD64PSUBB, ssa.OpAMD64PSUBW, ssa.OpAMD64PSUBL, ssa.OpAMD64PSUBQ,
 		ssa.OpAMD64BTSL, ssa.OpAMD64BTSQ,
 		ssa.OpAMD64BTCL, ssa.OpAMD64BTCQ,
 		ssa.OpAMD64BTRL, ssa.OpAMD64BTRQ:
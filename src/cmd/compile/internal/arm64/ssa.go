@@ -49,6 +49,8 @@ func loadByType(t *types.Type) obj.As {
 			}
 		case 8:
 			return arm64.AMOVD
+		case 16:
+			return arm64.AFMOVQ
 		}
 	}
 	panic("bad load type")
@@ -73,11 +75,19 @@ func storeByType(t *types.Type) obj.As {
 			return arm64.AMOVW
 		case 8:
 			return arm64.AMOVD
+		case 16:
+			return arm64.AFMOVQ
 		}
 	}
 	panic("bad store type")
 }
 
+// vecArng returns the vector register with arrangement arng that
+// overlaps the floating point register r, as in V1.D2 for F1.
+func vecArng(r, arng int16) int16 {
+	return (r-arm64.REG_F0)&31 + arm64.REG_ARNG + (arng&15)<<5
+}
+
 // makeshift encodes a register shifted by a constant, used as an Offset in Prog
 func makeshift(v *ssa.Value, reg int16, typ int64, s int64) int64 {
 	if s < 0 || s >= 64 {
@@ -129,6 +139,15 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		if x == y {
 			return
 		}
+		if v.Type == types.TypeInt128 {
+			// A vector, in a whole V register.
+			p := s.Prog(arm64.AVMOV)
+			p.From.Type = obj.TYPE_REG
+			p.From.Reg = vecArng(x, arm64.ARNG_16B)
+			p.To.Type = obj.TYPE_REG
+			p.To.Reg = vecArng(y, arm64.ARNG_16B)
+			return
+		}
 		as := arm64.AMOVD
 		if v.Type.IsFloat() {
 			switch v.Type.Size() {
@@ -441,7 +460,8 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssa.OpARM64MOVWUload,
 		ssa.OpARM64MOVDload,
 		ssa.OpARM64FMOVSload,
-		ssa.OpARM64FMOVDload:
+		ssa.OpARM64FMOVDload,
+		ssa.OpARM64FMOVQload:
 		p := s.Prog(v.Op.Asm())
 		p.From.Type = obj.TYPE_MEM
 		p.From.Reg = v.Args[0].Reg()
@@ -483,6 +503,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssa.OpARM64MOVDstore,
 		ssa.OpARM64FMOVSstore,
 		ssa.OpARM64FMOVDstore,
+		ssa.OpARM64FMOVQstore,
 		ssa.OpARM64STLRB,
 		ssa.OpARM64STLR,
 		ssa.OpARM64STLRW:
@@ -966,6 +987,24 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		p.From.Reg = (v.Args[0].Reg()-arm64.REG_F0)&31 + arm64.REG_ARNG + ((arm64.ARNG_8B & 15) << 5)
 		p.To.Type = obj.TYPE_REG
 		p.To.Reg = (v.Reg()-arm64.REG_F0)&31 + arm64.REG_ARNG + ((arm64.ARNG_8B & 15) << 5)
+	case ssa.OpARM64VADDB, ssa.OpARM64VADDH, ssa.OpARM64VADDS, ssa.OpARM64VADDD,
+		ssa.OpARM64VSUBB, ssa.OpARM64VSUBH, ssa.OpARM64VSUBS, ssa.OpARM64VSUBD,
+		ssa.OpARM64VAND, ssa.OpARM64VORR, ssa.OpARM64VEOR:
+		arng := int16(arm64.ARNG_16B)
+		switch v.Op {
+		case ssa.OpARM64VADDH, ssa.OpARM64VSUBH:
+			arng = arm64.ARNG_8H
+		case ssa.OpARM64VADDS, ssa.OpARM64VSUBS:
+			arng = arm64.ARNG_4S
+		case ssa.OpARM64VADDD, ssa.OpARM64VSUBD:
+			arng = arm64.ARNG_2D
+		}
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_REG
+		p.From.Reg = vecArng(v.Args[1].Reg(), arng)
+		p.Reg = vecArng(v.Args[0].Reg(), arng)
+		p.To.Type = obj.TYPE_REG
+		p.To.Reg = vecArng(v.Reg(), arng)
 	case ssa.OpARM64VUADDLV:
 		p := s.Prog(v.Op.Asm())
 		p.From.Type = obj.TYPE_REG
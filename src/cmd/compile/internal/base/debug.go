@@ -16,31 +16,64 @@ var Debug DebugFlags
 // The -d option takes a comma-separated list of settings.
 // Each setting is name=value; for ints, name is short for name=1.
 type DebugFlags struct {
+	AliasStats           int    `help:"report the queries of the alias oracle of each function and how many it answered with no alias"`
 	Append               int    `help:"print information about append compilation"`
+	BCE                  int    `help:"report each bounds check kept or removed, with the reason; 2 prints the report as JSON"`
 	Checkptr             int    `help:"instrument unsafe pointer conversions\n0: instrumentation disabled\n1: conversions involving unsafe.Pointer are instrumented\n2: conversions to unsafe.Pointer force heap allocation"`
 	Closure              int    `help:"print information about closure compilation"`
 	DclStack             int    `help:"run internal dclstack check"`
 	Defer                int    `help:"print information about defer compilation"`
+	Dictionary           string `help:"print the layout of the dictionaries of the instantiations of the generic function or method F, T.M, pkg.F or pkg.T.M"`
 	DisableNil           int    `help:"disable nil checks"`
 	DumpPtrs             int    `help:"show Node pointers values in dump output"`
 	DwarfInl             int    `help:"print information about DWARF inlined function creation"`
+	EscReport            int    `help:"report each value that escapes to the heap, with the chain of flows that makes it escape; 2 prints the report as JSON"`
 	Export               int    `help:"print export data"`
+	FindType             string `help:"print the types referred to by the given runtime name, type symbol or type hash"`
+	FrameLayout          int    `help:"print the stack frame layout of each function"`
+	FullPaths            int    `help:"qualify names in messages with full import paths"`
 	GCProg               int    `help:"print dump of GC programs"`
+	GVN                  int    `help:"report values eliminated by global value numbering"`
+	InstGrowth           int    `help:"print code and data size attributed to each generic function or type"`
+	Instantiations       int    `help:"print the generic instantiations created, with their shapes and sizes; 2 prints them as JSON"`
+	InferenceTrace       int    `help:"print the steps of type argument inference"`
 	InlFuncsWithClosures int    `help:"allow functions with closures to be inlined"`
+	InternLimit          int    `help:"limit the strings interned by symbol and type formatting to n bytes, or no limit if negative"`
+	InternStats          int    `help:"print statistics about the strings interned by symbol and type formatting"`
 	Libfuzzer            int    `help:"enable coverage instrumentation for libfuzzer"`
+	LocalTypeNames       int    `help:"distinguish function-scope defined types in type names and hashes, as in T·2"`
 	LocationLists        int    `help:"print information about DWARF location list creation"`
+	Log                  string `help:"print records of the work of the compiler phases in the slash-separated list, or of all phases, as in log=dict/inline"`
+	LogJSON              int    `help:"print the records of -d=log as JSON objects"`
+	MangleNames          int    `help:"mangle the type names in link symbols to use only ASCII letters, digits and _.*/$ (set for all packages)"`
+	NameBudget           int    `help:"abbreviate type names longer than this many bytes in diagnostics"`
 	Nil                  int    `help:"print information about nil checks"`
+	NilCheckReport       int    `help:"report each generated nil check, with why it could not be removed; 2 prints the report as JSON"`
 	NoOpenDefer          int    `help:"disable open-coded defers"`
+	PGOInline            int    `help:"report the hot call sites of the -pgoprofile profile and the inlining budget of their callees"`
+	PGOInlineBudgetMult  int    `help:"multiply the inlining budget of the callees of hot call sites by n"`
+	PGOInlineCDF         int    `help:"make hot the call sites that account for n percent of the call samples of the -pgoprofile profile, or none if 0"`
+	PGOLayout            int    `help:"report the block layout that the -pgoprofile profile chose for each function"`
 	PCTab                string `help:"print named pc-value table\nOne of: pctospadj, pctofile, pctoline, pctoinline, pctopcdata"`
 	Panic                int    `help:"show all compiler panics"`
+	ShowType             string `help:"print every representation of the named type pkg.Name"`
 	Slice                int    `help:"print information about slice compilation"`
 	SoftFloat            int    `help:"force compiler to emit soft-float code"`
+	SpillStats           int    `help:"report the spills, reloads and spill slot bytes of each function; 2 prints the report as JSON"`
+	StrengthReduce       int    `help:"experimental: strength-reduce multiplications of loop indexes by constants; with -m=2, report the loops transformed"`
+	StrictFmt            int    `help:"report an internal error when a compiler value is formatted with an unsupported verb"`
 	SyncFrames           int    `help:"how many writer stack frames to include at sync points in unified export data"`
+	TParams              int    `help:"print a summary of type parameter usage by exported generic declarations"`
+	TailCall             int    `help:"turn direct self tail calls into loops, which drops their frames from tracebacks; 2 also reports which calls are turned and why others are not"`
+	TypeAlloc            int    `help:"print statistics about the allocation of types, fields and symbols"`
 	TypeAssert           int    `help:"print information about type assertion inlining"`
 	TypecheckInl         int    `help:"eager typechecking of inline function bodies"`
 	Unified              int    `help:"enable unified IR construction"`
 	UnifiedQuirks        int    `help:"enable unified IR construction's quirks mode"`
+	Unroll               int    `help:"unroll loops with short bodies, by n if greater than 1, and report which loops are unrolled and why others are not"`
+	Vectorize            int    `help:"vectorize simple loops over slices, and report which loops are vectorized and why others are not"`
 	WB                   int    `help:"print information about write barriers"`
+	WBReport             int    `help:"report each generated write barrier, with why it could not be removed; 2 prints the report as JSON"`
 	ABIWrap              int    `help:"print information about ABI wrapper generation"`
 	MayMoreStack         string `help:"call named function before all stack growth checks"`
 
@@ -0,0 +1,32 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"cmd/compile/internal/errcode"
+)
+
+// printErrorCatalog prints the catalog of error codes as a JSON array
+// for -errcatalog, ordered by code.
+func printErrorCatalog() {
+	type entry struct {
+		Code    string `json:"code"`
+		Name    string `json:"name"`
+		Summary string `json:"summary"`
+	}
+	var list []entry
+	for _, info := range errcode.Catalog() {
+		list = append(list, entry{info.Code.String(), info.Name, info.Summary})
+	}
+	b, err := json.MarshalIndent(list, "", "\t")
+	if err != nil {
+		log.Fatalf("encoding error catalog: %v", err)
+	}
+	os.Stdout.Write(append(b, '\n'))
+}
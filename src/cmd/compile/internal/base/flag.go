@@ -66,7 +66,7 @@ type CmdFlags struct {
 
 	LowerC int        "help:\"concurrency during compilation (1 means no concurrency)\""
 	LowerD flag.Value "help:\"enable debugging settings; try -d help\""
-	LowerE CountFlag  "help:\"no limit on number of errors reported\""
+	LowerE CountFlag  "help:\"no limit on number of errors reported; same as -maxerrors=0\""
 	LowerH CountFlag  "help:\"halt on error\""
 	LowerJ CountFlag  "help:\"debug runtime-initialized variables\""
 	LowerL CountFlag  "help:\"disable inlining\""
@@ -92,36 +92,56 @@ type CmdFlags struct {
 	Complete           bool         "help:\"compiling complete package (no C or assembly)\""
 	ClobberDead        bool         "help:\"clobber dead stack slots (for debugging)\""
 	ClobberDeadReg     bool         "help:\"clobber dead registers (for debugging)\""
+	DiagStream         string       "help:\"write each error and warning as a JSON object to `file`, or standard output if -, as soon as it is reported\""
 	Dwarf              bool         "help:\"generate DWARF symbols\""
 	DwarfBASEntries    *bool        "help:\"use base address selection entries in DWARF\""                        // &Ctxt.UseBASEntries, set below
 	DwarfLocationLists *bool        "help:\"add location lists to DWARF in optimized mode\""                      // &Ctxt.Flag_locationlists, set below
 	Dynlink            *bool        "help:\"support references to Go symbols defined in other shared libraries\"" // &Ctxt.Flag_dynlink, set below
 	EmbedCfg           func(string) "help:\"read go:embed configuration from `file`\""
+	ErrCatalog         bool         "help:\"print the catalog of error codes as JSON and exit\""
+	ErrCodes           bool         "help:\"append error codes to error messages\""
+	ErrSummary         bool         "help:\"after the errors, print how many there were in each file\""
 	GenDwarfInl        int          "help:\"generate DWARF inline info records\"" // 0=disabled, 1=funcs, 2=funcs+formals/locals
 	GoVersion          string       "help:\"required version of the runtime\""
+	Hyperlinks         string       "help:\"if printing to a terminal that shows them, make error positions hyperlinks to `url`, in which {file}, {line} and {col} stand for the position\""
 	ImportCfg          func(string) "help:\"read import configuration from `file`\""
 	ImportMap          func(string) "help:\"add `definition` of the form source=actual to import map\""
+	InstLimit          int          "help:\"report an error if the package instantiates generic functions and methods more than `n` times, or never if 0\""
 	InstallSuffix      string       "help:\"set pkg directory `suffix`\""
 	JSON               string       "help:\"version,file for JSON compiler/optimizer detail output\""
+	JSONDiag           bool         "help:\"print errors and warnings as JSON objects, one per line\""
 	Lang               string       "help:\"Go language version source code expects\""
 	LinkObj            string       "help:\"write linker-specific object to `file`\""
 	LinkShared         *bool        "help:\"generate code that will be linked against Go shared libraries\"" // &Ctxt.Flag_linkshared, set below
 	Live               CountFlag    "help:\"debug liveness analysis\""
 	MSan               bool         "help:\"build code compatible with C/C++ memory sanitizer\""
+	MaxErrors          int          "help:\"stop after `n` errors, or never if 0\""
 	MemProfile         string       "help:\"write memory profile to `file`\""
 	MemProfileRate     int          "help:\"set runtime.MemProfileRate to `rate`\""
 	MutexProfile       string       "help:\"write mutex profile to `file`\""
 	NoLocalImports     bool         "help:\"reject local (relative) imports\""
 	Pack               bool         "help:\"write to file.a instead of file.o\""
+	PGOProfile         string       "help:\"read a CPU `profile` for profile-guided optimizations\""
+	PkgPathMap         string       "help:\"rewrite package paths in symbol names and type data by ;-separated `prefix=>replacement` rules\""
+	Quiet              bool         "help:\"report only the first error in each file, and a summary as with -errsummary\""
 	Race               bool         "help:\"enable race detector\""
+	Related            bool         "help:\"after each error, print the other positions it involves on see also lines\""
+	SARIF              string       "help:\"write errors and warnings as a SARIF log to `file`\""
 	Shared             *bool        "help:\"generate code that can be linked into a shared library\"" // &Ctxt.Flag_shared, set below
 	SmallFrames        bool         "help:\"reduce the size limit for stack allocated objects\""      // small stacks, to diagnose GC latency; see golang.org/issue/27732
+	Snippets           bool         "help:\"show the source line of each error, marking the error position, if printing to a terminal\""
 	Spectre            string       "help:\"enable spectre mitigations in `list` (all, index, ret)\""
 	Std                bool         "help:\"compiling standard library\""
 	SymABIs            string       "help:\"read symbol ABIs from `file`\""
 	TraceProfile       string       "help:\"write an execution trace to `file`\""
 	TrimPath           string       "help:\"remove `prefix` from recorded source file paths\""
+	TypeSnapshot       string       "help:\"write the encoding of the package's declared types to `file`\""
+	WAll               bool         "flag:\"Wall\" help:\"enable all warnings\""
 	WB                 bool         "help:\"enable write barrier\"" // TODO: remove
+	WConversion        bool         "flag:\"Wconversion\" help:\"warn about suspicious conversions\""
+	WError             bool         "flag:\"Werror\" help:\"report warnings as errors\""
+	WPragma            bool         "flag:\"Wpragma\" help:\"warn about misused compiler directives\""
+	WSymCollide        bool         "flag:\"Wsymcollide\" help:\"warn about exported names that collide when case and compatibility characters are ignored\""
 
 	// Configuration derived from flags; not a flag itself.
 	Cfg struct {
@@ -145,6 +165,8 @@ func ParseFlags() {
 	Flag.I = addImportDir
 
 	Flag.LowerC = 1
+	Flag.MaxErrors = 10
+	Flag.Hyperlinks = "file://{file}"
 	Flag.LowerD = objabi.NewDebugFlag(&Debug, DebugSSA)
 	Flag.LowerP = &Ctxt.Pkgpath
 	Flag.LowerV = &Ctxt.Debugvlog
@@ -163,6 +185,8 @@ func ParseFlags() {
 	Flag.WB = true
 
 	Debug.InlFuncsWithClosures = 1
+	Debug.PGOInlineBudgetMult = 25
+	Debug.PGOInlineCDF = 99
 	if buildcfg.Experiment.Unified {
 		Debug.Unified = 1
 	}
@@ -175,6 +199,23 @@ func ParseFlags() {
 	registerFlags()
 	objabi.Flagparse(usage)
 
+	if Flag.ErrCatalog {
+		printErrorCatalog()
+		Exit(0)
+	}
+	if Flag.SARIF != "" {
+		AtExit(writeSARIF)
+	}
+	if Flag.DiagStream != "" {
+		openDiagStream(Flag.DiagStream)
+	}
+	if Flag.Snippets && !isTerminal(os.Stdout) {
+		Flag.Snippets = false // the marks only line up in a terminal
+	}
+	if Flag.Hyperlinks != "" && !(isTerminal(os.Stdout) && showsHyperlinks(os.Getenv)) {
+		Flag.Hyperlinks = ""
+	}
+
 	if Flag.MSan && !sys.MSanSupported(buildcfg.GOOS, buildcfg.GOARCH) {
 		log.Fatalf("%s/%s does not support -msan", buildcfg.GOOS, buildcfg.GOARCH)
 	}
@@ -243,6 +284,27 @@ func ParseFlags() {
 	if Flag.LowerC < 1 {
 		log.Fatalf("-c must be at least 1, got %d", Flag.LowerC)
 	}
+	if Flag.MaxErrors < 0 {
+		log.Fatalf("-maxerrors must not be negative, got %d", Flag.MaxErrors)
+	}
+	if Flag.LowerE != 0 {
+		Flag.MaxErrors = 0
+	}
+	if Flag.InstLimit < 0 {
+		log.Fatalf("-instlimit must not be negative, got %d", Flag.InstLimit)
+	}
+	if Flag.Quiet {
+		Flag.ErrSummary = true
+	}
+	if Debug.PGOInlineBudgetMult < 1 {
+		log.Fatalf("-d=pgoinlinebudgetmult must be at least 1, got %d", Debug.PGOInlineBudgetMult)
+	}
+	if Debug.PGOInlineCDF < 0 || Debug.PGOInlineCDF > 100 {
+		log.Fatalf("-d=pgoinlinecdf must be between 0 and 100, got %d", Debug.PGOInlineCDF)
+	}
+	if Debug.Log != "" {
+		parseLogPhases(Debug.Log)
+	}
 	if Flag.LowerC > 1 && !concurrentBackendAllowed() {
 		log.Fatalf("cannot use concurrent backend compilation with provided flags; invoked as %v", os.Args)
 	}
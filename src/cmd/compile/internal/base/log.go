@@ -0,0 +1,182 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"cmd/internal/src"
+)
+
+// A Logger writes records about the work of a compiler phase, for
+// debugging the compiler. Each record has the phase, the position it
+// is about, a message and attributes, which are key-value pairs, as
+// in
+//
+//	phase=inline pos=x.go:12:3 msg=call func=main.f callee=main.g
+//
+// Records of a phase are written only if -d=log names the phase, and,
+// with -d=logjson, as JSON objects. The records are like those of
+// log/slog, which the compiler cannot use, as it must build with
+// older versions of Go.
+type Logger struct {
+	phase string
+	attrs []interface{}
+}
+
+// logPhases records the phases of the loggers that NewLogger returned.
+var logPhases = make(map[string]bool)
+
+// Enabled logging, set by parseLogPhases.
+var (
+	logAll     bool
+	logEnabled map[string]bool
+)
+
+// logOut is where loggers write records, under logMu.
+var (
+	logMu  sync.Mutex
+	logOut io.Writer = os.Stdout
+)
+
+// NewLogger returns a logger for the phase. Packages create their
+// loggers in package variables, so that -d=log can tell the names of
+// all phases apart from misspellings.
+func NewLogger(phase string) *Logger {
+	logPhases[phase] = true
+	return &Logger{phase: phase}
+}
+
+// parseLogPhases enables the loggers of the phases that list, the
+// value of -d=log, names. The phases in list are separated by
+// slashes, as -d separates settings by commas, and "all" names every
+// phase.
+func parseLogPhases(list string) {
+	logEnabled = make(map[string]bool)
+	for _, phase := range strings.Split(list, "/") {
+		switch {
+		case phase == "all":
+			logAll = true
+		case logPhases[phase]:
+			logEnabled[phase] = true
+		default:
+			var known []string
+			for p := range logPhases {
+				known = append(known, p)
+			}
+			sort.Strings(known)
+			log.Fatalf("-d=log: unknown phase %q; phases are %s and all", phase, strings.Join(known, ", "))
+		}
+	}
+}
+
+// Enabled reports whether l writes records. Callers that build the
+// values of attributes at some cost check it first.
+func (l *Logger) Enabled() bool {
+	return logAll || logEnabled[l.phase]
+}
+
+// With returns a logger that adds the attributes args, key-value
+// pairs as for Log, to each record of l.
+func (l *Logger) With(args ...interface{}) *Logger {
+	attrs := make([]interface{}, 0, len(l.attrs)+len(args))
+	attrs = append(attrs, l.attrs...)
+	return &Logger{phase: l.phase, attrs: append(attrs, args...)}
+}
+
+// Log writes a record with the position pos, if it is known, the
+// message msg and the attributes args, which alternate between string
+// keys and values, if l is enabled. Values that are positions read as
+// positions do in error messages and other values as they do in
+// internal compiler errors.
+func (l *Logger) Log(pos src.XPos, msg string, args ...interface{}) {
+	if !l.Enabled() {
+		return
+	}
+	keys := []string{"phase"}
+	vals := []string{l.phase}
+	if pos.IsKnown() {
+		keys = append(keys, "pos")
+		vals = append(vals, FmtPos(pos))
+	}
+	keys = append(keys, "msg")
+	vals = append(vals, msg)
+	attrs := append(l.attrs[:len(l.attrs):len(l.attrs)], args...)
+	for i := 0; i < len(attrs); i += 2 {
+		key, ok := attrs[i].(string)
+		if !ok || i+1 == len(attrs) {
+			Fatalf("logger %s: bad attributes %v", l.phase, attrs)
+		}
+		keys = append(keys, key)
+		vals = append(vals, logValue(attrs[i+1]))
+	}
+
+	var buf bytes.Buffer
+	if Debug.LogJSON != 0 {
+		writeLogJSON(&buf, keys, vals)
+	} else {
+		writeLogText(&buf, keys, vals)
+	}
+	logMu.Lock()
+	logOut.Write(buf.Bytes())
+	logMu.Unlock()
+}
+
+// logValue returns the text of the attribute value v.
+func logValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case src.XPos:
+		return FmtPos(v)
+	}
+	return FormatFatal("%v", v)
+}
+
+// writeLogText writes a record as a line of key=value pairs to buf,
+// quoting the values that are empty or would not read as one value.
+func writeLogText(buf *bytes.Buffer, keys, vals []string) {
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		val := vals[i]
+		if val == "" || strings.IndexFunc(val, func(r rune) bool {
+			return r == '"' || r == '=' || unicode.IsSpace(r) || !unicode.IsPrint(r)
+		}) >= 0 {
+			val = strconv.Quote(val)
+		}
+		buf.WriteString(val)
+	}
+	buf.WriteByte('\n')
+}
+
+// writeLogJSON writes a record as a line with a JSON object to buf,
+// with its fields in the order of keys.
+func writeLogJSON(buf *bytes.Buffer, keys, vals []string) {
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, _ := json.Marshal(vals[i])
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteString("}\n")
+}
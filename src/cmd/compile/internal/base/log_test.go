@@ -0,0 +1,40 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"cmd/internal/src"
+)
+
+func TestLogger(t *testing.T) {
+	defer func(out io.Writer, all bool, enabled map[string]bool, json int) {
+		logOut, logAll, logEnabled, Debug.LogJSON = out, all, enabled, json
+	}(logOut, logAll, logEnabled, Debug.LogJSON)
+	var buf bytes.Buffer
+	logOut = &buf
+
+	dict := NewLogger("testdict")
+	inline := NewLogger("testinline")
+	parseLogPhases("testdict")
+	if !dict.Enabled() || inline.Enabled() {
+		t.Fatalf("-d=log=testdict enabled testdict %v, testinline %v", dict.Enabled(), inline.Enabled())
+	}
+
+	dict.With("func", "p.F").Log(src.NoXPos, "creating dictionary", "dict", "p.F[int]", "entries", 3, "empty", "", "quote", `a"b`)
+	inline.Log(src.NoXPos, "call")
+	Debug.LogJSON = 1
+	dict.Log(src.NoXPos, "itab entry", "src", "*p.T", "dst", "p.I")
+
+	want := `phase=testdict msg="creating dictionary" func=p.F dict=p.F[int] entries=3 empty="" quote="a\"b"
+{"phase":"testdict","msg":"itab entry","src":"*p.T","dst":"p.I"}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got records\n%s\nwant\n%s", got, want)
+	}
+}
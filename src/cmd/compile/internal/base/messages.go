@@ -0,0 +1,38 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import "cmd/compile/internal/errcode"
+
+// The message templates (see MsgTemplate). Their IDs are stable: a
+// translation refers to them (see RegisterTranslation), so do not
+// change them, and keep a template's parameters when changing its
+// text.
+//
+// Some templates are only for translating the messages of the type
+// checker, which builds its messages itself and names the template of
+// each at the report site (see types2.MsgLine).
+var (
+	MsgUndefinedIn        = newMsgTemplate("UndefinedIn", errcode.UndeclaredName, "undefined: {name} in {expr}")
+	MsgUndefined          = newMsgTemplate("Undefined", errcode.UndeclaredName, "undefined: {name}")
+	MsgDeclaredNotUsed    = newMsgTemplate("DeclaredNotUsed", errcode.UnusedVar, "{name} declared but not used")
+	MsgImportedNotUsedAs  = newMsgTemplate("ImportedNotUsedAs", errcode.UnusedImport, "imported and not used: {path} as {name}")
+	MsgImportedNotUsed    = newMsgTemplate("ImportedNotUsed", errcode.UnusedImport, "imported and not used: {path}")
+	MsgMissingReturnAtEnd = newMsgTemplate("MissingReturnAtEnd", errcode.MissingReturn, "missing return at end of function")
+	MsgTooManyErrors      = newMsgTemplate("TooManyErrors", errcode.TooManyErrors, "too many errors; {count} more not shown")
+	MsgTooManyErrorsStop  = newMsgTemplate("TooManyErrorsStop", errcode.TooManyErrors, "too many errors")
+	MsgErrorSummaryOne    = newMsgTemplate("ErrorSummaryOne", 0, "1 error in {pkg}: {files}")
+	MsgErrorSummary       = newMsgTemplate("ErrorSummary", 0, "{count} errors in {pkg}: {files}")
+	MsgErrorsInFile       = newMsgTemplate("ErrorsInFile", 0, "{count} in {file}")
+
+	// Messages of the type checker.
+	_ = newMsgTemplate("MissingReturn", errcode.MissingReturn, "missing return")
+	_ = newMsgTemplate("Redeclared", errcode.DuplicateDecl, "{name} redeclared in this block")
+	_ = newMsgTemplate("OtherDeclaration", 0, "other declaration of {name}")
+	_ = newMsgTemplate("CannotUseAsValueIn", errcode.IncompatibleAssign, "cannot use {x} as {type} value in {context}")
+	_ = newMsgTemplate("NoFieldOrMethod", errcode.MissingFieldOrMethod, "{x} undefined (type {type} has no field or method {sel})")
+	_ = newMsgTemplate("NotEnoughArguments", errcode.WrongArgCount, "not enough arguments in call to {call}")
+	_ = newMsgTemplate("TooManyArguments", errcode.WrongArgCount, "too many arguments in call to {call}")
+)
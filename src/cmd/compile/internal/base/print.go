@@ -5,20 +5,104 @@
 package base
 
 import (
+	"encoding/json"
 	"fmt"
 	"internal/buildcfg"
 	"os"
+	"runtime"
 	"runtime/debug"
 	"sort"
 	"strings"
+	"sync"
 
+	"cmd/compile/internal/errcode"
 	"cmd/internal/src"
 )
 
 // An errorMsg is a queued error message, waiting to be printed.
 type errorMsg struct {
-	pos src.XPos
-	msg string
+	pos     src.XPos
+	end     src.XPos // end of the source text the message is about, if known
+	msg     string   // as printed, with the position and a newline
+	text    string   // just the message
+	warning bool
+	wcat    string       // -W category of a warning, or of an error for -Werror
+	code    errcode.Code // for errors; 0 if unclassified
+	backend bool         // reported while compiling functions; see byPos
+	related []Related
+	fixes   []SuggestedFix
+	lines   []MsgLine // the template of each line of text, for translations
+}
+
+// ErrorDetails holds what an error reports besides its message. See
+// ErrorfAtDetails.
+type ErrorDetails struct {
+	End     src.XPos // end of the source text at the error position, if known
+	Related []Related
+	Fixes   []SuggestedFix // only the -jsondiag and -sarif output show these
+	Lines   []MsgLine      // the template of each line of the message, if any; see RegisterTranslation
+}
+
+// A Related is a secondary position relevant to an error, such as
+// that of another declaration, with a note on what is there.
+type Related struct {
+	Pos src.XPos
+	Msg string // e.g. "other declaration of M"
+}
+
+// A SuggestedFix is a change to the source that would fix the problem
+// an error reports, for tools to offer or apply.
+type SuggestedFix struct {
+	Message string // what the fix does, e.g. "remove the import"
+	Edits   []TextEdit
+}
+
+// A TextEdit replaces the source text from Pos up to End with NewText.
+// An edit that inserts text has End == Pos.
+type TextEdit struct {
+	Pos, End src.XPos
+	NewText  string
+}
+
+// setText sets the message of e to text, and derives e's printed
+// form from it. The lines of the printed form that e.lines names
+// templates of are translated, if there is a translation (see
+// RegisterTranslation), and, with -related, it ends
+// with a "see also" line for each of e.related.
+func (e *errorMsg) setText(text string) {
+	e.text = text
+	// Tags like the -W category and the code go at the end of the
+	// first line, before any notes on further lines.
+	printed := translate(text, e.lines)
+	first, rest := printed, ""
+	if i := strings.IndexByte(printed, '\n'); i >= 0 {
+		first, rest = printed[:i], printed[i:]
+	}
+	if e.wcat != "" {
+		if e.warning {
+			first = "warning: " + first
+		}
+		first += " [-W" + e.wcat + "]"
+	}
+	if Flag.ErrCodes && e.code != 0 {
+		first += fmt.Sprintf(" [%v]", e.code)
+	}
+	msg := first + rest
+	// Only add the position if know the position.
+	// See issue golang.org/issue/11361.
+	if e.pos.IsKnown() {
+		msg = fmt.Sprintf("%v: %s", FmtPos(e.pos), msg)
+	}
+	if Flag.Related {
+		for _, r := range e.related {
+			if r.Pos.IsKnown() {
+				msg += fmt.Sprintf("\n\t%v: see also: %s", FmtPos(r.Pos), r.Msg)
+			} else {
+				msg += "\n\tsee also: " + r.Msg
+			}
+		}
+	}
+	e.msg = msg + "\n"
 }
 
 // Pos is the current source position being processed,
@@ -26,33 +110,99 @@ type errorMsg struct {
 var Pos src.XPos
 
 var (
+	// errorMu protects the variables below and lasterror, since the
+	// backend reports errors from concurrent goroutines.
+	errorMu sync.Mutex
+
 	errorMsgs       []errorMsg
 	numErrors       int // number of entries in errorMsgs that are errors (as opposed to warnings)
 	numSyntaxErrors int
+
+	// FlushErrors prints at most -maxerrors errors. lastShown is the
+	// position of the last one it printed, and numHidden counts those
+	// it left out. tooMany records that the compilation stopped at
+	// the limit; see tooManyErrors.
+	numShown  int
+	numHidden int
+	lastShown src.XPos
+	tooMany   bool
+
+	// numFlushed counts the errors FlushErrors met, less duplicates,
+	// errorFiles lists the files with errors in the order it met them,
+	// and fileErrors counts the errors in each, for -errsummary and
+	// -quiet.
+	numFlushed int
+	errorFiles []string
+	fileErrors map[string]int
 )
 
 // Errors returns the number of errors reported.
 func Errors() int {
+	errorMu.Lock()
+	defer errorMu.Unlock()
 	return numErrors
 }
 
+// inBackend reports whether the backend is compiling functions, on
+// as many goroutines as -c says.
+func inBackend() bool {
+	return Ctxt != nil && Ctxt.InParallel
+}
+
 // SyntaxErrors returns the number of syntax errors reported
 func SyntaxErrors() int {
 	return numSyntaxErrors
 }
 
+// FormatMessage formats the message of a diagnostic at pos. The
+// compiler sets it to types.FormatMessage, which refers to packages as
+// the source file does and makes package names unambiguous.
+var FormatMessage = func(pos src.XPos, format string, args ...interface{}) string {
+	return fmt.Sprintf(format, args...)
+}
+
+// FormatFatal formats the message of an internal compiler error. The
+// compiler sets it to types.FormatBestEffort, so that a malformed type
+// mentioned in the message does not cause an internal error of its own.
+var FormatFatal = fmt.Sprintf
+
 // addErrorMsg adds a new errorMsg (which may be a warning) to errorMsgs.
-func addErrorMsg(pos src.XPos, format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	// Only add the position if know the position.
-	// See issue golang.org/issue/11361.
-	if pos.IsKnown() {
-		msg = fmt.Sprintf("%v: %s", FmtPos(pos), msg)
+func addErrorMsg(pos src.XPos, warning bool, format string, args ...interface{}) {
+	e := errorMsg{pos: pos, warning: warning, backend: inBackend()}
+	e.setText(FormatMessage(pos, format, args...))
+	errorMu.Lock()
+	errorMsgs = append(errorMsgs, e)
+	streamDiag(e)
+	errorMu.Unlock()
+}
+
+// BadVerb writes text to s as the result of formatting a compiler
+// value with verb, which the value's Format method does not support.
+// If -d=strictfmt is set, BadVerb instead reports an internal compiler
+// error naming the call site that used verb, so that tests catch the
+// misuse. It does not panic: package fmt recovers panics in Format
+// methods and prints them in its output.
+func BadVerb(s fmt.State, verb rune, text string) {
+	if Debug.StrictFmt == 0 {
+		fmt.Fprint(s, text)
+		return
+	}
+
+	// Skip BadVerb's caller, the Format method, and package fmt.
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	site := "unknown call site"
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "fmt.") {
+			site = fmt.Sprintf("%s:%d (%s)", frame.File, frame.Line, frame.Function)
+			break
+		}
+		if !more {
+			break
+		}
 	}
-	errorMsgs = append(errorMsgs, errorMsg{
-		pos: pos,
-		msg: msg + "\n",
-	})
+	Fatalf("bad verb %%%c formatting %s at %s", verb, text, site)
 }
 
 // FmtPos formats pos as a file:line string.
@@ -63,31 +213,284 @@ func FmtPos(pos src.XPos) string {
 	return Ctxt.OutermostPos(pos).Format(Flag.C == 0, Flag.L == 1)
 }
 
-// byPos sorts errors by source position.
+// byPos sorts errors by source position. Errors at the same position
+// otherwise stay in the order they were reported in, except that those
+// the backend reported come last, sorted by message: the backend
+// compiles functions concurrently with -c, so the order it reports
+// them in varies, and the output must not.
 type byPos []errorMsg
 
-func (x byPos) Len() int           { return len(x) }
-func (x byPos) Less(i, j int) bool { return x[i].pos.Before(x[j].pos) }
-func (x byPos) Swap(i, j int)      { x[i], x[j] = x[j], x[i] }
+func (x byPos) Len() int      { return len(x) }
+func (x byPos) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+
+func (x byPos) Less(i, j int) bool {
+	a, b := &x[i], &x[j]
+	switch {
+	case a.pos != b.pos:
+		return a.pos.Before(b.pos)
+	case a.backend != b.backend:
+		return b.backend
+	case a.backend:
+		return a.msg < b.msg
+	}
+	return false
+}
 
 // FlushErrors sorts errors seen so far by line number, prints them to stdout,
-// and empties the errors array.
+// and empties the errors array. Once it has printed -maxerrors errors,
+// it prints only warnings; ErrorExit then notes how many errors it left
+// out. (That many errors usually stop the compilation; see
+// tooManyErrors.) With -quiet, it prints only the first error in each
+// file.
 func FlushErrors() {
 	if Ctxt != nil && Ctxt.Bso != nil {
 		Ctxt.Bso.Flush()
 	}
+	errorMu.Lock()
+	defer errorMu.Unlock()
 	if len(errorMsgs) == 0 {
 		return
 	}
 	sort.Stable(byPos(errorMsgs))
 	for i, err := range errorMsgs {
-		if i == 0 || err.msg != errorMsgs[i-1].msg {
-			fmt.Printf("%s", err.msg)
+		if i > 0 && err.msg == errorMsgs[i-1].msg {
+			continue
+		}
+		if !err.warning {
+			if countFileError(err.pos) > 1 && Flag.Quiet {
+				continue
+			}
+			if Flag.MaxErrors > 0 && numShown >= Flag.MaxErrors {
+				numHidden++
+				continue
+			}
+			numShown++
+			lastShown = err.pos
 		}
+		printDiag(err)
 	}
 	errorMsgs = errorMsgs[:0]
 }
 
+// countFileError counts an error at pos in its file, for -errsummary
+// and -quiet, and returns the number of errors in the file so far.
+// Errors without a position count in no file.
+func countFileError(pos src.XPos) int {
+	numFlushed++
+	if Ctxt == nil || !pos.IsKnown() {
+		return 0
+	}
+	file := Ctxt.OutermostPos(pos).RelFilename()
+	if fileErrors == nil {
+		fileErrors = make(map[string]int)
+	}
+	if fileErrors[file] == 0 {
+		errorFiles = append(errorFiles, file)
+	}
+	fileErrors[file]++
+	return fileErrors[file]
+}
+
+// errorSummary returns the footer that -errsummary prints after the
+// errors, as in "3 errors in pkg/foo: 2 in a.go, 1 in b.go".
+func errorSummary() string {
+	var files []string
+	for _, file := range errorFiles {
+		files = append(files, MsgErrorsInFile.RenderSummary(MsgArgs{"count": fileErrors[file], "file": file}))
+	}
+	pkgpath := Ctxt.Pkgpath
+	if pkgpath == "" {
+		pkgpath = "command-line-arguments" // no -p flag, as the go command names such a package
+	}
+	args := MsgArgs{"count": numFlushed, "pkg": pkgpath, "files": strings.Join(files, ", ")}
+	if numFlushed == 1 {
+		return MsgErrorSummaryOne.RenderSummary(args)
+	}
+	return MsgErrorSummary.RenderSummary(args)
+}
+
+// A jsonDiag is a diagnostic as printed by -jsondiag, one per line.
+type jsonDiag struct {
+	Pos      *jsonPos      `json:"pos,omitempty"`
+	End      *jsonPos      `json:"end,omitempty"`
+	Severity string        `json:"severity"` // "error" or "warning"
+	Code     string        `json:"code,omitempty"`
+	Category string        `json:"category,omitempty"` // -W category
+	Message  string        `json:"message"`
+	Related  []jsonRelated `json:"related,omitempty"`
+	Fixes    []jsonFix     `json:"fixes,omitempty"`
+}
+
+// A jsonPos is a source position in a jsonDiag. Like the positions
+// in the compiler's text output, it reflects //line directives.
+type jsonPos struct {
+	File string `json:"file"`
+	Line uint   `json:"line"`
+	Col  uint   `json:"col,omitempty"`
+}
+
+// A jsonRelated is a note about a diagnostic, typically about another
+// source position involved in it.
+type jsonRelated struct {
+	Pos     *jsonPos `json:"pos,omitempty"`
+	Message string   `json:"message"`
+}
+
+// A jsonFix is a SuggestedFix in a jsonDiag.
+type jsonFix struct {
+	Message string     `json:"message"`
+	Edits   []jsonEdit `json:"edits"`
+}
+
+// A jsonEdit is a TextEdit in a jsonFix. Unlike other positions in a
+// jsonDiag, its positions ignore //line directives: they locate the
+// text to replace in the file the compiler read.
+type jsonEdit struct {
+	Pos     jsonPos `json:"pos"`
+	End     jsonPos `json:"end"`
+	NewText string  `json:"new_text"`
+}
+
+// relJSONPos returns pos as a position in a jsonDiag, as FmtPos
+// would print it.
+func relJSONPos(pos src.XPos) *jsonPos {
+	p := Ctxt.OutermostPos(pos)
+	return &jsonPos{File: p.RelFilename(), Line: p.RelLine(), Col: p.RelCol()}
+}
+
+// editPos returns pos as a position in a jsonEdit.
+func editPos(pos src.XPos) jsonPos {
+	p := Ctxt.PosTable.Pos(pos)
+	return jsonPos{File: p.Filename(), Line: p.Line(), Col: p.Col()}
+}
+
+// printDiag prints e, as text or, with -jsondiag, as JSON, and keeps
+// it for the -sarif log. With -snippets, the text shows the source
+// line of e below its first line, and with -hyperlinks, its positions
+// are links.
+func printDiag(e errorMsg) {
+	if Flag.SARIF != "" {
+		sarifDiags = append(sarifDiags, newJSONDiag(e))
+	}
+	if streamsToStdout() {
+		return // streamed as reported
+	}
+	if Flag.JSONDiag {
+		b, err := json.Marshal(newJSONDiag(e))
+		if err != nil {
+			Fatalf("encoding diagnostic: %v", err)
+		}
+		fmt.Printf("%s\n", b)
+		return
+	}
+	msg := e.msg
+	if Flag.Hyperlinks != "" {
+		msg = e.withLinks()
+	}
+	if Flag.Snippets {
+		if s := e.snippet(); s != "" {
+			i := strings.IndexByte(msg, '\n') + 1
+			fmt.Printf("%s%s%s", msg[:i], s, msg[i:])
+			return
+		}
+	}
+	fmt.Printf("%s", msg)
+}
+
+// newJSONDiag returns e as a jsonDiag. Each further line of its text
+// in the "\n\tfile:line:col: note" form used by the type checker to
+// point at other declarations and the like becomes a related note, as
+// does each of e.related. Further lines without a position, like those
+// explaining why a type does not implement an interface, stay in the
+// message.
+func newJSONDiag(e errorMsg) jsonDiag {
+	lines := strings.Split(e.text, "\n\t")
+	d := jsonDiag{
+		Severity: "error",
+		Category: e.wcat,
+		Message:  lines[0],
+	}
+	if e.warning {
+		d.Severity = "warning"
+	}
+	if e.code != 0 {
+		d.Code = e.code.String()
+	}
+	if e.pos.IsKnown() {
+		d.Pos = relJSONPos(e.pos)
+		if e.end.IsKnown() {
+			d.End = relJSONPos(e.end)
+		}
+	}
+	for _, line := range lines[1:] {
+		pos, note := splitJSONPos(line)
+		if pos == nil {
+			d.Message += "\n\t" + line
+			continue
+		}
+		d.Related = append(d.Related, jsonRelated{pos, note})
+	}
+	for _, r := range e.related {
+		var pos *jsonPos
+		if r.Pos.IsKnown() {
+			pos = relJSONPos(r.Pos)
+		}
+		d.Related = append(d.Related, jsonRelated{pos, r.Msg})
+	}
+	for _, fix := range e.fixes {
+		f := jsonFix{Message: fix.Message}
+		for _, edit := range fix.Edits {
+			f.Edits = append(f.Edits, jsonEdit{editPos(edit.Pos), editPos(edit.End), edit.NewText})
+		}
+		d.Fixes = append(d.Fixes, f)
+	}
+	return d
+}
+
+// splitJSONPos splits a leading "file:line:col: " or "file:line: "
+// position off line.
+func splitJSONPos(line string) (*jsonPos, string) {
+	for i := 0; ; {
+		j := strings.Index(line[i:], ": ")
+		if j < 0 {
+			return nil, line
+		}
+		i += j
+		file, lineno, col, ok := parseFileLineCol(line[:i])
+		if ok {
+			return &jsonPos{File: file, Line: lineno, Col: col}, line[i+len(": "):]
+		}
+		i += len(": ")
+	}
+}
+
+// parseFileLineCol parses s as file:line:col or file:line.
+func parseFileLineCol(s string) (file string, line, col uint, ok bool) {
+	file, n, ok := cutUint(s)
+	if !ok {
+		return "", 0, 0, false
+	}
+	if f, m, ok := cutUint(file); ok {
+		return f, m, n, true
+	}
+	return file, n, 0, true
+}
+
+// cutUint cuts a trailing ":N" off s.
+func cutUint(s string) (rest string, n uint, ok bool) {
+	i := strings.LastIndexByte(s, ':')
+	if i <= 0 || i == len(s)-1 {
+		return "", 0, false
+	}
+	for _, c := range s[i+1:] {
+		if c < '0' || c > '9' {
+			return "", 0, false
+		}
+		n = n*10 + uint(c-'0')
+	}
+	return s[:i], n, true
+}
+
 // lasterror keeps track of the most recently issued error,
 // to avoid printing multiple error messages on the same line.
 var lasterror struct {
@@ -105,47 +508,99 @@ func sameline(a, b src.XPos) bool {
 
 // Errorf reports a formatted error at the current line.
 func Errorf(format string, args ...interface{}) {
-	ErrorfAt(Pos, format, args...)
+	ErrorfAt(Pos, 0, format, args...)
 }
 
-// ErrorfAt reports a formatted error message at pos.
-func ErrorfAt(pos src.XPos, format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
+// ErrorfAt reports a formatted error message at pos. The code
+// identifies the class of the error (see package errcode), or is 0 if
+// the error belongs to none.
+func ErrorfAt(pos src.XPos, code errcode.Code, format string, args ...interface{}) {
+	ErrorfAtDetails(pos, code, ErrorDetails{}, format, args...)
+}
 
+// ErrorfAtDetails is like ErrorfAt, but the error also reports details.
+func ErrorfAtDetails(pos src.XPos, code errcode.Code, details ErrorDetails, format string, args ...interface{}) {
+	errorfAt(pos, code, details, "", FormatMessage(pos, format, args...))
+}
+
+// errorfAt reports the error message msg with the code at pos. If
+// wcat is not empty, the error is a warning of that -W category, made
+// an error by -Werror.
+func errorfAt(pos src.XPos, code errcode.Code, details ErrorDetails, wcat, msg string) {
+	backend := inBackend()
+	errorMu.Lock()
 	if strings.HasPrefix(msg, "syntax error") {
 		numSyntaxErrors++
 		// only one syntax error per line, no matter what error
 		if sameline(lasterror.syntax, pos) {
+			errorMu.Unlock()
 			return
 		}
 		lasterror.syntax = pos
-	} else {
+	} else if !backend {
+		// (The errors of the backend may interleave; FlushErrors
+		// drops the duplicates among them.)
 		// only one of multiple equal non-syntax errors per line
 		// (FlushErrors shows only one of them, so we filter them
 		// here as best as we can (they may not appear in order)
 		// so that we don't count them here and exit early, and
 		// then have nothing to show for.)
 		if sameline(lasterror.other, pos) && lasterror.msg == msg {
+			errorMu.Unlock()
 			return
 		}
 		lasterror.other = pos
 		lasterror.msg = msg
 	}
 
-	addErrorMsg(pos, "%s", msg)
+	e := errorMsg{pos: pos, end: details.End, wcat: wcat, code: code, related: details.Related, fixes: details.Fixes, lines: details.Lines, backend: backend}
+	e.setText(msg)
+	errorMsgs = append(errorMsgs, e)
+	streamDiag(e)
 	numErrors++
+	// The backend checks the limit once it is done; see CheckErrorLimit.
+	stop := !backend && errorLimitReached()
+	errorMu.Unlock()
 
 	hcrash()
-	if numErrors >= 10 && Flag.LowerE == 0 {
-		FlushErrors()
-		fmt.Printf("%v: too many errors\n", FmtPos(pos))
-		ErrorExit()
+	if stop {
+		tooManyErrors()
 	}
 }
 
+// CheckErrorLimit stops the compilation, as reporting the -maxerrors'th
+// error does, if that many errors have been reported. The backend calls
+// it when it has compiled all functions: errors it reports while
+// compiling them do not stop it, so that which of them it prints does
+// not depend on the order in which they occur.
+func CheckErrorLimit() {
+	errorMu.Lock()
+	stop := errorLimitReached()
+	errorMu.Unlock()
+	if stop {
+		tooManyErrors()
+	}
+}
+
+// errorLimitReached reports whether -maxerrors errors have been
+// reported, and the compilation should stop. It never should with
+// -diagstream, which reports all errors. errorMu must be held.
+func errorLimitReached() bool {
+	return Flag.MaxErrors > 0 && numErrors >= Flag.MaxErrors && diagStream == nil
+}
+
+// tooManyErrors stops the compilation once -maxerrors errors have been
+// reported. ErrorExit prints the first of the errors reported so far,
+// and notes that there are too many, and how many of those it did not
+// print.
+func tooManyErrors() {
+	tooMany = true
+	ErrorExit()
+}
+
 // ErrorfVers reports that a language feature (format, args) requires a later version of Go.
 func ErrorfVers(lang string, format string, args ...interface{}) {
-	Errorf("%s requires %s or later (-lang was set to %s; check go.mod)", fmt.Sprintf(format, args...), lang, Flag.Lang)
+	ErrorfAt(Pos, errcode.UnsupportedVersion, "%s requires %s or later (-lang was set to %s; check go.mod)", FormatMessage(Pos, format, args...), lang, Flag.Lang)
 }
 
 // UpdateErrorDot is a clumsy hack that rewrites the last error,
@@ -156,8 +611,8 @@ func UpdateErrorDot(line string, name, expr string) {
 		return
 	}
 	e := &errorMsgs[len(errorMsgs)-1]
-	if strings.HasPrefix(e.msg, line) && e.msg == fmt.Sprintf("%v: undefined: %v\n", line, name) {
-		e.msg = fmt.Sprintf("%v: undefined: %v in %v\n", line, name, expr)
+	if strings.HasPrefix(e.msg, line) && len(e.lines) == 1 && e.lines[0].ID == MsgUndefined.ID && e.lines[0].Args["name"] == name {
+		e.setTemplate(MsgUndefinedIn, MsgArgs{"name": name, "expr": expr})
 	}
 }
 
@@ -174,12 +629,24 @@ func Warn(format string, args ...interface{}) {
 // so this should be used only when the user has opted in
 // to additional output by setting a particular flag.
 func WarnfAt(pos src.XPos, format string, args ...interface{}) {
-	addErrorMsg(pos, format, args...)
+	addErrorMsg(pos, true, format, args...)
 	if Flag.LowerM != 0 {
 		FlushErrors()
 	}
 }
 
+// NotefAt reports a note at pos, with the error code code: not an
+// error of its own, but information about other errors, such as how
+// many of them were left out.
+func NotefAt(pos src.XPos, code errcode.Code, format string, args ...interface{}) {
+	e := errorMsg{pos: pos, warning: true, code: code, backend: inBackend()}
+	e.setText(FormatMessage(pos, format, args...))
+	errorMu.Lock()
+	errorMsgs = append(errorMsgs, e)
+	streamDiag(e)
+	errorMu.Unlock()
+}
+
 // Fatalf reports a fatal error - an internal problem - at the current line and exits.
 // If other errors have already been printed, then Fatalf just quietly exits.
 // (The internal problem may have been caused by incomplete information
@@ -213,7 +680,7 @@ func FatalfAt(pos src.XPos, format string, args ...interface{}) {
 
 	if Debug.Panic != 0 || numErrors == 0 {
 		fmt.Printf("%v: internal compiler error: ", FmtPos(pos))
-		fmt.Printf(format, args...)
+		fmt.Print(FormatFatal(format, args...))
 		fmt.Printf("\n")
 
 		// If this is a released compiler version, ask for a bug report.
@@ -269,9 +736,24 @@ func hcrash() {
 // It flushes any pending errors, removes the output file, and exits.
 func ErrorExit() {
 	FlushErrors()
+	if numHidden > 0 || tooMany {
+		e := errorMsg{pos: lastShown, code: errcode.TooManyErrors}
+		if numHidden > 0 {
+			e.setTemplate(MsgTooManyErrors, MsgArgs{"count": numHidden})
+		} else {
+			e.setTemplate(MsgTooManyErrorsStop, nil)
+		}
+		printDiag(e)
+	}
+	if Flag.ErrSummary && numFlushed > 0 && !Flag.JSONDiag && !streamsToStdout() {
+		fmt.Printf("%s\n", errorSummary())
+	}
 	if Flag.LowerO != "" {
 		os.Remove(Flag.LowerO)
 	}
+	if Flag.SARIF != "" {
+		writeSARIF() // os.Exit skips the AtExit functions
+	}
 	os.Exit(2)
 }
 
@@ -0,0 +1,93 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"cmd/internal/src"
+)
+
+func TestByPos(t *testing.T) {
+	var tab src.PosTable
+	b := src.NewFileBase("x.go", "x.go")
+	p1 := tab.XPos(src.MakePos(b, 1, 1))
+	p2 := tab.XPos(src.MakePos(b, 2, 1))
+
+	// Errors of the frontend at a position keep the order they were
+	// reported in; those of the backend follow them in the same order,
+	// whatever order they were reported in.
+	want := []string{"d", "c", "z", "y", "a", "b"}
+	for _, msgs := range [][]errorMsg{
+		{
+			{pos: p2, msg: "b", backend: true},
+			{pos: p2, msg: "z"},
+			{pos: p1, msg: "c", backend: true},
+			{pos: p2, msg: "a", backend: true},
+			{pos: p2, msg: "y"},
+			{pos: p1, msg: "d"},
+		},
+		{
+			{pos: p2, msg: "a", backend: true},
+			{pos: p2, msg: "z"},
+			{pos: p2, msg: "y"},
+			{pos: p1, msg: "d"},
+			{pos: p2, msg: "b", backend: true},
+			{pos: p1, msg: "c", backend: true},
+		},
+	} {
+		sort.Stable(byPos(msgs))
+		var got []string
+		for _, e := range msgs {
+			got = append(got, e.msg)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("sorted errors: got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestFlushErrorsLimit(t *testing.T) {
+	defer func(saved int) { Flag.MaxErrors = saved }(Flag.MaxErrors)
+	Flag.MaxErrors = 2
+	defer func() { numShown, numHidden, numFlushed, lastShown = 0, 0, 0, src.NoXPos }()
+
+	// The backend reported these errors before CheckErrorLimit
+	// stopped the compilation.
+	var tab src.PosTable
+	b := src.NewFileBase("x.go", "x.go")
+	for _, line := range []uint{4, 1, 3, 2} {
+		errorMsgs = append(errorMsgs, errorMsg{
+			pos:     tab.XPos(src.MakePos(b, line, 1)),
+			msg:     fmt.Sprintf("x.go:%d: e\n", line),
+			backend: true,
+		})
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(saved *os.File) { os.Stdout = saved }(os.Stdout)
+	os.Stdout = w
+	FlushErrors()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(out), "x.go:1: e\nx.go:2: e\n"; got != want {
+		t.Errorf("FlushErrors printed %q, want %q", got, want)
+	}
+	if numHidden != 2 {
+		t.Errorf("FlushErrors left out %d errors, want 2", numHidden)
+	}
+}
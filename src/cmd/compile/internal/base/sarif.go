@@ -0,0 +1,223 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"encoding/json"
+	"internal/buildcfg"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"cmd/compile/internal/errcode"
+)
+
+// sarifDiags holds the diagnostics printed so far, for the -sarif log.
+var sarifDiags []jsonDiag
+
+// The types below are the parts of the SARIF 2.1.0 format, a standard
+// format for the output of static analysis tools, that -sarif uses.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver sarifDriver `json:"driver"`
+	} `json:"tool"`
+	// ColumnKind says how columns count characters. The compiler
+	// counts bytes, which SARIF has no kind for, so sarifPhysical
+	// converts its columns to code points.
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+// A sarifRule describes a kind of result: an error code or a warning
+// category.
+type sarifRule struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId,omitempty"`
+	Level            string          `json:"level"` // "error", "warning" or "note"
+	Message          sarifText       `json:"message"`
+	Locations        []sarifLocation `json:"locations,omitempty"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifText            `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   uint `json:"startLine"`
+	StartColumn uint `json:"startColumn,omitempty"`
+	EndLine     uint `json:"endLine,omitempty"`
+	EndColumn   uint `json:"endColumn,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifText             `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion `json:"deletedRegion"`
+	InsertedContent sarifText   `json:"insertedContent"`
+}
+
+// writeSARIF writes the diagnostics printed by the compilation as a
+// SARIF log, as -sarif asks. The log describes each error
+// code of the catalog and each warning category as a rule.
+func writeSARIF() {
+	var run sarifRun
+	driver := &run.Tool.Driver
+	driver.Name = "compile"
+	driver.Version = buildcfg.Version
+	driver.InformationURI = "https://pkg.go.dev/cmd/compile"
+	for _, info := range errcode.Catalog() {
+		driver.Rules = append(driver.Rules, sarifRule{info.Code.String(), info.Name, sarifText{info.Summary}})
+	}
+	for _, c := range warnCategories {
+		driver.Rules = append(driver.Rules, sarifRule{"W" + c.name, c.name, sarifText{c.summary}})
+	}
+	run.ColumnKind = "unicodeCodePoints"
+	run.Results = []sarifResult{} // not null when there are none
+
+	for _, d := range sarifDiags {
+		r := sarifResult{Level: d.Severity, Message: sarifText{d.Message}}
+		switch {
+		case d.Code != "":
+			r.RuleID = d.Code
+		case d.Category != "":
+			r.RuleID = "W" + d.Category
+		case d.Severity == "warning":
+			// An -m note about an optimization decision.
+			r.Level = "note"
+		}
+		if d.Pos != nil {
+			r.Locations = []sarifLocation{{PhysicalLocation: sarifPhysical(*d.Pos, d.End)}}
+		}
+		for _, rel := range d.Related {
+			if rel.Pos != nil {
+				r.RelatedLocations = append(r.RelatedLocations, sarifLocation{sarifPhysical(*rel.Pos, nil), &sarifText{rel.Message}})
+			}
+		}
+		for _, fix := range d.Fixes {
+			f := sarifFix{Description: sarifText{fix.Message}}
+			for _, e := range fix.Edits {
+				loc := sarifPhysical(e.Pos, &e.End)
+				f.ArtifactChanges = append(f.ArtifactChanges, sarifArtifactChange{
+					ArtifactLocation: loc.ArtifactLocation,
+					Replacements:     []sarifReplacement{{loc.Region, sarifText{e.NewText}}},
+				})
+			}
+			r.Fixes = append(r.Fixes, f)
+		}
+		run.Results = append(run.Results, r)
+	}
+
+	b, err := json.MarshalIndent(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}, "", "\t")
+	if err == nil {
+		err = ioutil.WriteFile(sarifFile(), append(b, '\n'), 0666)
+	}
+	if err != nil {
+		log.Fatalf("writing SARIF log: %v", err)
+	}
+}
+
+// sarifFile returns the name of the file to write the SARIF log to:
+// the argument of -sarif or, if that is a directory, a file in it
+// named for the package being compiled.
+func sarifFile() string {
+	if fi, err := os.Stat(Flag.SARIF); err == nil && fi.IsDir() {
+		pkgpath := Ctxt.Pkgpath
+		if pkgpath == "" {
+			pkgpath = "_" // no -p flag
+		}
+		return filepath.Join(Flag.SARIF, url.PathEscape(pkgpath)+".sarif")
+	}
+	return Flag.SARIF
+}
+
+// sarifPhysical returns the SARIF location of the text from pos to end
+// in pos's file, which is empty if end == pos. If end is nil, the
+// location is that of a diagnostic, which only has a start.
+func sarifPhysical(pos jsonPos, end *jsonPos) sarifPhysicalLocation {
+	loc := sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{sarifURI(pos.File)},
+		Region:           sarifRegion{StartLine: pos.Line, StartColumn: codePointCol(pos)},
+	}
+	if end != nil {
+		loc.Region.EndLine, loc.Region.EndColumn = end.Line, codePointCol(*end)
+	}
+	return loc
+}
+
+// codePointCol returns the column of pos, which counts bytes from 1,
+// as a count of code points from 1. If it cannot read pos's line, it
+// returns the column unchanged, which is right for ASCII text.
+func codePointCol(pos jsonPos) uint {
+	line, ok := sourceLine(pos.File, pos.Line)
+	if !ok || pos.Col == 0 || int(pos.Col-1) > len(line) {
+		return pos.Col
+	}
+	return uint(utf8.RuneCountInString(line[:pos.Col-1])) + 1
+}
+
+// sarifURI returns the URI of the file named file: a file URI if file
+// is absolute, and otherwise a relative reference.
+func sarifURI(file string) string {
+	file = filepath.ToSlash(file)
+	if !filepath.IsAbs(filepath.FromSlash(file)) {
+		return (&url.URL{Path: file}).String()
+	}
+	if !strings.HasPrefix(file, "/") {
+		file = "/" + file // a Windows path, like C:/x.go
+	}
+	return (&url.URL{Scheme: "file", Path: file}).String()
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"unicode/utf8"
+)
+
+// srcLines caches the lines of the source files that -snippets and
+// -sarif have read, or nil for a file that cannot be read.
+var srcLines = make(map[string][]string)
+
+// sourceLine returns line n, counting from 1, of file, without the
+// line ending, and reports whether the file could be read and has
+// such a line.
+func sourceLine(file string, n uint) (string, bool) {
+	lines, ok := srcLines[file]
+	if !ok {
+		if data, err := ioutil.ReadFile(file); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		srcLines[file] = lines
+	}
+	if n == 0 || int(n) > len(lines) {
+		return "", false
+	}
+	return strings.TrimSuffix(lines[n-1], "\r"), true
+}
+
+// snippet returns the lines that -snippets prints below the first line
+// of e: the source line at e.pos and a line marking e.pos with a caret
+// and the rest of the text up to e.end, if known, with tildes. It
+// returns "" if it cannot read the source line.
+func (e *errorMsg) snippet() string {
+	if !e.pos.IsKnown() {
+		return ""
+	}
+	// Use the position in the file the compiler read, not the one
+	// a //line directive makes up.
+	p := Ctxt.PosTable.Pos(e.pos)
+	file := p.Filename()
+	line, ok := sourceLine(file, p.Line())
+	if !ok {
+		return ""
+	}
+	var endCol uint
+	if e.end.IsKnown() {
+		end := Ctxt.PosTable.Pos(e.end)
+		switch {
+		case end.Filename() != file || end.Line() < p.Line():
+			// No span.
+		case end.Line() > p.Line():
+			endCol = ^uint(0) // to the end of the line
+		default:
+			endCol = end.Col()
+		}
+	}
+	return renderSnippet(line, p.Col(), endCol)
+}
+
+// renderSnippet returns line, indented by a tab, and a line below it
+// with a caret under the byte at column col and tildes under the
+// following ones up to column endCol, exclusive. Columns count bytes
+// from 1. It returns "" if col is not in line.
+//
+// The marks line up in a terminal that shows each rune in one column:
+// the marking line has a space for each rune and a tab for each tab
+// before col.
+func renderSnippet(line string, col, endCol uint) string {
+	if col == 0 || col > uint(len(line))+1 {
+		return ""
+	}
+	if endCol > uint(len(line))+1 {
+		endCol = uint(len(line)) + 1
+	}
+	var b bytes.Buffer
+	b.WriteString("\t" + line + "\n\t")
+	for _, r := range line[:col-1] {
+		if r == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteByte('^')
+	if endCol > col {
+		if n := utf8.RuneCountInString(line[col-1:endCol-1]) - 1; n > 0 {
+			b.WriteString(strings.Repeat("~", n))
+		}
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
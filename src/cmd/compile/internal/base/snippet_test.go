@@ -0,0 +1,33 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import "testing"
+
+func TestRenderSnippet(t *testing.T) {
+	tests := []struct {
+		line        string
+		col, endCol uint
+		want        string
+	}{
+		{"\tos.Exit(undefined)", 10, 19, "\t\tos.Exit(undefined)\n\t\t        ^~~~~~~~~\n"},
+		{"\tos.Exit(undefined)", 10, 0, "\t\tos.Exit(undefined)\n\t\t        ^\n"},
+		{"x := y", 1, 2, "\tx := y\n\t^\n"},
+		// Columns count bytes, marks count runes.
+		{`s := "héllo" + 1`, 6, 14, "\ts := \"héllo\" + 1\n\t     ^~~~~~~\n"},
+		{`s := "é" + y`, 12, 13, "\ts := \"é\" + y\n\t          ^\n"},
+		// A span past the end of the line stops there.
+		{"f(a,", 3, ^uint(0), "\tf(a,\n\t  ^~\n"},
+		// Just past the end of the line, as for an unexpected newline.
+		{"f(a,", 5, 0, "\tf(a,\n\t    ^\n"},
+		{"f(a,", 6, 0, ""},
+		{"f(a,", 0, 0, ""},
+	}
+	for _, test := range tests {
+		if got := renderSnippet(test.line, test.col, test.endCol); got != test.want {
+			t.Errorf("renderSnippet(%q, %d, %d) = %q, want %q", test.line, test.col, test.endCol, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+)
+
+// -diagstream writes each diagnostic to a stream as soon as it is
+// reported, as a line with the JSON object that -jsondiag would
+// print, so that an editor can show the errors of a large package
+// while the compiler is still working on it. Diagnostics arrive in the
+// order they are reported, not sorted, and -maxerrors does not apply.
+//
+// The stream is a file the compiler opens for writing, typically a
+// named pipe the editor reads from (a FIFO, or \\.\pipe\name on
+// Windows), or standard output if the file is "-". With "-" the
+// compiler prints no other diagnostics, so that every line of its
+// standard output is a JSON object.
+
+// diagStream is the stream of -diagstream, or nil, and streamed records
+// the diagnostics written to it, to leave out duplicates. Both are
+// protected by errorMu.
+var (
+	diagStream io.Writer
+	streamed   map[string]bool
+)
+
+// openDiagStream opens the stream of -diagstream.
+func openDiagStream(file string) {
+	if file == "-" {
+		diagStream = os.Stdout
+		return
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		log.Fatalf("-diagstream: %v", err)
+	}
+	diagStream = f
+	AtExit(func() { f.Close() })
+}
+
+// streamsToStdout reports whether -diagstream writes to standard
+// output, in place of the usual diagnostics.
+func streamsToStdout() bool {
+	return Flag.DiagStream == "-"
+}
+
+// streamDiag writes e to the stream of -diagstream, if there is one
+// and e is not a duplicate. The caller holds errorMu.
+func streamDiag(e errorMsg) {
+	if diagStream == nil {
+		return
+	}
+	if streamed[e.msg] { // e.msg starts with the position
+		return
+	}
+	if streamed == nil {
+		streamed = make(map[string]bool)
+	}
+	streamed[e.msg] = true
+	b, err := json.Marshal(newJSONDiag(e))
+	if err != nil {
+		panic(err) // not Fatalf, which takes errorMu
+	}
+	if _, err := diagStream.Write(append(b, '\n')); err != nil {
+		// The editor stopped listening; compile on without it.
+		diagStream = nil
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cmd/compile/internal/errcode"
+	"cmd/internal/src"
+)
+
+// A MsgTemplate is the text of a diagnostic message with named
+// parameters in braces, as in "{name} declared but not used". The
+// templates are listed in messages.go, where their IDs name them for
+// translations (see RegisterTranslation). Code is the code of the
+// errors that ErrorAt reports with the template.
+type MsgTemplate struct {
+	ID   string
+	Code errcode.Code
+	Text string
+
+	params []string // parameter names, in the order they appear in Text
+}
+
+// MsgArgs holds the values of the parameters of a message template,
+// by name.
+type MsgArgs map[string]interface{}
+
+// A MsgLine identifies a line of a diagnostic message as one that a
+// message template renders: ID is the ID of the template, and Args
+// holds the text of the values of its parameters, by name. The
+// compiler translates the message line by line from these, not from
+// the text of the message.
+type MsgLine struct {
+	ID   string
+	Args map[string]string
+}
+
+// msgTemplates lists the message templates, in the order of their IDs
+// in messages.go.
+var msgTemplates []*MsgTemplate
+
+// templateParamRx matches a parameter in the text of a template.
+var templateParamRx = regexp.MustCompile(`\{([a-z][a-zA-Z0-9]*)\}`)
+
+// newMsgTemplate returns a template with the given ID, code and text,
+// and adds it to msgTemplates.
+func newMsgTemplate(id string, code errcode.Code, text string) *MsgTemplate {
+	t := &MsgTemplate{ID: id, Code: code, Text: text, params: templateParams(text)}
+	msgTemplates = append(msgTemplates, t)
+	return t
+}
+
+// templateParams returns the names of the parameters in the text of a
+// template, in the order they appear in it.
+func templateParams(text string) []string {
+	var params []string
+	for _, m := range templateParamRx.FindAllStringSubmatch(text, -1) {
+		params = append(params, m[1])
+	}
+	return params
+}
+
+// fillTemplate returns text, the text of a template, with each
+// parameter replaced by its value in args.
+func fillTemplate(text string, args map[string]string) string {
+	return templateParamRx.ReplaceAllStringFunc(text, func(p string) string {
+		return args[p[1:len(p)-1]]
+	})
+}
+
+// line returns the message line that t renders with args for a
+// diagnostic at pos. It formats the values with %v through
+// FormatMessage, so that symbols and types read as in other messages.
+func (t *MsgTemplate) line(pos src.XPos, args MsgArgs) MsgLine {
+	l := MsgLine{ID: t.ID, Args: make(map[string]string)}
+	for _, p := range t.params {
+		v, ok := args[p]
+		if !ok {
+			Fatalf("message %s: no value for {%s}", t.ID, p)
+		}
+		l.Args[p] = FormatMessage(pos, "%v", v)
+	}
+	return l
+}
+
+// Render returns the message that t renders with args for a diagnostic
+// at pos.
+func (t *MsgTemplate) Render(pos src.XPos, args MsgArgs) string {
+	return fillTemplate(t.Text, t.line(pos, args).Args)
+}
+
+// RenderSummary returns the line of the summary after the errors that
+// t renders with args, in the translation of t if there is one.
+func (t *MsgTemplate) RenderSummary(args MsgArgs) string {
+	text := t.Text
+	if tr := translations[t.ID]; tr != nil {
+		text = tr.Text
+	}
+	vals := make(map[string]string)
+	for p, v := range args {
+		vals[p] = fmt.Sprint(v)
+	}
+	return fillTemplate(text, vals)
+}
+
+// ErrorAt reports the error message that t renders with args at pos.
+func (t *MsgTemplate) ErrorAt(pos src.XPos, args MsgArgs) {
+	l := t.line(pos, args)
+	errorfAt(pos, t.Code, ErrorDetails{Lines: []MsgLine{l}}, "", fillTemplate(t.Text, l.Args))
+}
+
+// setTemplate sets the message of e to the one that t renders with
+// args.
+func (e *errorMsg) setTemplate(t *MsgTemplate, args MsgArgs) {
+	l := t.line(e.pos, args)
+	e.lines = []MsgLine{l}
+	e.setText(fillTemplate(t.Text, l.Args))
+}
+
+// translations maps the IDs of message templates to the templates of
+// their translations, if RegisterTranslation installed one.
+var translations map[string]*MsgTemplate
+
+// RegisterTranslation installs a translation of diagnostic messages:
+// texts maps the IDs of message templates to translated texts with the
+// same parameters. A build of the toolchain plugs in a translation by
+// adding a file to this package whose init function calls it.
+//
+// The compiler then prints each message, and each note on the lines
+// that follow it, that a template with a translation renders, in the
+// translation. The report sites of the messages of the type checker
+// name the templates that render them (see types2.MsgLine). The
+// -jsondiag and -sarif output, like the error codes, stay in English,
+// for tools.
+func RegisterTranslation(texts map[string]string) {
+	byID := make(map[string]*MsgTemplate)
+	for _, t := range msgTemplates {
+		byID[t.ID] = t
+	}
+	translations = make(map[string]*MsgTemplate)
+	for id, text := range texts {
+		t := byID[id]
+		if t == nil {
+			panic(fmt.Sprintf("translation of unknown message %s", id))
+		}
+		tr := &MsgTemplate{ID: id, Text: text, params: templateParams(text)}
+		if !sameParams(t.params, tr.params) {
+			panic(fmt.Sprintf("translation of message %s: parameters %v, want %v", id, tr.params, t.params))
+		}
+		translations[id] = tr
+	}
+}
+
+// sameParams reports whether a and b name the same parameters, in any
+// order and number.
+func sameParams(a, b []string) bool {
+	set := func(names []string) map[string]bool {
+		m := make(map[string]bool)
+		for _, n := range names {
+			m[n] = true
+		}
+		return m
+	}
+	sa, sb := set(a), set(b)
+	if len(sa) != len(sb) {
+		return false
+	}
+	for n := range sa {
+		if !sb[n] {
+			return false
+		}
+	}
+	return true
+}
+
+// notePosRx matches the position at the start of a note on a further
+// line of a message, as in "x.go:3:6: other declaration of T".
+var notePosRx = regexp.MustCompile(`^\S+:\d+(:\d+)?: `)
+
+// translate returns text, the text of a message, with its first line
+// and the notes on the lines that follow translated where lines, which
+// identifies the template of each line, has a template with a
+// translation.
+func translate(text string, lines []MsgLine) string {
+	if translations == nil || lines == nil {
+		return text
+	}
+	parts := strings.Split(text, "\n\t")
+	for i, l := range lines {
+		tr := translations[l.ID]
+		if tr == nil || i >= len(parts) {
+			continue
+		}
+		prefix := ""
+		if i > 0 {
+			prefix = notePosRx.FindString(parts[i])
+		}
+		parts[i] = prefix + fillTemplate(tr.Text, l.Args)
+	}
+	return strings.Join(parts, "\n\t")
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"testing"
+
+	"cmd/internal/src"
+)
+
+func TestMsgTemplateRender(t *testing.T) {
+	tmpl := newMsgTemplate("Test", 0, "{x} is {pct}% of {y}, not {x}")
+	msgTemplates = msgTemplates[:len(msgTemplates)-1] // not a real message
+
+	got := tmpl.Render(src.NoXPos, MsgArgs{"x": "a", "y": 3, "pct": 50})
+	if want := "a is 50% of 3, not a"; got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer func() { translations = nil }()
+	RegisterTranslation(map[string]string{
+		"Undefined":        "nicht definiert: {name}",
+		"Redeclared":       "{name} in diesem Block erneut deklariert",
+		"OtherDeclaration": "andere Deklaration von {name}",
+	})
+
+	undefined := MsgLine{"Undefined", map[string]string{"name": "x"}}
+	tests := []struct {
+		text  string
+		lines []MsgLine
+		want  string
+	}{
+		{"undefined: x", []MsgLine{undefined}, "nicht definiert: x"},
+		{"undefined: x", nil, "undefined: x"}, // not rendered by a template
+		{"undefined: x in y.x", []MsgLine{{"UndefinedIn", map[string]string{"name": "x", "expr": "y.x"}}}, "undefined: x in y.x"}, // UndefinedIn has no translation
+		{"x redeclared in this block\n\tx.go:3:6: other declaration of x",
+			[]MsgLine{{"Redeclared", map[string]string{"name": "x"}}, {"OtherDeclaration", map[string]string{"name": "x"}}},
+			"x in diesem Block erneut deklariert\n\tx.go:3:6: andere Deklaration von x"},
+		{"x redeclared in this block\n\tsome note", []MsgLine{{"Redeclared", map[string]string{"name": "x"}}},
+			"x in diesem Block erneut deklariert\n\tsome note"},
+	}
+	for _, test := range tests {
+		if got := translate(test.text, test.lines); got != test.want {
+			t.Errorf("translate(%q, %v) = %q, want %q", test.text, test.lines, got, test.want)
+		}
+	}
+}
+
+func TestRenderSummary(t *testing.T) {
+	defer func() { translations = nil }()
+	RegisterTranslation(map[string]string{"ErrorsInFile": "{file}: {count}"})
+
+	args := MsgArgs{"count": 2, "file": "a.go"}
+	if got, want := MsgErrorsInFile.RenderSummary(args), "a.go: 2"; got != want {
+		t.Errorf("RenderSummary = %q, want %q", got, want)
+	}
+	if got, want := MsgErrorSummary.RenderSummary(MsgArgs{"count": 3, "pkg": "p", "files": "a.go: 2, b.go: 1"}), "3 errors in p: a.go: 2, b.go: 1"; got != want {
+		t.Errorf("RenderSummary without translation = %q, want %q", got, want)
+	}
+}
+
+func TestRegisterTranslationParams(t *testing.T) {
+	defer func() { translations = nil }()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterTranslation accepted a translation with other parameters")
+		}
+	}()
+	RegisterTranslation(map[string]string{"Undefined": "nicht definiert: {nom}"})
+}
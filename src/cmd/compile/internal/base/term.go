@@ -0,0 +1,97 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cmd/internal/src"
+)
+
+// This file implements what the compiler knows about terminals. It
+// only uses their features when it prints to one, as it does not
+// under go build, which collects its output.
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// showsHyperlinks reports whether the terminal that the environment
+// variables getenv looks up describe shows OSC 8 hyperlinks. There is
+// no portable way to ask a terminal, so it goes by the variables that
+// the terminals known to show them set.
+func showsHyperlinks(getenv func(string) string) bool {
+	switch getenv("TERM") {
+	case "dumb":
+		return false
+	case "xterm-kitty", "foot", "alacritty":
+		return true
+	}
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty":
+		return true
+	}
+	if getenv("WT_SESSION") != "" || getenv("KONSOLE_VERSION") != "" || getenv("DOMTERM") != "" {
+		return true
+	}
+	// GNOME Terminal and other terminals based on VTE show them
+	// since VTE 0.50.
+	v, err := strconv.Atoi(getenv("VTE_VERSION"))
+	return err == nil && v >= 5000
+}
+
+// hyperlink returns text as an OSC 8 hyperlink to url.
+func hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// linkURL returns the URL that the -hyperlinks template tmpl gives for
+// line and column col of file, an absolute file name.
+func linkURL(tmpl, file string, line, col uint) string {
+	file = filepath.ToSlash(file)
+	if !strings.HasPrefix(file, "/") {
+		file = "/" + file // as in C:/x.go
+	}
+	return strings.NewReplacer(
+		"{file}", (&url.URL{Path: file}).EscapedPath(),
+		"{line}", strconv.FormatUint(uint64(line), 10),
+		"{col}", strconv.FormatUint(uint64(col), 10),
+	).Replace(tmpl)
+}
+
+// posURL returns the -hyperlinks URL for pos, in the file the compiler
+// read rather than the one a //line directive makes up.
+func posURL(pos src.XPos) string {
+	p := Ctxt.PosTable.Pos(pos)
+	file := p.Filename()
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	return linkURL(Flag.Hyperlinks, file, p.Line(), p.Col())
+}
+
+// withLinks returns the printed form of e with its position, and those
+// of its "see also" lines, as -hyperlinks hyperlinks.
+func (e *errorMsg) withLinks() string {
+	msg := e.msg
+	if e.pos.IsKnown() {
+		if p := FmtPos(e.pos); strings.HasPrefix(msg, p+": ") {
+			msg = hyperlink(posURL(e.pos), p) + msg[len(p):]
+		}
+	}
+	for _, r := range e.related {
+		if r.Pos.IsKnown() {
+			p := FmtPos(r.Pos)
+			msg = strings.Replace(msg, "\n\t"+p+": see also: ", "\n\t"+hyperlink(posURL(r.Pos), p)+": see also: ", 1)
+		}
+	}
+	return msg
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import "testing"
+
+func TestShowsHyperlinks(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{"TERM": "xterm-256color"}, false},
+		{map[string]string{"TERM": "xterm-kitty"}, true},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, true},
+		{map[string]string{"TERM": "dumb", "TERM_PROGRAM": "vscode"}, false},
+		{map[string]string{"WT_SESSION": "e2b4c6a0"}, true},
+		{map[string]string{"VTE_VERSION": "6003"}, true},
+		{map[string]string{"VTE_VERSION": "4205"}, false},
+	}
+	for _, test := range tests {
+		getenv := func(key string) string { return test.env[key] }
+		if got := showsHyperlinks(getenv); got != test.want {
+			t.Errorf("showsHyperlinks(%v) = %v, want %v", test.env, got, test.want)
+		}
+	}
+}
+
+func TestLinkURL(t *testing.T) {
+	tests := []struct {
+		tmpl, file string
+		want       string
+	}{
+		{"file://{file}", "/home/gopher/x.go", "file:///home/gopher/x.go"},
+		{"file://{file}", "/home/go pher/x%y.go", "file:///home/go%20pher/x%25y.go"},
+		{"vscode://file{file}:{line}:{col}", "/src/x.go", "vscode://file/src/x.go:12:3"},
+		{"file://{file}", "C:/src/x.go", "file:///C:/src/x.go"},
+	}
+	for _, test := range tests {
+		if got := linkURL(test.tmpl, test.file, 12, 3); got != test.want {
+			t.Errorf("linkURL(%q, %q, 12, 3) = %q, want %q", test.tmpl, test.file, got, test.want)
+		}
+	}
+}
+
+func TestHyperlink(t *testing.T) {
+	got := hyperlink("file:///x.go", "x.go:1:2")
+	if want := "\x1b]8;;file:///x.go\x1b\\x.go:1:2\x1b]8;;\x1b\\"; got != want {
+		t.Errorf("hyperlink = %q, want %q", got, want)
+	}
+}
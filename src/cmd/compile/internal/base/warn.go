@@ -0,0 +1,66 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"cmd/internal/src"
+)
+
+// A WarnCategory is a category of warnings: checks for code that is
+// valid but likely wrong. Unlike the notes that -m prints about
+// optimization decisions, warnings concern the code itself. Each
+// category is off unless its -W flag, like -Wpragma for WarnPragma,
+// or -Wall enables it. -Werror makes the warnings errors.
+type WarnCategory int
+
+const (
+	WarnConversion WarnCategory = iota // suspicious conversions, as from int to string
+	WarnPragma                         // misused compiler directives
+	WarnSymCollide                     // exported names that collide when case is ignored
+
+	numWarnCategories
+)
+
+// warnCategories holds, for each category, its name, its enabling
+// flag and a summary of what its warnings report.
+// The flag of a category named "name" is -Wname.
+var warnCategories = [numWarnCategories]struct {
+	name    string
+	flag    *bool
+	summary string
+}{
+	WarnConversion: {"conversion", &Flag.WConversion, "conversion yields an unexpected result"},
+	WarnPragma:     {"pragma", &Flag.WPragma, "compiler directive has no effect"},
+	WarnSymCollide: {"symcollide", &Flag.WSymCollide, "exported names collide when case and compatibility characters are ignored"},
+}
+
+func (c WarnCategory) String() string {
+	return warnCategories[c].name
+}
+
+// Enabled reports whether warnings of category c are enabled.
+// Callers can use it to skip checks that are costly.
+func (c WarnCategory) Enabled() bool {
+	return Flag.WAll || *warnCategories[c].flag
+}
+
+// WarningfAt reports a formatted warning of category c at pos, if c is
+// enabled. The warning is an error if -Werror is set.
+func WarningfAt(pos src.XPos, c WarnCategory, format string, args ...interface{}) {
+	if !c.Enabled() {
+		return
+	}
+	msg := FormatMessage(pos, format, args...)
+	if Flag.WError {
+		errorfAt(pos, 0, ErrorDetails{}, c.String(), msg)
+		return
+	}
+	e := errorMsg{pos: pos, warning: true, wcat: c.String(), backend: inBackend()}
+	e.setText(msg)
+	errorMu.Lock()
+	errorMsgs = append(errorMsgs, e)
+	streamDiag(e)
+	errorMu.Unlock()
+}
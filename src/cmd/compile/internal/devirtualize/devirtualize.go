@@ -10,6 +10,7 @@ package devirtualize
 import (
 	"cmd/compile/internal/base"
 	"cmd/compile/internal/ir"
+	"cmd/compile/internal/logopt"
 	"cmd/compile/internal/typecheck"
 	"cmd/compile/internal/types"
 )
@@ -50,6 +51,10 @@ func Call(call *ir.CallExpr) {
 		if base.Flag.LowerM != 0 {
 			base.WarnfAt(call.Pos(), "devirtualizing %v to %v", sel, typ)
 		}
+		if logopt.Enabled() {
+			logopt.LogOpt(call.Pos(), "devirtualize", "devirtualize", ir.FuncName(ir.CurFunc),
+				logopt.Msgf("devirtualizing %v to %v", sel, types.LogType{T: typ}))
+		}
 		call.SetOp(ir.OCALLMETH)
 		call.X = x
 	case ir.ODOTINTER:
@@ -58,6 +63,10 @@ func Call(call *ir.CallExpr) {
 		if base.Flag.LowerM != 0 {
 			base.WarnfAt(call.Pos(), "partially devirtualizing %v to %v", sel, typ)
 		}
+		if logopt.Enabled() {
+			logopt.LogOpt(call.Pos(), "devirtualize", "devirtualize", ir.FuncName(ir.CurFunc),
+				logopt.Msgf("partially devirtualizing %v to %v", sel, types.LogType{T: typ}))
+		}
 		call.SetOp(ir.OCALLINTER)
 		call.X = x
 	default:
@@ -65,6 +74,10 @@ func Call(call *ir.CallExpr) {
 		if base.Flag.LowerM != 0 {
 			base.WarnfAt(call.Pos(), "failed to devirtualize %v (%v)", x, x.Op())
 		}
+		if logopt.Enabled() {
+			logopt.LogOpt(call.Pos(), "cannotDevirtualize", "devirtualize", ir.FuncName(ir.CurFunc),
+				logopt.Msgf("failed to devirtualize %v (%v)", x, x.Op()))
+		}
 		return
 	}
 
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"internal/buildcfg"
 	"sort"
+	"strings"
 
 	"cmd/compile/internal/base"
 	"cmd/compile/internal/ir"
@@ -23,7 +24,7 @@ import (
 	"cmd/internal/src"
 )
 
-func Info(fnsym *obj.LSym, infosym *obj.LSym, curfn interface{}) ([]dwarf.Scope, dwarf.InlCalls) {
+func Info(fnsym *obj.LSym, infosym *obj.LSym, curfn interface{}) ([]dwarf.Scope, dwarf.InlCalls, []dwarf.TypeParam) {
 	fn := curfn.(*ir.Func)
 
 	if fn.Nname != nil {
@@ -96,6 +97,7 @@ func Info(fnsym *obj.LSym, infosym *obj.LSym, curfn interface{}) ([]dwarf.Scope,
 	}
 
 	decls, dwarfVars := createDwarfVars(fnsym, isODCLFUNC, fn, apdecls)
+	tparams := createTypeParams(fnsym, fn)
 
 	// For each type referenced by the functions auto vars but not
 	// already referenced by a dwarf var, attach an R_USETYPE relocation to
@@ -124,7 +126,34 @@ func Info(fnsym *obj.LSym, infosym *obj.LSym, curfn interface{}) ([]dwarf.Scope,
 	if base.Flag.GenDwarfInl > 0 {
 		inlcalls = assembleInlines(fnsym, dwarfVars)
 	}
-	return scopes, inlcalls
+	return scopes, inlcalls, tparams
+}
+
+// createTypeParams returns the DWARF type parameters of fn, if it is a
+// shape-based instantiation. Each one refers to its shape type; the
+// type argument of a particular call is described by the entry of the
+// dictionary passed to it, whose index is the type parameter's index.
+func createTypeParams(fnsym *obj.LSym, fn *ir.Func) []dwarf.TypeParam {
+	if fn.Inst == nil {
+		return nil
+	}
+	tparams := make([]dwarf.TypeParam, len(fn.Inst.TParams))
+	for i, tp := range fn.Inst.TParams {
+		// The noder qualifies type parameter names by their
+		// declaration, as in F.T; use the name as declared.
+		name := tp.Sym().Name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		gotype := reflectdata.TypeLinksym(fn.Inst.Shapes[i])
+		fnsym.Func().RecordAutoType(gotype)
+		tparams[i] = dwarf.TypeParam{
+			Name:      name,
+			Type:      base.Ctxt.Lookup(dwarf.InfoPrefix + gotype.Name[len("type."):]),
+			DictIndex: uint16(i),
+		}
+	}
+	return tparams
 }
 
 func declPos(decl *ir.Name) src.XPos {
@@ -0,0 +1,218 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package errcode defines the codes that identify classes of compiler
+// errors, so that tools can treat the errors of a class alike without
+// matching the text of the messages, which changes from release to
+// release. The code that reports an error gives it its code: the
+// parser (package syntax), the type checker (package types2) and the
+// rest of the compiler (see base.ErrorfAt).
+//
+// Codes are stable: do not change their values, and add new codes at
+// the end. The names follow those of the error codes of go/types
+// where there is one.
+package errcode
+
+import "fmt"
+
+// A Code identifies a class of compiler errors. The zero Code is that
+// of errors that belong to no class in the catalog.
+type Code int
+
+const (
+	_ Code = iota
+
+	SyntaxError
+	UndeclaredName
+	UndeclaredImportedName
+	MissingFieldOrMethod
+	UnusedVar
+	UnusedImport
+	UnusedLabel
+	UnusedExpr
+	DuplicateDecl
+	MismatchedTypes
+	IncompatibleAssign
+	InvalidIfaceAssign
+	InvalidConversion
+	WrongArgCount
+	WrongResultCount
+	WrongAssignCount
+	MissingReturn
+	InvalidCond
+	UnassignableOperand
+	UndefinedOp
+	InvalidArgument
+	NotAType
+	NotAnExpr
+	InvalidDeclCycle
+	InvalidInitCycle
+	NumericOverflow
+	TruncatedFloat
+	DivByZero
+	BrokenImport
+	MismatchedPkgName
+	DuplicateCase
+	DuplicateLitKey
+	CannotInferTypeArgs
+	WrongTypeArgCount
+	NotInstantiated
+	MisplacedBranch
+	MissingLitField
+	MixedStructLit
+	MissingFuncBody
+	InvalidRecv
+	DuplicateMethod
+	TypeTooLarge
+	InvalidDirective
+	UnsupportedVersion
+	UntypedNil
+	ImpossibleAssert
+	InvalidPkgUse
+	NoNewVar
+	TooManyErrors
+	InvalidToken
+	InvalidSliceExpr
+	InvalidIndex
+	MisplacedDotDotDot
+	IncomparableMapKey
+	InvalidDefer
+	InvalidGo
+	InvalidIota
+	InvalidStructLit
+	TooManyValues
+	UntypedLit
+	InvalidInitDecl
+	InvalidMainDecl
+	RepeatedDecl
+	InvalidSelectCase
+	DuplicateDefault
+	UnexportedLitField
+	InvalidBlank
+	InvalidConstInit
+	UncalledBuiltin
+	UndeclaredLabel
+	DuplicateLabel
+	InvalidConstType
+	InvalidLit
+	InvalidAssert
+	BadRecv
+	MissingLitKey
+	InvalidLitField
+	InvalidArrayLen
+	BlankPkgName
+	AmbiguousSelector
+	InvalidExprSwitch
+	InvalidPtrEmbed
+	CascadingErrors
+	InvalidConstVal
+	InvalidUntypedConversion
+	InvalidChanAssign
+)
+
+// String returns the code as the compiler prints it, as in "E0002".
+func (c Code) String() string {
+	return fmt.Sprintf("E%04d", int(c))
+}
+
+// An Info describes a code in the catalog.
+type Info struct {
+	Code    Code
+	Name    string
+	Summary string
+}
+
+// Catalog returns the descriptions of all codes, ordered by code.
+func Catalog() []Info {
+	return append([]Info(nil), catalog...)
+}
+
+var catalog = []Info{
+	{SyntaxError, "SyntaxError", "the source does not parse"},
+	{UndeclaredName, "UndeclaredName", "an identifier is not declared"},
+	{UndeclaredImportedName, "UndeclaredImportedName", "a qualified identifier refers to a name the package does not declare or export"},
+	{MissingFieldOrMethod, "MissingFieldOrMethod", "a selector names no field or method of its operand"},
+	{UnusedVar, "UnusedVar", "a local variable is declared but not used"},
+	{UnusedImport, "UnusedImport", "a package is imported but not used"},
+	{UnusedLabel, "UnusedLabel", "a label is declared but not used"},
+	{UnusedExpr, "UnusedExpr", "the value of an expression statement is not used"},
+	{DuplicateDecl, "DuplicateDecl", "a name is declared twice in the same block"},
+	{MismatchedTypes, "MismatchedTypes", "the operands of a binary operation have different types"},
+	{IncompatibleAssign, "IncompatibleAssign", "a value is not assignable to the type it is used as"},
+	{InvalidIfaceAssign, "InvalidIfaceAssign", "a type does not implement an interface or satisfy a constraint"},
+	{InvalidConversion, "InvalidConversion", "a value cannot be converted to a type"},
+	{WrongArgCount, "WrongArgCount", "a call has too many or too few arguments"},
+	{WrongResultCount, "WrongResultCount", "a return statement has too many or too few values"},
+	{WrongAssignCount, "WrongAssignCount", "the two sides of an assignment have different numbers of values"},
+	{MissingReturn, "MissingReturn", "a function with results can end without a return statement"},
+	{InvalidCond, "InvalidCond", "the condition of an if or for statement is not a boolean"},
+	{UnassignableOperand, "UnassignableOperand", "the left side of an assignment cannot be assigned to"},
+	{UndefinedOp, "UndefinedOp", "an operator is applied to an operand it is not defined on"},
+	{InvalidArgument, "InvalidArgument", "an argument or operand is invalid for its operation"},
+	{NotAType, "NotAType", "a name that is not a type is used as one"},
+	{NotAnExpr, "NotAnExpr", "a type or other non-value is used as a value"},
+	{InvalidDeclCycle, "InvalidDeclCycle", "a type or constant is defined in terms of itself"},
+	{InvalidInitCycle, "InvalidInitCycle", "package-level variables depend on each other for their initialization"},
+	{NumericOverflow, "NumericOverflow", "a constant does not fit in its type"},
+	{TruncatedFloat, "TruncatedFloat", "a constant with a fractional part is converted to an integer type"},
+	{DivByZero, "DivByZero", "a constant division or remainder divides by zero"},
+	{BrokenImport, "BrokenImport", "an imported package cannot be found or read"},
+	{MismatchedPkgName, "MismatchedPkgName", "the files of a package declare different package names"},
+	{DuplicateCase, "DuplicateCase", "a switch has two equal cases"},
+	{DuplicateLitKey, "DuplicateLitKey", "a composite literal repeats a key, index or field"},
+	{CannotInferTypeArgs, "CannotInferTypeArgs", "the type arguments of a generic function cannot be inferred"},
+	{WrongTypeArgCount, "WrongTypeArgCount", "a generic type or function is given the wrong number of type arguments"},
+	{NotInstantiated, "NotInstantiated", "a generic type or function is used without type arguments"},
+	{MisplacedBranch, "MisplacedBranch", "a break, continue, fallthrough or goto statement is misplaced"},
+	{MissingLitField, "MissingLitField", "a struct literal names a field the struct does not have"},
+	{MixedStructLit, "MixedStructLit", "a struct literal mixes field:value and value elements"},
+	{MissingFuncBody, "MissingFuncBody", "a function declared in Go has no body"},
+	{InvalidRecv, "InvalidRecv", "a method receiver or the type it is declared on is invalid"},
+	{DuplicateMethod, "DuplicateMethod", "a method is declared twice"},
+	{TypeTooLarge, "TypeTooLarge", "a type or stack frame exceeds an implementation limit on its size"},
+	{InvalidDirective, "InvalidDirective", "a //go: directive is misplaced or misused"},
+	{UnsupportedVersion, "UnsupportedVersion", "the code uses a feature of a later Go version than -lang selects"},
+	{UntypedNil, "UntypedNil", "nil is used where a typed value is needed"},
+	{ImpossibleAssert, "ImpossibleAssert", "a type assertion or type switch case can never succeed"},
+	{InvalidPkgUse, "InvalidPkgUse", "a package name is used without a selector"},
+	{NoNewVar, "NoNewVar", "a short variable declaration declares no new variables"},
+	{TooManyErrors, "TooManyErrors", "the compiler stopped at the limit on errors that -maxerrors sets"},
+	{InvalidToken, "InvalidToken", "the source contains a malformed literal, comment or character"},
+	{InvalidSliceExpr, "InvalidSliceExpr", "a slice expression has invalid or missing indices"},
+	{InvalidIndex, "InvalidIndex", "an index is out of range or not an integer"},
+	{MisplacedDotDotDot, "MisplacedDotDotDot", "... is used where it is not allowed"},
+	{IncomparableMapKey, "IncomparableMapKey", "a map key type does not support =="},
+	{InvalidDefer, "InvalidDefer", "a defer statement does not call a function or discards its result"},
+	{InvalidGo, "InvalidGo", "a go statement does not call a function or discards its result"},
+	{InvalidIota, "InvalidIota", "iota is used outside a constant declaration"},
+	{InvalidStructLit, "InvalidStructLit", "a struct literal without field names has too many or too few values"},
+	{TooManyValues, "TooManyValues", "a multiple-value expression is used as a single value"},
+	{UntypedLit, "UntypedLit", "a composite literal lacks its type"},
+	{InvalidInitDecl, "InvalidInitDecl", "init is declared as something other than a function without parameters or results"},
+	{InvalidMainDecl, "InvalidMainDecl", "main is declared as something other than a function without parameters or results"},
+	{RepeatedDecl, "RepeatedDecl", "a short variable declaration names a variable twice"},
+	{InvalidSelectCase, "InvalidSelectCase", "a select case is neither a send nor a receive"},
+	{DuplicateDefault, "DuplicateDefault", "a switch or select has two default cases"},
+	{UnexportedLitField, "UnexportedLitField", "a struct literal assigns to an unexported field of another package"},
+	{InvalidBlank, "InvalidBlank", "the blank identifier is used as a value or type"},
+	{InvalidConstInit, "InvalidConstInit", "a constant is initialized with a non-constant value"},
+	{UncalledBuiltin, "UncalledBuiltin", "a built-in function is used as a value"},
+	{UndeclaredLabel, "UndeclaredLabel", "a goto, break or continue statement refers to an undeclared label"},
+	{DuplicateLabel, "DuplicateLabel", "a label is declared twice"},
+	{InvalidConstType, "InvalidConstType", "a constant is declared with a type constants cannot have"},
+	{InvalidLit, "InvalidLit", "a composite literal has a type that has no composite literals"},
+	{InvalidAssert, "InvalidAssert", "a type assertion or type switch is applied to a non-interface"},
+	{BadRecv, "BadRecv", "a method has no receiver or more than one"},
+	{MissingLitKey, "MissingLitKey", "an element of a map literal has no key"},
+	{InvalidLitField, "InvalidLitField", "a struct literal uses something other than a field name as a key"},
+	{InvalidArrayLen, "InvalidArrayLen", "an array length is not a non-negative integer constant"},
+	{BlankPkgName, "BlankPkgName", "a package is named _"},
+	{AmbiguousSelector, "AmbiguousSelector", "a selector refers to fields or methods at the same depth of several embedded fields"},
+	{InvalidExprSwitch, "InvalidExprSwitch", "a switch is on a value of a type that does not support =="},
+	{InvalidPtrEmbed, "InvalidPtrEmbed", "a struct embeds a type that cannot be embedded"},
+	{CascadingErrors, "CascadingErrors", "the compiler left out errors involving a type whose declaration is in error"},
+	{InvalidConstVal, "InvalidConstVal", "a constant cannot be represented in the type it is converted to"},
+	{InvalidUntypedConversion, "InvalidUntypedConversion", "an untyped value cannot be converted to the type it is used as"},
+	{InvalidChanAssign, "InvalidChanAssign", "a channel value is assigned to a channel type of another direction or element type"},
+}
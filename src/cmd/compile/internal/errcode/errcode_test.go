@@ -0,0 +1,27 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errcode
+
+import "testing"
+
+func TestCatalog(t *testing.T) {
+	names := make(map[string]bool)
+	for i, info := range Catalog() {
+		// Codes are numbered from 1 without gaps.
+		if want := Code(i + 1); info.Code != want {
+			t.Errorf("catalog entry %d is %s (%v), want code %v", i, info.Name, info.Code, want)
+		}
+		if names[info.Name] {
+			t.Errorf("name %s listed twice", info.Name)
+		}
+		names[info.Name] = true
+		if info.Summary == "" {
+			t.Errorf("%s has no summary", info.Name)
+		}
+	}
+	if got, want := UndeclaredName.String(), "E0002"; got != want {
+		t.Errorf("UndeclaredName.String() = %s, want %s", got, want)
+	}
+}
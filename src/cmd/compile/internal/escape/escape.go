@@ -296,7 +296,7 @@ func (b *batch) finish(fns []*ir.Func) {
 		if loc.escapes {
 			if n.Op() == ir.ONAME {
 				if base.Flag.CompilingRuntime {
-					base.ErrorfAt(n.Pos(), "%v escapes to heap, not allowed in runtime", n)
+					base.ErrorfAt(n.Pos(), 0, "%v escapes to heap, not allowed in runtime", n)
 				}
 				if base.Flag.LowerM != 0 {
 					base.WarnfAt(n.Pos(), "moved to heap: %v", n)
@@ -306,14 +306,21 @@ func (b *batch) finish(fns []*ir.Func) {
 					base.WarnfAt(n.Pos(), "%v escapes to heap", n)
 				}
 				if logopt.Enabled() {
-					var e_curfn *ir.Func // TODO(mdempsky): Fix.
-					logopt.LogOpt(n.Pos(), "escape", "escape", ir.FuncName(e_curfn))
+					logopt.LogOpt(n.Pos(), "escape", "escape", ir.FuncName(loc.curfn))
 				}
 			}
+			if base.Debug.EscReport != 0 && !goDeferWrapper {
+				b.reportEscape(loc)
+			}
 			n.SetEsc(ir.EscHeap)
 		} else {
-			if base.Flag.LowerM != 0 && n.Op() != ir.ONAME && !goDeferWrapper {
-				base.WarnfAt(n.Pos(), "%v does not escape", n)
+			if n.Op() != ir.ONAME && !goDeferWrapper {
+				if base.Flag.LowerM != 0 {
+					base.WarnfAt(n.Pos(), "%v does not escape", n)
+				}
+				if logopt.Enabled() {
+					logopt.LogOpt(n.Pos(), "doesNotEscape", "escape", ir.FuncName(loc.curfn), fmt.Sprintf("%v does not escape", n))
+				}
 			}
 			n.SetEsc(ir.EscNone)
 			if loc.transient {
@@ -374,7 +381,15 @@ func (b *batch) paramTag(fn *ir.Func, narg int, f *types.Field) string {
 	// Only report diagnostics for user code;
 	// not for wrappers generated around them.
 	// TODO(mdempsky): Generalize this.
-	diagnose := base.Flag.LowerM != 0 && !(fn.Wrapper() || fn.Dupok())
+	diagnose := (base.Flag.LowerM != 0 || logopt.Enabled()) && !(fn.Wrapper() || fn.Dupok())
+	report := func(code, format string, args ...interface{}) {
+		if base.Flag.LowerM != 0 {
+			base.WarnfAt(f.Pos, format, args...)
+		}
+		if logopt.Enabled() {
+			logopt.LogOpt(f.Pos, code, "escape", ir.FuncName(fn), fmt.Sprintf(format, args...))
+		}
+	}
 
 	if len(fn.Body) == 0 {
 		// Assume that uintptr arguments must be held live across the call.
@@ -387,7 +402,7 @@ func (b *batch) paramTag(fn *ir.Func, narg int, f *types.Field) string {
 
 		if f.Type.IsUintptr() {
 			if diagnose {
-				base.WarnfAt(f.Pos, "assuming %v is unsafe uintptr", name())
+				report("unsafeUintptr", "assuming %v is unsafe uintptr", name())
 			}
 			return ""
 		}
@@ -402,11 +417,11 @@ func (b *batch) paramTag(fn *ir.Func, narg int, f *types.Field) string {
 		// //go:noescape is given before the declaration.
 		if fn.Pragma&ir.Noescape != 0 {
 			if diagnose && f.Sym != nil {
-				base.WarnfAt(f.Pos, "%v does not escape", name())
+				report("doesNotEscape", "%v does not escape", name())
 			}
 		} else {
 			if diagnose && f.Sym != nil {
-				base.WarnfAt(f.Pos, "leaking param: %v", name())
+				report("leakingParam", "leaking param: %v", name())
 			}
 			esc.AddHeap(0)
 		}
@@ -419,14 +434,14 @@ func (b *batch) paramTag(fn *ir.Func, narg int, f *types.Field) string {
 
 		if f.Type.IsUintptr() {
 			if diagnose {
-				base.WarnfAt(f.Pos, "marking %v as escaping uintptr", name())
+				report("uintptrEscapes", "marking %v as escaping uintptr", name())
 			}
 			return ""
 		}
 		if f.IsDDD() && f.Type.Elem().IsUintptr() {
 			// final argument is ...uintptr.
 			if diagnose {
-				base.WarnfAt(f.Pos, "marking %v as escaping ...uintptr", name())
+				report("uintptrEscapes", "marking %v as escaping ...uintptr", name())
 			}
 			return ""
 		}
@@ -449,20 +464,20 @@ func (b *batch) paramTag(fn *ir.Func, narg int, f *types.Field) string {
 
 	if diagnose && !loc.escapes {
 		if esc.Empty() {
-			base.WarnfAt(f.Pos, "%v does not escape", name())
+			report("doesNotEscape", "%v does not escape", name())
 		}
 		if x := esc.Heap(); x >= 0 {
 			if x == 0 {
-				base.WarnfAt(f.Pos, "leaking param: %v", name())
+				report("leakingParam", "leaking param: %v", name())
 			} else {
 				// TODO(mdempsky): Mention level=x like below?
-				base.WarnfAt(f.Pos, "leaking param content: %v", name())
+				report("leakingParam", "leaking param content: %v", name())
 			}
 		}
 		for i := 0; i < numEscResults; i++ {
 			if x := esc.Result(i); x >= 0 {
 				res := fn.Type().Results().Field(i).Sym
-				base.WarnfAt(f.Pos, "leaking param: %v to result %v level=%d", name(), res, x)
+				report("leakingParam", "leaking param: %v to result %v level=%d", name(), res, x)
 			}
 		}
 	}
@@ -82,6 +82,11 @@ type location struct {
 	captured   bool // has a closure captured this variable?
 	reassigned bool // has this variable been reassigned?
 	addrtaken  bool // has this variable's address been taken?
+
+	// escFlows records, for -d=escreport, the chain of flows that
+	// makes the location escape, to escRoot.
+	escFlows []escFlow
+	escRoot  *location
 }
 
 // An edge represents an assignment edge between two Go variables.
@@ -141,7 +146,7 @@ func (k hole) note(where ir.Node, why string) hole {
 	if where == nil || why == "" {
 		base.Fatalf("note: missing where/why")
 	}
-	if base.Flag.LowerM >= 2 || logopt.Enabled() {
+	if base.Flag.LowerM >= 2 || logopt.Enabled() || base.Debug.EscReport != 0 {
 		k.notes = &note{
 			next:  k.notes,
 			where: where,
@@ -195,6 +200,10 @@ func (b *batch) flow(k hole, src *location) {
 			}
 
 		}
+		if base.Debug.EscReport != 0 {
+			src.escFlows = []escFlow{b.newEscFlow(dst, src, k.derefs, k.notes)}
+			src.escRoot = dst
+		}
 		src.escapes = true
 		return
 	}
@@ -218,7 +227,7 @@ func (e *escape) newLoc(n ir.Node, transient bool) *location {
 		base.Fatalf("e.curfn isn't set")
 	}
 	if n != nil && n.Type() != nil && n.Type().NotInHeap() {
-		base.ErrorfAt(n.Pos(), "%v is incomplete (or unallocatable); stack allocation disallowed", n.Type())
+		base.ErrorfAt(n.Pos(), 0, "%v is incomplete (or unallocatable); stack allocation disallowed", n.Type())
 	}
 
 	if n != nil && n.Op() == ir.ONAME {
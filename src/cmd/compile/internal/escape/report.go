@@ -0,0 +1,150 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package escape
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/internal/src"
+)
+
+// The -d=escreport report lists the values that escape to the heap,
+// each with the chain of flows that makes it escape, from the value
+// to the heap or to a location that outlives it. With the flag set to
+// 1, each value is reported as a compiler message, like those of -m.
+// With it set to 2, the reports are collected and printed as JSON
+// objects by DumpEscReports.
+
+// An escReport describes a value that escapes to the heap.
+type escReport struct {
+	pos src.XPos
+
+	Pos   string    `json:"pos"`
+	Func  string    `json:"func"`
+	Value string    `json:"value"`
+	Kind  string    `json:"kind"` // "variable" or "allocation"
+	Flows []escFlow `json:"flows"`
+}
+
+// An escFlow is a flow of a chain that makes a value escape: the
+// value of Src, dereferenced Derefs times, or its address if Derefs is
+// -1, flows to Dst, by way of the operations in Via.
+type escFlow struct {
+	Dst    string   `json:"dst"`
+	Src    string   `json:"src"`
+	Derefs int      `json:"derefs"`
+	Via    []escVia `json:"via,omitempty"`
+}
+
+// An escVia is an operation that a flow goes through, as an
+// assignment or an interface conversion.
+type escVia struct {
+	Pos string `json:"pos"`
+	Op  string `json:"op"`
+}
+
+// escReports holds the reports collected for DumpEscReports.
+var escReports []escReport
+
+// newEscFlow returns the flow of srcloc, dereferenced derefs times, to
+// dst, through the operations of notes.
+func (b *batch) newEscFlow(dst, srcloc *location, derefs int, notes *note) escFlow {
+	f := escFlow{Dst: b.explainLoc(dst), Src: b.explainLoc(srcloc), Derefs: derefs}
+	for note := notes; note != nil; note = note.next {
+		f.Via = append(f.Via, escVia{Pos: base.FmtPos(note.where.Pos()), Op: note.why})
+	}
+	return f
+}
+
+// flowChain returns the chain of flows from src to the walk root,
+// as explainPath prints it.
+func (b *batch) flowChain(root, src *location) []escFlow {
+	var chain []escFlow
+	visited := make(map[*location]bool)
+	for !visited[src] {
+		visited[src] = true
+		dst := src.dst
+		edge := &dst.edges[src.dstEdgeIdx]
+		chain = append(chain, b.newEscFlow(dst, src, edge.derefs, edge.notes))
+		if dst == root {
+			break
+		}
+		src = dst
+	}
+	return chain
+}
+
+// String returns f as in "&x flows to ~r0 via address-of at x.go:4:9".
+func (f escFlow) String() string {
+	var buf strings.Builder
+	if f.Derefs < 0 {
+		buf.WriteString("&")
+	} else {
+		buf.WriteString(strings.Repeat("*", f.Derefs))
+	}
+	fmt.Fprintf(&buf, "%s flows to %s", f.Src, f.Dst)
+	for i, v := range f.Via {
+		if i == 0 {
+			buf.WriteString(" via ")
+		} else {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "%s at %s", v.Op, v.Pos)
+	}
+	return buf.String()
+}
+
+// reportEscape reports, for -d=escreport, that the value of loc
+// escapes to the heap. If the chain of flows that makes it escape ends
+// at a location that escapes too, the report goes on with the chain of
+// that location.
+func (b *batch) reportEscape(loc *location) {
+	n := loc.n
+	r := escReport{
+		pos:   n.Pos(),
+		Func:  ir.FuncName(loc.curfn),
+		Value: fmt.Sprint(n),
+		Kind:  "allocation",
+		Flows: []escFlow{},
+	}
+	seen := make(map[*location]bool)
+	for l := loc; l != nil && l != &b.heapLoc && !seen[l]; l = l.escRoot {
+		seen[l] = true
+		r.Flows = append(r.Flows, l.escFlows...)
+	}
+	if n.Op() == ir.ONAME {
+		r.Kind = "variable"
+	}
+	if base.Debug.EscReport == 2 {
+		escReports = append(escReports, r)
+		return
+	}
+	flows := make([]string, len(r.Flows))
+	for i, f := range r.Flows {
+		flows[i] = f.String()
+	}
+	base.WarnfAt(r.pos, "%s escapes to heap: %s", r.Value, strings.Join(flows, "; "))
+}
+
+// DumpEscReports prints the reports collected with -d=escreport=2 as
+// JSON objects, sorted by position. It must be called after escape
+// analysis.
+func DumpEscReports() {
+	sort.SliceStable(escReports, func(i, j int) bool {
+		return escReports[i].pos.Before(escReports[j].pos)
+	})
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	for _, r := range escReports {
+		r.Pos = base.FmtPos(r.pos)
+		enc.Encode(r)
+	}
+}
@@ -121,6 +121,9 @@ func (b *batch) walkOne(root *location, walkgen uint32, enqueue func(*location))
 						logopt.LogOpt(l.n.Pos(), "escape", "escape", ir.FuncName(e_curfn), fmt.Sprintf("%v escapes to heap", l.n), explanation)
 					}
 				}
+				if base.Debug.EscReport != 0 {
+					l.escFlows, l.escRoot = b.flowChain(root, l), root
+				}
 				l.escapes = true
 				enqueue(l)
 				continue
@@ -50,7 +50,7 @@ func enqueueFunc(fn *ir.Func) {
 		abiInfo := a.ABIAnalyzeFuncType(fn.Type().FuncType()) // abiInfo has spill/home locations for wrapper
 		liveness.WriteFuncMap(fn, abiInfo)
 		if fn.ABI == obj.ABI0 {
-			x := ssagen.EmitArgInfo(fn, abiInfo)
+			x := ssagen.EmitArgInfo(fn, abiInfo, false)
 			objw.Global(x, int32(len(x.P)), obj.RODATA|obj.LOCAL)
 		}
 		return
@@ -166,4 +166,5 @@ func compileFunctions() {
 
 	base.Ctxt.InParallel = false
 	types.CalcSizeDisabled = false
+	base.CheckErrorLimit()
 }
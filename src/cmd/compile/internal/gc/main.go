@@ -16,6 +16,7 @@ import (
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/logopt"
 	"cmd/compile/internal/noder"
+	"cmd/compile/internal/pgo"
 	"cmd/compile/internal/pkginit"
 	"cmd/compile/internal/reflectdata"
 	"cmd/compile/internal/ssa"
@@ -102,6 +103,8 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 	ir.Pkgs.Go = types.NewPkg("go", "")
 
 	base.DebugSSA = ssa.PhaseOption
+	base.FormatMessage = types.FormatMessage
+	base.FormatFatal = types.FormatBestEffort
 	base.ParseFlags()
 
 	// Record flags that affect the build result. (And don't
@@ -141,6 +144,14 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 
 	types.ParseLangFlag()
 
+	if base.Flag.PGOProfile != "" {
+		p, err := pgo.New(base.Flag.PGOProfile)
+		if err != nil {
+			log.Fatalf("reading profile: %v", err)
+		}
+		pgo.Current = p
+	}
+
 	symABIs := ssagen.NewSymABIs(base.Ctxt.Pkgpath)
 	if base.Flag.SymABIs != "" {
 		symABIs.ReadSymABIs(base.Flag.SymABIs)
@@ -163,6 +174,18 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 	if base.Debug.SoftFloat != 0 {
 		ssagen.Arch.SoftFloat = true
 	}
+	if base.Flag.PkgPathMap != "" {
+		hook, err := types.ParsePkgPathMap(base.Flag.PkgPathMap)
+		if err != nil {
+			log.Fatalf("-pkgpathmap: %v", err)
+		}
+		types.PkgPathHook = hook
+		base.Ctxt.LinkPkgpath = types.LinkPkgPath(base.Ctxt.Pkgpath)
+	}
+	if base.Debug.InternLimit != 0 {
+		types.SetInternLimit(int64(base.Debug.InternLimit))
+	}
+	types.NameVargen = base.Debug.LocalTypeNames != 0
 
 	if base.Flag.JSON != "" { // parse version,destination from json logging optimization.
 		logopt.LogJsonOption(base.Flag.JSON)
@@ -253,6 +276,18 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 	}
 	noder.MakeWrappers(typecheck.Target) // must happen after inlining
 
+	if base.Debug.TParams != 0 {
+		noder.DumpTParams()
+	}
+	if base.Debug.ShowType != "" {
+		types.ShowTypes()
+	}
+	if base.Flag.TypeSnapshot != "" {
+		if err := os.WriteFile(base.Flag.TypeSnapshot, types.EncodeTypes(types.DeclaredTypes()), 0666); err != nil {
+			base.Fatalf("%v", err)
+		}
+	}
+
 	// Devirtualize.
 	for _, n := range typecheck.Target.Decls {
 		if n.Op() == ir.ODCLFUNC {
@@ -344,6 +379,31 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 		base.Fatalf("%d uncompiled functions", len(compilequeue))
 	}
 
+	if base.Debug.InstGrowth != 0 {
+		noder.DumpInstGrowth()
+	}
+	if base.Debug.Instantiations != 0 {
+		noder.DumpInstantiations()
+	}
+	if base.Debug.BCE == 2 || base.Debug.NilCheckReport == 2 || base.Debug.WBReport == 2 {
+		ssa.DumpCheckReports()
+	}
+	if base.Debug.EscReport == 2 {
+		escape.DumpEscReports()
+	}
+	if base.Debug.SpillStats == 2 {
+		ssa.DumpSpillStats()
+	}
+	if base.Debug.FindType != "" {
+		types.FindTypes()
+	}
+	if base.Debug.TypeAlloc != 0 {
+		types.DumpAllocStats()
+	}
+	if base.Debug.InternStats != 0 {
+		types.DumpInternStats()
+	}
+
 	logopt.FlushLoggedOpts(base.Ctxt, base.Ctxt.Pkgpath)
 	base.ExitIfErrors()
 
@@ -369,6 +429,12 @@ func writebench(filename string) error {
 	fmt.Fprintln(&buf, "goarch:", runtime.GOARCH)
 	base.Timer.Write(&buf, "BenchmarkCompile:"+base.Ctxt.Pkgpath+":")
 
+	// Report the interning done by the symbol and type formatting
+	// layer, so that regressions in it show up in benchmark results.
+	st := types.ReadInternStats()
+	fmt.Fprintf(&buf, "BenchmarkCompile:%s:intern    1    %d lookups    %d strings    %d B    %.2f hit-%%    %d syms\n",
+		base.Ctxt.Pkgpath, st.Lookups, st.Strings, st.Bytes, st.HitRate()*100, st.Syms)
+
 	n, err := f.Write(buf.Bytes())
 	if err != nil {
 		return err
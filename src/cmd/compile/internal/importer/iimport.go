@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"go/constant"
 	"go/token"
+	"internal/buildcfg"
 	"io"
 	"math/big"
 	"sort"
@@ -329,25 +330,36 @@ func (r *importReader) obj(name string) {
 		named.SetUnderlying(underlying)
 
 		if !isInterface(underlying) {
-			for n := r.uint64(); n > 0; n-- {
-				mpos := r.pos()
-				mname := r.ident()
-				recv := r.param()
-
-				// If the receiver has any targs, set those as the
-				// rparams of the method (since those are the
-				// typeparams being used in the method sig/body).
-				targs := baseType(recv.Type()).TypeArgs()
-				var rparams []*types2.TypeParam
-				if targs.Len() > 0 {
-					rparams = make([]*types2.TypeParam, targs.Len())
-					for i := range rparams {
-						rparams[i], _ = targs.At(i).(*types2.TypeParam)
+			// The methods with type parameters of their own
+			// follow the others.
+			for _, generic := range []bool{false, true} {
+				for n := r.uint64(); n > 0; n-- {
+					mpos := r.pos()
+					mname := r.ident()
+					recv := r.param()
+
+					// If the receiver has any targs, set those as the
+					// rparams of the method (since those are the
+					// typeparams being used in the method sig/body).
+					targs := baseType(recv.Type()).TypeArgs()
+					var rparams []*types2.TypeParam
+					if targs.Len() > 0 {
+						rparams = make([]*types2.TypeParam, targs.Len())
+						for i := range rparams {
+							rparams[i], _ = targs.At(i).(*types2.TypeParam)
+						}
 					}
-				}
-				msig := r.signature(recv, rparams, nil)
+					var tparams []*types2.TypeParam
+					if generic {
+						if !buildcfg.Experiment.GenericMethods {
+							errorf("unexpected generic method %s (GOEXPERIMENT=genericmethods is not set)", mname)
+						}
+						tparams = r.tparamList()
+					}
+					msig := r.signature(recv, rparams, tparams)
 
-				named.AddMethod(types2.NewFunc(mpos, r.currPkg, mname, msig))
+					named.AddMethod(types2.NewFunc(mpos, r.currPkg, mname, msig))
+				}
 			}
 		}
 
@@ -696,6 +708,9 @@ func (r *importReader) signature(recv *types2.Var, rparams, tparams []*types2.Ty
 	params := r.paramList()
 	results := r.paramList()
 	variadic := params.Len() > 0 && r.bool()
+	if recv != nil && len(tparams) != 0 {
+		return types2.NewGenericMethodType(recv, rparams, tparams, params, results, variadic)
+	}
 	return types2.NewSignatureType(recv, rparams, tparams, params, results, variadic)
 }
 
@@ -29,6 +29,7 @@ package inline
 import (
 	"fmt"
 	"go/constant"
+	"sort"
 	"strings"
 
 	"cmd/compile/internal/base"
@@ -49,12 +50,26 @@ const (
 	inlineExtraPanicCost = 1               // do not penalize inlining panics.
 	inlineExtraThrowCost = inlineMaxBudget // with current (2018-05/1.11) code, inlining runtime.throw does not help.
 
+	// Converting a value larger than four words to an interface
+	// copies it to the heap; see types.SizeLarge.
+	inlineExtraConvIfaceCost = 10
+
 	inlineBigFunctionNodes   = 5000 // Functions with this many nodes are considered "big".
 	inlineBigFunctionMaxCost = 20   // Max cost of inlinee when inlining into a "big" function.
+
+	// MaxBudget is the largest budget that //go:inlinebudget can give
+	// a function, and so the largest cost of the calls inlined into
+	// functions that are not big.
+	MaxBudget = 4 * inlineMaxBudget
 )
 
+// inlLog logs the calls that inlining visits and the substitutions it
+// makes in inlined bodies.
+var inlLog = base.NewLogger("inline")
+
 // InlinePackage finds functions that can be inlined and clones them before walk expands them.
 func InlinePackage() {
+	initPGOInline()
 	ir.VisitFuncsBottomUp(typecheck.Target.Decls, func(list []*ir.Func, recursive bool) {
 		numfns := numNonClosures(list)
 		for _, n := range list {
@@ -67,6 +82,9 @@ func InlinePackage() {
 				if base.Flag.LowerM > 1 {
 					fmt.Printf("%v: cannot inline %v: recursive\n", ir.Line(n), n.Nname)
 				}
+				if logopt.Enabled() {
+					logopt.LogOpt(n.Pos(), "cannotInlineFunction", "inline", ir.FuncName(n), "recursive")
+				}
 			}
 			InlineCalls(n)
 		}
@@ -82,12 +100,21 @@ func CanInline(fn *ir.Func) {
 	}
 
 	var reason string // reason, if any, that the function was not inlined
+	var visitor hairyVisitor
+	if fn.Pragma&ir.InlineBudget != 0 && (base.Flag.LowerM != 0 || logopt.Enabled()) {
+		defer func() {
+			checkInlineBudget(fn, reason, visitor.budget < 0)
+		}()
+	}
 	if base.Flag.LowerM > 1 || logopt.Enabled() {
 		defer func() {
 			if reason != "" {
 				if base.Flag.LowerM > 1 {
 					fmt.Printf("%v: cannot inline %v: %s\n", ir.Line(fn), fn.Nname, reason)
 				}
+				if base.Flag.LowerM > 2 && visitor.budget < 0 {
+					visitor.printCosts(fn)
+				}
 				if logopt.Enabled() {
 					logopt.LogOpt(fn.Pos(), "cannotInlineFunction", "inline", ir.FuncName(fn), reason)
 				}
@@ -101,6 +128,13 @@ func CanInline(fn *ir.Func) {
 		return
 	}
 
+	// If marked "go:cold", don't inline, to keep its code out of
+	// its callers.
+	if fn.Pragma&ir.Cold != 0 {
+		reason = "marked go:cold"
+		return
+	}
+
 	// If marked "go:norace" and -race compilation, don't inline.
 	if base.Flag.Race && fn.Pragma&ir.Norace != 0 {
 		reason = "marked go:norace with -race compilation"
@@ -166,8 +200,15 @@ func CanInline(fn *ir.Func) {
 	// locals, and we use this map to produce a pruned Inline.Dcl
 	// list. See issue 25249 for more context.
 
-	visitor := hairyVisitor{
-		budget:        inlineMaxBudget,
+	budget := int32(inlineMaxBudget)
+	if fn.Pragma&ir.InlineBudget != 0 {
+		budget = fn.InlineBudget
+	} else if hotCallee(fn) {
+		budget = hotBudget
+	}
+	visitor = hairyVisitor{
+		budget:        budget,
+		maxBudget:     budget,
 		extraCallCost: cc,
 	}
 	if visitor.tooHairy(fn) {
@@ -176,7 +217,7 @@ func CanInline(fn *ir.Func) {
 	}
 
 	n.Func.Inl = &ir.Inline{
-		Cost: inlineMaxBudget - visitor.budget,
+		Cost: visitor.maxBudget - visitor.budget,
 		Dcl:  pruneUnusedAutos(n.Defn.(*ir.Func).Dcl, &visitor),
 		Body: inlcopylist(fn.Body),
 
@@ -184,12 +225,36 @@ func CanInline(fn *ir.Func) {
 	}
 
 	if base.Flag.LowerM > 1 {
-		fmt.Printf("%v: can inline %v with cost %d as: %v { %v }\n", ir.Line(fn), n, inlineMaxBudget-visitor.budget, fn.Type(), ir.Nodes(n.Func.Inl.Body))
+		fmt.Printf("%v: can inline %v with cost %d as: %v { %v }\n", ir.Line(fn), n, n.Func.Inl.Cost, fn.Type(), ir.Nodes(n.Func.Inl.Body))
 	} else if base.Flag.LowerM != 0 {
 		fmt.Printf("%v: can inline %v\n", ir.Line(fn), n)
 	}
 	if logopt.Enabled() {
-		logopt.LogOpt(fn.Pos(), "canInlineFunction", "inline", ir.FuncName(fn), fmt.Sprintf("cost: %d", inlineMaxBudget-visitor.budget))
+		logopt.LogOpt(fn.Pos(), "canInlineFunction", "inline", ir.FuncName(fn), fmt.Sprintf("cost: %d", n.Func.Inl.Cost))
+	}
+}
+
+// checkInlineBudget reports, with -m or -json, if the
+// //go:inlinebudget directive of fn has no effect: if fn cannot be
+// inlined, for reason, or it is within the default budget. overBudget
+// reports whether the reason is that fn is over its budget.
+func checkInlineBudget(fn *ir.Func, reason string, overBudget bool) {
+	switch {
+	case overBudget && fn.InlineBudget < inlineMaxBudget:
+		// The budget is lowered, which may be what keeps fn
+		// from being inlined.
+		return
+	case reason != "":
+	case fn.Inl != nil && fn.Inl.Cost <= inlineMaxBudget:
+		reason = fmt.Sprintf("cost %d is within the default budget %d", fn.Inl.Cost, inlineMaxBudget)
+	default:
+		return
+	}
+	if base.Flag.LowerM != 0 {
+		fmt.Printf("%v: //go:inlinebudget has no effect: %s\n", ir.Line(fn), reason)
+	}
+	if logopt.Enabled() {
+		logopt.LogOpt(fn.Pos(), "inlineBudgetNoEffect", "inline", ir.FuncName(fn), reason)
 	}
 }
 
@@ -229,10 +294,23 @@ func canDelayResults(fn *ir.Func) bool {
 // hairiness and whether or not it can be inlined.
 type hairyVisitor struct {
 	budget        int32
+	maxBudget     int32
 	reason        string
 	extraCallCost int32
 	usedLocals    ir.NameSet
 	do            func(ir.Node) bool
+
+	// With -m=3, the cost of each node visited, for printCosts.
+	costs   []nodeCost
+	charged int32  // the sum of costs
+	why     string // why the node being visited costs more than 1
+}
+
+// A nodeCost is the cost of a node, without its children.
+type nodeCost struct {
+	n    ir.Node
+	cost int32
+	why  string
 }
 
 func (v *hairyVisitor) tooHairy(fn *ir.Func) bool {
@@ -241,7 +319,7 @@ func (v *hairyVisitor) tooHairy(fn *ir.Func) bool {
 		return true
 	}
 	if v.budget < 0 {
-		v.reason = fmt.Sprintf("function too complex: cost %d exceeds budget %d", inlineMaxBudget-v.budget, inlineMaxBudget)
+		v.reason = fmt.Sprintf("function too complex: cost %d exceeds budget %d", v.maxBudget-v.budget, v.maxBudget)
 		return true
 	}
 	return false
@@ -251,6 +329,8 @@ func (v *hairyVisitor) doNode(n ir.Node) bool {
 	if n == nil {
 		return false
 	}
+	budget, charged := v.budget, v.charged
+	v.why = ""
 	switch n.Op() {
 	// Call is okay if inlinable and we have the budget for the body.
 	case ir.OCALLFUNC:
@@ -269,6 +349,7 @@ func (v *hairyVisitor) doNode(n ir.Node) bool {
 				}
 				if fn == "throw" {
 					v.budget -= inlineExtraThrowCost
+					v.why = "calls of runtime.throw cost the whole budget"
 					break
 				}
 			}
@@ -311,11 +392,17 @@ func (v *hairyVisitor) doNode(n ir.Node) bool {
 
 		if fn := inlCallee(n.X); fn != nil && typecheck.HaveInlineBody(fn) {
 			v.budget -= fn.Inl.Cost
+			if base.Flag.LowerM > 2 {
+				v.why = fmt.Sprintf("inlined body of %v costs %d", fn, fn.Inl.Cost)
+			}
 			break
 		}
 
 		// Call cost for non-leaf inlining.
 		v.budget -= v.extraCallCost
+		if base.Flag.LowerM > 2 {
+			v.why = fmt.Sprintf("calls that are not inlined cost %d more", v.extraCallCost)
+		}
 
 	case ir.OCALLMETH:
 		base.FatalfAt(n.Pos(), "OCALLMETH missed by typecheck")
@@ -324,6 +411,9 @@ func (v *hairyVisitor) doNode(n ir.Node) bool {
 	case ir.OCALL, ir.OCALLINTER:
 		// Call cost for non-leaf inlining.
 		v.budget -= v.extraCallCost
+		if base.Flag.LowerM > 2 {
+			v.why = fmt.Sprintf("calls that are not inlined cost %d more", v.extraCallCost)
+		}
 
 	case ir.OPANIC:
 		n := n.(*ir.UnaryExpr)
@@ -357,6 +447,7 @@ func (v *hairyVisitor) doNode(n ir.Node) bool {
 		if doList(n.(*ir.ClosureExpr).Func.Body, v.do) {
 			return true
 		}
+		v.why = "func literals cost 15 more"
 
 	case ir.OSELECT,
 		ir.OGO,
@@ -369,6 +460,13 @@ func (v *hairyVisitor) doNode(n ir.Node) bool {
 	case ir.OAPPEND:
 		v.budget -= inlineExtraAppendCost
 
+	case ir.OCONVIFACE:
+		n := n.(*ir.ConvExpr)
+		if from := n.X.Type(); !from.IsInterface() && types.Complexity(from).Size == types.SizeLarge {
+			v.budget -= inlineExtraConvIfaceCost
+			v.why = fmt.Sprintf("converting a value larger than four words to an interface costs %d more", inlineExtraConvIfaceCost)
+		}
+
 	case ir.ODEREF:
 		// *(*X)(unsafe.Pointer(&x)) is low-cost
 		n := n.(*ir.StarExpr)
@@ -424,6 +522,14 @@ func (v *hairyVisitor) doNode(n ir.Node) bool {
 
 	v.budget--
 
+	if base.Flag.LowerM > 2 {
+		// The cost of n's children that were visited
+		// already, as for closures, is not n's.
+		cost := budget - v.budget - (v.charged - charged)
+		v.costs = append(v.costs, nodeCost{n, cost, v.why})
+		v.charged += cost
+	}
+
 	// When debugging, don't stop early, to get full cost of inlining this function
 	if v.budget < 0 && base.Flag.LowerM < 2 && !logopt.Enabled() {
 		v.reason = "too expensive"
@@ -433,6 +539,67 @@ func (v *hairyVisitor) doNode(n ir.Node) bool {
 	return ir.DoChildren(n, v.do)
 }
 
+// maxCostlyNodes is the number of the most expensive nodes that
+// printCosts lists.
+const maxCostlyNodes = 10
+
+// printCosts prints, for -m=3, how the cost of fn, which is over the
+// inlining budget, adds up: its most expensive nodes, and its cost by
+// op, so that it can be restructured to become inlinable.
+func (v *hairyVisitor) printCosts(fn *ir.Func) {
+	cost := v.maxBudget - v.budget
+	fmt.Printf("%v: cost of %v is %d, %d over budget %d\n", ir.Line(fn), fn.Nname, cost, cost-v.maxBudget, v.maxBudget)
+
+	costs := append([]nodeCost(nil), v.costs...)
+	sort.SliceStable(costs, func(i, j int) bool {
+		return costs[i].cost > costs[j].cost
+	})
+	for i, c := range costs {
+		if i == maxCostlyNodes || c.cost <= 1 {
+			break
+		}
+		if c.why != "" {
+			fmt.Printf("%v:   cost %d: %v (%s)\n", ir.Line(c.n), c.cost, c.n, c.why)
+		} else {
+			fmt.Printf("%v:   cost %d: %v\n", ir.Line(c.n), c.cost, c.n)
+		}
+	}
+
+	type opCost struct {
+		op          ir.Op
+		nodes, cost int32
+	}
+	var ops []*opCost
+	byOp := make(map[ir.Op]*opCost)
+	for _, c := range v.costs {
+		if c.cost == 0 {
+			continue
+		}
+		o := byOp[c.n.Op()]
+		if o == nil {
+			o = &opCost{op: c.n.Op()}
+			byOp[o.op] = o
+			ops = append(ops, o)
+		}
+		o.nodes++
+		o.cost += c.cost
+	}
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].cost > ops[j].cost
+	})
+	var b strings.Builder
+	for i, o := range ops {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%+v %d", o.op, o.cost)
+		if o.nodes > 1 {
+			fmt.Fprintf(&b, " (%d nodes)", o.nodes)
+		}
+	}
+	fmt.Printf("%v:   cost by op: %s\n", ir.Line(fn), b.String())
+}
+
 func isBigFunc(fn *ir.Func) bool {
 	budget := inlineBigFunctionNodes
 	return ir.Any(fn, func(n ir.Node) bool {
@@ -492,7 +659,7 @@ func inlcopy(n ir.Node) ir.Node {
 func InlineCalls(fn *ir.Func) {
 	savefn := ir.CurFunc
 	ir.CurFunc = fn
-	maxCost := int32(inlineMaxBudget)
+	maxCost := int32(MaxBudget)
 	if isBigFunc(fn) {
 		maxCost = inlineBigFunctionMaxCost
 	}
@@ -579,14 +746,14 @@ func inlnode(n ir.Node, maxCost int32, inlMap map[*ir.Func]bool, edit func(ir.No
 		if call.NoInline {
 			break
 		}
-		if base.Flag.LowerM > 3 {
-			fmt.Printf("%v:call to func %+v\n", ir.Line(n), call.X)
+		if inlLog.Enabled() {
+			inlLog.Log(n.Pos(), "call", "func", ir.CurFunc, "callee", call.X)
 		}
 		if ir.IsIntrinsicCall(call) {
 			break
 		}
 		if fn := inlCallee(call.X); fn != nil && typecheck.HaveInlineBody(fn) {
-			n = mkinlcall(call, fn, maxCost, inlMap, edit)
+			n = mkinlcall(call, fn, pgoMaxCost(call, fn, maxCost), inlMap, edit)
 		}
 	}
 
@@ -652,6 +819,27 @@ var SSADumpInline = func(*ir.Func) {}
 // instead.
 var NewInline = func(call *ir.CallExpr, fn *ir.Func, inlIndex int) *ir.InlinedCallExpr { return nil }
 
+// calleeName returns the name of fn, the callee of call, for -m output.
+// If fn is a shape-based instantiation and call passes it a dictionary
+// variable, the name shows the type arguments of the dictionary in place
+// of the shapes, as in Sum[MyInt] for Sum[shape:int].
+func calleeName(call *ir.CallExpr, fn *ir.Func) string {
+	name := fmt.Sprint(fn)
+	if fn.Inst == nil || len(call.Args) == 0 || call.Args[0].Op() != ir.OADDR {
+		return name
+	}
+	dict, ok := call.Args[0].(*ir.AddrExpr).X.(*ir.Name)
+	if !ok {
+		return name
+	}
+	targs := typecheck.DictTArgs(dict.Sym())
+	i, j := strings.Index(name, "["), strings.LastIndex(name, "]")
+	if targs == nil || i < 0 || j < i {
+		return name
+	}
+	return name[:i+1] + types.JoinTypes(targs, ",", 'v') + name[j:]
+}
+
 // If n is a OCALLFUNC node, and fn is an ONAME node for a
 // function with an inlinable body, return an OINLCALL node that can replace n.
 // The returned node's Ninit has the parameter assignments, the Nbody is the
@@ -680,7 +868,8 @@ func mkinlcall(n *ir.CallExpr, fn *ir.Func, maxCost int32, inlMap map[*ir.Func]b
 	if fn == ir.CurFunc {
 		// Can't recursively inline a function into itself.
 		if logopt.Enabled() {
-			logopt.LogOpt(n.Pos(), "cannotInlineCall", "inline", fmt.Sprintf("recursive call to %s", ir.FuncName(ir.CurFunc)))
+			logopt.LogOpt(n.Pos(), "cannotInlineCall", "inline", ir.FuncName(ir.CurFunc),
+				fmt.Sprintf("recursive call to %s", ir.FuncName(ir.CurFunc)))
 		}
 		return n
 	}
@@ -720,6 +909,10 @@ func mkinlcall(n *ir.CallExpr, fn *ir.Func, maxCost int32, inlMap map[*ir.Func]b
 		if base.Flag.LowerM > 1 {
 			fmt.Printf("%v: cannot inline %v into %v: repeated recursive cycle\n", ir.Line(n), fn, ir.FuncName(ir.CurFunc))
 		}
+		if logopt.Enabled() {
+			logopt.LogOpt(n.Pos(), "cannotInlineCall", "inline", ir.FuncName(ir.CurFunc),
+				fmt.Sprintf("repeated recursive cycle of %s", ir.PkgFuncName(fn)))
+		}
 		return n
 	}
 	inlMap[fn] = true
@@ -733,6 +926,9 @@ func mkinlcall(n *ir.CallExpr, fn *ir.Func, maxCost int32, inlMap map[*ir.Func]b
 
 	sym := fn.Linksym()
 	inlIndex := base.Ctxt.InlTree.Add(parent, n.Pos(), sym)
+	if inlinedFuncs != nil {
+		inlinedFuncs[inlIndex] = fn
+	}
 
 	if base.Flag.GenDwarfInl > 0 {
 		if !sym.WasInlined() {
@@ -742,10 +938,13 @@ func mkinlcall(n *ir.CallExpr, fn *ir.Func, maxCost int32, inlMap map[*ir.Func]b
 	}
 
 	if base.Flag.LowerM != 0 {
-		fmt.Printf("%v: inlining call to %v\n", ir.Line(n), fn)
+		fmt.Printf("%v: inlining call to %s\n", ir.Line(n), calleeName(n, fn))
 	}
-	if base.Flag.LowerM > 2 {
-		fmt.Printf("%v: Before inlining: %+v\n", ir.Line(n), n)
+	if logopt.Enabled() {
+		logopt.LogOpt(n.Pos(), "inlineCall", "inline", ir.FuncName(ir.CurFunc), calleeName(n, fn))
+	}
+	if inlLog.Enabled() {
+		inlLog.Log(n.Pos(), "before inlining", "func", ir.CurFunc, "callee", fn, "call", fmt.Sprintf("%+v", n))
 	}
 
 	res := NewInline(n, fn, inlIndex)
@@ -761,8 +960,8 @@ func mkinlcall(n *ir.CallExpr, fn *ir.Func, maxCost int32, inlMap map[*ir.Func]b
 	// luckily these are small.
 	ir.EditChildren(res, edit)
 
-	if base.Flag.LowerM > 2 {
-		fmt.Printf("%v: After inlining %+v\n\n", ir.Line(res), res)
+	if inlLog.Enabled() {
+		inlLog.Log(res.Pos(), "after inlining", "func", ir.CurFunc, "callee", fn, "result", fmt.Sprintf("%+v", res))
 	}
 
 	return res
@@ -843,7 +1042,7 @@ func oldInline(call *ir.CallExpr, fn *ir.Func, inlIndex int) *ir.InlinedCallExpr
 	var retvars []ir.Node
 	for i, t := range fn.Type().Results().Fields().Slice() {
 		var m *ir.Name
-		if nn := t.Nname; nn != nil && !ir.IsBlank(nn.(*ir.Name)) && !strings.HasPrefix(nn.Sym().Name, "~r") {
+		if nn := t.Nname; nn != nil && !ir.IsBlank(nn.(*ir.Name)) && types.ParamKindOf(nn.Sym().Name) != types.ParamResult {
 			n := nn.(*ir.Name)
 			m = inlvar(n)
 			m = typecheck.Expr(m).(*ir.Name)
@@ -857,7 +1056,7 @@ func oldInline(call *ir.CallExpr, fn *ir.Func, inlIndex int) *ir.InlinedCallExpr
 			// Don't update the src.Pos on a return variable if it
 			// was manufactured by the inliner (e.g. "~R2"); such vars
 			// were not part of the original callee.
-			if !strings.HasPrefix(m.Sym().Name, "~R") {
+			if types.ParamKindOf(m.Sym().Name) != types.ParamInlResult {
 				m.Name().SetInlFormal(true)
 				m.SetPos(t.Pos)
 				inlfvars = append(inlfvars, m)
@@ -945,8 +1144,8 @@ func oldInline(call *ir.CallExpr, fn *ir.Func, inlIndex int) *ir.InlinedCallExpr
 // PAUTO's in the calling functions, and link them off of the
 // PPARAM's, PAUTOS and PPARAMOUTs of the called function.
 func inlvar(var_ *ir.Name) *ir.Name {
-	if base.Flag.LowerM > 3 {
-		fmt.Printf("inlvar %+v\n", var_)
+	if inlLog.Enabled() {
+		inlLog.Log(var_.Pos(), "new variable for inlined variable", "var", var_)
 	}
 
 	n := typecheck.NewName(var_.Sym())
@@ -964,7 +1163,7 @@ func inlvar(var_ *ir.Name) *ir.Name {
 
 // Synthesize a variable to store the inlined function's results in.
 func retvar(t *types.Field, i int) *ir.Name {
-	n := typecheck.NewName(typecheck.LookupNum("~R", i))
+	n := typecheck.NewName(types.ParamSym(types.ParamInlResult, i))
 	n.SetType(t.Type)
 	n.SetTypecheck(1)
 	n.Class = ir.PAUTO
@@ -1208,21 +1407,21 @@ func (subst *inlsubst) node(n ir.Node) ir.Node {
 				base.Fatalf("%v: unresolvable capture %v\n", ir.Line(n), n)
 			}
 
-			if base.Flag.LowerM > 2 {
-				fmt.Printf("substituting captured name %+v  ->  %+v\n", n, o)
+			if inlLog.Enabled() {
+				inlLog.Log(n.Pos(), "substituting captured name", "name", n, "outer", o)
 			}
 			return o
 		}
 
 		if inlvar := subst.inlvars[n]; inlvar != nil { // These will be set during inlnode
-			if base.Flag.LowerM > 2 {
-				fmt.Printf("substituting name %+v  ->  %+v\n", n, inlvar)
+			if inlLog.Enabled() {
+				inlLog.Log(n.Pos(), "substituting name", "name", n, "var", inlvar)
 			}
 			return inlvar
 		}
 
-		if base.Flag.LowerM > 2 {
-			fmt.Printf("not substituting name %+v\n", n)
+		if inlLog.Enabled() {
+			inlLog.Log(n.Pos(), "not substituting name", "name", n)
 		}
 		return n
 
@@ -0,0 +1,88 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inline
+
+import (
+	"fmt"
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/pgo"
+)
+
+// Profile-guided inlining gives the functions called from the hot call
+// sites of the -pgoprofile profile a larger inlining budget, and
+// inlines calls of them from those sites that cost up to that budget.
+// The hot call sites are the hottest calls that together account for
+// -d=pgoinlinecdf percent of the call samples of the profile, and the
+// budget is -d=pgoinlinebudgetmult times the default one.
+var (
+	hotCalls     map[pgo.CallSite]bool
+	hotCallees   map[string]bool
+	hotBudget    int32
+	inlinedFuncs map[int]*ir.Func // functions inlined at each inlining index, if hotCalls
+)
+
+// initPGOInline finds the hot call sites of the -pgoprofile profile,
+// if any, and reports them for -d=pgoinline.
+func initPGOInline() {
+	if pgo.Current == nil {
+		return
+	}
+	hot, minSamples := pgo.Current.HotCalls(base.Debug.PGOInlineCDF)
+	hotBudget = int32(inlineMaxBudget * base.Debug.PGOInlineBudgetMult)
+	if base.Debug.PGOInline != 0 {
+		fmt.Printf("pgo inlining: %d hot call sites with at least %d samples make up %d%% of %d call samples; their callees have budget %d (%d times %d)\n",
+			len(hot), minSamples, base.Debug.PGOInlineCDF, pgo.Current.CallTotal(), hotBudget, base.Debug.PGOInlineBudgetMult, inlineMaxBudget)
+	}
+	if len(hot) == 0 {
+		return
+	}
+	hotCalls = hot
+	hotCallees = make(map[string]bool)
+	for c := range hot {
+		hotCallees[c.Callee] = true
+	}
+	inlinedFuncs = make(map[int]*ir.Func)
+}
+
+// hotCallee reports whether fn is called from a hot call site.
+// Generic functions, whose names in profiles are not those of their
+// instantiations, never are.
+func hotCallee(fn *ir.Func) bool {
+	if hotCallees == nil {
+		return false
+	}
+	name := ir.PkgFuncName(fn)
+	return !strings.Contains(name, "[") && hotCallees[name]
+}
+
+// pgoMaxCost returns the largest cost of fn that call, in a function
+// that may inline callees that cost up to maxCost, may inline. Hot
+// calls may inline callees that cost up to hotBudget, and other calls
+// of the callees of hot calls those within the default budget, unless
+// a //go:inlinebudget directive gives them another.
+func pgoMaxCost(call *ir.CallExpr, fn *ir.Func, maxCost int32) int32 {
+	if !hotCallee(fn) {
+		return maxCost
+	}
+	pos := base.Ctxt.PosTable.Pos(call.Pos())
+	caller := ir.CurFunc
+	if f := inlinedFuncs[pos.Base().InliningIndex()]; f != nil {
+		caller = f
+	}
+	c := pgo.CallSite{Caller: ir.PkgFuncName(caller), Line: int(pos.RelLine()), Callee: ir.PkgFuncName(fn)}
+	if hotCalls[c] {
+		if base.Debug.PGOInline != 0 {
+			fmt.Printf("%v: hot call to %s, callee cost %d, budget %d\n", ir.Line(call), c.Callee, fn.Inl.Cost, hotBudget)
+		}
+		return hotBudget
+	}
+	if fn.Pragma&ir.InlineBudget == 0 && maxCost > inlineMaxBudget {
+		return inlineMaxBudget
+	}
+	return maxCost
+}
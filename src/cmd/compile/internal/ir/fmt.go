@@ -109,7 +109,7 @@ func (o Op) GoString() string {
 func (o Op) Format(s fmt.State, verb rune) {
 	switch verb {
 	default:
-		fmt.Fprintf(s, "%%!%c(Op=%d)", verb, int(o))
+		base.BadVerb(s, verb, fmt.Sprintf("%%!%c(Op=%d)", verb, int(o)))
 	case 'v':
 		if s.Flag('+') {
 			// %+v is OMUL instead of "*"
@@ -139,7 +139,7 @@ func fmtNode(n Node, s fmt.State, verb rune) {
 	}
 
 	if verb != 'v' && verb != 'S' && verb != 'L' {
-		fmt.Fprintf(s, "%%!%c(*Node=%p)", verb, n)
+		base.BadVerb(s, verb, fmt.Sprintf("%%!%c(*Node=%p)", verb, n))
 		return
 	}
 
@@ -153,9 +153,9 @@ func fmtNode(n Node, s fmt.State, verb rune) {
 		if t.Kind() == types.TNIL {
 			fmt.Fprint(s, "nil")
 		} else if n.Op() == ONAME && n.Name().AutoTemp() {
-			fmt.Fprintf(s, "%v value", t)
+			types.Fprintf(s, "%v value", t)
 		} else {
-			fmt.Fprintf(s, "%v (type %v)", n, t)
+			types.Fprintf(s, "%v (type %v)", n, t)
 		}
 		return
 	}
@@ -334,13 +334,13 @@ func stmtFmt(n Node, s fmt.State) {
 	}
 
 	if complexinit {
-		fmt.Fprintf(s, " %v; ", n.Init())
+		types.Fprintf(s, " %v; ", n.Init())
 	}
 
 	switch n.Op() {
 	case ODCL:
 		n := n.(*Decl)
-		fmt.Fprintf(s, "var %v %v", n.X.Sym(), n.X.Type())
+		types.Fprintf(s, "var %v %v", n.X.Sym(), n.X.Type())
 
 	// Don't export "v = <N>" initializing statements, hope they're always
 	// preceded by the DCL which will be re-parsed and typechecked to reproduce
@@ -348,67 +348,67 @@ func stmtFmt(n Node, s fmt.State) {
 	case OAS:
 		n := n.(*AssignStmt)
 		if n.Def && !complexinit {
-			fmt.Fprintf(s, "%v := %v", n.X, n.Y)
+			types.Fprintf(s, "%v := %v", n.X, n.Y)
 		} else {
-			fmt.Fprintf(s, "%v = %v", n.X, n.Y)
+			types.Fprintf(s, "%v = %v", n.X, n.Y)
 		}
 
 	case OASOP:
 		n := n.(*AssignOpStmt)
 		if n.IncDec {
 			if n.AsOp == OADD {
-				fmt.Fprintf(s, "%v++", n.X)
+				types.Fprintf(s, "%v++", n.X)
 			} else {
-				fmt.Fprintf(s, "%v--", n.X)
+				types.Fprintf(s, "%v--", n.X)
 			}
 			break
 		}
 
-		fmt.Fprintf(s, "%v %v= %v", n.X, n.AsOp, n.Y)
+		types.Fprintf(s, "%v %v= %v", n.X, n.AsOp, n.Y)
 
 	case OAS2, OAS2DOTTYPE, OAS2FUNC, OAS2MAPR, OAS2RECV:
 		n := n.(*AssignListStmt)
 		if n.Def && !complexinit {
-			fmt.Fprintf(s, "%.v := %.v", n.Lhs, n.Rhs)
+			types.Fprintf(s, "%.v := %.v", n.Lhs, n.Rhs)
 		} else {
-			fmt.Fprintf(s, "%.v = %.v", n.Lhs, n.Rhs)
+			types.Fprintf(s, "%.v = %.v", n.Lhs, n.Rhs)
 		}
 
 	case OBLOCK:
 		n := n.(*BlockStmt)
 		if len(n.List) != 0 {
-			fmt.Fprintf(s, "%v", n.List)
+			types.Fprintf(s, "%v", n.List)
 		}
 
 	case ORETURN:
 		n := n.(*ReturnStmt)
-		fmt.Fprintf(s, "return %.v", n.Results)
+		types.Fprintf(s, "return %.v", n.Results)
 
 	case OTAILCALL:
 		n := n.(*TailCallStmt)
-		fmt.Fprintf(s, "tailcall %v", n.Call)
+		types.Fprintf(s, "tailcall %v", n.Call)
 
 	case OINLMARK:
 		n := n.(*InlineMarkStmt)
-		fmt.Fprintf(s, "inlmark %d", n.Index)
+		types.Fprintf(s, "inlmark %d", n.Index)
 
 	case OGO:
 		n := n.(*GoDeferStmt)
-		fmt.Fprintf(s, "go %v", n.Call)
+		types.Fprintf(s, "go %v", n.Call)
 
 	case ODEFER:
 		n := n.(*GoDeferStmt)
-		fmt.Fprintf(s, "defer %v", n.Call)
+		types.Fprintf(s, "defer %v", n.Call)
 
 	case OIF:
 		n := n.(*IfStmt)
 		if simpleinit {
-			fmt.Fprintf(s, "if %v; %v { %v }", n.Init()[0], n.Cond, n.Body)
+			types.Fprintf(s, "if %v; %v { %v }", n.Init()[0], n.Cond, n.Body)
 		} else {
-			fmt.Fprintf(s, "if %v { %v }", n.Cond, n.Body)
+			types.Fprintf(s, "if %v { %v }", n.Cond, n.Body)
 		}
 		if len(n.Else) != 0 {
-			fmt.Fprintf(s, " else { %v }", n.Else)
+			types.Fprintf(s, " else { %v }", n.Else)
 		}
 
 	case OFOR, OFORUNTIL:
@@ -418,32 +418,32 @@ func stmtFmt(n Node, s fmt.State) {
 			opname = "foruntil"
 		}
 		if !exportFormat { // TODO maybe only if FmtShort, same below
-			fmt.Fprintf(s, "%s loop", opname)
+			types.Fprintf(s, "%s loop", opname)
 			break
 		}
 
 		fmt.Fprint(s, opname)
 		if simpleinit {
-			fmt.Fprintf(s, " %v;", n.Init()[0])
+			types.Fprintf(s, " %v;", n.Init()[0])
 		} else if n.Post != nil {
 			fmt.Fprint(s, " ;")
 		}
 
 		if n.Cond != nil {
-			fmt.Fprintf(s, " %v", n.Cond)
+			types.Fprintf(s, " %v", n.Cond)
 		}
 
 		if n.Post != nil {
-			fmt.Fprintf(s, "; %v", n.Post)
+			types.Fprintf(s, "; %v", n.Post)
 		} else if simpleinit {
 			fmt.Fprint(s, ";")
 		}
 
 		if n.Op() == OFORUNTIL && len(n.Late) != 0 {
-			fmt.Fprintf(s, "; %v", n.Late)
+			types.Fprintf(s, "; %v", n.Late)
 		}
 
-		fmt.Fprintf(s, " { %v }", n.Body)
+		types.Fprintf(s, " { %v }", n.Body)
 
 	case ORANGE:
 		n := n.(*RangeStmt)
@@ -454,57 +454,57 @@ func stmtFmt(n Node, s fmt.State) {
 
 		fmt.Fprint(s, "for")
 		if n.Key != nil {
-			fmt.Fprintf(s, " %v", n.Key)
+			types.Fprintf(s, " %v", n.Key)
 			if n.Value != nil {
-				fmt.Fprintf(s, ", %v", n.Value)
+				types.Fprintf(s, ", %v", n.Value)
 			}
 			fmt.Fprint(s, " =")
 		}
-		fmt.Fprintf(s, " range %v { %v }", n.X, n.Body)
+		types.Fprintf(s, " range %v { %v }", n.X, n.Body)
 
 	case OSELECT:
 		n := n.(*SelectStmt)
 		if !exportFormat {
-			fmt.Fprintf(s, "%v statement", n.Op())
+			types.Fprintf(s, "%v statement", n.Op())
 			break
 		}
-		fmt.Fprintf(s, "select { %v }", n.Cases)
+		types.Fprintf(s, "select { %v }", n.Cases)
 
 	case OSWITCH:
 		n := n.(*SwitchStmt)
 		if !exportFormat {
-			fmt.Fprintf(s, "%v statement", n.Op())
+			types.Fprintf(s, "%v statement", n.Op())
 			break
 		}
-		fmt.Fprintf(s, "switch")
+		types.Fprintf(s, "switch")
 		if simpleinit {
-			fmt.Fprintf(s, " %v;", n.Init()[0])
+			types.Fprintf(s, " %v;", n.Init()[0])
 		}
 		if n.Tag != nil {
-			fmt.Fprintf(s, " %v ", n.Tag)
+			types.Fprintf(s, " %v ", n.Tag)
 		}
-		fmt.Fprintf(s, " { %v }", n.Cases)
+		types.Fprintf(s, " { %v }", n.Cases)
 
 	case OCASE:
 		n := n.(*CaseClause)
 		if len(n.List) != 0 {
-			fmt.Fprintf(s, "case %.v", n.List)
+			types.Fprintf(s, "case %.v", n.List)
 		} else {
 			fmt.Fprint(s, "default")
 		}
-		fmt.Fprintf(s, ": %v", n.Body)
+		types.Fprintf(s, ": %v", n.Body)
 
 	case OBREAK, OCONTINUE, OGOTO, OFALL:
 		n := n.(*BranchStmt)
 		if n.Label != nil {
-			fmt.Fprintf(s, "%v %v", n.Op(), n.Label)
+			types.Fprintf(s, "%v %v", n.Op(), n.Label)
 		} else {
-			fmt.Fprintf(s, "%v", n.Op())
+			types.Fprintf(s, "%v", n.Op())
 		}
 
 	case OLABEL:
 		n := n.(*LabelStmt)
-		fmt.Fprintf(s, "%v: ", n.Label)
+		types.Fprintf(s, "%v: ", n.Label)
 	}
 
 	if extrablock {
@@ -564,7 +564,7 @@ func exprFmt(n Node, s fmt.State, prec int) {
 	}
 
 	if prec > nprec {
-		fmt.Fprintf(s, "(%v)", n)
+		types.Fprintf(s, "(%v)", n)
 		return
 	}
 
@@ -576,14 +576,14 @@ func exprFmt(n Node, s fmt.State, prec int) {
 	switch n.Op() {
 	case OPAREN:
 		n := n.(*ParenExpr)
-		fmt.Fprintf(s, "(%v)", n.X)
+		types.Fprintf(s, "(%v)", n.X)
 
 	case ONIL:
 		fmt.Fprint(s, "nil")
 
 	case OLITERAL: // this is a bit of a mess
 		if !exportFormat && n.Sym() != nil {
-			fmt.Fprint(s, n.Sym())
+			types.Fprintf(s, "%v", n.Sym())
 			return
 		}
 
@@ -592,9 +592,9 @@ func exprFmt(n Node, s fmt.State, prec int) {
 			// Need parens when type begins with what might
 			// be misinterpreted as a unary operator: * or <-.
 			if n.Type().IsPtr() || (n.Type().IsChan() && n.Type().ChanDir() == types.Crecv) {
-				fmt.Fprintf(s, "(%v)(", n.Type())
+				types.Fprintf(s, "(%v)(", n.Type())
 			} else {
-				fmt.Fprintf(s, "%v(", n.Type())
+				types.Fprintf(s, "%v(", n.Type())
 			}
 			needUnparen = true
 		}
@@ -604,90 +604,90 @@ func exprFmt(n Node, s fmt.State, prec int) {
 			case !ok:
 				fallthrough
 			default:
-				fmt.Fprintf(s, "('\\x00' + %v)", n.Val())
+				types.Fprintf(s, "('\\x00' + %v)", n.Val())
 
 			case x < utf8.RuneSelf:
-				fmt.Fprintf(s, "%q", x)
+				types.Fprintf(s, "%q", x)
 
 			case x < 1<<16:
-				fmt.Fprintf(s, "'\\u%04x'", x)
+				types.Fprintf(s, "'\\u%04x'", x)
 
 			case x <= utf8.MaxRune:
-				fmt.Fprintf(s, "'\\U%08x'", x)
+				types.Fprintf(s, "'\\U%08x'", x)
 			}
 		} else {
 			fmt.Fprint(s, types.FmtConst(n.Val(), s.Flag('#')))
 		}
 
 		if needUnparen {
-			fmt.Fprintf(s, ")")
+			types.Fprintf(s, ")")
 		}
 
 	case ODCLFUNC:
 		n := n.(*Func)
 		if sym := n.Sym(); sym != nil {
-			fmt.Fprint(s, sym)
+			types.Fprintf(s, "%v", sym)
 			return
 		}
-		fmt.Fprintf(s, "<unnamed Func>")
+		types.Fprintf(s, "<unnamed Func>")
 
 	case ONAME:
 		n := n.(*Name)
 		// Special case: name used as local variable in export.
 		// _ becomes ~b%d internally; print as _ for export
-		if !exportFormat && n.Sym() != nil && n.Sym().Name[0] == '~' && n.Sym().Name[1] == 'b' {
+		if !exportFormat && n.Sym() != nil && types.ParamKindOf(n.Sym().Name) == types.ParamBlank {
 			fmt.Fprint(s, "_")
 			return
 		}
 		fallthrough
 	case OPACK, ONONAME:
-		fmt.Fprint(s, n.Sym())
+		types.Fprintf(s, "%v", n.Sym())
 
 	case OLINKSYMOFFSET:
 		n := n.(*LinksymOffsetExpr)
-		fmt.Fprintf(s, "(%v)(%s@%d)", n.Type(), n.Linksym.Name, n.Offset_)
+		types.Fprintf(s, "(%v)(%s@%d)", n.Type(), n.Linksym.Name, n.Offset_)
 
 	case OTYPE:
 		if n.Type() == nil && n.Sym() != nil {
-			fmt.Fprint(s, n.Sym())
+			types.Fprintf(s, "%v", n.Sym())
 			return
 		}
-		fmt.Fprintf(s, "%v", n.Type())
+		types.Fprintf(s, "%v", n.Type())
 
 	case OTSLICE:
 		n := n.(*SliceType)
 		if n.DDD {
-			fmt.Fprintf(s, "...%v", n.Elem)
+			types.Fprintf(s, "...%v", n.Elem)
 		} else {
-			fmt.Fprintf(s, "[]%v", n.Elem) // happens before typecheck
+			types.Fprintf(s, "[]%v", n.Elem) // happens before typecheck
 		}
 
 	case OTARRAY:
 		n := n.(*ArrayType)
 		if n.Len == nil {
-			fmt.Fprintf(s, "[...]%v", n.Elem)
+			types.Fprintf(s, "[...]%v", n.Elem)
 		} else {
-			fmt.Fprintf(s, "[%v]%v", n.Len, n.Elem)
+			types.Fprintf(s, "[%v]%v", n.Len, n.Elem)
 		}
 
 	case OTMAP:
 		n := n.(*MapType)
-		fmt.Fprintf(s, "map[%v]%v", n.Key, n.Elem)
+		types.Fprintf(s, "map[%v]%v", n.Key, n.Elem)
 
 	case OTCHAN:
 		n := n.(*ChanType)
 		switch n.Dir {
 		case types.Crecv:
-			fmt.Fprintf(s, "<-chan %v", n.Elem)
+			types.Fprintf(s, "<-chan %v", n.Elem)
 
 		case types.Csend:
-			fmt.Fprintf(s, "chan<- %v", n.Elem)
+			types.Fprintf(s, "chan<- %v", n.Elem)
 
 		default:
 			if n.Elem != nil && n.Elem.Op() == OTCHAN && n.Elem.(*ChanType).Dir == types.Crecv {
-				fmt.Fprintf(s, "chan (%v)", n.Elem)
+				types.Fprintf(s, "chan (%v)", n.Elem)
 			} else {
-				fmt.Fprintf(s, "chan %v", n.Elem)
+				types.Fprintf(s, "chan %v", n.Elem)
 			}
 		}
 
@@ -706,61 +706,61 @@ func exprFmt(n Node, s fmt.State, prec int) {
 			fmt.Fprint(s, "func literal")
 			return
 		}
-		fmt.Fprintf(s, "%v { %v }", n.Type(), n.Func.Body)
+		types.Fprintf(s, "%v { %v }", n.Type(), n.Func.Body)
 
 	case OCOMPLIT:
 		n := n.(*CompLitExpr)
 		if !exportFormat {
 			if n.Implicit() {
-				fmt.Fprintf(s, "... argument")
+				types.Fprintf(s, "... argument")
 				return
 			}
 			if typ := n.Type(); typ != nil {
-				fmt.Fprintf(s, "%v{%s}", typ, ellipsisIf(len(n.List) != 0))
+				types.Fprintf(s, "%v{%s}", typ, ellipsisIf(len(n.List) != 0))
 				return
 			}
 			if n.Ntype != nil {
-				fmt.Fprintf(s, "%v{%s}", n.Ntype, ellipsisIf(len(n.List) != 0))
+				types.Fprintf(s, "%v{%s}", n.Ntype, ellipsisIf(len(n.List) != 0))
 				return
 			}
 
 			fmt.Fprint(s, "composite literal")
 			return
 		}
-		fmt.Fprintf(s, "(%v{ %.v })", n.Ntype, n.List)
+		types.Fprintf(s, "(%v{ %.v })", n.Ntype, n.List)
 
 	case OPTRLIT:
 		n := n.(*AddrExpr)
-		fmt.Fprintf(s, "&%v", n.X)
+		types.Fprintf(s, "&%v", n.X)
 
 	case OSTRUCTLIT, OARRAYLIT, OSLICELIT, OMAPLIT:
 		n := n.(*CompLitExpr)
 		if !exportFormat {
-			fmt.Fprintf(s, "%v{%s}", n.Type(), ellipsisIf(len(n.List) != 0))
+			types.Fprintf(s, "%v{%s}", n.Type(), ellipsisIf(len(n.List) != 0))
 			return
 		}
-		fmt.Fprintf(s, "(%v{ %.v })", n.Type(), n.List)
+		types.Fprintf(s, "(%v{ %.v })", n.Type(), n.List)
 
 	case OKEY:
 		n := n.(*KeyExpr)
 		if n.Key != nil && n.Value != nil {
-			fmt.Fprintf(s, "%v:%v", n.Key, n.Value)
+			types.Fprintf(s, "%v:%v", n.Key, n.Value)
 			return
 		}
 
 		if n.Key == nil && n.Value != nil {
-			fmt.Fprintf(s, ":%v", n.Value)
+			types.Fprintf(s, ":%v", n.Value)
 			return
 		}
 		if n.Key != nil && n.Value == nil {
-			fmt.Fprintf(s, "%v:", n.Key)
+			types.Fprintf(s, "%v:", n.Key)
 			return
 		}
 		fmt.Fprint(s, ":")
 
 	case OSTRUCTKEY:
 		n := n.(*StructKeyExpr)
-		fmt.Fprintf(s, "%v:%v", n.Field, n.Value)
+		types.Fprintf(s, "%v:%v", n.Field, n.Value)
 
 	case OXDOT, ODOT, ODOTPTR, ODOTINTER, ODOTMETH, OMETHVALUE, OMETHEXPR:
 		n := n.(*SelectorExpr)
@@ -769,48 +769,48 @@ func exprFmt(n Node, s fmt.State, prec int) {
 			fmt.Fprint(s, ".<nil>")
 			return
 		}
-		fmt.Fprintf(s, ".%s", n.Sel.Name)
+		types.Fprintf(s, ".%s", n.Sel.Name)
 
 	case ODOTTYPE, ODOTTYPE2:
 		n := n.(*TypeAssertExpr)
 		exprFmt(n.X, s, nprec)
 		if n.Ntype != nil {
-			fmt.Fprintf(s, ".(%v)", n.Ntype)
+			types.Fprintf(s, ".(%v)", n.Ntype)
 			return
 		}
-		fmt.Fprintf(s, ".(%v)", n.Type())
+		types.Fprintf(s, ".(%v)", n.Type())
 
 	case OINDEX, OINDEXMAP:
 		n := n.(*IndexExpr)
 		exprFmt(n.X, s, nprec)
-		fmt.Fprintf(s, "[%v]", n.Index)
+		types.Fprintf(s, "[%v]", n.Index)
 
 	case OSLICE, OSLICESTR, OSLICEARR, OSLICE3, OSLICE3ARR:
 		n := n.(*SliceExpr)
 		exprFmt(n.X, s, nprec)
 		fmt.Fprint(s, "[")
 		if n.Low != nil {
-			fmt.Fprint(s, n.Low)
+			types.Fprintf(s, "%v", n.Low)
 		}
 		fmt.Fprint(s, ":")
 		if n.High != nil {
-			fmt.Fprint(s, n.High)
+			types.Fprintf(s, "%v", n.High)
 		}
 		if n.Op().IsSlice3() {
 			fmt.Fprint(s, ":")
 			if n.Max != nil {
-				fmt.Fprint(s, n.Max)
+				types.Fprintf(s, "%v", n.Max)
 			}
 		}
 		fmt.Fprint(s, "]")
 
 	case OSLICEHEADER:
 		n := n.(*SliceHeaderExpr)
-		fmt.Fprintf(s, "sliceheader{%v,%v,%v}", n.Ptr, n.Len, n.Cap)
+		types.Fprintf(s, "sliceheader{%v,%v,%v}", n.Ptr, n.Len, n.Cap)
 
 	case OCOMPLEX, OCOPY, OUNSAFEADD, OUNSAFESLICE:
 		n := n.(*BinaryExpr)
-		fmt.Fprintf(s, "%v(%v, %v)", n.Op(), n.X, n.Y)
+		types.Fprintf(s, "%v(%v, %v)", n.Op(), n.X, n.Y)
 
 	case OCONV,
 		OCONVIFACE,
@@ -824,11 +824,11 @@ func exprFmt(n Node, s fmt.State, prec int) {
 		OSLICE2ARRPTR:
 		n := n.(*ConvExpr)
 		if n.Type() == nil || n.Type().Sym() == nil {
-			fmt.Fprintf(s, "(%v)", n.Type())
+			types.Fprintf(s, "(%v)", n.Type())
 		} else {
-			fmt.Fprintf(s, "%v", n.Type())
+			types.Fprintf(s, "%v", n.Type())
 		}
-		fmt.Fprintf(s, "(%v)", n.X)
+		types.Fprintf(s, "(%v)", n.X)
 
 	case OREAL,
 		OIMAG,
@@ -841,7 +841,7 @@ func exprFmt(n Node, s fmt.State, prec int) {
 		OOFFSETOF,
 		OSIZEOF:
 		n := n.(*UnaryExpr)
-		fmt.Fprintf(s, "%v(%v)", n.Op(), n.X)
+		types.Fprintf(s, "%v(%v)", n.Op(), n.X)
 
 	case OAPPEND,
 		ODELETE,
@@ -851,49 +851,49 @@ func exprFmt(n Node, s fmt.State, prec int) {
 		OPRINTN:
 		n := n.(*CallExpr)
 		if n.IsDDD {
-			fmt.Fprintf(s, "%v(%.v...)", n.Op(), n.Args)
+			types.Fprintf(s, "%v(%.v...)", n.Op(), n.Args)
 			return
 		}
-		fmt.Fprintf(s, "%v(%.v)", n.Op(), n.Args)
+		types.Fprintf(s, "%v(%.v)", n.Op(), n.Args)
 
 	case OCALL, OCALLFUNC, OCALLINTER, OCALLMETH, OGETG:
 		n := n.(*CallExpr)
 		exprFmt(n.X, s, nprec)
 		if n.IsDDD {
-			fmt.Fprintf(s, "(%.v...)", n.Args)
+			types.Fprintf(s, "(%.v...)", n.Args)
 			return
 		}
-		fmt.Fprintf(s, "(%.v)", n.Args)
+		types.Fprintf(s, "(%.v)", n.Args)
 
 	case OINLCALL:
 		n := n.(*InlinedCallExpr)
 		// TODO(mdempsky): Print Init and/or Body?
 		if len(n.ReturnVars) == 1 {
-			fmt.Fprintf(s, "%v", n.ReturnVars[0])
+			types.Fprintf(s, "%v", n.ReturnVars[0])
 			return
 		}
-		fmt.Fprintf(s, "(.%v)", n.ReturnVars)
+		types.Fprintf(s, "(.%v)", n.ReturnVars)
 
 	case OMAKEMAP, OMAKECHAN, OMAKESLICE:
 		n := n.(*MakeExpr)
 		if n.Cap != nil {
-			fmt.Fprintf(s, "make(%v, %v, %v)", n.Type(), n.Len, n.Cap)
+			types.Fprintf(s, "make(%v, %v, %v)", n.Type(), n.Len, n.Cap)
 			return
 		}
 		if n.Len != nil && (n.Op() == OMAKESLICE || !n.Len.Type().IsUntyped()) {
-			fmt.Fprintf(s, "make(%v, %v)", n.Type(), n.Len)
+			types.Fprintf(s, "make(%v, %v)", n.Type(), n.Len)
 			return
 		}
-		fmt.Fprintf(s, "make(%v)", n.Type())
+		types.Fprintf(s, "make(%v)", n.Type())
 
 	case OMAKESLICECOPY:
 		n := n.(*MakeExpr)
-		fmt.Fprintf(s, "makeslicecopy(%v, %v, %v)", n.Type(), n.Len, n.Cap)
+		types.Fprintf(s, "makeslicecopy(%v, %v, %v)", n.Type(), n.Len, n.Cap)
 
 	case OPLUS, ONEG, OBITNOT, ONOT, ORECV:
 		// Unary
 		n := n.(*UnaryExpr)
-		fmt.Fprintf(s, "%v", n.Op())
+		types.Fprintf(s, "%v", n.Op())
 		if n.X != nil && n.X.Op() == n.Op() {
 			fmt.Fprint(s, " ")
 		}
@@ -901,7 +901,7 @@ func exprFmt(n Node, s fmt.State, prec int) {
 
 	case OADDR:
 		n := n.(*AddrExpr)
-		fmt.Fprintf(s, "%v", n.Op())
+		types.Fprintf(s, "%v", n.Op())
 		if n.X != nil && n.X.Op() == n.Op() {
 			fmt.Fprint(s, " ")
 		}
@@ -909,7 +909,7 @@ func exprFmt(n Node, s fmt.State, prec int) {
 
 	case ODEREF:
 		n := n.(*StarExpr)
-		fmt.Fprintf(s, "%v", n.Op())
+		types.Fprintf(s, "%v", n.Op())
 		exprFmt(n.X, s, nprec+1)
 
 		// Binary
@@ -932,20 +932,20 @@ func exprFmt(n Node, s fmt.State, prec int) {
 		OXOR:
 		n := n.(*BinaryExpr)
 		exprFmt(n.X, s, nprec)
-		fmt.Fprintf(s, " %v ", n.Op())
+		types.Fprintf(s, " %v ", n.Op())
 		exprFmt(n.Y, s, nprec+1)
 
 	case OANDAND,
 		OOROR:
 		n := n.(*LogicalExpr)
 		exprFmt(n.X, s, nprec)
-		fmt.Fprintf(s, " %v ", n.Op())
+		types.Fprintf(s, " %v ", n.Op())
 		exprFmt(n.Y, s, nprec+1)
 
 	case OSEND:
 		n := n.(*SendStmt)
 		exprFmt(n.Chan, s, nprec)
-		fmt.Fprintf(s, " <- ")
+		types.Fprintf(s, " <- ")
 		exprFmt(n.Value, s, nprec+1)
 
 	case OADDSTR:
@@ -957,7 +957,7 @@ func exprFmt(n Node, s fmt.State, prec int) {
 			exprFmt(n1, s, nprec)
 		}
 	default:
-		fmt.Fprintf(s, "<node %v>", n.Op())
+		types.Fprintf(s, "<node %v>", n.Op())
 	}
 }
 
@@ -985,7 +985,7 @@ func (l Nodes) Format(s fmt.State, verb rune) {
 	}
 
 	if verb != 'v' {
-		fmt.Fprintf(s, "%%!%c(Nodes)", verb)
+		base.BadVerb(s, verb, fmt.Sprintf("%%!%c(Nodes)", verb))
 		return
 	}
 
@@ -995,7 +995,7 @@ func (l Nodes) Format(s fmt.State, verb rune) {
 	}
 
 	for i, n := range l {
-		fmt.Fprint(s, n)
+		types.Fprintf(s, "%v", n)
 		if i+1 < len(l) {
 			fmt.Fprint(s, sep)
 		}
@@ -1180,7 +1180,7 @@ func dumpNode(w io.Writer, n Node, depth int) {
 		dumpNodeHeader(w, n)
 
 	case OLITERAL:
-		fmt.Fprintf(w, "%+v-%v", n.Op(), n.Val())
+		fmt.Fprintf(w, "%+v-%v", n.Op(), types.FmtConst(n.Val(), true))
 		dumpNodeHeader(w, n)
 		return
 
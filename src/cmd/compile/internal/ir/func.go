@@ -98,6 +98,10 @@ type Func struct {
 
 	Inl *Inline
 
+	// Inst describes the function if it is a shape-based
+	// instantiation of a generic function or method.
+	Inst *FuncInst
+
 	// Closgen tracks how many closures have been generated within
 	// this function. Used by closurename for creating unique
 	// function names.
@@ -110,6 +114,8 @@ type Func struct {
 
 	Pragma PragmaFlag // go:xxx function annotations
 
+	InlineBudget int32 // budget of //go:inlinebudget, or 0
+
 	flags bitset16
 
 	// ABI is a function's "definition" ABI. This is the ABI that
@@ -148,6 +154,17 @@ func NewFunc(pos src.XPos) *Func {
 
 func (f *Func) isStmt() {}
 
+// A FuncInst describes a shape-based instantiation of a generic
+// function or method.
+type FuncInst struct {
+	// TParams are the type parameters of the generic function, or of
+	// the receiver type of the generic method, in the order of their
+	// entries in the instantiation's dictionary.
+	TParams []*types.Type
+	// Shapes are the shape type arguments for TParams.
+	Shapes []*types.Type
+}
+
 func (n *Func) copy() Node                         { panic(n.no("copy")) }
 func (n *Func) doChildren(do func(Node) bool) bool { return doNodes(n.Body, do) }
 func (n *Func) editChildren(edit func(Node) Node)  { editNodes(n.Body, edit) }
@@ -317,7 +334,7 @@ func ClosureDebugRuntimeCheck(clo *ClosureExpr) {
 		}
 	}
 	if base.Flag.CompilingRuntime && clo.Esc() == EscHeap && !clo.IsGoWrap {
-		base.ErrorfAt(clo.Pos(), "heap-allocated closure %s, not allowed in runtime", FuncName(clo.Func))
+		base.ErrorfAt(clo.Pos(), 0, "heap-allocated closure %s, not allowed in runtime", FuncName(clo.Func))
 	}
 }
 
@@ -456,7 +456,7 @@ func (s NameSet) Sorted(less func(*Name, *Name) bool) []*Name {
 	return res
 }
 
-type PragmaFlag uint16
+type PragmaFlag uint32
 
 const (
 	// Func pragmas.
@@ -469,6 +469,8 @@ const (
 	CgoUnsafeArgs               // treat a pointer to one arg as a pointer to them all
 	UintptrKeepAlive            // pointers converted to uintptr must be kept alive (compiler internal only)
 	UintptrEscapes              // pointers converted to uintptr escape
+	Stencil                     // generic func is instantiated separately for each type argument
+	Cold                        // func is rarely called
 
 	// Runtime-only func pragmas.
 	// See ../../../../runtime/README.md for detailed descriptions.
@@ -485,6 +487,17 @@ const (
 
 	RegisterParams // TODO(register args) remove after register abi is working
 
+	// Func pragmas that come after NotInHeap, as the type pragmas
+	// of a Name must fit in 16 bits.
+	InlineBudget // func has the inlining budget given with the pragma
+
+	// If statement pragmas.
+	Likely   // the condition is usually true
+	Unlikely // the condition is usually false
+
+	// For statement pragmas.
+	Unroll // unroll the loop, by the factor given with the pragma
+
 )
 
 func AsNode(n types.Object) Node {
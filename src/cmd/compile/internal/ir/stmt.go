@@ -202,6 +202,7 @@ type ForStmt struct {
 	Post     Node
 	Body     Nodes
 	HasBreak bool
+	Unroll   int // //go:unroll factor, or 0
 }
 
 func NewForStmt(pos src.XPos, init Node, cond, post Node, body []Node) *ForStmt {
@@ -247,10 +248,11 @@ func NewGoDeferStmt(pos src.XPos, op Op, call Node) *GoDeferStmt {
 // An IfStmt is a return statement: if Init; Cond { Body } else { Else }.
 type IfStmt struct {
 	miniStmt
-	Cond   Node
-	Body   Nodes
-	Else   Nodes
-	Likely bool // code layout hint
+	Cond     Node
+	Body     Nodes
+	Else     Nodes
+	Likely   bool // code layout hint
+	Unlikely bool // code layout hint
 }
 
 func NewIfStmt(pos src.XPos, cond Node, body, els []Node) *IfStmt {
@@ -304,6 +306,7 @@ type RangeStmt struct {
 	Body     Nodes
 	HasBreak bool
 	Prealloc *Name
+	Unroll   int // //go:unroll factor, or 0
 }
 
 func NewRangeStmt(pos src.XPos, key, value, x Node, body []Node) *RangeStmt {
@@ -59,6 +59,14 @@ import (
 // Source: (always) "go compiler"
 // Code: a string describing the missed optimization, e.g., "nilcheck", "cannotInline", "isInBounds", "escape"
 // Message: depending on code, additional information, e.g., the reason a function cannot be inlined.
+//
+// The decisions that -m prints are all logged, so that tools need not parse
+// its text. Their codes are
+//
+//    canInlineFunction, cannotInlineFunction, inlineCall, cannotInlineCall (inlining),
+//    escape, doesNotEscape, leak, leakingParam, unsafeUintptr, uintptrEscapes (escape analysis),
+//    devirtualize, cannotDevirtualize (devirtualization),
+//    bceKept, bceRemoved (bounds checks, with why they are kept or removed, as for -d=bce).
 // RelatedInformation: if the missed optimization actually occurred at a function inlined at Range,
 //    then the sequence of inlined locations appears here, from (second) outermost to innermost,
 //    each with message="inlineLoc".
@@ -219,6 +227,12 @@ type Diagnostic struct {
 	 * a scope collide all definitions can be marked via this property.
 	 */
 	RelatedInformation []DiagnosticRelatedInformation `json:"relatedInformation,omitempty"`
+
+	/*Data defined:
+	 * A data entry field that is preserved between a `textDocument/publishDiagnostics`
+	 * notification and `textDocument/codeAction` request (LSP 3.16).
+	 */
+	Data []interface{} `json:"data,omitempty"` // the structured arguments of a Message, if any.
 }
 
 // A LoggedOpt is what the compiler produces and accumulates,
@@ -329,6 +343,38 @@ func NewLoggedOpt(pos src.XPos, what, pass, funcName string, args ...interface{}
 	return &LoggedOpt{pos, pass, funcName, what, args}
 }
 
+// A Message is a diagnostic message whose arguments are only formatted
+// when the diagnostic is written out, so that each output format can
+// render them as it sees fit. Pass one to NewLoggedOpt or LogOpt as the
+// first of "args". Arguments that implement json.Marshaler (such as
+// types.LogType) are also recorded in the Data field of -json output.
+type Message struct {
+	format string
+	args   []interface{}
+}
+
+// Msgf returns a Message formatting args according to format.
+func Msgf(format string, args ...interface{}) *Message {
+	return &Message{format, args}
+}
+
+// String returns the text of m, with its arguments formatted as for
+// fmt.Sprintf.
+func (m *Message) String() string {
+	return fmt.Sprintf(m.format, m.args...)
+}
+
+// data returns the arguments of m that marshal themselves to JSON.
+func (m *Message) data() []interface{} {
+	var data []interface{}
+	for _, arg := range m.args {
+		if _, ok := arg.(json.Marshaler); ok {
+			data = append(data, arg)
+		}
+	}
+	return data
+}
+
 // Logopt logs information about a (usually missed) optimization performed by the compiler.
 // Pos is the source position (including inlining), what is the message, pass is which pass created the message,
 // funcName is the name of the function
@@ -453,8 +499,12 @@ func FlushLoggedOpts(ctxt *obj.Link, slashPkgPath string) {
 
 			// The first "target" is the most important one.
 			var target string
+			diagnostic.Data = nil
 			if len(x.target) > 0 {
 				target = fmt.Sprint(x.target[0])
+				if m, ok := x.target[0].(*Message); ok {
+					diagnostic.Data = m.data()
+				}
 			}
 
 			diagnostic.Code = x.what
@@ -182,6 +182,35 @@ func s15a8(x *[15]int64) [15]int64 {
 		}
 	})
 
+	// Types in devirtualization diagnostics are recorded as data too.
+	t.Run("Devirtualize", func(t *testing.T) {
+		const devirtCode = `package x
+type I interface{ M() }
+type T struct{ n int }
+func (t T) M() { println(t.n) }
+func f() {
+	var i I = T{}
+	i.M()
+}
+`
+		devirt := filepath.Join(dir, "devirt.go")
+		if err := ioutil.WriteFile(devirt, []byte(devirtCode), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := testLogOptDir(t, dir, "-json=0,file://log/opt", devirt, filepath.Join(dir, "devirt.o"))
+		if err != nil {
+			t.Error("-json=0,file://log/opt should have succeeded")
+		}
+		logged, err := ioutil.ReadFile(filepath.Join(dir, "log", "opt", "x", "devirt.json"))
+		if err != nil {
+			t.Error("-json=0,file://log/opt missing expected log file")
+		}
+		slogged := normalize(logged, string(uriIfy(dir)), string(uriIfy("tmpdir")))
+		t.Logf("%s", slogged)
+		want(t, slogged, `{"range":{"start":{"line":7,"character":5},"end":{"line":7,"character":5}},"severity":3,"code":"devirtualize","source":"go compiler","message":"devirtualizing i.M to T",`+
+			`"data":[{"type":"T","kind":"STRUCT","pkgpath":"x"}]}`)
+	})
+
 	// Some architectures don't fault on nil dereference, so nilchecks are eliminated differently.
 	// The N-way copy test also doesn't need to run N-ways N times.
 	if runtime.GOARCH != "amd64" {
@@ -208,6 +237,15 @@ func s15a8(x *[15]int64) [15]int64 {
 			`"relatedInformation":[{"location":{"uri":"file://tmpdir/file.go","range":{"start":{"line":4,"character":11},"end":{"line":4,"character":11}}},"message":"inlineLoc"}]}`)
 		want(t, slogged, `{"range":{"start":{"line":11,"character":6},"end":{"line":11,"character":6}},"severity":3,"code":"isInBounds","source":"go compiler","message":""}`)
 		want(t, slogged, `{"range":{"start":{"line":7,"character":6},"end":{"line":7,"character":6}},"severity":3,"code":"canInlineFunction","source":"go compiler","message":"cost: 35"}`)
+		want(t, slogged, `{"range":{"start":{"line":9,"character":13},"end":{"line":9,"character":13}},"severity":3,"code":"inlineCall","source":"go compiler","message":"bar"}`)
+		want(t, slogged, `{"range":{"start":{"line":18,"character":6},"end":{"line":18,"character":6}},"severity":3,"code":"cannotInlineFunction","source":"go compiler","message":"function too complex: cost 154 exceeds budget 80"}`)
+		// escape analysis results
+		want(t, slogged, `{"range":{"start":{"line":7,"character":10},"end":{"line":7,"character":10}},"severity":3,"code":"doesNotEscape","source":"go compiler","message":"w does not escape"}`)
+		want(t, slogged, `{"range":{"start":{"line":7,"character":13},"end":{"line":7,"character":13}},"severity":3,"code":"leakingParam","source":"go compiler","message":"leaking param: z to result ~r0 level=0"}`)
+		want(t, slogged, `{"range":{"start":{"line":19,"character":9},"end":{"line":19,"character":9}},"severity":3,"code":"doesNotEscape","source":"go compiler","message":"func literal does not escape"}`)
+		// bounds checks, with reasons
+		want(t, slogged, `{"range":{"start":{"line":11,"character":6},"end":{"line":11,"character":6}},"severity":3,"code":"bceKept","source":"go compiler","message":"index bounds check: len unknown"}`)
+		want(t, slogged, `{"range":{"start":{"line":12,"character":8},"end":{"line":12,"character":8}},"severity":3,"code":"bceRemoved","source":"go compiler","message":"slice bounds check: proved in range by dominating conditions"}`)
 		// escape analysis explanation
 		want(t, slogged, `{"range":{"start":{"line":7,"character":13},"end":{"line":7,"character":13}},"severity":3,"code":"leak","source":"go compiler","message":"parameter z leaks to ~r0 with derefs=0",`+
 			`"relatedInformation":[`+
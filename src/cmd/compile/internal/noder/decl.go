@@ -8,6 +8,7 @@ import (
 	"go/constant"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/typecheck"
@@ -44,12 +45,17 @@ func (g *irgen) importDecl(p *noder, decl *syntax.ImportDecl) {
 	// Get the imported package's path, as resolved already by types2
 	// and gcimporter. This is the same path as would be computed by
 	// parseImportPath.
-	switch pkgNameOf(g.info, decl).Imported().Path() {
+	imported := pkgNameOf(g.info, decl).Imported()
+	switch imported.Path() {
 	case "unsafe":
 		p.importedUnsafe = true
 	case "embed":
 		p.importedEmbed = true
 	}
+
+	if name := decl.LocalPkgName; name != nil {
+		g.pkg(imported).AddAlias(base.Ctxt.PosTable.Pos(g.pos(decl)).Filename(), name.Value)
+	}
 }
 
 // pkgNameOf returns the PkgName associated with the given ImportDecl.
@@ -102,9 +108,13 @@ func (g *irgen) funcDecl(out *ir.Nodes, decl *syntax.FuncDecl) {
 	fn.Nname.Func = fn
 	fn.Nname.Defn = fn
 
+	fn.InlineBudget = inlineBudget(decl.Pragma)
 	fn.Pragma = g.pragmaFlags(decl.Pragma, funcPragmas)
 	if fn.Pragma&ir.Systemstack != 0 && fn.Pragma&ir.Nosplit != 0 {
-		base.ErrorfAt(fn.Pos(), "go:nosplit and go:systemstack cannot be combined")
+		base.ErrorfAt(fn.Pos(), errcode.InvalidDirective, "go:nosplit and go:systemstack cannot be combined")
+	}
+	if msg := checkInlineBudget(fn.Pragma, fn.InlineBudget); msg != "" {
+		base.ErrorfAt(fn.Pos(), errcode.InvalidDirective, "%s", msg)
 	}
 	if fn.Pragma&ir.Nointerface != 0 {
 		// Propagate //go:nointerface from Func.Pragma to Field.Nointerface.
@@ -125,7 +135,7 @@ func (g *irgen) funcDecl(out *ir.Nodes, decl *syntax.FuncDecl) {
 	}
 
 	if decl.Body != nil && fn.Pragma&ir.Noescape != 0 {
-		base.ErrorfAt(fn.Pos(), "can only use //go:noescape with external func implementations")
+		base.ErrorfAt(fn.Pos(), errcode.InvalidDirective, "can only use //go:noescape with external func implementations")
 	}
 
 	if decl.Name.Value == "init" && decl.Recv == nil {
@@ -324,12 +334,12 @@ func (g *irgen) pragmaFlags(pragma syntax.Pragma, allowed ir.PragmaFlag) ir.Prag
 func (g *irgen) reportUnused(pragma *pragmas) {
 	for _, pos := range pragma.Pos {
 		if pos.Flag&pragma.Flag != 0 {
-			base.ErrorfAt(g.makeXPos(pos.Pos), "misplaced compiler directive")
+			base.ErrorfAt(g.makeXPos(pos.Pos), errcode.InvalidDirective, "misplaced compiler directive")
 		}
 	}
 	if len(pragma.Embeds) > 0 {
 		for _, e := range pragma.Embeds {
-			base.ErrorfAt(g.makeXPos(e.Pos), "misplaced go:embed directive")
+			base.ErrorfAt(g.makeXPos(e.Pos), errcode.InvalidDirective, "misplaced go:embed directive")
 		}
 	}
 }
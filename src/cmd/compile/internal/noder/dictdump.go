@@ -0,0 +1,135 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+)
+
+// A dictDump collects the entries of a dictionary of an instantiation
+// of the generic function selected by -d=dictionary, to print its
+// layout once the dictionary is finished.
+type dictDump struct {
+	gf      *ir.Name
+	name    string // name of the instantiation, as in T[int].M
+	sym     *types.Sym
+	entries []string
+}
+
+// newDictDump returns a dictDump for the dictionary sym of the
+// instantiation of gf with targs, or nil if -d=dictionary does not
+// select gf.
+func newDictDump(gf *ir.Name, targs []*types.Type, isMeth bool, sym *types.Sym) *dictDump {
+	if base.Debug.Dictionary == "" {
+		return nil
+	}
+	name, tparams, nrecv := genericName(gf, isMeth)
+	if name == "" || !dictDumpMatch(gf.Sym().Pkg, name, base.Debug.Dictionary) {
+		return nil
+	}
+
+	d := &dictDump{gf: gf, name: instName(name, targs, nrecv), sym: sym}
+	for i, t := range targs {
+		tparam := tparams[i].Sym().Name // qualified, as in G.X
+		d.add("type argument %s = %v", tparam[strings.LastIndexByte(tparam, '.')+1:], t)
+	}
+	return d
+}
+
+// genericName returns the name of the generic function or method gf,
+// as in F or T.M, and its type parameters, of which the first nrecv are
+// those of its receiver type (see instTParams). It returns "" if gf is
+// a method with no type parameters of its own whose receiver is not a
+// generic type.
+func genericName(gf *ir.Name, isMeth bool) (name string, tparams []*types.Type, nrecv int) {
+	tparams, nrecv = instTParams(gf, isMeth)
+	if !isMeth {
+		return gf.Sym().Name, tparams, 0
+	}
+	recv := deref(gf.Type().Recv().Type)
+	sym := recv.OrigSym()
+	if sym == nil {
+		if len(tparams) == 0 {
+			return "", nil, 0
+		}
+		sym = recv.Sym()
+	}
+	// The name of a method is qualified by its receiver, as in
+	// (*L[E]).Get.
+	meth := gf.Sym().Name
+	return sym.Name + "." + meth[strings.LastIndexByte(meth, '.')+1:], tparams, nrecv
+}
+
+// instName returns the name of the instantiation with targs of the
+// generic function or method name, as in F[int], T[int].M or
+// T[int].M[string], where the first nrecv of targs are those of the
+// receiver type of a method.
+func instName(name string, targs []*types.Type, nrecv int) string {
+	var args []string
+	for _, t := range targs {
+		args = append(args, t.String())
+	}
+	if nrecv > 0 {
+		i := strings.IndexByte(name, '.')
+		name = name[:i] + "[" + strings.Join(args[:nrecv], ", ") + "]" + name[i:]
+		if nrecv == len(args) {
+			return name
+		}
+	}
+	return name + "[" + strings.Join(args[nrecv:], ", ") + "]"
+}
+
+// dictDumpMatch reports whether query, the value of -d=dictionary,
+// selects the generic function or method name of package pkg. Query
+// is name, as in F or T.M, optionally qualified by the name or import
+// path of the package, as in pkg.F.
+func dictDumpMatch(pkg *types.Pkg, name, query string) bool {
+	if query == name {
+		return true
+	}
+	if !strings.HasSuffix(query, "."+name) {
+		return false
+	}
+	path := pkg.Path
+	if pkg == types.LocalPkg {
+		path = base.Ctxt.Pkgpath
+	}
+	qual := strings.TrimSuffix(query, "."+name)
+	return qual == pkg.Name || qual == path
+}
+
+// add adds an entry, described by format and args, to d, if d is not
+// nil. Entries are added in the order of the dictionary.
+func (d *dictDump) add(format string, args ...interface{}) {
+	if d != nil {
+		d.entries = append(d.entries, fmt.Sprintf(format, args...))
+	}
+}
+
+// print prints the layout of the dictionary of d, if d is not nil, as
+// in
+//
+//	x.go:8:6: dictionary .dict.G["".T] of G[T]
+//		0: type argument X = T
+//		1: derived type *go.shape.int_0 = *T
+//		2: itab T, I
+func (d *dictDump) print() {
+	if d == nil {
+		return
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v: dictionary %s of %s\n", base.FmtPos(d.gf.Pos()), d.sym.Name, d.name)
+	for i, e := range d.entries {
+		fmt.Fprintf(&buf, "\t%d: %s\n", i, e)
+	}
+	os.Stdout.Write(buf.Bytes())
+}
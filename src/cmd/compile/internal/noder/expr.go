@@ -211,6 +211,11 @@ func (g *irgen) substType(typ *types.Type, tparams *types.Type, targs []ir.Node)
 // than in typecheck.go.
 func (g *irgen) selectorExpr(pos src.XPos, typ types2.Type, expr *syntax.SelectorExpr) ir.Node {
 	x := g.expr(expr.X)
+	if selinfo, ok := g.info.Selections[expr]; ok {
+		if fn, ok := selinfo.Obj().(*types2.Func); ok && fn.Type().(*types2.Signature).TypeParams().Len() > 0 {
+			return g.genericMethod(pos, x, expr, selinfo)
+		}
+	}
 	if x.Type().HasTParam() {
 		// Leave a method call on a type param as an OXDOT, since it can
 		// only be fully transformed once it has an instantiated type.
@@ -308,6 +313,61 @@ func (g *irgen) selectorExpr(pos src.XPos, typ types2.Type, expr *syntax.Selecto
 	return n
 }
 
+// genericMethod returns the node for the selection x.M of a method M with
+// type parameters of its own (see types.Field.IsGenericMethod). Like a
+// method of a generic type, it is an OFUNCINST of an OMETHVALUE whose
+// Selection is the generic method, with the type arguments of the
+// receiver type followed by those of the method, so that stenciling
+// implements it by an instantiated function taking the receiver as its
+// first argument. Since generic methods are not in the method set, they
+// can't be selected as method expressions.
+func (g *irgen) genericMethod(pos src.XPos, x ir.Node, expr *syntax.SelectorExpr, selinfo *types2.Selection) ir.Node {
+	if selinfo.Kind() == types2.MethodExpr {
+		base.ErrorfAt(pos, 0, "method expression %s of method with type parameters not supported", syntax.String(expr))
+		base.ErrorExit()
+	}
+	index := selinfo.Index()
+	embeds, last := index[:len(index)-1], index[len(index)-1]
+	for _, ix := range embeds {
+		x = Implicit(DotField(pos, x, ix))
+	}
+
+	method2 := selinfo.Obj().(*types2.Func)
+	recvType2 := method2.Type().(*types2.Signature).Recv().Type()
+	_, wantPtr := recvType2.(*types2.Pointer)
+	if havePtr := x.Type().IsPtr(); havePtr != wantPtr {
+		if havePtr {
+			x = Implicit(Deref(pos, x.Type().Elem(), x))
+		} else {
+			x = Implicit(Addr(pos, x))
+		}
+	}
+
+	// As for methods of generic types, select the method of the generic
+	// receiver type, which is the one with a body.
+	recvObj := types2.AsNamed(deref2(recvType2)).Obj()
+	recvType := g.pkg(recvObj.Pkg()).Lookup(recvObj.Name()).Def.(*ir.Name).Type()
+	method := recvType.Methods().Index(last).Nname.(*ir.Name)
+	n := ir.NewSelectorExpr(pos, ir.OMETHVALUE, x, typecheck.Lookup(expr.Sel.Value))
+	n.Selection = types.NewField(pos, method.Sym(), method.Type())
+	n.Selection.Nname = method
+	typed(method.Type(), n)
+
+	inst, ok := g.info.Instances[expr.Sel]
+	if !ok {
+		base.FatalfAt(pos, "missing instance for generic method %v", expr)
+	}
+	rparams := deref(x.Type()).RParams()
+	targs := make([]ir.Node, 0, len(rparams)+inst.TypeArgs.Len())
+	for _, t := range rparams {
+		targs = append(targs, ir.TypeNode(t))
+	}
+	for i := 0; i < inst.TypeArgs.Len(); i++ {
+		targs = append(targs, ir.TypeNode(g.typ(inst.TypeArgs.At(i))))
+	}
+	return typed(g.typ(inst.Type), ir.NewInstExpr(pos, ir.OFUNCINST, n, targs))
+}
+
 func (g *irgen) exprList(expr syntax.Expr) []ir.Node {
 	return g.exprs(unpackListExpr(expr))
 }
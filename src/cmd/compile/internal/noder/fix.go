@@ -0,0 +1,257 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types"
+	"cmd/compile/internal/types2"
+)
+
+// suggestFixes returns fixes for the type checking error terr in one
+// of files, for base.ErrorDetails. It is called while the type checker
+// checks pkg, and uses what info records so far. It goes by the code
+// and details of terr, only knows a few kinds of errors, and only
+// suggests a fix when the syntax and types make the fix unambiguous.
+func suggestFixes(m *posMap, files []*syntax.File, pkg *types2.Package, info *types2.Info, terr types2.Error) []base.SuggestedFix {
+	if !base.Flag.JSONDiag && base.Flag.SARIF == "" || !terr.Pos.IsKnown() {
+		return nil // only -jsondiag and -sarif show fixes
+	}
+	path := pathTo(fileOf(files, terr.Pos), terr.Pos)
+	if len(path) == 0 {
+		return nil
+	}
+
+	var fix *fixer
+	switch d := terr.Details; {
+	case terr.Code == errcode.UnusedImport:
+		fix = fixUnusedImport(path)
+	case terr.Code == errcode.UnusedVar:
+		fix = fixUnusedVar(path)
+	case d != nil && d.Operand != nil:
+		fix = fixCannotUse(m, pkg, info, d)
+	case d != nil && len(d.Suggestions) == 1:
+		fix = fixDidYouMean(path, d.Suggestions[0])
+	}
+	if fix == nil {
+		return nil
+	}
+	sf := base.SuggestedFix{Message: fix.msg}
+	for _, e := range fix.edits {
+		sf.Edits = append(sf.Edits, base.TextEdit{Pos: m.makeXPos(e.pos), End: m.makeXPos(e.end), NewText: e.text})
+	}
+	return []base.SuggestedFix{sf}
+}
+
+// A fixer is a fix in terms of syntax positions.
+type fixer struct {
+	msg   string
+	edits []edit
+}
+
+type edit struct {
+	pos, end syntax.Pos
+	text     string
+}
+
+func (f *fixer) replace(pos, end syntax.Pos, text string) *fixer {
+	f.edits = append(f.edits, edit{pos, end, text})
+	return f
+}
+
+func (f *fixer) insert(pos syntax.Pos, text string) *fixer {
+	return f.replace(pos, pos, text)
+}
+
+func (f *fixer) withMsg(msg string) *fixer {
+	f.msg = msg
+	return f
+}
+
+// fileBase returns the base of the file containing pos, looking
+// through line directives.
+func fileBase(pos syntax.Pos) *syntax.PosBase {
+	b := pos.Base()
+	for b != nil && !b.IsFileBase() {
+		b = b.Pos().Base()
+	}
+	return b
+}
+
+// fileOf returns the one of files that contains pos, or nil.
+func fileOf(files []*syntax.File, pos syntax.Pos) *syntax.File {
+	for _, f := range files {
+		if fileBase(f.Pos()) == fileBase(pos) {
+			return f
+		}
+	}
+	return nil
+}
+
+// pathTo returns the nodes enclosing the innermost node of file that
+// starts at pos, outermost first and ending with that node. It
+// returns nil if file is nil or no node starts at pos.
+func pathTo(file *syntax.File, pos syntax.Pos) []syntax.Node {
+	if file == nil {
+		return nil
+	}
+	var stack, path []syntax.Node
+	syntax.Inspect(file, func(n syntax.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		if syntax.StartPos(n) == pos {
+			// Nodes starting at pos nest, so the last one wins.
+			path = append(path[:0], stack...)
+		}
+		return true
+	})
+	return path
+}
+
+// shiftCol returns the position n columns to the right of pos.
+func shiftCol(pos syntax.Pos, n uint) syntax.Pos {
+	return syntax.MakePos(pos.Base(), pos.Line(), pos.Col()+n)
+}
+
+// fixUnusedImport removes an unused import from a parenthesized
+// import declaration. An unparenthesized one would leave the import
+// keyword behind, so it gets no fix.
+func fixUnusedImport(path []syntax.Node) *fixer {
+	d, ok := path[len(path)-1].(*syntax.ImportDecl)
+	if !ok {
+		// The innermost node is the local name or the path.
+		if len(path) < 2 {
+			return nil
+		}
+		if d, ok = path[len(path)-2].(*syntax.ImportDecl); !ok {
+			return nil
+		}
+	}
+	if d.Group == nil || d.Path == nil {
+		return nil
+	}
+	return new(fixer).replace(syntax.StartPos(d), syntax.EndPos(d.Path), "").withMsg("remove the import")
+}
+
+// fixUnusedVar removes the declaration of an unused variable where
+// that leaves the statement declaring it intact: in a short variable
+// declaration of a single variable, a range clause or a type switch
+// guard.
+func fixUnusedVar(path []syntax.Node) *fixer {
+	name, ok := path[len(path)-1].(*syntax.Name)
+	if !ok || len(path) < 2 {
+		return nil
+	}
+	parent := path[len(path)-2]
+	if l, ok := parent.(*syntax.ListExpr); ok && len(path) >= 3 {
+		if r, ok := path[len(path)-3].(*syntax.RangeClause); ok && r.Def && len(l.ElemList) == 2 {
+			key, value := l.ElemList[0], l.ElemList[1]
+			if name == key {
+				return new(fixer).replace(name.Pos(), syntax.EndPos(name), "_").withMsg("replace " + name.Value + " with _")
+			}
+			if name == value {
+				return new(fixer).replace(syntax.EndPos(key), r.Pos(), " := ").withMsg("remove " + name.Value)
+			}
+		}
+		return nil
+	}
+	switch s := parent.(type) {
+	case *syntax.AssignStmt:
+		// s.Pos() is the position of the := operator.
+		if s.Op == syntax.Def && s.Lhs == name {
+			return new(fixer).replace(name.Pos(), shiftCol(s.Pos(), uint(len(":="))), "_ =").withMsg("assign to _ instead of declaring " + name.Value)
+		}
+	case *syntax.RangeClause:
+		// s.Pos() is the position of the range keyword.
+		if s.Def && s.Lhs == name {
+			return new(fixer).replace(name.Pos(), s.Pos(), "").withMsg("remove " + name.Value)
+		}
+	case *syntax.TypeSwitchGuard:
+		if s.Lhs == name {
+			return new(fixer).replace(name.Pos(), syntax.StartPos(s.X), "").withMsg("remove " + name.Value)
+		}
+	}
+	return nil
+}
+
+// fixDidYouMean replaces an undefined name with the only name the type
+// checker suggests instead.
+func fixDidYouMean(path []syntax.Node, s string) *fixer {
+	name, ok := path[len(path)-1].(*syntax.Name)
+	if !ok {
+		return nil
+	}
+	return new(fixer).replace(name.Pos(), syntax.EndPos(name), s).withMsg("replace " + name.Value + " with " + s)
+}
+
+// fixCannotUse fixes an operand that is not assignable to the type it
+// is used as, as d describes it: a numeric one by converting it, and a
+// variable whose type implements the interface it is used as only
+// through pointer receivers by taking its address.
+func fixCannotUse(m *posMap, pkg *types2.Package, info *types2.Info, d *types2.ErrorDetails) *fixer {
+	x := d.Operand
+	if len(d.Types) != 2 || !isNameOrSelector(x) {
+		return nil
+	}
+	from, to := d.Types[0], d.Types[1]
+	text := syntax.String(x)
+
+	if numericKind(from) != 0 && numericKind(from) == numericKind(to) {
+		// The file refers to the packages by the names that
+		// FormatMessage uses, unless it uses paths to tell apart
+		// packages of the same name.
+		target := types.FormatMessage(m.makeXPos(x.Pos()), "%s", typeString(pkg, to))
+		if strings.Contains(target, `"`) {
+			return nil
+		}
+		return new(fixer).insert(syntax.StartPos(x), target+"(").insert(syntax.EndPos(x), ")").withMsg("convert " + text + " to " + target)
+	}
+	iface, _ := to.Underlying().(*types2.Interface)
+	if _, ok := from.Underlying().(*types2.Pointer); ok || iface == nil || types2.IsInterface(from) {
+		return nil
+	}
+	if info.Types[x].Addressable() && types2.Implements(types2.NewPointer(from), iface) && !types2.Implements(from, iface) {
+		return new(fixer).insert(syntax.StartPos(x), "&").withMsg("use &" + text)
+	}
+	return nil
+}
+
+// isNameOrSelector reports whether x is a name, possibly qualified by
+// further names, as in a.b.c. EndPos is exact for such expressions.
+func isNameOrSelector(x syntax.Expr) bool {
+	for {
+		switch e := x.(type) {
+		case *syntax.Name:
+			return true
+		case *syntax.SelectorExpr:
+			x = e.X
+		default:
+			return false
+		}
+	}
+}
+
+// numericKind returns 1 for real number types, 2 for complex ones
+// and 0 for other types. Values of types of the same kind convert to
+// each other.
+func numericKind(t types2.Type) int {
+	b, ok := t.Underlying().(*types2.Basic)
+	switch {
+	case !ok:
+		return 0
+	case b.Info()&types2.IsComplex != 0:
+		return 2
+	case b.Info()&types2.IsNumeric != 0:
+		return 1
+	}
+	return 0
+}
@@ -480,9 +480,9 @@ func pkgnotused(lineno src.XPos, path string, name string) {
 		elem = elem[i+1:]
 	}
 	if name == "" || elem == name {
-		base.ErrorfAt(lineno, "imported and not used: %q", path)
+		base.MsgImportedNotUsed.ErrorAt(lineno, base.MsgArgs{"path": strconv.Quote(path)})
 	} else {
-		base.ErrorfAt(lineno, "imported and not used: %q as %s", path, name)
+		base.MsgImportedNotUsedAs.ErrorAt(lineno, base.MsgArgs{"path": strconv.Quote(path), "name": name})
 	}
 }
 
@@ -507,7 +507,7 @@ func clearImports() {
 	}
 	var unused []importedPkg
 
-	for _, s := range types.LocalPkg.Syms {
+	for _, s := range types.LocalPkg.SortedSyms() {
 		n := ir.AsNode(s.Def)
 		if n == nil {
 			continue
@@ -544,7 +544,7 @@ func clearImports() {
 func CheckDotImports() {
 	for _, pack := range dotImports {
 		if !pack.Used {
-			base.ErrorfAt(pack.Pos(), "imported and not used: %q", pack.Pkg.Path)
+			base.MsgImportedNotUsed.ErrorAt(pack.Pos(), base.MsgArgs{"path": strconv.Quote(pack.Pkg.Path)})
 		}
 	}
 
@@ -564,7 +564,7 @@ func importDot(pack *ir.PkgName) {
 	}
 
 	opkg := pack.Pkg
-	for _, s := range opkg.Syms {
+	for _, s := range opkg.SortedSyms() {
 		if s.Def == nil {
 			if _, ok := typecheck.DeclImporter[s]; !ok {
 				continue
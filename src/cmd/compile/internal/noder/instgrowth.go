@@ -0,0 +1,99 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/reflectdata"
+	"cmd/compile/internal/types"
+	"cmd/internal/obj"
+)
+
+// growthInfo records the artifacts generated for the instantiations of
+// a single generic function or type.
+type growthInfo struct {
+	funcs []*ir.Func    // shape-based instantiations
+	dicts []*obj.LSym   // dictionaries
+	types []*types.Type // fully-instantiated types
+}
+
+// growthFor returns the growthInfo for the generic function or type
+// named by orig, or nil if -d=instgrowth is not enabled.
+func (g *genInst) growthFor(orig *types.Sym) *growthInfo {
+	if base.Debug.InstGrowth == 0 || orig == nil {
+		return nil
+	}
+	if g.growth == nil {
+		g.growth = make(map[*types.Sym]*growthInfo)
+	}
+	gi := g.growth[orig]
+	if gi == nil {
+		gi = new(growthInfo)
+		g.growth[orig] = gi
+	}
+	return gi
+}
+
+// recordGrowth returns the growthInfo to which artifacts generated for
+// an instantiation of the generic function or method nameNode should
+// be attributed. Methods are attributed to their generic receiver type.
+func (g *genInst) recordGrowth(nameNode *ir.Name, isMeth bool) *growthInfo {
+	orig := nameNode.Sym()
+	if isMeth {
+		if recv := nameNode.Type().Recv(); recv != nil && deref(recv.Type).OrigSym() != nil {
+			orig = deref(recv.Type).OrigSym()
+		}
+	}
+	return g.growthFor(orig)
+}
+
+// DumpInstGrowth prints a table attributing the size of the code,
+// dictionaries and runtime type descriptors emitted for generic
+// instantiations to the generic functions and types they came from,
+// largest first. It must be called after all functions have been
+// compiled and all data has been dumped.
+func DumpInstGrowth() {
+	type row struct {
+		orig                      *types.Sym
+		insts                     int
+		code, dicts, descs, total int64
+	}
+	var rows []row
+	for orig, gi := range geninst.growth {
+		r := row{orig: orig, insts: len(gi.funcs) + len(gi.types)}
+		for _, fn := range gi.funcs {
+			if fn.LSym != nil {
+				r.code += fn.LSym.Size
+			}
+		}
+		for _, lsym := range gi.dicts {
+			r.dicts += lsym.Size
+		}
+		for _, t := range gi.types {
+			r.descs += reflectdata.TypeLinksym(t).Size
+		}
+		r.total = r.code + r.dicts + r.descs
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].total != rows[j].total {
+			return rows[i].total > rows[j].total
+		}
+		return rows[i].orig.Less(rows[j].orig)
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "generic\tinsts\tcode\tdicts\ttypes\ttotal\t\n")
+	for _, r := range rows {
+		fmt.Fprintf(w, "%v\t%d\t%d\t%d\t%d\t%d\t\n", r.orig, r.insts, r.code, r.dicts, r.descs, r.total)
+	}
+	w.Flush()
+}
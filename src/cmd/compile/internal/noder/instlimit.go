@@ -0,0 +1,70 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"fmt"
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+// An instLink is an instantiation of a generic function or method in
+// the chain of instantiations being created (see genInst.chain).
+type instLink struct {
+	name string   // as in F[int] or T[int].M
+	site src.XPos // reference that caused it, if known
+}
+
+// maxChainLinks is the number of links of a long instantiation chain
+// shown by an instantiation limit error: half from each end.
+const maxChainLinks = 10
+
+// enterInst records that the instantiation with targs of the generic
+// function or method gf is being created, because of the reference at
+// g.instSite. Since creating an instantiation creates those of the
+// functions and methods it refers to, the instantiations being created
+// form a chain from a reference in non-generic code. If the package
+// has now created more than -instlimit instantiations, enterInst
+// reports an error showing that chain and exits. Each call of
+// enterInst must be paired with a call of leaveInst.
+func (g *genInst) enterInst(gf *ir.Name, targs []*types.Type, isMeth bool) {
+	name, _, nrecv := genericName(gf, isMeth)
+	if name == "" {
+		name, nrecv = gf.Sym().Name, len(targs)
+	}
+	g.chain = append(g.chain, instLink{instName(name, targs, nrecv), g.instSite})
+	g.ninsts++
+	if base.Flag.InstLimit == 0 || g.ninsts <= base.Flag.InstLimit {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "too many instantiations of generic functions and methods (more than %d; see -instlimit), while instantiating:", base.Flag.InstLimit)
+	for i, l := range g.chain {
+		if n := len(g.chain); n > maxChainLinks && i >= maxChainLinks/2 && i < n-maxChainLinks/2 {
+			if i == maxChainLinks/2 {
+				fmt.Fprintf(&b, "\n\t... %d more", n-maxChainLinks)
+			}
+			continue
+		}
+		b.WriteString("\n\t")
+		if l.site.IsKnown() {
+			fmt.Fprintf(&b, "%v: ", base.FmtPos(l.site))
+		}
+		b.WriteString(l.name)
+	}
+	base.ErrorfAt(g.chain[0].site, 0, "%s", b.String())
+	base.ErrorExit()
+}
+
+// leaveInst records that the instantiation most recently entered by
+// enterInst has been created.
+func (g *genInst) leaveInst() {
+	g.chain = g.chain[:len(g.chain)-1]
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/reflectdata"
+	"cmd/compile/internal/types"
+	"cmd/internal/obj"
+	"cmd/internal/src"
+)
+
+// An instRecord records an instantiation of a generic function, method
+// or type created during this compilation, for -d=instantiations.
+type instRecord struct {
+	kind string   // "func", "method" or "type"
+	name string   // as in F[int], T[int].M or T[int]
+	site src.XPos // reference that caused it, if known
+
+	// For functions and methods, the shape-based instantiation that
+	// implements it and its dictionary; for types, the type.
+	fun  *ir.Func
+	dict *obj.LSym
+	typ  *types.Type
+}
+
+// recordInstFunc records the instantiation with targs of the generic
+// function or method gf, implemented by fun with the dictionary dict.
+func (g *genInst) recordInstFunc(gf *ir.Name, targs []*types.Type, isMeth bool, fun *ir.Func, dict *obj.LSym) {
+	if base.Debug.Instantiations == 0 {
+		return
+	}
+	name, _, nrecv := genericName(gf, isMeth)
+	if name == "" {
+		name, nrecv = gf.Sym().Name, len(targs)
+	}
+	kind := "func"
+	if isMeth {
+		kind = "method"
+	}
+	g.insts = append(g.insts, &instRecord{
+		kind: kind,
+		name: instName(name, targs, nrecv),
+		site: g.instSite,
+		fun:  fun,
+		dict: dict,
+	})
+}
+
+// recordInstType records the fully-instantiated type t.
+func (g *genInst) recordInstType(t *types.Type) {
+	if base.Debug.Instantiations == 0 {
+		return
+	}
+	g.insts = append(g.insts, &instRecord{
+		kind: "type",
+		name: t.String(),
+		typ:  t,
+	})
+}
+
+// An instReport is the description of an instantiation that
+// -d=instantiations=2 prints as a JSON object.
+type instReport struct {
+	Site     string `json:"site,omitempty"`
+	Kind     string `json:"kind"`
+	Instance string `json:"instance"`
+	Shape    string `json:"shape,omitempty"`
+	Code     int64  `json:"code"`
+	Shared   bool   `json:"shared,omitempty"`
+	Data     int64  `json:"data"`
+
+	// TypeID identifies an instantiated type the same way in every
+	// compilation (see types.StableLinkString), so that the reports
+	// of several packages can be merged.
+	TypeID string `json:"typeid,omitempty"`
+}
+
+// DumpInstantiations prints the instantiations of generic functions,
+// methods and types created during this compilation, in the order they
+// were created: for each, the reference that caused it, the
+// shape-based instantiation that implements it, and the size of its
+// code and data. Instantiations with the same shape share their code,
+// which is attributed to the first of them. The data is the dictionary
+// of a function or method, or the runtime type descriptor of a type.
+// With -d=instantiations=2, it prints a JSON object for each instead of
+// a table. It must be called after all functions have been compiled
+// and all data has been dumped.
+func DumpInstantiations() {
+	var reports []instReport
+	compiled := make(map[*ir.Func]bool)
+	for _, r := range geninst.insts {
+		rep := instReport{Kind: r.kind, Instance: r.name}
+		if r.site.IsKnown() {
+			rep.Site = base.FmtPos(r.site)
+		}
+		if r.fun != nil {
+			rep.Shape = r.fun.Sym().Name
+			if compiled[r.fun] {
+				rep.Shared = true
+			} else if r.fun.LSym != nil {
+				rep.Code = r.fun.LSym.Size
+				compiled[r.fun] = true
+			}
+			rep.Data = r.dict.Size
+		} else {
+			rep.Data = reflectdata.TypeLinksym(r.typ).Size
+			rep.TypeID = r.typ.StableLinkString()
+		}
+		reports = append(reports, rep)
+	}
+
+	if base.Debug.Instantiations == 2 {
+		enc := json.NewEncoder(os.Stdout)
+		for _, rep := range reports {
+			enc.Encode(rep)
+		}
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', 0)
+	fmt.Fprintf(w, "site\tkind\tinstance\tshape\tcode\tdata\n")
+	for _, rep := range reports {
+		site, shape, code := rep.Site, rep.Shape, fmt.Sprint(rep.Code)
+		if site == "" {
+			site = "-"
+		}
+		if shape == "" {
+			shape, code = "-", "-"
+		} else if rep.Shared {
+			code = "shared"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\n", site, rep.Kind, rep.Instance, shape, code, rep.Data)
+	}
+	w.Flush()
+}
@@ -6,10 +6,12 @@ package noder
 
 import (
 	"fmt"
+	"internal/buildcfg"
 	"os"
 
 	"cmd/compile/internal/base"
 	"cmd/compile/internal/dwarfgen"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/typecheck"
@@ -18,6 +20,67 @@ import (
 	"cmd/internal/src"
 )
 
+// msgLines returns the templates of the lines of the message of terr,
+// for translations; see base.RegisterTranslation.
+func msgLines(terr types2.Error) []base.MsgLine {
+	if terr.Details == nil {
+		return nil
+	}
+	var lines []base.MsgLine
+	for _, l := range terr.Details.Lines {
+		lines = append(lines, base.MsgLine{ID: l.ID, Args: l.Args})
+	}
+	return lines
+}
+
+// qualifyMessages returns msg, the message of an error, with package
+// names qualified as the compiler's own messages do, and qualifies
+// them alike in the notes of details and in the values of the template
+// parameters of its lines; see types.FormatMessages.
+func qualifyMessages(pos src.XPos, msg string, details *base.ErrorDetails) string {
+	msgs := []string{msg}
+	for _, r := range details.Related {
+		msgs = append(msgs, r.Msg)
+	}
+	type param struct {
+		args map[string]string
+		name string
+	}
+	var params []param
+	for _, l := range details.Lines {
+		for name, v := range l.Args {
+			params = append(params, param{l.Args, name})
+			msgs = append(msgs, v)
+		}
+	}
+	msgs = types.FormatMessages(pos, msgs...)
+	for i := range details.Related {
+		details.Related[i].Msg = msgs[1+i]
+	}
+	for i, p := range params {
+		p.args[p.name] = msgs[1+len(details.Related)+i]
+	}
+	return msgs[0]
+}
+
+// errorQualifier qualifies the packages in the messages of the type
+// checker so that types.FormatMessage qualifies them as it does in the
+// compiler's own messages.
+func errorQualifier(pkg *types2.Package) string {
+	return types.NewPkg(pkg.Path(), pkg.Name()).Qualifier()
+}
+
+// typeString returns t as the messages of the type checker about pkg
+// spell it, for types.FormatMessage.
+func typeString(pkg *types2.Package, t types2.Type) string {
+	return types2.TypeString(t, func(p *types2.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return errorQualifier(p)
+	})
+}
+
 // checkFiles configures and runs the types2 checker on the given
 // parsed source files and then returns the result.
 func checkFiles(noders []*noder) (posMap, *types2.Package, *types2.Info) {
@@ -34,6 +97,17 @@ func checkFiles(noders []*noder) (posMap, *types2.Package, *types2.Info) {
 	}
 
 	// typechecking
+	pkg := types2.NewPackage(base.Ctxt.Pkgpath, "")
+	info := &types2.Info{
+		Types:      make(map[syntax.Expr]types2.TypeAndValue),
+		Defs:       make(map[*syntax.Name]types2.Object),
+		Uses:       make(map[*syntax.Name]types2.Object),
+		Selections: make(map[*syntax.SelectorExpr]*types2.Selection),
+		Implicits:  make(map[syntax.Node]types2.Object),
+		Scopes:     make(map[syntax.Node]*types2.Scope),
+		Instances:  make(map[*syntax.Name]types2.Instance),
+		// expand as needed
+	}
 	ctxt := types2.NewContext()
 	importer := gcimports{
 		ctxt:     ctxt,
@@ -44,25 +118,38 @@ func checkFiles(noders []*noder) (posMap, *types2.Package, *types2.Info) {
 		GoVersion:             base.Flag.Lang,
 		IgnoreLabels:          true, // parser already checked via syntax.CheckBranches mode
 		CompilerErrorMessages: true, // use error strings matching existing compiler errors
+		MethodTypeParams:      buildcfg.Experiment.GenericMethods,
+		ErrorQualifier:        errorQualifier,
 		Error: func(err error) {
 			terr := err.(types2.Error)
-			base.ErrorfAt(m.makeXPos(terr.Pos), "%s", terr.Msg)
+			pos := m.makeXPos(terr.Pos)
+			if terr.Code == errcode.CascadingErrors {
+				// A count of the errors left out, not an error.
+				base.NotefAt(pos, terr.Code, "%s", terr.Msg)
+				return
+			}
+			details := base.ErrorDetails{
+				End:     errorEnd(&m, files, terr),
+				Related: relatedPositions(&m, pkg, terr),
+				Fixes:   suggestFixes(&m, files, pkg, info, terr),
+				Lines:   msgLines(terr),
+			}
+			msg := qualifyMessages(pos, terr.Msg, &details)
+			base.ErrorfAtDetails(pos, terr.Code, details, "%s", msg)
 		},
 		Importer: &importer,
 		Sizes:    &gcSizes{},
 	}
-	info := &types2.Info{
-		Types:      make(map[syntax.Expr]types2.TypeAndValue),
-		Defs:       make(map[*syntax.Name]types2.Object),
-		Uses:       make(map[*syntax.Name]types2.Object),
-		Selections: make(map[*syntax.SelectorExpr]*types2.Selection),
-		Implicits:  make(map[syntax.Node]types2.Object),
-		Scopes:     make(map[syntax.Node]*types2.Scope),
-		Instances:  make(map[*syntax.Name]types2.Instance),
-		// expand as needed
+	if base.Debug.InferenceTrace != 0 {
+		conf.InferenceTrace = func(pos syntax.Pos, msg string) {
+			xpos := m.makeXPos(pos)
+			fmt.Printf("%v: %s\n", base.FmtPos(xpos), types.FormatMessage(xpos, "%s", msg))
+		}
+	}
+	err := types2.NewChecker(&conf, pkg, info).Files(files)
+	if base.WarnConversion.Enabled() {
+		warnConversions(&m, files, pkg, info)
 	}
-
-	pkg, err := conf.Check(base.Ctxt.Pkgpath, files, info)
 
 	base.ExitIfErrors()
 	if err != nil {
@@ -185,6 +272,26 @@ type genInst struct {
 
 	// New instantiations created during this round of buildInstantiations().
 	newInsts []ir.Node
+
+	// Map from generic functions and types to the type argument lists
+	// they were instantiated with during this compilation, for -d=tparams.
+	instances map[*types.Sym][][]*types.Type
+
+	// Map from generic functions and types to the artifacts generated
+	// for their instantiations, for -d=instgrowth.
+	growth map[*types.Sym]*growthInfo
+
+	// Instantiations created during this compilation, and the position
+	// of the reference for which they are being created, for
+	// -d=instantiations.
+	insts    []*instRecord
+	instSite src.XPos
+
+	// The number of instantiations of generic functions and methods
+	// created during this compilation, and the chain of those being
+	// created, for -instlimit.
+	ninsts int
+	chain  []instLink
 }
 
 func (g *irgen) later(fn func()) {
@@ -197,6 +304,7 @@ type delayInfo struct {
 	sym    *types.Sym
 	off    int
 	isMeth bool
+	dump   *dictDump // for -d=dictionary
 }
 
 type typeDelayInfo struct {
@@ -303,7 +411,7 @@ Outer:
 		for _, n := range g.target.Decls {
 			if fn, ok := n.(*ir.Func); ok {
 				if fn.Body == nil && fn.Nname.Sym().Linkname == "" {
-					base.ErrorfAt(fn.Pos(), "missing function body")
+					base.ErrorfAt(fn.Pos(), errcode.MissingFuncBody, "missing function body")
 				}
 			}
 		}
@@ -9,6 +9,8 @@ import (
 	"internal/buildcfg"
 	"strings"
 
+	"cmd/compile/internal/errcode"
+	"cmd/compile/internal/inline"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/syntax"
 )
@@ -31,14 +33,64 @@ const (
 		ir.RegisterParams | // TODO(register args) remove after register abi is working
 		ir.CgoUnsafeArgs |
 		ir.UintptrEscapes |
+		ir.Stencil |
+		ir.Cold |
+		ir.InlineBudget |
 		ir.Systemstack |
 		ir.Nowritebarrier |
 		ir.Nowritebarrierrec |
 		ir.Yeswritebarrierrec
 
 	typePragmas = ir.NotInHeap
+
+	ifPragmas = ir.Likely | ir.Unlikely
+
+	forPragmas = ir.Unroll
 )
 
+// unrollFactor returns the factor of the //go:unroll directive in the
+// pragma of a for statement, or 0 if there is none.
+func unrollFactor(pragma syntax.Pragma) int {
+	if p, ok := pragma.(*pragmas); ok && p.Flag&ir.Unroll != 0 {
+		return p.Unroll
+	}
+	return 0
+}
+
+// inlineBudget returns the budget of the //go:inlinebudget directive
+// in the pragma of a function declaration, or 0 if there is none.
+func inlineBudget(pragma syntax.Pragma) int32 {
+	if p, ok := pragma.(*pragmas); ok && p.Flag&ir.InlineBudget != 0 {
+		return p.InlineBudget
+	}
+	return 0
+}
+
+// checkInlineBudget returns an error message if the budget of the
+// //go:inlinebudget directive of a function is too large, or its
+// pragma flags conflict with the directive.
+func checkInlineBudget(pragma ir.PragmaFlag, budget int32) string {
+	switch {
+	case pragma&ir.InlineBudget == 0:
+	case budget > inline.MaxBudget:
+		return fmt.Sprintf("//go:inlinebudget %d exceeds the maximum of %d", budget, inline.MaxBudget)
+	case pragma&(ir.Noinline|ir.Cold) != 0:
+		return "go:inlinebudget cannot be combined with go:noinline or go:cold"
+	}
+	return ""
+}
+
+// setIfLikely sets the branch likelihood of n from its pragma flags,
+// and returns an error message if they conflict.
+func setIfLikely(n *ir.IfStmt, pragma ir.PragmaFlag) string {
+	if pragma&ifPragmas == ifPragmas {
+		return "if statement cannot be both //go:likely and //go:unlikely"
+	}
+	n.Likely = pragma&ir.Likely != 0
+	n.Unlikely = pragma&ir.Unlikely != 0
+	return ""
+}
+
 func pragmaFlag(verb string) ir.PragmaFlag {
 	switch verb {
 	case "go:build":
@@ -80,6 +132,27 @@ func pragmaFlag(verb string) ir.PragmaFlag {
 		// in the argument list.
 		// Used in syscall/dll_windows.go.
 		return ir.UintptrEscapes
+	case "go:stencil":
+		// Instantiate the next function declared in the file, a
+		// generic function or method, separately for each type
+		// argument, rather than share an instantiation between
+		// type arguments of the same underlying type. This costs
+		// code size, but may speed up hot generic code.
+		return ir.Stencil
+	case "go:cold":
+		// The next function declared in the file is rarely
+		// called, as for error formatting. It is not inlined,
+		// calls to it are unlikely, and its code is placed with
+		// other cold code, away from the hot code.
+		return ir.Cold
+	case "go:likely":
+		// The condition of the next if statement is usually
+		// true, so the code layout favors its then branch.
+		return ir.Likely
+	case "go:unlikely":
+		// The condition of the next if statement is usually
+		// false, as for error paths and assertion failures.
+		return ir.Unlikely
 	case "go:registerparams": // TODO(register args) remove after register abi is working
 		return ir.RegisterParams
 	case "go:notinheap":
@@ -101,7 +174,7 @@ func (p *noder) pragcgo(pos syntax.Pos, text string) {
 		case len(f) == 2 && !isQuoted(f[1]):
 		case len(f) == 3 && !isQuoted(f[1]) && !isQuoted(f[2]):
 		default:
-			p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf(`usage: //go:%s local [remote]`, verb)})
+			p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf(`usage: //go:%s local [remote]`, verb), Code: errcode.InvalidDirective})
 			return
 		}
 	case "cgo_import_dynamic":
@@ -115,19 +188,19 @@ func (p *noder) pragcgo(pos syntax.Pos, text string) {
 				// or "lib.a/libname.so.X"
 				n := strings.Split(f[3], "/")
 				if len(n) != 2 || !strings.HasSuffix(n[0], ".a") || (!strings.HasSuffix(n[1], ".o") && !strings.Contains(n[1], ".so.")) {
-					p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_import_dynamic local [remote ["lib.a/object.o"]]`})
+					p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_import_dynamic local [remote ["lib.a/object.o"]]`, Code: errcode.InvalidDirective})
 					return
 				}
 			}
 		default:
-			p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_import_dynamic local [remote ["library"]]`})
+			p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_import_dynamic local [remote ["library"]]`, Code: errcode.InvalidDirective})
 			return
 		}
 	case "cgo_import_static":
 		switch {
 		case len(f) == 2 && !isQuoted(f[1]):
 		default:
-			p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_import_static local`})
+			p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_import_static local`, Code: errcode.InvalidDirective})
 			return
 		}
 	case "cgo_dynamic_linker":
@@ -135,7 +208,7 @@ func (p *noder) pragcgo(pos syntax.Pos, text string) {
 		case len(f) == 2 && isQuoted(f[1]):
 			f[1] = strings.Trim(f[1], `"`)
 		default:
-			p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_dynamic_linker "path"`})
+			p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_dynamic_linker "path"`, Code: errcode.InvalidDirective})
 			return
 		}
 	case "cgo_ldflag":
@@ -143,7 +216,7 @@ func (p *noder) pragcgo(pos syntax.Pos, text string) {
 		case len(f) == 2 && isQuoted(f[1]):
 			f[1] = strings.Trim(f[1], `"`)
 		default:
-			p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_ldflag "arg"`})
+			p.error(syntax.Error{Pos: pos, Msg: `usage: //go:cgo_ldflag "arg"`, Code: errcode.InvalidDirective})
 			return
 		}
 	default:
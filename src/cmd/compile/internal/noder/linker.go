@@ -195,6 +195,7 @@ func (l *linker) relocFuncExt(w *encoder, name *ir.Name) {
 	w.sync(syncFuncExt)
 
 	l.pragmaFlag(w, name.Func.Pragma)
+	w.len(int(name.Func.InlineBudget))
 	l.linkname(w, name)
 
 	// Relocated extension data.
@@ -20,6 +20,7 @@ import (
 
 	"cmd/compile/internal/base"
 	"cmd/compile/internal/dwarfgen"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/typecheck"
@@ -71,7 +72,10 @@ func LoadPackage(filenames []string) {
 	var lines uint
 	for _, p := range noders {
 		for e := range p.err {
-			p.errorAt(e.Pos, "%s", e.Msg)
+			p.errorAt(e.Pos, e.Code, "%s", e.Msg)
+		}
+		for _, w := range p.pragmaWarnings {
+			base.WarningfAt(p.makeXPos(w.Pos), base.WarnPragma, "%s", w.Msg)
 		}
 		if p.file == nil {
 			base.ErrorExit()
@@ -185,8 +189,8 @@ func LoadPackage(filenames []string) {
 	base.ExitIfErrors()
 }
 
-func (p *noder) errorAt(pos syntax.Pos, format string, args ...interface{}) {
-	base.ErrorfAt(p.makeXPos(pos), format, args...)
+func (p *noder) errorAt(pos syntax.Pos, code errcode.Code, format string, args ...interface{}) {
+	base.ErrorfAt(p.makeXPos(pos), code, format, args...)
 }
 
 // trimFilename returns the "trimmed" filename of b, which is the
@@ -216,6 +220,7 @@ type noder struct {
 	linknames      []linkname
 	pragcgobuf     [][]string
 	err            chan syntax.Error
+	pragmaWarnings []syntax.Error // for base.WarnPragma, reported after parsing
 	importedUnsafe bool
 	importedEmbed  bool
 	trackScopes    bool
@@ -318,16 +323,16 @@ func (p *noder) node() {
 func (p *noder) processPragmas() {
 	for _, l := range p.linknames {
 		if !p.importedUnsafe {
-			p.errorAt(l.pos, "//go:linkname only allowed in Go files that import \"unsafe\"")
+			p.errorAt(l.pos, errcode.InvalidDirective, "//go:linkname only allowed in Go files that import \"unsafe\"")
 			continue
 		}
 		n := ir.AsNode(typecheck.Lookup(l.local).Def)
 		if n == nil || n.Op() != ir.ONAME {
-			p.errorAt(l.pos, "//go:linkname must refer to declared function or variable")
+			p.errorAt(l.pos, errcode.InvalidDirective, "//go:linkname must refer to declared function or variable")
 			continue
 		}
 		if n.Sym().Linkname != "" {
-			p.errorAt(l.pos, "duplicate //go:linkname for %s", l.local)
+			p.errorAt(l.pos, errcode.InvalidDirective, "duplicate //go:linkname for %s", l.local)
 			continue
 		}
 		n.Sym().Linkname = l.remote
@@ -402,11 +407,14 @@ func (p *noder) importDecl(imp *syntax.ImportDecl) {
 		importDot(pack)
 		return
 	case "init":
-		base.ErrorfAt(pack.Pos(), "cannot import package as init - init must be a func")
+		base.ErrorfAt(pack.Pos(), errcode.InvalidInitDecl, "cannot import package as init - init must be a func")
 		return
 	case "_":
 		return
 	}
+	if imp.LocalPkgName != nil {
+		ipkg.AddAlias(base.Ctxt.PosTable.Pos(pack.Pos()).Filename(), my.Name)
+	}
 	if my.Def != nil {
 		typecheck.Redeclared(pack.Pos(), my, "as imported package name")
 	}
@@ -556,7 +564,7 @@ func (p *noder) typeDecl(decl *syntax.TypeDecl) ir.Node {
 
 	nod := ir.NewDecl(p.pos(decl), ir.ODCLTYPE, n)
 	if n.Alias() && !types.AllowsGoVersion(types.LocalPkg, 1, 9) {
-		base.ErrorfAt(nod.Pos(), "type aliases only supported as of -lang=go1.9")
+		base.ErrorfAt(nod.Pos(), errcode.UnsupportedVersion, "type aliases only supported as of -lang=go1.9")
 	}
 	return nod
 }
@@ -582,14 +590,14 @@ func (p *noder) funcDecl(fun *syntax.FuncDecl) ir.Node {
 		if name.Name == "init" {
 			name = renameinit()
 			if len(t.Params) > 0 || len(t.Results) > 0 {
-				base.ErrorfAt(f.Pos(), "func init must have no arguments and no return values")
+				base.ErrorfAt(f.Pos(), errcode.InvalidInitDecl, "func init must have no arguments and no return values")
 			}
 			typecheck.Target.Inits = append(typecheck.Target.Inits, f)
 		}
 
 		if types.LocalPkg.Name == "main" && name.Name == "main" {
 			if len(t.Params) > 0 || len(t.Results) > 0 {
-				base.ErrorfAt(f.Pos(), "func main must have no arguments and no return values")
+				base.ErrorfAt(f.Pos(), errcode.InvalidMainDecl, "func main must have no arguments and no return values")
 			}
 		}
 	} else {
@@ -604,8 +612,12 @@ func (p *noder) funcDecl(fun *syntax.FuncDecl) ir.Node {
 
 	if pragma, ok := fun.Pragma.(*pragmas); ok {
 		f.Pragma = pragma.Flag & funcPragmas
+		f.InlineBudget = inlineBudget(pragma)
 		if pragma.Flag&ir.Systemstack != 0 && pragma.Flag&ir.Nosplit != 0 {
-			base.ErrorfAt(f.Pos(), "go:nosplit and go:systemstack cannot be combined")
+			base.ErrorfAt(f.Pos(), errcode.InvalidDirective, "go:nosplit and go:systemstack cannot be combined")
+		}
+		if msg := checkInlineBudget(f.Pragma, f.InlineBudget); msg != "" {
+			base.ErrorfAt(f.Pos(), errcode.InvalidDirective, "%s", msg)
 		}
 		pragma.Flag &^= funcPragmas
 		p.checkUnused(pragma)
@@ -619,7 +631,7 @@ func (p *noder) funcDecl(fun *syntax.FuncDecl) ir.Node {
 
 	if fun.Body != nil {
 		if f.Pragma&ir.Noescape != 0 {
-			base.ErrorfAt(f.Pos(), "can only use //go:noescape with external func implementations")
+			base.ErrorfAt(f.Pos(), errcode.InvalidDirective, "can only use //go:noescape with external func implementations")
 		}
 	} else {
 		if base.Flag.Complete || strings.HasPrefix(ir.FuncName(f), "init.") {
@@ -633,7 +645,7 @@ func (p *noder) funcDecl(fun *syntax.FuncDecl) ir.Node {
 				}
 			}
 			if !isLinknamed {
-				base.ErrorfAt(f.Pos(), "missing function body")
+				base.ErrorfAt(f.Pos(), errcode.MissingFuncBody, "missing function body")
 			}
 		}
 	}
@@ -682,7 +694,7 @@ func (p *noder) param(param *syntax.Field, dddOk, final bool) *ir.Field {
 			if param.Name == nil {
 				base.Errorf("syntax error: cannot use ... with non-final parameter")
 			} else {
-				p.errorAt(param.Name.Pos(), "syntax error: cannot use ... with non-final parameter %s", param.Name.Value)
+				p.errorAt(param.Name.Pos(), errcode.SyntaxError, "syntax error: cannot use ... with non-final parameter %s", param.Name.Value)
 			}
 		}
 		typ.DDD = false
@@ -996,7 +1008,7 @@ func (p *noder) packname(expr syntax.Expr) *types.Sym {
 		name := p.name(expr.X.(*syntax.Name))
 		def := ir.AsNode(name.Def)
 		if def == nil {
-			base.Errorf("undefined: %v", name)
+			base.MsgUndefined.ErrorAt(base.Pos, base.MsgArgs{"name": name})
 			return name
 		}
 		var pkg *types.Pkg
@@ -1189,7 +1201,7 @@ func (p *noder) assignList(expr syntax.Expr, defn ir.InitNode, colas bool) []ir.
 
 		name, ok := expr.(*syntax.Name)
 		if !ok {
-			p.errorAt(expr.Pos(), "non-name %v on left side of :=", p.expr(expr))
+			p.errorAt(expr.Pos(), errcode.NotAnExpr, "non-name %v on left side of :=", p.expr(expr))
 			newOrErr = true
 			continue
 		}
@@ -1200,7 +1212,7 @@ func (p *noder) assignList(expr syntax.Expr, defn ir.InitNode, colas bool) []ir.
 		}
 
 		if seen[sym] {
-			p.errorAt(expr.Pos(), "%v repeated on left side of :=", sym)
+			p.errorAt(expr.Pos(), errcode.RepeatedDecl, "%v repeated on left side of :=", sym)
 			newOrErr = true
 			continue
 		}
@@ -1220,7 +1232,7 @@ func (p *noder) assignList(expr syntax.Expr, defn ir.InitNode, colas bool) []ir.
 	}
 
 	if !newOrErr {
-		base.ErrorfAt(defn.Pos(), "no new variables on left side of :=")
+		base.ErrorfAt(defn.Pos(), errcode.NoNewVar, "no new variables on left side of :=")
 	}
 	return res
 }
@@ -1233,9 +1245,18 @@ func (p *noder) blockStmt(stmt *syntax.BlockStmt) []ir.Node {
 }
 
 func (p *noder) ifStmt(stmt *syntax.IfStmt) ir.Node {
+	var pragma ir.PragmaFlag
+	if prag, ok := stmt.Pragma.(*pragmas); ok {
+		pragma = prag.Flag & ifPragmas
+		prag.Flag &^= ifPragmas
+		p.checkUnused(prag)
+	}
 	p.openScope(stmt.Pos())
 	init := p.stmt(stmt.Init)
 	n := ir.NewIfStmt(p.pos(stmt), p.expr(stmt.Cond), p.blockStmt(stmt.Then), nil)
+	if err := setIfLikely(n, pragma); err != "" {
+		p.errorAt(stmt.Pos(), errcode.InvalidDirective, "%s", err)
+	}
 	if init != nil {
 		n.SetInit([]ir.Node{init})
 	}
@@ -1253,6 +1274,12 @@ func (p *noder) ifStmt(stmt *syntax.IfStmt) ir.Node {
 }
 
 func (p *noder) forStmt(stmt *syntax.ForStmt) ir.Node {
+	var unroll int
+	if prag, ok := stmt.Pragma.(*pragmas); ok {
+		unroll = unrollFactor(prag)
+		prag.Flag &^= forPragmas
+		p.checkUnused(prag)
+	}
 	p.openScope(stmt.Pos())
 	if r, ok := stmt.Init.(*syntax.RangeClause); ok {
 		if stmt.Cond != nil || stmt.Post != nil {
@@ -1269,11 +1296,13 @@ func (p *noder) forStmt(stmt *syntax.ForStmt) ir.Node {
 			}
 		}
 		n.Body = p.blockStmt(stmt.Body)
+		n.Unroll = unroll
 		p.closeAnotherScope()
 		return n
 	}
 
 	n := ir.NewForStmt(p.pos(stmt), p.stmt(stmt.Init), p.expr(stmt.Cond), p.stmt(stmt.Post), p.blockStmt(stmt.Body))
+	n.Unroll = unroll
 	p.closeAnotherScope()
 	return n
 }
@@ -1505,7 +1534,7 @@ func (p *noder) basicLit(lit *syntax.BasicLit) constant.Value {
 		// allows for separators between all digits.
 		const limit = 10000
 		if len(lit.Value) > limit {
-			p.errorAt(lit.Pos(), "excessively long constant: %s... (%d chars)", lit.Value[:10], len(lit.Value))
+			p.errorAt(lit.Pos(), errcode.InvalidLit, "excessively long constant: %s... (%d chars)", lit.Value[:10], len(lit.Value))
 			return constant.MakeUnknown()
 		}
 	}
@@ -1513,7 +1542,7 @@ func (p *noder) basicLit(lit *syntax.BasicLit) constant.Value {
 	v := constant.MakeFromLiteral(lit.Value, tokenForLitKind[lit.Kind], 0)
 	if v.Kind() == constant.Unknown {
 		// TODO(mdempsky): Better error message?
-		p.errorAt(lit.Pos(), "malformed constant: %s", lit.Value)
+		p.errorAt(lit.Pos(), errcode.InvalidLit, "malformed constant: %s", lit.Value)
 	}
 
 	return v
@@ -1581,11 +1610,21 @@ var allowedStdPragmas = map[string]bool{
 	"go:generate":           true,
 }
 
+// otherPragmas lists the directives that the compiler knows but
+// records no flag for, other than the allowedStdPragmas.
+var otherPragmas = map[string]bool{
+	"go:binary-only-package": true, // for the go command
+	"go:nointerface":         true, // only has an effect with GOEXPERIMENT=fieldtrack
+}
+
 // *pragmas is the value stored in a syntax.pragmas during parsing.
 type pragmas struct {
 	Flag   ir.PragmaFlag // collected bits
 	Pos    []pragmaPos   // position of each individual flag
 	Embeds []pragmaEmbed
+	Unroll int // factor of //go:unroll
+
+	InlineBudget int32 // budget of //go:inlinebudget
 }
 
 type pragmaPos struct {
@@ -1601,12 +1640,12 @@ type pragmaEmbed struct {
 func (p *noder) checkUnused(pragma *pragmas) {
 	for _, pos := range pragma.Pos {
 		if pos.Flag&pragma.Flag != 0 {
-			p.errorAt(pos.Pos, "misplaced compiler directive")
+			p.errorAt(pos.Pos, errcode.InvalidDirective, "misplaced compiler directive")
 		}
 	}
 	if len(pragma.Embeds) > 0 {
 		for _, e := range pragma.Embeds {
-			p.errorAt(e.Pos, "misplaced go:embed directive")
+			p.errorAt(e.Pos, errcode.InvalidDirective, "misplaced go:embed directive")
 		}
 	}
 }
@@ -1614,12 +1653,12 @@ func (p *noder) checkUnused(pragma *pragmas) {
 func (p *noder) checkUnusedDuringParse(pragma *pragmas) {
 	for _, pos := range pragma.Pos {
 		if pos.Flag&pragma.Flag != 0 {
-			p.error(syntax.Error{Pos: pos.Pos, Msg: "misplaced compiler directive"})
+			p.error(syntax.Error{Pos: pos.Pos, Msg: "misplaced compiler directive", Code: errcode.InvalidDirective})
 		}
 	}
 	if len(pragma.Embeds) > 0 {
 		for _, e := range pragma.Embeds {
-			p.error(syntax.Error{Pos: e.Pos, Msg: "misplaced go:embed directive"})
+			p.error(syntax.Error{Pos: e.Pos, Msg: "misplaced go:embed directive", Code: errcode.InvalidDirective})
 		}
 	}
 }
@@ -1644,7 +1683,7 @@ func (p *noder) pragma(pos syntax.Pos, blankLine bool, text string, old syntax.P
 
 	if !blankLine {
 		// directive must be on line by itself
-		p.error(syntax.Error{Pos: pos, Msg: "misplaced compiler directive"})
+		p.error(syntax.Error{Pos: pos, Msg: "misplaced compiler directive", Code: errcode.InvalidDirective})
 		return pragma
 	}
 
@@ -1652,7 +1691,7 @@ func (p *noder) pragma(pos syntax.Pos, blankLine bool, text string, old syntax.P
 	case strings.HasPrefix(text, "go:linkname "):
 		f := strings.Fields(text)
 		if !(2 <= len(f) && len(f) <= 3) {
-			p.error(syntax.Error{Pos: pos, Msg: "usage: //go:linkname localname [linkname]"})
+			p.error(syntax.Error{Pos: pos, Msg: "usage: //go:linkname localname [linkname]", Code: errcode.InvalidDirective})
 			break
 		}
 		// The second argument is optional. If omitted, we use
@@ -1666,9 +1705,9 @@ func (p *noder) pragma(pos syntax.Pos, blankLine bool, text string, old syntax.P
 		} else if base.Ctxt.Pkgpath != "" {
 			// Use the default object symbol name if the
 			// user didn't provide one.
-			target = objabi.PathToPrefix(base.Ctxt.Pkgpath) + "." + f[1]
+			target = objabi.PathToPrefix(types.LinkPkgPath(base.Ctxt.Pkgpath)) + "." + f[1]
 		} else {
-			p.error(syntax.Error{Pos: pos, Msg: "//go:linkname requires linkname argument or -p compiler flag"})
+			p.error(syntax.Error{Pos: pos, Msg: "//go:linkname requires linkname argument or -p compiler flag", Code: errcode.InvalidDirective})
 			break
 		}
 		p.linknames = append(p.linknames, linkname{pos, f[1], target})
@@ -1676,14 +1715,46 @@ func (p *noder) pragma(pos syntax.Pos, blankLine bool, text string, old syntax.P
 	case text == "go:embed", strings.HasPrefix(text, "go:embed "):
 		args, err := parseGoEmbed(text[len("go:embed"):])
 		if err != nil {
-			p.error(syntax.Error{Pos: pos, Msg: err.Error()})
+			p.error(syntax.Error{Pos: pos, Msg: err.Error(), Code: errcode.InvalidDirective})
 		}
 		if len(args) == 0 {
-			p.error(syntax.Error{Pos: pos, Msg: "usage: //go:embed pattern..."})
+			p.error(syntax.Error{Pos: pos, Msg: "usage: //go:embed pattern...", Code: errcode.InvalidDirective})
 			break
 		}
 		pragma.Embeds = append(pragma.Embeds, pragmaEmbed{pos, args})
 
+	case text == "go:unroll", strings.HasPrefix(text, "go:unroll "):
+		// The next for statement should be unrolled by the
+		// given factor.
+		f := strings.Fields(text)
+		n := 0
+		if len(f) == 2 {
+			n, _ = strconv.Atoi(f[1])
+		}
+		if n < 1 {
+			p.error(syntax.Error{Pos: pos, Msg: "usage: //go:unroll n", Code: errcode.InvalidDirective})
+			break
+		}
+		pragma.Unroll = n
+		pragma.Flag |= ir.Unroll
+		pragma.Pos = append(pragma.Pos, pragmaPos{ir.Unroll, pos})
+
+	case text == "go:inlinebudget", strings.HasPrefix(text, "go:inlinebudget "):
+		// The next function should be inlined if its cost is
+		// within the given budget.
+		f := strings.Fields(text)
+		n := 0
+		if len(f) == 2 {
+			n, _ = strconv.Atoi(f[1])
+		}
+		if n < 1 {
+			p.error(syntax.Error{Pos: pos, Msg: "usage: //go:inlinebudget n", Code: errcode.InvalidDirective})
+			break
+		}
+		pragma.InlineBudget = int32(n)
+		pragma.Flag |= ir.InlineBudget
+		pragma.Pos = append(pragma.Pos, pragmaPos{ir.InlineBudget, pos})
+
 	case strings.HasPrefix(text, "go:cgo_import_dynamic "):
 		// This is permitted for general use because Solaris
 		// code relies on it in golang.org/x/sys/unix and others.
@@ -1691,7 +1762,7 @@ func (p *noder) pragma(pos syntax.Pos, blankLine bool, text string, old syntax.P
 		if len(fields) >= 4 {
 			lib := strings.Trim(fields[3], `"`)
 			if lib != "" && !safeArg(lib) && !isCgoGeneratedFile(pos) {
-				p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf("invalid library name %q in cgo_import_dynamic directive", lib)})
+				p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf("invalid library name %q in cgo_import_dynamic directive", lib), Code: errcode.InvalidDirective})
 			}
 			p.pragcgo(pos, text)
 			pragma.Flag |= pragmaFlag("go:cgo_import_dynamic")
@@ -1703,7 +1774,7 @@ func (p *noder) pragma(pos syntax.Pos, blankLine bool, text string, old syntax.P
 		// than cgo_import_dynamic outside cgo-generated files.
 		// Exception: they are allowed in the standard library, for runtime and syscall.
 		if !isCgoGeneratedFile(pos) && !base.Flag.Std {
-			p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf("//%s only allowed in cgo-generated code", text)})
+			p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf("//%s only allowed in cgo-generated code", text), Code: errcode.InvalidDirective})
 		}
 		p.pragcgo(pos, text)
 		fallthrough // because of //go:cgo_unsafe_args
@@ -1715,10 +1786,13 @@ func (p *noder) pragma(pos syntax.Pos, blankLine bool, text string, old syntax.P
 		flag := pragmaFlag(verb)
 		const runtimePragmas = ir.Systemstack | ir.Nowritebarrier | ir.Nowritebarrierrec | ir.Yeswritebarrierrec
 		if !base.Flag.CompilingRuntime && flag&runtimePragmas != 0 {
-			p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf("//%s only allowed in runtime", verb)})
+			p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf("//%s only allowed in runtime", verb), Code: errcode.InvalidDirective})
 		}
 		if flag == 0 && !allowedStdPragmas[verb] && base.Flag.Std {
-			p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf("//%s is not allowed in the standard library", verb)})
+			p.error(syntax.Error{Pos: pos, Msg: fmt.Sprintf("//%s is not allowed in the standard library", verb), Code: errcode.InvalidDirective})
+		}
+		if flag == 0 && !allowedStdPragmas[verb] && !otherPragmas[verb] {
+			p.pragmaWarnings = append(p.pragmaWarnings, syntax.Error{Pos: pos, Msg: fmt.Sprintf("ignoring unknown compiler directive //%s", verb)})
 		}
 		pragma.Flag |= flag
 		pragma.Pos = append(pragma.Pos, pragmaPos{flag, pos})
@@ -1881,7 +1955,7 @@ func varEmbed(makeXPos func(syntax.Pos) src.XPos, name *ir.Name, decl *syntax.Va
 	}
 
 	if err := checkEmbed(decl, haveEmbed, typecheck.DeclContext != ir.PEXTERN); err != nil {
-		base.ErrorfAt(makeXPos(pragmaEmbeds[0].Pos), "%s", err)
+		base.ErrorfAt(makeXPos(pragmaEmbeds[0].Pos), errcode.InvalidDirective, "%s", err)
 		return
 	}
 
@@ -139,9 +139,9 @@ func (g *irgen) obj(obj types2.Object) *ir.Name {
 				}
 			}
 			if sym == nil {
-				sym = typecheck.LookupNum("~r", nresults) // 'r' for "result"
+				sym = types.ParamSym(types.ParamResult, nresults)
 			} else {
-				sym = typecheck.LookupNum("~b", nresults) // 'b' for "blank"
+				sym = types.ParamSym(types.ParamBlank, nresults)
 			}
 		}
 		name = g.objCommon(pos, ir.ONAME, sym, class, g.typ(obj.Type()))
@@ -844,6 +844,7 @@ func (r *reader) funcExt(name *ir.Name) {
 	}
 
 	fn.Pragma = r.pragmaFlag()
+	fn.InlineBudget = int32(r.len())
 	r.linkname(name)
 
 	typecheck.Func(fn)
@@ -995,13 +996,13 @@ func (r *reader) funcargs(fn *ir.Func) {
 		sym := types.OrigSym(param.Sym)
 
 		if sym == nil || sym.IsBlank() {
-			prefix := "~r"
+			kind := types.ParamResult
 			if r.inlCall != nil {
-				prefix = "~R"
+				kind = types.ParamInlResult
 			} else if sym != nil {
-				prefix = "~b"
+				kind = types.ParamBlank
 			}
-			sym = typecheck.LookupNum(prefix, i)
+			sym = types.ParamSym(kind, i)
 		}
 
 		r.funcarg(param, sym, ir.PPARAMOUT)
@@ -1342,6 +1343,7 @@ func (r *reader) forStmt(label *types.Sym) ir.Node {
 	r.sync(syncForStmt)
 
 	r.openScope()
+	unroll := r.len()
 
 	if r.bool() {
 		pos := r.pos()
@@ -1363,6 +1365,7 @@ func (r *reader) forStmt(label *types.Sym) ir.Node {
 		}
 		rang.Def = r.initDefn(rang, names)
 		rang.Label = label
+		rang.Unroll = unroll
 		return rang
 	}
 
@@ -1375,6 +1378,7 @@ func (r *reader) forStmt(label *types.Sym) ir.Node {
 
 	stmt := ir.NewForStmt(pos, init, cond, post, body)
 	stmt.Label = label
+	stmt.Unroll = unroll
 	return stmt
 }
 
@@ -1382,11 +1386,13 @@ func (r *reader) ifStmt() ir.Node {
 	r.sync(syncIfStmt)
 	r.openScope()
 	pos := r.pos()
+	pragma := r.pragmaFlag()
 	init := r.stmts()
 	cond := r.expr()
 	then := r.blockStmt()
 	els := r.stmts()
 	n := ir.NewIfStmt(pos, cond, then, els)
+	setIfLikely(n, pragma)
 	n.SetInit(init)
 	r.closeAnotherScope()
 	return n
@@ -2252,8 +2258,8 @@ func MakeWrappers(target *ir.Package) {
 func wrapType(typ *types.Type, target *ir.Package, seen map[string]*types.Type, needed bool) {
 	key := typ.LinkString()
 	if prev := seen[key]; prev != nil {
-		if !types.Identical(typ, prev) {
-			base.Fatalf("collision: types %v and %v have link string %q", typ, prev, key)
+		if ok, why := types.IdenticalReason(typ, prev); !ok {
+			base.Fatalf("collision: types %v and %v have link string %q: %s", typ, prev, key, why)
 		}
 		return
 	}
@@ -2414,7 +2420,7 @@ func newWrapperType(recvType *types.Type, method *types.Field) *types.Type {
 		for i, param := range params {
 			sym := param.Sym
 			if sym == nil || sym.Name == "_" {
-				sym = typecheck.LookupNum(".anon", i)
+				sym = types.ParamSym(types.ParamAnon, i)
 			}
 			res[i] = types.NewField(param.Pos, sym, param.Type)
 			res[i].SetIsDDD(param.IsDDD())
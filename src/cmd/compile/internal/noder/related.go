@@ -0,0 +1,58 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+	"cmd/internal/src"
+)
+
+// relatedPositions returns secondary positions for the type checking
+// error terr, for base.ErrorDetails. When an operand cannot be used as
+// a value of some type, or two operands have mismatched types, they
+// are the declarations of the defined types that the details of terr
+// name. The export data records where the types of imported packages
+// are declared, so that tells apart types of the same name, as in
+// "mismatched types a.Config and b.Config".
+//
+// Errors that the type checker reports with notes on further lines of
+// their messages, like that of a redeclaration, already point at
+// other positions and get none.
+func relatedPositions(m *posMap, pkg *types2.Package, terr types2.Error) []base.Related {
+	if terr.Details == nil {
+		return nil
+	}
+	var related []base.Related
+	for _, t := range terr.Details.Types {
+		t, ok := t.(*types2.Named)
+		if !ok || !t.Obj().Pos().IsKnown() {
+			continue
+		}
+		related = append(related, base.Related{Pos: m.makeXPos(t.Obj().Pos()), Msg: "declaration of " + typeString(pkg, t)})
+	}
+	return related
+}
+
+// errorEnd returns the end of the source text at the position of the
+// type checking error terr in one of files, for base.ErrorDetails, or
+// src.NoXPos if it does not know it. It knows it if the innermost node
+// at the position is a name or a literal, the only nodes whose end the
+// syntax tree records exactly.
+func errorEnd(m *posMap, files []*syntax.File, terr types2.Error) src.XPos {
+	if !terr.Pos.IsKnown() {
+		return src.NoXPos
+	}
+	path := pathTo(fileOf(files, terr.Pos), terr.Pos)
+	if len(path) == 0 {
+		return src.NoXPos
+	}
+	switch n := path[len(path)-1].(type) {
+	case *syntax.Name, *syntax.BasicLit:
+		return m.makeXPos(syntax.EndPos(n))
+	}
+	return src.NoXPos
+}
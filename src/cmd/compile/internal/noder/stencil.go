@@ -28,15 +28,9 @@ func assert(p bool) {
 	base.Assert(p)
 }
 
-// For outputting debug information on dictionary format and instantiated dictionaries
-// (type arg, derived types, sub-dictionary, and itab entries).
-var infoPrintMode = false
-
-func infoPrint(format string, a ...interface{}) {
-	if infoPrintMode {
-		fmt.Printf(format, a...)
-	}
-}
+// dictLog logs the format of dictionaries and the entries of instantiated
+// dictionaries (type arg, derived types, sub-dictionary, and itab entries).
+var dictLog = base.NewLogger("dict")
 
 var geninst genInst
 
@@ -166,18 +160,19 @@ func (g *genInst) scanForGenCalls(decl ir.Node) {
 			inst := call.X.(*ir.InstExpr)
 			nameNode, isMeth := g.getInstNameNode(inst)
 			targs := typecheck.TypesOf(inst.Targs)
+			g.instSite = call.Pos()
 			st := g.getInstantiation(nameNode, targs, isMeth).fun
 			dictValue, usingSubdict := g.getDictOrSubdict(declInfo, n, nameNode, targs, isMeth)
-			if infoPrintMode {
-				dictkind := "Main dictionary"
+			if dictLog.Enabled() {
+				dictkind := "main dictionary"
 				if usingSubdict {
-					dictkind = "Sub-dictionary"
+					dictkind = "sub-dictionary"
 				}
+				callee := "function"
 				if inst.X.Op() == ir.OMETHVALUE {
-					fmt.Printf("%s in %v at generic method call: %v - %v\n", dictkind, decl, inst.X, call)
-				} else {
-					fmt.Printf("%s in %v at generic function call: %v - %v\n", dictkind, decl, inst.X, call)
+					callee = "method"
 				}
+				dictLog.Log(call.Pos(), dictkind+" at generic "+callee+" call", "func", decl, "callee", inst.X, "call", call)
 			}
 
 			// Transform the Call now, which changes OCALL to
@@ -225,6 +220,7 @@ func (g *genInst) scanForGenCalls(decl ir.Node) {
 			// to OCALLFUNC and does typecheckaste/assignconvfn.
 			transformCall(call)
 
+			g.instSite = call.Pos()
 			st := g.getInstantiation(gf, targs, true).fun
 			dictValue, usingSubdict := g.getDictOrSubdict(declInfo, n, gf, targs, true)
 			// We have to be using a subdictionary, since this is
@@ -331,18 +327,19 @@ func (g *genInst) buildClosure(outer *ir.Func, x ir.Node) ir.Node {
 		// For method values, the target expects a dictionary and the receiver
 		// as its first two arguments.
 		// dictValue is the value to use for the dictionary argument.
+		g.instSite = x.Pos()
 		target = g.getInstantiation(gf, targs, rcvrValue != nil).fun
 		dictValue, usingSubdict = g.getDictOrSubdict(outerInfo, x, gf, targs, rcvrValue != nil)
-		if infoPrintMode {
-			dictkind := "Main dictionary"
+		if dictLog.Enabled() {
+			dictkind := "main dictionary"
 			if usingSubdict {
-				dictkind = "Sub-dictionary"
+				dictkind = "sub-dictionary"
 			}
-			if rcvrValue == nil {
-				fmt.Printf("%s in %v for generic function value %v\n", dictkind, outer, inst.X)
-			} else {
-				fmt.Printf("%s in %v for generic method value %v\n", dictkind, outer, inst.X)
+			value := "function"
+			if rcvrValue != nil {
+				value = "method"
 			}
+			dictLog.Log(x.Pos(), dictkind+" for generic "+value+" value", "func", outer, "value", inst.X)
 		}
 	} else { // ir.OMETHEXPR or ir.METHVALUE
 		// Method expression T.M where T is a generic type.
@@ -380,12 +377,12 @@ func (g *genInst) buildClosure(outer *ir.Func, x ir.Node) ir.Node {
 		}
 		target = g.getInstantiation(gf, targs, true).fun
 		dictValue, usingSubdict = g.getDictOrSubdict(outerInfo, x, gf, targs, true)
-		if infoPrintMode {
-			dictkind := "Main dictionary"
+		if dictLog.Enabled() {
+			dictkind := "main dictionary"
 			if usingSubdict {
-				dictkind = "Sub-dictionary"
+				dictkind = "sub-dictionary"
 			}
-			fmt.Printf("%s in %v for method expression %v\n", dictkind, outer, x)
+			dictLog.Log(x.Pos(), dictkind+" for method expression", "func", outer, "expr", x)
 		}
 	}
 
@@ -544,6 +541,12 @@ func (g *genInst) buildClosure(outer *ir.Func, x ir.Node) ir.Node {
 // all fully-instantiated generic types that have been added to typecheck.instTypeList.
 // It continues until no more types are added to typecheck.instTypeList.
 func (g *genInst) instantiateMethods() {
+	// The instantiations of methods are caused by the instantiation of
+	// their type, not by the reference being stenciled.
+	site := g.instSite
+	g.instSite = src.NoXPos
+	defer func() { g.instSite = site }()
+
 	for {
 		instTypeList := typecheck.GetInstTypeList()
 		if len(instTypeList) == 0 {
@@ -557,11 +560,22 @@ func (g *genInst) instantiateMethods() {
 			// instantiated type has a different package from the local
 			// package.
 			typecheck.NeedRuntimeType(typ)
+			g.recordInstance(typ.OrigSym(), typ.RParams())
+			g.recordInstType(typ)
+			if gi := g.growthFor(typ.OrigSym()); gi != nil {
+				gi.types = append(gi.types, typ)
+			}
 			// Lookup the method on the base generic type, since methods may
 			// not be set on imported instantiated types.
 			baseSym := typ.OrigSym()
 			baseType := baseSym.Def.(*ir.Name).Type()
 			for j, _ := range typ.Methods().Slice() {
+				if baseType.Methods().Slice()[j].IsGenericMethod() {
+					// Generic methods are only instantiated
+					// where they are called, with their own
+					// type arguments as well.
+					continue
+				}
 				if baseType.Methods().Slice()[j].Nointerface() {
 					typ.Methods().Slice()[j].SetNointerface(true)
 				}
@@ -655,10 +669,18 @@ func (g *genInst) getInstantiation(nameNode *ir.Name, shapes []*types.Type, isMe
 	// number of instantiations we have to generate. You can actually have a mix
 	// of shape and non-shape arguments, because of inferred or explicitly
 	// specified concrete type args.
+	// A function marked //go:stencil has an instantiation for each
+	// concrete type argument, though references from other shape-based
+	// instantiations, which only know the shapes, share one.
+	stencil := nameNode.Func != nil && nameNode.Func.Pragma&ir.Stencil != 0
 	s1 := make([]*types.Type, len(shapes))
 	for i, t := range shapes {
 		if !t.IsShape() {
-			s1[i] = typecheck.Shapify(t, i)
+			if stencil {
+				s1[i] = typecheck.StencilShape(t, i)
+			} else {
+				s1[i] = typecheck.Shapify(t, i)
+			}
 		} else {
 			// Already a shape, but make sure it has the correct index.
 			s1[i] = typecheck.Shapify(shapes[i].Underlying(), i)
@@ -666,7 +688,8 @@ func (g *genInst) getInstantiation(nameNode *ir.Name, shapes []*types.Type, isMe
 	}
 	shapes = s1
 
-	sym := typecheck.MakeFuncInstSym(nameNode.Sym(), shapes, false, isMeth)
+	_, nrecv := instTParams(nameNode, isMeth)
+	sym := typecheck.MakeFuncInstSym(nameNode.Sym(), shapes, false, nrecv > 0)
 	info := g.instInfoMap[sym]
 	if info == nil {
 		// If instantiation doesn't exist yet, create it and add
@@ -692,6 +715,9 @@ func (g *genInst) getInstantiation(nameNode *ir.Name, shapes []*types.Type, isMe
 		st.SetDupok(true)
 		typecheck.Target.Decls = append(typecheck.Target.Decls, st)
 		g.newInsts = append(g.newInsts, st)
+		if gi := g.recordGrowth(nameNode, isMeth); gi != nil {
+			gi.funcs = append(gi.funcs, st)
+		}
 	}
 	return info
 }
@@ -716,24 +742,12 @@ type subster struct {
 // method or function, a dictionary parameter is the added as the very first
 // parameter. genericSubst fills in info.dictParam and info.tparamToBound.
 func (g *genInst) genericSubst(newsym *types.Sym, nameNode *ir.Name, shapes []*types.Type, isMethod bool, info *instInfo) *ir.Func {
-	var tparams []*types.Type
-	if isMethod {
-		// Get the type params from the method receiver (after skipping
-		// over any pointer)
-		recvType := nameNode.Type().Recv().Type
-		recvType = deref(recvType)
-		tparams = recvType.RParams()
-	} else {
-		fields := nameNode.Type().TParams().Fields().Slice()
-		tparams = make([]*types.Type, len(fields))
-		for i, f := range fields {
-			tparams[i] = f.Type
-		}
-	}
+	tparams, _ := instTParams(nameNode, isMethod)
 	gf := nameNode.Func
 	// Pos of the instantiated function is same as the generic function
 	newf := ir.NewFunc(gf.Pos())
 	newf.Pragma = gf.Pragma // copy over pragmas from generic function to stenciled implementation.
+	newf.InlineBudget = gf.InlineBudget
 	newf.Nname = ir.NewNameAt(gf.Pos(), newsym)
 	newf.Nname.Func = newf
 	newf.Nname.Defn = newf
@@ -744,6 +758,7 @@ func (g *genInst) genericSubst(newsym *types.Sym, nameNode *ir.Name, shapes []*t
 	ir.CurFunc = newf
 
 	assert(len(tparams) == len(shapes))
+	newf.Inst = &ir.FuncInst{TParams: tparams, Shapes: shapes}
 
 	subst := &subster{
 		g:        g,
@@ -1464,6 +1479,24 @@ func (subst *subster) fields(class ir.Class, oldfields []*types.Field, dcl []*ir
 }
 
 // deref does a single deref of type t, if it is a pointer type.
+// instTParams returns the type parameters that an instantiation of the
+// generic function or method gf binds, in the order of its type
+// arguments: for a method, the type parameters of its receiver, of
+// which there are nrecv, followed by any of its own (see
+// types.Field.IsGenericMethod).
+func instTParams(gf *ir.Name, isMeth bool) (tparams []*types.Type, nrecv int) {
+	if isMeth {
+		// Get the type params from the method receiver (after skipping
+		// over any pointer)
+		tparams = deref(gf.Type().Recv().Type).RParams()
+		nrecv = len(tparams)
+	}
+	for _, f := range gf.Type().TParams().FieldSlice() {
+		tparams = append(tparams, f.Type)
+	}
+	return tparams, nrecv
+}
+
 func deref(t *types.Type) *types.Type {
 	if t.IsPtr() {
 		return t.Elem()
@@ -1498,7 +1531,8 @@ func (g *genInst) getDictionarySym(gf *ir.Name, targs []*types.Type, isMeth bool
 	}
 
 	// Get a symbol representing the dictionary.
-	sym := typecheck.MakeDictSym(gf.Sym(), targs, isMeth)
+	_, nrecv := instTParams(gf, isMeth)
+	sym := typecheck.MakeDictSym(gf.Sym(), targs, nrecv > 0)
 
 	// Initialize the dictionary, if we haven't yet already.
 	lsym := sym.Linksym()
@@ -1507,11 +1541,22 @@ func (g *genInst) getDictionarySym(gf *ir.Name, targs []*types.Type, isMeth bool
 		return sym
 	}
 
-	infoPrint("=== Creating dictionary %v\n", sym.Name)
+	g.enterInst(gf, targs, isMeth)
+	defer g.leaveInst()
+
+	if !isMeth {
+		g.recordInstance(gf.Sym(), targs)
+	}
+	if gi := g.recordGrowth(gf, isMeth); gi != nil {
+		gi.dicts = append(gi.dicts, lsym)
+	}
+
+	dictLog.Log(src.NoXPos, "creating dictionary", "dict", sym.Name)
+	dump := newDictDump(gf, targs, isMeth, sym)
 	off := 0
 	// Emit an entry for each targ (concrete type or gcshape).
 	for _, t := range targs {
-		infoPrint(" * %v\n", t)
+		dictLog.Log(src.NoXPos, "type argument entry", "dict", sym.Name, "type", t)
 		s := reflectdata.TypeLinksym(t)
 		off = objw.SymPtr(lsym, off, s, 0)
 		markTypeUsed(t, lsym)
@@ -1519,6 +1564,7 @@ func (g *genInst) getDictionarySym(gf *ir.Name, targs []*types.Type, isMeth bool
 
 	instInfo := g.getInstantiation(gf, targs, isMeth)
 	info := instInfo.dictInfo
+	g.recordInstFunc(gf, targs, isMeth, instInfo.fun, lsym)
 
 	subst := typecheck.Tsubster{
 		Tparams: info.shapeParams,
@@ -1527,13 +1573,16 @@ func (g *genInst) getDictionarySym(gf *ir.Name, targs []*types.Type, isMeth bool
 	// Emit an entry for each derived type (after substituting targs)
 	for _, t := range info.derivedTypes {
 		ts := subst.Typ(t)
-		infoPrint(" - %v\n", ts)
+		dictLog.Log(src.NoXPos, "derived type entry", "dict", sym.Name, "type", ts)
+		dump.add("derived type %v = %v", t, ts)
 		s := reflectdata.TypeLinksym(ts)
 		off = objw.SymPtr(lsym, off, s, 0)
 		markTypeUsed(ts, lsym)
 	}
 	// Emit an entry for each subdictionary (after substituting targs)
+	site := g.instSite
 	for _, n := range info.subDictCalls {
+		g.instSite = n.Pos()
 		var sym *types.Sym
 		switch n.Op() {
 		case ir.OCALL, ir.OCALLFUNC, ir.OCALLMETH:
@@ -1610,12 +1659,12 @@ func (g *genInst) getDictionarySym(gf *ir.Name, targs []*types.Type, isMeth bool
 
 		case ir.OFUNCINST:
 			inst := n.(*ir.InstExpr)
-			nameNode := inst.X.(*ir.Name)
+			nameNode, isMeth := g.getInstNameNode(inst)
 			subtargs := typecheck.TypesOf(inst.Targs)
 			for i, t := range subtargs {
 				subtargs[i] = subst.Typ(t)
 			}
-			sym = g.getDictionarySym(nameNode, subtargs, false)
+			sym = g.getDictionarySym(nameNode, subtargs, isMeth)
 
 		case ir.OXDOT, ir.OMETHEXPR, ir.OMETHVALUE:
 			selExpr := n.(*ir.SelectorExpr)
@@ -1636,12 +1685,15 @@ func (g *genInst) getDictionarySym(gf *ir.Name, targs []*types.Type, isMeth bool
 		if sym == nil {
 			// Unused sub-dictionary entry, just emit 0.
 			off = objw.Uintptr(lsym, off, 0)
-			infoPrint(" - Unused subdict entry\n")
+			dictLog.Log(src.NoXPos, "unused sub-dictionary entry", "dict", lsym.Name)
+			dump.add("unused sub-dictionary for %v at %v", n.Op(), base.FmtPos(n.Pos()))
 		} else {
 			off = objw.SymPtr(lsym, off, sym.Linksym(), 0)
-			infoPrint(" - Subdict %v\n", sym.Name)
+			dictLog.Log(src.NoXPos, "sub-dictionary entry", "dict", lsym.Name, "subdict", sym.Name)
+			dump.add("sub-dictionary %s for %v at %v", sym.Name, n.Op(), base.FmtPos(n.Pos()))
 		}
 	}
+	g.instSite = site
 
 	g.instantiateMethods()
 	delay := &delayInfo{
@@ -1650,6 +1702,7 @@ func (g *genInst) getDictionarySym(gf *ir.Name, targs []*types.Type, isMeth bool
 		sym:    sym,
 		off:    off,
 		isMeth: isMeth,
+		dump:   dump,
 	}
 	g.dictSymsToFinalize = append(g.dictSymsToFinalize, delay)
 	return sym
@@ -1662,7 +1715,7 @@ func (g *genInst) getDictionarySym(gf *ir.Name, targs []*types.Type, isMeth bool
 // instantiations have been created.
 func (g *genInst) finalizeSyms() {
 	for _, d := range g.dictSymsToFinalize {
-		infoPrint("=== Finalizing dictionary %s\n", d.sym.Name)
+		dictLog.Log(src.NoXPos, "finalizing dictionary", "dict", d.sym.Name)
 
 		lsym := d.sym.Linksym()
 		instInfo := g.getInstantiation(d.gf, d.targs, d.isMeth)
@@ -1697,19 +1750,22 @@ func (g *genInst) finalizeSyms() {
 				// No itab is wanted if src type is an interface. We
 				// will use a type assert instead.
 				d.off = objw.Uintptr(lsym, d.off, 0)
-				infoPrint(" + Unused itab entry for %v\n", srctype)
+				dictLog.Log(src.NoXPos, "unused itab entry", "dict", d.sym.Name, "src", srctype)
+				d.dump.add("unused itab for %v (an interface; converted by type assertion)", srctype)
 			} else {
 				// Make sure all new fully-instantiated types have
 				// their methods created before generating any itabs.
 				g.instantiateMethods()
 				itabLsym := reflectdata.ITabLsym(srctype, dsttype)
 				d.off = objw.SymPtr(lsym, d.off, itabLsym, 0)
-				infoPrint(" + Itab for (%v,%v)\n", srctype, dsttype)
+				dictLog.Log(src.NoXPos, "itab entry", "dict", d.sym.Name, "src", srctype, "dst", dsttype)
+				d.dump.add("itab %v, %v", srctype, dsttype)
 			}
 		}
 
 		objw.Global(lsym, int32(d.off), obj.DUPOK|obj.RODATA)
-		infoPrint("=== Finalized dictionary %s\n", d.sym.Name)
+		dictLog.Log(src.NoXPos, "finalized dictionary", "dict", d.sym.Name)
+		d.dump.print()
 	}
 	g.dictSymsToFinalize = nil
 }
@@ -1781,11 +1837,10 @@ func (g *genInst) getInstInfo(st *ir.Func, shapes []*types.Type, instInfo *instI
 		n.DictIndex = uint16(findDictType(instInfo, n.Type()) + 1)
 	}
 
-	if infoPrintMode {
-		fmt.Printf(">>> InstInfo for %v\n", st)
-		for _, t := range info.shapeParams {
-			fmt.Printf("  Typeparam %v\n", t)
-		}
+	log := dictLog.With("func", st)
+	log.Log(st.Pos(), "computing dictionary format")
+	for _, t := range info.shapeParams {
+		log.Log(src.NoXPos, "type parameter", "type", t)
 	}
 
 	// Map to remember when we have seen an instantiated function value or method
@@ -1798,17 +1853,18 @@ func (g *genInst) getInstInfo(st *ir.Func, shapes []*types.Type, instInfo *instI
 		switch n.Op() {
 		case ir.OFUNCINST:
 			if !callMap[n] && hasShapeNodes(n.(*ir.InstExpr).Targs) {
-				infoPrint("  Closure&subdictionary required at generic function value %v\n", n.(*ir.InstExpr).X)
+				log.Log(n.Pos(), "closure and sub-dictionary required at generic function value", "value", n.(*ir.InstExpr).X)
 				info.subDictCalls = append(info.subDictCalls, n)
 			}
 		case ir.OMETHEXPR, ir.OMETHVALUE:
 			if !callMap[n] && !types.IsInterfaceMethod(n.(*ir.SelectorExpr).Selection.Type) &&
+				!n.(*ir.SelectorExpr).Selection.IsGenericMethod() &&
 				len(deref(n.(*ir.SelectorExpr).X.Type()).RParams()) > 0 &&
 				hasShapeTypes(deref(n.(*ir.SelectorExpr).X.Type()).RParams()) {
 				if n.(*ir.SelectorExpr).X.Op() == ir.OTYPE {
-					infoPrint("  Closure&subdictionary required at generic meth expr %v\n", n)
+					log.Log(n.Pos(), "closure and sub-dictionary required at generic method expression", "expr", n)
 				} else {
-					infoPrint("  Closure&subdictionary required at generic meth value %v\n", n)
+					log.Log(n.Pos(), "closure and sub-dictionary required at generic method value", "value", n)
 				}
 				info.subDictCalls = append(info.subDictCalls, n)
 			}
@@ -1817,14 +1873,14 @@ func (g *genInst) getInstInfo(st *ir.Func, shapes []*types.Type, instInfo *instI
 			if ce.X.Op() == ir.OFUNCINST {
 				callMap[ce.X] = true
 				if hasShapeNodes(ce.X.(*ir.InstExpr).Targs) {
-					infoPrint("  Subdictionary at generic function/method call: %v - %v\n", ce.X.(*ir.InstExpr).X, n)
+					log.Log(n.Pos(), "sub-dictionary at generic function or method call", "callee", ce.X.(*ir.InstExpr).X, "call", n)
 					info.subDictCalls = append(info.subDictCalls, n)
 				}
 			}
 			if ce.X.Op() == ir.OXDOT &&
 				isShapeDeref(ce.X.(*ir.SelectorExpr).X.Type()) {
 				callMap[ce.X] = true
-				infoPrint("  Optional subdictionary at generic bound call: %v\n", n)
+				log.Log(n.Pos(), "optional sub-dictionary at generic bound call", "call", n)
 				info.subDictCalls = append(info.subDictCalls, n)
 			}
 		case ir.OCALLMETH:
@@ -1833,24 +1889,24 @@ func (g *genInst) getInstInfo(st *ir.Func, shapes []*types.Type, instInfo *instI
 				len(deref(ce.X.(*ir.SelectorExpr).X.Type()).RParams()) > 0 {
 				callMap[ce.X] = true
 				if hasShapeTypes(deref(ce.X.(*ir.SelectorExpr).X.Type()).RParams()) {
-					infoPrint("  Subdictionary at generic method call: %v\n", n)
+					log.Log(n.Pos(), "sub-dictionary at generic method call", "call", n)
 					info.subDictCalls = append(info.subDictCalls, n)
 				}
 			}
 		case ir.OCONVIFACE:
 			if n.Type().IsInterface() && !n.Type().IsEmptyInterface() &&
 				n.(*ir.ConvExpr).X.Type().HasShape() {
-				infoPrint("  Itab for interface conv: %v\n", n)
+				log.Log(n.Pos(), "itab for interface conversion", "conv", n)
 				info.itabConvs = append(info.itabConvs, n)
 			}
 		case ir.OXDOT:
 			if n.(*ir.SelectorExpr).X.Type().IsShape() {
-				infoPrint("  Itab for bound call: %v\n", n)
+				log.Log(n.Pos(), "itab for bound call", "call", n)
 				info.itabConvs = append(info.itabConvs, n)
 			}
 		case ir.ODOTTYPE, ir.ODOTTYPE2:
 			if !n.(*ir.TypeAssertExpr).Type().IsInterface() && !n.(*ir.TypeAssertExpr).X.Type().IsEmptyInterface() {
-				infoPrint("  Itab for dot type: %v\n", n)
+				log.Log(n.Pos(), "itab for type assertion", "assert", n)
 				info.itabConvs = append(info.itabConvs, n)
 			}
 		case ir.OCLOSURE:
@@ -1872,7 +1928,7 @@ func (g *genInst) getInstInfo(st *ir.Func, shapes []*types.Type, instInfo *instI
 					for _, c := range cc.List {
 						if c.Op() == ir.OTYPE && c.Type().HasShape() {
 							// Type switch from a non-empty interface - might need an itab.
-							infoPrint("  Itab for type switch: %v\n", c)
+							log.Log(c.Pos(), "itab for type switch case", "case", c)
 							info.itabConvs = append(info.itabConvs, c)
 							if info.type2switchType == nil {
 								info.type2switchType = map[ir.Node]*types.Type{}
@@ -1889,11 +1945,8 @@ func (g *genInst) getInstInfo(st *ir.Func, shapes []*types.Type, instInfo *instI
 	for _, stmt := range st.Body {
 		ir.Visit(stmt, visitFunc)
 	}
-	if infoPrintMode {
-		for _, t := range info.derivedTypes {
-			fmt.Printf("  Derived type %v\n", t)
-		}
-		fmt.Printf(">>> Done Instinfo\n")
+	for _, t := range info.derivedTypes {
+		log.Log(src.NoXPos, "derived type", "type", t)
 	}
 	info.startSubDict = len(info.shapeParams) + len(info.derivedTypes)
 	info.startItabConv = len(info.shapeParams) + len(info.derivedTypes) + len(info.subDictCalls)
@@ -1942,78 +1995,48 @@ func addType(info *dictInfo, n ir.Node, t *types.Type) {
 
 // parameterizedBy returns true if t is parameterized by (at most) params.
 func parameterizedBy(t *types.Type, params []*types.Type) bool {
-	return parameterizedBy1(t, params, map[*types.Type]bool{})
-}
-func parameterizedBy1(t *types.Type, params []*types.Type, visited map[*types.Type]bool) bool {
-	if visited[t] {
-		return true
-	}
-	visited[t] = true
-
-	if t.Sym() != nil && len(t.RParams()) > 0 {
-		// This defined type is instantiated. Check the instantiating types.
-		for _, r := range t.RParams() {
-			if !parameterizedBy1(r, params, visited) {
-				return false
-			}
-		}
-		return true
-	}
-	if t.IsShape() {
-		// Check if t is one of the allowed parameters in scope.
-		for _, p := range params {
-			if p == t {
-				return true
-			}
+	ok := true
+	instantiated := make(map[*types.Type]bool)
+	var check func(t *types.Type) bool
+	check = func(t *types.Type) bool {
+		if !ok {
+			return false
 		}
-		// Couldn't find t in the list of allowed parameters.
-		return false
-
-	}
-	switch t.Kind() {
-	case types.TARRAY, types.TPTR, types.TSLICE, types.TCHAN:
-		return parameterizedBy1(t.Elem(), params, visited)
-
-	case types.TMAP:
-		return parameterizedBy1(t.Key(), params, visited) && parameterizedBy1(t.Elem(), params, visited)
-
-	case types.TFUNC:
-		return parameterizedBy1(t.TParams(), params, visited) && parameterizedBy1(t.Recvs(), params, visited) && parameterizedBy1(t.Params(), params, visited) && parameterizedBy1(t.Results(), params, visited)
-
-	case types.TSTRUCT:
-		for _, f := range t.Fields().Slice() {
-			if !parameterizedBy1(f.Type, params, visited) {
-				return false
+		if t.Sym() != nil && len(t.RParams()) > 0 {
+			// This defined type is instantiated. Check the instantiating types.
+			if !instantiated[t] {
+				instantiated[t] = true
+				for _, r := range t.RParams() {
+					types.Walk(r, check)
+				}
 			}
+			return false
 		}
-		return true
-
-	case types.TINTER:
-		for _, f := range t.Methods().Slice() {
-			if !parameterizedBy1(f.Type, params, visited) {
-				return false
+		if t.IsShape() {
+			// Check if t is one of the allowed parameters in scope.
+			ok = false
+			for _, p := range params {
+				if p == t {
+					ok = true
+					break
+				}
 			}
+			return false
 		}
-		return true
-
-	case types.TINT, types.TINT8, types.TINT16, types.TINT32, types.TINT64,
-		types.TUINT, types.TUINT8, types.TUINT16, types.TUINT32, types.TUINT64,
-		types.TUINTPTR, types.TBOOL, types.TSTRING, types.TFLOAT32, types.TFLOAT64, types.TCOMPLEX64, types.TCOMPLEX128, types.TUNSAFEPTR:
-		return true
-
-	case types.TUNION:
-		for i := 0; i < t.NumTerms(); i++ {
-			tt, _ := t.Term(i)
-			if !parameterizedBy1(tt, params, visited) {
-				return false
-			}
+		switch t.Kind() {
+		case types.TARRAY, types.TPTR, types.TSLICE, types.TCHAN, types.TMAP,
+			types.TFUNC, types.TSTRUCT, types.TINTER, types.TUNION,
+			types.TINT, types.TINT8, types.TINT16, types.TINT32, types.TINT64,
+			types.TUINT, types.TUINT8, types.TUINT16, types.TUINT32, types.TUINT64,
+			types.TUINTPTR, types.TBOOL, types.TSTRING, types.TFLOAT32, types.TFLOAT64, types.TCOMPLEX64, types.TCOMPLEX128, types.TUNSAFEPTR:
+			return true
+		default:
+			base.Fatalf("bad type kind %+v", t)
+			return false
 		}
-		return true
-
-	default:
-		base.Fatalf("bad type kind %+v", t)
-		return true
 	}
+	types.Walk(t, check)
+	return ok
 }
 
 // startClosures starts creation of a closure that has the function type typ. It
@@ -6,6 +6,7 @@ package noder
 
 import (
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/typecheck"
@@ -206,8 +207,12 @@ func (g *irgen) blockStmt(stmt *syntax.BlockStmt) []ir.Node {
 }
 
 func (g *irgen) ifStmt(stmt *syntax.IfStmt) ir.Node {
+	pragma := g.pragmaFlags(stmt.Pragma, ifPragmas)
 	init := g.stmt(stmt.Init)
 	n := ir.NewIfStmt(g.pos(stmt), g.expr(stmt.Cond), g.blockStmt(stmt.Then), nil)
+	if err := setIfLikely(n, pragma); err != "" {
+		base.ErrorfAt(n.Pos(), errcode.InvalidDirective, "%s", err)
+	}
 	if stmt.Else != nil {
 		e := g.stmt(stmt.Else)
 		if e.Op() == ir.OBLOCK {
@@ -234,6 +239,8 @@ func unpackTwo(list []ir.Node) (fst, snd ir.Node) {
 }
 
 func (g *irgen) forStmt(stmt *syntax.ForStmt) ir.Node {
+	unroll := unrollFactor(stmt.Pragma)
+	g.pragmaFlags(stmt.Pragma, forPragmas)
 	if r, ok := stmt.Init.(*syntax.RangeClause); ok {
 		names, lhs := g.assignList(r.Lhs, r.Def)
 		key, value := unpackTwo(lhs)
@@ -245,10 +252,13 @@ func (g *irgen) forStmt(stmt *syntax.ForStmt) ir.Node {
 		if value != nil {
 			transformCheckAssign(n, value)
 		}
+		n.Unroll = unroll
 		return n
 	}
 
-	return ir.NewForStmt(g.pos(stmt), g.stmt(stmt.Init), g.expr(stmt.Cond), g.stmt(stmt.Post), g.blockStmt(stmt.Body))
+	n := ir.NewForStmt(g.pos(stmt), g.stmt(stmt.Init), g.expr(stmt.Cond), g.stmt(stmt.Post), g.blockStmt(stmt.Body))
+	n.Unroll = unroll
+	return n
 }
 
 func (g *irgen) selectStmt(stmt *syntax.SelectStmt) ir.Node {
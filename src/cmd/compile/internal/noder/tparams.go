@@ -0,0 +1,94 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/typecheck"
+	"cmd/compile/internal/types"
+)
+
+// recordInstance records that the generic function or type named by
+// orig was instantiated with the concrete type arguments targs.
+func (g *genInst) recordInstance(orig *types.Sym, targs []*types.Type) {
+	if base.Debug.TParams == 0 || orig == nil {
+		return
+	}
+	if g.instances == nil {
+		g.instances = make(map[*types.Sym][][]*types.Type)
+	}
+	for _, prev := range g.instances[orig] {
+		if identicalLists(prev, targs) {
+			return
+		}
+	}
+	g.instances[orig] = append(g.instances[orig], targs)
+}
+
+func identicalLists(a, b []*types.Type) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !types.Identical(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// DumpTParams prints a summary of type parameter usage for each
+// exported generic function and type of the package being compiled:
+// its type parameters, their constraints, and the instantiations of it
+// that were created during this compilation.
+func DumpTParams() {
+	var buf bytes.Buffer
+	for _, n := range exportedGenerics() {
+		t := n.Type()
+		var tparams []*types.Type
+		if n.Op() == ir.OTYPE {
+			fmt.Fprintf(&buf, "%v: type %v\n", ir.Line(n), n.Sym())
+			tparams = t.RParams()
+		} else {
+			fmt.Fprintf(&buf, "%v: func %v\n", ir.Line(n), n.Sym())
+			for _, f := range t.TParams().FieldSlice() {
+				tparams = append(tparams, f.Type)
+			}
+		}
+		for _, tp := range tparams {
+			fmt.Fprintf(&buf, "\ttparam %v %v\n", tp, tp.Bound())
+		}
+		for _, targs := range geninst.instances[n.Sym()] {
+			fmt.Fprintf(&buf, "\tinstance %v[%s]\n", n.Sym(), types.JoinTypes(targs, ",", 'v'))
+		}
+	}
+	os.Stdout.Write(buf.Bytes())
+}
+
+// exportedGenerics returns the exported generic functions and types of
+// the package being compiled, sorted by position.
+func exportedGenerics() []*ir.Name {
+	var list []*ir.Name
+	for _, n := range typecheck.Target.Exports {
+		if n.Sym().Pkg != types.LocalPkg || n.Type() == nil {
+			continue
+		}
+		switch {
+		case n.Op() == ir.OTYPE && n.Type().IsBaseGeneric(),
+			n.Op() == ir.ONAME && n.Class == ir.PFUNC && n.Type().NumTParams() > 0:
+			list = append(list, n)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Pos().Before(list[j].Pos())
+	})
+	return list
+}
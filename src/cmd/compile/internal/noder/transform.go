@@ -20,6 +20,7 @@ package noder
 
 import (
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/typecheck"
 	"cmd/compile/internal/types"
@@ -91,7 +92,7 @@ func transformConv(n *ir.ConvExpr) ir.Node {
 		// one type-related error that it does not catch. This error will be
 		// caught here by Convertop (see two checks near beginning of
 		// Convertop) and reported at the end of noding.
-		base.ErrorfAt(n.Pos(), "cannot convert %L to type %v%s", n.X, n.Type(), why)
+		base.ErrorfAt(n.Pos(), errcode.InvalidConversion, "cannot convert %L to type %v%s", n.X, n.Type(), why)
 		return n
 	}
 	n.SetOp(op)
@@ -0,0 +1,58 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noder
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types2"
+)
+
+// warnConversions reports base.WarnConversion warnings for the
+// conversions in files, as the type checker recorded them in info
+// when it checked pkg.
+//
+// It warns about converting an integer other than a byte or rune to
+// a string, which yields the UTF-8 encoding of the integer as a code
+// point rather than its decimal digits, a common mistake.
+func warnConversions(m *posMap, files []*syntax.File, pkg *types2.Package, info *types2.Info) {
+	qual := types2.RelativeTo(pkg)
+	for _, file := range files {
+		syntax.Inspect(file, func(n syntax.Node) bool {
+			call, ok := n.(*syntax.CallExpr)
+			if !ok || len(call.ArgList) != 1 || !info.Types[call.Fun].IsType() {
+				return true
+			}
+			to, from := info.Types[call.Fun].Type, info.Types[call.ArgList[0]].Type
+			if from == nil || !isString(to) || !isIntConv(from) {
+				return true
+			}
+			base.WarningfAt(m.makeXPos(syntax.StartPos(call)), base.WarnConversion, "conversion from %s to %s yields a string of one rune, not a string of digits",
+				types2.TypeString(from, qual), types2.TypeString(to, qual))
+			return true
+		})
+	}
+}
+
+// isString reports whether t is a string type.
+func isString(t types2.Type) bool {
+	b, ok := t.Underlying().(*types2.Basic)
+	return ok && b.Info()&types2.IsString != 0
+}
+
+// isIntConv reports whether t is an integer type whose values are not
+// meant as code points when converted to a string, as those of byte
+// and rune are.
+func isIntConv(t types2.Type) bool {
+	b, ok := t.Underlying().(*types2.Basic)
+	if !ok || b.Info()&types2.IsInteger == 0 {
+		return false
+	}
+	switch b.Kind() {
+	case types2.Byte, types2.Rune, types2.UntypedRune:
+		return false
+	}
+	return true
+}
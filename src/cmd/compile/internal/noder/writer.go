@@ -11,6 +11,7 @@ import (
 	"go/constant"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/types2"
@@ -58,8 +59,8 @@ func newPkgWriter(m posMap, pkg *types2.Package, info *types2.Info) *pkgWriter {
 	}
 }
 
-func (pw *pkgWriter) errorf(p poser, msg string, args ...interface{}) {
-	base.ErrorfAt(pw.m.pos(p), msg, args...)
+func (pw *pkgWriter) errorf(p poser, code errcode.Code, msg string, args ...interface{}) {
+	base.ErrorfAt(pw.m.pos(p), code, msg, args...)
 }
 
 func (pw *pkgWriter) fatalf(p poser, msg string, args ...interface{}) {
@@ -711,19 +712,22 @@ func (w *writer) funcExt(obj *types2.Func) {
 
 	pragma := asPragmaFlag(decl.Pragma)
 	if pragma&ir.Systemstack != 0 && pragma&ir.Nosplit != 0 {
-		w.p.errorf(decl, "go:nosplit and go:systemstack cannot be combined")
+		w.p.errorf(decl, errcode.InvalidDirective, "go:nosplit and go:systemstack cannot be combined")
+	}
+	if msg := checkInlineBudget(pragma, inlineBudget(decl.Pragma)); msg != "" {
+		w.p.errorf(decl, errcode.InvalidDirective, "%s", msg)
 	}
 
 	if decl.Body != nil {
 		if pragma&ir.Noescape != 0 {
-			w.p.errorf(decl, "can only use //go:noescape with external func implementations")
+			w.p.errorf(decl, errcode.InvalidDirective, "can only use //go:noescape with external func implementations")
 		}
 	} else {
 		if base.Flag.Complete || decl.Name.Value == "init" {
 			// Linknamed functions are allowed to have no body. Hopefully
 			// the linkname target has a body. See issue 23311.
 			if _, ok := w.p.linknames[obj]; !ok {
-				w.p.errorf(decl, "missing function body")
+				w.p.errorf(decl, errcode.MissingFuncBody, "missing function body")
 			}
 		}
 	}
@@ -734,6 +738,7 @@ func (w *writer) funcExt(obj *types2.Func) {
 
 	w.sync(syncFuncExt)
 	w.pragmaFlag(pragma)
+	w.len(int(inlineBudget(decl.Pragma)))
 	w.linkname(obj)
 	w.bool(false) // stub extension
 	w.reloc(relocBody, body)
@@ -1038,6 +1043,7 @@ func (w *writer) blockStmt(stmt *syntax.BlockStmt) {
 func (w *writer) forStmt(stmt *syntax.ForStmt) {
 	w.sync(syncForStmt)
 	w.openScope(stmt.Pos())
+	w.len(unrollFactor(stmt.Pragma))
 
 	if rang, ok := stmt.Init.(*syntax.RangeClause); w.bool(ok) {
 		w.pos(rang)
@@ -1058,6 +1064,7 @@ func (w *writer) ifStmt(stmt *syntax.IfStmt) {
 	w.sync(syncIfStmt)
 	w.openScope(stmt.Pos())
 	w.pos(stmt)
+	w.pragmaFlag(asPragmaFlag(stmt.Pragma) & ifPragmas)
 	w.stmt(stmt.Init)
 	w.expr(stmt.Cond)
 	w.blockStmt(stmt.Then)
@@ -1488,6 +1495,15 @@ func (c *declCollector) Visit(n syntax.Node) syntax.Visitor {
 	case *syntax.ConstDecl:
 		pw.checkPragmas(n.Pragma, 0, false)
 
+	case *syntax.ForStmt:
+		pw.checkPragmas(n.Pragma, forPragmas, false)
+
+	case *syntax.IfStmt:
+		pw.checkPragmas(n.Pragma, ifPragmas, false)
+		if asPragmaFlag(n.Pragma)&ifPragmas == ifPragmas {
+			pw.errorf(n, errcode.InvalidDirective, "if statement cannot be both //go:likely and //go:unlikely")
+		}
+
 	case *syntax.FuncDecl:
 		pw.checkPragmas(n.Pragma, funcPragmas, false)
 
@@ -1525,7 +1541,7 @@ func (c *declCollector) Visit(n syntax.Node) syntax.Visitor {
 
 		if p, ok := n.Pragma.(*pragmas); ok && len(p.Embeds) > 0 {
 			if err := checkEmbed(n, c.file.importedEmbed, c.withinFunc); err != nil {
-				pw.errorf(p.Embeds[0].Pos, "%s", err)
+				pw.errorf(p.Embeds[0].Pos, errcode.InvalidDirective, "%s", err)
 			}
 		}
 
@@ -1570,7 +1586,7 @@ func (pw *pkgWriter) collectDecls(noders []*noder) {
 
 		for _, l := range p.linknames {
 			if !file.importedUnsafe {
-				pw.errorf(l.pos, "//go:linkname only allowed in Go files that import \"unsafe\"")
+				pw.errorf(l.pos, errcode.InvalidDirective, "//go:linkname only allowed in Go files that import \"unsafe\"")
 				continue
 			}
 
@@ -1579,13 +1595,13 @@ func (pw *pkgWriter) collectDecls(noders []*noder) {
 				if _, ok := pw.linknames[obj]; !ok {
 					pw.linknames[obj] = l.remote
 				} else {
-					pw.errorf(l.pos, "duplicate //go:linkname for %s", l.local)
+					pw.errorf(l.pos, errcode.InvalidDirective, "duplicate //go:linkname for %s", l.local)
 				}
 
 			default:
 				// TODO(mdempsky): Enable after #42938 is fixed.
 				if false {
-					pw.errorf(l.pos, "//go:linkname must refer to declared function or variable")
+					pw.errorf(l.pos, errcode.InvalidDirective, "//go:linkname must refer to declared function or variable")
 				}
 			}
 		}
@@ -1600,13 +1616,13 @@ func (pw *pkgWriter) checkPragmas(p syntax.Pragma, allowed ir.PragmaFlag, embedO
 
 	for _, pos := range pragma.Pos {
 		if pos.Flag&^allowed != 0 {
-			pw.errorf(pos.Pos, "misplaced compiler directive")
+			pw.errorf(pos.Pos, errcode.InvalidDirective, "misplaced compiler directive")
 		}
 	}
 
 	if !embedOK {
 		for _, e := range pragma.Embeds {
-			pw.errorf(e.Pos, "misplaced go:embed directive")
+			pw.errorf(e.Pos, 0, "misplaced go:embed directive")
 		}
 	}
 }
@@ -0,0 +1,197 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pgo reads the CPU profiles that profile-guided optimizations
+// use, as written by runtime/pprof.
+package pgo
+
+import (
+	"fmt"
+	"internal/profile"
+	"os"
+	"sort"
+	"strings"
+)
+
+// A Profile holds the samples of a CPU profile, by function.
+type Profile struct {
+	funcs     map[string]*Func   // functions in the stack of any sample
+	calls     map[CallSite]int64 // samples with each call in their stack
+	callTotal int64              // sum of calls
+	total     int64              // samples
+}
+
+// A CallSite is a call from a line of a function to another function.
+// Calls in code inlined into a function are at the lines of the
+// function they were inlined from, as the compiler sees them before
+// inlining.
+type CallSite struct {
+	Caller string // linker symbol name of the calling function
+	Line   int
+	Callee string // linker symbol name of the called function
+}
+
+// A Func holds the samples of a CPU profile with a function in their
+// stack, by the line of the function that ran or made the call that
+// ran. Samples in code inlined into the function are counted at the
+// line of the call that was inlined, as the compiler sees them before
+// inlining.
+type Func struct {
+	Lines map[int]int64 // samples by line
+	Total int64         // samples with the function in their stack
+}
+
+// Current is the profile read from the -pgoprofile file, or nil.
+var Current *Profile
+
+// New reads the CPU profile in the file at path.
+func New(path string) (*Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	prof, err := profile.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(prof.SampleType) == 0 {
+		return nil, fmt.Errorf("%s: profile has no sample types", path)
+	}
+
+	// CPU profiles have two sample values, samples/count and
+	// cpu/nanoseconds. Use the count, or the first value of other
+	// profiles.
+	index := 0
+	for i, t := range prof.SampleType {
+		if t.Type == "samples" {
+			index = i
+			break
+		}
+	}
+
+	p := &Profile{
+		funcs: make(map[string]*Func),
+		calls: make(map[CallSite]int64),
+	}
+	var frames []profile.Line
+	for _, s := range prof.Sample {
+		if len(s.Location) == 0 || index >= len(s.Value) {
+			continue
+		}
+		frames = frames[:0]
+		for _, loc := range s.Location {
+			for _, line := range loc.Line {
+				if line.Function != nil {
+					frames = append(frames, line)
+				}
+			}
+		}
+		p.addFrames(frames, s.Value[index])
+		p.total += s.Value[index]
+	}
+	return p, nil
+}
+
+// addFrames adds n samples to the lines and calls of the frames of a
+// stack, which run from the innermost. A line or call that is in the
+// stack more than once, as in recursion, is counted once.
+func (p *Profile) addFrames(frames []profile.Line, n int64) {
+	for i, f := range frames {
+		name, line := f.Function.Name, int(f.Line)
+		newFunc, newLine, newCall := true, true, i > 0
+		for j, g := range frames[:i] {
+			if g.Function.Name != name {
+				continue
+			}
+			newFunc = false
+			if int(g.Line) == line {
+				newLine = false
+				if j > 0 && frames[j-1].Function.Name == frames[i-1].Function.Name {
+					newCall = false
+				}
+			}
+		}
+		fn := p.funcs[name]
+		if fn == nil {
+			fn = &Func{Lines: make(map[int]int64)}
+			p.funcs[name] = fn
+		}
+		if newFunc {
+			fn.Total += n
+		}
+		if newLine {
+			fn.Lines[line] += n
+		}
+		if newCall {
+			c := CallSite{Caller: name, Line: line, Callee: frames[i-1].Function.Name}
+			p.calls[c] += n
+			p.callTotal += n
+		}
+	}
+}
+
+// HotCalls returns the hottest calls of the profile, which together
+// are in the stacks of at least cdf percent of the samples of all
+// calls, and the fewest samples of any of them. Calls with as many
+// samples as the coolest of them are hot too.
+func (p *Profile) HotCalls(cdf int) (hot map[CallSite]bool, minSamples int64) {
+	if p == nil || cdf <= 0 || p.callTotal == 0 {
+		return nil, 0
+	}
+	calls := make([]CallSite, 0, len(p.calls))
+	for c := range p.calls {
+		calls = append(calls, c)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return p.calls[calls[i]] > p.calls[calls[j]]
+	})
+	hot = make(map[CallSite]bool)
+	var sum int64
+	for _, c := range calls {
+		n := p.calls[c]
+		if sum*100 >= int64(cdf)*p.callTotal && n < minSamples {
+			break
+		}
+		hot[c] = true
+		sum += n
+		minSamples = n
+	}
+	return hot, minSamples
+}
+
+// CallTotal returns the samples of all calls of the profile, counting
+// each sample once for each call in its stack.
+func (p *Profile) CallTotal() int64 {
+	if p == nil {
+		return 0
+	}
+	return p.callTotal
+}
+
+// Func returns the samples in the function with the given linker
+// symbol name, such as "pkg/path.(*T).M", or nil if it has none.
+func (p *Profile) Func(name string) *Func {
+	if p == nil {
+		return nil
+	}
+	return p.funcs[name]
+}
+
+// coldRatio is the number of samples of a profile for each one, at most,
+// with a cold function in its stack.
+const coldRatio = 1000
+
+// Cold reports whether the function with the given linker symbol name
+// is in the stack of some samples of the profile, but at most one in
+// coldRatio of them. A function in no sample is not cold, as the
+// profile may just not cover its code. Generic functions, whose names
+// in profiles are not those of their instantiations, are never cold.
+func (p *Profile) Cold(name string) bool {
+	if p == nil || strings.Contains(name, "[") {
+		return false
+	}
+	fn := p.funcs[name]
+	return fn != nil && fn.Total > 0 && fn.Total*coldRatio <= p.total
+}
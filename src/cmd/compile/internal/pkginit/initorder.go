@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 )
 
@@ -243,7 +244,7 @@ func reportInitLoopAndExit(l []*ir.Name) {
 	}
 	fmt.Fprintf(&msg, "\t%v: %v", ir.Line(l[0]), l[0])
 
-	base.ErrorfAt(l[0].Pos(), msg.String())
+	base.ErrorfAt(l[0].Pos(), errcode.InvalidInitCycle, msg.String())
 	base.ErrorExit()
 }
 
@@ -5,7 +5,6 @@
 package reflectdata
 
 import (
-	"encoding/binary"
 	"fmt"
 	"os"
 	"sort"
@@ -39,12 +38,14 @@ func CountPTabs() int {
 
 // runtime interface and reflection data structures
 var (
-	// protects signatset and signatslice
+	// protects signatset, signatslice and symPrefixBuf
 	signatmu sync.Mutex
 	// Tracking which types need runtime type descriptor
 	signatset = make(map[*types.Type]struct{})
 	// Queue of types wait to be generated runtime type descriptor
 	signatslice []typeAndStr
+	// Scratch buffer for TypeSymPrefix
+	symPrefixBuf []byte
 
 	gcsymmu  sync.Mutex // protects gcsymset and gcsymslice
 	gcsymset = make(map[*types.Type]struct{})
@@ -309,26 +310,22 @@ func methods(t *types.Type) []*typeSig {
 		// Shape types have no methods.
 		return nil
 	}
-	// method type
-	mt := types.ReceiverBaseType(t)
-
-	if mt == nil {
+	if t.IsInterface() {
 		return nil
 	}
-	typecheck.CalcMethods(mt)
 
 	// make list of methods for t,
 	// generating code if necessary.
 	var ms []*typeSig
-	for _, f := range mt.AllMethods().Slice() {
+	for _, f := range types.ComputeMethodSet(t) {
 		if f.Sym == nil {
-			base.Fatalf("method with no sym on %v", mt)
+			base.Fatalf("method with no sym on %v", t)
 		}
 		if !f.IsMethod() {
-			base.Fatalf("non-method on %v method %v %v", mt, f.Sym, f)
+			base.Fatalf("non-method on %v method %v %v", t, f.Sym, f)
 		}
 		if f.Type.Recv() == nil {
-			base.Fatalf("receiver with no type on %v method %v %v", mt, f.Sym, f)
+			base.Fatalf("receiver with no type on %v method %v %v", t, f.Sym, f)
 		}
 		if f.Nointerface() && !t.IsFullyInstantiated() {
 			// Skip creating method wrappers if f is nointerface. But, if
@@ -338,14 +335,6 @@ func methods(t *types.Type) []*typeSig {
 			continue
 		}
 
-		// get receiver type for this particular method.
-		// if pointer receiver but non-pointer t and
-		// this is not an embedded pointer inside a struct,
-		// method does not apply.
-		if !types.IsMethodApplicable(t, f) {
-			continue
-		}
-
 		sig := &typeSig{
 			name:  f.Sym,
 			isym:  methodWrapper(t, f, true),
@@ -415,6 +404,7 @@ func dimportpath(p *types.Pkg) {
 		// Note: myimportpath != "", or else dgopkgpath won't call dimportpath.
 		str = base.Ctxt.Pkgpath
 	}
+	str = types.LinkPkgPath(str)
 
 	s := base.Ctxt.Lookup("type..importpath." + p.Prefix + ".")
 	ot := dnameData(s, 0, str, "", nil, false)
@@ -472,41 +462,7 @@ func dnameField(lsym *obj.LSym, ot int, spkg *types.Pkg, ft *types.Field) int {
 
 // dnameData writes the contents of a reflect.name into s at offset ot.
 func dnameData(s *obj.LSym, ot int, name, tag string, pkg *types.Pkg, exported bool) int {
-	if len(name) >= 1<<29 {
-		base.Fatalf("name too long: %d %s...", len(name), name[:1024])
-	}
-	if len(tag) >= 1<<29 {
-		base.Fatalf("tag too long: %d %s...", len(tag), tag[:1024])
-	}
-	var nameLen [binary.MaxVarintLen64]byte
-	nameLenLen := binary.PutUvarint(nameLen[:], uint64(len(name)))
-	var tagLen [binary.MaxVarintLen64]byte
-	tagLenLen := binary.PutUvarint(tagLen[:], uint64(len(tag)))
-
-	// Encode name and tag. See reflect/type.go for details.
-	var bits byte
-	l := 1 + nameLenLen + len(name)
-	if exported {
-		bits |= 1 << 0
-	}
-	if len(tag) > 0 {
-		l += tagLenLen + len(tag)
-		bits |= 1 << 1
-	}
-	if pkg != nil {
-		bits |= 1 << 2
-	}
-	b := make([]byte, l)
-	b[0] = bits
-	copy(b[1:], nameLen[:nameLenLen])
-	copy(b[1+nameLenLen:], name)
-	if len(tag) > 0 {
-		tb := b[1+nameLenLen+len(name):]
-		copy(tb, tagLen[:tagLenLen])
-		copy(tb[tagLenLen:], tag)
-	}
-
-	ot = int(s.WriteBytes(base.Ctxt, int64(ot), b))
+	ot = int(s.WriteBytes(base.Ctxt, int64(ot), types.NameData(name, tag, exported, pkg != nil)))
 
 	if pkg != nil {
 		ot = dgopkgpathOff(s, ot, pkg)
@@ -515,39 +471,30 @@ func dnameData(s *obj.LSym, ot int, name, tag string, pkg *types.Pkg, exported b
 	return ot
 }
 
-var dnameCount int
-
 // dname creates a reflect.name for a struct field or method.
 func dname(name, tag string, pkg *types.Pkg, exported bool) *obj.LSym {
 	// Write out data as "type.." to signal two things to the
 	// linker, first that when dynamically linking, the symbol
 	// should be moved to a relro section, and second that the
 	// contents should not be decoded as a type.
-	sname := "type..namedata."
-	if pkg == nil {
-		// In the common case, share data with other packages.
-		if name == "" {
-			if exported {
-				sname += "-noname-exported." + tag
-			} else {
-				sname += "-noname-unexported." + tag
-			}
-		} else {
-			if exported {
-				sname += name + "." + tag
-			} else {
-				sname += name + "-" + tag
-			}
-		}
-	} else {
-		sname = fmt.Sprintf(`%s"".%d`, sname, dnameCount)
-		dnameCount++
+	//
+	// The symbol is named by the hash of its contents, so that
+	// identical names are shared with other packages. Names
+	// that refer to a package path are qualified by it.
+	data := types.NameData(name, tag, exported, pkg != nil)
+	h := types.NameDataHash(data)
+	sname := "type..namedata." + h
+	if pkg != nil {
+		sname = "type..namedata." + pkg.Prefix + "." + h
 	}
 	s := base.Ctxt.Lookup(sname)
 	if len(s.P) > 0 {
 		return s
 	}
-	ot := dnameData(s, 0, name, tag, pkg, exported)
+	ot := int(s.WriteBytes(base.Ctxt, 0, data))
+	if pkg != nil {
+		ot = dgopkgpathOff(s, ot, pkg)
+	}
 	objw.Global(s, int32(ot), obj.DUPOK|obj.RODATA)
 	s.Set(obj.AttrContentAddressable, true)
 	return s
@@ -801,16 +748,18 @@ func TrackSym(t *types.Type, f *types.Field) *obj.LSym {
 }
 
 func TypeSymPrefix(prefix string, t *types.Type) *types.Sym {
-	p := prefix + "." + t.LinkString()
-	s := types.TypeSymLookup(p)
+	signatmu.Lock()
+	symPrefixBuf = append(symPrefixBuf[:0], prefix...)
+	symPrefixBuf = append(symPrefixBuf, '.')
+	symPrefixBuf = types.AppendLinkName(symPrefixBuf, t)
+	s := types.TypeSymLookupBytes(symPrefixBuf)
 
 	// This function is for looking up type-related generated functions
 	// (e.g. eq and hash). Make sure they are indeed generated.
-	signatmu.Lock()
 	NeedRuntimeType(t)
 	signatmu.Unlock()
 
-	//print("algsym: %s -> %+S\n", p, s);
+	//print("algsym: %s -> %+S\n", s.Name, s);
 
 	return s
 }
@@ -1904,9 +1853,14 @@ func methodWrapper(rcvr *types.Type, method *types.Field, forItab bool) *obj.LSy
 			args = append(args, ir.ParamNames(tfn.Type())...)
 
 			// Target method uses shaped names.
+			stencil := genericMethodPragma(deref(rcvr), method.Sym)&ir.Stencil != 0
 			targs2 := make([]*types.Type, len(targs))
 			for i, t := range targs {
-				targs2[i] = typecheck.Shapify(t, i)
+				if stencil {
+					targs2[i] = typecheck.StencilShape(t, i)
+				} else {
+					targs2[i] = typecheck.Shapify(t, i)
+				}
 			}
 			targs = targs2
 
@@ -2074,3 +2028,21 @@ func deref(t *types.Type) *types.Type {
 	}
 	return t
 }
+
+// genericMethodPragma returns the pragmas of the method of the generic
+// type that t, a fully-instantiated type, is an instantiation of, with
+// the given symbol.
+func genericMethodPragma(t *types.Type, sym *types.Sym) ir.PragmaFlag {
+	orig := t.OrigSym()
+	if orig == nil || orig.Def == nil {
+		return 0
+	}
+	for _, m := range orig.Def.Type().Methods().Slice() {
+		if m.Sym.Name == sym.Name && m.Nname != nil {
+			if fn := m.Nname.(*ir.Name).Func; fn != nil {
+				return fn.Pragma
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+)
+
+// aliasMaxWalk bounds how many stores the users of the alias oracle
+// look through for each load, to keep compile time linear in
+// pathological functions.
+const aliasMaxWalk = 50
+
+// An aliasUser is a pass that queries the alias oracle.
+type aliasUser uint8
+
+const (
+	aliasCSE aliasUser = iota
+	aliasDSE
+	aliasGVN
+	aliasSchedule
+
+	aliasUsers
+)
+
+// aliasStats counts the queries of the alias oracle of a function, and
+// how many of them it answered with no alias, for each user.
+type aliasStats [aliasUsers]struct{ queries, noAlias int }
+
+// A memAccess is the memory that a load or store reads or writes:
+// size bytes at ptr+off, or at the offset off of the variable sym
+// that ptr, SP or SB, is the base of. Only lowered ops have a sym or
+// an offset.
+type memAccess struct {
+	ptr   *Value
+	sym   Sym
+	off   int64
+	size  int64
+	write bool
+}
+
+// memAccessOf returns the memory that v accesses, and whether v is a
+// load or store that the alias oracle knows. Loads and stores of
+// lowered ops take their sizes from the types of their results and
+// of the stored values, which may be larger than the bytes they access
+// but never smaller.
+func memAccessOf(v *Value) (memAccess, bool) {
+	switch v.Op {
+	case OpLoad:
+		return memAccess{ptr: v.Args[0], size: v.Type.Size()}, true
+	case OpStore:
+		return memAccess{ptr: v.Args[0], size: v.Aux.(*types.Type).Size(), write: true}, true
+	case OpZero, OpMove:
+		return memAccess{ptr: v.Args[0], size: v.AuxInt, write: true}, true
+	}
+	info := &opcodeTable[v.Op]
+	if info.generic || info.auxType != auxSymOff || info.hasSideEffects || v.Type.IsTuple() {
+		return memAccess{}, false
+	}
+	sym, ok := v.Aux.(Sym)
+	if !ok && v.Aux != nil {
+		return memAccess{}, false
+	}
+	switch {
+	case info.symEffect == SymRead && len(v.Args) == 2 && !v.Type.IsMemory() && v.Args[1].Type.IsMemory():
+		return memAccess{ptr: v.Args[0], sym: sym, off: v.AuxInt, size: v.Type.Size()}, true
+	case info.symEffect == SymWrite && len(v.Args) == 3 && v.Type.IsMemory():
+		return memAccess{ptr: v.Args[0], sym: sym, off: v.AuxInt, size: v.Args[1].Type.Size(), write: true}, true
+	}
+	return memAccess{}, false
+}
+
+// mayAlias reports whether the memory accesses a and b may overlap,
+// and counts the query for user.
+func (f *Func) mayAlias(user aliasUser, a, b memAccess) bool {
+	s := &f.aliasStats[user]
+	s.queries++
+	if noAlias(a, b) {
+		s.noAlias++
+		return false
+	}
+	return true
+}
+
+// noAlias reports whether the memory accesses a and b do not overlap.
+// A return value of false does not imply that they overlap.
+//
+// Accesses through the same base pointer, after their constant
+// offsets, as of the fields of a struct, overlap only if their offset
+// ranges do. Accesses through different base pointers do not overlap
+// if the bases are different variables, autos or globals, as are the
+// different syms of the lowered accesses at SP and SB. An auto whose
+// address is not taken is only accessed through its LocalAddr, so it
+// does not overlap anything that a pointer loaded from memory points
+// to.
+func noAlias(a, b memAccess) bool {
+	if a.size == 0 || b.size == 0 {
+		return true
+	}
+	if a.sym == nil && b.sym == nil && disjoint(a.ptr, a.size, b.ptr, b.size) {
+		return true
+	}
+	pa, oa := aliasBase(a.ptr)
+	pb, ob := aliasBase(b.ptr)
+	if a.sym == b.sym {
+		if pa == pb || a.sym == nil && isSamePtr(pa, pb) {
+			return !overlap(oa+a.off, a.size, ob+b.off, b.size)
+		}
+	} else if a.sym != nil && b.sym != nil && pa.Op == pb.Op && (pa.Op == OpSP || pa.Op == OpSB) {
+		return true
+	}
+	if a.sym == nil && b.sym == nil {
+		ra, rb := aliasRoot(pa), aliasRoot(pb)
+		return unaddressedAuto(ra) && rb.Op == OpLoad || unaddressedAuto(rb) && ra.Op == OpLoad
+	}
+	return false
+}
+
+// aliasBase returns the base pointer of p and the constant offset of
+// p from it.
+func aliasBase(p *Value) (base *Value, off int64) {
+	for {
+		switch {
+		case p.Op == OpOffPtr:
+			off += p.AuxInt
+		case p.Op == OpAddPtr && p.Args[1].Op == OpConst64:
+			off += p.Args[1].AuxInt
+		case p.Op == OpAddPtr && p.Args[1].Op == OpConst32:
+			off += int64(int32(p.Args[1].AuxInt))
+		default:
+			return p, off
+		}
+		p = p.Args[0]
+	}
+}
+
+// aliasRoot returns the pointer that p is derived from by pointer
+// arithmetic, which points into the same variable or object as p.
+func aliasRoot(p *Value) *Value {
+	for p.Op == OpOffPtr || p.Op == OpAddPtr {
+		p = p.Args[0]
+	}
+	return p
+}
+
+// unaddressedAuto reports whether p is the address of an auto whose
+// address is not taken.
+func unaddressedAuto(p *Value) bool {
+	if p.Op != OpLocalAddr {
+		return false
+	}
+	n, ok := p.Aux.(*ir.Name)
+	return ok && n.Class == ir.PAUTO && !n.Addrtaken()
+}
+
+// nonNilPtr reports whether p points into a variable, an auto or a
+// global, and so cannot be nil.
+func nonNilPtr(p *Value) bool {
+	switch aliasRoot(p).Op {
+	case OpSP, OpSB, OpLocalAddr, OpAddr:
+		return true
+	}
+	return false
+}
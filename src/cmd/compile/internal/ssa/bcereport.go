@@ -0,0 +1,221 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/logopt"
+	"cmd/internal/src"
+)
+
+// The -d=bce report lists every bounds check of the compiled functions,
+// with whether it is kept or removed and why. A function's checks are
+// followed from the start of SSA construction to the lower pass, which
+// turns the remaining ones into machine comparisons: after each pass,
+// the checks that are no longer in the function were removed by it.
+// Passes that know why they removed a check, such as prove, note the
+// reason with bceRemoved first.
+//
+// The checks are reported with reportCheck, as are those of the other
+// reports in report.go.
+
+// A bceTracker follows the bounds checks of a function.
+type bceTracker struct {
+	checks  []*bceCheck
+	byValue map[*Value]*bceCheck // the checks still in the function
+	inds    map[*Value]bool      // induction variables found by prove
+}
+
+// A bceCheck is a bounds check followed for -d=bce.
+type bceCheck struct {
+	pos    src.XPos
+	kind   string    // "index" or "slice"
+	args   [2]*Value // the index and length when last seen
+	reason string    // why the check was removed, if it was
+}
+
+// constArgs reports whether the index and length of c are constants,
+// as they may have become in the pass that removed c.
+func (c *bceCheck) constArgs() bool {
+	return c.args[0] != nil && c.args[0].isGenericIntConst() && c.args[1].isGenericIntConst()
+}
+
+// bceKind returns the kind of the bounds check v, or "" if v is not one.
+func bceKind(v *Value) string {
+	switch v.Op {
+	case OpIsInBounds:
+		return "index"
+	case OpIsSliceInBounds:
+		return "slice"
+	}
+	return ""
+}
+
+// bceTrack returns the tracker of f's bounds checks, creating it if
+// needed.
+func (f *Func) bceTrack() *bceTracker {
+	if f.bce == nil {
+		f.bce = &bceTracker{byValue: make(map[*Value]*bceCheck)}
+	}
+	return f.bce
+}
+
+// bceEnabled reports whether the bounds checks are reported, with
+// -d=bce or -json.
+func bceEnabled() bool {
+	return base.Debug.BCE != 0 || logopt.Enabled()
+}
+
+// NoteElidedBoundsCheck records, for -d=bce, that the front end did not
+// generate the bounds check of kind at pos, for reason.
+func (f *Func) NoteElidedBoundsCheck(pos src.XPos, kind BoundsKind, reason string) {
+	if !bceEnabled() {
+		return
+	}
+	k := "slice"
+	switch kind {
+	case BoundsIndex, BoundsIndexU:
+		k = "index"
+	}
+	t := f.bceTrack()
+	t.checks = append(t.checks, &bceCheck{pos: pos, kind: k, reason: reason})
+}
+
+// bceRemoved records that the bounds check v is about to be removed,
+// for reason.
+func (f *Func) bceRemoved(v *Value, reason string) {
+	if f.bce == nil {
+		return
+	}
+	if c := f.bce.byValue[v]; c != nil && c.reason == "" {
+		c.reason = reason
+	}
+}
+
+// bceProved records that prove found the bounds check v to always
+// succeed, if proved is set, or to always fail.
+func (f *Func) bceProved(v *Value, proved bool) {
+	if f.bce == nil || bceKind(v) == "" {
+		return
+	}
+	switch {
+	case !proved:
+		f.bceRemoved(v, "index always out of range")
+	case f.bce.inds[bceIndex(v)]:
+		f.bceRemoved(v, "induction variable proved in range")
+	default:
+		f.bceRemoved(v, "proved in range by dominating conditions")
+	}
+}
+
+// bceIndex returns the index checked by the bounds check v, without
+// any extensions.
+func bceIndex(v *Value) *Value {
+	idx := v.Args[0]
+	for {
+		switch idx.Op {
+		case OpZeroExt8to16, OpZeroExt8to32, OpZeroExt8to64, OpZeroExt16to32, OpZeroExt16to64, OpZeroExt32to64,
+			OpSignExt8to16, OpSignExt8to32, OpSignExt8to64, OpSignExt16to32, OpSignExt16to64, OpSignExt32to64:
+			idx = idx.Args[0]
+		default:
+			return idx
+		}
+	}
+}
+
+// update records the bounds checks that are new in f, and the reasons
+// for removing those that are gone, after the pass named pass. Checks
+// that are no longer used, as after cse replaced them with another,
+// are gone even if dead code elimination has not removed them yet.
+func (t *bceTracker) update(f *Func, pass string) {
+	present := make(map[*Value]bool)
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			kind := bceKind(v)
+			if kind == "" || v.Uses == 0 {
+				continue
+			}
+			present[v] = true
+			c := t.byValue[v]
+			if c == nil {
+				c = &bceCheck{pos: v.Pos, kind: kind}
+				t.checks = append(t.checks, c)
+				t.byValue[v] = c
+			}
+			c.args = [2]*Value{v.Args[0], v.Args[1]}
+		}
+	}
+	for v, c := range t.byValue {
+		if present[v] {
+			continue
+		}
+		delete(t.byValue, v)
+		if c.reason != "" {
+			continue
+		}
+		switch {
+		case c.constArgs():
+			c.reason = "constant index in range"
+		case strings.HasSuffix(pass, "cse"):
+			c.reason = "same as another check"
+		case strings.HasSuffix(pass, "deadcode"):
+			c.reason = "unreachable or unused"
+		default:
+			c.reason = "simplified by " + pass
+		}
+	}
+}
+
+// report reports the bounds checks of f, which is about to be lowered.
+func (t *bceTracker) report(f *Func) {
+	inds := make(map[*Value]bool)
+	if len(t.byValue) > 0 {
+		for _, iv := range findIndVar(f) {
+			inds[iv.ind] = true
+		}
+	}
+	kept := make(map[*bceCheck]string)
+	for v, c := range t.byValue {
+		kept[c] = bceKeptReason(v, inds, f.Config.optimize)
+	}
+
+	for _, c := range t.checks {
+		reason, isKept := kept[c]
+		if !isKept {
+			reason = c.reason
+		}
+		f.reportCheck(base.Debug.BCE, checkReport{
+			pos:    c.pos,
+			what:   c.kind + " bounds check",
+			Report: "bce",
+			Kind:   c.kind,
+			Kept:   isKept,
+			Reason: reason,
+		})
+	}
+}
+
+// bceKeptReason returns why the bounds check v could not be removed.
+// inds holds the induction variables of v's function.
+func bceKeptReason(v *Value, inds map[*Value]bool, optimize bool) string {
+	idx, n := bceIndex(v), v.Args[1]
+	rel := "<"
+	if v.Op == OpIsSliceInBounds {
+		rel = "<="
+	}
+	switch {
+	case !optimize:
+		return "optimizations disabled"
+	case idx.isGenericIntConst() && !n.isGenericIntConst():
+		return "len unknown"
+	case inds[idx]:
+		return "induction variable not provably " + rel + " len"
+	case v.Op == OpIsSliceInBounds:
+		return "slice index not provably <= len or cap"
+	}
+	return "index not provably < len"
+}
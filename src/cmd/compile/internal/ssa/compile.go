@@ -6,6 +6,7 @@ package ssa
 
 import (
 	"bytes"
+	"cmd/compile/internal/base"
 	"cmd/internal/src"
 	"fmt"
 	"hash/crc32"
@@ -64,9 +65,15 @@ func Compile(f *Func) {
 	if BuildDump[f.Name] {
 		f.dumpFile("build")
 	}
+	if BuildJSON[f.Name] {
+		f.dumpJSON("build")
+	}
 	if checkEnabled {
 		checkFunc(f)
 	}
+	if bceEnabled() {
+		f.bceTrack().update(f, "start")
+	}
 	const logMemStats = false
 	for _, p := range passes {
 		if !f.Config.optimize && !p.required || p.disabled {
@@ -77,6 +84,15 @@ func Compile(f *Func) {
 		if f.Log() {
 			f.Logf("  pass %s begin\n", p.name)
 		}
+		if f.bce != nil && p.name == "lower" {
+			// Lowering turns bounds checks into comparisons.
+			f.bce.report(f)
+			f.bce = nil
+		}
+		if base.Debug.NilCheckReport != 0 && p.name == "flagalloc" {
+			// The nil checks that remain by now are generated.
+			reportNilChecks(f)
+		}
 		// TODO: capture logging during this pass, add it to the HTML
 		var mStart runtime.MemStats
 		if logMemStats || p.mem {
@@ -118,6 +134,10 @@ func Compile(f *Func) {
 			}
 			f.HTMLWriter.WritePhase(phaseName, fmt.Sprintf("%s <span class=\"stats\">%s</span>", phaseName, stats))
 		}
+		if f.regallocTrace != nil {
+			f.regallocTrace.write(f)
+			f.regallocTrace = nil
+		}
 		if p.time || p.mem {
 			// Surround timing information w/ enough context to allow comparisons.
 			time := tEnd.Sub(tStart).Nanoseconds()
@@ -136,6 +156,21 @@ func Compile(f *Func) {
 			// Dump function to appropriately named file
 			f.dumpFile(phaseName)
 		}
+		if p.json != nil && p.json[f.Name] {
+			f.dumpJSON(phaseName)
+		}
+		if f.bce != nil {
+			f.bce.update(f, p.name)
+		}
+		if buildcfg.Experiment.SSAHooks {
+			for _, h := range analysisHooks {
+				if h.phases[p.name] {
+					phaseName = p.name + " (" + h.Name + " hook)"
+					h.Run(&AnalysisPass{Func: f, Phase: p.name, hook: h})
+				}
+			}
+			phaseName = p.name
+		}
 		if checkEnabled {
 			checkFunc(f)
 		}
@@ -168,8 +203,13 @@ func Compile(f *Func) {
 // DumpFileForPhase creates a file from the function name and phase name,
 // warning and returning nil if this is not possible.
 func (f *Func) DumpFileForPhase(phaseName string) io.WriteCloser {
+	return f.createDumpFile(phaseName, "dump")
+}
+
+// createDumpFile is DumpFileForPhase for a file with the suffix ext.
+func (f *Func) createDumpFile(phaseName, ext string) io.WriteCloser {
 	f.dumpFileSeq++
-	fname := fmt.Sprintf("%s_%02d__%s.dump", f.Name, int(f.dumpFileSeq), phaseName)
+	fname := fmt.Sprintf("%s_%02d__%s.%s", f.Name, int(f.dumpFileSeq), phaseName, ext)
 	fname = strings.Replace(fname, " ", "_", -1)
 	fname = strings.Replace(fname, "/", "_", -1)
 	fname = strings.Replace(fname, ":", "_", -1)
@@ -209,6 +249,7 @@ type pass struct {
 	debug    int             // pass performs some debugging. =1 should be in error-testing-friendly Warnl format.
 	test     int             // pass-specific ad-hoc option, perhaps useful in development
 	dump     map[string]bool // dump if function name matches
+	json     map[string]bool // dump as JSON if function name matches
 }
 
 func (p *pass) addDump(s string) {
@@ -218,6 +259,13 @@ func (p *pass) addDump(s string) {
 	p.dump[s] = true
 }
 
+func (p *pass) addJSON(s string) {
+	if p.json == nil {
+		p.json = make(map[string]bool)
+	}
+	p.json[s] = true
+}
+
 func (p *pass) String() string {
 	if p == nil {
 		return "nil pass"
@@ -239,6 +287,7 @@ var BuildDebug int
 var BuildTest int
 var BuildStats int
 var BuildDump map[string]bool = make(map[string]bool) // names of functions to dump after initial build of ssa
+var BuildJSON map[string]bool = make(map[string]bool) // names of functions to dump as JSON after initial build of ssa
 
 var GenssaDump map[string]bool = make(map[string]bool) // names of functions to dump after ssa has been converted to asm
 
@@ -285,19 +334,22 @@ where:
 ` + phasenames + `
 
 - <flag> is one of:
-    on, off, debug, mem, time, test, stats, dump, seed
+    on, off, debug, mem, time, test, stats, dump, json, seed
 
 - <value> defaults to 1
 
-- <function_name> is required for the "dump" flag, and specifies the
-  name of function to dump after <phase>
+- <function_name> is required for the "dump" and "json" flags, and
+  specifies the name of function to dump after <phase>
 
-Phase "all" supports flags "time", "mem", and "dump".
+Phase "all" supports flags "time", "mem", "dump", and "json".
 Phase "intrinsics" supports flags "on", "off", and "debug".
 Phase "genssa" (assembly generation) supports the flag "dump".
 
 If the "dump" flag is specified, the output is written on a file named
-<phase>__<function_name>_<seq>.dump; otherwise it is directed to stdout.
+<function_name>_<seq>__<phase>.dump; otherwise it is directed to stdout.
+The "json" flag writes the function's blocks and values as JSON, on a
+file named <function_name>_<seq>__<phase>.json. Both files are created
+in the directory $GOSSADIR, if set.
 
 Examples:
 
@@ -314,6 +366,9 @@ enables time reporting for all phases
     -d=ssa/prove/debug=2
 sets debugging level to 2 in the prove pass
 
+    -d=ssa/lower/json=Foo
+writes function Foo as JSON after the lower pass
+
 Be aware that when "/debug=X" is applied to a pass, some passes
 will emit debug output for all functions, and other passes will
 only emit debug output for functions that match the current
@@ -347,6 +402,7 @@ commas. For example:
 	alltime := false
 	allmem := false
 	alldump := false
+	alljson := false
 	if phase == "all" {
 		switch flag {
 		case "time":
@@ -359,8 +415,13 @@ commas. For example:
 				BuildDump[valString] = true
 				GenssaDump[valString] = true
 			}
+		case "json":
+			alljson = val != 0
+			if alljson {
+				BuildJSON[valString] = true
+			}
 		default:
-			return fmt.Sprintf("Did not find a flag matching %s in -d=ssa/%s debug option (expected ssa/all/{time,mem,dump=function_name})", flag, phase)
+			return fmt.Sprintf("Did not find a flag matching %s in -d=ssa/%s debug option (expected ssa/all/{time,mem,dump=function_name,json=function_name})", flag, phase)
 		}
 	}
 
@@ -387,8 +448,10 @@ commas. For example:
 			BuildStats = val
 		case "dump":
 			BuildDump[valString] = true
+		case "json":
+			BuildJSON[valString] = true
 		default:
-			return fmt.Sprintf("Did not find a flag matching %s in -d=ssa/%s debug option (expected ssa/build/{debug,test,stats,dump=function_name})", flag, phase)
+			return fmt.Sprintf("Did not find a flag matching %s in -d=ssa/%s debug option (expected ssa/build/{debug,test,stats,dump=function_name,json=function_name})", flag, phase)
 		}
 		return ""
 	}
@@ -419,6 +482,9 @@ commas. For example:
 			if alldump {
 				p.addDump(valString)
 			}
+			if alljson {
+				p.addJSON(valString)
+			}
 			passes[i] = p
 			matchedOne = true
 		} else if p.name == phase || p.name == underphase || re != nil && re.MatchString(p.name) {
@@ -439,6 +505,8 @@ commas. For example:
 				p.test = val
 			case "dump":
 				p.addDump(valString)
+			case "json":
+				p.addJSON(valString)
 			default:
 				return fmt.Sprintf("Did not find a flag matching %s in -d=ssa/%s debug option", flag, phase)
 			}
@@ -469,6 +537,7 @@ var passes = [...]pass{
 	{name: "zero arg cse", fn: zcse, required: true},     // required to merge OpSB values
 	{name: "opt deadcode", fn: deadcode, required: true}, // remove any blocks orphaned during opt
 	{name: "generic cse", fn: cse},
+	{name: "gvn", fn: gvn},
 	{name: "phiopt", fn: phiopt},
 	{name: "gcse deadcode", fn: deadcode, required: true}, // clean out after cse and phiopt
 	{name: "nilcheckelim", fn: nilcheckelim},
@@ -483,6 +552,9 @@ var passes = [...]pass{
 	{name: "check bce", fn: checkbce},
 	{name: "branchelim", fn: branchelim},
 	{name: "late fuse", fn: fuseLate},
+	{name: "strength reduce", fn: strengthReduce},
+	{name: "vectorize", fn: vectorize},
+	{name: "unroll", fn: unroll},
 	{name: "dse", fn: dse},
 	{name: "writebarrier", fn: writebarrier, required: true}, // expand write barrier ops
 	{name: "insert resched checks", fn: insertLoopReschedChecks,
@@ -528,6 +600,8 @@ var passOrder = [...]constraint{
 	{"insert resched checks", "tighten"},
 
 	// prove relies on common-subexpression elimination for maximum benefits.
+	// gvn numbers loads that cse could not merge
+	{"generic cse", "gvn"},
 	{"generic cse", "prove"},
 	// deadcode after prove to eliminate all new dead blocks.
 	{"prove", "generic deadcode"},
@@ -545,6 +619,12 @@ var passOrder = [...]constraint{
 	// tighten will be most effective when as many values have been removed as possible
 	{"generic deadcode", "tighten"},
 	{"generic cse", "tighten"},
+	// strength reduce leaves the bounds checks of the loop index to prove
+	{"prove", "strength reduce"},
+	// unroll copies only loop bodies whose bounds checks prove removed,
+	// and leaves the loops that vectorize does not handle
+	{"prove", "unroll"},
+	{"vectorize", "unroll"},
 	// checkbce needs the values removed
 	{"generic deadcode", "check bce"},
 	// don't run optimization pass until we've decomposed builtin objects
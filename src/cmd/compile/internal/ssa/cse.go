@@ -30,6 +30,11 @@ func cse(f *Func) {
 	// It starts with a coarse partition and iteratively refines it
 	// until it reaches a fixed point.
 
+	// Loads of the same address from memory states that differ only
+	// by stores that do not alias it are equivalent too, once they
+	// read the earlier state.
+	relaxLoadMem(f)
+
 	// Make initial coarse partitions by using a subset of the conditions above.
 	a := make([]*Value, 0, f.NumValues())
 	if f.auxmap == nil {
@@ -228,6 +233,38 @@ func cse(f *Func) {
 	}
 }
 
+// relaxLoadMem makes each load of an auto or a global read the
+// earliest memory state of its block that has the same contents at the
+// loaded address, looking back through the stores that the alias
+// oracle finds do not alias the load. The memory states stay in the
+// load's block, since the schedule orders loads only before the stores
+// of their own block, and do not go back past stores that may need
+// write barriers, since the writebarrier pass splits the block there.
+// Loads through other pointers are left alone, as their nil checks may
+// read a later memory state, and they must not be scheduled before
+// their nil checks.
+func relaxLoadMem(f *Func) {
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if v.Op != OpLoad || v.Uses == 0 || !nonNilPtr(v.Args[0]) {
+				continue
+			}
+			ld, _ := memAccessOf(v)
+			m := v.Args[1]
+			for i := 0; i < aliasMaxWalk && m.Block == b; i++ {
+				st, ok := memAccessOf(m)
+				if !ok || needwb(m, nil) || m.MemoryArg().Block != b || f.mayAlias(aliasCSE, ld, st) {
+					break
+				}
+				m = m.MemoryArg()
+			}
+			if m != v.Args[1] {
+				v.SetArg(1, m)
+			}
+		}
+	}
+}
+
 // An eqclass approximates an equivalence class. During the
 // algorithm it may represent the union of several of the
 // final equivalence classes.
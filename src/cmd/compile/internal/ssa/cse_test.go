@@ -129,3 +129,40 @@ func TestZCSE(t *testing.T) {
 		t.Errorf("zsce should have removed sb1 or sb2")
 	}
 }
+
+// TestCSELoadAcrossStore tests that cse merges loads from the same
+// address across a store that does not alias them.
+func TestCSELoadAcrossStore(t *testing.T) {
+	c := testConfig(t)
+	ptrType := c.config.Types.Int64.PtrTo()
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("start", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+			Valu("v", OpConst64, c.config.Types.Int64, 1, nil),
+			Valu("addr", OpAddr, ptrType, 0, nil, "sb"),
+			Valu("a", OpOffPtr, ptrType, 0, nil, "addr"),
+			Valu("b", OpOffPtr, ptrType, 8, nil, "addr"),
+			Valu("load1", OpLoad, c.config.Types.Int64, 0, nil, "b", "start"),
+			Valu("store1", OpStore, types.TypeMem, 0, c.config.Types.Int64, "a", "v", "start"),
+			Valu("load2", OpLoad, c.config.Types.Int64, 0, nil, "b", "store1"),
+			Valu("store2", OpStore, types.TypeMem, 0, c.config.Types.Int64, "b", "v", "store1"),
+			Valu("load3", OpLoad, c.config.Types.Int64, 0, nil, "b", "store2"),
+			Valu("sum1", OpAdd64, c.config.Types.Int64, 0, nil, "load1", "load2"),
+			Valu("sum2", OpAdd64, c.config.Types.Int64, 0, nil, "sum1", "load3"),
+			Valu("store3", OpStore, types.TypeMem, 0, c.config.Types.Int64, "a", "sum2", "store2"),
+			Goto("exit")),
+		Bloc("exit",
+			Exit("store3")))
+
+	CheckFunc(fun.f)
+	cse(fun.f)
+	CheckFunc(fun.f)
+
+	if sum1 := fun.values["sum1"]; sum1.Args[0] != sum1.Args[1] {
+		t.Errorf("cse should have merged load1 and load2")
+	}
+	if sum2 := fun.values["sum2"]; sum2.Args[1].Args[1] != fun.values["store2"] {
+		t.Errorf("cse should not have moved load3 before store2")
+	}
+}
@@ -12,7 +12,8 @@ import (
 
 // dse does dead-store elimination on the Function.
 // Dead stores are those which are unconditionally followed by
-// another store to the same location, with no intervening load.
+// another store to the same location, with no intervening load
+// that the alias oracle finds may read it.
 // This implementation only works within a basic block. TODO: use something more global.
 func dse(f *Func) {
 	var stores []*Value
@@ -22,13 +23,21 @@ func dse(f *Func) {
 	defer f.retSparseSet(storeUse)
 	shadowed := f.newSparseMap(f.NumValues())
 	defer f.retSparseMap(shadowed)
+	var shadowPtrs []*Value        // the addresses in shadowed
+	loads := make(map[ID][]*Value) // the loads of each store
 	for _, b := range f.Blocks {
 		// Find all the stores in this block. Categorize their uses:
-		//  loadUse contains stores which are used by a subsequent load.
+		//  loadUse contains stores which are used by a subsequent load
+		//   other than an OpLoad, or by a store that also reads.
 		//  storeUse contains stores which are used by a subsequent store.
+		//  loads maps stores to the OpLoads which use them, whose
+		//   addresses the alias oracle knows.
 		loadUse.clear()
 		storeUse.clear()
 		stores = stores[:0]
+		for id := range loads {
+			delete(loads, id)
+		}
 		for _, v := range b.Values {
 			if v.Op == OpPhi {
 				// Ignore phis - they will always be first and can't be eliminated
@@ -49,7 +58,11 @@ func dse(f *Func) {
 			} else {
 				for _, a := range v.Args {
 					if a.Block == b && a.Type.IsMemory() {
-						loadUse.add(a.ID)
+						if v.Op == OpLoad {
+							loads[a.ID] = append(loads[a.ID], v)
+						} else {
+							loadUse.add(a.ID)
+						}
 					}
 				}
 			}
@@ -80,6 +93,7 @@ func dse(f *Func) {
 		// Since we're walking backwards, writes to a shadowed region are useless,
 		// as they will be immediately overwritten.
 		shadowed.clear()
+		shadowPtrs = shadowPtrs[:0]
 		v := last
 
 	walkloop:
@@ -87,6 +101,25 @@ func dse(f *Func) {
 			// Someone might be reading this memory state.
 			// Clear all shadowed addresses.
 			shadowed.clear()
+			shadowPtrs = shadowPtrs[:0]
+		} else if lds := loads[v.ID]; len(lds) > 0 && shadowed.size() > 0 {
+			// Loads are reading this memory state.
+			// Clear the shadowed addresses that they may read.
+			live := shadowPtrs[:0]
+			for _, p := range shadowPtrs {
+				sh := memAccess{ptr: p, size: int64(shadowed.get(p.ID))}
+				for _, l := range lds {
+					ld, _ := memAccessOf(l)
+					if f.mayAlias(aliasDSE, ld, sh) {
+						shadowed.remove(p.ID)
+						break
+					}
+				}
+				if shadowed.contains(p.ID) {
+					live = append(live, p)
+				}
+			}
+			shadowPtrs = live
 		}
 		if v.Op == OpStore || v.Op == OpZero {
 			var sz int64
@@ -112,6 +145,9 @@ func dse(f *Func) {
 				if sz > 0x7fffffff { // work around sparseMap's int32 value type
 					sz = 0x7fffffff
 				}
+				if !shadowed.contains(v.Args[0].ID) {
+					shadowPtrs = append(shadowPtrs, v.Args[0])
+				}
 				shadowed.set(v.Args[0].ID, int32(sz), src.NoXPos)
 			}
 		}
@@ -127,3 +127,38 @@ func TestDeadStoreUnsafe(t *testing.T) {
 		t.Errorf("store %s incorrectly removed", v)
 	}
 }
+
+func TestDeadStoreLoadOtherField(t *testing.T) {
+	// Make sure a load between two stores to the same field only keeps
+	// the first store if the load may read the field.
+	c := testConfig(t)
+	ptrType := c.config.Types.Int64.PtrTo()
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("start", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+			Valu("v", OpConst64, c.config.Types.Int64, 1, nil),
+			Valu("addr", OpAddr, ptrType, 0, nil, "sb"),
+			Valu("a", OpOffPtr, ptrType, 0, nil, "addr"),
+			Valu("b", OpOffPtr, ptrType, 8, nil, "addr"),
+			Valu("store1", OpStore, types.TypeMem, 0, c.config.Types.Int64, "a", "v", "start"),
+			Valu("load1", OpLoad, c.config.Types.Int64, 0, nil, "b", "store1"),
+			Valu("store2", OpStore, types.TypeMem, 0, c.config.Types.Int64, "a", "load1", "store1"),
+			Valu("store3", OpStore, types.TypeMem, 0, c.config.Types.Int64, "b", "v", "store2"),
+			Valu("load2", OpLoad, c.config.Types.Int64, 0, nil, "b", "store3"),
+			Valu("store4", OpStore, types.TypeMem, 0, c.config.Types.Int64, "b", "load2", "store3"),
+			Goto("exit")),
+		Bloc("exit",
+			Exit("store4")))
+
+	CheckFunc(fun.f)
+	dse(fun.f)
+	CheckFunc(fun.f)
+
+	if v := fun.values["store1"]; v.Op != OpCopy {
+		t.Errorf("dead store %s not removed", v)
+	}
+	if v := fun.values["store3"]; v.Op == OpCopy {
+		t.Errorf("store %s incorrectly removed", v)
+	}
+}
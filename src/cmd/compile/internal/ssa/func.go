@@ -7,6 +7,7 @@ package ssa
 import (
 	"cmd/compile/internal/abi"
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/pgo"
 	"cmd/compile/internal/types"
 	"cmd/internal/src"
 	"crypto/sha1"
@@ -55,6 +56,13 @@ type Func struct {
 	NoSplit     bool  // true if function is marked as nosplit.  Used by schedule check pass.
 	dumpFileSeq uint8 // the sequence numbers of dump file. (%s_%02d__%s.dump", funcname, dumpFileSeq, phaseName)
 
+	bce           *bceTracker    // bounds checks followed for -d=bce, if set
+	regallocTrace *regallocTrace // register allocation trace for ssa.html, if set
+	aliasStats    aliasStats     // queries of the alias oracle, for -d=aliasstats
+
+	Profile *pgo.Func      // samples of the function in the -pgoprofile profile, if any
+	Unroll  map[*Block]int // //go:unroll factors of loops, by the block that tests their condition
+
 	// when register allocation is done, maps value ids to locations
 	RegAlloc []Location
 
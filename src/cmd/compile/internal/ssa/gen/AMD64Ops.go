@@ -663,6 +663,18 @@ func init() {
 
 		{name: "PXOR", argLength: 2, reg: fp21, asm: "PXOR", commutative: true, resultInArg0: true}, // exclusive or, applied to X regs for float negation.
 
+		// Elementwise operations on the 16-byte vectors made by the vectorize pass.
+		{name: "PADDB", argLength: 2, reg: fp21, asm: "PADDB", commutative: true, resultInArg0: true}, // arg0 + arg1, 16 x 8 bits
+		{name: "PADDW", argLength: 2, reg: fp21, asm: "PADDW", commutative: true, resultInArg0: true}, // arg0 + arg1, 8 x 16 bits
+		{name: "PADDL", argLength: 2, reg: fp21, asm: "PADDL", commutative: true, resultInArg0: true}, // arg0 + arg1, 4 x 32 bits
+		{name: "PADDQ", argLength: 2, reg: fp21, asm: "PADDQ", commutative: true, resultInArg0: true}, // arg0 + arg1, 2 x 64 bits
+		{name: "PSUBB", argLength: 2, reg: fp21, asm: "PSUBB", resultInArg0: true},                    // arg0 - arg1, 16 x 8 bits
+		{name: "PSUBW", argLength: 2, reg: fp21, asm: "PSUBW", resultInArg0: true},                    // arg0 - arg1, 8 x 16 bits
+		{name: "PSUBL", argLength: 2, reg: fp21, asm: "PSUBL", resultInArg0: true},                    // arg0 - arg1, 4 x 32 bits
+		{name: "PSUBQ", argLength: 2, reg: fp21, asm: "PSUBQ", resultInArg0: true},                    // arg0 - arg1, 2 x 64 bits
+		{name: "PAND", argLength: 2, reg: fp21, asm: "PAND", commutative: true, resultInArg0: true},   // arg0 & arg1
+		{name: "POR", argLength: 2, reg: fp21, asm: "POR", commutative: true, resultInArg0: true},     // arg0 | arg1
+
 		{name: "LEAQ", argLength: 1, reg: gp11sb, asm: "LEAQ", aux: "SymOff", rematerializeable: true, symEffect: "Addr"},      // arg0 + auxint + offset encoded in aux
 		{name: "LEAL", argLength: 1, reg: gp11sb, asm: "LEAL", aux: "SymOff", rematerializeable: true, symEffect: "Addr"},      // arg0 + auxint + offset encoded in aux
 		{name: "LEAW", argLength: 1, reg: gp11sb, asm: "LEAW", aux: "SymOff", rematerializeable: true, symEffect: "Addr"},      // arg0 + auxint + offset encoded in aux
@@ -249,6 +249,19 @@ func init() {
 		{name: "CLZW", argLength: 1, reg: gp11, asm: "CLZW"},                                  // count leading zero, 32-bit
 		{name: "VCNT", argLength: 1, reg: fp11, asm: "VCNT"},                                  // count set bits for each 8-bit unit and store the result in each 8-bit unit
 		{name: "VUADDLV", argLength: 1, reg: fp11, asm: "VUADDLV"},                            // unsigned sum of eight bytes in a 64-bit value, zero extended to 64-bit.
+
+		// Elementwise operations on the 16-byte vectors made by the vectorize pass.
+		{name: "VADDB", argLength: 2, reg: fp21, asm: "VADD", commutative: true}, // arg0 + arg1, 16 x 8 bits
+		{name: "VADDH", argLength: 2, reg: fp21, asm: "VADD", commutative: true}, // arg0 + arg1, 8 x 16 bits
+		{name: "VADDS", argLength: 2, reg: fp21, asm: "VADD", commutative: true}, // arg0 + arg1, 4 x 32 bits
+		{name: "VADDD", argLength: 2, reg: fp21, asm: "VADD", commutative: true}, // arg0 + arg1, 2 x 64 bits
+		{name: "VSUBB", argLength: 2, reg: fp21, asm: "VSUB"},                    // arg0 - arg1, 16 x 8 bits
+		{name: "VSUBH", argLength: 2, reg: fp21, asm: "VSUB"},                    // arg0 - arg1, 8 x 16 bits
+		{name: "VSUBS", argLength: 2, reg: fp21, asm: "VSUB"},                    // arg0 - arg1, 4 x 32 bits
+		{name: "VSUBD", argLength: 2, reg: fp21, asm: "VSUB"},                    // arg0 - arg1, 2 x 64 bits
+		{name: "VAND", argLength: 2, reg: fp21, asm: "VAND", commutative: true},  // arg0 & arg1
+		{name: "VORR", argLength: 2, reg: fp21, asm: "VORR", commutative: true},  // arg0 | arg1
+		{name: "VEOR", argLength: 2, reg: fp21, asm: "VEOR", commutative: true},  // arg0 ^ arg1
 		{name: "LoweredRound32F", argLength: 1, reg: fp11, resultInArg0: true, zeroWidth: true},
 		{name: "LoweredRound64F", argLength: 1, reg: fp11, resultInArg0: true, zeroWidth: true},
 
@@ -378,6 +391,7 @@ func init() {
 		{name: "MOVDload", argLength: 2, reg: gpload, aux: "SymOff", asm: "MOVD", typ: "UInt64", faultOnNilArg0: true, symEffect: "Read"},    // load from arg0 + auxInt + aux.  arg1=mem.
 		{name: "FMOVSload", argLength: 2, reg: fpload, aux: "SymOff", asm: "FMOVS", typ: "Float32", faultOnNilArg0: true, symEffect: "Read"}, // load from arg0 + auxInt + aux.  arg1=mem.
 		{name: "FMOVDload", argLength: 2, reg: fpload, aux: "SymOff", asm: "FMOVD", typ: "Float64", faultOnNilArg0: true, symEffect: "Read"}, // load from arg0 + auxInt + aux.  arg1=mem.
+		{name: "FMOVQload", argLength: 2, reg: fpload, aux: "SymOff", asm: "FMOVQ", typ: "Int128", faultOnNilArg0: true, symEffect: "Read"},  // load 16 bytes from arg0 + auxInt + aux.  arg1=mem.
 
 		// register indexed load
 		{name: "MOVDloadidx", argLength: 3, reg: gp2load, asm: "MOVD", typ: "UInt64"},    // load 64-bit dword from arg0 + arg1, arg2 = mem.
@@ -406,6 +420,7 @@ func init() {
 		{name: "STP", argLength: 4, reg: gpstore2, aux: "SymOff", asm: "STP", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},         // store 16 bytes of arg1 and arg2 to arg0 + auxInt + aux.  arg3=mem.
 		{name: "FMOVSstore", argLength: 3, reg: fpstore, aux: "SymOff", asm: "FMOVS", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"}, // store 4 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
 		{name: "FMOVDstore", argLength: 3, reg: fpstore, aux: "SymOff", asm: "FMOVD", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"}, // store 8 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
+		{name: "FMOVQstore", argLength: 3, reg: fpstore, aux: "SymOff", asm: "FMOVQ", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"}, // store 16 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
 
 		// register indexed store
 		{name: "MOVBstoreidx", argLength: 4, reg: gpstore2, asm: "MOVB", typ: "Mem"},   // store 1 byte of arg2 to arg0 + arg1, arg3 = mem.
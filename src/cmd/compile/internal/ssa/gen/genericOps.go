@@ -627,6 +627,23 @@ var genericOps = []opData{
 	// Prefetch instruction
 	{name: "PrefetchCache", argLength: 2, hasSideEffects: true},         // Do prefetch arg0 to cache. arg0=addr, arg1=memory.
 	{name: "PrefetchCacheStreamed", argLength: 2, hasSideEffects: true}, // Do non-temporal or streamed prefetch arg0 to cache. arg0=addr, arg1=memory.
+
+	// Vector operations, made by the vectorize pass on architectures
+	// with 128-bit vector registers. A vector is an Int128 holding
+	// 16, 8, 4 or 2 elements of 8, 16, 32 or 64 bits.
+	{name: "VecLoad", argLength: 2, typ: "Int128"},                     // Load 16 bytes from arg0. arg1=memory
+	{name: "VecStore", argLength: 3, typ: "Mem"},                       // Store 16 bytes of arg1 to arg0. arg2=memory.  Returns memory.
+	{name: "VecAdd8", argLength: 2, typ: "Int128", commutative: true},  // elementwise arg0 + arg1
+	{name: "VecAdd16", argLength: 2, typ: "Int128", commutative: true}, // elementwise arg0 + arg1
+	{name: "VecAdd32", argLength: 2, typ: "Int128", commutative: true}, // elementwise arg0 + arg1
+	{name: "VecAdd64", argLength: 2, typ: "Int128", commutative: true}, // elementwise arg0 + arg1
+	{name: "VecSub8", argLength: 2, typ: "Int128"},                     // elementwise arg0 - arg1
+	{name: "VecSub16", argLength: 2, typ: "Int128"},                    // elementwise arg0 - arg1
+	{name: "VecSub32", argLength: 2, typ: "Int128"},                    // elementwise arg0 - arg1
+	{name: "VecSub64", argLength: 2, typ: "Int128"},                    // elementwise arg0 - arg1
+	{name: "VecAnd", argLength: 2, typ: "Int128", commutative: true},   // arg0 & arg1
+	{name: "VecOr", argLength: 2, typ: "Int128", commutative: true},    // arg0 | arg1
+	{name: "VecXor", argLength: 2, typ: "Int128", commutative: true},   // arg0 ^ arg1
 }
 
 //     kind          controls        successors   implicit exit
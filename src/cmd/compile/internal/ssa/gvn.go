@@ -0,0 +1,202 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/types"
+)
+
+// A gvnKey identifies the values that gvn considers equal. Loads are
+// keyed by the earliest memory state that they can be proved to read
+// the same contents from, rather than by their memory argument.
+type gvnKey struct {
+	op     Op
+	typ    *types.Type
+	aux    Aux
+	auxint int64
+	nargs  int
+	args   [3]ID
+}
+
+// gvn is a global value numbering pass. It walks the dominator tree,
+// numbering the values of each block, and replaces a value with an
+// equal one of a dominating block or earlier in its own block.
+//
+// Generic cse already merges pure values with equal arguments. gvn
+// also merges loads from the same address whose memory arguments
+// differ only by stores, zeroings and moves that provably do not
+// overlap the loaded bytes, as in
+//
+//	x := p.a
+//	p.b = 1
+//	y := p.a // same as x
+//
+// which cse cannot, since it requires equal memory arguments.
+func gvn(f *Func) {
+	sdom := f.Sdom()
+	table := make(map[gvnKey][]*Value)
+	var added []gvnKey // keys added to table, in order, to undo them on leaving a block
+	var marks []int    // len(added) on entering each block of the walk
+	eliminated := 0
+
+	type work struct {
+		b    *Block
+		exit bool
+	}
+	stack := []work{{b: f.Entry}}
+	for len(stack) > 0 {
+		w := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if w.exit {
+			mark := marks[len(marks)-1]
+			marks = marks[:len(marks)-1]
+			for _, k := range added[mark:] {
+				vs := table[k]
+				if len(vs) == 1 {
+					delete(table, k)
+				} else {
+					table[k] = vs[:len(vs)-1]
+				}
+			}
+			added = added[:mark]
+			continue
+		}
+
+		b := w.b
+		marks = append(marks, len(added))
+		stack = append(stack, work{b: b, exit: true})
+		for c := sdom.Child(b); c != nil; c = sdom.Sibling(c) {
+			stack = append(stack, work{b: c})
+		}
+
+		for _, v := range b.Values {
+			k, ok := gvnKeyOf(v)
+			if !ok {
+				continue
+			}
+			vs := table[k]
+			if i, later := gvnMatch(v, vs); i >= 0 {
+				u := vs[i]
+				if later {
+					// u is a load of the same block from a
+					// later memory state than v. Keep v.
+					u, v = v, u
+					vs[i] = u
+				}
+				if base.Debug.GVN != 0 {
+					f.Warnl(v.Pos, "gvn eliminated %v, same as line %d", v.Op, u.Pos.Line())
+				}
+				v.copyOf(u)
+				eliminated++
+				continue
+			}
+			table[k] = append(table[k], v)
+			added = append(added, k)
+		}
+	}
+
+	if f.pass.stats > 0 {
+		f.LogStat("GVN_ELIMINATED", eliminated)
+	}
+}
+
+// gvnKeyOf returns the key that numbers v, and whether gvn can number
+// it at all. Values that write or depend on memory cannot be
+// numbered, except loads, and dead values, which cse leaves for
+// deadcode, are not worth it.
+func gvnKeyOf(v *Value) (gvnKey, bool) {
+	if v.Uses == 0 || v.Op == OpPhi || v.Op == OpCopy || len(v.Args) > len(gvnKey{}.args) ||
+		v.Type.IsMemory() || v.Type.IsVoid() || v.Type.IsTuple() || v.Type.IsResults() ||
+		opcodeTable[v.Op].hasSideEffects || opcodeTable[v.Op].call || opcodeTable[v.Op].nilCheck {
+		return gvnKey{}, false
+	}
+	k := gvnKey{op: v.Op, typ: v.Type, aux: v.Aux, auxint: v.AuxInt, nargs: len(v.Args)}
+	for i, a := range v.Args {
+		a = gvnValue(a)
+		if a.Type.IsMemory() {
+			if v.Op != OpLoad {
+				return gvnKey{}, false
+			}
+			a, _ = gvnLoadMem(v, nil)
+		}
+		k.args[i] = a.ID
+	}
+	if opcodeTable[v.Op].commutative && k.args[0] > k.args[1] {
+		k.args[0], k.args[1] = k.args[1], k.args[0]
+	}
+	return k, true
+}
+
+// gvnMatch returns the index of the value among vs, which have the
+// same key as v, that v can be replaced with, or -1 if there is none.
+// Values in a block are not ordered until scheduling, so a load found
+// in v's block may read from a later memory state than v does. If so,
+// gvnMatch reports that it is later, and the load can be replaced with
+// v instead.
+func gvnMatch(v *Value, vs []*Value) (i int, later bool) {
+	for i := len(vs) - 1; i >= 0; i-- {
+		u := vs[i]
+		if v.Op != OpLoad {
+			return i, false
+		}
+		// Both loads read the same contents from the earliest
+		// memory state, but one can only reuse the other if
+		// that reads them from a memory state before its own.
+		if _, ok := gvnLoadMem(v, u.Args[1]); ok {
+			return i, false
+		}
+		if u.Block == v.Block {
+			if _, ok := gvnLoadMem(u, v.Args[1]); ok {
+				return i, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// gvnLoadMem walks back from the memory argument of the load v through
+// the operations that do not change the bytes it loads, as the alias
+// oracle tells, and returns the memory state it stopped at. If stop is
+// not nil, the walk stops there, and gvnLoadMem reports whether it was
+// reached.
+func gvnLoadMem(v *Value, stop *Value) (*Value, bool) {
+	f := v.Block.Func
+	ld, _ := memAccessOf(v)
+	ld.ptr = gvnValue(ld.ptr)
+	m := gvnValue(v.Args[1])
+	for i := 0; i < aliasMaxWalk && m != stop; i++ {
+		var next *Value
+		switch m.Op {
+		case OpVarDef, OpVarKill, OpVarLive:
+			// These change the liveness of a variable, not
+			// the contents of memory.
+			next = m.Args[0]
+		default:
+			st, ok := memAccessOf(m)
+			if !ok || !st.write {
+				break
+			}
+			st.ptr = gvnValue(st.ptr)
+			if !f.mayAlias(aliasGVN, ld, st) {
+				next = m.MemoryArg()
+			}
+		}
+		if next == nil {
+			break
+		}
+		m = gvnValue(next)
+	}
+	return m, m == stop
+}
+
+// gvnValue returns the value that v, which may be a copy made by gvn,
+// is a copy of.
+func gvnValue(v *Value) *Value {
+	for v.Op == OpCopy {
+		v = v.Args[0]
+	}
+	return v
+}
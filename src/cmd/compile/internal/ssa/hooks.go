@@ -0,0 +1,96 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/internal/src"
+	"fmt"
+	"strings"
+)
+
+// An AnalysisHook is an analysis pass that is compiled into the
+// toolchain without being added to the pass list. It observes the SSA
+// form of each function after the phases it names and may report
+// diagnostics about it, but it must not modify the function.
+//
+// Hooks run only if the ssahooks GOEXPERIMENT is enabled. A hook is
+// typically defined in its own file in this package, guarded by
+//
+//	//go:build goexperiment.ssahooks
+//
+// so that it is only compiled into a toolchain built with the
+// experiment, and registered from that file's init function:
+//
+//	func init() {
+//		RegisterAnalysisHook(&AnalysisHook{
+//			Name:   "divzero",
+//			Phases: []string{"prove"},
+//			Run:    checkDivZero,
+//		})
+//	}
+type AnalysisHook struct {
+	// Name identifies the hook in its diagnostics.
+	Name string
+
+	// Phases lists the passes after which the hook runs, by name,
+	// as in -d=ssa/<phase>/... (see -d=ssa/help). The hook does
+	// not run after passes that are disabled or skipped, such as
+	// the optional passes when optimizations are disabled.
+	Phases []string
+
+	// Run analyzes the function p.Func after the phase p.Phase.
+	Run func(p *AnalysisPass)
+
+	phases map[string]bool // the pass names in Phases
+}
+
+// An AnalysisPass is a run of an AnalysisHook on a function.
+type AnalysisPass struct {
+	Func  *Func
+	Phase string // name of the pass just run, as in the passes list
+
+	hook *AnalysisHook
+}
+
+// Reportf reports a diagnostic at pos, prefixed with the name of the
+// hook. Diagnostics are reported like the compiler's other -d and -m
+// messages, and do not make the compilation fail.
+func (p *AnalysisPass) Reportf(pos src.XPos, format string, args ...interface{}) {
+	p.Func.Warnl(pos, "%s: %s", p.hook.Name, fmt.Sprintf(format, args...))
+}
+
+// analysisHooks holds the registered hooks, in registration order.
+var analysisHooks []*AnalysisHook
+
+// RegisterAnalysisHook registers h to run after the phases it names.
+// It must be called before compilation starts, typically from an init
+// function. It panics if h has no name or Run function, if its name is
+// already registered, or if it names a phase that does not exist.
+func RegisterAnalysisHook(h *AnalysisHook) {
+	if h.Name == "" || h.Run == nil {
+		panic("ssa: analysis hook without name or Run function")
+	}
+	for _, o := range analysisHooks {
+		if o.Name == h.Name {
+			panic(fmt.Sprintf("ssa: duplicate analysis hook %s", h.Name))
+		}
+	}
+	h.phases = make(map[string]bool)
+	for _, name := range h.Phases {
+		name = strings.Replace(name, "_", " ", -1)
+		found := false
+		for _, p := range passes {
+			if p.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			panic(fmt.Sprintf("ssa: analysis hook %s runs after unknown phase %q", h.Name, name))
+		}
+		h.phases[name] = true
+	}
+	analysisHooks = append(analysisHooks, h)
+}
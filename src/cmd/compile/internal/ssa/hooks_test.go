@@ -0,0 +1,68 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"internal/buildcfg"
+	"reflect"
+	"testing"
+)
+
+func TestAnalysisHook(t *testing.T) {
+	defer func(hooks []*AnalysisHook, on bool) {
+		analysisHooks = hooks
+		buildcfg.Experiment.SSAHooks = on
+	}(analysisHooks, buildcfg.Experiment.SSAHooks)
+
+	var phases []string
+	lowered := false
+	RegisterAnalysisHook(&AnalysisHook{
+		Name:   "test",
+		Phases: []string{"opt", "lowered_cse"},
+		Run: func(p *AnalysisPass) {
+			phases = append(phases, p.Phase)
+			for _, b := range p.Func.Blocks {
+				for _, v := range b.Values {
+					if v.Op == OpAMD64SHLQconst {
+						lowered = true
+					}
+				}
+			}
+			p.Reportf(p.Func.Entry.Pos, "ran after %s", p.Phase)
+		},
+	})
+
+	buildcfg.Experiment.SSAHooks = false
+	makeConstShiftFunc(testConfig(t), 18, OpLsh64x64, types.Types[types.TUINT64])
+	if phases != nil {
+		t.Errorf("hook ran after %v with the experiment disabled", phases)
+	}
+
+	buildcfg.Experiment.SSAHooks = true
+	makeConstShiftFunc(testConfig(t), 18, OpLsh64x64, types.Types[types.TUINT64])
+	if want := []string{"opt", "lowered cse"}; !reflect.DeepEqual(phases, want) {
+		t.Errorf("hook ran after %v, want %v", phases, want)
+	}
+	if !lowered {
+		t.Errorf("hook did not see the lowered function")
+	}
+}
+
+func TestAnalysisHookUnknownPhase(t *testing.T) {
+	defer func(hooks []*AnalysisHook) {
+		analysisHooks = hooks
+	}(analysisHooks)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering a hook after an unknown phase did not panic")
+		}
+	}()
+	RegisterAnalysisHook(&AnalysisHook{
+		Name:   "unknown",
+		Phases: []string{"no such phase"},
+		Run:    func(p *AnalysisPass) {},
+	})
+}
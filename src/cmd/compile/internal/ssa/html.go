@@ -170,6 +170,27 @@ td.ssa-prog {
     word-wrap: break-word;
 }
 
+table.regalloc-events, table.regalloc-regs {
+    font-family: Menlo, monospace;
+    font-size: 12px;
+    border-collapse: collapse;
+    margin-bottom: 10px;
+}
+
+table.regalloc-events td, table.regalloc-regs td, table.regalloc-regs th {
+    padding: 1px 4px;
+    white-space: nowrap;
+}
+
+td.regalloc-live {
+    background-color: #e0f0ff;
+}
+
+td.regalloc-def {
+    background-color: #a0d0ff;
+    font-weight: bold;
+}
+
 li {
     list-style-type: none;
 }
@@ -248,6 +269,30 @@ dd.ssa-prog {
     color: gray;
 }
 
+#toolbar {
+    margin-left: 15px;
+}
+
+#toolbar input, #toolbar select, #toolbar button {
+    font-size: 12px;
+}
+
+#search-status {
+    font-size: 12px;
+    color: gray;
+}
+
+.diff-added   { background-color: #d6f5d6; }
+.diff-changed { background-color: #fbf0c4; }
+.diff-removed { background-color: #f8d7d7; text-decoration: line-through; }
+
+.search-match   { outline: #2893ff dashed 1px; }
+.search-current { outline: #2893ff solid 2px; }
+
+ul.unchanged-block > li.ssa-start-block {
+    color: gray;
+}
+
 .zoom {
 	position: absolute;
 	float: left;
@@ -360,6 +405,10 @@ ellipse.outline-black          { stroke-width: 2px; stroke: black; }
 body.darkmode .outline-black        { outline: gray solid 2px; }
 body.darkmode ellipse.outline-black { outline: gray solid 2px; }
 
+body.darkmode .diff-added   { background-color: #1e4620; }
+body.darkmode .diff-changed { background-color: #4d4218; }
+body.darkmode .diff-removed { background-color: #5a1e1e; }
+
 </style>
 
 <script type="text/javascript">
@@ -510,6 +559,27 @@ window.onload = function() {
         lines[i].addEventListener('click', ssaValueClicked);
     }
 
+    const searchBox = document.getElementById("search");
+    searchBox.addEventListener('input', search);
+    searchBox.addEventListener('keydown', function(event) {
+        if (event.key === 'Enter') {
+            if (searchIndex < 0) {
+                search();
+            }
+            searchNext(event.shiftKey ? -1 : 1);
+        }
+    });
+
+    // Offer each SSA column for diffing, and diff the first two by default.
+    const diffFrom = document.getElementById("diff-from");
+    const diffTo = document.getElementById("diff-to");
+    ssaColumns().forEach(td => {
+        const titles = Array.from(td.getElementsByTagName("h2"), h => h.firstChild.textContent.trim());
+        diffFrom.add(new Option(titles.join(" + "), td.id));
+        diffTo.add(new Option(titles.join(" + "), td.id));
+    });
+    diffTo.selectedIndex = Math.min(1, diffTo.options.length-1);
+
 
     function toggler(phase) {
         return function() {
@@ -736,6 +806,207 @@ function toggleDarkMode() {
     });
 }
 
+// ssaColumns returns the expanded cells of the columns that hold an SSA
+// function, in phase order.
+function ssaColumns() {
+    const cols = [];
+    document.querySelectorAll('td[id$="-exp"]').forEach(td => {
+        if (td.querySelector('ul.ssa-print-func') !== null) {
+            cols.push(td);
+        }
+    });
+    return cols;
+}
+
+// expandColumn expands the column whose expanded cell is td.
+function expandColumn(td) {
+    const phase = td.id.substr(0, td.id.length-4);
+    td.style.display = 'table-cell';
+    document.getElementById(phase+'-col').style.display = 'none';
+    if (expandedDefault.indexOf(phase) === -1) {
+        expandedDefault.push(phase);
+        history.pushState({expandedDefault}, "", location.href);
+    }
+}
+
+// ssaText returns the text of el, leaving out its buttons.
+function ssaText(el) {
+    let s = '';
+    el.childNodes.forEach(n => {
+        if (n.nodeName !== 'BUTTON') {
+            s += n.textContent;
+        }
+    });
+    return s;
+}
+
+// ssaFunc describes the SSA function in the cell td, for comparing it
+// with other phases. It maps each value ID to the value's element and
+// text, and each block ID to the elements of the block's start and end,
+// the text of those (head), and the text of the whole block.
+function ssaFunc(td) {
+    const f = {values: new Map(), blocks: new Map()};
+    td.querySelectorAll('ul.ssa-print-func').forEach(ul => {
+        const start = ul.querySelector('li.ssa-start-block');
+        const end = ul.querySelector('li.ssa-end-block');
+        const head = ssaText(start) + '\n' + end.textContent;
+        let text = head;
+        ul.querySelectorAll('li[data-value]').forEach(li => {
+            f.values.set(li.dataset.value, {els: [li], text: li.textContent});
+            text += '\n' + li.textContent;
+        });
+        f.blocks.set(ul.dataset.block, {ul: ul, els: [start, end], head: head, text: text});
+    });
+    return f;
+}
+
+// diffPhases marks the differences between the phases chosen in the
+// diff boxes: in the second phase, the values and blocks that were
+// added or changed since the first, and in the first phase, those that
+// were removed. Blocks are compared by their start and end only.
+function diffPhases() {
+    clearDiff();
+    const from = document.getElementById(document.getElementById("diff-from").value);
+    const to = document.getElementById(document.getElementById("diff-to").value);
+    if (from === null || to === null || from === to) {
+        return;
+    }
+    const a = ssaFunc(from);
+    const b = ssaFunc(to);
+    const mark = function(old, cur, key) {
+        cur.forEach((x, id) => {
+            const y = old.get(id);
+            if (y === undefined) {
+                x.els.forEach(e => e.classList.add('diff-added'));
+            } else if (y[key] !== x[key]) {
+                x.els.forEach(e => e.classList.add('diff-changed'));
+            }
+        });
+        old.forEach((y, id) => {
+            if (!cur.has(id)) {
+                y.els.forEach(e => e.classList.add('diff-removed'));
+            }
+        });
+    };
+    mark(a.values, b.values, 'text');
+    mark(a.blocks, b.blocks, 'head');
+    expandColumn(from);
+    expandColumn(to);
+    from.scrollIntoView({inline: 'start'});
+}
+
+// clearDiff removes the marks made by diffPhases.
+function clearDiff() {
+    document.querySelectorAll('.diff-added, .diff-changed, .diff-removed').forEach(e => {
+        e.classList.remove('diff-added', 'diff-changed', 'diff-removed');
+    });
+}
+
+// showValues shows or hides the values of the block ul, as hideBlock does.
+function showValues(ul, show) {
+    const list = ul.querySelector('li.ssa-value-list');
+    const button = ul.querySelector('li.ssa-start-block button');
+    if (list === null || button === null) {
+        return;
+    }
+    list.style.display = show ? 'block' : 'none';
+    button.innerHTML = show ? '-' : '+';
+}
+
+// collapseUnchanged hides the values of each block that is the same as
+// in the previous SSA column if collapse is set, and shows them again
+// if not.
+function collapseUnchanged(collapse) {
+    if (!collapse) {
+        document.querySelectorAll('ul.unchanged-block').forEach(ul => {
+            ul.classList.remove('unchanged-block');
+            showValues(ul, true);
+        });
+        return;
+    }
+    let prev = null;
+    ssaColumns().forEach(td => {
+        const f = ssaFunc(td);
+        if (prev !== null) {
+            f.blocks.forEach((b, id) => {
+                const p = prev.blocks.get(id);
+                if (p !== undefined && p.text === b.text) {
+                    b.ul.classList.add('unchanged-block');
+                    showValues(b.ul, false);
+                }
+            });
+        }
+        prev = f;
+    });
+}
+
+// state: the elements matching the search, and which of them is current
+var searchMatches = [];
+var searchIndex = -1;
+
+// search marks the values and blocks in the expanded SSA columns that
+// match the text in the search box. A value or block ID, such as v12
+// or b3, matches that value or the start of that block; other text
+// matches the values and block ends that contain it, ignoring case.
+function search() {
+    document.querySelectorAll('.search-match').forEach(e => {
+        e.classList.remove('search-match', 'search-current');
+    });
+    searchMatches = [];
+    searchIndex = -1;
+    const status = document.getElementById("search-status");
+    const q = document.getElementById("search").value.trim();
+    if (q === '') {
+        status.textContent = '';
+        return;
+    }
+    const isID = /^[bv][0-9]+$/.test(q);
+    const contains = e => e.textContent.toLowerCase().includes(q.toLowerCase());
+    ssaColumns().forEach(td => {
+        if (td.style.display === 'none') {
+            return;
+        }
+        td.querySelectorAll('ul.ssa-print-func').forEach(ul => {
+            if (isID && ul.dataset.block === q) {
+                searchMatches.push(ul.querySelector('li.ssa-start-block'));
+            }
+            ul.querySelectorAll('li[data-value]').forEach(li => {
+                if (isID ? li.dataset.value === q : contains(li)) {
+                    searchMatches.push(li);
+                }
+            });
+            const end = ul.querySelector('li.ssa-end-block');
+            if (!isID && contains(end)) {
+                searchMatches.push(end);
+            }
+        });
+    });
+    searchMatches.forEach(e => e.classList.add('search-match'));
+    status.textContent = searchMatches.length + ' matches';
+}
+
+// searchNext moves to the next search match if dir is 1, and to the
+// previous one if dir is -1, showing its block's values if needed.
+function searchNext(dir) {
+    const n = searchMatches.length;
+    if (n === 0) {
+        return;
+    }
+    if (searchIndex < 0) {
+        searchIndex = dir > 0 ? 0 : n-1;
+    } else {
+        searchMatches[searchIndex].classList.remove('search-current');
+        searchIndex = (searchIndex + dir + n) % n;
+    }
+    const e = searchMatches[searchIndex];
+    if (e.closest('li.ssa-value-list') !== null) {
+        showValues(e.closest('ul.ssa-print-func'), true);
+    }
+    e.classList.add('search-current');
+    e.scrollIntoView({block: 'center', inline: 'center'});
+    document.getElementById("search-status").textContent = (searchIndex+1) + ' of ' + n;
+}
+
 </script>
 
 </head>`)
@@ -767,9 +1038,41 @@ Values printed in italics have a dependency cycle.
 Edge with a dot means that this edge follows the order in which blocks were laidout.
 </p>
 
+<p>
+<b>Search</b>: Type a value or block ID (such as v12 or b3) to mark that
+value or block in the expanded columns, or any other text (such as an op
+name) to mark the values and block ends that contain it. Press Enter to
+go to the next match, and Shift+Enter to go to the previous one.
+</p>
+
+<p>
+<b>Diff</b>: Choose two phases and click diff to mark, in the second
+phase, the values and blocks that were added (green) or changed (yellow)
+since the first, and in the first phase, those that were removed (red).
+Values and blocks are matched by ID.
+</p>
+
+<p>
+<b>Collapse unchanged blocks</b> hides the values of the blocks that are
+the same as in the previous phase. Use a block's +/- button to show or
+hide its values again.
+</p>
+
 </div>
 <label for="dark-mode-button" style="margin-left: 15px; cursor: pointer;">darkmode</label>
 <input type="checkbox" onclick="toggleDarkMode();" id="dark-mode-button" style="cursor: pointer" />
+<span id="toolbar">
+<input type="search" id="search" placeholder="search values and blocks" />
+<span id="search-status"></span>
+<label for="diff-from" style="margin-left: 15px;">diff</label>
+<select id="diff-from"></select>
+<label for="diff-to">to</label>
+<select id="diff-to"></select>
+<button onclick="diffPhases();">diff</button>
+<button onclick="clearDiff();">clear</button>
+<label for="collapse-button" style="margin-left: 15px; cursor: pointer;">collapse unchanged blocks</label>
+<input type="checkbox" onclick="collapseUnchanged(this.checked);" id="collapse-button" style="cursor: pointer" />
+</span>
 `)
 	w.WriteString("<table>")
 	w.WriteString("<tr>")
@@ -1199,7 +1502,7 @@ func (p htmlFuncPrinter) startBlock(b *Block, reachable bool) {
 	if !reachable {
 		dead = "dead-block"
 	}
-	fmt.Fprintf(p.w, "<ul class=\"%s ssa-print-func %s\">", b, dead)
+	fmt.Fprintf(p.w, "<ul class=\"%s ssa-print-func %s\" data-block=\"%s\">", b, dead, b)
 	fmt.Fprintf(p.w, "<li class=\"ssa-start-block\">%s:", b.HTML())
 	if len(b.Preds) > 0 {
 		io.WriteString(p.w, " &#8592;") // left arrow
@@ -1234,7 +1537,7 @@ func (p htmlFuncPrinter) value(v *Value, live bool) {
 	if !live {
 		dead = "dead-value"
 	}
-	fmt.Fprintf(p.w, "<li class=\"ssa-long-value %s\">", dead)
+	fmt.Fprintf(p.w, "<li class=\"ssa-long-value %s\" data-value=\"%s\">", dead, v)
 	fmt.Fprint(p.w, v.LongHTML())
 	io.WriteString(p.w, "</li>")
 }
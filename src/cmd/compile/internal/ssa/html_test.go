@@ -0,0 +1,36 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"strings"
+	"testing"
+)
+
+// TestHTMLDataAttributes checks that the blocks and values in ssa.html
+// carry the IDs that its script uses to search and diff phases.
+func TestHTMLDataAttributes(t *testing.T) {
+	c := testConfig(t)
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("c", OpConst64, c.config.Types.Int64, 42, nil),
+			Goto("exit")),
+		Bloc("exit",
+			Exit("mem")))
+
+	got := fun.f.HTML("opt", nil)
+	for _, b := range fun.f.Blocks {
+		if want := `data-block="` + b.String() + `"`; !strings.Contains(got, want) {
+			t.Errorf("HTML does not contain %s:\n%s", want, got)
+		}
+		for _, v := range b.Values {
+			if want := `data-value="` + v.String() + `"`; !strings.Contains(got, want) {
+				t.Errorf("HTML does not contain %s:\n%s", want, got)
+			}
+		}
+	}
+}
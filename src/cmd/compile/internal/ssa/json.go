@@ -0,0 +1,152 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"cmd/internal/src"
+)
+
+// The JSON form of a function, written by -d=ssa/<phase>/json, is meant
+// for tools that visualize or analyze the SSA form without scraping the
+// text dumps or ssa.html. It follows the text form (see fprintFunc):
+// blocks are in f.Blocks order, values in b.Values order, and each is
+// identified by the name it has there, as in b1 or v3. Types are given
+// by their link strings, so that they are unambiguous.
+
+// A jsonFunc is the JSON form of a function after a phase.
+type jsonFunc struct {
+	Name   string      `json:"name"`
+	Phase  string      `json:"phase"`
+	Arch   string      `json:"arch"`
+	Entry  string      `json:"entry"`
+	Blocks []jsonBlock `json:"blocks"`
+}
+
+// A jsonBlock is the JSON form of a block.
+type jsonBlock struct {
+	ID       string      `json:"id"`
+	Kind     string      `json:"kind"`
+	Pos      *jsonPos    `json:"pos,omitempty"`
+	Aux      string      `json:"aux,omitempty"` // as in the text form
+	Controls []string    `json:"controls,omitempty"`
+	Preds    []string    `json:"preds,omitempty"`
+	Succs    []string    `json:"succs,omitempty"`
+	Likely   string      `json:"likely,omitempty"` // "likely" or "unlikely", for Succs[0]
+	Values   []jsonValue `json:"values"`
+}
+
+// A jsonValue is the JSON form of a value.
+type jsonValue struct {
+	ID   string   `json:"id"`
+	Op   string   `json:"op"`
+	Type string   `json:"type"`
+	Aux  string   `json:"aux,omitempty"` // as in the text form, such as [8] or {x}
+	Args []string `json:"args,omitempty"`
+	Pos  *jsonPos `json:"pos,omitempty"`
+	Uses int32    `json:"uses"`
+	Home string   `json:"home,omitempty"` // assigned location, after regalloc
+}
+
+// A jsonPos is the JSON form of a position. For code inlined from
+// another function, it is the position in that function.
+type jsonPos struct {
+	File string `json:"file"`
+	Line uint   `json:"line"`
+	Col  uint   `json:"col,omitempty"`
+	Stmt bool   `json:"stmt,omitempty"` // is a statement boundary
+}
+
+// dumpJSON writes the JSON form of f after phase to a file, named like
+// the text dump but with a .json suffix.
+func (f *Func) dumpJSON(phaseName string) {
+	fi := f.createDumpFile(phaseName, "json")
+	if fi == nil {
+		return
+	}
+	defer fi.Close()
+	if err := fprintJSON(fi, f, phaseName); err != nil {
+		f.Warnl(src.NoXPos, "Unable to write JSON dump of %s after %s: %v", f.Name, phaseName, err)
+	}
+}
+
+// fprintJSON writes the JSON form of f after phase to w.
+func fprintJSON(w io.Writer, f *Func, phase string) error {
+	jf := jsonFunc{
+		Name:  f.Name,
+		Phase: phase,
+		Arch:  f.Config.arch,
+		Entry: f.Entry.String(),
+	}
+	for _, b := range f.Blocks {
+		jb := jsonBlock{
+			ID:   b.String(),
+			Kind: b.Kind.String(),
+			Pos:  f.jsonPos(b.Pos),
+		}
+		if b.Aux != nil {
+			jb.Aux = fmt.Sprintf("{%s}", b.Aux)
+		}
+		if t := b.AuxIntString(); t != "" {
+			jb.Aux = strings.TrimSpace(jb.Aux + " [" + t + "]")
+		}
+		for _, c := range b.ControlValues() {
+			jb.Controls = append(jb.Controls, c.String())
+		}
+		for _, e := range b.Preds {
+			jb.Preds = append(jb.Preds, e.b.String())
+		}
+		for _, e := range b.Succs {
+			jb.Succs = append(jb.Succs, e.b.String())
+		}
+		switch b.Likely {
+		case BranchLikely:
+			jb.Likely = "likely"
+		case BranchUnlikely:
+			jb.Likely = "unlikely"
+		}
+		jb.Values = make([]jsonValue, 0, len(b.Values))
+		for _, v := range b.Values {
+			jv := jsonValue{
+				ID:   v.String(),
+				Op:   v.Op.String(),
+				Type: v.Type.LinkString(),
+				Aux:  strings.TrimSpace(v.auxString()),
+				Pos:  f.jsonPos(v.Pos),
+				Uses: v.Uses,
+			}
+			for _, a := range v.Args {
+				jv.Args = append(jv.Args, a.String())
+			}
+			if loc := f.getHome(v.ID); loc != nil {
+				jv.Home = loc.String()
+			}
+			jb.Values = append(jb.Values, jv)
+		}
+		jf.Blocks = append(jf.Blocks, jb)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(jf)
+}
+
+// jsonPos returns the JSON form of pos, or nil if pos is not known.
+func (f *Func) jsonPos(pos src.XPos) *jsonPos {
+	if !pos.IsKnown() {
+		return nil
+	}
+	p := f.Config.ctxt.InnermostPos(pos)
+	return &jsonPos{
+		File: p.Filename(),
+		Line: p.Line(),
+		Col:  p.Col(),
+		Stmt: pos.IsStmt() == src.PosIsStmt,
+	}
+}
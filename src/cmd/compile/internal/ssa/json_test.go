@@ -0,0 +1,76 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"bytes"
+	"cmd/compile/internal/types"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONDump(t *testing.T) {
+	c := testConfig(t)
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+			Valu("c", OpConst64, c.config.Types.Int64, 42, nil),
+			Valu("cond", OpConstBool, c.config.Types.Bool, 1, nil),
+			If("cond", "then", "exit")),
+		Bloc("then",
+			Valu("add", OpAdd64, c.config.Types.Int64, 0, nil, "c", "c"),
+			Goto("exit")),
+		Bloc("exit",
+			Exit("mem")))
+	fun.blocks["entry"].Likely = BranchUnlikely
+
+	var buf bytes.Buffer
+	if err := fprintJSON(&buf, fun.f, "opt"); err != nil {
+		t.Fatal(err)
+	}
+	var got jsonFunc
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshaling %s: %v", buf.Bytes(), err)
+	}
+
+	if got.Phase != "opt" || got.Arch != "amd64" || got.Entry != fun.blocks["entry"].String() {
+		t.Errorf("got phase %q, arch %q, entry %q", got.Phase, got.Arch, got.Entry)
+	}
+	if len(got.Blocks) != len(fun.f.Blocks) {
+		t.Fatalf("got %d blocks, want %d", len(got.Blocks), len(fun.f.Blocks))
+	}
+
+	entry := got.Blocks[0]
+	if entry.Kind != "If" || entry.Likely != "unlikely" {
+		t.Errorf("entry block has kind %q, likely %q; want If, unlikely", entry.Kind, entry.Likely)
+	}
+	cond := fun.values["cond"].String()
+	if len(entry.Controls) != 1 || entry.Controls[0] != cond {
+		t.Errorf("entry block has controls %v, want [%s]", entry.Controls, cond)
+	}
+	then, exit := fun.blocks["then"].String(), fun.blocks["exit"].String()
+	if len(entry.Succs) != 2 || entry.Succs[0] != then || entry.Succs[1] != exit {
+		t.Errorf("entry block has succs %v, want [%s %s]", entry.Succs, then, exit)
+	}
+
+	values := make(map[string]jsonValue)
+	for _, b := range got.Blocks {
+		for _, v := range b.Values {
+			values[v.ID] = v
+		}
+	}
+	c42 := values[fun.values["c"].String()]
+	if c42.Op != "Const64" || c42.Type != "int64" || c42.Aux != "[42]" || c42.Uses != 2 {
+		t.Errorf("got constant %+v", c42)
+	}
+	add := values[fun.values["add"].String()]
+	if add.Op != "Add64" || len(add.Args) != 2 || add.Args[0] != c42.ID || add.Args[1] != c42.ID {
+		t.Errorf("got add %+v", add)
+	}
+	if mem := values[fun.values["mem"].String()]; mem.Type != "mem" {
+		t.Errorf("got memory value of type %q, want mem", mem.Type)
+	}
+}
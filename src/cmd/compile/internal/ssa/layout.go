@@ -4,11 +4,21 @@
 
 package ssa
 
+import (
+	"cmd/compile/internal/base"
+	"cmd/internal/src"
+	"fmt"
+	"strings"
+)
+
 // layout orders basic blocks in f with the goal of minimizing control flow instructions.
 // After this phase returns, the order of f.Blocks matters and is the order
 // in which those blocks will appear in the assembly output.
 func layout(f *Func) {
 	f.Blocks = layoutOrder(f)
+	if base.Debug.PGOLayout != 0 && f.Profile != nil {
+		reportLayout(f)
+	}
 }
 
 // Register allocation may use a different order which has constraints
@@ -69,14 +79,60 @@ func layoutOrder(f *Func) []*Block {
 		}
 	}
 
+	// With a profile, the blocks that did not run are cold, as are
+	// the blocks that call cold functions. They are scheduled after
+	// the exit blocks. They stay in the function: moving them into
+	// functions of their own would have to pass them the live values
+	// and add a frame to the tracebacks of the panics they report.
+	weights := blockWeights(f)
+	cold := make([]bool, f.NumBlocks())
+	var colds []ID
+	for _, b := range f.Blocks {
+		if b == f.Entry || exit.contains(b.ID) {
+			continue
+		}
+		if weights != nil && weights[b.ID] == 0 || callsCold(b) {
+			cold[b.ID] = true
+			colds = append(colds, b.ID)
+		}
+	}
+	if len(colds) > 0 {
+		// So is any block that only cold blocks lead to, so that
+		// each block follows one of its predecessors, as register
+		// allocation, which visits blocks in this order, requires.
+		reached := make([]bool, f.NumBlocks())
+		reached[f.Entry.ID] = true
+		queue := []*Block{f.Entry}
+		for len(queue) > 0 {
+			b := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			for _, e := range b.Succs {
+				if c := e.b; !reached[c.ID] && !cold[c.ID] {
+					reached[c.ID] = true
+					queue = append(queue, c)
+				}
+			}
+		}
+		for _, b := range f.Blocks {
+			if !reached[b.ID] && !cold[b.ID] {
+				cold[b.ID] = true
+				colds = append(colds, b.ID)
+			}
+		}
+	}
+
 	// Initialize indegree of each block
 	for _, b := range f.Blocks {
-		if exit.contains(b.ID) {
+		if exit.contains(b.ID) || cold[b.ID] {
 			// exit blocks are always scheduled last
 			continue
 		}
-		indegree[b.ID] = len(b.Preds)
-		if len(b.Preds) == 0 {
+		for _, e := range b.Preds {
+			if !cold[e.b.ID] {
+				indegree[b.ID]++
+			}
+		}
+		if indegree[b.ID] == 0 {
 			// Push an element to the tail of the queue.
 			zerodegree = append(zerodegree, b.ID)
 		} else {
@@ -127,7 +183,17 @@ blockloop:
 		case BranchUnlikely:
 			likely = b.Succs[1].b
 		}
-		if likely != nil && !scheduled[likely.ID] {
+		// A profile overrides the static likely direction: the
+		// successor that ran more often falls through.
+		if weights != nil && len(b.Succs) == 2 {
+			w0, w1 := weights[b.Succs[0].b.ID], weights[b.Succs[1].b.ID]
+			if w0 > w1 {
+				likely = b.Succs[0].b
+			} else if w1 > w0 {
+				likely = b.Succs[1].b
+			}
+		}
+		if likely != nil && !scheduled[likely.ID] && !cold[likely.ID] {
 			bid = likely.ID
 			continue
 		}
@@ -141,7 +207,7 @@ blockloop:
 			// Pop an element from the tail of the queue.
 			cid := zerodegree[len(zerodegree)-1]
 			zerodegree = zerodegree[:len(zerodegree)-1]
-			if !scheduled[cid] {
+			if !scheduled[cid] && !cold[cid] {
 				bid = cid
 				continue blockloop
 			}
@@ -152,7 +218,7 @@ blockloop:
 			// Pop an element from the tail of the queue.
 			cid := succs[len(succs)-1]
 			succs = succs[:len(succs)-1]
-			if !scheduled[cid] {
+			if !scheduled[cid] && !cold[cid] {
 				bid = cid
 				continue blockloop
 			}
@@ -168,15 +234,135 @@ blockloop:
 		}
 		// Pick any exit block.
 		// TODO: Order these to minimize jump distances?
-		for {
+		for exit.size() > 0 {
 			cid := exit.pop()
-			if !scheduled[cid] {
+			if !scheduled[cid] && !cold[cid] {
 				bid = cid
 				continue blockloop
 			}
 		}
+		// Pick a cold block that follows a scheduled one.
+		for _, cid := range colds {
+			if !scheduled[cid] && hasScheduledPred(idToBlock[cid], scheduled) {
+				bid = cid
+				continue blockloop
+			}
+		}
+		f.Fatalf("layout: no block to schedule")
 	}
 	f.laidout = true
 	return order
 	//f.Blocks = order
 }
+
+// hasScheduledPred reports whether a predecessor of b is scheduled.
+func hasScheduledPred(b *Block, scheduled []bool) bool {
+	for _, e := range b.Preds {
+		if scheduled[e.b.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// callsCold reports whether b calls a cold function.
+func callsCold(b *Block) bool {
+	for _, v := range b.Values {
+		if isColdCall(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// pgoLayoutMinSamples is the fewest samples that a function must have in
+// the profile for the profile to guide its block layout.
+const pgoLayoutMinSamples = 10
+
+// blockWeights returns how many samples of f's profile each block has,
+// by block ID, or nil if f has too few samples for layout. A block's
+// weight is the most samples at any of its lines, which approximates
+// how often it ran. Blocks without lines of their own, such as those
+// made for critical edges, have the weight of their successor, or -1
+// if unknown.
+func blockWeights(f *Func) []int64 {
+	if f.Profile == nil || f.Profile.Total < pgoLayoutMinSamples {
+		return nil
+	}
+	weights := make([]int64, f.NumBlocks())
+	known := make([]bool, f.NumBlocks())
+	add := func(b *Block, pos src.XPos) {
+		if !pos.IsKnown() {
+			return
+		}
+		line := int(f.Config.ctxt.OutermostPos(pos).RelLine())
+		if w := f.Profile.Lines[line]; !known[b.ID] || w > weights[b.ID] {
+			weights[b.ID] = w
+		}
+		known[b.ID] = true
+	}
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			add(b, v.Pos)
+		}
+		add(b, b.Pos)
+	}
+	for _, b := range f.Blocks {
+		if known[b.ID] {
+			continue
+		}
+		weights[b.ID] = -1
+		c := b
+		for i := 0; i < len(f.Blocks) && c.Kind == BlockPlain && !known[c.ID]; i++ {
+			c = c.Succs[0].b
+		}
+		if known[c.ID] {
+			weights[b.ID] = weights[c.ID]
+		}
+	}
+	return weights
+}
+
+// reportLayout reports, for -d=pgolayout, the layout of f and the
+// weights from the profile that chose it.
+func reportLayout(f *Func) {
+	weights := blockWeights(f)
+	if weights == nil {
+		f.Warnl(f.Entry.Pos, "profile-guided layout of %s: too few samples (%d)", f.Name, f.Profile.Total)
+		return
+	}
+	var buf strings.Builder
+	for i, b := range f.Blocks {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "%v", b)
+		if line := blockLine(f, b); line != 0 {
+			fmt.Fprintf(&buf, " line %d", line)
+		}
+		switch w := weights[b.ID]; {
+		case (w == 0 || callsCold(b)) && b.Kind != BlockExit && b != f.Entry:
+			buf.WriteString(": cold")
+		case w < 0:
+			buf.WriteString(": ?")
+		default:
+			fmt.Fprintf(&buf, ": %d", w)
+		}
+	}
+	f.Warnl(f.Entry.Pos, "profile-guided layout of %s: %s", f.Name, buf.String())
+}
+
+// blockLine returns the first line of b, or 0 if b has none.
+func blockLine(f *Func, b *Block) uint {
+	pos := b.Pos
+	for _, v := range b.Values {
+		if v.Pos.IsKnown() {
+			pos = v.Pos
+			break
+		}
+	}
+	if !pos.IsKnown() {
+		return 0
+	}
+	return f.Config.ctxt.OutermostPos(pos).RelLine()
+}
@@ -218,7 +218,13 @@ func likelyadjust(f *Func) {
 				}
 			}
 			// Look for calls in the block.  If there is one, make this block unlikely.
+			// A call to a cold function is as unlikely as an exit.
 			for _, v := range b.Values {
+				if isColdCall(v) {
+					local[b.ID] = blEXIT
+					certain[b.ID] = blEXIT
+					break
+				}
 				if opcodeTable[v.Op].call {
 					local[b.ID] = blCALL
 					certain[b.ID] = max8(blCALL, certain[b.Succs[0].b.ID])
@@ -233,6 +239,16 @@ func likelyadjust(f *Func) {
 	}
 }
 
+// isColdCall reports whether v is a static call to a function marked
+// cold, with //go:cold.
+func isColdCall(v *Value) bool {
+	if !opcodeTable[v.Op].call {
+		return false
+	}
+	aux, ok := v.Aux.(*AuxCall)
+	return ok && aux.Fn != nil && aux.Fn.Cold()
+}
+
 func (l *loop) String() string {
 	return fmt.Sprintf("hdr:%s", l.header)
 }
@@ -5,6 +5,7 @@
 package ssa
 
 import (
+	"cmd/compile/internal/base"
 	"cmd/compile/internal/ir"
 	"cmd/internal/src"
 	"internal/buildcfg"
@@ -335,3 +336,26 @@ func nilcheckelim2(f *Func) {
 		// more unnecessary nil checks.  Would fix test/nilptr3.go:159.
 	}
 }
+
+// reportNilChecks reports the nil checks that remain in f after the
+// nil check elimination passes, for -d=nilcheckreport.
+func reportNilChecks(f *Func) {
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if !opcodeTable[v.Op].nilCheck {
+				continue
+			}
+			reason := "pointer is " + pointerOrigin(v.Args[0])
+			if !f.Config.optimize {
+				reason = "optimizations disabled"
+			}
+			f.reportCheck(base.Debug.NilCheckReport, checkReport{
+				pos:    v.Pos,
+				what:   "nil check",
+				Report: "nilcheck",
+				Kept:   true,
+				Reason: reason,
+			})
+		}
+	}
+}
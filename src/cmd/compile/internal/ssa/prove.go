@@ -836,6 +836,12 @@ func prove(f *Func) {
 			indVars = make(map[*Block]indVar)
 		}
 		indVars[v.entry] = v
+		if f.bce != nil {
+			if f.bce.inds == nil {
+				f.bce.inds = make(map[*Value]bool)
+			}
+			f.bce.inds[v.ind] = true
+		}
 	}
 
 	// current node state
@@ -1336,6 +1342,9 @@ func removeBranch(b *Block, branch branch) {
 			b.Func.Warnl(b.Pos, "%s %s", verb, c.Op)
 		}
 	}
+	if c != nil {
+		b.Func.bceProved(c, branch == negative)
+	}
 	if c != nil && c.Pos.IsStmt() == src.PosIsStmt && c.Pos.SameFileAndLine(b.Pos) {
 		// attempt to preserve statement marker.
 		b.Pos = b.Pos.WithIsStmt()
@@ -145,7 +145,17 @@ const (
 func regalloc(f *Func) {
 	var s regAllocState
 	s.init(f)
+	if f.HTMLWriter != nil {
+		s.trace = newRegallocTrace()
+	}
 	s.regalloc(f)
+	if s.trace != nil {
+		s.trace.finish(&s)
+		f.regallocTrace = s.trace
+	}
+	if base.Debug.SpillStats != 0 {
+		reportSpills(f)
+	}
 }
 
 type register uint8
@@ -305,6 +315,9 @@ type regAllocState struct {
 
 	// whether to insert instructions that clobber dead registers at call sites
 	doClobber bool
+
+	// trace of the allocation for ssa.html, if not nil
+	trace *regallocTrace
 }
 
 type endReg struct {
@@ -442,9 +455,10 @@ func (s *regAllocState) allocReg(mask regMask, v *Value) register {
 	// We generate a Copy and record it. It will be deleted if never used.
 	v2 := s.regs[r].v
 	m := s.compatRegs(v2.Type) &^ s.used &^ s.tmpused &^ (regMask(1) << r)
+	var c *Value
 	if m != 0 && !s.values[v2.ID].rematerializeable && countRegs(s.values[v2.ID].regs) == 1 {
 		r2 := pickReg(m)
-		c := s.curBlock.NewValue1(v2.Pos, OpCopy, v2.Type, s.regs[r].c)
+		c = s.curBlock.NewValue1(v2.Pos, OpCopy, v2.Type, s.regs[r].c)
 		s.copies[c] = false
 		if s.f.pass.debug > regDebug {
 			fmt.Printf("copy %s to %s : %s\n", v2, c, &s.registers[r2])
@@ -452,6 +466,9 @@ func (s *regAllocState) allocReg(mask regMask, v *Value) register {
 		s.setOrig(c, v2)
 		s.assignReg(r2, v2, c)
 	}
+	if s.trace != nil {
+		s.trace.evicted(s, v2, r, v, c)
+	}
 	s.freeReg(r)
 	return r
 }
@@ -1343,6 +1360,9 @@ func (s *regAllocState) regalloc(f *Func) {
 				if s.doClobber && v.Op.IsCall() {
 					s.clobberRegs(regspec.clobbers)
 				}
+				if s.trace != nil {
+					s.trace.clobbered(s, regspec.clobbers, v)
+				}
 				s.freeRegs(regspec.clobbers)
 				b.Values = append(b.Values, v)
 				s.advanceUses(v)
@@ -1555,6 +1575,9 @@ func (s *regAllocState) regalloc(f *Func) {
 				// don't clobber inputs.
 				s.clobberRegs(regspec.clobbers &^ s.tmpused &^ s.nospill)
 			}
+			if s.trace != nil {
+				s.trace.clobbered(s, regspec.clobbers, v)
+			}
 			s.freeRegs(regspec.clobbers)
 			s.tmpused |= regspec.clobbers
 
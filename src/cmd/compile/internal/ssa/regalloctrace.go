@@ -0,0 +1,396 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strings"
+)
+
+// When GOSSAFUNC is set, register allocation records a trace of what
+// it did to the function: the spills, restores, copies and
+// rematerializations it inserted, with why, and the values each
+// register holds through each block. The trace is written as a column
+// of ssa.html, after the regalloc phase, and as JSON to a file named
+// like ssa.html but with a .regalloc.json suffix.
+//
+// The trace is made from the allocated function, with the help of
+// reasons noted by the allocator as it goes, so that the allocator
+// itself does not need to know how the trace is laid out.
+
+// A regallocTrace is the register allocation trace of a function.
+type regallocTrace struct {
+	// reasons noted during allocation
+	spillWhy map[ID]string // why the (preregalloc) value was spilled
+	copyWhy  map[ID]string // why the copy was made
+
+	// filled in by finish
+	Func      string          `json:"func"`
+	Registers []string        `json:"registers"` // the registers that hold values, in the order of the table
+	Events    []regallocEvent `json:"events"`
+	Blocks    []regallocBlock `json:"blocks"`
+	Ranges    []regallocRange `json:"ranges"`
+	counts    map[string]int  // events of each kind
+}
+
+// A regallocEvent is a value inserted by register allocation.
+type regallocEvent struct {
+	Kind   string `json:"kind"`  // "spill", "restore", "copy" or "remat"
+	Value  string `json:"value"` // the inserted value, as in v42
+	Orig   string `json:"orig"`  // the value it is a spill, restore or copy of
+	Block  string `json:"block"`
+	From   string `json:"from,omitempty"` // the location of the source, if any
+	To     string `json:"to,omitempty"`   // the location of the inserted value
+	Reason string `json:"reason,omitempty"`
+}
+
+// A regallocBlock is the register state through a block: for each
+// value of the block, the value each register holds after it.
+type regallocBlock struct {
+	ID   string        `json:"id"`
+	Rows []regallocRow `json:"rows"`
+}
+
+// A regallocRow is the register state after a value.
+type regallocRow struct {
+	Value string            `json:"value"`
+	Regs  map[string]string `json:"regs,omitempty"` // register name to value, as in "AX": "v3"
+}
+
+// A regallocRange is a live range: a run of values of a block,
+// numbered from 0, after each of which a register holds a value.
+type regallocRange struct {
+	Value string `json:"value"`
+	Reg   string `json:"reg"`
+	Block string `json:"block"`
+	Start int    `json:"start"`
+	End   int    `json:"end"` // inclusive
+}
+
+func newRegallocTrace() *regallocTrace {
+	return &regallocTrace{
+		spillWhy: make(map[ID]string),
+		copyWhy:  make(map[ID]string),
+	}
+}
+
+// spillReason notes why v, a preregalloc value, had to leave its
+// register, if nothing else has been noted for it.
+func (t *regallocTrace) spillReason(v *Value, format string, args ...interface{}) {
+	if _, ok := t.spillWhy[v.ID]; !ok {
+		t.spillWhy[v.ID] = fmt.Sprintf(format, args...)
+	}
+}
+
+// evicted notes that register allocation kicked v out of register r
+// to make room for w, moving it to copy c if c is not nil.
+func (t *regallocTrace) evicted(s *regAllocState, v *Value, r register, w, c *Value) {
+	if c != nil {
+		t.copyWhy[c.ID] = fmt.Sprintf("moved out of %s to make room for %s", &s.registers[r], w)
+		return
+	}
+	t.spillReason(v, "evicted from %s to make room for %s", &s.registers[r], w)
+}
+
+// clobbered notes that the registers in m, clobbered by v, held
+// values that are used after v.
+func (t *regallocTrace) clobbered(s *regAllocState, m regMask, v *Value) {
+	m &= s.used
+	for r := register(0); m != 0; r++ {
+		if m&1 != 0 {
+			if x := s.regs[r].v; x != nil && s.values[x.ID].uses != nil {
+				t.spillReason(x, "%s clobbered by %s (%s)", &s.registers[r], v, v.Op)
+			}
+		}
+		m >>= 1
+	}
+}
+
+// finish fills in the trace from s, after allocation.
+func (t *regallocTrace) finish(s *regAllocState) {
+	f := s.f
+	t.Func = f.Name
+	t.counts = make(map[string]int)
+
+	// orig returns the preregalloc value that v stands for, and
+	// whether v was inserted by the allocator's edge shuffles.
+	orig := func(v *Value) (*Value, bool) {
+		shuffle := false
+		for {
+			if int(v.ID) < len(s.orig) && s.orig[v.ID] != nil {
+				return s.orig[v.ID], shuffle
+			}
+			switch v.Op {
+			case OpCopy, OpLoadReg, OpStoreReg:
+				shuffle = true
+				v = v.Args[0]
+				continue
+			}
+			return v, shuffle
+		}
+	}
+	loc := func(v *Value) string {
+		if l := f.getHome(v.ID); l != nil {
+			return l.String()
+		}
+		return ""
+	}
+
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			o, shuffle := orig(v)
+			if o == v || v.Op == OpPhi || v.Op == OpSP || v.Op == OpSB {
+				continue
+			}
+			e := regallocEvent{Value: v.String(), Orig: o.String(), Block: b.String(), To: loc(v)}
+			switch v.Op {
+			case OpStoreReg:
+				e.Kind = "spill"
+				e.From = loc(v.Args[0])
+				e.Reason = t.spillWhy[o.ID]
+				if e.Reason == "" {
+					e.Reason = "live in a register where it is not kept"
+				}
+			case OpLoadReg:
+				e.Kind = "restore"
+				e.From = loc(v.Args[0])
+				e.Reason = "used after it was spilled"
+			case OpCopy:
+				e.Kind = "copy"
+				e.From = loc(v.Args[0])
+				e.Reason = t.copyWhy[v.ID]
+				if e.Reason == "" {
+					e.Reason = "needed in another register"
+				}
+			default:
+				e.Kind = "remat"
+				e.Reason = "recomputed instead of restored"
+			}
+			if shuffle && len(b.Succs) == 1 {
+				e.Reason = "to merge into " + b.Succs[0].b.String()
+			}
+			t.counts[e.Kind]++
+			t.Events = append(t.Events, e)
+		}
+	}
+
+	t.liveRanges(f)
+}
+
+// liveRanges fills in the register state through each block of f and
+// the live ranges it is made of.
+func (t *regallocTrace) liveRanges(f *Func) {
+	reg := func(v *Value) *Register {
+		if r, ok := f.getHome(v.ID).(*Register); ok {
+			return r
+		}
+		return nil
+	}
+
+	byID := make([]*Value, f.NumValues())
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			byID[v.ID] = v
+		}
+	}
+
+	// Compute the values live, in registers, at the end of each block.
+	po := f.postorder()
+	liveIn := make([]map[ID]bool, f.NumBlocks())
+	liveOut := make([]map[ID]bool, f.NumBlocks())
+	for changed := true; changed; {
+		changed = false
+		for _, b := range po {
+			out := make(map[ID]bool)
+			for _, e := range b.Succs {
+				for id := range liveIn[e.b.ID] {
+					out[id] = true
+				}
+				for _, v := range e.b.Values {
+					if v.Op == OpPhi && reg(v.Args[e.i]) != nil {
+						out[v.Args[e.i].ID] = true
+					}
+				}
+			}
+			live := make(map[ID]bool, len(out))
+			for id := range out {
+				live[id] = true
+			}
+			for _, c := range b.ControlValues() {
+				if reg(c) != nil {
+					live[c.ID] = true
+				}
+			}
+			for i := len(b.Values) - 1; i >= 0; i-- {
+				v := b.Values[i]
+				delete(live, v.ID)
+				if v.Op == OpPhi {
+					continue
+				}
+				for _, a := range v.Args {
+					if reg(a) != nil {
+						live[a.ID] = true
+					}
+				}
+			}
+			if len(live) != len(liveIn[b.ID]) || len(out) != len(liveOut[b.ID]) {
+				changed = true
+			}
+			liveIn[b.ID], liveOut[b.ID] = live, out
+		}
+	}
+
+	// Walk each block backwards from its end, recording the register
+	// state after each value.
+	used := make(map[*Register]bool)
+	for _, b := range f.Blocks {
+		rb := regallocBlock{ID: b.String(), Rows: make([]regallocRow, len(b.Values))}
+		live := make(map[ID]*Value)
+		for id := range liveOut[b.ID] {
+			live[id] = byID[id]
+		}
+		for _, c := range b.ControlValues() {
+			if reg(c) != nil {
+				live[c.ID] = c
+			}
+		}
+		for i := len(b.Values) - 1; i >= 0; i-- {
+			v := b.Values[i]
+			row := regallocRow{Value: v.String()}
+			if reg(v) != nil {
+				live[v.ID] = v
+			}
+			for _, x := range live {
+				if row.Regs == nil {
+					row.Regs = make(map[string]string)
+				}
+				r := reg(x)
+				used[r] = true
+				row.Regs[r.String()] = x.String()
+			}
+			rb.Rows[i] = row
+			delete(live, v.ID)
+			if v.Op == OpPhi {
+				continue
+			}
+			for _, a := range v.Args {
+				if reg(a) != nil {
+					live[a.ID] = a
+				}
+			}
+		}
+		t.Blocks = append(t.Blocks, rb)
+	}
+
+	var regs []*Register
+	for r := range used {
+		regs = append(regs, r)
+	}
+	sort.Slice(regs, func(i, j int) bool { return regs[i].num < regs[j].num })
+	for _, r := range regs {
+		t.Registers = append(t.Registers, r.String())
+	}
+
+	for _, rb := range t.Blocks {
+		for _, r := range t.Registers {
+			start := -1
+			for i := 0; i <= len(rb.Rows); i++ {
+				var v string
+				if i < len(rb.Rows) {
+					v = rb.Rows[i].Regs[r]
+				}
+				if start >= 0 && v != rb.Rows[start].Regs[r] {
+					t.Ranges = append(t.Ranges, regallocRange{Value: rb.Rows[start].Regs[r], Reg: r, Block: rb.ID, Start: start, End: i - 1})
+					start = -1
+				}
+				if start < 0 && v != "" {
+					start = i
+				}
+			}
+		}
+	}
+}
+
+// write writes t to the HTML of f and as JSON next to it.
+func (t *regallocTrace) write(f *Func) {
+	w := f.HTMLWriter
+	w.flushPhases()
+	w.WriteColumn("regalloc trace", "regalloc trace", "allow-x-scroll", t.html(f))
+
+	path := strings.TrimSuffix(w.path, ".html") + ".regalloc.json"
+	out, err := os.Create(path)
+	if err != nil {
+		f.Warnl(f.Entry.Pos, "Unable to write regalloc trace of %s: %v", f.Name, err)
+		return
+	}
+	defer out.Close()
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(t); err != nil {
+		f.Warnl(f.Entry.Pos, "Unable to write regalloc trace of %s: %v", f.Name, err)
+		return
+	}
+	fmt.Printf("dumped regalloc trace to %v\n", path)
+}
+
+// html returns the HTML of the column of t.
+func (t *regallocTrace) html(f *Func) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<p>%d spills, %d restores, %d copies, %d rematerializations</p>\n",
+		t.counts["spill"], t.counts["restore"], t.counts["copy"], t.counts["remat"])
+
+	values := make(map[string]*Value)
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			values[v.String()] = v
+		}
+	}
+	valueHTML := func(s string) string {
+		if v := values[s]; v != nil {
+			return v.HTML()
+		}
+		return html.EscapeString(s)
+	}
+
+	if len(t.Events) > 0 {
+		buf.WriteString("<table class=\"regalloc-events\">\n<tr><th>value</th><th>kind</th><th>of</th><th>in</th><th>from</th><th>to</th><th>reason</th></tr>\n")
+		for _, e := range t.Events {
+			fmt.Fprintf(&buf, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				valueHTML(e.Value), e.Kind, valueHTML(e.Orig), e.Block,
+				html.EscapeString(e.From), html.EscapeString(e.To), html.EscapeString(e.Reason))
+		}
+		buf.WriteString("</table>\n")
+	}
+
+	for _, rb := range t.Blocks {
+		fmt.Fprintf(&buf, "<table class=\"regalloc-regs\">\n<tr><th>%s</th>", rb.ID)
+		for _, r := range t.Registers {
+			fmt.Fprintf(&buf, "<th>%s</th>", r)
+		}
+		buf.WriteString("</tr>\n")
+		for _, row := range rb.Rows {
+			fmt.Fprintf(&buf, "<tr><td>%s</td>", valueHTML(row.Value))
+			for _, r := range t.Registers {
+				v := row.Regs[r]
+				switch {
+				case v == "":
+					buf.WriteString("<td></td>")
+				case v == row.Value:
+					fmt.Fprintf(&buf, "<td class=\"regalloc-def\">%s</td>", valueHTML(v))
+				default:
+					fmt.Fprintf(&buf, "<td class=\"regalloc-live\">%s</td>", valueHTML(v))
+				}
+			}
+			buf.WriteString("</tr>\n")
+		}
+		buf.WriteString("</table>\n")
+	}
+	return buf.String()
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"strings"
+	"testing"
+)
+
+// TestRegallocTrace checks that the register allocation trace explains
+// the spill of a value that is live across a call, and shows the
+// value in a register where it is defined.
+func TestRegallocTrace(t *testing.T) {
+	c := testConfig(t)
+	f := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sp", OpSP, c.config.Types.Uintptr, 0, nil),
+			Valu("ld", OpAMD64MOVQload, c.config.Types.Int64, 0, nil, "sp", "mem"),
+			Valu("call", OpAMD64CALLstatic, types.TypeMem, 0, AuxCallLSym("_"), "mem"),
+			Valu("st", OpAMD64MOVQstore, types.TypeMem, 0, nil, "sp", "ld", "call"),
+			Exit("st"),
+		),
+	)
+	var s regAllocState
+	s.init(f.f)
+	s.trace = newRegallocTrace()
+	s.regalloc(f.f)
+	s.trace.finish(&s)
+	checkFunc(f.f)
+
+	ld := f.values["ld"].String()
+	var spill *regallocEvent
+	for i, e := range s.trace.Events {
+		if e.Kind == "spill" && e.Orig == ld {
+			spill = &s.trace.Events[i]
+		}
+	}
+	if spill == nil {
+		t.Fatalf("no spill of %s in %+v", ld, s.trace.Events)
+	}
+	if want := "clobbered by " + f.values["call"].String(); !strings.Contains(spill.Reason, want) {
+		t.Errorf("spill of %s has reason %q, want one containing %q", ld, spill.Reason, want)
+	}
+
+	found := false
+	for _, rb := range s.trace.Blocks {
+		for _, row := range rb.Rows {
+			if row.Value != ld {
+				continue
+			}
+			for _, v := range row.Regs {
+				if v == ld {
+					found = true
+				}
+			}
+		}
+	}
+	if !found {
+		t.Errorf("%s is in no register after it is defined: %+v", ld, s.trace.Blocks)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/logopt"
+	"cmd/internal/src"
+)
+
+// The -d=bce, -d=nilcheckreport and -d=wbreport reports list the
+// runtime checks and write barriers of the compiled functions that
+// are kept, with why they could not be removed, and for bounds
+// checks, those that are removed, with why. With a flag set to 1, its
+// report is made of compiler messages, like those of -m. With it set
+// to 2, the report is collected and printed as JSON objects by
+// DumpCheckReports. The bounds checks are also reported with -json,
+// in the optimization log.
+
+// A checkReport describes a runtime check or write barrier of a
+// function, for one of the reports.
+type checkReport struct {
+	pos  src.XPos
+	what string // in the compiler message, as in "nil check"
+
+	Report string `json:"report"` // "bce", "nilcheck" or "wb"
+	Pos    string `json:"pos"`
+	Func   string `json:"func"`
+	Kind   string `json:"kind,omitempty"` // such as "index" or "store"
+	Kept   bool   `json:"kept"`
+	Reason string `json:"reason"`
+}
+
+// checkReports holds the reports collected for DumpCheckReports, under
+// checkMu, as functions are compiled concurrently.
+var (
+	checkMu      sync.Mutex
+	checkReports []checkReport
+)
+
+// reportCheck reports r, about f, as a compiler message if mode, the
+// value of the -d flag of r's report, is 1, or collects it for
+// DumpCheckReports if mode is 2. With -json, r is also logged, as a
+// diagnostic whose code is the name of its report followed by "Kept"
+// or "Removed", such as "bceRemoved".
+func (f *Func) reportCheck(mode int, r checkReport) {
+	verb := "removed"
+	if r.Kept {
+		verb = "kept"
+	}
+	if logopt.Enabled() {
+		code := r.Report + "Removed"
+		if r.Kept {
+			code = r.Report + "Kept"
+		}
+		logopt.LogOpt(r.pos, code, r.Report, f.Name, r.what+": "+r.Reason)
+	}
+	switch mode {
+	case 1:
+		f.Warnl(r.pos, "%s %s: %s", r.what, verb, r.Reason)
+	case 2:
+		r.Func = f.Name
+		checkMu.Lock()
+		checkReports = append(checkReports, r)
+		checkMu.Unlock()
+	}
+}
+
+// DumpCheckReports prints the reports collected with -d=bce=2,
+// -d=nilcheckreport=2 or -d=wbreport=2 as JSON objects, sorted by
+// position. It must be called after all functions have been compiled.
+func DumpCheckReports() {
+	sort.SliceStable(checkReports, func(i, j int) bool {
+		return checkReports[i].pos.Before(checkReports[j].pos)
+	})
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	for _, r := range checkReports {
+		r.Pos = base.FmtPos(r.pos)
+		enc.Encode(r)
+	}
+}
+
+// pointerOrigin describes where the pointer v comes from, to explain
+// the runtime checks and write barriers it needs, as in "a parameter".
+func pointerOrigin(v *Value) string {
+	for v.Op == OpOffPtr || v.Op == OpAddPtr || v.Op == OpPtrIndex || v.Op == OpCopy {
+		v = v.Args[0]
+	}
+	switch {
+	case v.Op == OpArg || v.Op == OpArgIntReg:
+		return "a parameter"
+	case v.Op == OpPhi:
+		return "merged from several paths"
+	case v.Op == OpSelectN && opcodeTable[v.Args[0].Op].call:
+		return "returned by a call"
+	case v.Op == OpAddr && v.Args[0].Op == OpSB:
+		return "a global"
+	case v.MemoryArg() != nil:
+		return "loaded from memory"
+	}
+	return "computed by " + v.Op.String()
+}
@@ -1136,6 +1136,43 @@ func rewriteValueAMD64(v *Value) bool {
 	case OpTrunc64to8:
 		v.Op = OpCopy
 		return true
+	case OpVecAdd16:
+		v.Op = OpAMD64PADDW
+		return true
+	case OpVecAdd32:
+		v.Op = OpAMD64PADDL
+		return true
+	case OpVecAdd64:
+		v.Op = OpAMD64PADDQ
+		return true
+	case OpVecAdd8:
+		v.Op = OpAMD64PADDB
+		return true
+	case OpVecAnd:
+		v.Op = OpAMD64PAND
+		return true
+	case OpVecLoad:
+		return rewriteValueAMD64_OpVecLoad(v)
+	case OpVecOr:
+		v.Op = OpAMD64POR
+		return true
+	case OpVecStore:
+		return rewriteValueAMD64_OpVecStore(v)
+	case OpVecSub16:
+		v.Op = OpAMD64PSUBW
+		return true
+	case OpVecSub32:
+		v.Op = OpAMD64PSUBL
+		return true
+	case OpVecSub64:
+		v.Op = OpAMD64PSUBQ
+		return true
+	case OpVecSub8:
+		v.Op = OpAMD64PSUBB
+		return true
+	case OpVecXor:
+		v.Op = OpAMD64PXOR
+		return true
 	case OpWB:
 		v.Op = OpAMD64LoweredWB
 		return true
@@ -33358,6 +33395,34 @@ func rewriteValueAMD64_OpTrunc(v *Value) bool {
 		return true
 	}
 }
+func rewriteValueAMD64_OpVecLoad(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecLoad ptr mem)
+	// result: (MOVOload ptr mem)
+	for {
+		ptr := v_0
+		mem := v_1
+		v.reset(OpAMD64MOVOload)
+		v.AddArg2(ptr, mem)
+		return true
+	}
+}
+func rewriteValueAMD64_OpVecStore(v *Value) bool {
+	v_2 := v.Args[2]
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecStore ptr val mem)
+	// result: (MOVOstore ptr val mem)
+	for {
+		ptr := v_0
+		val := v_1
+		mem := v_2
+		v.reset(OpAMD64MOVOstore)
+		v.AddArg3(ptr, val, mem)
+		return true
+	}
+}
 func rewriteValueAMD64_OpZero(v *Value) bool {
 	v_1 := v.Args[1]
 	v_0 := v.Args[0]
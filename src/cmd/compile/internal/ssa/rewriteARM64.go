@@ -1092,6 +1092,43 @@ func rewriteValueARM64(v *Value) bool {
 	case OpTrunc64to8:
 		v.Op = OpCopy
 		return true
+	case OpVecAdd16:
+		v.Op = OpARM64VADDH
+		return true
+	case OpVecAdd32:
+		v.Op = OpARM64VADDS
+		return true
+	case OpVecAdd64:
+		v.Op = OpARM64VADDD
+		return true
+	case OpVecAdd8:
+		v.Op = OpARM64VADDB
+		return true
+	case OpVecAnd:
+		v.Op = OpARM64VAND
+		return true
+	case OpVecLoad:
+		return rewriteValueARM64_OpVecLoad(v)
+	case OpVecOr:
+		v.Op = OpARM64VORR
+		return true
+	case OpVecStore:
+		return rewriteValueARM64_OpVecStore(v)
+	case OpVecSub16:
+		v.Op = OpARM64VSUBH
+		return true
+	case OpVecSub32:
+		v.Op = OpARM64VSUBS
+		return true
+	case OpVecSub64:
+		v.Op = OpARM64VSUBD
+		return true
+	case OpVecSub8:
+		v.Op = OpARM64VSUBB
+		return true
+	case OpVecXor:
+		v.Op = OpARM64VEOR
+		return true
 	case OpWB:
 		v.Op = OpARM64LoweredWB
 		return true
@@ -26881,6 +26918,34 @@ func rewriteValueARM64_OpStore(v *Value) bool {
 	}
 	return false
 }
+func rewriteValueARM64_OpVecLoad(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecLoad ptr mem)
+	// result: (FMOVQload ptr mem)
+	for {
+		ptr := v_0
+		mem := v_1
+		v.reset(OpARM64FMOVQload)
+		v.AddArg2(ptr, mem)
+		return true
+	}
+}
+func rewriteValueARM64_OpVecStore(v *Value) bool {
+	v_2 := v.Args[2]
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecStore ptr val mem)
+	// result: (FMOVQstore ptr val mem)
+	for {
+		ptr := v_0
+		val := v_1
+		mem := v_2
+		v.reset(OpARM64FMOVQstore)
+		v.AddArg3(ptr, val, mem)
+		return true
+	}
+}
 func rewriteValueARM64_OpZero(v *Value) bool {
 	v_1 := v.Args[1]
 	v_0 := v.Args[0]
@@ -5,6 +5,7 @@
 package ssa
 
 import (
+	"cmd/compile/internal/base"
 	"cmd/compile/internal/types"
 	"container/heap"
 	"sort"
@@ -110,6 +111,9 @@ func schedule(f *Func) {
 	nextMem := make([]*Value, f.NumValues())
 	// additional pretend arguments for each Value. Used to enforce load/store ordering.
 	additionalArgs := make([][]*Value, f.NumValues())
+	// loads that may be scheduled after stores before them
+	relaxed := f.newSparseSet(f.NumValues())
+	defer f.retSparseSet(relaxed)
 
 	for _, b := range f.Blocks {
 		// Compute score. Larger numbers are scheduled closer to the end of the block.
@@ -202,13 +206,18 @@ func schedule(f *Func) {
 				if w.Block == b {
 					uses[w.ID]++
 				}
-				// Any load must come before the following store.
+				// Any load must come before the following store
+				// that may alias it.
 				if !v.Type.IsMemory() && w.Type.IsMemory() {
 					// v is a load.
 					s := nextMem[w.ID]
 					if s == nil || s.Block != b {
 						continue
 					}
+					if t := aliasingStore(f, v, s, nextMem); t != s {
+						relaxed.add(v.ID)
+						s = t
+					}
 					additionalArgs[s.ID] = append(additionalArgs[s.ID], v)
 					uses[v.ID]++
 				}
@@ -329,11 +338,71 @@ func schedule(f *Func) {
 		for i := 0; i < len(b.Values); i++ {
 			b.Values[i] = order[len(b.Values)-1-i]
 		}
+
+		// The loads scheduled after stores that do not alias them
+		// read the memory state they are scheduled at instead, so
+		// that only one memory state is live at a time.
+		if relaxed.size() > 0 {
+			var mem *Value
+			for _, v := range b.Values {
+				if relaxed.contains(v.ID) && mem != nil && v.MemoryArg() != mem {
+					v.SetArg(len(v.Args)-1, mem)
+				}
+				if v.Type.IsMemory() {
+					mem = v
+				}
+			}
+			relaxed.clear()
+		}
+	}
+
+	if base.Debug.AliasStats != 0 {
+		s := &f.aliasStats
+		queries, noAlias := 0, 0
+		for _, u := range s {
+			queries += u.queries
+			noAlias += u.noAlias
+		}
+		f.Warnl(f.Entry.Pos, "alias analysis of %s: %d queries, %d no alias (cse %d/%d, dse %d/%d, gvn %d/%d, schedule %d/%d)",
+			f.Name, queries, noAlias,
+			s[aliasCSE].noAlias, s[aliasCSE].queries,
+			s[aliasDSE].noAlias, s[aliasDSE].queries,
+			s[aliasGVN].noAlias, s[aliasGVN].queries,
+			s[aliasSchedule].noAlias, s[aliasSchedule].queries)
 	}
 
 	f.scheduled = true
 }
 
+// aliasingStore returns the store that the load v, which reads the
+// memory state before the store s, must be scheduled before: the first
+// store of the chain from s in s's block that the alias oracle finds
+// may alias v, or cannot look past. Only loads that cannot fault are
+// scheduled after other stores, since a store before a faulting load
+// would be visible to a recovered panic, and would come between the
+// load and the nil check that late nilcheck removes because of it.
+func aliasingStore(f *Func, v, s *Value, nextMem []*Value) *Value {
+	ld, ok := memAccessOf(v)
+	if !ok || ld.write || opcodeTable[v.Op].faultOnNilArg0 && !nonNilPtr(ld.ptr) {
+		return s
+	}
+	b := s.Block
+	for i := 0; i < aliasMaxWalk; i++ {
+		st, ok := memAccessOf(s)
+		if !ok || !st.write || f.mayAlias(aliasSchedule, ld, st) {
+			return s
+		}
+		next := nextMem[s.ID]
+		if next == nil || next.Block != b {
+			// s is the last store of the block, or a tuple
+			// op takes its memory.
+			return s
+		}
+		s = next
+	}
+	return s
+}
+
 // storeOrder orders values with respect to stores. That is,
 // if v transitively depends on store s, v is ordered after s,
 // otherwise v is ordered before s.
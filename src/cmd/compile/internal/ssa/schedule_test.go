@@ -26,6 +26,20 @@ func TestSchedule(t *testing.T) {
 				Goto("exit")),
 			Bloc("exit",
 				Exit("mem3"))),
+		c.Fun("entry",
+			Bloc("entry",
+				Valu("mem0", OpInitMem, types.TypeMem, 0, nil),
+				Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+				Valu("a", OpAddr, c.config.Types.Int64.PtrTo(), 0, nil, "sb"),
+				Valu("b", OpOffPtr, c.config.Types.Int64.PtrTo(), 8, nil, "a"),
+				Valu("v", OpConst64, c.config.Types.Int64, 12, nil),
+				Valu("l1", OpLoad, c.config.Types.Int64, 0, nil, "a", "mem0"),
+				Valu("mem1", OpStore, types.TypeMem, 0, c.config.Types.Int64, "b", "v", "mem0"),
+				Valu("sum", OpAdd64, c.config.Types.Int64, 0, nil, "l1", "v"),
+				Valu("mem2", OpStore, types.TypeMem, 0, c.config.Types.Int64, "b", "sum", "mem1"),
+				Goto("exit")),
+			Bloc("exit",
+				Exit("mem2"))),
 	}
 	for _, c := range cases {
 		schedule(c.f)
@@ -0,0 +1,85 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+
+	"cmd/compile/internal/base"
+	"cmd/internal/src"
+)
+
+// The -d=spillstats report counts, for each compiled function, the
+// spills and reloads that register allocation inserted and the bytes
+// of stack its spill slots take, to measure register pressure. With
+// the flag set to 1, the report is made of compiler messages, like
+// those of -m. With it set to 2, the report is collected and printed
+// as JSON objects by DumpSpillStats.
+
+// A spillStats holds the counts of the -d=spillstats report for a
+// function.
+type spillStats struct {
+	pos src.XPos
+
+	Pos        string `json:"pos"`
+	Func       string `json:"func"`
+	Spills     int    `json:"spills"`
+	Reloads    int    `json:"reloads"`
+	SpillBytes int64  `json:"spill_bytes"`
+}
+
+// spillReports holds the reports collected for DumpSpillStats, under
+// spillMu, as functions are compiled concurrently.
+var (
+	spillMu      sync.Mutex
+	spillReports []spillStats
+)
+
+// reportSpills reports the spills and reloads of f, after register
+// allocation, for -d=spillstats.
+func reportSpills(f *Func) {
+	r := spillStats{pos: f.Entry.Pos, Func: f.Name}
+	slots := make(map[LocalSlot]bool)
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			switch v.Op {
+			case OpStoreReg:
+				r.Spills++
+				if slot, ok := f.getHome(v.ID).(LocalSlot); ok && !slots[slot] {
+					slots[slot] = true
+					r.SpillBytes += slot.Type.Size()
+				}
+			case OpLoadReg:
+				r.Reloads++
+			}
+		}
+	}
+
+	if base.Debug.SpillStats == 2 {
+		spillMu.Lock()
+		spillReports = append(spillReports, r)
+		spillMu.Unlock()
+		return
+	}
+	f.Warnl(r.pos, "spills of %s: %d spills, %d reloads, %d bytes of spill slots", r.Func, r.Spills, r.Reloads, r.SpillBytes)
+}
+
+// DumpSpillStats prints the reports collected with -d=spillstats=2 as
+// JSON objects, sorted by position. It must be called after all
+// functions have been compiled.
+func DumpSpillStats() {
+	sort.SliceStable(spillReports, func(i, j int) bool {
+		return spillReports[i].pos.Before(spillReports[j].pos)
+	})
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	for _, r := range spillReports {
+		r.Pos = base.FmtPos(r.pos)
+		enc.Encode(r)
+	}
+}
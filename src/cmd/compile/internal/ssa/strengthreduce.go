@@ -0,0 +1,129 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "cmd/compile/internal/base"
+
+// strengthReduce replaces the multiplications of the index of a
+// counted loop by a constant, as in the addresses of slice elements
+// whose size is not a power of two,
+//
+//	for i := range s {
+//		s[i].x = 0 // *(SlicePtr(s) + i*24) = 0
+//	}
+//
+// with a new induction variable, which is incremented by the constant
+// times the index's increment in each iteration:
+//
+//	header:
+//		i = Phi(min, i+inc), o = Phi(min*24, o+inc*24)
+//	body:
+//		*(SlicePtr(s) + o) = 0
+//
+// o is i*24 modulo 2⁶⁴ in every iteration and after the loop, so it
+// replaces all the uses of i*24 in the loop. Multiplications by powers
+// of two are left alone, since they are shifts that most architectures
+// fold into their addressing modes.
+//
+// The pass runs after prove, with the same induction variables, so
+// that prove removes the bounds checks of i before they are hidden in
+// the new variables. With -m=2, it reports the loops it transforms.
+//
+// Each new variable is live throughout its loop. Over the standard
+// library, -d=spillstats shows that this only ever adds spills and
+// reloads, so the pass is experimental and only enabled by
+// -d=strengthreduce.
+func strengthReduce(f *Func) {
+	if base.Debug.StrengthReduce == 0 {
+		return
+	}
+	ivs := findIndVar(f)
+	if len(ivs) == 0 {
+		return
+	}
+	index := make(map[*Value]int, len(ivs)) // index in ivs of each induction variable
+	for i, iv := range ivs {
+		index[iv.ind] = i
+	}
+
+	// Find the multiplications of each induction variable by a
+	// constant within its loop.
+	ln := f.loopnest()
+	muls := make([][]*Value, len(ivs))
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if v.Op != OpMul64 {
+				continue
+			}
+			for j := 0; j < 2; j++ {
+				i, ok := index[v.Args[j]]
+				if !ok || v.Args[1-j].Op != OpConst64 || isPowerOfTwo64(v.Args[1-j].AuxInt) || !blockInLoop(ln, b, ivs[i].ind.Block) {
+					continue
+				}
+				muls[i] = append(muls[i], v)
+				break
+			}
+		}
+	}
+
+	for i, iv := range ivs {
+		if len(muls[i]) == 0 {
+			continue
+		}
+		header := iv.ind.Block
+		min, inc, nxt := parseIndVar(iv.ind)
+		inside := 0 // index of the pred of header in the loop
+		if iv.ind.Args[1] == nxt {
+			inside = 1
+		}
+		pre := header.Preds[1-inside].b
+
+		reduced := make(map[int64]*Value)
+		var order []int64
+		for _, m := range muls[i] {
+			c := m.Args[0]
+			if c.Op != OpConst64 || c == iv.ind {
+				c = m.Args[1]
+			}
+			o := reduced[c.AuxInt]
+			if o == nil {
+				o = header.NewValue0(iv.ind.Pos, OpPhi, iv.ind.Type)
+				var init *Value
+				if min.Op == OpConst64 {
+					init = f.ConstInt64(iv.ind.Type, min.AuxInt*c.AuxInt)
+				} else {
+					init = pre.NewValue2(m.Pos, OpMul64, iv.ind.Type, min, c)
+				}
+				next := nxt.Block.NewValue2(nxt.Pos, OpAdd64, iv.ind.Type, o, f.ConstInt64(iv.ind.Type, inc.AuxInt*c.AuxInt))
+				if inside == 0 {
+					o.AddArg2(next, init)
+				} else {
+					o.AddArg2(init, next)
+				}
+				reduced[c.AuxInt] = o
+				order = append(order, c.AuxInt)
+			}
+			m.copyOf(o)
+		}
+		if base.Flag.LowerM >= 2 {
+			for _, c := range order {
+				f.Warnl(header.Controls[0].Pos, "strength-reduced loop index * %d", c)
+			}
+		}
+		if f.pass.stats > 0 {
+			f.LogStat("STRENGTH_REDUCED", len(order))
+		}
+	}
+}
+
+// blockInLoop reports whether b is in the loop with the given header.
+func blockInLoop(ln *loopnest, b, header *Block) bool {
+	for l := ln.b2l[b.ID]; l != nil; l = l.outer {
+		if l.header == header {
+			return true
+		}
+	}
+	return false
+}
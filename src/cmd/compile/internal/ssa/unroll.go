@@ -0,0 +1,297 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/base"
+	"fmt"
+	"math"
+)
+
+const (
+	unrollFactor    = 4   // how many times -d=unroll unrolls loops without //go:unroll
+	unrollMaxBody   = 16  // most values in the body of a loop that -d=unroll unrolls without //go:unroll
+	unrollMaxValues = 512 // most values in the body of an unrolled loop
+)
+
+// unroll unrolls counted loops, such as
+//
+//	for i := 0; i < len(a); i++ {
+//		s += a[i]
+//	}
+//
+// to do U iterations of the loop body at a time. An unrolled loop is
+// put in front of the original loop, which is kept to do the
+// iterations that remain, if fewer than U:
+//
+//	pre:
+//		if max >= MinInt64+(U-1)*inc → uheader, else → header
+//	uheader:
+//		ui = Phi(min, ui+U*inc), ux = Phi(x0, x'), ...
+//		if ui < max-(U-1)*inc → ubody, else → header
+//	ubody:
+//		the body with i = ui, then with i = ui+inc, ... ui+(U-1)*inc,
+//		each copy taking the loop-carried values of the one before it
+//		→ uheader
+//	header:
+//		i = Phi(min, ui, i+inc), x = Phi(x0, ux, x'), ...  (the original loop)
+//
+// A loop that runs a constant number of iterations, at most U, is
+// unrolled completely, and the original loop is removed.
+//
+// Loops are unrolled after prove, and only if their body is a single
+// block, that is, if prove has removed all of its bounds checks. The
+// copies of the body need none either, since their index is within
+// min <= i < max too.
+//
+// The pass unrolls the loops marked //go:unroll N by N. With
+// -d=unroll=n, it also unrolls the loops with short bodies, by n if
+// it is greater than 1 or else by 4, and reports which loops are
+// unrolled, and why the other counted loops are not.
+func unroll(f *Func) {
+	if len(f.Unroll) == 0 && base.Debug.Unroll == 0 {
+		return
+	}
+	for _, iv := range findIndVar(f) {
+		header := iv.ind.Block
+		pos := header.Controls[0].Pos
+		n, ok := f.Unroll[header]
+		if !ok {
+			if base.Debug.Unroll == 0 {
+				continue
+			}
+			n = unrollFactor
+			if base.Debug.Unroll > 1 {
+				n = base.Debug.Unroll
+			}
+		}
+		what, why := unrollLoop(f, iv, n, !ok)
+		if base.Debug.Unroll == 0 {
+			continue
+		}
+		if why != "" {
+			f.Warnl(pos, "loop not unrolled: %s", why)
+		} else {
+			f.Warnl(pos, "loop unrolled %s", what)
+		}
+	}
+}
+
+// unrollLoop unrolls the loop of iv n times, or completely if it
+// runs at most n iterations. If short is set, only a loop with a short
+// body is unrolled. unrollLoop returns a description of how the loop
+// was unrolled, or why it was not.
+func unrollLoop(f *Func, iv indVar, n int, short bool) (what, why string) {
+	header := iv.ind.Block
+	body := iv.entry
+	if n < 2 {
+		return "", fmt.Sprintf("//go:unroll %d", n)
+	}
+	if iv.flags != 0 {
+		return "", "bounds are not min <= i < max"
+	}
+	if body.Kind == BlockIf && (body.Controls[0].Op == OpIsInBounds || body.Controls[0].Op == OpIsSliceInBounds) {
+		return "", "loop body has bounds checks"
+	}
+	if body.Kind != BlockPlain || body.Succs[0].b != header || len(body.Preds) != 1 {
+		return "", "loop body is not a single block"
+	}
+	outside := 0 // index of the pred of header outside the loop
+	if header.Preds[0].b == body {
+		outside = 1
+	}
+	inside := 1 - outside
+	if header.Preds[inside].b != body {
+		return "", "loop body is not a single block"
+	}
+	pre := header.Preds[outside].b
+
+	var phis []*Value
+	for _, v := range header.Values {
+		switch {
+		case v.Op == OpPhi:
+			phis = append(phis, v)
+		case v != header.Controls[0]:
+			return "", "loop header computes " + v.Op.String()
+		}
+	}
+	max := iv.max
+	if max.Block == header || max.Block == body {
+		return "", "loop bound is computed in the loop"
+	}
+	for _, v := range body.Values {
+		if opcodeTable[v.Op].call {
+			return "", "loop body has calls"
+		}
+		for _, a := range v.Args {
+			if a == header.Controls[0] {
+				return "", "loop body uses the loop condition"
+			}
+		}
+	}
+
+	_, incv, _ := parseIndVar(iv.ind)
+	inc := incv.AuxInt
+	u := int64(n)
+	trips := int64(-1) // number of iterations, if constant
+	if iv.min.Op == OpConst64 && max.Op == OpConst64 && max.AuxInt > iv.min.AuxInt {
+		d := uint64(max.AuxInt) - uint64(iv.min.AuxInt)
+		trips = int64((d-1)/uint64(inc) + 1)
+	}
+	full := trips >= 0 && trips <= u
+	if full {
+		u = trips
+	} else if short && len(body.Values) > unrollMaxBody {
+		return "", "loop body is too large"
+	}
+	if u*int64(len(body.Values)) > unrollMaxValues {
+		return "", "unrolled loop body would be too large"
+	}
+	if inc > math.MaxInt64/u {
+		return "", "index step is too large"
+	}
+
+	if full {
+		// Replace the loop with u copies of its body, and make
+		// the header exit at once.
+		ubody := f.NewBlock(BlockPlain)
+		ubody.Pos = body.Pos
+		k := header.Preds[outside].i
+		pre.Succs[k] = Edge{ubody, 0}
+		ubody.Preds = append(ubody.Preds, Edge{pre, k})
+		ubody.Succs = append(ubody.Succs, Edge{header, outside})
+		header.Preds[outside] = Edge{ubody, 0}
+
+		vals := make(map[*Value]*Value, len(phis))
+		for _, p := range phis {
+			vals[p] = p.Args[outside]
+		}
+		for t := int64(0); t < u; t++ {
+			vals[iv.ind] = f.ConstInt64(iv.ind.Type, iv.min.AuxInt+t*inc)
+			vals = unrollCopy(ubody, body, phis, vals, inside)
+		}
+		vals[iv.ind] = f.ConstInt64(iv.ind.Type, iv.min.AuxInt+u*inc)
+		for _, p := range phis {
+			p.SetArg(outside, vals[p])
+		}
+		header.ResetControls()
+		header.Kind = BlockFirst
+		header.swapSuccessors()
+		header.Likely = BranchUnknown
+		f.invalidateCFG()
+		return fmt.Sprintf("completely, %d iterations", u), ""
+	}
+
+	pos := header.Controls[0].Pos
+	typs := &f.Config.Types
+	span := (u - 1) * inc
+
+	// The unrolled loop is entered only if max >= MinInt64+span, so
+	// that lim does not overflow.
+	var safe *Value
+	if max.Op == OpConst64 {
+		if max.AuxInt < math.MinInt64+span {
+			return "", "loop bound is too small"
+		}
+	} else {
+		safe = pre.NewValue2(pos, OpLeq64, typs.Bool, f.ConstInt64(typs.Int64, math.MinInt64+span), max)
+	}
+	lim := pre.NewValue2(pos, OpSub64, typs.Int64, max, f.ConstInt64(typs.Int64, span))
+
+	uheader := f.NewBlock(BlockIf)
+	ubody := f.NewBlock(BlockPlain)
+	uheader.Pos, ubody.Pos = header.Pos, body.Pos
+	uheader.Likely = BranchLikely
+
+	// Enter the unrolled loop instead of the original loop, if it is
+	// safe to, and make the original loop continue after the
+	// unrolled loop.
+	enter := pre
+	k := header.Preds[outside].i
+	if safe != nil {
+		enter = f.NewBlock(BlockIf)
+		enter.Pos = header.Pos
+		enter.SetControl(safe)
+		enter.Likely = BranchLikely
+		pre.Succs[k] = Edge{enter, 0}
+		enter.Preds = append(enter.Preds, Edge{pre, k})
+		enter.AddEdgeTo(uheader)
+		enter.Succs = append(enter.Succs, Edge{header, outside})
+		header.Preds[outside] = Edge{enter, 1}
+	} else {
+		pre.Succs[k] = Edge{uheader, 0}
+		uheader.Preds = append(uheader.Preds, Edge{pre, k})
+	}
+	uheader.AddEdgeTo(ubody)
+	ubody.AddEdgeTo(uheader)
+
+	uphis := make([]*Value, len(phis))
+	vals := make(map[*Value]*Value, len(phis))
+	for i, p := range phis {
+		uphis[i] = uheader.NewValue0(p.Pos, OpPhi, p.Type)
+		vals[p] = uphis[i]
+	}
+	ui := vals[iv.ind]
+	uheader.SetControl(uheader.NewValue2(pos, OpLess64, typs.Bool, ui, lim))
+	for t := int64(0); t < u; t++ {
+		if t > 0 {
+			vals[iv.ind] = ubody.NewValue2(pos, OpAdd64, iv.ind.Type, ui, f.ConstInt64(iv.ind.Type, t*inc))
+		}
+		vals = unrollCopy(ubody, body, phis, vals, inside)
+	}
+	for i, p := range phis {
+		uphis[i].AddArg2(p.Args[outside], vals[p])
+	}
+
+	if safe != nil {
+		header.Preds = append(header.Preds, Edge{uheader, 1})
+		uheader.Succs = append(uheader.Succs, Edge{header, len(header.Preds) - 1})
+		for i, p := range phis {
+			p.AddArg(uphis[i])
+		}
+	} else {
+		header.Preds[outside] = Edge{uheader, 1}
+		uheader.Succs = append(uheader.Succs, Edge{header, outside})
+		for i, p := range phis {
+			p.SetArg(outside, uphis[i])
+		}
+	}
+	f.invalidateCFG()
+	return fmt.Sprintf("by %d", u), ""
+}
+
+// unrollCopy appends to b a copy of the loop body, in which the phis
+// of the loop header have the values vals. It returns the values that
+// the phis have in the next iteration, whose pred is the body at index
+// inside of the loop header.
+func unrollCopy(b, body *Block, phis []*Value, vals map[*Value]*Value, inside int) map[*Value]*Value {
+	copies := make(map[*Value]*Value, len(body.Values)+len(phis))
+	for p, v := range vals {
+		copies[p] = v
+	}
+	for _, v := range body.Values {
+		c := b.NewValue0(v.Pos, v.Op, v.Type)
+		c.Aux, c.AuxInt = v.Aux, v.AuxInt
+		copies[v] = c
+	}
+	for _, v := range body.Values {
+		c := copies[v]
+		for _, a := range v.Args {
+			if x := copies[a]; x != nil {
+				a = x
+			}
+			c.AddArg(a)
+		}
+	}
+	next := make(map[*Value]*Value, len(phis))
+	for _, p := range phis {
+		a := p.Args[inside]
+		if x := copies[a]; x != nil {
+			a = x
+		}
+		next[p] = a
+	}
+	return next
+}
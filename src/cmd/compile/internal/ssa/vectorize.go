@@ -0,0 +1,401 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/types"
+	"fmt"
+	"internal/buildcfg"
+	"math/bits"
+)
+
+// vectorize rewrites simple counted loops over slices, such as
+//
+//	for i := range dst {
+//		dst[i] = a[i] + b[i]
+//	}
+//
+// to do most of their iterations 16 bytes at a time, with the 128-bit
+// vector registers of amd64 and arm64. The loop body must be a single
+// block that stores one integer element at index i, computed from
+// elements at index i with +, -, &, | and ^, and the bounds checks of
+// the body must have been removed by prove.
+//
+// A vector loop is put in front of the original loop, which is kept to
+// do the iterations that remain, if fewer than fit in a vector:
+//
+//	pre:
+//		if max >= W-1, and dst and the sources do not overlap with a shift
+//			→ vheader, else → header
+//	vheader:
+//		vi = Phi(min, vi+W), vmem = Phi(mem, vstore)
+//		if vi < max-(W-1) → vbody, else → header
+//	vbody:
+//		vstore = VecStore(&dst[vi], VecAdd(VecLoad(&a[vi]), VecLoad(&b[vi])), vmem)
+//		→ vheader
+//	header:
+//		i = Phi(min, vi, i+1), ...  (the original loop)
+//
+// The pass is enabled by GOEXPERIMENT=vectorize or by -d=vectorize,
+// which also reports the loops that are vectorized, and why the other
+// counted loops are not.
+func vectorize(f *Func) {
+	if !buildcfg.Experiment.Vectorize && base.Debug.Vectorize == 0 {
+		return
+	}
+	if f.Config.arch != "amd64" && f.Config.arch != "arm64" {
+		return
+	}
+	for _, iv := range findIndVar(f) {
+		pos := iv.ind.Block.Controls[0].Pos
+		what, why := vectorizeLoop(f, iv)
+		if base.Debug.Vectorize == 0 {
+			continue
+		}
+		if why != "" {
+			f.Warnl(pos, "loop not vectorized: %s", why)
+		} else {
+			f.Warnl(pos, "loop vectorized: %s", what)
+		}
+	}
+}
+
+// vecOps maps the generic integer operations that the vectorize pass
+// handles to their vector forms, by element size.
+var vecOps = map[Op][4]Op{
+	OpAdd8:  {OpVecAdd8},
+	OpAdd16: {1: OpVecAdd16},
+	OpAdd32: {2: OpVecAdd32},
+	OpAdd64: {3: OpVecAdd64},
+	OpSub8:  {OpVecSub8},
+	OpSub16: {1: OpVecSub16},
+	OpSub32: {2: OpVecSub32},
+	OpSub64: {3: OpVecSub64},
+	OpAnd8:  {OpVecAnd},
+	OpAnd16: {1: OpVecAnd},
+	OpAnd32: {2: OpVecAnd},
+	OpAnd64: {3: OpVecAnd},
+	OpOr8:   {OpVecOr},
+	OpOr16:  {1: OpVecOr},
+	OpOr32:  {2: OpVecOr},
+	OpOr64:  {3: OpVecOr},
+	OpXor8:  {OpVecXor},
+	OpXor16: {1: OpVecXor},
+	OpXor32: {2: OpVecXor},
+	OpXor64: {3: OpVecXor},
+}
+
+// vecOp returns the vector form of op on elements of size bytes, or
+// OpInvalid if there is none.
+func vecOp(op Op, size int64) Op {
+	ops, ok := vecOps[op]
+	if !ok {
+		return OpInvalid
+	}
+	switch size {
+	case 1:
+		return ops[0]
+	case 2:
+		return ops[1]
+	case 4:
+		return ops[2]
+	case 8:
+		return ops[3]
+	}
+	return OpInvalid
+}
+
+// vectorizeLoop vectorizes the loop of iv, if it can. It returns a
+// description of the vectorized loop, or why it could not be
+// vectorized.
+func vectorizeLoop(f *Func, iv indVar) (what, why string) {
+	header := iv.ind.Block
+	body := iv.entry
+	if iv.flags != 0 {
+		return "", "bounds are not min <= i < max"
+	}
+	if _, inc, _ := parseIndVar(iv.ind); inc.AuxInt != 1 {
+		return "", "index is not incremented by 1"
+	}
+	if body.Kind != BlockPlain || body.Succs[0].b != header || len(body.Preds) != 1 {
+		return "", "loop body is not a single block"
+	}
+	outside := 0 // index of the pred of header outside the loop
+	if header.Preds[0].b == body {
+		outside = 1
+	}
+	if header.Preds[1-outside].b != body {
+		return "", "loop body is not a single block"
+	}
+	pre := header.Preds[outside].b
+	inLoop := func(v *Value) bool { return v.Block == header || v.Block == body }
+
+	var mem *Value
+	for _, v := range header.Values {
+		if v.Op != OpPhi || v == iv.ind {
+			continue
+		}
+		if !v.Type.IsMemory() {
+			return "", "loop carries a value other than the index"
+		}
+		mem = v
+	}
+	if mem == nil {
+		return "", "loop does not store to memory"
+	}
+
+	var store *Value
+	for _, v := range body.Values {
+		switch {
+		case v.Op == OpStore:
+			if store != nil {
+				return "", "loop body stores more than once"
+			}
+			store = v
+		case v.Op == OpLoad, v.Op == OpAddPtr, v.Op == OpLsh64x64, v.Op == OpMul64, vecOps[v.Op] != [4]Op{}:
+		case v.Op == OpAdd64 && v == iv.ind.Args[1-outside]:
+		case vecHoistable(v, inLoop):
+		default:
+			return "", "loop body has " + v.Op.String()
+		}
+	}
+	if store == nil || mem.Args[1-outside] != store || store.Args[2] != mem {
+		return "", "loop body does not store exactly once"
+	}
+	t := store.Aux.(*types.Type)
+	if !t.IsInteger() {
+		return "", "stored element is not an integer"
+	}
+	size := t.Size()
+	lanes := 16 / size
+
+	// Check the addresses, which opt has rewritten from &x[i] to
+	// x+i*size, and collect the base pointers that the vector loop
+	// needs before the loop.
+	var hoist []*Value
+	baseOf := func(ptr *Value) *Value {
+		if ptr.Op != OpAddPtr || !isScaledIndex(ptr.Args[1], iv.ind, size) || ptr.Type.Elem().Size() != size {
+			return nil
+		}
+		b := ptr.Args[0]
+		if inLoop(b) {
+			if !vecHoistable(b, inLoop) {
+				return nil
+			}
+			hoist = append(hoist, b)
+		}
+		return b
+	}
+	dst := baseOf(store.Args[0])
+	if dst == nil {
+		return "", "store is not to element i"
+	}
+
+	// Check the stored value, and collect the distinct sources.
+	var srcs []*Value
+	var check func(v *Value) string
+	check = func(v *Value) string {
+		if v.Op == OpLoad {
+			if v.Args[1] != mem || !v.Type.IsInteger() || v.Type.Size() != size {
+				return "operand is not an element of the stored size"
+			}
+			b := baseOf(v.Args[0])
+			if b == nil {
+				return "operand is not element i"
+			}
+			for _, s := range srcs {
+				if s == b {
+					return ""
+				}
+			}
+			srcs = append(srcs, b)
+			return ""
+		}
+		if v.Block != body || vecOp(v.Op, size) == OpInvalid {
+			return "operand is " + v.Op.String() + ", not an element"
+		}
+		for _, a := range v.Args {
+			if why := check(a); why != "" {
+				return why
+			}
+		}
+		return ""
+	}
+	if why := check(store.Args[1]); why != "" {
+		return "", why
+	}
+
+	max := iv.max
+	if inLoop(max) {
+		if !vecHoistable(max, inLoop) {
+			return "", "loop bound is computed in the loop"
+		}
+		hoist = append(hoist, max)
+	}
+	if max.Op == OpConst64 && max.AuxInt < lanes {
+		return "", "loop is too short"
+	}
+
+	// Rewrite. Move the loop-invariant values that the vector loop
+	// needs before the loop.
+	for _, v := range hoist {
+		vecHoist(v, pre, inLoop)
+	}
+	pos := store.Pos
+	typs := &f.Config.Types
+	lim := pre.NewValue2(pos, OpSub64, typs.Int64, max, f.ConstInt64(typs.Int64, lanes-1))
+
+	// The vector loop is entered only if max >= W-1, so that lim
+	// does not overflow.
+	var safe *Value
+	if max.Op != OpConst64 {
+		safe = pre.NewValue2(pos, OpLeq64, typs.Bool, f.ConstInt64(typs.Int64, lanes-1), max)
+	}
+
+	// If dst is a source shifted by less than a vector, an element
+	// stored by an iteration is loaded by one of the next few, so the
+	// vector loop would load it too soon. That is, d = dst-src is
+	// unsafe if 0 < d < 16, or uint64(d-1) < 15.
+	for _, s := range srcs {
+		if s == dst {
+			continue
+		}
+		d := pre.NewValue2(pos, OpSubPtr, typs.Uintptr, dst, s)
+		d1 := pre.NewValue2(pos, OpSub64, typs.UInt64, d, f.ConstInt64(typs.UInt64, 1))
+		ok := pre.NewValue2(pos, OpLeq64U, typs.Bool, f.ConstInt64(typs.UInt64, 15), d1)
+		if safe == nil {
+			safe = ok
+		} else {
+			safe = pre.NewValue2(pos, OpAndB, typs.Bool, safe, ok)
+		}
+	}
+
+	vheader := f.NewBlock(BlockIf)
+	vbody := f.NewBlock(BlockPlain)
+	vheader.Pos, vbody.Pos = header.Pos, body.Pos
+	vheader.Likely = BranchLikely
+
+	// Enter the vector loop instead of the original loop, if it is
+	// safe to, and make the original loop continue after the vector
+	// loop.
+	enter := pre
+	k := header.Preds[outside].i
+	if safe != nil {
+		enter = f.NewBlock(BlockIf)
+		enter.Pos = header.Pos
+		enter.SetControl(safe)
+		enter.Likely = BranchLikely
+		pre.Succs[k] = Edge{enter, 0}
+		enter.Preds = append(enter.Preds, Edge{pre, k})
+		enter.AddEdgeTo(vheader)
+		enter.Succs = append(enter.Succs, Edge{header, outside})
+		header.Preds[outside] = Edge{enter, 1}
+	} else {
+		pre.Succs[k] = Edge{vheader, 0}
+		vheader.Preds = append(vheader.Preds, Edge{pre, k})
+	}
+	vheader.AddEdgeTo(vbody)
+	vbody.AddEdgeTo(vheader)
+
+	vi := vheader.NewValue0(iv.ind.Pos, OpPhi, iv.ind.Type)
+	vmem := vheader.NewValue0(mem.Pos, OpPhi, types.TypeMem)
+	vheader.SetControl(vheader.NewValue2(pos, OpLess64, typs.Bool, vi, lim))
+
+	idx := vi
+	if size > 1 {
+		idx = vbody.NewValue2I(pos, OpLsh64x64, iv.ind.Type, 0, vi, f.ConstInt64(typs.UInt64, int64(bits.TrailingZeros64(uint64(size)))))
+	}
+	vals := make(map[*Value]*Value)
+	var vec func(v *Value) *Value
+	vec = func(v *Value) *Value {
+		if x := vals[v]; x != nil {
+			return x
+		}
+		var x *Value
+		if v.Op == OpLoad {
+			p := vbody.NewValue2(v.Args[0].Pos, OpAddPtr, v.Args[0].Type, v.Args[0].Args[0], idx)
+			x = vbody.NewValue2(v.Pos, OpVecLoad, types.TypeInt128, p, vmem)
+		} else {
+			x = vbody.NewValue2(v.Pos, vecOp(v.Op, size), types.TypeInt128, vec(v.Args[0]), vec(v.Args[1]))
+		}
+		vals[v] = x
+		return x
+	}
+	p := vbody.NewValue2(store.Args[0].Pos, OpAddPtr, store.Args[0].Type, dst, idx)
+	vstore := vbody.NewValue3(pos, OpVecStore, types.TypeMem, p, vec(store.Args[1]), vmem)
+	vnxt := vbody.NewValue2(pos, OpAdd64, iv.ind.Type, vi, f.ConstInt64(iv.ind.Type, lanes))
+	vi.AddArg2(iv.ind.Args[outside], vnxt)
+	vmem.AddArg2(mem.Args[outside], vstore)
+
+	if safe != nil {
+		header.Preds = append(header.Preds, Edge{vheader, 1})
+		vheader.Succs = append(vheader.Succs, Edge{header, len(header.Preds) - 1})
+		iv.ind.AddArg(vi)
+		mem.AddArg(vmem)
+	} else {
+		header.Preds[outside] = Edge{vheader, 1}
+		vheader.Succs = append(vheader.Succs, Edge{header, outside})
+		iv.ind.SetArg(outside, vi)
+		mem.SetArg(outside, vmem)
+	}
+	f.invalidateCFG()
+	return fmt.Sprintf("%d x %v", lanes, t), ""
+}
+
+// isScaledIndex reports whether x is i*size.
+func isScaledIndex(x, i *Value, size int64) bool {
+	switch x.Op {
+	case OpLsh64x64:
+		return x.Args[0] == i && x.Args[1].Op == OpConst64 && 1<<x.Args[1].AuxInt == size
+	case OpMul64:
+		for j := 0; j < 2; j++ {
+			if x.Args[j] == i && x.Args[1-j].Op == OpConst64 && x.Args[1-j].AuxInt == size {
+				return true
+			}
+		}
+		return false
+	}
+	return size == 1 && x == i
+}
+
+// vecHoistable reports whether v, a value in a loop, can be computed
+// before the loop instead: it is the address or length of a slice or
+// string, or an offset pointer, of values computed before the loop.
+func vecHoistable(v *Value, inLoop func(*Value) bool) bool {
+	switch v.Op {
+	case OpSlicePtr, OpSliceLen, OpSliceCap, OpStringPtr, OpStringLen, OpOffPtr, OpCopy:
+	default:
+		return false
+	}
+	for _, a := range v.Args {
+		if inLoop(a) && !vecHoistable(a, inLoop) {
+			return false
+		}
+	}
+	return true
+}
+
+// vecHoist moves v, and the values of the loop that it uses, to the
+// end of pre.
+func vecHoist(v *Value, pre *Block, inLoop func(*Value) bool) {
+	if !inLoop(v) {
+		return
+	}
+	for _, a := range v.Args {
+		vecHoist(a, pre, inLoop)
+	}
+	b := v.Block
+	for i, x := range b.Values {
+		if x == v {
+			copy(b.Values[i:], b.Values[i+1:])
+			b.Values[len(b.Values)-1] = nil
+			b.Values = b.Values[:len(b.Values)-1]
+			break
+		}
+	}
+	v.Block = pre
+	pre.Values = append(pre.Values, v)
+}
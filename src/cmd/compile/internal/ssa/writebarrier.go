@@ -5,6 +5,7 @@
 package ssa
 
 import (
+	"cmd/compile/internal/base"
 	"cmd/compile/internal/reflectdata"
 	"cmd/compile/internal/types"
 	"cmd/internal/obj"
@@ -76,6 +77,41 @@ func needwb(v *Value, zeroes map[ID]ZeroRegion) bool {
 	return true
 }
 
+// reportWB reports that the store op v needs a write barrier, for
+// -d=wbreport.
+func reportWB(v *Value) {
+	kind := "store"
+	switch v.Op {
+	case OpMove:
+		kind = "move"
+	case OpZero:
+		kind = "zero"
+	}
+	dst := v.Args[0]
+	for dst.Op == OpOffPtr || dst.Op == OpAddPtr || dst.Op == OpPtrIndex || dst.Op == OpCopy {
+		dst = dst.Args[0]
+	}
+	reason := "destination is " + pointerOrigin(dst)
+	if _, ok := IsNewObject(dst); ok {
+		switch v.Op {
+		case OpStore:
+			reason = "destination is a new object, but the stored value may be a heap pointer"
+		case OpMove:
+			reason = "destination is a new object, but the source is not read-only"
+		default:
+			reason = "destination is a new object"
+		}
+	}
+	v.Block.Func.reportCheck(base.Debug.WBReport, checkReport{
+		pos:    v.Pos,
+		what:   "write barrier for " + kind,
+		Report: "wb",
+		Kind:   kind,
+		Kept:   true,
+		Reason: reason,
+	})
+}
+
 // writebarrier pass inserts write barriers for store ops (Store, Move, Zero)
 // when necessary (the condition above). It rewrites store ops to branches
 // and runtime calls, like
@@ -108,6 +144,9 @@ func writebarrier(f *Func) {
 			switch v.Op {
 			case OpStore, OpMove, OpZero:
 				if needwb(v, zeroes) {
+					if base.Debug.WBReport != 0 {
+						reportWB(v)
+					}
 					switch v.Op {
 					case OpStore:
 						v.Op = OpStoreWB
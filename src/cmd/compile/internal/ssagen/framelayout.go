@@ -0,0 +1,109 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssagen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/ssa"
+	"cmd/internal/obj"
+)
+
+// A frameSlot is a range of a stack frame reported by -d=framelayout.
+type frameSlot struct {
+	off, size int64
+	what      string
+}
+
+// reportFrameLayout prints, for -d=framelayout, the stack frame of the
+// function f, whose size as computed by genssa was frame, and whose
+// assembled TEXT instruction is text, as in
+//
+//	x.go:5:6: frame of f: 40 bytes (32 allocated by the compiler), morestack check, open-coded defers
+//		sp+0	16	outgoing arguments
+//		sp+16	8	local x int
+//		sp+24	1	local .autotmp_3 uint8
+//		sp+25	7	padding
+//
+// with offsets from the stack pointer after the function's prologue.
+// Any bytes the assembler adds, for a saved frame pointer and
+// alignment, are not broken down.
+func reportFrameLayout(f *ssa.Func, frame int64, text *obj.Prog) {
+	e := f.Frontend().(*ssafn)
+	fn := e.curfn
+	fixed := base.Ctxt.FixedFrameSize()
+
+	// Locals that register allocation assigned values to are
+	// spill slots.
+	spills := make(map[*ir.Name]bool)
+	for _, l := range f.RegAlloc {
+		if ls, ok := l.(ssa.LocalSlot); ok {
+			spills[ls.N] = true
+		}
+	}
+
+	var slots []frameSlot
+	if fixed > 0 && frame > 0 {
+		slots = append(slots, frameSlot{0, fixed, "saved link register"})
+	}
+	if e.maxarg > 0 {
+		slots = append(slots, frameSlot{fixed, e.maxarg, "outgoing arguments"})
+	}
+	for _, n := range fn.Dcl {
+		if n.Op() != ir.ONAME || n.Class != ir.PAUTO && !(n.Class == ir.PPARAMOUT && n.IsOutputParamInRegisters()) {
+			continue
+		}
+		what := "local"
+		if spills[n] {
+			what = "spill slot"
+		}
+		slots = append(slots, frameSlot{frame + fixed + n.FrameOffset(), n.Type().Size(), fmt.Sprintf("%s %v %v", what, n.Sym().Name, n.Type())})
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].off < slots[j].off })
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v: frame of %s: %d bytes (%d allocated by the compiler)", base.FmtPos(fn.Pos()), f.Name, text.To.Offset, frame)
+	if callsMorestack(text) {
+		buf.WriteString(", morestack check")
+	} else {
+		buf.WriteString(", no morestack check")
+	}
+	if fn.LSym.Func().OpenCodedDeferInfo != nil {
+		buf.WriteString(", open-coded defers")
+	}
+	buf.WriteString("\n")
+	off := int64(0)
+	for _, s := range slots {
+		if s.off > off {
+			fmt.Fprintf(&buf, "\tsp+%d\t%d\tpadding\n", off, s.off-off)
+		}
+		fmt.Fprintf(&buf, "\tsp+%d\t%d\t%s\n", s.off, s.size, s.what)
+		if end := s.off + s.size; end > off {
+			off = end
+		}
+	}
+	if end := frame + fixed; frame > 0 && end > off {
+		fmt.Fprintf(&buf, "\tsp+%d\t%d\tpadding\n", off, end-off)
+	}
+	os.Stdout.Write(buf.Bytes())
+}
+
+// callsMorestack reports whether the assembled function starting at
+// text checks for stack overflow, which the assembler decides by
+// architecture, frame size and whether the function is a leaf.
+func callsMorestack(text *obj.Prog) bool {
+	for p := text; p != nil; p = p.Link {
+		if p.To.Sym != nil && strings.HasPrefix(p.To.Sym.Name, "runtime.morestack") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssagen
+
+import (
+	"math"
+	"math/bits"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/ssa"
+	"cmd/compile/internal/types"
+)
+
+type pureKey struct {
+	pkg string
+	fn  string
+}
+
+// A pureIntrinsic evaluates a call of an intrinsic that is a pure
+// function of its arguments. It is passed the bits of the arguments,
+// integers sign- or zero-extended to 64 bits and float64s as by
+// math.Float64bits, and returns the bits of the result.
+type pureIntrinsic func(x []uint64) uint64
+
+// pureIntrinsics are the intrinsics that intrinsicCall evaluates at
+// compile time when all their arguments are constants, folding the
+// call to a constant instead of building its code. They are evaluated
+// with the math and math/bits packages of the compiler, whose results
+// are the same on all architectures, except for the sizes of int and
+// uint, for which the functions of uint arguments look at the target's.
+var pureIntrinsics = map[pureKey]pureIntrinsic{
+	{"math", "Sqrt"}:        float1(math.Sqrt),
+	{"math", "Floor"}:       float1(math.Floor),
+	{"math", "Ceil"}:        float1(math.Ceil),
+	{"math", "Trunc"}:       float1(math.Trunc),
+	{"math", "Round"}:       float1(math.Round),
+	{"math", "RoundToEven"}: float1(math.RoundToEven),
+	{"math", "Abs"}:         float1(math.Abs),
+	{"math", "Copysign"}: func(x []uint64) uint64 {
+		return math.Float64bits(math.Copysign(math.Float64frombits(x[0]), math.Float64frombits(x[1])))
+	},
+	{"math", "FMA"}: func(x []uint64) uint64 {
+		return math.Float64bits(math.FMA(math.Float64frombits(x[0]), math.Float64frombits(x[1]), math.Float64frombits(x[2])))
+	},
+
+	{"math/bits", "TrailingZeros8"}:  func(x []uint64) uint64 { return uint64(bits.TrailingZeros8(uint8(x[0]))) },
+	{"math/bits", "TrailingZeros16"}: func(x []uint64) uint64 { return uint64(bits.TrailingZeros16(uint16(x[0]))) },
+	{"math/bits", "TrailingZeros32"}: func(x []uint64) uint64 { return uint64(bits.TrailingZeros32(uint32(x[0]))) },
+	{"math/bits", "TrailingZeros64"}: func(x []uint64) uint64 { return uint64(bits.TrailingZeros64(x[0])) },
+	{"math/bits", "Len"}: func(x []uint64) uint64 {
+		if types.PtrSize == 4 {
+			return uint64(bits.Len32(uint32(x[0])))
+		}
+		return uint64(bits.Len64(x[0]))
+	},
+	{"math/bits", "Len8"}:  func(x []uint64) uint64 { return uint64(bits.Len8(uint8(x[0]))) },
+	{"math/bits", "Len16"}: func(x []uint64) uint64 { return uint64(bits.Len16(uint16(x[0]))) },
+	{"math/bits", "Len32"}: func(x []uint64) uint64 { return uint64(bits.Len32(uint32(x[0]))) },
+	{"math/bits", "Len64"}: func(x []uint64) uint64 { return uint64(bits.Len64(x[0])) },
+	{"math/bits", "OnesCount"}: func(x []uint64) uint64 {
+		if types.PtrSize == 4 {
+			return uint64(bits.OnesCount32(uint32(x[0])))
+		}
+		return uint64(bits.OnesCount64(x[0]))
+	},
+	{"math/bits", "OnesCount8"}:  func(x []uint64) uint64 { return uint64(bits.OnesCount8(uint8(x[0]))) },
+	{"math/bits", "OnesCount16"}: func(x []uint64) uint64 { return uint64(bits.OnesCount16(uint16(x[0]))) },
+	{"math/bits", "OnesCount32"}: func(x []uint64) uint64 { return uint64(bits.OnesCount32(uint32(x[0]))) },
+	{"math/bits", "OnesCount64"}: func(x []uint64) uint64 { return uint64(bits.OnesCount64(x[0])) },
+	{"math/bits", "Reverse"}: func(x []uint64) uint64 {
+		if types.PtrSize == 4 {
+			return uint64(bits.Reverse32(uint32(x[0])))
+		}
+		return bits.Reverse64(x[0])
+	},
+	{"math/bits", "Reverse8"}:       func(x []uint64) uint64 { return uint64(bits.Reverse8(uint8(x[0]))) },
+	{"math/bits", "Reverse16"}:      func(x []uint64) uint64 { return uint64(bits.Reverse16(uint16(x[0]))) },
+	{"math/bits", "Reverse32"}:      func(x []uint64) uint64 { return uint64(bits.Reverse32(uint32(x[0]))) },
+	{"math/bits", "Reverse64"}:      func(x []uint64) uint64 { return bits.Reverse64(x[0]) },
+	{"math/bits", "ReverseBytes32"}: func(x []uint64) uint64 { return uint64(bits.ReverseBytes32(uint32(x[0]))) },
+	{"math/bits", "ReverseBytes64"}: func(x []uint64) uint64 { return bits.ReverseBytes64(x[0]) },
+	{"math/bits", "RotateLeft"}: func(x []uint64) uint64 {
+		if types.PtrSize == 4 {
+			return uint64(bits.RotateLeft32(uint32(x[0]), int(int64(x[1]))))
+		}
+		return bits.RotateLeft64(x[0], int(int64(x[1])))
+	},
+	{"math/bits", "RotateLeft8"}:  func(x []uint64) uint64 { return uint64(bits.RotateLeft8(uint8(x[0]), int(int64(x[1])))) },
+	{"math/bits", "RotateLeft16"}: func(x []uint64) uint64 { return uint64(bits.RotateLeft16(uint16(x[0]), int(int64(x[1])))) },
+	{"math/bits", "RotateLeft32"}: func(x []uint64) uint64 { return uint64(bits.RotateLeft32(uint32(x[0]), int(int64(x[1])))) },
+	{"math/bits", "RotateLeft64"}: func(x []uint64) uint64 { return bits.RotateLeft64(x[0], int(int64(x[1]))) },
+}
+
+// float1 returns the pureIntrinsic of the float64 function f.
+func float1(f func(float64) float64) pureIntrinsic {
+	return func(x []uint64) uint64 {
+		return math.Float64bits(f(math.Float64frombits(x[0])))
+	}
+}
+
+// evalPureIntrinsic returns the constant result of the call n of an
+// intrinsic, whose arguments are args, if it is one of pureIntrinsics
+// and args are all constants, or else nil.
+func (s *state) evalPureIntrinsic(n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+	sym := n.X.Sym()
+	eval := pureIntrinsics[pureKey{intrinsicPkgPath(sym), sym.Name}]
+	if eval == nil {
+		return nil
+	}
+	x := make([]uint64, len(args))
+	for i, a := range args {
+		switch a.Op {
+		case ssa.OpConst8, ssa.OpConst16, ssa.OpConst32, ssa.OpConst64:
+			x[i] = uint64(a.AuxInt)
+		case ssa.OpConst64F:
+			x[i] = math.Float64bits(a.AuxFloat())
+		default:
+			return nil
+		}
+	}
+	r := eval(x)
+	t := n.Type()
+	switch {
+	case t.IsFloat():
+		f := math.Float64frombits(r)
+		if math.IsNaN(f) {
+			return nil // constants cannot be NaNs
+		}
+		return s.constFloat64(t, f)
+	case t.Size() == 1:
+		return s.constInt8(t, int8(r))
+	case t.Size() == 2:
+		return s.constInt16(t, int16(r))
+	case t.Size() == 4:
+		return s.constInt32(t, int32(r))
+	}
+	return s.constInt64(t, int64(r))
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssagen
+
+import (
+	"fmt"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/ssa"
+	"cmd/compile/internal/types"
+)
+
+// findTailCalls finds the statements of the body of s.curfn that make
+// direct self tail calls, for -d=tailcall. After walk, they are
+//
+//	tmp = f(args); return ~r0 = tmp // return f(args)
+//	f(args); return                 // or f(args) at the end of the body
+//
+// buildssa turns each of them into assignments of the arguments to the
+// parameters and a jump back to the start of the body, so that deep
+// recursions run in constant stack space. The frames of the calls
+// are then missing from tracebacks, which is why this is done only
+// with -d=tailcall.
+//
+// With -d=tailcall=2, findTailCalls reports the self tail calls, and
+// why those that are not turned into jumps are not. It returns whether
+// any are.
+func (s *state) findTailCalls() bool {
+	var stmts []ir.Node
+	var calls []*ir.CallExpr
+	s.findTailCallsIn(s.curfn.Body, true, func(n ir.Node, call *ir.CallExpr) {
+		stmts = append(stmts, n)
+		calls = append(calls, call)
+	})
+	if len(calls) == 0 {
+		return false
+	}
+	blocker := s.tailCallBlocker()
+	for i, call := range calls {
+		why := blocker
+		if why == "" && len(call.KeepAlive) > 0 {
+			why = "arguments are kept alive after the call"
+		}
+		if base.Debug.TailCall > 1 {
+			if why != "" {
+				base.WarnfAt(call.Pos(), "self tail call not turned into a loop: %s", why)
+			} else {
+				base.WarnfAt(call.Pos(), "self tail call turned into a loop")
+			}
+		}
+		if why != "" {
+			continue
+		}
+		if s.tailCalls == nil {
+			s.tailCalls = make(map[ir.Node]*ir.CallExpr)
+		}
+		s.tailCalls[stmts[i]] = call
+	}
+	return len(s.tailCalls) > 0
+}
+
+// findTailCallsIn calls found for each statement of list, and of the
+// statement lists nested in it, that makes a self tail call. atEnd
+// reports whether the function returns after list.
+func (s *state) findTailCallsIn(list ir.Nodes, atEnd bool, found func(n ir.Node, call *ir.CallExpr)) {
+	for i, n := range list {
+		// The statements after n, without the VARKILLs of
+		// temporaries, which are no-ops at a return.
+		rest := list[i+1:]
+		for len(rest) > 0 && rest[0].Op() == ir.OVARKILL {
+			rest = rest[1:]
+		}
+		last := atEnd && len(rest) == 0
+		var ret *ir.ReturnStmt
+		if len(rest) > 0 && rest[0].Op() == ir.ORETURN && len(rest[0].Init()) == 0 {
+			ret = rest[0].(*ir.ReturnStmt)
+		}
+
+		switch n.Op() {
+		case ir.OBLOCK:
+			n := n.(*ir.BlockStmt)
+			s.findTailCallsIn(n.List, last, found)
+		case ir.OIF:
+			n := n.(*ir.IfStmt)
+			s.findTailCallsIn(n.Body, last, found)
+			s.findTailCallsIn(n.Else, last, found)
+		case ir.OFOR, ir.OFORUNTIL:
+			n := n.(*ir.ForStmt)
+			s.findTailCallsIn(n.Body, false, found)
+		case ir.OSWITCH:
+			n := n.(*ir.SwitchStmt)
+			s.findTailCallsIn(n.Compiled, last, found)
+		case ir.OSELECT:
+			n := n.(*ir.SelectStmt)
+			s.findTailCallsIn(n.Compiled, last, found)
+
+		case ir.OCALLFUNC:
+			// f(args); return
+			call := s.selfCall(n)
+			if call != nil && s.curfn.Type().NumResults() == 0 && (last || ret != nil && len(ret.Results) == 0) {
+				found(n, call)
+			}
+		case ir.OAS:
+			// tmp = f(args); return ~r0 = tmp
+			n := n.(*ir.AssignStmt)
+			call := s.selfCall(n.Y)
+			if call != nil && ret != nil && len(ret.Results) == 1 && ret.Results[0].Op() == ir.OAS {
+				if r := ret.Results[0].(*ir.AssignStmt); r.Y == n.X && len(r.Init()) == 0 {
+					found(n, call)
+				}
+			}
+		case ir.ORETURN:
+			// return ~r0 = f(args)
+			n := n.(*ir.ReturnStmt)
+			if len(n.Results) == 1 && n.Results[0].Op() == ir.OAS {
+				if call := s.selfCall(n.Results[0].(*ir.AssignStmt).Y); call != nil {
+					found(n, call)
+				}
+			}
+		}
+	}
+}
+
+// selfCall returns n if it is a direct call of s.curfn, or else nil.
+func (s *state) selfCall(n ir.Node) *ir.CallExpr {
+	if n == nil || n.Op() != ir.OCALLFUNC {
+		return nil
+	}
+	call := n.(*ir.CallExpr)
+	if fn, ok := call.X.(*ir.Name); ok && fn.Class == ir.PFUNC && fn.Func == s.curfn {
+		return call
+	}
+	return nil
+}
+
+// tailCallBlocker returns why the self tail calls of s.curfn cannot be
+// turned into jumps, or "" if they can. A jump reuses the frame of the
+// function, so its parameters and results must be SSA values or stack
+// slots, it must not run defers or recover at its return, and the
+// autos whose addresses the arguments may hold must not be reused.
+func (s *state) tailCallBlocker() string {
+	fn := s.curfn
+	if s.hasdefer {
+		return "function has defers"
+	}
+	if ir.AnyList(fn.Body, func(n ir.Node) bool {
+		switch n.Op() {
+		case ir.ORECOVER, ir.ORECOVERFP, ir.OGETCALLERPC, ir.OGETCALLERSP:
+			return true
+		}
+		return false
+	}) {
+		return "function uses its caller's frame"
+	}
+	for _, n := range fn.Dcl {
+		switch n.Class {
+		case ir.PPARAM:
+			if !s.canSSA(n) {
+				return fmt.Sprintf("parameter %v is kept in memory", n)
+			}
+		case ir.PPARAMOUT:
+			if !n.OnStack() {
+				return fmt.Sprintf("result %v is moved to the heap", n)
+			}
+		case ir.PAUTO:
+			if n.Addrtaken() && n.OnStack() {
+				if ir.IsAutoTmp(n) {
+					return "address of a temporary is taken"
+				}
+				return fmt.Sprintf("address of %v is taken", n)
+			}
+		}
+	}
+	return ""
+}
+
+// tailCall assigns the arguments of the self tail call n to the
+// parameters of s.curfn, zeroes its results, and jumps back to the
+// start of its body.
+func (s *state) tailCall(n *ir.CallExpr) {
+	args := make([]*ssa.Value, len(n.Args))
+	for i, a := range n.Args {
+		args[i] = s.expr(a)
+	}
+	i := 0
+	for _, params := range types.RecvsParams {
+		for _, f := range params(s.curfn.Type()).FieldSlice() {
+			if p, ok := f.Nname.(*ir.Name); ok && !ir.IsBlank(p) {
+				s.assign(p, args[i], false, 0)
+			}
+			i++
+		}
+	}
+	s.zeroResults()
+	b := s.endBlock()
+	b.Pos = s.lastPos.WithIsStmt()
+	b.AddEdgeTo(s.tailLoop)
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/objw"
 	"cmd/compile/internal/types"
@@ -31,11 +32,11 @@ func embedFileList(v *ir.Name, kind int) []string {
 		for _, pattern := range e.Patterns {
 			files, ok := base.Flag.Cfg.Embed.Patterns[pattern]
 			if !ok {
-				base.ErrorfAt(e.Pos, "invalid go:embed: build system did not map pattern: %s", pattern)
+				base.ErrorfAt(e.Pos, errcode.InvalidDirective, "invalid go:embed: build system did not map pattern: %s", pattern)
 			}
 			for _, file := range files {
 				if base.Flag.Cfg.Embed.Files[file] == "" {
-					base.ErrorfAt(e.Pos, "invalid go:embed: build system did not map file: %s", file)
+					base.ErrorfAt(e.Pos, errcode.InvalidDirective, "invalid go:embed: build system did not map file: %s", file)
 					continue
 				}
 				if !have[file] {
@@ -57,7 +58,7 @@ func embedFileList(v *ir.Name, kind int) []string {
 
 	if kind == embedString || kind == embedBytes {
 		if len(list) > 1 {
-			base.ErrorfAt(v.Pos(), "invalid go:embed: multiple files for type %v", v.Type())
+			base.ErrorfAt(v.Pos(), errcode.InvalidDirective, "invalid go:embed: multiple files for type %v", v.Type())
 			return nil
 		}
 	}
@@ -109,12 +110,12 @@ func WriteEmbed(v *ir.Name) {
 
 	commentPos := (*v.Embed)[0].Pos
 	if base.Flag.Cfg.Embed.Patterns == nil {
-		base.ErrorfAt(commentPos, "invalid go:embed: build system did not supply embed configuration")
+		base.ErrorfAt(commentPos, errcode.InvalidDirective, "invalid go:embed: build system did not supply embed configuration")
 		return
 	}
 	kind := embedKind(v.Type())
 	if kind == embedUnknown {
-		base.ErrorfAt(v.Pos(), "go:embed cannot apply to var of type %v", v.Type())
+		base.ErrorfAt(v.Pos(), errcode.InvalidDirective, "go:embed cannot apply to var of type %v", v.Type())
 		return
 	}
 
@@ -124,7 +125,7 @@ func WriteEmbed(v *ir.Name) {
 		file := files[0]
 		fsym, size, err := fileStringSym(v.Pos(), base.Flag.Cfg.Embed.Files[file], kind == embedString, nil)
 		if err != nil {
-			base.ErrorfAt(v.Pos(), "embed %s: %v", file, err)
+			base.ErrorfAt(v.Pos(), 0, "embed %s: %v", file, err)
 		}
 		sym := v.Linksym()
 		off := 0
@@ -160,7 +161,7 @@ func WriteEmbed(v *ir.Name) {
 			} else {
 				fsym, size, err := fileStringSym(v.Pos(), base.Flag.Cfg.Embed.Files[file], true, hash)
 				if err != nil {
-					base.ErrorfAt(v.Pos(), "embed %s: %v", file, err)
+					base.ErrorfAt(v.Pos(), 0, "embed %s: %v", file, err)
 				}
 				off = objw.SymPtr(slicedata, off, fsym, 0) // data string
 				off = objw.Uintptr(slicedata, off, uint64(size))
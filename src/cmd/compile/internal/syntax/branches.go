@@ -4,7 +4,11 @@
 
 package syntax
 
-import "fmt"
+import (
+	"fmt"
+
+	"cmd/compile/internal/errcode"
+)
 
 // TODO(gri) consider making this part of the parser code
 
@@ -31,9 +35,9 @@ func checkBranches(body *BlockStmt, errh ErrorHandler) {
 		name := fwd.Label.Value
 		if l := ls.labels[name]; l != nil {
 			l.used = true // avoid "defined and not used" error
-			ls.err(fwd.Label.Pos(), "goto %s jumps into block starting at %s", name, l.parent.start)
+			ls.err(fwd.Label.Pos(), errcode.MisplacedBranch, "goto %s jumps into block starting at %s", name, l.parent.start)
 		} else {
-			ls.err(fwd.Label.Pos(), "label %s not defined", name)
+			ls.err(fwd.Label.Pos(), errcode.UndeclaredLabel, "label %s not defined", name)
 		}
 	}
 
@@ -41,7 +45,7 @@ func checkBranches(body *BlockStmt, errh ErrorHandler) {
 	for _, l := range ls.labels {
 		if !l.used {
 			l := l.lstmt.Label
-			ls.err(l.Pos(), "label %s defined and not used", l.Value)
+			ls.err(l.Pos(), errcode.UnusedLabel, "label %s defined and not used", l.Value)
 		}
 	}
 }
@@ -63,8 +67,8 @@ type block struct {
 	lstmt  *LabeledStmt // labeled statement associated with this block, or nil
 }
 
-func (ls *labelScope) err(pos Pos, format string, args ...interface{}) {
-	ls.errh(Error{pos, fmt.Sprintf(format, args...)})
+func (ls *labelScope) err(pos Pos, code errcode.Code, format string, args ...interface{}) {
+	ls.errh(Error{pos, fmt.Sprintf(format, args...), code})
 }
 
 // declare declares the label introduced by s in block b and returns
@@ -77,7 +81,7 @@ func (ls *labelScope) declare(b *block, s *LabeledStmt) *label {
 		labels = make(map[string]*label)
 		ls.labels = labels
 	} else if alt := labels[name]; alt != nil {
-		ls.err(s.Label.Pos(), "label %s already defined at %s", name, alt.lstmt.Label.Pos().String())
+		ls.err(s.Label.Pos(), errcode.DuplicateLabel, "label %s already defined at %s", name, alt.lstmt.Label.Pos().String())
 		return alt
 	}
 	l := &label{b, s, false}
@@ -188,6 +192,7 @@ func (ls *labelScope) blockBranches(parent *block, ctxt targets, lstmt *LabeledS
 						if jumpsOverVarDecl(fwd) {
 							ls.err(
 								fwd.Label.Pos(),
+								errcode.MisplacedBranch,
 								"goto %s jumps over declaration of %s at %s",
 								name, String(varName), varPos,
 							)
@@ -213,13 +218,13 @@ func (ls *labelScope) blockBranches(parent *block, ctxt targets, lstmt *LabeledS
 					if t := ctxt.breaks; t != nil {
 						s.Target = t
 					} else {
-						ls.err(s.Pos(), "break is not in a loop, switch, or select")
+						ls.err(s.Pos(), errcode.MisplacedBranch, "break is not in a loop, switch, or select")
 					}
 				case _Continue:
 					if t := ctxt.continues; t != nil {
 						s.Target = t
 					} else {
-						ls.err(s.Pos(), "continue is not in a loop")
+						ls.err(s.Pos(), errcode.MisplacedBranch, "continue is not in a loop")
 					}
 				case _Fallthrough:
 					// nothing to do
@@ -243,10 +248,10 @@ func (ls *labelScope) blockBranches(parent *block, ctxt targets, lstmt *LabeledS
 					case *SwitchStmt, *SelectStmt, *ForStmt:
 						s.Target = t
 					default:
-						ls.err(s.Label.Pos(), "invalid break label %s", name)
+						ls.err(s.Label.Pos(), errcode.MisplacedBranch, "invalid break label %s", name)
 					}
 				} else {
-					ls.err(s.Label.Pos(), "break label not defined: %s", name)
+					ls.err(s.Label.Pos(), errcode.UndeclaredLabel, "break label not defined: %s", name)
 				}
 
 			case _Continue:
@@ -256,10 +261,10 @@ func (ls *labelScope) blockBranches(parent *block, ctxt targets, lstmt *LabeledS
 					if t, ok := t.Stmt.(*ForStmt); ok {
 						s.Target = t
 					} else {
-						ls.err(s.Label.Pos(), "invalid continue label %s", name)
+						ls.err(s.Label.Pos(), errcode.MisplacedBranch, "invalid continue label %s", name)
 					}
 				} else {
-					ls.err(s.Label.Pos(), "continue label not defined: %s", name)
+					ls.err(s.Label.Pos(), errcode.UndeclaredLabel, "continue label not defined: %s", name)
 				}
 
 			case _Goto:
@@ -395,18 +395,20 @@ type (
 	}
 
 	IfStmt struct {
-		Init SimpleStmt
-		Cond Expr
-		Then *BlockStmt
-		Else Stmt // either nil, *IfStmt, or *BlockStmt
+		Pragma Pragma
+		Init   SimpleStmt
+		Cond   Expr
+		Then   *BlockStmt
+		Else   Stmt // either nil, *IfStmt, or *BlockStmt
 		stmt
 	}
 
 	ForStmt struct {
-		Init SimpleStmt // incl. *RangeClause
-		Cond Expr
-		Post SimpleStmt
-		Body *BlockStmt
+		Pragma Pragma
+		Init   SimpleStmt // incl. *RangeClause
+		Cond   Expr
+		Post   SimpleStmt
+		Body   *BlockStmt
 		stmt
 	}
 
@@ -9,6 +9,8 @@ import (
 	"io"
 	"strconv"
 	"strings"
+
+	"cmd/compile/internal/errcode"
 )
 
 const debug = false
@@ -45,7 +47,7 @@ func (p *parser) init(file *PosBase, r io.Reader, errh ErrorHandler, pragh Pragm
 		// base to compute the corresponding Pos value.
 		func(line, col uint, msg string) {
 			if msg[0] != '/' {
-				p.errorAt(p.posAt(line, col), msg)
+				p.errorAt(p.posAt(line, col), errcode.InvalidToken, msg)
 				return
 			}
 
@@ -120,7 +122,7 @@ func (p *parser) updateBase(pos Pos, tline, tcol uint, text string) {
 
 	if !ok {
 		// text has a suffix :xxx but xxx is not a number
-		p.errorAt(p.posAt(tline, tcol+i), "invalid line number: "+text[i:])
+		p.errorAt(p.posAt(tline, tcol+i), 0, "invalid line number: "+text[i:])
 		return
 	}
 
@@ -131,7 +133,7 @@ func (p *parser) updateBase(pos Pos, tline, tcol uint, text string) {
 		i, i2 = i2, i
 		line, col = n2, n
 		if col == 0 || col > PosMax {
-			p.errorAt(p.posAt(tline, tcol+i2), "invalid column number: "+text[i2:])
+			p.errorAt(p.posAt(tline, tcol+i2), 0, "invalid column number: "+text[i2:])
 			return
 		}
 		text = text[:i2-1] // lop off ":col"
@@ -141,7 +143,7 @@ func (p *parser) updateBase(pos Pos, tline, tcol uint, text string) {
 	}
 
 	if line == 0 || line > PosMax {
-		p.errorAt(p.posAt(tline, tcol+i), "invalid line number: "+text[i:])
+		p.errorAt(p.posAt(tline, tcol+i), 0, "invalid line number: "+text[i:])
 		return
 	}
 
@@ -220,8 +222,8 @@ func (p *parser) posAt(line, col uint) Pos {
 }
 
 // error reports an error at the given position.
-func (p *parser) errorAt(pos Pos, msg string) {
-	err := Error{pos, msg}
+func (p *parser) errorAt(pos Pos, code errcode.Code, msg string) {
+	err := Error{pos, msg, code}
 	if p.first == nil {
 		p.first = err
 	}
@@ -252,7 +254,7 @@ func (p *parser) syntaxErrorAt(pos Pos, msg string) {
 		msg = ", " + msg
 	default:
 		// plain error - we don't care about current token
-		p.errorAt(pos, "syntax error: "+msg)
+		p.errorAt(pos, errcode.SyntaxError, "syntax error: "+msg)
 		return
 	}
 
@@ -274,7 +276,7 @@ func (p *parser) syntaxErrorAt(pos Pos, msg string) {
 		tok = tokstring(p.tok)
 	}
 
-	p.errorAt(pos, "syntax error: unexpected "+tok+msg)
+	p.errorAt(pos, errcode.SyntaxError, "syntax error: unexpected "+tok+msg)
 }
 
 // tokstring returns the English word for selected punctuation tokens
@@ -292,9 +294,9 @@ func tokstring(tok token) string {
 }
 
 // Convenience methods using the current token position.
-func (p *parser) pos() Pos               { return p.posAt(p.line, p.col) }
-func (p *parser) error(msg string)       { p.errorAt(p.pos(), msg) }
-func (p *parser) syntaxError(msg string) { p.syntaxErrorAt(p.pos(), msg) }
+func (p *parser) pos() Pos                            { return p.posAt(p.line, p.col) }
+func (p *parser) error(code errcode.Code, msg string) { p.errorAt(p.pos(), code, msg) }
+func (p *parser) syntaxError(msg string)              { p.syntaxErrorAt(p.pos(), msg) }
 
 // The stopset contains keywords that start a statement.
 // They are good synchronization points in case of syntax
@@ -692,9 +694,9 @@ func (p *parser) funcDeclOrNil() *FuncDecl {
 		rcvr := p.paramList(nil, _Rparen, false)
 		switch len(rcvr) {
 		case 0:
-			p.error("method has no receiver")
+			p.error(errcode.BadRecv, "method has no receiver")
 		default:
-			p.error("method has multiple receivers")
+			p.error(errcode.BadRecv, "method has multiple receivers")
 			fallthrough
 		case 1:
 			f.Recv = rcvr[0]
@@ -866,16 +868,21 @@ func (p *parser) callStmt() *CallStmt {
 	s.Tok = p.tok // _Defer or _Go
 	p.next()
 
+	code := errcode.InvalidDefer
+	if s.Tok == _Go {
+		code = errcode.InvalidGo
+	}
+
 	x := p.pexpr(nil, p.tok == _Lparen) // keep_parens so we can report error below
 	if t := unparen(x); t != x {
-		p.errorAt(x.Pos(), fmt.Sprintf("expression in %s must not be parenthesized", s.Tok))
+		p.errorAt(x.Pos(), code, fmt.Sprintf("expression in %s must not be parenthesized", s.Tok))
 		// already progressed, no need to advance
 		x = t
 	}
 
 	cx, ok := x.(*CallExpr)
 	if !ok {
-		p.errorAt(x.Pos(), fmt.Sprintf("expression in %s must be function call", s.Tok))
+		p.errorAt(x.Pos(), code, fmt.Sprintf("expression in %s must be function call", s.Tok))
 		// already progressed, no need to advance
 		cx = new(CallExpr)
 		cx.pos = x.Pos()
@@ -1093,7 +1100,7 @@ loop:
 				t.Full = true
 				// x[i:j:...]
 				if t.Index[1] == nil {
-					p.error("middle index required in 3-index slice")
+					p.error(errcode.InvalidSliceExpr, "middle index required in 3-index slice")
 					t.Index[1] = p.badExpr()
 				}
 				p.next()
@@ -1101,7 +1108,7 @@ loop:
 					// x[i:j:k...
 					t.Index[2] = p.expr()
 				} else {
-					p.error("final index required in 3-index slice")
+					p.error(errcode.InvalidSliceExpr, "final index required in 3-index slice")
 					t.Index[2] = p.badExpr()
 				}
 			}
@@ -1730,11 +1737,11 @@ func (p *parser) methodDecl() *Field {
 				p.next()
 				if p.tok == _Lparen {
 					// name[](
-					p.errorAt(pos, "empty type parameter list")
+					p.errorAt(pos, 0, "empty type parameter list")
 					f.Name = name
 					_, f.Type = p.funcType(context)
 				} else {
-					p.errorAt(pos, "empty type argument list")
+					p.errorAt(pos, 0, "empty type argument list")
 					f.Type = name
 				}
 				break
@@ -1764,7 +1771,7 @@ func (p *parser) methodDecl() *Field {
 				// TODO(gri) Record list as type parameter list with f.Type
 				//           if we want to type-check the generic method.
 				//           For now, report an error so this is not a silent event.
-				p.errorAt(pos, "interface method cannot have type parameters")
+				p.errorAt(pos, 0, "interface method cannot have type parameters")
 				break
 			}
 
@@ -2225,7 +2232,7 @@ func (p *parser) declStmt(f func(*Group) Decl) *DeclStmt {
 	return s
 }
 
-func (p *parser) forStmt() Stmt {
+func (p *parser) forStmt() *ForStmt {
 	if trace {
 		defer p.trace("forStmt")()
 	}
@@ -2513,6 +2520,21 @@ func (p *parser) stmtOrNil() Stmt {
 
 	case _Type:
 		return p.declStmt(p.typeDecl)
+
+	case _If:
+		// An if statement accepts a pragma, for its branch
+		// likelihood.
+		pragma := p.takePragma()
+		s := p.ifStmt()
+		s.Pragma = pragma
+		return s
+
+	case _For:
+		// A for statement accepts a pragma, for unrolling.
+		pragma := p.takePragma()
+		s := p.forStmt()
+		s.Pragma = pragma
+		return s
 	}
 
 	p.clearPragma()
@@ -2532,18 +2554,12 @@ func (p *parser) stmtOrNil() Stmt {
 		_Arrow: // receive operator
 		return p.simpleStmt(nil, 0)
 
-	case _For:
-		return p.forStmt()
-
 	case _Switch:
 		return p.switchStmt()
 
 	case _Select:
 		return p.selectStmt()
 
-	case _If:
-		return p.ifStmt()
-
 	case _Fallthrough:
 		s := new(BranchStmt)
 		s.pos = p.pos()
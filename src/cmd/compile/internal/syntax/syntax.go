@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+
+	"cmd/compile/internal/errcode"
 )
 
 // Mode describes the parser mode.
@@ -21,8 +23,9 @@ const (
 
 // Error describes a syntax error. Error implements the error interface.
 type Error struct {
-	Pos Pos
-	Msg string
+	Pos  Pos
+	Msg  string
+	Code errcode.Code // class of the error, or 0
 }
 
 func (err Error) Error() string {
@@ -52,7 +52,7 @@ func ErrorMap(src io.Reader) (errmap map[uint][]Error) {
 		}
 		if s := errRx.FindStringSubmatch(text[2:]); len(s) == 2 {
 			pos := MakePos(base, prev.line, prev.col)
-			err := Error{pos, strings.TrimSpace(s[1])}
+			err := Error{Pos: pos, Msg: strings.TrimSpace(s[1])}
 			if errmap == nil {
 				errmap = make(map[uint][]Error)
 			}
@@ -0,0 +1,97 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const diagStreamSrc = `package p
+
+func f() {
+	var x int = "s"
+	_ = x
+}
+
+func g() {
+	undefined()
+}
+`
+
+func TestDiagStream(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestDiagStream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(diagStreamSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	compile := func(stream string) []byte {
+		cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-diagstream="+stream, "-o", filepath.Join(dir, "x.o"), src)
+		out, err := cmd.Output()
+		if err == nil {
+			t.Fatalf("compilation succeeded unexpectedly:\n%s", out)
+		}
+		return out
+	}
+
+	// messages returns the messages of the JSON objects on the lines of
+	// out, sorted, as the stream has them in the order they were
+	// reported.
+	messages := func(out []byte) []string {
+		var msgs []string
+		for _, line := range bytes.Split(bytes.TrimSuffix(out, []byte("\n")), []byte("\n")) {
+			var d struct {
+				Message string
+			}
+			if err := json.Unmarshal(line, &d); err != nil {
+				t.Fatalf("line %q: %v", line, err)
+			}
+			msgs = append(msgs, d.Message)
+		}
+		sort.Strings(msgs)
+		return msgs
+	}
+	want := []string{
+		`cannot use "s" (untyped string constant) as int value in variable declaration`,
+		"undefined: undefined",
+	}
+
+	// To a file, alongside the usual output.
+	stream := filepath.Join(dir, "stream")
+	out := compile(stream)
+	b, err := ioutil.ReadFile(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messages(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed messages %q, want %q", got, want)
+	}
+	if bytes.Count(out, []byte("\n")) != len(want) || out[0] == '{' {
+		t.Errorf("output with -diagstream=file:\n%s\nwant the usual errors", out)
+	}
+
+	// To standard output, in place of the usual output.
+	if got := messages(compile("-")); !reflect.DeepEqual(got, want) {
+		t.Errorf("messages on standard output %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const dictDumpSrc = `package p
+
+type I interface{ M() }
+
+type T int
+
+func (T) M() {}
+
+func G[X I](x X) I { return x }
+
+func H[X I, Y any](x X, y Y) (I, *Y) {
+	G(x)
+	return x, &y
+}
+
+var _, _ = H[T, string](1, "")
+`
+
+func TestDictionaryDump(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestDictionaryDump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(dictDumpSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"G", `x.go:9:6: dictionary .dict.G["".T] of G[T]
+	0: type argument X = T
+	1: itab T, I
+`},
+		{"p.H", `x.go:11:6: dictionary .dict.H["".T,string] of H[T, string]
+	0: type argument X = T
+	1: type argument Y = string
+	2: derived type *shape:string = *string
+	3: derived type func(shape:int) I = func(T) I
+	4: sub-dictionary .dict.G["".T] for function call at x.go:12:3
+	5: itab T, I
+`},
+		{"q.G", ""},
+	}
+	for _, test := range tests {
+		cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-d=dictionary="+test.query, "-o", filepath.Join(dir, "x.o"), src)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("compile -d=dictionary=%s: %v\n%s", test.query, err, out)
+		}
+		if got := strings.Replace(string(out), dir+string(filepath.Separator), "", -1); got != test.want {
+			t.Errorf("compile -d=dictionary=%s: got output:\n%s\nwant:\n%s", test.query, got, test.want)
+		}
+	}
+}
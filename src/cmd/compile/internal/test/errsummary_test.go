@@ -0,0 +1,78 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var errSummarySrcs = map[string]string{
+	"a.go": `package foo
+
+var x int = "a"
+var y string = 1
+`,
+	"b.go": `package foo
+
+func f() { g() }
+`,
+}
+
+func TestErrorSummary(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestErrorSummary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var srcs []string
+	for _, name := range []string{"a.go", "b.go"} {
+		src := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(src, []byte(errSummarySrcs[name]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, src)
+	}
+
+	compile := func(flags ...string) string {
+		args := append([]string{"tool", "compile", "-p", "pkg/foo", "-o", filepath.Join(dir, "x.o")}, flags...)
+		cmd := exec.Command(testenv.GoToolPath(t), append(args, srcs...)...)
+		out, err := cmd.CombinedOutput()
+		if err == nil {
+			t.Fatalf("compile %v succeeded unexpectedly:\n%s", flags, out)
+		}
+		return strings.Replace(string(out), dir+string(filepath.Separator), "", -1)
+	}
+
+	const (
+		errA1 = "a.go:3:13: cannot use \"a\" (untyped string constant) as int value in variable declaration\n"
+		errA2 = "a.go:4:16: cannot use 1 (untyped int constant) as string value in variable declaration\n"
+		errB  = "b.go:3:12: undefined: g\n"
+	)
+	tests := []struct {
+		flags []string
+		want  string
+	}{
+		{nil, errA1 + errA2 + errB},
+		{[]string{"-errsummary"}, errA1 + errA2 + errB + "3 errors in pkg/foo: 2 in a.go, 1 in b.go\n"},
+		{[]string{"-quiet"}, errA1 + errB + "3 errors in pkg/foo: 2 in a.go, 1 in b.go\n"},
+		{[]string{"-quiet", "-maxerrors=1"}, errA1 + "a.go:3:13: too many errors\n" + "1 error in pkg/foo: 1 in a.go\n"},
+	}
+	for _, test := range tests {
+		if out := compile(test.flags...); out != test.want {
+			t.Errorf("compile %v: got output:\n%s\nwant:\n%s", test.flags, out, test.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const escReportSrc = `package p
+
+var sink interface{}
+
+func f() {
+	s := make([]byte, 8)
+	sink = s
+}
+
+func g() *int {
+	x := 1
+	p := &x
+	return p
+}
+`
+
+func TestEscapeReport(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestEscapeReport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(escReportSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-d=escreport=2", "-o", "x.o", "x.go")
+	cmd.Dir = dir // for the relative positions
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	type via struct{ Pos, Op string }
+	type flow struct {
+		Dst, Src string
+		Derefs   int
+		Via      []via
+	}
+	type report struct {
+		Pos, Func, Value, Kind string
+		Flows                  []flow
+	}
+	var got []report
+	for _, line := range bytes.Split(bytes.TrimSuffix(out, []byte("\n")), []byte("\n")) {
+		var r report
+		if err := json.Unmarshal(line, &r); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		got = append(got, r)
+	}
+	want := []report{
+		{"x.go:6:11", "f", "make([]byte, 8)", "allocation", []flow{
+			{"s", "{storage for make([]byte, 8)}", -1, []via{{"x.go:6:11", "spill"}, {"x.go:6:4", "assign"}}},
+			{"{storage for s}", "s", 0, []via{{"x.go:7:2", "interface-converted"}}},
+			{"{heap}", "{storage for s}", -1, []via{{"x.go:7:2", "spill"}, {"x.go:7:7", "assign"}}},
+		}},
+		{"x.go:7:2", "f", "s", "allocation", []flow{
+			{"{heap}", "{storage for s}", -1, []via{{"x.go:7:2", "spill"}, {"x.go:7:7", "assign"}}},
+		}},
+		{"x.go:11:2", "g", "x", "variable", []flow{
+			{"p", "x", -1, []via{{"x.go:12:7", "address-of"}, {"x.go:12:4", "assign"}}},
+			{"~r0", "p", 0, []via{{"x.go:13:2", "return"}}},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got escape reports\n%+v\nwant\n%+v\noutput:\n%s", got, want, out)
+	}
+}
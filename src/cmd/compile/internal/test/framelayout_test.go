@@ -0,0 +1,62 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+const frameLayoutSrc = `package p
+
+var sink *[4]int
+
+func f(x int) (r int) {
+	defer func() { r++ }()
+	var a [4]int
+	a[x&3] = x
+	sink = &a
+	return a[0]
+}
+
+func g(x int) int { return x + 1 }
+`
+
+func TestFrameLayout(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestFrameLayout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "x.go"), []byte(frameLayoutSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-d=framelayout", "-o", "x.o", "x.go")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	for _, want := range []string{
+		`(?m)^x\.go:5:6: frame of f: \d+ bytes \(\d+ allocated by the compiler\), morestack check, open-coded defers$`,
+		`(?m)^\tsp\+\d+\t8\tlocal r int$`,
+		`(?m)^x\.go:13:6: frame of g: 0 bytes \(0 allocated by the compiler\), no morestack check$`,
+	} {
+		if !regexp.MustCompile(want).Match(out) {
+			t.Errorf("-d=framelayout output does not match %s:\n%s", want, out)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -114,3 +115,46 @@ func main() {
 		}
 	}
 }
+
+// Make sure -S=3 interleaves source lines, encodings and relocations
+// with the assembly code.
+func TestDashS3(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestDashS3")
+	if err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	err = ioutil.WriteFile(src, []byte(`package p
+
+var sink int
+
+func f(x int) {
+	sink = g(x)
+}
+
+//go:noinline
+func g(x int) int { return x * 3 }
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-S=3", "-o", "x.o", "x.go")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("could not compile: %v\n%s", err, out)
+	}
+
+	// The call of g is under its source line, with its
+	// encoding, and followed by its relocation.
+	want := regexp.MustCompile(`(?m)^\tx\.go:6\tsink = g\(x\)\n(\t0x[0-9a-f]+.*\n)*\t0x[0-9a-f]+( [0-9a-f]{2})+ *\t[A-Z]+\t"".g\(SB\)\n\t\trel \d+\+\d+ t=\d+ "".g<1>\+0\n`)
+	if !want.Match(out) {
+		t.Errorf("-S=3 output does not match %v:\n%s", want, out)
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"bufio"
 	"internal/testenv"
 	"io"
+	"io/ioutil"
 	"math/bits"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
@@ -273,3 +276,158 @@ func TestIntendedInlining(t *testing.T) {
 		t.Errorf("%s was not inlined: %s", fullName, reason)
 	}
 }
+
+const genericInlineA = `package a
+
+type Number interface{ ~int | ~int64 | ~float64 }
+
+func Max[T Number](x, y T) T {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func Sum[T Number](s []T) T {
+	var t T
+	for _, v := range s {
+		t += v
+	}
+	return t
+}
+
+func Id[T any](x T) T { return x }
+
+func Wrap[T any](x T) T { return Id(x) }
+
+func Conv[T any](x T) interface{} { return x }
+
+type Box[T any] struct{ v T }
+
+func (b *Box[T]) Get() T { return b.v }
+
+func (b *Box[T]) Set(v T) { b.v = v }
+`
+
+const genericInlineB = `package b
+
+import "a"
+
+func F(s []int, b *a.Box[float64]) {
+	_ = a.Max(1, 2)
+	_ = a.Sum(s)
+	_ = a.Wrap("x")
+	_ = a.Conv(1)
+	b.Set(b.Get() + 1)
+	f := a.Id[int]
+	_ = f(3)
+}
+`
+
+// TestGenericInlining tests that small instantiations of generic
+// functions and methods imported from another package are inlined like
+// ordinary exported functions. The importing package instantiates them
+// from the generic bodies in the export data, dictionaries included.
+func TestGenericInlining(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestGenericInlining")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	compile := func(pkg, src string, flags ...string) []byte {
+		file := filepath.Join(dir, pkg+".go")
+		if err := ioutil.WriteFile(file, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		args := append([]string{"tool", "compile", "-p", pkg, "-I", dir, "-o", filepath.Join(dir, pkg+".o")}, flags...)
+		cmd := exec.Command(testenv.GoToolPath(t), append(args, file)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("compile %s: %v\n%s", pkg, err, out)
+		}
+		return out
+	}
+	compile("a", genericInlineA)
+	out := compile("b", genericInlineB, "-m")
+
+	inlined := make(map[string]bool)
+	haveInlined := regexp.MustCompile(`: inlining call to (\S+)`)
+	for _, m := range haveInlined.FindAllStringSubmatch(string(out), -1) {
+		inlined[m[1]] = true
+	}
+	// Calls passing a dictionary are named by its type arguments. The
+	// call of Id in the inlined body of Wrap passes Wrap's dictionary's
+	// sub-dictionary, so it is named by its shape.
+	for _, fn := range []string{
+		"a.Max[int]",
+		"a.Sum[int]",
+		"a.Wrap[string]",
+		"a.Id[shape:string]",
+		"a.Conv[int]",
+		"a.(*Box[float64]).Get",
+		"a.(*Box[float64]).Set",
+		"a.Id[int]",
+	} {
+		if !inlined[fn] {
+			t.Errorf("%s was not inlined", fn)
+		}
+	}
+	if t.Failed() {
+		t.Logf("compile -m output:\n%s", out)
+	}
+}
+
+const inlineCostSrc = `package p
+
+func small(x int) int { return x*x + 1 }
+
+//go:noinline
+func g() {}
+
+func big(x int) int {
+	g()
+	return small(x) + small(x+1) + small(x+2)
+}
+`
+
+// TestInlineCostBreakdown tests that -m=3 explains the cost of
+// functions that are over the inlining budget.
+func TestInlineCostBreakdown(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestInlineCostBreakdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "p.go")
+	if err := ioutil.WriteFile(src, []byte(inlineCostSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-m=3", "-o", filepath.Join(dir, "p.o"), src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	for _, want := range []string{
+		`p.go:8:6: cannot inline big: function too complex: cost 93 exceeds budget 80`,
+		`p.go:8:6: cost of big is 93, 13 over budget 80`,
+		`p.go:9:3:   cost 58: g\(\) \(calls that are not inlined cost 57 more\)`,
+		`p.go:10:14:   cost 7: small\(x\) \(inlined body of small costs 6\)`,
+		`p.go:8:6:   cost by op: CALLFUNC 79 \(4 nodes\), NAME 7 \(7 nodes\), ADD 4 \(4 nodes\)`,
+	} {
+		if !regexp.MustCompile(want).Match(out) {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if t.Failed() {
+		t.Logf("compile -m=3 output:\n%s", out)
+	}
+}
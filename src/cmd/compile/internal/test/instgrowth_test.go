@@ -0,0 +1,104 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const instGrowthSrc = `package p
+
+type Box[T any] struct{ v T }
+
+func (b *Box[T]) Get() T { return b.v }
+
+func Map[T, U any](xs []T, f func(T) U) []U {
+	var r []U
+	for _, x := range xs {
+		r = append(r, f(x))
+	}
+	return r
+}
+
+func Use() {
+	_ = Map([]int{1}, func(i int) string { return "" })
+	_ = Map([]string{""}, func(s string) int { return 0 })
+	b := &Box[int]{}
+	_ = b.Get()
+}
+`
+
+// TestInstGrowth tests that -d=instgrowth attributes the code,
+// dictionaries and type descriptors of instantiations to the generic
+// functions and types they came from, largest first.
+func TestInstGrowth(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestInstGrowth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(instGrowthSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-d=instgrowth", "-o", filepath.Join(dir, "x.o"), src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 3 || strings.Join(strings.Fields(lines[0]), " ") != "generic insts code dicts types total" {
+		t.Fatalf("unexpected -d=instgrowth output:\n%s", out)
+	}
+	type row struct {
+		insts, code, dicts, types, total int
+	}
+	rows := make(map[string]row)
+	var order []string
+	for _, line := range lines[1:] {
+		f := strings.Fields(line)
+		if len(f) != 6 {
+			t.Fatalf("malformed row %q", line)
+		}
+		var n [5]int
+		for i := range n {
+			if n[i], err = strconv.Atoi(f[i+1]); err != nil {
+				t.Fatalf("malformed row %q", line)
+			}
+		}
+		if n[1]+n[2]+n[3] != n[4] {
+			t.Errorf("row %q: total is not the sum of code, dicts and types", line)
+		}
+		rows[f[0]] = row{n[0], n[1], n[2], n[3], n[4]}
+		order = append(order, f[0])
+	}
+
+	// Map is instantiated with two shapes, and Box once as a type
+	// and once for its method.
+	m, b := rows["Map"], rows["Box"]
+	if m.insts != 2 || m.code == 0 || m.dicts == 0 {
+		t.Errorf("Map: got %+v, want 2 instantiations with code and dictionaries", m)
+	}
+	if b.insts != 2 || b.code == 0 || b.types == 0 {
+		t.Errorf("Box: got %+v, want 2 instantiations with code and type descriptors", b)
+	}
+	if rows[order[0]].total < rows[order[1]].total {
+		t.Errorf("rows not sorted by size:\n%s", out)
+	}
+}
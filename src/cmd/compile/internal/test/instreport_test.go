@@ -0,0 +1,90 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const instReportSrc = `package p
+
+type L[E any] struct{ e E }
+
+func (l *L[E]) Get() E { return l.e }
+
+func G[X any](x X) *X { return &x }
+
+func H[X any](x X) *X { return G(x) }
+
+var _ = H(1)
+var _ = G(2)
+var _ = (&L[string]{}).Get()
+
+type MyInt int
+
+var _ = G(MyInt(3))
+`
+
+func TestInstantiationReport(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestInstantiationReport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(instReportSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-d=instantiations=2", "-o", "x.o", "x.go")
+	cmd.Dir = dir // for the relative positions of the sites
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	type inst struct {
+		Site, Kind, Instance, Shape string
+		Shared                      bool
+		TypeID                      string
+	}
+	var got []inst
+	for _, line := range bytes.Split(bytes.TrimSuffix(out, []byte("\n")), []byte("\n")) {
+		var r struct {
+			inst
+			Code, Data int64
+		}
+		if err := json.Unmarshal(line, &r); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		if r.Data <= 0 || (r.Shape != "" && !r.Shared && r.Code <= 0) {
+			t.Errorf("line %q: want positive sizes", line)
+		}
+		got = append(got, r.inst)
+	}
+	want := []inst{
+		{"", "type", "L[string]", "", false, "p.L[string]"},
+		{"", "method", "L[string].Get", "(*L[go.shape.string_0]).Get", false, ""},
+		{"x.go:11:10", "func", "H[int]", "H[go.shape.int_0]", false, ""},
+		{"x.go:9:33", "func", "G[int]", "G[go.shape.int_0]", false, ""},
+		{"x.go:17:10", "func", "G[MyInt]", "G[go.shape.int_0]", true, ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got instantiations\n%+v\nwant\n%+v", got, want)
+	}
+}
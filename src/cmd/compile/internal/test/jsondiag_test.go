@@ -0,0 +1,298 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const jsonDiagSrc = `package p
+
+func f() {
+	var x int = "s"
+	_ = x
+}
+
+func g() {}
+func g() {}
+
+type T struct{}
+
+func h(t T) error {
+	return t
+}
+`
+
+func TestJSONDiag(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestJSONDiag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(jsonDiagSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-jsondiag", "-o", filepath.Join(dir, "x.o"), src)
+	out, err := cmd.Output()
+	if err == nil {
+		t.Fatalf("compilation succeeded unexpectedly:\n%s", out)
+	}
+
+	type pos struct {
+		File      string
+		Line, Col int
+	}
+	type related struct {
+		Pos     *pos
+		Message string
+	}
+	type diag struct {
+		Pos      *pos
+		End      *pos
+		Severity string
+		Code     string
+		Message  string
+		Related  []related
+	}
+	var got []diag
+	for _, line := range bytes.Split(bytes.TrimSuffix(out, []byte("\n")), []byte("\n")) {
+		var d diag
+		if err := json.Unmarshal(line, &d); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		got = append(got, d)
+	}
+
+	want := []diag{
+		{Pos: &pos{src, 4, 14}, End: &pos{src, 4, 17}, Severity: "error", Code: "E0011", Message: `cannot use "s" (untyped string constant) as int value in variable declaration`},
+		{Pos: &pos{src, 9, 6}, End: &pos{src, 9, 7}, Severity: "error", Code: "E0009", Message: "g redeclared in this block",
+			Related: []related{{&pos{src, 8, 6}, "other declaration of g"}}},
+		{Pos: &pos{src, 14, 9}, End: &pos{src, 14, 10}, Severity: "error", Code: "E0012", Message: "incompatible type: cannot use t (variable of type T) as error value:\n\tT does not implement error (missing Error method)",
+			Related: []related{{&pos{src, 11, 6}, "declaration of T"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got diagnostics:\n%s\nwant %+v", out, want)
+	}
+}
+
+const jsonDiagFixesSrc = `package p
+
+import (
+	"fmt"
+	"strings"
+)
+
+type T struct{}
+
+func (*T) M() {}
+
+type I interface{ M() }
+
+func f(xs []int, v interface{}, n int, t T) {
+	a := len(xs)
+	for i, x := range xs {
+		_ = i
+	}
+	switch y := v.(type) {
+	}
+	var f float64 = n
+	var i I = t
+	_, _ = f, i
+	_ = strings.ToUppr
+	_ = lenn(xs)
+}
+`
+
+const jsonDiagFixesWant = `package p
+
+import (
+	
+	"strings"
+)
+
+type T struct{}
+
+func (*T) M() {}
+
+type I interface{ M() }
+
+func f(xs []int, v interface{}, n int, t T) {
+	_ = len(xs)
+	for i := range xs {
+		_ = i
+	}
+	switch v.(type) {
+	}
+	var f float64 = float64(n)
+	var i I = &t
+	_, _ = f, i
+	_ = strings.ToUpper
+	_ = len(xs)
+}
+`
+
+func TestJSONDiagFixes(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestJSONDiagFixes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(jsonDiagFixesSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-jsondiag", "-o", filepath.Join(dir, "x.o"), src)
+	out, err := cmd.Output()
+	if err == nil {
+		t.Fatalf("compilation succeeded unexpectedly:\n%s", out)
+	}
+
+	type pos struct {
+		File      string
+		Line, Col int
+	}
+	type edit struct {
+		Pos, End pos
+		NewText  string `json:"new_text"`
+	}
+	type diag struct {
+		Message string
+		Fixes   []struct {
+			Message string
+			Edits   []edit
+		}
+	}
+
+	// Apply the edits of all fixes, from the last one in the file to
+	// the first, so that earlier offsets stay valid.
+	lines := strings.SplitAfter(jsonDiagFixesSrc, "\n")
+	offset := func(p pos) int {
+		if p.File != src {
+			t.Fatalf("edit in file %s, want %s", p.File, src)
+		}
+		n := 0
+		for _, line := range lines[:p.Line-1] {
+			n += len(line)
+		}
+		return n + p.Col - 1
+	}
+	var edits []edit
+	for _, line := range bytes.Split(bytes.TrimSuffix(out, []byte("\n")), []byte("\n")) {
+		var d diag
+		if err := json.Unmarshal(line, &d); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		if len(d.Fixes) != 1 {
+			t.Errorf("%s: got %d fixes, want 1", d.Message, len(d.Fixes))
+			continue
+		}
+		edits = append(edits, d.Fixes[0].Edits...)
+	}
+	sort.Slice(edits, func(i, j int) bool { return offset(edits[i].Pos) > offset(edits[j].Pos) })
+	got := jsonDiagFixesSrc
+	for _, e := range edits {
+		got = got[:offset(e.Pos)] + e.NewText + got[offset(e.End):]
+	}
+	if got != jsonDiagFixesWant {
+		t.Errorf("fixed source:\n%s\nwant:\n%s\ndiagnostics:\n%s", got, jsonDiagFixesWant, out)
+	}
+}
+
+// TestJSONDiagImportedTypes checks that errors mixing up types of the
+// same name from different packages point at their declarations.
+func TestJSONDiagImportedTypes(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestJSONDiagImportedTypes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	compile := func(path, src string, args ...string) ([]byte, error) {
+		file := filepath.Join(dir, filepath.FromSlash(path)+".go")
+		if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		args = append([]string{"tool", "compile", "-p", path, "-I", dir, "-o", filepath.Join(dir, filepath.FromSlash(path)+".a")}, args...)
+		return exec.Command(testenv.GoToolPath(t), append(args, file)...).Output()
+	}
+	for _, path := range []string{"x/config", "y/config"} {
+		if out, err := compile(path, "package config\n\ntype Config struct{}\n"); err != nil {
+			t.Fatalf("compiling %s: %v\n%s", path, err, out)
+		}
+	}
+	out, err := compile("m", `package m
+
+import (
+	xc "x/config"
+	"y/config"
+)
+
+func f(a xc.Config, b config.Config) bool {
+	return a == b
+}
+`, "-jsondiag")
+	if err == nil {
+		t.Fatalf("compilation succeeded unexpectedly:\n%s", out)
+	}
+
+	var d struct {
+		Message string
+		Related []struct {
+			Pos struct {
+				File      string
+				Line, Col int
+			}
+			Message string
+		}
+	}
+	if err := json.Unmarshal(bytes.TrimSuffix(out, []byte("\n")), &d); err != nil {
+		t.Fatalf("%q: %v", out, err)
+	}
+	if want := `invalid operation: cannot compare a == b (mismatched types "x/config".Config and "y/config".Config)`; d.Message != want {
+		t.Errorf("got message %q, want %q", d.Message, want)
+	}
+	var got []string
+	for _, r := range d.Related {
+		got = append(got, fmt.Sprintf("%s:%d:%d: %s", r.Pos.File, r.Pos.Line, r.Pos.Col, r.Message))
+	}
+	want := []string{
+		filepath.Join(dir, "x", "config.go") + `:3:6: declaration of "x/config".Config`,
+		filepath.Join(dir, "y", "config.go") + `:3:6: declaration of "y/config".Config`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got related\n%s\nwant\n%s", d.Message, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
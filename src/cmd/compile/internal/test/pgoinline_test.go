@@ -0,0 +1,117 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+const pgoInlineSrc = `package p
+
+func f(x []int) int {
+	s := 0
+	for _, v := range x {
+		s += g(v)
+	}
+	return s + g(len(x))
+}
+
+func g(v int) int {
+	s := 0
+	for i := 0; i < v; i++ {
+		s += i*v ^ i>>3
+		if s > 1000 {
+			s -= v * 7
+		}
+	}
+	for i := v; i > 0; i /= 2 {
+		s += i & 5
+		if s < 0 {
+			s = -s
+		}
+	}
+	for i := 0; i < s; i += 3 {
+		v += i | s
+	}
+	return s + v
+}
+`
+
+func TestPGOInline(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestPGOInline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(pgoInlineSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(dir, "cpu.pprof")
+	// The call of g in the loop is hot, the one after it is not.
+	writePGOStacks(t, prof, map[int64][]pgoFrame{
+		200: {{"p.g", 14}, {"p.f", 6}},
+		10:  {{"p.f", 5}},
+		1:   {{"p.g", 25}, {"p.f", 8}},
+	})
+
+	compile := func(flags ...string) string {
+		args := append([]string{"tool", "compile", "-p", "p", "-pgoprofile", prof, "-m", "-o", "x.o"}, flags...)
+		cmd := exec.Command(testenv.GoToolPath(t), append(args, "x.go")...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("compile %v: %v\n%s", flags, err, out)
+		}
+		return string(out)
+	}
+	inlinedHot := regexp.MustCompile(`(?m)^x\.go:6:\d+: inlining call to g$`)
+	inlinedCold := regexp.MustCompile(`(?m)^x\.go:8:\d+: inlining call to g$`)
+
+	out := compile("-d=pgoinline")
+	want := regexp.MustCompile(`(?m)^pgo inlining: 1 hot call sites with at least 200 samples make up 99% of 201 call samples; their callees have budget 2000 \(25 times 80\)$`)
+	if !want.MatchString(out) {
+		t.Errorf("-d=pgoinline output does not match %v:\n%s", want, out)
+	}
+	if want := regexp.MustCompile(`(?m)^x\.go:6:\d+: hot call to p\.g, callee cost \d+, budget 2000$`); !want.MatchString(out) {
+		t.Errorf("-d=pgoinline output does not match %v:\n%s", want, out)
+	}
+	if !inlinedHot.MatchString(out) {
+		t.Errorf("the hot call of g is not inlined:\n%s", out)
+	}
+	if inlinedCold.MatchString(out) {
+		t.Errorf("the call of g that is not hot is inlined:\n%s", out)
+	}
+
+	// With every call hot, both calls of g are inlined.
+	out = compile("-d=pgoinline,pgoinlinecdf=100")
+	want = regexp.MustCompile(`(?m)^pgo inlining: 2 hot call sites with at least 1 samples make up 100% of 201 call samples`)
+	if !want.MatchString(out) {
+		t.Errorf("-d=pgoinlinecdf=100 output does not match %v:\n%s", want, out)
+	}
+	if !inlinedHot.MatchString(out) || !inlinedCold.MatchString(out) {
+		t.Errorf("-d=pgoinlinecdf=100 does not inline both calls of g:\n%s", out)
+	}
+
+	// Without hot calls, or without a larger budget, g is too
+	// expensive to inline.
+	for _, flag := range []string{"-d=pgoinlinecdf=0", "-d=pgoinlinebudgetmult=1"} {
+		out = compile(flag)
+		if inlinedHot.MatchString(out) || inlinedCold.MatchString(out) {
+			t.Errorf("%s inlines g:\n%s", flag, out)
+		}
+	}
+}
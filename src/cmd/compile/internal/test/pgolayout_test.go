@@ -0,0 +1,310 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/profile"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+const pgoLayoutSrc = `package p
+
+func f(x []int, k int) int {
+	s := 0
+	for _, v := range x {
+		if v == k {
+			s += g(v)
+		}
+		s += v
+	}
+	return s
+}
+
+//go:noinline
+func g(v int) int { return v * 3 }
+`
+
+// writePGOProfile writes a CPU profile with the given samples by line
+// of the function p.f.
+func writePGOProfile(t *testing.T, path string, lines map[int64]int64) {
+	fn := &profile.Function{ID: 1, Name: "p.f", SystemName: "p.f", Filename: "x.go"}
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "cpu", Unit: "nanoseconds"},
+		},
+		PeriodType: &profile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+		Period:     10000000,
+		Function:   []*profile.Function{fn},
+	}
+	for line, n := range lines {
+		loc := &profile.Location{
+			ID:      uint64(len(p.Location) + 1),
+			Address: uint64(0x1000 + line),
+			Line:    []profile.Line{{Function: fn, Line: line}},
+		}
+		p.Location = append(p.Location, loc)
+		p.Sample = append(p.Sample, &profile.Sample{
+			Location: []*profile.Location{loc},
+			Value:    []int64{n, n * p.Period},
+		})
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Write(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPGOLayout(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestPGOLayout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(pgoLayoutSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(dir, "cpu.pprof")
+	// The loop runs, but its v == k branch never does.
+	writePGOProfile(t, prof, map[int64]int64{5: 50, 6: 40, 9: 40, 11: 1})
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-pgoprofile", prof, "-d=pgolayout", "-o", "x.o", "x.go")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	// The block that calls g is cold, and laid out last.
+	want := regexp.MustCompile(`(?m)^x\.go:\d+:\d+: profile-guided layout of f: b1 line 3: \d+, .* line 11: 1, b\d+ line 7: cold$`)
+	if !want.Match(out) {
+		t.Errorf("-d=pgolayout output does not match %v:\n%s", want, out)
+	}
+	if regexp.MustCompile(`layout of g`).Match(out) {
+		t.Errorf("-d=pgolayout reported g, which has no samples:\n%s", out)
+	}
+}
+
+const pgoLayoutPredsSrc = `package p
+
+func f(v int) int {
+	s := 0
+	for i := 0; i < v; i++ {
+		s += i*v ^ i>>3
+		if s > 1000 {
+			s -= v * 7
+		}
+	}
+	for i := v; i > 0; i /= 2 {
+		s += i & 5
+		if s < 0 {
+			s = -s
+		}
+	}
+	for i := 0; i < s; i += 3 {
+		v += i | s
+	}
+	return s + v
+}
+`
+
+// TestPGOLayoutPreds tests that profile-guided layout places each
+// block after one of its predecessors, as register allocation, which
+// visits blocks in layout order, requires, even where the profile has
+// samples in blocks that only cold blocks lead to.
+func TestPGOLayoutPreds(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestPGOLayoutPreds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(pgoLayoutPredsSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(dir, "cpu.pprof")
+	// The second loop has no samples, but the third does.
+	writePGOProfile(t, prof, map[int64]int64{6: 200, 17: 1})
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-pgoprofile", prof, "-o", "x.o", "x.go")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+}
+
+// A pgoFrame is a frame of a sample of a profile written by
+// writePGOStacks.
+type pgoFrame struct {
+	fn   string
+	line int64
+}
+
+// writePGOStacks writes a CPU profile with the given numbers of samples
+// of the stacks, whose frames run from the innermost.
+func writePGOStacks(t *testing.T, path string, stacks map[int64][]pgoFrame) {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "cpu", Unit: "nanoseconds"},
+		},
+		PeriodType: &profile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+		Period:     10000000,
+	}
+	funcs := make(map[string]*profile.Function)
+	for n, stack := range stacks {
+		s := &profile.Sample{Value: []int64{n, n * p.Period}}
+		for _, fr := range stack {
+			fn := funcs[fr.fn]
+			if fn == nil {
+				fn = &profile.Function{ID: uint64(len(p.Function) + 1), Name: fr.fn, SystemName: fr.fn, Filename: "x.go"}
+				funcs[fr.fn] = fn
+				p.Function = append(p.Function, fn)
+			}
+			loc := &profile.Location{
+				ID:      uint64(len(p.Location) + 1),
+				Address: uint64(0x1000 + len(p.Location)),
+				Line:    []profile.Line{{Function: fn, Line: fr.line}},
+			}
+			p.Location = append(p.Location, loc)
+			s.Location = append(s.Location, loc)
+		}
+		p.Sample = append(p.Sample, s)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Write(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const pgoLayoutCallsSrc = `package p
+
+func f(x []int, k int) int {
+	s := 0
+	for _, v := range x {
+		if v == k {
+			s += g(v)
+		} else {
+			s += h(v)
+		}
+	}
+	return s
+}
+
+//go:noinline
+func g(v int) int { return v * 3 }
+
+//go:noinline
+func h(v int) int { return v * 5 }
+`
+
+// TestPGOLayoutCalls tests that profile-guided layout counts the
+// samples of a call at the line of the call, so that the block of a
+// hot call is not cold because the samples are in the callee.
+func TestPGOLayoutCalls(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestPGOLayoutCalls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(pgoLayoutCallsSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(dir, "cpu.pprof")
+	// The loop calls g, but never h.
+	writePGOStacks(t, prof, map[int64][]pgoFrame{
+		100: {{"p.g", 16}, {"p.f", 7}},
+		20:  {{"p.f", 5}},
+	})
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-pgoprofile", prof, "-d=pgolayout", "-o", "x.o", "x.go")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	// The block that calls g is hot, the one that calls h cold.
+	want := regexp.MustCompile(`(?m)^x\.go:\d+:\d+: profile-guided layout of f: .*b\d+ line 7: 100, .*b\d+ line 9: cold`)
+	if !want.Match(out) {
+		t.Errorf("-d=pgolayout output does not match %v:\n%s", want, out)
+	}
+}
+
+// TestPGOLayoutCold tests that profile-guided layout treats a function
+// in the stack of only a few samples of the profile as cold, but not
+// one in none, which the profile may just not cover.
+func TestPGOLayoutCold(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestPGOLayoutCold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(pgoLayoutCallsSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prof := filepath.Join(dir, "cpu.pprof")
+	// The loop calls g once in 2000 samples, and h, which the profile
+	// does not cover, as often.
+	writePGOStacks(t, prof, map[int64][]pgoFrame{
+		2000: {{"p.f", 5}},
+		1:    {{"p.g", 16}, {"p.f", 7}},
+		2:    {{"p.f", 9}},
+	})
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-pgoprofile", prof, "-d=pgolayout", "-o", "x.o", "x.go")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	// The block that calls g is cold, the one that calls h is not.
+	want := regexp.MustCompile(`(?m)^x\.go:\d+:\d+: profile-guided layout of f: .*b\d+ line 9: 2, .*b\d+ line 7: cold`)
+	if !want.Match(out) {
+		t.Errorf("-d=pgolayout output does not match %v:\n%s", want, out)
+	}
+}
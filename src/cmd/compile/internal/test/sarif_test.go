@@ -0,0 +1,141 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"encoding/json"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const sarifSrc = `package p
+
+import (
+	"fmt"
+	"os"
+)
+
+//go:nosplitt
+func f() {
+	_ = "é"; os.Exit(undefined)
+}
+`
+
+func TestSARIF(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestSARIF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(sarifSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// With a directory, the log is named for the package.
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "example.com/p", "-Wpragma", "-sarif", dir, "-o", filepath.Join(dir, "x.o"), "x.go")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("compilation succeeded unexpectedly:\n%s", out)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "example.com%2Fp.sarif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type region struct {
+		StartLine, StartColumn, EndLine, EndColumn int
+	}
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct{ URI string }
+			Region           region
+		}
+	}
+	type text struct{ Text string }
+	var log struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct{ ID, Name string }
+				}
+			}
+			Results []struct {
+				RuleID    string
+				Level     string
+				Message   text
+				Locations []location
+				Fixes     []struct {
+					Description     text
+					ArtifactChanges []struct {
+						Replacements []struct {
+							DeletedRegion   region
+							InsertedContent text
+						}
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("got version %q and %d runs, want 2.1.0 and 1 run", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+
+	rules := make(map[string]string)
+	for _, r := range run.Tool.Driver.Rules {
+		rules[r.ID] = r.Name
+	}
+	for id, name := range map[string]string{"E0002": "UndeclaredName", "E0006": "UnusedImport", "Wpragma": "pragma"} {
+		if rules[id] != name {
+			t.Errorf("rule %s is named %q, want %q", id, rules[id], name)
+		}
+	}
+
+	type result struct {
+		RuleID, Level, Message string
+		Line, Col              int
+	}
+	var got []result
+	for _, r := range run.Results {
+		if len(r.Locations) != 1 {
+			t.Fatalf("result %q has %d locations, want 1", r.Message.Text, len(r.Locations))
+		}
+		loc := r.Locations[0].PhysicalLocation
+		if loc.ArtifactLocation.URI != "x.go" {
+			t.Errorf("result %q is in %s, want x.go", r.Message.Text, loc.ArtifactLocation.URI)
+		}
+		got = append(got, result{r.RuleID, r.Level, r.Message.Text, loc.Region.StartLine, loc.Region.StartColumn})
+	}
+	want := []result{
+		{"E0006", "error", `imported and not used: "fmt"`, 4, 2},
+		{"Wpragma", "warning", "ignoring unknown compiler directive //go:nosplitt", 8, 3},
+		{"E0002", "error", "undefined: undefined", 10, 19}, // columns count code points
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got results %+v, want %+v", got, want)
+	}
+
+	// The unused import comes with a fix that removes it.
+	if fixes := run.Results[0].Fixes; len(fixes) != 1 || len(fixes[0].ArtifactChanges) != 1 {
+		t.Errorf("got fixes %+v, want one with one change", fixes)
+	} else if r := fixes[0].ArtifactChanges[0].Replacements; len(r) != 1 || r[0].DeletedRegion != (region{4, 2, 4, 7}) || r[0].InsertedContent.Text != "" {
+		t.Errorf("got replacements %+v, want deletion of 4:2-4:7", r)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const spillStatsSrc = `package p
+
+func leaf(x, y int) int { return x*y + x }
+
+//go:noinline
+func g() {}
+
+func live(x, y int) int {
+	g()
+	return x + y
+}
+`
+
+func TestSpillStats(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestSpillStats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "x.go"), []byte(spillStatsSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-d=spillstats=2", "-o", "x.o", "x.go")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+
+	type stats struct {
+		Pos        string
+		Func       string
+		Spills     int
+		Reloads    int
+		SpillBytes int64 `json:"spill_bytes"`
+	}
+	got := make(map[string]stats)
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var s stats
+		if err := dec.Decode(&s); err != nil {
+			t.Fatalf("decoding -d=spillstats=2 output: %v\n%s", err, out)
+		}
+		got[s.Func] = s
+	}
+
+	if s, ok := got["leaf"]; !ok || s.Spills != 0 || s.Reloads != 0 || s.SpillBytes != 0 {
+		t.Errorf("leaf: got %+v, want no spills", s)
+	}
+	// x and y are live across the call of g, so they are spilled
+	// before it and reloaded after it.
+	if s, ok := got["live"]; !ok || s.Spills < 2 || s.Reloads < 2 || s.SpillBytes == 0 {
+		t.Errorf("live: got %+v, want at least 2 spills and reloads", s)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const tparamsSrc = `package p
+
+type Number interface{ ~int | ~float64 }
+
+type List[T any] struct{ elems []T }
+
+func (l *List[T]) Push(v T) { l.elems = append(l.elems, v) }
+
+func Sum[T Number](xs ...T) T {
+	var s T
+	for _, x := range xs {
+		s += x
+	}
+	return s
+}
+
+func unexported[T any](x T) T { return x }
+
+var _ = Sum(1, 2)
+var _ = Sum(1.5)
+var _ = Sum(3)
+var _ List[string]
+var _ = unexported(1)
+`
+
+// TestTParamsSummary tests that -d=tparams lists the exported generic
+// declarations of a package, with their type parameters and the
+// distinct instantiations of them, in source order.
+func TestTParamsSummary(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestTParamsSummary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(tparamsSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-d=tparams", "-o", filepath.Join(dir, "x.o"), src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+	want := `x.go:5:6: type List
+	tparam List.T interface {}
+	instance List[string]
+x.go:9:6: func Sum
+	tparam Sum.T Number
+	instance Sum[int]
+	instance Sum[float64]
+`
+	if got := strings.Replace(string(out), dir+string(filepath.Separator), "", -1); got != want {
+		t.Errorf("compile -d=tparams: got output:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"fmt"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/types"
+)
+
+const typeSnapshotSrc = `package p
+
+type List[T any] struct {
+	next *List[T]
+	val  T
+}
+
+type Celsius float64
+
+type alias = Celsius
+
+var _ List[int]
+
+func f() {
+	type local int
+}
+`
+
+// TestTypeSnapshot checks that -typesnapshot writes the package-level
+// type declarations, and only those, in the encoding of types.Encode.
+func TestTypeSnapshot(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestTypeSnapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(typeSnapshotSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	snap := filepath.Join(dir, "types.bin")
+	cmd := exec.Command(testenv.GoToolPath(t), "tool", "compile", "-p", "p", "-typesnapshot", snap, "-o", filepath.Join(dir, "x.o"), "x.go")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("compilation failed: %v\n%s", err, out)
+	}
+	data, err := ioutil.ReadFile(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := types.DecodeTypes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, typ := range ts {
+		got = append(got, fmt.Sprintf("%v: %L", typ, typ))
+	}
+	want := []string{
+		"Celsius: float64",
+		`List: struct { next *List["".List.T]; val List.T }`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("-typesnapshot wrote:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
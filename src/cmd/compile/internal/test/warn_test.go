@@ -0,0 +1,69 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const warnSrc = `package p
+
+//go:nosplitt
+func f(i int, r rune) (string, string) {
+	return string(i), string(r)
+}
+`
+
+func TestWarnings(t *testing.T) {
+	t.Parallel()
+
+	testenv.MustHaveGoBuild(t)
+
+	dir, err := ioutil.TempDir("", "TestWarnings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(warnSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	compile := func(flags ...string) (string, error) {
+		args := append([]string{"tool", "compile", "-o", filepath.Join(dir, "x.o")}, flags...)
+		cmd := exec.Command(testenv.GoToolPath(t), append(args, src)...)
+		out, err := cmd.CombinedOutput()
+		return strings.Replace(string(out), src, "x.go", -1), err
+	}
+
+	tests := []struct {
+		flags   []string
+		want    string
+		wantErr bool
+	}{
+		{nil, "", false},
+		{[]string{"-Wpragma"}, "x.go:3:3: warning: ignoring unknown compiler directive //go:nosplitt [-Wpragma]\n", false},
+		{[]string{"-Wall"}, "x.go:3:3: warning: ignoring unknown compiler directive //go:nosplitt [-Wpragma]\n" +
+			"x.go:5:9: warning: conversion from int to string yields a string of one rune, not a string of digits [-Wconversion]\n", false},
+		{[]string{"-Wconversion", "-Werror"}, "x.go:5:9: conversion from int to string yields a string of one rune, not a string of digits [-Wconversion]\n", true},
+		{[]string{"-Werror"}, "", false},
+	}
+	for _, test := range tests {
+		out, err := compile(test.flags...)
+		if (err != nil) != test.wantErr {
+			t.Errorf("compile %v: error %v, want error %v", test.flags, err, test.wantErr)
+		}
+		if out != test.want {
+			t.Errorf("compile %v: got output:\n%s\nwant:\n%s", test.flags, out, test.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typecheck
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+// checkCollisions warns, for -Wsymcollide, about exported names that
+// are distinct in Go but collide when case is ignored or compatibility
+// characters are replaced by the characters they stand for, as on
+// case-insensitive file systems and in some object file formats. It
+// checks the package-level declarations in exports, and the methods
+// of each exported type.
+func checkCollisions(exports []*ir.Name) {
+	var decls []*ir.Name
+	for _, n := range exports {
+		if n.Sym().Pkg == types.LocalPkg && types.IsExported(n.Sym().Name) {
+			decls = append(decls, n)
+		}
+	}
+	sort.Slice(decls, func(i, j int) bool { return decls[i].Pos().Before(decls[j].Pos()) })
+
+	seen := make(map[string]*ir.Name)
+	for _, n := range decls {
+		key := collisionKey(n.Sym().Name)
+		if prev := seen[key]; prev != nil {
+			warnCollision(n.Pos(), n.Sym().Name, prev.Pos(), prev.Sym().Name)
+		} else {
+			seen[key] = n
+		}
+	}
+
+	for _, n := range decls {
+		if n.Op() != ir.OTYPE || n.Type() == nil {
+			continue
+		}
+		methods := make(map[string]*types.Field)
+		for _, m := range n.Type().Methods().Slice() {
+			if !types.IsExported(m.Sym.Name) {
+				continue
+			}
+			key := collisionKey(m.Sym.Name)
+			if prev := methods[key]; prev != nil {
+				tname := n.Sym().Name
+				warnCollision(m.Pos, tname+"."+m.Sym.Name, prev.Pos, tname+"."+prev.Sym.Name)
+			} else {
+				methods[key] = m
+			}
+		}
+	}
+}
+
+func warnCollision(pos src.XPos, name string, prevPos src.XPos, prev string) {
+	base.WarningfAt(pos, base.WarnSymCollide, "%s collides with %s at %v when case and compatibility characters are ignored", name, prev, base.FmtPos(prevPos))
+}
+
+// collisionKey returns the key by which name is compared with other
+// names for collisions: name with each character replaced by its
+// compatibility decomposition, if listed in compatChars, and then by
+// the smallest character it folds to under simple case folding.
+//
+// This approximates comparing NFKC normalizations case-insensitively:
+// the normalization tables are not available to the compiler, so only
+// the compatibility characters most likely to appear in identifiers
+// are handled.
+func collisionKey(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if s, ok := compatChars[r]; ok {
+			for _, r := range s {
+				b.WriteRune(foldRune(r))
+			}
+			continue
+		}
+		b.WriteRune(foldRune(r))
+	}
+	return b.String()
+}
+
+// foldRune returns the smallest character in r's case folding orbit.
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+// compatChars maps letters and digits with compatibility
+// decompositions to the decompositions.
+var compatChars = map[rune]string{
+	'ª': "a",
+	'µ': "μ",
+	'º': "o",
+	'ſ': "s",
+	'ǆ': "dž", 'ǅ': "Dž", 'Ǆ': "DŽ",
+	'ǉ': "lj", 'ǈ': "Lj", 'Ǉ': "LJ",
+	'ǌ': "nj", 'ǋ': "Nj", 'Ǌ': "NJ",
+	'ǳ': "dz", 'ǲ': "Dz", 'Ǳ': "DZ",
+	'ℂ': "C", 'ℊ': "g", 'ℋ': "H", 'ℌ': "H", 'ℍ': "H", 'ℎ': "h",
+	'ℐ': "I", 'ℑ': "I", 'ℒ': "L", 'ℓ': "l", 'ℕ': "N", 'ℙ': "P",
+	'ℚ': "Q", 'ℛ': "R", 'ℜ': "R", 'ℝ': "R", 'ℤ': "Z", 'Ω': "Ω",
+	'K': "K", 'Å': "Å", 'ℬ': "B", 'ℭ': "C", 'ℯ': "e", 'ℰ': "E",
+	'ℱ': "F", 'ℳ': "M", 'ℴ': "o", 'ℹ': "i",
+	'ﬀ': "ff", 'ﬁ': "fi", 'ﬂ': "fl", 'ﬃ': "ffi", 'ﬄ': "ffl", 'ﬅ': "st", 'ﬆ': "st",
+}
+
+func init() {
+	// Fullwidth forms of ASCII letters, digits and underscore.
+	for r := rune(0xFF10); r <= 0xFF5A; r++ {
+		if c := r - 0xFF10 + '0'; unicode.IsLetter(c) || unicode.IsDigit(c) {
+			compatChars[r] = string(c)
+		}
+	}
+	compatChars['＿'] = "_"
+}
@@ -10,10 +10,12 @@ import (
 	"go/token"
 	"math"
 	"math/big"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/types"
 	"cmd/internal/src"
@@ -342,12 +344,46 @@ func overflow(v constant.Value, t *types.Type) bool {
 		return true
 	}
 	if ir.ConstOverflow(v, t) {
-		base.Errorf("constant %v overflows %v", types.FmtConst(v, false), t)
+		if note := rangeNote(v, t); note != "" {
+			base.Errorf("constant %v overflows %v (%s)", types.FmtConst(v, false), t, note)
+		} else {
+			base.Errorf("constant %v overflows %v", types.FmtConst(v, false), t)
+		}
 		return true
 	}
 	return false
 }
 
+// rangeNote returns a note for the error about the constant v, which t
+// cannot represent, as in "valid range is -128 to 127". If t is a
+// floating-point type, the note also gives the value of t nearest to v.
+// It returns "" if t is not an integer or floating-point type.
+func rangeNote(v constant.Value, t *types.Type) string {
+	switch {
+	case t.IsInteger():
+		bits := uint(8 * t.Size())
+		min := constant.MakeInt64(0)
+		max := constant.Shift(constant.MakeInt64(1), token.SHL, bits)
+		if !t.IsUnsigned() {
+			max = constant.Shift(max, token.SHR, 1)
+			min = constant.UnaryOp(token.SUB, max, 0)
+		}
+		max = constant.BinaryOp(max, token.SUB, constant.MakeInt64(1))
+		return "valid range is " + min.ExactString() + " to " + max.ExactString()
+	case t.IsFloat():
+		max := strconv.FormatFloat(math.MaxFloat64, 'g', -1, 64)
+		if t.Size() == 4 {
+			max = strconv.FormatFloat(math.MaxFloat32, 'g', -1, 32)
+		}
+		near := max
+		if constant.Sign(v) < 0 {
+			near = "-" + max
+		}
+		return "valid range is -" + max + " to " + max + ", nearest value is " + near
+	}
+	return ""
+}
+
 func tostr(v constant.Value) constant.Value {
 	if v.Kind() == constant.Int {
 		r := unicode.ReplacementChar
@@ -607,7 +643,7 @@ func OrigConst(n ir.Node, v constant.Value) ir.Node {
 		if what == "" {
 			base.Fatalf("unexpected overflow: %v", n.Op())
 		}
-		base.ErrorfAt(n.Pos(), "constant %v overflow", what)
+		base.ErrorfAt(n.Pos(), errcode.NumericOverflow, "constant %v overflow", what)
 		n.SetType(nil)
 		return n
 	}
@@ -840,7 +876,7 @@ func (s *constSet) add(pos src.XPos, n ir.Node, what, where string) {
 	}
 
 	if prevPos, isDup := s.m[k]; isDup {
-		base.ErrorfAt(pos, "duplicate %s %s in %s\n\tprevious %s at %v",
+		base.ErrorfAt(pos, errcode.DuplicateLitKey, "duplicate %s %s in %s\n\tprevious %s at %v",
 			what, nodeAndVal(n), where,
 			what, base.FmtPos(prevPos))
 	} else {
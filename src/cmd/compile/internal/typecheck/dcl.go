@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/types"
 	"cmd/internal/src"
@@ -43,15 +44,15 @@ func Declare(n *ir.Name, ctxt ir.Class) {
 
 	// kludgy: TypecheckAllowed means we're past parsing. Eg reflectdata.methodWrapper may declare out of package names later.
 	if !inimport && !TypecheckAllowed && s.Pkg != types.LocalPkg {
-		base.ErrorfAt(n.Pos(), "cannot declare name %v", s)
+		base.ErrorfAt(n.Pos(), 0, "cannot declare name %v", s)
 	}
 
 	if ctxt == ir.PEXTERN {
 		if s.Name == "init" {
-			base.ErrorfAt(n.Pos(), "cannot declare init - must be func")
+			base.ErrorfAt(n.Pos(), errcode.InvalidInitDecl, "cannot declare init - must be func")
 		}
 		if s.Name == "main" && s.Pkg.Name == "main" {
-			base.ErrorfAt(n.Pos(), "cannot declare main - must be func")
+			base.ErrorfAt(n.Pos(), errcode.InvalidMainDecl, "cannot declare main - must be func")
 		}
 		Target.Externs = append(Target.Externs, n)
 	} else {
@@ -117,7 +118,7 @@ func Redeclared(pos src.XPos, s *types.Sym, where string) {
 		} else {
 			pkgName = DotImportRefs[s.Def.(*ir.Ident)]
 		}
-		base.ErrorfAt(pos, "%v redeclared %s\n"+
+		base.ErrorfAt(pos, errcode.DuplicateDecl, "%v redeclared %s\n"+
 			"\t%v: previous declaration during import %q", s, where, base.FmtPos(pkgName.Pos()), pkgName.Pkg.Path)
 	} else {
 		prevPos := s.Lastlineno
@@ -130,7 +131,7 @@ func Redeclared(pos src.XPos, s *types.Sym, where string) {
 			pos, prevPos = prevPos, pos
 		}
 
-		base.ErrorfAt(pos, "%v redeclared %s\n"+
+		base.ErrorfAt(pos, errcode.DuplicateDecl, "%v redeclared %s\n"+
 			"\t%v: previous declaration", s, where, base.FmtPos(prevPos))
 	}
 }
@@ -278,17 +279,18 @@ func autoexport(n *ir.Name, ctxt ir.Class) {
 // checkdupfields emits errors for duplicately named fields or methods in
 // a list of struct or interface types.
 func checkdupfields(what string, fss ...[]*types.Field) {
-	seen := make(map[*types.Sym]bool)
+	seen := make(map[*types.Sym]*types.Field)
 	for _, fs := range fss {
 		for _, f := range fs {
 			if f.Sym == nil || f.Sym.IsBlank() {
 				continue
 			}
-			if seen[f.Sym] {
-				base.ErrorfAt(f.Pos, "duplicate %s %s", what, f.Sym.Name)
+			if prev := seen[f.Sym]; prev != nil {
+				related := []base.Related{{Pos: prev.Pos, Msg: "other declaration of " + f.Sym.Name}}
+				base.ErrorfAtDetails(f.Pos, errcode.DuplicateDecl, base.ErrorDetails{Related: related}, "duplicate %s %s", what, f.Sym.Name)
 				continue
 			}
-			seen[f.Sym] = true
+			seen[f.Sym] = f
 		}
 	}
 }
@@ -359,7 +361,7 @@ func funcargs(nt *ir.FuncType) {
 	for i, n := range nt.Results {
 		if n.Sym == nil {
 			// Name so that escape analysis can track it. ~r stands for 'result'.
-			n.Sym = LookupNum("~r", i)
+			n.Sym = types.ParamSym(types.ParamResult, i)
 		} else if n.Sym.IsBlank() {
 			// Give it a name so we can assign to it during return. ~b stands for 'blank'.
 			// The name must be different from ~r above because if you have
@@ -367,7 +369,7 @@ func funcargs(nt *ir.FuncType) {
 			//	func g() int
 			// f is allowed to use a plain 'return' with no arguments, while g is not.
 			// So the two cases must be distinguished.
-			n.Sym = LookupNum("~b", i)
+			n.Sym = types.ParamSym(types.ParamBlank, i)
 		}
 
 		funcarg(n, ir.PPARAMOUT)
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/types"
 )
@@ -210,7 +211,7 @@ func tcCompLit(n *ir.CompLitExpr) (res ir.Node) {
 	}()
 
 	if n.Ntype == nil {
-		base.ErrorfAt(n.Pos(), "missing type in composite literal")
+		base.ErrorfAt(n.Pos(), errcode.UntypedLit, "missing type in composite literal")
 		n.SetType(nil)
 		return n
 	}
@@ -574,7 +575,7 @@ func tcDotType(n *ir.TypeAssertExpr) ir.Node {
 		if !implements(n.Type(), t, &missing, &have, &ptr) {
 			if have != nil && have.Sym == missing.Sym {
 				base.Errorf("impossible type assertion:\n\t%v does not implement %v (wrong type for %v method)\n"+
-					"\t\thave %v%S\n\t\twant %v%S", n.Type(), t, missing.Sym, have.Sym, have.Type, missing.Sym, missing.Type)
+					"\t\thave %v%S\n\t\twant %v%S\n\t\t%s", n.Type(), t, missing.Sym, have.Sym, have.Type, missing.Sym, missing.Type, typeDiff(have.Type, missing.Type))
 			} else if ptr != 0 {
 				base.Errorf("impossible type assertion:\n\t%v does not implement %v (%v method has pointer receiver)", n.Type(), t, missing.Sym)
 			} else if have != nil {
@@ -87,6 +87,17 @@
 //             Recv      Param
 //             Signature Signature
 //         }
+//
+//         // Methods with type parameters of their own
+//         // (GOEXPERIMENT=genericmethods); omitted if
+//         // Underlying is an interface type
+//         GenericMethods []struct{
+//             Pos        Pos
+//             Name       stringOff
+//             Recv       Param
+//             TypeParams []typeOff
+//             Signature  Signature
+//         }
 //     }
 //
 //     type Alias struct {
@@ -296,6 +307,10 @@ const (
 	magic = 0x6742937dc293105
 )
 
+// exportLog logs the sections of the export data and the offsets at
+// which declarations, types and strings are written.
+var exportLog = base.NewLogger("export")
+
 // WriteExports writes the indexed export format to out. If extensions
 // is true, then the compiler-only extensions are included.
 func WriteExports(out io.Writer, extensions bool) {
@@ -304,6 +319,9 @@ func WriteExports(out io.Writer, extensions bool) {
 		// which bodies to include.
 		crawlExports(Target.Exports)
 	}
+	if base.WarnSymCollide.Enabled() {
+		checkCollisions(Target.Exports)
+	}
 
 	p := iexporter{
 		allPkgs:     map[*types.Pkg]bool{},
@@ -340,9 +358,7 @@ func WriteExports(out io.Writer, extensions bool) {
 	w.writeIndex(p.inlineIndex, false)
 	w.flush()
 
-	if *base.Flag.LowerV {
-		fmt.Printf("export: hdr strings %v, data %v, index %v\n", p.strings.Len(), dataLen, p.data0.Len())
-	}
+	exportLog.Log(src.NoXPos, "section sizes", "strings", p.strings.Len(), "data", dataLen, "index", p.data0.Len())
 
 	// Assemble header.
 	var hdr intWriter
@@ -445,8 +461,8 @@ func (p *iexporter) stringOff(s string) uint64 {
 		off = uint64(p.strings.Len())
 		p.stringIndex[s] = off
 
-		if *base.Flag.LowerV {
-			fmt.Printf("export: str %v %.40q\n", off, s)
+		if exportLog.Enabled() {
+			exportLog.Log(src.NoXPos, "string", "off", off, "str", fmt.Sprintf("%.40s", s))
 		}
 
 		p.strings.uint64(uint64(len(s)))
@@ -626,6 +642,17 @@ func (p *iexporter) doDecl(n *ir.Name) {
 			sort.Sort(types.MethodsByName(methods))
 		}
 
+		// Generic methods follow the others, so that importers
+		// that don't know about them can stop reading early.
+		var generic []*types.Field
+		for i := 0; i < len(methods); i++ {
+			if m := methods[i]; m.IsGenericMethod() {
+				generic = append(generic, m)
+				methods = append(methods[:i], methods[i+1:]...)
+				i--
+			}
+		}
+
 		w.uint64(uint64(len(methods)))
 		for _, m := range methods {
 			w.pos(m.Pos)
@@ -634,11 +661,23 @@ func (p *iexporter) doDecl(n *ir.Name) {
 			w.signature(m.Type)
 		}
 
+		w.uint64(uint64(len(generic)))
+		for _, m := range generic {
+			w.pos(m.Pos)
+			w.selector(m.Sym)
+			w.param(m.Type.Recv())
+			w.tparamList(m.Type.TParams().FieldSlice())
+			w.signature(m.Type)
+		}
+
 		if w.p.extensions {
 			w.typeExt(t)
 			for _, m := range methods {
 				w.methExt(m)
 			}
+			for _, m := range generic {
+				w.methExt(m)
+			}
 		}
 
 	default:
@@ -654,8 +693,8 @@ func (w *exportWriter) tag(tag byte) {
 
 func (w *exportWriter) finish(what string, index map[*types.Sym]uint64, sym *types.Sym) {
 	off := w.flush()
-	if *base.Flag.LowerV {
-		fmt.Printf("export: %v %v %v\n", what, off, sym)
+	if exportLog.Enabled() {
+		exportLog.Log(src.NoXPos, "index entry", "section", what, "off", off, "sym", sym)
 	}
 	index[sym] = off
 }
@@ -907,8 +946,8 @@ func (p *iexporter) typOff(t *types.Type) uint64 {
 		w := p.newWriter()
 		w.doTyp(t)
 		rawOff := w.flush()
-		if *base.Flag.LowerV {
-			fmt.Printf("export: typ %v %v\n", rawOff, t)
+		if exportLog.Enabled() {
+			exportLog.Log(src.NoXPos, "type", "off", rawOff, "type", t)
 		}
 		off = predeclReserved + rawOff
 		p.typIndex[t] = off
@@ -1050,11 +1089,12 @@ func (w *exportWriter) doTyp(t *types.Type) {
 		// TODO(danscales): possibly put out the tilde bools in more
 		// compact form.
 		w.startType(unionType)
-		nt := t.NumTerms()
-		w.uint64(uint64(nt))
-		for i := 0; i < nt; i++ {
-			typ, tilde := t.Term(i)
-			w.bool(tilde)
+		// Write the terms in their original order, which is what
+		// other importers of the export data expect.
+		terms, tildes := t.SourceTerms()
+		w.uint64(uint64(len(terms)))
+		for i, typ := range terms {
+			w.bool(tildes[i])
 			w.typ(typ)
 		}
 
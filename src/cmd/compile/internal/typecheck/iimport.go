@@ -179,7 +179,6 @@ func ReadImports(pkg *types.Pkg, data string) {
 		if pkg.Name == "" {
 			pkg.Name = pkgName
 			pkg.Height = pkgHeight
-			types.NumImport[pkgName]++
 
 			// TODO(mdempsky): This belongs somewhere else.
 			pkg.Lookup("_").Def = ir.BlankNode
@@ -375,24 +374,32 @@ func (r *importReader) doDecl(sym *types.Sym) *ir.Name {
 			return n
 		}
 
-		ms := make([]*types.Field, r.uint64())
-		for i := range ms {
-			mpos := r.pos()
-			msym := r.selector()
-			recv := r.param()
-			mtyp := r.signature(recv, nil)
+		// The methods with type parameters of their own follow the
+		// others.
+		var ms []*types.Field
+		for _, generic := range []bool{false, true} {
+			for n := r.uint64(); n > 0; n-- {
+				mpos := r.pos()
+				msym := r.selector()
+				recv := r.param()
+				var tparams []*types.Field
+				if generic {
+					tparams = r.tparamList()
+				}
+				mtyp := r.signature(recv, tparams)
 
-			// MethodSym already marked m.Sym as a function.
-			m := ir.NewNameAt(mpos, ir.MethodSym(recv.Type, msym))
-			m.Class = ir.PFUNC
-			m.SetType(mtyp)
+				// MethodSym already marked m.Sym as a function.
+				m := ir.NewNameAt(mpos, ir.MethodSym(recv.Type, msym))
+				m.Class = ir.PFUNC
+				m.SetType(mtyp)
 
-			m.Func = ir.NewFunc(mpos)
-			m.Func.Nname = m
+				m.Func = ir.NewFunc(mpos)
+				m.Func.Nname = m
 
-			f := types.NewField(mpos, msym, mtyp)
-			f.Nname = m
-			ms[i] = f
+				f := types.NewField(mpos, msym, mtyp)
+				f.Nname = m
+				ms = append(ms, f)
+			}
 		}
 		t.Methods().Set(ms)
 
@@ -6,6 +6,7 @@ package typecheck
 
 import (
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/types"
 	"cmd/internal/src"
@@ -37,7 +38,7 @@ func typecheckrangeExpr(n *ir.RangeStmt) {
 	toomany := false
 	switch t.Kind() {
 	default:
-		base.ErrorfAt(n.Pos(), "cannot range over %L", n.X)
+		base.ErrorfAt(n.Pos(), 0, "cannot range over %L", n.X)
 		return
 
 	case types.TARRAY, types.TSLICE:
@@ -50,7 +51,7 @@ func typecheckrangeExpr(n *ir.RangeStmt) {
 
 	case types.TCHAN:
 		if !t.ChanDir().CanRecv() {
-			base.ErrorfAt(n.Pos(), "invalid operation: range %v (receive from send-only type %v)", n.X, n.X.Type())
+			base.ErrorfAt(n.Pos(), errcode.InvalidArgument, "invalid operation: range %v (receive from send-only type %v)", n.X, n.X.Type())
 			return
 		}
 
@@ -66,7 +67,7 @@ func typecheckrangeExpr(n *ir.RangeStmt) {
 	}
 
 	if toomany {
-		base.ErrorfAt(n.Pos(), "too many variables in range")
+		base.ErrorfAt(n.Pos(), 0, "too many variables in range")
 	}
 
 	do := func(nn ir.Node, t *types.Type) {
@@ -75,7 +76,7 @@ func typecheckrangeExpr(n *ir.RangeStmt) {
 				nn.SetType(t)
 			} else if nn.Type() != nil {
 				if op, why := Assignop(t, nn.Type()); op == ir.OXXX {
-					base.ErrorfAt(n.Pos(), "cannot assign type %v to %L in range%s", t, nn, why)
+					base.ErrorfAt(n.Pos(), 0, "cannot assign type %v to %L in range%s", t, nn, why)
 				}
 			}
 			checkassign(n, nn)
@@ -188,10 +189,10 @@ assignOK:
 	if len(lhs) != cr {
 		if r, ok := rhs[0].(*ir.CallExpr); ok && len(rhs) == 1 {
 			if r.Type() != nil {
-				base.ErrorfAt(stmt.Pos(), "assignment mismatch: %d variable%s but %v returns %d value%s", len(lhs), plural(len(lhs)), r.X, cr, plural(cr))
+				base.ErrorfAt(stmt.Pos(), errcode.WrongAssignCount, "assignment mismatch: %d variable%s but %v returns %d value%s", len(lhs), plural(len(lhs)), r.X, cr, plural(cr))
 			}
 		} else {
-			base.ErrorfAt(stmt.Pos(), "assignment mismatch: %d variable%s but %v value%s", len(lhs), plural(len(lhs)), len(rhs), plural(len(rhs)))
+			base.ErrorfAt(stmt.Pos(), errcode.WrongAssignCount, "assignment mismatch: %d variable%s but %v value%s", len(lhs), plural(len(lhs)), len(rhs), plural(len(rhs)))
 		}
 
 		for i := range lhs {
@@ -303,7 +304,7 @@ func tcGoDefer(n *ir.GoDeferStmt) {
 		if orig := ir.Orig(n.Call); orig.Op() == ir.OCONV {
 			break
 		}
-		base.ErrorfAt(n.Pos(), "%s discards result of %v", what, n.Call)
+		base.ErrorfAt(n.Pos(), 0, "%s discards result of %v", what, n.Call)
 		return
 	}
 
@@ -317,7 +318,7 @@ func tcGoDefer(n *ir.GoDeferStmt) {
 		// The syntax made sure it was a call, so this must be
 		// a conversion.
 		n.SetDiag(true)
-		base.ErrorfAt(n.Pos(), "%s requires function call, not conversion", what)
+		base.ErrorfAt(n.Pos(), 0, "%s requires function call, not conversion", what)
 	}
 }
 
@@ -387,7 +388,7 @@ func tcSelect(sel *ir.SelectStmt) {
 		if ncase.Comm == nil {
 			// default
 			if def != nil {
-				base.ErrorfAt(ncase.Pos(), "multiple defaults in select (first at %v)", ir.Line(def))
+				base.ErrorfAt(ncase.Pos(), errcode.DuplicateDefault, "multiple defaults in select (first at %v)", ir.Line(def))
 			} else {
 				def = ncase
 			}
@@ -411,7 +412,7 @@ func tcSelect(sel *ir.SelectStmt) {
 					// on the same line). This matches the approach before 1.10.
 					pos = ncase.Pos()
 				}
-				base.ErrorfAt(pos, "select case must be receive, send or assign recv")
+				base.ErrorfAt(pos, 0, "select case must be receive, send or assign recv")
 
 			case ir.OAS:
 				// convert x = <-c into x, _ = <-c
@@ -425,7 +426,7 @@ func tcSelect(sel *ir.SelectStmt) {
 					}
 				}
 				if n.Y.Op() != ir.ORECV {
-					base.ErrorfAt(n.Pos(), "select assignment must have receive on right hand side")
+					base.ErrorfAt(n.Pos(), 0, "select assignment must have receive on right hand side")
 					break
 				}
 				oselrecv2(n.X, n.Y, n.Def)
@@ -433,7 +434,7 @@ func tcSelect(sel *ir.SelectStmt) {
 			case ir.OAS2RECV:
 				n := n.(*ir.AssignListStmt)
 				if n.Rhs[0].Op() != ir.ORECV {
-					base.ErrorfAt(n.Pos(), "select assignment must have receive on right hand side")
+					base.ErrorfAt(n.Pos(), 0, "select assignment must have receive on right hand side")
 					break
 				}
 				n.SetOp(ir.OSELRECV2)
@@ -510,9 +511,9 @@ func tcSwitchExpr(n *ir.SwitchStmt) {
 
 		case !types.IsComparable(t):
 			if t.IsStruct() {
-				base.ErrorfAt(n.Pos(), "cannot switch on %L (struct containing %v cannot be compared)", n.Tag, types.IncomparableField(t).Type)
+				base.ErrorfAt(n.Pos(), errcode.InvalidExprSwitch, "cannot switch on %L (struct containing %v cannot be compared)", n.Tag, types.IncomparableField(t).Type)
 			} else {
-				base.ErrorfAt(n.Pos(), "cannot switch on %L", n.Tag)
+				base.ErrorfAt(n.Pos(), errcode.InvalidExprSwitch, "cannot switch on %L", n.Tag)
 			}
 			t = nil
 		}
@@ -524,7 +525,7 @@ func tcSwitchExpr(n *ir.SwitchStmt) {
 		ls := ncase.List
 		if len(ls) == 0 { // default:
 			if defCase != nil {
-				base.ErrorfAt(ncase.Pos(), "multiple defaults in switch (first at %v)", ir.Line(defCase))
+				base.ErrorfAt(ncase.Pos(), errcode.DuplicateDefault, "multiple defaults in switch (first at %v)", ir.Line(defCase))
 			} else {
 				defCase = ncase
 			}
@@ -540,17 +541,17 @@ func tcSwitchExpr(n *ir.SwitchStmt) {
 			}
 
 			if nilonly != "" && !ir.IsNil(n1) {
-				base.ErrorfAt(ncase.Pos(), "invalid case %v in switch (can only compare %s %v to nil)", n1, nilonly, n.Tag)
+				base.ErrorfAt(ncase.Pos(), 0, "invalid case %v in switch (can only compare %s %v to nil)", n1, nilonly, n.Tag)
 			} else if t.IsInterface() && !n1.Type().IsInterface() && !types.IsComparable(n1.Type()) {
-				base.ErrorfAt(ncase.Pos(), "invalid case %L in switch (incomparable type)", n1)
+				base.ErrorfAt(ncase.Pos(), 0, "invalid case %L in switch (incomparable type)", n1)
 			} else {
 				op1, _ := Assignop(n1.Type(), t)
 				op2, _ := Assignop(t, n1.Type())
 				if op1 == ir.OXXX && op2 == ir.OXXX {
 					if n.Tag != nil {
-						base.ErrorfAt(ncase.Pos(), "invalid case %v in switch on %v (mismatched types %v and %v)", n1, n.Tag, n1.Type(), t)
+						base.ErrorfAt(ncase.Pos(), errcode.MismatchedTypes, "invalid case %v in switch on %v (mismatched types %v and %v)", n1, n.Tag, n1.Type(), t)
 					} else {
-						base.ErrorfAt(ncase.Pos(), "invalid case %v in switch (mismatched types %v and bool)", n1, n1.Type())
+						base.ErrorfAt(ncase.Pos(), errcode.MismatchedTypes, "invalid case %v in switch (mismatched types %v and bool)", n1, n1.Type())
 					}
 				}
 			}
@@ -575,7 +576,7 @@ func tcSwitchType(n *ir.SwitchStmt) {
 	guard.X = Expr(guard.X)
 	t := guard.X.Type()
 	if t != nil && !t.IsInterface() {
-		base.ErrorfAt(n.Pos(), "cannot type switch on non-interface value %L", guard.X)
+		base.ErrorfAt(n.Pos(), 0, "cannot type switch on non-interface value %L", guard.X)
 		t = nil
 	}
 
@@ -583,7 +584,7 @@ func tcSwitchType(n *ir.SwitchStmt) {
 	// declaration itself. So if there are no cases, we won't
 	// notice that it went unused.
 	if v := guard.Tag; v != nil && !ir.IsBlank(v) && len(n.Cases) == 0 {
-		base.ErrorfAt(v.Pos(), "%v declared but not used", v.Sym())
+		base.MsgDeclaredNotUsed.ErrorAt(v.Pos(), base.MsgArgs{"name": v.Sym()})
 	}
 
 	var defCase, nilCase ir.Node
@@ -592,7 +593,7 @@ func tcSwitchType(n *ir.SwitchStmt) {
 		ls := ncase.List
 		if len(ls) == 0 { // default:
 			if defCase != nil {
-				base.ErrorfAt(ncase.Pos(), "multiple defaults in switch (first at %v)", ir.Line(defCase))
+				base.ErrorfAt(ncase.Pos(), errcode.DuplicateDefault, "multiple defaults in switch (first at %v)", ir.Line(defCase))
 			} else {
 				defCase = ncase
 			}
@@ -609,25 +610,25 @@ func tcSwitchType(n *ir.SwitchStmt) {
 			var ptr int
 			if ir.IsNil(n1) { // case nil:
 				if nilCase != nil {
-					base.ErrorfAt(ncase.Pos(), "multiple nil cases in type switch (first at %v)", ir.Line(nilCase))
+					base.ErrorfAt(ncase.Pos(), errcode.DuplicateCase, "multiple nil cases in type switch (first at %v)", ir.Line(nilCase))
 				} else {
 					nilCase = ncase
 				}
 				continue
 			}
 			if n1.Op() != ir.OTYPE {
-				base.ErrorfAt(ncase.Pos(), "%L is not a type", n1)
+				base.ErrorfAt(ncase.Pos(), errcode.NotAType, "%L is not a type", n1)
 				continue
 			}
 			if !n1.Type().IsInterface() && !implements(n1.Type(), t, &missing, &have, &ptr) && !missing.Broke() {
 				if have != nil && !have.Broke() {
-					base.ErrorfAt(ncase.Pos(), "impossible type switch case: %L cannot have dynamic type %v"+
-						" (wrong type for %v method)\n\thave %v%S\n\twant %v%S", guard.X, n1.Type(), missing.Sym, have.Sym, have.Type, missing.Sym, missing.Type)
+					base.ErrorfAt(ncase.Pos(), errcode.ImpossibleAssert, "impossible type switch case: %L cannot have dynamic type %v"+
+						" (wrong type for %v method)\n\thave %v%S\n\twant %v%S\n\t%s", guard.X, n1.Type(), missing.Sym, have.Sym, have.Type, missing.Sym, missing.Type, typeDiff(have.Type, missing.Type))
 				} else if ptr != 0 {
-					base.ErrorfAt(ncase.Pos(), "impossible type switch case: %L cannot have dynamic type %v"+
+					base.ErrorfAt(ncase.Pos(), errcode.ImpossibleAssert, "impossible type switch case: %L cannot have dynamic type %v"+
 						" (%v method has pointer receiver)", guard.X, n1.Type(), missing.Sym)
 				} else {
-					base.ErrorfAt(ncase.Pos(), "impossible type switch case: %L cannot have dynamic type %v"+
+					base.ErrorfAt(ncase.Pos(), errcode.ImpossibleAssert, "impossible type switch case: %L cannot have dynamic type %v"+
 						" (missing %v method)", guard.X, n1.Type(), missing.Sym)
 				}
 				continue
@@ -676,7 +677,7 @@ func (s *typeSet) add(pos src.XPos, typ *types.Type) {
 
 	ls := typ.LinkString()
 	if prev, ok := s.m[ls]; ok {
-		base.ErrorfAt(pos, "duplicate case %v in type switch\n\tprevious case at %s", typ, base.FmtPos(prev))
+		base.ErrorfAt(pos, errcode.DuplicateCase, "duplicate case %v in type switch\n\tprevious case at %s", typ, base.FmtPos(prev))
 		return
 	}
 	s.m[ls] = pos
@@ -7,7 +7,6 @@ package typecheck
 import (
 	"bytes"
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -43,7 +42,7 @@ func NewFuncParams(tl *types.Type, mustname bool) []*ir.Field {
 		s := t.Sym
 		if mustname && (s == nil || s.Name == "_") {
 			// invent a name so that we can refer to it in the trampoline
-			s = LookupNum(".anon", gen)
+			s = types.ParamSym(types.ParamAnon, gen)
 			gen++
 		} else if s != nil && s.Pkg != types.LocalPkg {
 			// TODO(mdempsky): Preserve original position, name, and package.
@@ -173,57 +172,10 @@ func AddImplicitDots(n *ir.SelectorExpr) *ir.SelectorExpr {
 	return n
 }
 
+// CalcMethods computes the methods of t, including promoted ones.
+// See types.CalcMethods.
 func CalcMethods(t *types.Type) {
-	if t == nil || t.AllMethods().Len() != 0 {
-		return
-	}
-
-	// mark top-level method symbols
-	// so that expand1 doesn't consider them.
-	for _, f := range t.Methods().Slice() {
-		f.Sym.SetUniq(true)
-	}
-
-	// generate all reachable methods
-	slist = slist[:0]
-	expand1(t, true)
-
-	// check each method to be uniquely reachable
-	var ms []*types.Field
-	for i, sl := range slist {
-		slist[i].field = nil
-		sl.field.Sym.SetUniq(false)
-
-		var f *types.Field
-		path, _ := dotpath(sl.field.Sym, t, &f, false)
-		if path == nil {
-			continue
-		}
-
-		// dotpath may have dug out arbitrary fields, we only want methods.
-		if !f.IsMethod() {
-			continue
-		}
-
-		// add it to the base type method list
-		f = f.Copy()
-		f.Embedded = 1 // needs a trampoline
-		for _, d := range path {
-			if d.field.Type.IsPtr() {
-				f.Embedded = 2
-				break
-			}
-		}
-		ms = append(ms, f)
-	}
-
-	for _, f := range t.Methods().Slice() {
-		f.Sym.SetUniq(false)
-	}
-
-	ms = append(ms, t.Methods().Slice()...)
-	sort.Sort(types.MethodsByName(ms))
-	t.SetAllMethods(ms)
+	types.CalcMethods(t)
 }
 
 // adddot1 returns the number of fields or methods named s at depth d in Type t.
@@ -410,7 +362,7 @@ func Assignop1(src, dst *types.Type) (ir.Op, string) {
 			why = fmt.Sprintf(":\n\t%v does not implement %v (%v method is marked 'nointerface')", src, dst, missing.Sym)
 		} else if have != nil && have.Sym == missing.Sym {
 			why = fmt.Sprintf(":\n\t%v does not implement %v (wrong type for %v method)\n"+
-				"\t\thave %v%S\n\t\twant %v%S", src, dst, missing.Sym, have.Sym, have.Type, missing.Sym, missing.Type)
+				"\t\thave %v%S\n\t\twant %v%S\n\t\t%s", src, dst, missing.Sym, have.Sym, have.Type, missing.Sym, missing.Type, typeDiff(have.Type, missing.Type))
 		} else if ptr != 0 {
 			why = fmt.Sprintf(":\n\t%v does not implement %v (%v method has pointer receiver)", src, dst, missing.Sym)
 		} else if have != nil {
@@ -637,72 +589,6 @@ func dotpath(s *types.Sym, t *types.Type, save **types.Field, ignorecase bool) (
 	}
 }
 
-func expand0(t *types.Type) {
-	u := t
-	if u.IsPtr() {
-		u = u.Elem()
-	}
-
-	if u.IsInterface() {
-		for _, f := range u.AllMethods().Slice() {
-			if f.Sym.Uniq() {
-				continue
-			}
-			f.Sym.SetUniq(true)
-			slist = append(slist, symlink{field: f})
-		}
-
-		return
-	}
-
-	u = types.ReceiverBaseType(t)
-	if u != nil {
-		for _, f := range u.Methods().Slice() {
-			if f.Sym.Uniq() {
-				continue
-			}
-			f.Sym.SetUniq(true)
-			slist = append(slist, symlink{field: f})
-		}
-	}
-}
-
-func expand1(t *types.Type, top bool) {
-	if t.Recur() {
-		return
-	}
-	t.SetRecur(true)
-
-	if !top {
-		expand0(t)
-	}
-
-	u := t
-	if u.IsPtr() {
-		u = u.Elem()
-	}
-
-	if u.IsStruct() || u.IsInterface() {
-		var fields *types.Fields
-		if u.IsStruct() {
-			fields = u.Fields()
-		} else {
-			fields = u.AllMethods()
-		}
-		for _, f := range fields.Slice() {
-			if f.Embedded == 0 {
-				continue
-			}
-			if f.Sym == nil {
-				continue
-			}
-			expand1(f.Type, false)
-		}
-	}
-
-	t.SetRecur(false)
-}
-
 func ifacelookdot(s *types.Sym, t *types.Type, ignorecase bool) (m *types.Field, followptr bool) {
 	if t == nil {
 		return nil, false
@@ -826,6 +712,13 @@ func implements(t, iface *types.Type, m, samename **types.Field, ptr *int) bool
 	return true
 }
 
+// typeDiff describes the first difference between the types t1 and
+// t2, which are not identical, as in "parameter 0: string vs int".
+func typeDiff(t1, t2 *types.Type) string {
+	_, why := types.IdenticalReason(t1, t2)
+	return why
+}
+
 func isptrto(t *types.Type, et types.Kind) bool {
 	if t == nil {
 		return false
@@ -890,17 +783,6 @@ func lookdot0(s *types.Sym, t *types.Type, save **types.Field, ignorecase bool)
 	return c
 }
 
-var slist []symlink
-
-// Code to help generate trampoline functions for methods on embedded
-// types. These are approx the same as the corresponding AddImplicitDots
-// routines except that they expect to be called with unique tasks and
-// they return the actual methods.
-
-type symlink struct {
-	field *types.Field
-}
-
 // TypesOf converts a list of nodes to a list
 // of types of those nodes.
 func TypesOf(x []ir.Node) []*types.Type {
@@ -940,24 +822,61 @@ func InstTypeName(name string, targs []*types.Type) string {
 // makeInstName1 returns the name of the generic function instantiated with the
 // given types, which can have type params or shapes, or be concrete types. name is
 // the name of the generic function or method.
+//
+// If name has brackets, the bracket list takes as many of targs as it has
+// elements. Any type arguments left over are those of a method's own type
+// parameters (see types.Field.IsGenericMethod), and are appended in a
+// bracket list of their own, as in '(*genType[int]).methodName[string]'.
 func makeInstName1(name string, targs []*types.Type, hasBrackets bool) string {
 	b := bytes.NewBufferString("")
 	i := strings.Index(name, "[")
 	assert(hasBrackets == (i >= 0))
-	if i >= 0 {
-		b.WriteString(name[0:i])
-	} else {
+	if i < 0 {
 		b.WriteString(name)
+		addTargs(b, targs)
+		return b.String()
 	}
-	addTargs(b, targs)
-	if i >= 0 {
-		i2 := strings.LastIndex(name[i:], "]")
-		assert(i2 >= 0)
-		b.WriteString(name[i+i2+1:])
+	b.WriteString(name[0:i])
+	i2 := strings.LastIndex(name[i:], "]")
+	assert(i2 >= 0)
+	n := bracketListLen(name[i+1 : i+i2])
+	if n > len(targs) {
+		n = len(targs)
+	}
+	addTargs(b, targs[:n])
+	b.WriteString(name[i+i2+1:])
+	if n < len(targs) {
+		addTargs(b, targs[n:])
 	}
 	return b.String()
 }
 
+// bracketListLen returns the number of comma-separated elements of the
+// bracket list s, not counting commas nested in brackets, parentheses,
+// braces or quoted strings.
+func bracketListLen(s string) int {
+	n, depth := 1, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				n++
+			}
+		case '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return n
+}
+
 // MakeFuncInstSym makes the unique sym for a stenciled generic function or method,
 // based on the name of the function gf and the targs. It replaces any
 // existing bracket type list in the name. MakeInstName asserts that gf has
@@ -988,16 +907,28 @@ func MakeFuncInstSym(gf *types.Sym, targs []*types.Type, isMethodNode, hasBracke
 func MakeDictSym(gf *types.Sym, targs []*types.Type, hasBrackets bool) *types.Sym {
 	for _, targ := range targs {
 		if targ.HasTParam() {
-			fmt.Printf("FUNCTION %s\n", gf.Name)
-			for _, targ := range targs {
-				fmt.Printf("  PARAM %+v\n", targ)
-			}
+			fmt.Printf("FUNCTION %s\n  PARAM %s\n", gf.Name, types.JoinTypes(targs, "\n  PARAM ", '+'))
 			panic("dictionary should always have concrete type args")
 		}
 	}
 	name := makeInstName1(gf.Name, targs, hasBrackets)
 	name = fmt.Sprintf("%s.%s", objabi.GlobalDictPrefix, name)
-	return gf.Pkg.Lookup(name)
+	sym := gf.Pkg.Lookup(name)
+	dictTArgs[sym] = targs
+	return sym
+}
+
+// dictTArgs maps the dictionary symbols made by MakeDictSym to their
+// type arguments.
+var dictTArgs = make(map[*types.Sym][]*types.Type)
+
+// DictTArgs returns the type arguments of the instantiation whose
+// dictionary is dict, or nil if dict is not a dictionary symbol. Unlike
+// the shapes in the name of the shape-based instantiation the
+// dictionary is passed to, these are the type arguments the user
+// wrote or that were inferred, so messages should prefer them.
+func DictTArgs(dict *types.Sym) []*types.Type {
+	return dictTArgs[dict]
 }
 
 func assert(p bool) {
@@ -1103,17 +1034,19 @@ func (ts *Tsubster) typ1(t *types.Type) *types.Type {
 		// type as well. We do this first, so we can look up if we've
 		// already seen this type during this substitution or other
 		// definitions/substitutions.
+		//
+		// In order to deal with recursive generic types, create a TFORW
+		// type initially and set the Def field of its sym, so it can be
+		// found if this type appears recursively within the type.
 		genName := genericTypeName(t.Sym())
-		newsym = t.Sym().Pkg.Lookup(InstTypeName(genName, neededTargs))
-		if newsym.Def != nil {
+		var created bool
+		newsym, created = t.Sym().Pkg.DefineOnce(InstTypeName(genName, neededTargs), func(s *types.Sym) {
+			forw = NewIncompleteNamedType(t.Pos(), s)
+		})
+		if !created {
 			// We've already created this instantiated defined type.
 			return newsym.Def.Type()
 		}
-
-		// In order to deal with recursive generic types, create a TFORW
-		// type initially and set the Def field of its sym, so it can be
-		// found if this type appears recursively within the type.
-		forw = NewIncompleteNamedType(t.Pos(), newsym)
 		//println("Creating new type by sub", newsym.Name, forw.HasTParam())
 		forw.SetRParams(neededTargs)
 		// Copy the OrigSym from the re-instantiated type (which is the sym of
@@ -1229,13 +1162,10 @@ func (ts *Tsubster) typ1(t *types.Type) *types.Type {
 		types.TUINTPTR, types.TBOOL, types.TSTRING, types.TFLOAT32, types.TFLOAT64, types.TCOMPLEX64, types.TCOMPLEX128, types.TUNSAFEPTR:
 		newt = t.Underlying()
 	case types.TUNION:
-		nt := t.NumTerms()
-		newterms := make([]*types.Type, nt)
-		tildes := make([]bool, nt)
+		terms, tildes := t.SourceTerms()
+		newterms := make([]*types.Type, len(terms))
 		changed := false
-		for i := 0; i < nt; i++ {
-			term, tilde := t.Term(i)
-			tildes[i] = tilde
+		for i, term := range terms {
 			newterms[i] = ts.typ1(term)
 			if newterms[i] != term {
 				changed = true
@@ -1460,3 +1390,45 @@ func Shapify(t *types.Type, index int) *types.Type {
 }
 
 var shapeMap map[int]map[*types.Type]*types.Type
+
+// StencilShape is like Shapify, but returns a shape type that is used
+// only for t, so that the instantiations of a generic function marked
+// //go:stencil with different type arguments are not shared. If t is
+// an unnamed non-pointer type, its shape is the one Shapify returns.
+func StencilShape(t *types.Type, index int) *types.Type {
+	assert(!t.IsShape())
+	if t.Sym() == nil && !t.IsPtr() {
+		return Shapify(t, index)
+	}
+
+	if stencilShapeMap == nil {
+		stencilShapeMap = map[int]map[*types.Type]*types.Type{}
+	}
+	submap := stencilShapeMap[index]
+	if submap == nil {
+		submap = map[*types.Type]*types.Type{}
+		stencilShapeMap[index] = submap
+	}
+	if s := submap[t]; s != nil {
+		return s
+	}
+
+	nm := fmt.Sprintf("%s_%d", t.LinkString(), index)
+	sym := types.ShapePkg.Lookup(nm)
+	if sym.Def != nil {
+		submap[t] = sym.Def.Type()
+		return submap[t]
+	}
+	name := ir.NewDeclNameAt(t.Pos(), ir.OTYPE, sym)
+	s := types.NewNamed(name)
+	sym.Def = name
+	s.SetUnderlying(t.Underlying())
+	s.SetIsShape(true)
+	s.SetHasShape(true)
+	name.SetType(s)
+	name.SetTypecheck(1)
+	submap[t] = s
+	return s
+}
+
+var stencilShapeMap map[int]map[*types.Type]*types.Type
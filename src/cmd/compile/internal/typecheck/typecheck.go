@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/types"
 	"cmd/internal/src"
@@ -335,7 +336,7 @@ func typecheck(n ir.Node, top int) (res ir.Node) {
 						return n
 					}
 				}
-				base.ErrorfAt(n.Pos(), "invalid recursive type alias %v%s", n, cycleTrace(cycle))
+				base.ErrorfAt(n.Pos(), errcode.InvalidDeclCycle, "invalid recursive type alias %v%s", n, cycleTrace(cycle))
 			}
 
 		case ir.OLITERAL:
@@ -343,7 +344,7 @@ func typecheck(n ir.Node, top int) (res ir.Node) {
 				base.Errorf("%v is not a type", n)
 				break
 			}
-			base.ErrorfAt(n.Pos(), "constant definition loop%s", cycleTrace(cycleFor(n)))
+			base.ErrorfAt(n.Pos(), errcode.InvalidDeclCycle, "constant definition loop%s", cycleTrace(cycleFor(n)))
 		}
 
 		if base.Errors() == 0 {
@@ -494,7 +495,7 @@ func typecheck1(n ir.Node, top int) ir.Node {
 		if !n.Diag() {
 			// Note: adderrorname looks for this string and
 			// adds context about the outer expression
-			base.ErrorfAt(n.Pos(), "undefined: %v", n.Sym())
+			base.MsgUndefined.ErrorAt(n.Pos(), base.MsgArgs{"name": n.Sym()})
 			n.SetDiag(true)
 		}
 		n.SetType(nil)
@@ -1731,7 +1732,7 @@ func CheckMapKeys() {
 	for _, n := range mapqueue {
 		k := n.Type().MapType().Key
 		if !k.Broke() && !types.IsComparable(k) {
-			base.ErrorfAt(n.Pos(), "invalid map key type %v", k)
+			base.ErrorfAt(n.Pos(), errcode.IncomparableMapKey, "invalid map key type %v", k)
 		}
 	}
 	mapqueue = nil
@@ -1825,7 +1826,7 @@ func typecheckdef(n *ir.Name) {
 		n.Defn = nil
 		if e == nil {
 			ir.Dump("typecheckdef nil defn", n)
-			base.ErrorfAt(n.Pos(), "xxx")
+			base.ErrorfAt(n.Pos(), 0, "xxx")
 		}
 
 		e = Expr(e)
@@ -1835,9 +1836,9 @@ func typecheckdef(n *ir.Name) {
 		if !ir.IsConstNode(e) {
 			if !e.Diag() {
 				if e.Op() == ir.ONIL {
-					base.ErrorfAt(n.Pos(), "const initializer cannot be nil")
+					base.ErrorfAt(n.Pos(), errcode.InvalidConstInit, "const initializer cannot be nil")
 				} else {
-					base.ErrorfAt(n.Pos(), "const initializer %v is not a constant", e)
+					base.ErrorfAt(n.Pos(), errcode.InvalidConstInit, "const initializer %v is not a constant", e)
 				}
 				e.SetDiag(true)
 			}
@@ -1847,12 +1848,12 @@ func typecheckdef(n *ir.Name) {
 		t := n.Type()
 		if t != nil {
 			if !ir.OKForConst[t.Kind()] {
-				base.ErrorfAt(n.Pos(), "invalid constant type %v", t)
+				base.ErrorfAt(n.Pos(), errcode.InvalidConstType, "invalid constant type %v", t)
 				goto ret
 			}
 
 			if !e.Type().IsUntyped() && !types.Identical(t, e.Type()) {
-				base.ErrorfAt(n.Pos(), "cannot use %L as type %v in const initializer", e, t)
+				base.ErrorfAt(n.Pos(), errcode.IncompatibleAssign, "cannot use %L as type %v in const initializer", e, t)
 				goto ret
 			}
 
@@ -2178,10 +2179,10 @@ func CheckUnused(fn *ir.Func) {
 			if defn.Used {
 				continue
 			}
-			base.ErrorfAt(defn.Tag.Pos(), "%v declared but not used", ln.Sym())
+			base.MsgDeclaredNotUsed.ErrorAt(defn.Tag.Pos(), base.MsgArgs{"name": ln.Sym()})
 			defn.Used = true // suppress repeats
 		} else {
-			base.ErrorfAt(ln.Pos(), "%v declared but not used", ln.Sym())
+			base.MsgDeclaredNotUsed.ErrorAt(ln.Pos(), base.MsgArgs{"name": ln.Sym()})
 		}
 	}
 }
@@ -2191,7 +2192,7 @@ func CheckReturn(fn *ir.Func) {
 	if fn.Type() != nil && fn.Type().NumResults() != 0 && len(fn.Body) != 0 {
 		markBreak(fn)
 		if !isTermNodes(fn.Body) {
-			base.ErrorfAt(fn.Endlineno, "missing return at end of function")
+			base.MsgMissingReturnAtEnd.ErrorAt(fn.Endlineno, nil)
 		}
 	}
 }
@@ -216,7 +216,7 @@ func DeclareUniverse() {
 	// that we silently skip symbols that are already declared in the
 	// package block rather than emitting a redeclared symbol error.
 
-	for _, s := range types.BuiltinPkg.Syms {
+	for _, s := range types.BuiltinPkg.SortedSyms() {
 		if s.Def == nil {
 			continue
 		}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package typefmt implements the layout of struct and interface
+// types shared by the type checker (types2) and the backend type
+// representation (types), so that both spell the body of a composite
+// type by the same rules.
+package typefmt
+
+// A Style describes how the element list of a composite type is
+// laid out.
+type Style struct {
+	// Pad separates the keyword from the opening brace and pads
+	// a non-empty element list inside the braces, as in
+	// "struct { x int }" rather than "struct{x int}".
+	Pad bool
+
+	// Sep separates consecutive elements.
+	Sep string
+}
+
+var (
+	// Go is the style of types2 and go/types: "struct{x int; y int}".
+	Go = Style{Sep: "; "}
+
+	// Compiler is the style of the backend types package:
+	// "struct { x int; y int }".
+	Compiler = Style{Pad: true, Sep: "; "}
+)
+
+// Composite writes the keyword ("struct" or "interface") followed by
+// the braced list of n elements in style s. Text is written with
+// write, and the i'th element with elem.
+func Composite(s Style, keyword string, n int, write func(string), elem func(i int)) {
+	write(keyword)
+	if s.Pad {
+		write(" ")
+	}
+	write("{")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			write(s.Sep)
+		} else if s.Pad {
+			write(" ")
+		}
+		elem(i)
+	}
+	if n > 0 && s.Pad {
+		write(" ")
+	}
+	write("}")
+}
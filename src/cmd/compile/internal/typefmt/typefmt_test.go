@@ -0,0 +1,34 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typefmt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComposite(t *testing.T) {
+	tests := []struct {
+		style   Style
+		keyword string
+		elems   []string
+		want    string
+	}{
+		{Go, "struct", nil, "struct{}"},
+		{Go, "struct", []string{"x int"}, "struct{x int}"},
+		{Go, "interface", []string{"M()", "~int"}, "interface{M(); ~int}"},
+		{Compiler, "struct", nil, "struct {}"},
+		{Compiler, "struct", []string{"x int"}, "struct { x int }"},
+		{Compiler, "interface", []string{"M()", "~int"}, "interface { M(); ~int }"},
+		{Style{Sep: ";"}, "struct", []string{"x#int", "y#int"}, "struct{x#int;y#int}"},
+	}
+	for _, test := range tests {
+		var b strings.Builder
+		Composite(test.style, test.keyword, len(test.elems), func(s string) { b.WriteString(s) }, func(i int) { b.WriteString(test.elems[i]) })
+		if got := b.String(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// The compiler creates many small Type, Field and Sym objects, which
+// live until the end of the compilation. Rather than allocate them one
+// at a time, allocType, allocField and allocSym hand them out from
+// chunks, which cuts the number of allocations the garbage collector
+// must track. A chunk is freed when none of its objects is in use.
+
+// allocChunk is the number of objects in a chunk.
+const allocChunk = 128
+
+var typeAlloc struct {
+	sync.Mutex
+	types  []Type
+	fields []Field
+	syms   []Sym
+	stats  AllocStats
+}
+
+// AllocStats holds statistics about the allocation of types, fields
+// and symbols.
+type AllocStats struct {
+	Types, Fields, Syms int64 // objects allocated
+	Chunks              int64 // chunks allocated
+	Bytes               int64 // total size of the chunks
+}
+
+// ReadAllocStats returns the allocation statistics so far.
+func ReadAllocStats() AllocStats {
+	typeAlloc.Lock()
+	defer typeAlloc.Unlock()
+	return typeAlloc.stats
+}
+
+// DumpAllocStats prints the allocation statistics, for -d=typealloc.
+func DumpAllocStats() {
+	st := ReadAllocStats()
+	fmt.Printf("types:  %d (%d bytes each)\n", st.Types, unsafe.Sizeof(Type{}))
+	fmt.Printf("fields: %d (%d bytes each)\n", st.Fields, unsafe.Sizeof(Field{}))
+	fmt.Printf("syms:   %d (%d bytes each)\n", st.Syms, unsafe.Sizeof(Sym{}))
+	fmt.Printf("chunks: %d (%d bytes)\n", st.Chunks, st.Bytes)
+}
+
+// allocType returns a new zero Type.
+func allocType() *Type {
+	typeAlloc.Lock()
+	if len(typeAlloc.types) == 0 {
+		typeAlloc.types = make([]Type, allocChunk)
+		typeAlloc.stats.Chunks++
+		typeAlloc.stats.Bytes += allocChunk * int64(unsafe.Sizeof(Type{}))
+	}
+	t := &typeAlloc.types[0]
+	typeAlloc.types = typeAlloc.types[1:]
+	typeAlloc.stats.Types++
+	typeAlloc.Unlock()
+	return t
+}
+
+// allocField returns a new zero Field.
+func allocField() *Field {
+	typeAlloc.Lock()
+	if len(typeAlloc.fields) == 0 {
+		typeAlloc.fields = make([]Field, allocChunk)
+		typeAlloc.stats.Chunks++
+		typeAlloc.stats.Bytes += allocChunk * int64(unsafe.Sizeof(Field{}))
+	}
+	f := &typeAlloc.fields[0]
+	typeAlloc.fields = typeAlloc.fields[1:]
+	typeAlloc.stats.Fields++
+	typeAlloc.Unlock()
+	return f
+}
+
+// allocSym returns a new zero Sym.
+func allocSym() *Sym {
+	typeAlloc.Lock()
+	if len(typeAlloc.syms) == 0 {
+		typeAlloc.syms = make([]Sym, allocChunk)
+		typeAlloc.stats.Chunks++
+		typeAlloc.stats.Bytes += allocChunk * int64(unsafe.Sizeof(Sym{}))
+	}
+	s := &typeAlloc.syms[0]
+	typeAlloc.syms = typeAlloc.syms[1:]
+	typeAlloc.stats.Syms++
+	typeAlloc.Unlock()
+	return s
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"testing"
+
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestAllocStats(t *testing.T) {
+	pkg := types.NewPkg("alloc", "alloc")
+	before := types.ReadAllocStats()
+	f := types.NewField(src.NoXPos, pkg.Lookup("f"), types.Types[types.TINT])
+	types.NewStruct(pkg, []*types.Field{f, f.Copy()})
+	after := types.ReadAllocStats()
+
+	if got := after.Types - before.Types; got != 1 {
+		t.Errorf("allocated %d types, want 1", got)
+	}
+	if got := after.Fields - before.Fields; got != 2 {
+		t.Errorf("allocated %d fields, want 2", got)
+	}
+	if got := after.Syms - before.Syms; got != 1 {
+		t.Errorf("allocated %d syms, want 1", got)
+	}
+	if after.Chunks == 0 || after.Bytes == 0 {
+		t.Errorf("stats report no chunks: %+v", after)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import "sync"
+
+// canonTable interns unnamed types, keyed by their structural hash.
+var canonTable struct {
+	sync.Mutex
+	byHash map[uint64][]*Type // canonical types
+	canon  map[*Type]*Type    // types already canonicalized
+	tuples map[[2]*Type]*Type // see NewTuple
+}
+
+// Canonical returns the canonical type of the types identical to t:
+// the first such type passed to Canonical. Thus, for two types t1 and
+// t2 that Canonical interns, IdenticalStrict(t1, t2) reports whether
+// Canonical(t1) == Canonical(t2), except that byte and rune are
+// distinct from uint8 and int32, so that the canonical type is
+// spelled as t is.
+//
+// Only unnamed pointer, slice, array, channel, map, struct, function
+// and interface types are interned; Canonical returns other types, as
+// well as function argument structs, broken types, types the compiler
+// generates without algorithms and interfaces whose method sets are not
+// yet known, unchanged.
+//
+// NewSlice interns its element type, and NewSignature the types of
+// the parameters and results, so that identical element and parameter
+// types built in different places share one type.
+//
+// Identity ignores the names of function parameters and receivers, so
+// the canonical type of a function type may differ from it in those.
+// Use canonical types where only type identity matters, for example as
+// map keys, and not in place of the types of declared functions.
+func Canonical(t *Type) *Type {
+	if t == nil || t.sym != nil || t.Broke() {
+		return t
+	}
+	switch t.kind {
+	case TPTR, TSLICE, TARRAY, TCHAN, TMAP, TSTRUCT, TFUNC, TINTER:
+	default:
+		return t
+	}
+	if t.IsFuncArgStruct() {
+		return t
+	}
+
+	canonTable.Lock()
+	defer canonTable.Unlock()
+	if c, ok := canonTable.canon[t]; ok {
+		return c
+	}
+	h, ok := structuralHash(fnvOffset, t, nil)
+	if !ok {
+		return t
+	}
+	if canonTable.byHash == nil {
+		canonTable.byHash = make(map[uint64][]*Type)
+		canonTable.canon = make(map[*Type]*Type)
+	}
+	c := t
+	for _, u := range canonTable.byHash[h] {
+		if identical(t, u, identStrict|identKeepAliases, nil, nil) {
+			c = u
+			break
+		}
+	}
+	if c == t {
+		canonTable.byHash[h] = append(canonTable.byHash[h], t)
+	}
+	canonTable.canon[t] = c
+	return c
+}
+
+// FNV-1a parameters, used by structuralHash.
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+func hashInt(h uint64, x int64) uint64 {
+	for i := 0; i < 8; i++ {
+		h ^= uint64(byte(x))
+		h *= fnvPrime
+		x >>= 8
+	}
+	return h
+}
+
+func hashString(h uint64, s string) uint64 {
+	h = hashInt(h, int64(len(s)))
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime
+	}
+	return h
+}
+
+func hashSym(h uint64, s *Sym) uint64 {
+	if s == nil {
+		return hashInt(h, -1)
+	}
+	if s.Pkg != nil {
+		h = hashString(h, s.Pkg.Path)
+	}
+	return hashString(h, s.Name)
+}
+
+// structuralHash mixes a hash of t into h and returns the result. The
+// hashes of types that Canonical considers identical are equal: named
+// types are hashed by name, and unnamed types by structure. It
+// reports false if t contains an interface whose method set is not
+// yet known, so that identity cannot yet be decided, or an unnamed
+// type that contains itself. Outer lists the unnamed types that
+// contain t.
+func structuralHash(h uint64, t *Type, outer []*Type) (uint64, bool) {
+	if t == nil {
+		return hashInt(h, -1), true
+	}
+	if t.Noalg() {
+		// Identity does not tell these from the types they copy.
+		return h, false
+	}
+	h = hashInt(h, int64(t.kind))
+	if t.sym != nil {
+		h = hashSym(h, t.sym)
+		return hashInt(h, int64(t.vargen)), true
+	}
+	for _, o := range outer {
+		if o == t {
+			return h, false
+		}
+	}
+	outer = append(outer, t)
+
+	ok := true
+	mix := func(t *Type) {
+		if ok {
+			h, ok = structuralHash(h, t, outer)
+		}
+	}
+	switch t.kind {
+	case TPTR, TSLICE:
+		mix(t.Elem())
+
+	case TARRAY:
+		h = hashInt(h, t.NumElem())
+		mix(t.Elem())
+
+	case TCHAN:
+		h = hashInt(h, int64(t.ChanDir()))
+		mix(t.Elem())
+
+	case TMAP:
+		mix(t.Key())
+		mix(t.Elem())
+
+	case TSTRUCT:
+		for _, f := range t.FieldSlice() {
+			h = hashSym(h, f.Sym)
+			h = hashInt(h, int64(f.Embedded))
+			h = hashString(h, f.Note)
+			mix(f.Type)
+		}
+
+	case TFUNC:
+		// Like identity, ignore receivers and parameter names.
+		for _, params := range ParamsResults {
+			fs := params(t).FieldSlice()
+			h = hashInt(h, int64(len(fs)))
+			for _, f := range fs {
+				if f.IsDDD() {
+					h = hashInt(h, 1)
+				}
+				mix(f.Type)
+			}
+		}
+
+	case TINTER:
+		if t.AllMethods().Len() == 0 && t.Methods().Len() != 0 {
+			return h, false
+		}
+		for _, f := range t.AllMethods().Slice() {
+			h = hashSym(h, f.Sym)
+			mix(f.Type)
+		}
+
+	case TUNION:
+		// Terms are in canonical order; see NewUnion.
+		for i := 0; i < t.NumTerms(); i++ {
+			term, tilde := t.Term(i)
+			if tilde {
+				h = hashInt(h, 1)
+			}
+			mix(term)
+		}
+	}
+	return h, ok
+}
+
+// canonicalTuple returns the tuple type of t1 and t2, which NewTuple
+// creates only once for each pair of canonical types.
+func canonicalTuple(t1, t2 *Type) *Type {
+	t1, t2 = Canonical(t1), Canonical(t2)
+	key := [2]*Type{t1, t2}
+	canonTable.Lock()
+	defer canonTable.Unlock()
+	if t := canonTable.tuples[key]; t != nil {
+		return t
+	}
+	t := newTuple(t1, t2)
+	if canonTable.tuples == nil {
+		canonTable.tuples = make(map[[2]*Type]*Type)
+	}
+	canonTable.tuples[key] = t
+	return t
+}
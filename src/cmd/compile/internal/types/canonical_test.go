@@ -0,0 +1,100 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"testing"
+
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestCanonical(t *testing.T) {
+	pkg := types.NewPkg("k", "k")
+	i64, str := types.Types[types.TINT64], types.Types[types.TSTRING]
+	st := func(tag string) *types.Type {
+		f := types.NewField(src.NoXPos, pkg.Lookup("x"), types.NewSlice(i64))
+		f.Note = tag
+		return types.NewStruct(pkg, []*types.Field{f})
+	}
+	fn := func(name string, ddd bool) *types.Type {
+		p := types.NewField(src.NoXPos, pkg.Lookup(name), types.NewSlice(str))
+		p.SetIsDDD(ddd)
+		return types.NewSignature(pkg, nil, nil, []*types.Field{p}, nil)
+	}
+
+	a := st("")
+	tests := []struct {
+		t1, t2 *types.Type
+		same   bool
+	}{
+		{a, st(""), true},
+		{a, st(`json:"x"`), false},
+		{types.NewMap(str, a), types.NewMap(str, st("")), true},
+		{types.NewArray(a, 2), types.NewArray(st(""), 3), false},
+		{fn("a", false), fn("b", false), true},
+		{fn("a", false), fn("a", true), false},
+		{types.NewSlice(types.ByteType), types.NewSlice(types.Types[types.TUINT8]), false},
+		{types.NewChan(a, types.Crecv), types.NewChan(st(""), types.Csend), false},
+	}
+	for _, test := range tests {
+		c1, c2 := types.Canonical(test.t1), types.Canonical(test.t2)
+		if !types.IdenticalStrict(c1, test.t1) || !types.IdenticalStrict(c2, test.t2) {
+			t.Errorf("Canonical(%v) = %v, Canonical(%v) = %v: not identical", test.t1, c1, test.t2, c2)
+		}
+		if (c1 == c2) != test.same {
+			t.Errorf("Canonical(%v) == Canonical(%v) is %v, want %v", test.t1, test.t2, c1 == c2, test.same)
+		}
+	}
+
+	if c := types.Canonical(st("")); c != a {
+		t.Errorf("Canonical(%v) is not the first type interned", c)
+	}
+	for _, u := range []*types.Type{i64, types.ByteType, nil} {
+		if c := types.Canonical(u); c != u {
+			t.Errorf("Canonical(%v) = %v, want unchanged", u, c)
+		}
+	}
+}
+
+// TestCanonicalConstructors checks that the types of slices, tuples
+// and function parameters are interned as they are created.
+func TestCanonicalConstructors(t *testing.T) {
+	pkg := types.NewPkg("kc", "kc")
+	st := func() *types.Type {
+		return types.NewStruct(pkg, []*types.Field{
+			types.NewField(src.NoXPos, pkg.Lookup("x"), types.Types[types.TINT16]),
+		})
+	}
+	a, b := st(), st()
+
+	if s1, s2 := types.NewSlice(a), types.NewSlice(b); s1 != s2 {
+		t.Errorf("NewSlice of identical %v and %v: %p != %p", a, b, s1, s2)
+	}
+	if t1, t2 := types.NewTuple(a, types.TypeMem), types.NewTuple(b, types.TypeMem); t1 != t2 {
+		t.Errorf("NewTuple of identical %v and %v: %p != %p", a, b, t1, t2)
+	}
+	if t1, t2 := types.NewTuple(a, types.TypeMem), types.NewTuple(types.TypeMem, a); t1 == t2 {
+		t.Errorf("NewTuple(%v, mem) == NewTuple(mem, %v)", a, a)
+	}
+
+	sig := func(p *types.Type) *types.Type {
+		return types.NewSignature(pkg, nil, nil, []*types.Field{
+			types.NewField(src.NoXPos, pkg.Lookup("p"), p),
+		}, nil)
+	}
+	p1, p2 := sig(types.NewPtr(a)).Params().Field(0), sig(types.NewPtr(b)).Params().Field(0)
+	if p1.Type != p2.Type {
+		t.Errorf("parameters of identical types %v and %v: %p != %p", p1.Type, p2.Type, p1.Type, p2.Type)
+	}
+
+	// Types that contain themselves without a name are left alone.
+	next := types.NewField(src.NoXPos, pkg.Lookup("next"), nil)
+	list := types.NewStruct(pkg, []*types.Field{next})
+	next.Type = types.NewPtr(list)
+	if c := types.Canonical(list); c != list {
+		t.Errorf("Canonical(%v) = %v, want unchanged", list, c)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+// Metrics summarizes the complexity of a type, for use by heuristics
+// such as inlining, escape analysis and stenciling. See Complexity.
+type Metrics struct {
+	// Nodes is the number of distinct types that make up the type,
+	// including the type itself, as visited by Walk.
+	Nodes int
+
+	// PtrDepth is the largest number of pointer types on any path
+	// from the type to one of the types that make it up. Maps,
+	// slices, channels and functions do not count as pointers.
+	// A recursive type's pointers are counted once.
+	PtrDepth int
+
+	// Interfaces is the number of distinct interface types among
+	// the types that make up the type.
+	Interfaces int
+
+	// Size is the class of the type's size.
+	Size SizeClass
+}
+
+// A SizeClass is a coarse classification of the size of a type.
+type SizeClass uint8
+
+const (
+	SizeUnknown SizeClass = iota // size is not available, e.g. t has type parameters
+	SizeZero                     // zero-sized
+	SizeWord                     // at most one word
+	SizeSmall                    // at most four words
+	SizeLarge                    // larger than four words
+)
+
+// Complexity returns metrics describing the complexity of t.
+//
+// Complexity does not compute t's size if doing so is unsafe: if
+// CalcSizeDisabled is set, or if t has type parameters or is untyped,
+// and its size has not already been calculated, the size class is
+// SizeUnknown.
+func Complexity(t *Type) Metrics {
+	var m Metrics
+	if t == nil {
+		return m
+	}
+	Walk(t, func(t *Type) bool {
+		m.Nodes++
+		if t.IsInterface() {
+			m.Interfaces++
+		}
+		return true
+	})
+	m.PtrDepth = ptrDepth(t, make(map[*Type]int))
+	m.Size = sizeClass(t)
+	return m
+}
+
+// onPath marks a type in ptrDepth's memo whose depth is being
+// computed further up the stack.
+const onPath = -1
+
+// ptrDepth returns the PtrDepth of t. memo records the depths of the
+// types seen so far.
+func ptrDepth(t *Type, memo map[*Type]int) int {
+	if t == nil {
+		return 0
+	}
+	if d, ok := memo[t]; ok {
+		if d == onPath {
+			// t refers to itself; its pointers are counted
+			// by the outer call.
+			return 0
+		}
+		return d
+	}
+	memo[t] = onPath
+
+	d := 0
+	max := func(u *Type) {
+		if ud := ptrDepth(u, memo); ud > d {
+			d = ud
+		}
+	}
+	fields := func(s *Type) {
+		for _, f := range s.FieldSlice() {
+			max(f.Type)
+		}
+	}
+	switch t.Kind() {
+	case TPTR:
+		d = 1 + ptrDepth(t.Elem(), memo)
+	case TARRAY, TSLICE, TCHAN:
+		max(t.Elem())
+	case TMAP:
+		max(t.Key())
+		max(t.Elem())
+	case TSTRUCT:
+		fields(t)
+	case TFUNC:
+		fields(t.Recvs())
+		fields(t.Params())
+		fields(t.Results())
+	case TINTER:
+		for _, f := range t.Methods().Slice() {
+			max(f.Type)
+		}
+	}
+
+	memo[t] = d
+	return d
+}
+
+// sizeClass returns the SizeClass of t.
+func sizeClass(t *Type) SizeClass {
+	if !t.widthCalculated() {
+		if PtrSize == 0 || CalcSizeDisabled || t.HasTParam() || t.IsUntyped() || t.Kind() == TFORW {
+			return SizeUnknown
+		}
+	}
+	switch w := t.Size(); {
+	case w == 0:
+		return SizeZero
+	case w <= int64(PtrSize):
+		return SizeWord
+	case w <= 4*int64(PtrSize):
+		return SizeSmall
+	}
+	return SizeLarge
+}
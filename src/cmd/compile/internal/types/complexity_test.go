@@ -0,0 +1,54 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"testing"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestComplexity(t *testing.T) {
+	pkg := types.NewPkg("c", "c")
+	i64, str := types.Types[types.TINT64], types.Types[types.TSTRING]
+
+	// type List struct { next *List; v interface{} }
+	obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup("List"))
+	list := types.NewNamed(obj)
+	obj.SetType(list)
+	list.SetUnderlying(types.NewStruct(pkg, []*types.Field{
+		types.NewField(src.NoXPos, pkg.Lookup("next"), types.NewPtr(list)),
+		types.NewField(src.NoXPos, pkg.Lookup("v"), types.Types[types.TINTER]),
+	}))
+
+	tests := []struct {
+		typ  *types.Type
+		want types.Metrics
+	}{
+		{i64, types.Metrics{Nodes: 1, Size: types.SizeWord}},
+		{types.NewStruct(pkg, nil), types.Metrics{Nodes: 1, Size: types.SizeZero}},
+		{types.NewPtr(types.NewPtr(i64)), types.Metrics{Nodes: 3, PtrDepth: 2, Size: types.SizeWord}},
+		{types.NewMap(str, types.NewPtr(i64)), types.Metrics{Nodes: 4, PtrDepth: 1, Size: types.SizeWord}},
+		{types.NewArray(str, 2), types.Metrics{Nodes: 2, Size: types.SizeSmall}},
+		{types.NewArray(str, 3), types.Metrics{Nodes: 2, Size: types.SizeLarge}},
+		{list, types.Metrics{Nodes: 3, PtrDepth: 1, Interfaces: 1, Size: types.SizeSmall}},
+		{types.NewPtr(list), types.Metrics{Nodes: 3, PtrDepth: 1, Interfaces: 1, Size: types.SizeWord}},
+		{nil, types.Metrics{}},
+	}
+	for _, test := range tests {
+		if got := types.Complexity(test.typ); got != test.want {
+			t.Errorf("Complexity(%v) = %+v, want %+v", test.typ, got, test.want)
+		}
+	}
+
+	// The size of a type parameter is unknown.
+	tobj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup("T"))
+	tp := types.NewTypeParam(tobj.Sym(), 0)
+	if got := types.Complexity(types.NewSlice(tp)).Size; got != types.SizeUnknown {
+		t.Errorf("Complexity([]T).Size = %v, want SizeUnknown", got)
+	}
+}
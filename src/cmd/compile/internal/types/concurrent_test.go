@@ -0,0 +1,118 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+var concRuns int
+
+// TestConcurrentConstruction checks that goroutines constructing the
+// same types and symbols concurrently agree on them. Run it with -race.
+func TestConcurrentConstruction(t *testing.T) {
+	// A package of its own for each run, since symbols stay defined.
+	concRuns++
+	path := fmt.Sprintf("conc%d", concRuns)
+	pkg := types.NewPkg(path, "conc")
+	elems := []*types.Type{types.Types[types.TINT], types.Types[types.TSTRING], types.NewStruct(pkg, nil)}
+
+	const n = 8
+	var (
+		wg      sync.WaitGroup
+		ptrs    [n][]*types.Type
+		syms    [n][]*types.Sym
+		strs    [n][]string
+		defined [n]int
+	)
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i, elem := range elems {
+				ptrs[g] = append(ptrs[g], types.NewPtr(types.NewSlice(types.NewPtr(elem))))
+				name := fmt.Sprintf("S%d", i)
+				syms[g] = append(syms[g], types.NewPkg(path, "").Lookup(name), pkg.LookupBytes([]byte("B"+name)))
+				strs[g] = append(strs[g], types.InternString([]byte("str"+name)))
+				_, ok := pkg.DefineOnce("T"+name, func(s *types.Sym) {
+					s.Def = ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, s)
+				})
+				if ok {
+					defined[g]++
+				}
+				types.ReadInternStats()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	total := 0
+	for g := 0; g < n; g++ {
+		total += defined[g]
+		for i := range elems {
+			if ptrs[g][i] != ptrs[0][i] {
+				t.Errorf("goroutines %d and 0 built different *[]*%v", g, elems[i])
+			}
+			if stringData(strs[g][i]) != stringData(strs[0][i]) {
+				t.Errorf("goroutines %d and 0 interned different copies of %q", g, strs[0][i])
+			}
+		}
+		for i := range syms[g] {
+			if syms[g][i] != syms[0][i] {
+				t.Errorf("goroutines %d and 0 looked up different %v", g, syms[0][i])
+			}
+		}
+	}
+	if total != len(elems) {
+		t.Errorf("DefineOnce defined %d symbols, want %d", total, len(elems))
+	}
+}
+
+// stringData returns the address of the bytes of s.
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+// TestConcurrentFormatMessage checks that messages formatted
+// concurrently, and the types and symbols formatted concurrently with
+// them, qualify packages independently of each other. Run it with
+// -race.
+func TestConcurrentFormatMessage(t *testing.T) {
+	x1 := types.NewPkg("example.com/one/conc", "conc").Lookup("X")
+	x2 := types.NewPkg("example.com/two/conc", "conc").Lookup("X")
+
+	const n = 8
+	var wg sync.WaitGroup
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				var got, want string
+				switch g % 3 {
+				case 0:
+					got, want = types.FormatMessage(src.NoXPos, "%v %v", x1, x2), `"example.com/one/conc".X "example.com/two/conc".X`
+				case 1:
+					got, want = types.FormatMessage(src.NoXPos, "%v", x1), "conc.X"
+				case 2:
+					got, want = x2.String(), "conc.X"
+				}
+				if got != want {
+					t.Errorf("goroutine %d: got %s, want %s", g, got, want)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
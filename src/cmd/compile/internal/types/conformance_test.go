@@ -0,0 +1,169 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"internal/testenv"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// conformanceSrc declares the types checked by TestFormatConformance.
+const conformanceSrc = `package conf
+
+import "io"
+
+type T struct {
+	x, y int
+	S    string ` + "`json:\"s\"`" + `
+	io.Reader
+	f func(int, ...string) (err error)
+	m map[string][]*T
+	c <-chan struct{}
+	e struct{ A int }
+}
+
+func (T) M() int      { return 0 }
+func (*T) n(int) bool { return false }
+
+type I interface {
+	io.Reader
+	M() int
+	m(x, y int) (T, error)
+}
+
+type E interface{}
+
+type G[P any] struct{ v P }
+
+type Num interface{ ~int | ~float64 }
+
+type F func(...interface{}) (int, error)
+
+type P *T
+
+type A [4]struct{ _ int }
+
+type B byte
+
+type R []rune
+`
+
+// conformanceUser mentions the types of conformanceSrc, so that
+// compiling it imports them.
+const conformanceUser = `package user
+
+import "conf"
+
+var (
+	_ conf.T
+	_ conf.I
+	_ conf.E
+	_ conf.F
+	_ conf.P
+	_ conf.A
+	_ conf.B
+	_ conf.R
+)
+
+func _[P conf.Num, Q conf.G[P]]() {}
+`
+
+// conformanceModes lists the representations printed by -d=showtype
+// that must not depend on whether the type was declared in the
+// package being compiled or imported from export data. The debug
+// representation and size are for compiler developers and are
+// excluded.
+var conformanceModes = map[string]bool{
+	"go":         true,
+	"underlying": true,
+	"link":       true,
+	"name":       true,
+	"hash":       true,
+	"method":     true,
+}
+
+// TestFormatConformance checks that types declared in one package
+// print the same when another package imports them from export data.
+func TestFormatConformance(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	dir := t.TempDir()
+	for name, src := range map[string]string{"conf.go": conformanceSrc, "user.go": conformanceUser} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	compile := func(query string, args ...string) map[string][]string {
+		run := append([]string{testenv.GoToolPath(t), "tool", "compile", "-d=showtype=" + query}, args...)
+		cmd := exec.Command(run[0], run[1:]...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", run, err, out)
+		}
+		return parseShowType(string(out))
+	}
+
+	for _, name := range []string{"T", "I", "E", "G", "Num", "F", "P", "A", "B", "R"} {
+		query := "conf." + name
+		orig := compile(query, "-p", "conf", "-o", "conf.o", "conf.go")
+		imported := compile(query, "-p", "user", "-I", ".", "-o", "user.o", "user.go")
+
+		want := orig[name]
+		if want == nil {
+			t.Errorf("%s: not shown when compiling its package", query)
+			continue
+		}
+		got := imported[name]
+		if got == nil {
+			t.Errorf("%s: not shown when imported", query)
+			continue
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%s: imported type prints differently\nimported:\n\t%s\noriginal:\n\t%s",
+				query, strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+		}
+	}
+}
+
+var confQual = regexp.MustCompile(`\bconf\.|""\.`)
+
+// parseShowType parses the output of -d=showtype, keyed by type
+// name. It keeps the representations in conformanceModes, and
+// rewrites them to refer to the package conf the same way whether it
+// is being compiled or imported: the local package prefix "" of link
+// strings is spelled conf, and Go syntax is unqualified. Go syntax
+// spells unexported interface methods of the local package with the
+// prefix "" too.
+func parseShowType(out string) map[string][]string {
+	m := make(map[string][]string)
+	name := ""
+	for _, line := range strings.Split(out, "\n") {
+		if i := strings.Index(line, ": showtype "); i >= 0 {
+			name = confQual.ReplaceAllString(line[i+len(": showtype "):], "")
+			m[name] = []string{}
+			continue
+		}
+		label, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || name == "" || !conformanceModes[label] {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch label {
+		case "go", "underlying":
+			value = confQual.ReplaceAllString(value, "")
+		case "link":
+			value = strings.ReplaceAll(value, `"".`, "conf.")
+		}
+		m[name] = append(m[name], label+": "+value)
+	}
+	return m
+}
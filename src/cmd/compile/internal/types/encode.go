@@ -0,0 +1,607 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"cmd/compile/internal/base"
+	"cmd/internal/src"
+)
+
+// Encode and Decode serialize a type and the types it refers to,
+// independently of export data, so that tools and tests can snapshot
+// the compiler's view of a type. EncodeTypes and DecodeTypes do the
+// same for a list of types, which share the encodings of the types
+// they refer to. Tools that cannot import this package get the
+// declared types of a package in this encoding from the -typesnapshot
+// flag of the compiler.
+//
+// The encoding records each type once; later references to it are
+// back references, so recursive types are supported. The underlying
+// type and methods of a named type follow the top-level type in whose
+// encoding the named type begins, so that no composite type is
+// referred to before it is complete, even through a named type. It does not
+// record positions, field nodes, or any other IR. Predeclared types
+// are recorded by reference, so data is only meaningful to a compiler
+// built from the same sources.
+//
+// The encoding is:
+//
+//	data     = version n (type body*)^n
+//	type     = tagNil                     // a missing type, such as an unset bound
+//	         | tagRef index
+//	         | tagSpecial index           // see specialTypes
+//	         | tagNamed sym vargen rparams // body follows later
+//	         | tagTypeParam sym index type
+//	         | tagPtr type | tagSlice type
+//	         | tagArray type bound
+//	         | tagChan type dir
+//	         | tagMap type type
+//	         | tagStruct pkg fields
+//	         | tagInter pkg implicit fields
+//	         | tagFunc pkg fields fields fields fields // receiver, type parameters, parameters, results
+//	         | tagUnion n (type tilde)^n
+//	body     = type methods               // of each named type begun, in order
+//	sym      = pkg string
+//	pkg      = 0                          // no package
+//	         | 1 path name                // a new package; LocalPkg has path ""
+//	         | 2+index                    // a package seen before
+//	fields   = n field^n
+//	field    = (0 | 1 sym) type embedded ddd note
+//
+// Integers are uvarints, and strings are a length followed by bytes.
+// Types and packages are numbered from 0 in the order their encodings
+// begin.
+const encodeVersion = 2
+
+const (
+	tagNil = iota
+	tagRef
+	tagSpecial
+	tagNamed
+	tagTypeParam
+	tagPtr
+	tagSlice
+	tagArray
+	tagChan
+	tagMap
+	tagStruct
+	tagInter
+	tagFunc
+	tagUnion
+)
+
+// specialTypes returns the types that Encode records by reference:
+// the predeclared types and the special types of the universe.
+// Entries may be nil.
+func specialTypes() []*Type {
+	list := append([]*Type(nil), Types[:]...)
+	return append(list,
+		ByteType, RuneType, ErrorType, ComparableType, AnyType,
+		UntypedString, UntypedBool, UntypedInt, UntypedRune, UntypedFloat, UntypedComplex)
+}
+
+// Encode returns the encoding of t and the types it refers to, which
+// Decode turns back into a type.
+func Encode(t *Type) []byte {
+	return EncodeTypes([]*Type{t})
+}
+
+// EncodeTypes returns the encoding of ts and the types they refer to,
+// which DecodeTypes turns back into a list of types.
+func EncodeTypes(ts []*Type) []byte {
+	e := encoder{
+		types: make(map[*Type]int),
+		pkgs:  make(map[*Pkg]int),
+	}
+	for i, st := range specialTypes() {
+		if _, ok := e.types[st]; st != nil && !ok {
+			e.types[st] = -1 - i
+		}
+	}
+	e.uint(encodeVersion)
+	e.int(len(ts))
+	for _, t := range ts {
+		e.typ(t)
+		for len(e.bodies) > 0 {
+			t := e.bodies[0]
+			e.bodies = e.bodies[1:]
+			e.typ(t.Underlying())
+			e.fields(t.Methods().Slice())
+		}
+	}
+	return e.buf
+}
+
+type encoder struct {
+	buf    []byte
+	types  map[*Type]int // index of each encoded type; -1-i for specialTypes()[i]
+	ntypes int
+	pkgs   map[*Pkg]int
+	bodies []*Type // named types whose bodies are still to be encoded
+}
+
+func (e *encoder) uint(x uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], x)
+	e.buf = append(e.buf, b[:n]...)
+}
+
+func (e *encoder) int(x int) { e.uint(uint64(x)) }
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.uint(1)
+	} else {
+		e.uint(0)
+	}
+}
+
+func (e *encoder) string(s string) {
+	e.int(len(s))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) pkg(p *Pkg) {
+	if p == nil {
+		e.int(0)
+		return
+	}
+	if i, ok := e.pkgs[p]; ok {
+		e.int(2 + i)
+		return
+	}
+	e.pkgs[p] = len(e.pkgs)
+	e.int(1)
+	e.string(p.Path)
+	e.string(p.Name)
+}
+
+func (e *encoder) sym(s *Sym) {
+	e.pkg(s.Pkg)
+	e.string(s.Name)
+}
+
+func (e *encoder) typ(t *Type) {
+	if t == nil {
+		e.int(tagNil)
+		return
+	}
+	if i, ok := e.types[t]; ok {
+		if i < 0 {
+			e.int(tagSpecial)
+			e.int(-1 - i)
+		} else {
+			e.int(tagRef)
+			e.int(i)
+		}
+		return
+	}
+	e.types[t] = e.ntypes
+	e.ntypes++
+
+	switch {
+	case t.kind == TTYPEPARAM:
+		e.int(tagTypeParam)
+		e.sym(t.sym)
+		e.int(t.Index())
+		e.typ(t.Bound())
+		return
+
+	case t.sym != nil:
+		if t.kind == TFORW {
+			base.Fatalf("cannot encode incomplete type %v", t)
+		}
+		e.int(tagNamed)
+		e.sym(t.sym)
+		e.int(int(t.vargen))
+		e.int(len(t.RParams()))
+		for _, rp := range t.RParams() {
+			e.typ(rp)
+		}
+		e.bodies = append(e.bodies, t)
+		return
+	}
+
+	switch t.kind {
+	case TPTR:
+		e.int(tagPtr)
+		e.typ(t.Elem())
+	case TSLICE:
+		e.int(tagSlice)
+		e.typ(t.Elem())
+	case TARRAY:
+		e.int(tagArray)
+		e.typ(t.Elem())
+		e.uint(uint64(t.NumElem()))
+	case TCHAN:
+		e.int(tagChan)
+		e.typ(t.Elem())
+		e.int(int(t.ChanDir()))
+	case TMAP:
+		e.int(tagMap)
+		e.typ(t.Key())
+		e.typ(t.Elem())
+	case TSTRUCT:
+		e.int(tagStruct)
+		e.pkg(t.Pkg())
+		e.fields(t.FieldSlice())
+	case TINTER:
+		e.int(tagInter)
+		e.pkg(t.Pkg())
+		e.bool(t.IsImplicit())
+		e.fields(t.Methods().Slice())
+	case TFUNC:
+		e.int(tagFunc)
+		e.pkg(t.Pkg())
+		e.fields(t.Recvs().FieldSlice())
+		e.fields(t.TParams().FieldSlice())
+		e.fields(t.Params().FieldSlice())
+		e.fields(t.Results().FieldSlice())
+	case TUNION:
+		e.int(tagUnion)
+		e.int(t.NumTerms())
+		for i := 0; i < t.NumTerms(); i++ {
+			term, tilde := t.Term(i)
+			e.typ(term)
+			e.bool(tilde)
+		}
+	default:
+		base.Fatalf("cannot encode %v type %v", t.kind, t)
+	}
+}
+
+func (e *encoder) fields(fs []*Field) {
+	e.int(len(fs))
+	for _, f := range fs {
+		if f.Sym == nil {
+			e.int(0)
+		} else {
+			e.int(1)
+			e.sym(f.Sym)
+		}
+		e.typ(f.Type)
+		e.int(int(f.Embedded))
+		e.bool(f.IsDDD())
+		e.string(f.Note)
+	}
+}
+
+// Decode returns the type encoded in data by Encode.
+//
+// A named type whose name is already declared as that type in the
+// current compilation decodes as the existing type. Otherwise, Decode
+// creates a new named type, which is distinct from any type created
+// by another call to Decode. Types of the package that was being
+// compiled when data was encoded decode as types of LocalPkg.
+func Decode(data []byte) (*Type, error) {
+	ts, err := DecodeTypes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(ts) != 1 {
+		return nil, fmt.Errorf("types.Decode: %d types encoded, want 1", len(ts))
+	}
+	return ts[0], nil
+}
+
+// DecodeTypes returns the types encoded in data by EncodeTypes, or by
+// Encode, as Decode does.
+func DecodeTypes(data []byte) (ts []*Type, err error) {
+	d := decoder{data: data, special: specialTypes()}
+	defer func() {
+		if r := recover(); r != nil {
+			if derr, ok := r.(decodeError); ok {
+				ts, err = nil, derr.err
+				return
+			}
+			panic(r)
+		}
+	}()
+	if v := d.uint(); v != encodeVersion {
+		d.fail("unknown version %d", v)
+	}
+	n := d.int()
+	if n > len(d.data) {
+		d.fail("%d types in %d bytes", n, len(d.data))
+	}
+	ts = make([]*Type, n)
+	for i := range ts {
+		ts[i] = d.typ()
+		for len(d.bodies) > 0 {
+			n := d.bodies[0]
+			d.bodies = d.bodies[1:]
+			d.body(n)
+		}
+	}
+	if len(d.data) != 0 {
+		d.fail("%d bytes of trailing data", len(d.data))
+	}
+	return ts, nil
+}
+
+// DeclaredTypes returns the named types declared at package level in
+// the package being compiled, sorted by name, as -typesnapshot
+// encodes them.
+func DeclaredTypes() []*Type {
+	var ts []*Type
+	for _, s := range LocalPkg.SortedSyms() {
+		if s.Def == nil {
+			continue
+		}
+		// Only the declaration of a defined type gives it its own
+		// symbol. Type parameters and instantiations are left out:
+		// they are derived from the declarations of generic types.
+		t := s.Def.Type()
+		if t == nil || t.Sym() != s || t.Kind() == TFORW || t.Kind() == TTYPEPARAM || t.Broke() {
+			continue
+		}
+		if len(t.RParams()) > 0 && !t.IsBaseGeneric() {
+			continue
+		}
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+type decoder struct {
+	data    []byte
+	special []*Type
+	types   []*Type
+	pkgs    []*Pkg
+	bodies  []decodedNamed // named types whose bodies are still to be decoded
+}
+
+type decodedNamed struct {
+	t        *Type
+	existing bool // t was declared already, and its body is skipped
+}
+
+type decodeError struct{ err error }
+
+func (d *decoder) fail(format string, args ...interface{}) {
+	panic(decodeError{fmt.Errorf("types.Decode: "+format, args...)})
+}
+
+func (d *decoder) uint() uint64 {
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("bad integer")
+	}
+	d.data = d.data[n:]
+	return x
+}
+
+func (d *decoder) int() int {
+	x := d.uint()
+	if x > 1<<31 {
+		d.fail("integer %d out of range", x)
+	}
+	return int(x)
+}
+
+func (d *decoder) bool() bool { return d.uint() != 0 }
+
+func (d *decoder) string() string {
+	n := d.int()
+	if n > len(d.data) {
+		d.fail("string length %d out of range", n)
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *decoder) pkg() *Pkg {
+	switch i := d.int(); i {
+	case 0:
+		return nil
+	case 1:
+	default:
+		if i-2 >= len(d.pkgs) {
+			d.fail("package %d out of range", i-2)
+		}
+		return d.pkgs[i-2]
+	}
+	path, name := d.string(), d.string()
+	p := LocalPkg
+	if path != "" {
+		if p = existingPkg(path); p == nil {
+			p = NewPkg(path, name)
+		}
+	}
+	d.pkgs = append(d.pkgs, p)
+	return p
+}
+
+func (d *decoder) sym() *Sym {
+	pkg := d.pkg()
+	if pkg == nil {
+		d.fail("symbol without package")
+	}
+	return pkg.Lookup(d.string())
+}
+
+func (d *decoder) typ() *Type {
+	switch tag := d.int(); tag {
+	case tagNil:
+		return nil
+
+	case tagRef:
+		i := d.int()
+		if i >= len(d.types) || d.types[i] == nil {
+			d.fail("type %d out of range", i)
+		}
+		return d.types[i]
+
+	case tagSpecial:
+		i := d.int()
+		if i >= len(d.special) || d.special[i] == nil {
+			d.fail("special type %d out of range", i)
+		}
+		return d.special[i]
+
+	case tagTypeParam:
+		idx := len(d.types)
+		d.types = append(d.types, nil)
+		t := NewTypeParam(d.sym(), 0)
+		t.SetIndex(d.int())
+		d.types[idx] = t
+		t.SetBound(d.typ())
+		return t
+
+	case tagNamed:
+		return d.named()
+
+	default:
+		// Composite types cannot refer to themselves except
+		// through a named type, so their own index stays nil
+		// while their elements are decoded.
+		idx := len(d.types)
+		d.types = append(d.types, nil)
+		t := d.composite(tag)
+		d.types[idx] = t
+		return t
+	}
+}
+
+func (d *decoder) named() *Type {
+	idx := len(d.types)
+	d.types = append(d.types, nil)
+
+	sym := d.sym()
+	vargen := int32(d.int())
+	var existing *Type
+	if sym.Def != nil && vargen == 0 {
+		if t := sym.Def.Type(); t != nil && t.sym == sym {
+			existing = t
+		}
+	}
+
+	var t *Type
+	if existing != nil {
+		t = existing
+	} else {
+		obj := &decodedTypeName{sym: sym}
+		t = NewNamed(obj)
+		obj.typ = t
+		t.vargen = vargen
+	}
+	d.types[idx] = t
+
+	rparams := make([]*Type, d.int())
+	for i := range rparams {
+		rparams[i] = d.typ()
+	}
+	if existing == nil && len(rparams) > 0 {
+		t.SetRParams(rparams)
+	}
+	d.bodies = append(d.bodies, decodedNamed{t, existing != nil})
+	return t
+}
+
+// body decodes the underlying type and methods of n.t.
+func (d *decoder) body(n decodedNamed) {
+	underlying := d.typ()
+	methods := d.fields()
+	if n.existing {
+		return
+	}
+	if underlying == nil {
+		d.fail("named type %v has no underlying type", n.t)
+	}
+	n.t.nod.(*decodedTypeName).defn = underlying
+	n.t.SetUnderlying(underlying)
+	n.t.Methods().Set(methods)
+}
+
+func (d *decoder) composite(tag int) *Type {
+	switch tag {
+	case tagPtr:
+		return NewPtr(d.typ())
+	case tagSlice:
+		return NewSlice(d.typ())
+	case tagArray:
+		elem := d.typ()
+		n := d.uint()
+		if n > 1<<62 {
+			d.fail("array bound %d out of range", n)
+		}
+		return NewArray(elem, int64(n))
+	case tagChan:
+		elem := d.typ()
+		dir := ChanDir(d.int())
+		if dir&^Cboth != 0 || dir == 0 {
+			d.fail("bad channel direction %d", dir)
+		}
+		return NewChan(elem, dir)
+	case tagMap:
+		key := d.typ()
+		return NewMap(key, d.typ())
+	case tagStruct:
+		pkg := d.pkg()
+		return NewStruct(pkg, d.fields())
+	case tagInter:
+		pkg := d.pkg()
+		implicit := d.bool()
+		return NewInterface(pkg, d.fields(), implicit)
+	case tagFunc:
+		pkg := d.pkg()
+		recvs, tparams, params, results := d.fields(), d.fields(), d.fields(), d.fields()
+		var recv *Field
+		switch len(recvs) {
+		case 0:
+		case 1:
+			recv = recvs[0]
+		default:
+			d.fail("%d receivers", len(recvs))
+		}
+		return NewSignature(pkg, recv, tparams, params, results)
+	case tagUnion:
+		n := d.int()
+		terms, tildes := make([]*Type, n), make([]bool, n)
+		for i := range terms {
+			terms[i] = d.typ()
+			tildes[i] = d.bool()
+		}
+		return NewUnion(terms, tildes)
+	}
+	d.fail("unknown type tag %d", tag)
+	panic("unreachable")
+}
+
+func (d *decoder) fields() []*Field {
+	n := d.int()
+	if n > len(d.data) {
+		d.fail("field count %d out of range", n)
+	}
+	fs := make([]*Field, n)
+	for i := range fs {
+		var sym *Sym
+		if d.int() != 0 {
+			sym = d.sym()
+		}
+		f := NewField(src.NoXPos, sym, d.typ())
+		f.Embedded = uint8(d.int())
+		f.SetIsDDD(d.bool())
+		f.Note = d.string()
+		fs[i] = f
+	}
+	return fs
+}
+
+// A decodedTypeName is the object of a named type created by Decode.
+type decodedTypeName struct {
+	sym  *Sym
+	typ  *Type
+	defn *Type
+}
+
+func (n *decodedTypeName) Pos() src.XPos   { return src.NoXPos }
+func (n *decodedTypeName) Sym() *Sym       { return n.sym }
+func (n *decodedTypeName) Type() *Type     { return n.typ }
+func (n *decodedTypeName) TypeDefn() *Type { return n.defn }
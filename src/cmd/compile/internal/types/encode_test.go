@@ -0,0 +1,130 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"fmt"
+	"testing"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestEncode(t *testing.T) {
+	pkg := types.NewPkg("enc", "enc")
+	i8, str := types.Types[types.TINT8], types.Types[types.TSTRING]
+
+	// type List struct { next *List; Val map[string]int8 `json:"v"` }
+	declare := func(name string) *types.Type {
+		obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup(name))
+		typ := types.NewNamed(obj)
+		obj.SetType(typ)
+		val := types.NewField(src.NoXPos, pkg.Lookup("Val"), types.NewMap(str, i8))
+		val.Note = `json:"v"`
+		typ.SetUnderlying(types.NewStruct(pkg, []*types.Field{
+			types.NewField(src.NoXPos, pkg.Lookup("next"), types.NewPtr(typ)),
+			val,
+		}))
+		return typ
+	}
+	list := declare("List")
+	list.Sym().Def = list.Obj()
+	undeclared := declare("Undeclared")
+	undeclared.Methods().Set([]*types.Field{types.NewField(src.NoXPos, pkg.Lookup("Len"),
+		types.NewSignature(pkg, types.NewField(src.NoXPos, nil, undeclared), nil, nil,
+			[]*types.Field{types.NewField(src.NoXPos, nil, types.Types[types.TINT])}))})
+
+	union := types.NewUnion([]*types.Type{types.Types[types.TINT], types.ByteType}, []bool{true, false})
+	iface := types.NewInterface(pkg, []*types.Field{
+		types.NewField(src.NoXPos, nil, types.ErrorType),
+		types.NewField(src.NoXPos, pkg.Lookup("M"), types.NewSignature(pkg, types.FakeRecv(), nil, nil, nil)),
+	}, false)
+	variadic := types.NewField(src.NoXPos, pkg.Lookup("xs"), types.NewSlice(types.Types[types.TINTER]))
+	variadic.SetIsDDD(true)
+
+	// Types that refer to undeclared decode as new types.
+	fresh := types.NewArray(types.NewChan(undeclared, types.Crecv), 7)
+
+	for _, typ := range []*types.Type{
+		types.Types[types.TINT],
+		types.RuneType,
+		fresh,
+		types.NewSignature(pkg, nil, nil,
+			[]*types.Field{types.NewField(src.NoXPos, nil, list), variadic},
+			[]*types.Field{types.NewField(src.NoXPos, nil, types.ErrorType)}),
+		types.NewInterface(pkg, []*types.Field{types.NewField(src.NoXPos, nil, union)}, true),
+		iface,
+		undeclared,
+	} {
+		data := types.Encode(typ)
+		got, err := types.Decode(data)
+		if err != nil {
+			t.Errorf("Decode(Encode(%v)): %v", typ, err)
+			continue
+		}
+		for _, format := range []string{"%v", "%L", "%+v", "%-v"} {
+			if g, w := fmt.Sprintf(format, got), fmt.Sprintf(format, typ); g != w {
+				t.Errorf("Decode(Encode(%v)) formats with %s as %q, want %q", typ, format, g, w)
+			}
+		}
+		if typ != undeclared && typ != fresh && !types.Identical(got, typ) {
+			t.Errorf("Decode(Encode(%v)) = %v, not identical", typ, got)
+		}
+
+		// Every prefix of the data is an error, not a crash.
+		for i := range data {
+			if _, err := types.Decode(data[:i]); err == nil {
+				t.Errorf("Decode of %d-byte prefix of Encode(%v) succeeded", i, typ)
+			}
+		}
+	}
+
+	if got, err := types.Decode(types.Encode(list)); err != nil || got != list {
+		t.Errorf("Decode(Encode(%v)) = %p, %v; want the declared type %p", list, got, err, list)
+	}
+	got, err := types.Decode(types.Encode(undeclared))
+	if err != nil || got == undeclared {
+		t.Fatalf("Decode(Encode(%v)) = %p, %v; want a new type", undeclared, got, err)
+	}
+	if ms := got.Methods().Slice(); len(ms) != 1 || ms[0].Sym.Name != "Len" || ms[0].Type.Recv().Type != got {
+		t.Errorf("Decode(Encode(%v)) has methods %v, want Len with the new type as receiver", undeclared, ms)
+	}
+}
+
+func TestEncodeTypes(t *testing.T) {
+	pkg := types.NewPkg("encs", "encs")
+	obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup("T"))
+	named := types.NewNamed(obj)
+	obj.SetType(named)
+	named.SetUnderlying(types.NewStruct(pkg, []*types.Field{
+		types.NewField(src.NoXPos, pkg.Lookup("next"), types.NewPtr(named)),
+	}))
+
+	// The second type refers to the first, which is encoded only once.
+	list := []*types.Type{named, types.NewSlice(named), types.Types[types.TINT]}
+	data := types.EncodeTypes(list)
+	if one, all := len(types.Encode(named)), len(data); all >= 2*one {
+		t.Errorf("EncodeTypes takes %d bytes, Encode of the first type alone %d; want the types to share encodings", all, one)
+	}
+	got, err := types.DecodeTypes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(list) {
+		t.Fatalf("DecodeTypes returned %d types, want %d", len(got), len(list))
+	}
+	for i, typ := range list {
+		if g, w := fmt.Sprintf("%L", got[i]), fmt.Sprintf("%L", typ); g != w {
+			t.Errorf("type %d decodes as %s, want %s", i, g, w)
+		}
+	}
+	if got[1].Elem() != got[0] {
+		t.Errorf("decoded %v has element type %p, want the decoded %v %p", got[1], got[1].Elem(), got[0], got[0])
+	}
+	if _, err := types.Decode(data); err == nil {
+		t.Errorf("Decode of %d types succeeded", len(list))
+	}
+}
@@ -5,16 +5,22 @@
 package types
 
 import (
-	"bytes"
 	"crypto/md5"
 	"encoding/binary"
 	"fmt"
 	"go/constant"
+	"io"
+	"math/big"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/typefmt"
+	"cmd/internal/objabi"
+	"cmd/internal/src"
 )
 
 // BuiltinPkg is a fake package that declares the universe block.
@@ -29,42 +35,257 @@ var UnsafePkg *Pkg
 // BlankSym is the blank (_) symbol.
 var BlankSym *Sym
 
-// OrigSym returns the original symbol written by the user.
-func OrigSym(s *Sym) *Sym {
-	if s == nil {
-		return nil
+// FormatMessage formats a diagnostic at pos like fmt.Sprintf.
+//
+// Symbols of imported packages are normally qualified by the name by
+// which the source file containing pos refers to the package (see
+// Pkg.AliasIn), but if the message would use the same name for two
+// different packages, the symbols of those packages are qualified by
+// their import paths instead. This applies to the types and symbols
+// among args, and to those they refer to; other messages are
+// unaffected.
+//
+// A large type that the message mentions more than once is printed
+// in full only the first time; see Mentions.
+func FormatMessage(pos src.XPos, format string, args ...interface{}) string {
+	q := new(msgQualifier)
+	if pos.IsKnown() && base.Ctxt != nil {
+		q.file = base.Ctxt.PosTable.Pos(pos).Filename()
+	}
+	args = msgArgs(args, fmtState{qual: q, bestEffort: true, mentions: new(Mentions)})
+	return q.resolve(q.adopt(fmt.Sprintf(format, args...)))
+}
+
+// FormatMessages returns the texts msgs, such as those of a diagnostic
+// and its notes, with the packages that they refer to qualified as
+// FormatMessage(pos, "%s", msg) qualifies those of a single text, but
+// alike: a package is qualified by its import path in all the texts
+// if they use its name for another package.
+func FormatMessages(pos src.XPos, msgs ...string) []string {
+	q := new(msgQualifier)
+	if pos.IsKnown() && base.Ctxt != nil {
+		q.file = base.Ctxt.PosTable.Pos(pos).Filename()
+	}
+	out := make([]string, len(msgs))
+	for i, msg := range msgs {
+		out[i] = q.adopt(msg)
 	}
+	for i, msg := range out {
+		out[i] = q.resolve(msg)
+	}
+	return out
+}
+
+// FormatBestEffort formats like fmt.Sprintf, except that it formats
+// the types among args on a best-effort basis: rather than report an
+// internal error about a malformed or half-built type, which would
+// replace the error being reported, it prints a placeholder for the
+// type. The compiler formats internal errors with it, and
+// FormatMessage formats types this way too.
+func FormatBestEffort(format string, args ...interface{}) string {
+	return fmt.Sprintf(format, msgArgs(args, fmtState{bestEffort: true})...)
+}
 
-	if len(s.Name) > 1 && s.Name[0] == '~' {
-		switch s.Name[1] {
-		case 'r': // originally an unnamed result
-			return nil
-		case 'b': // originally the blank identifier _
-			// TODO(mdempsky): Does s.Pkg matter here?
-			return BlankSym
+// msgArgs returns a copy of the arguments args of a message, in which
+// the types and symbols are formatted in state st. Other formatters,
+// such as IR nodes, are formatted with a msgState, so that they can
+// format the types and symbols they print in st with Fprintf.
+func msgArgs(args []interface{}, st fmtState) []interface{} {
+	margs := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case *Type, *Sym:
+			margs[i] = msgArg{arg, st}
+			continue
+		case fmt.Formatter:
+			// Leave nil pointers to fmt, which prints them as <nil>.
+			if v := reflect.ValueOf(arg); v.Kind() != reflect.Ptr || !v.IsNil() {
+				margs[i] = msgArg{arg, st}
+				continue
+			}
 		}
-		return s
+		margs[i] = arg
 	}
+	return margs
+}
 
-	if strings.HasPrefix(s.Name, ".anon") {
-		// originally an unnamed or _ name (see subr.go: NewFuncParams)
-		return nil
+// A msgArg is an argument of a message. It formats like the type,
+// symbol or other formatter x, but in state st.
+type msgArg struct {
+	x  interface{} // *Type, *Sym or fmt.Formatter
+	st fmtState
+}
+
+func (a msgArg) Format(s fmt.State, verb rune) {
+	switch x := a.x.(type) {
+	case *Type:
+		if verb == 'v' || verb == 'S' || verb == 'L' {
+			x.format(s, verb, a.st)
+			return
+		}
+		x.Format(s, verb)
+	case *Sym:
+		if verb == 'v' || verb == 'S' {
+			x.format(s, verb, a.st.qual)
+			return
+		}
+		x.Format(s, verb)
+	case fmt.Formatter:
+		x.Format(msgState{s, a.st}, verb)
 	}
+}
 
-	return s
+// A msgState is the fmt.State in which a formatter among the arguments
+// of a message is formatted; see Fprintf.
+type msgState struct {
+	fmt.State
+	st fmtState
+}
+
+// Fprintf is like fmt.Fprintf, except that if w is the fmt.State in
+// which an argument of a message formatted by FormatMessage or
+// FormatBestEffort is being formatted, it formats the types, symbols
+// and other formatters among args as part of that message. Format
+// methods of values that print types and symbols, like those of IR
+// nodes, use it, so that the symbols take part in the message's
+// package qualification.
+func Fprintf(w io.Writer, format string, args ...interface{}) {
+	if ms, ok := w.(msgState); ok {
+		args = msgArgs(args, ms.st)
+	}
+	fmt.Fprintf(w, format, args...)
+}
+
+// Qualifier returns the qualifier of the symbols of pkg in a message
+// formatted by FormatMessage. Messages formatted by other packages,
+// like those of types2, contain it in place of the package name, so
+// that FormatMessage qualifies pkg as if the message had mentioned one
+// of its symbols.
+func (pkg *Pkg) Qualifier() string {
+	return string(msgQualMark) + "@" + pkg.Path + string(msgQualMark)
+}
+
+// adopt returns msg with each qualifier returned by Pkg.Qualifier
+// replaced by a placeholder of q.
+func (q *msgQualifier) adopt(msg string) string {
+	mark := string(msgQualMark) + "@"
+	if !strings.Contains(msg, mark) {
+		return msg
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(msg, mark)
+		if i < 0 {
+			break
+		}
+		b.WriteString(msg[:i])
+		msg = msg[i+len(mark):]
+		j := strings.IndexByte(msg, msgQualMark)
+		if j < 0 {
+			b.WriteString(mark)
+			continue
+		}
+		if pkg := existingPkg(msg[:j]); pkg != nil {
+			b.WriteString(q.placeholder(pkg))
+		} else {
+			b.WriteString(strconv.Quote(userPkgPath(msg[:j])))
+		}
+		msg = msg[j+1:]
+	}
+	b.WriteString(msg)
+	return b.String()
+}
+
+// A msgQualifier qualifies the symbols of the packages that a message
+// formatted by FormatMessage refers to. Its packages are only known
+// once the whole message is formatted, so the message contains a
+// placeholder for each qualifier, which resolve replaces.
+type msgQualifier struct {
+	file string       // source file the message is about, if known
+	pkgs []*Pkg       // packages of the message, by placeholder number
+	nums map[*Pkg]int // placeholder number of each package
+}
+
+// msgQualMark delimits the placeholders of a msgQualifier, which are
+// the numbers of their packages.
+const msgQualMark = '\x00'
+
+// placeholder returns the placeholder for the qualifier of pkg.
+func (q *msgQualifier) placeholder(pkg *Pkg) string {
+	n, ok := q.nums[pkg]
+	if !ok {
+		if q.nums == nil {
+			q.nums = make(map[*Pkg]int)
+		}
+		n = len(q.pkgs)
+		q.pkgs = append(q.pkgs, pkg)
+		q.nums[pkg] = n
+	}
+	return string(msgQualMark) + strconv.Itoa(n) + string(msgQualMark)
+}
+
+// resolve returns msg with each placeholder replaced by the name by
+// which q's file refers to the package, or by the package's import
+// path if msg uses that name for another package too.
+func (q *msgQualifier) resolve(msg string) string {
+	if len(q.pkgs) == 0 {
+		return msg
+	}
+	names := make([]string, len(q.pkgs))
+	uses := make(map[string]int)
+	for i, pkg := range q.pkgs {
+		names[i] = pkg.Name
+		if q.file != "" {
+			names[i] = pkg.AliasIn(q.file)
+		}
+		uses[names[i]]++
+	}
+	for i, pkg := range q.pkgs {
+		if uses[names[i]] > 1 {
+			names[i] = strconv.Quote(userPkgPath(pkg.Path))
+		}
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(msg, msgQualMark)
+		if i < 0 {
+			break
+		}
+		b.WriteString(msg[:i])
+		msg = msg[i+1:]
+		if j := strings.IndexByte(msg, msgQualMark); j >= 0 {
+			if n, err := strconv.Atoi(msg[:j]); err == nil && 0 <= n && n < len(names) {
+				b.WriteString(names[n])
+				msg = msg[j+1:]
+				continue
+			}
+		}
+		// Not a placeholder.
+		b.WriteByte(msgQualMark)
+	}
+	b.WriteString(msg)
+	return b.String()
 }
 
-// numImport tracks how often a package with a given name is imported.
-// It is used to provide a better error message (by using the package
-// path to disambiguate) if a package that appears multiple times with
-// the same name appears in an error message.
-var NumImport = make(map[string]int)
+// fmtAllMethods returns t.AllMethods(). However, when formatting on a
+// best-effort basis, it returns the declared methods of an interface
+// whose method set has not been computed yet, rather than compute it:
+// doing so can report errors of its own.
+func fmtAllMethods(t *Type, st fmtState) *Fields {
+	if st.bestEffort && !t.widthCalculated() && t.allMethods.Len() == 0 {
+		return t.Methods()
+	}
+	return t.AllMethods()
+}
 
 // fmtMode represents the kind of printing being done.
 // The default is regular Go syntax (fmtGo).
 // fmtDebug is like fmtGo but for debugging dumps and prints the type kind too.
 // fmtTypeID and fmtTypeIDName are for generating various unique representations
 // of types used in hashes, the linker, and function/method instantiations.
+// fmtTypeIDStable is like fmtTypeID but qualifies identifiers by their
+// packages' full paths, including the current package's.
 type fmtMode int
 
 const (
@@ -72,8 +293,27 @@ const (
 	fmtDebug
 	fmtTypeID
 	fmtTypeIDName
+	fmtTypeIDStable
 )
 
+// isTypeID reports whether mode is fmtTypeID or its stable variant.
+func (mode fmtMode) isTypeID() bool {
+	return mode == fmtTypeID || mode == fmtTypeIDStable
+}
+
+// NameVargen controls whether NameString distinguishes function-scope
+// defined types from each other and from package-scoped defined types,
+// by the ·N suffix that LinkString uses for them, as in T·2. It is off
+// by default for compatibility: NameString is the type name that
+// reflection reports, which programs do not expect to carry a suffix.
+var NameVargen bool
+
+// hasVargen reports whether mode writes the ·N suffix of
+// function-scope defined types.
+func (mode fmtMode) hasVargen() bool {
+	return mode.isTypeID() || mode == fmtTypeIDName && NameVargen
+}
+
 // Sym
 
 // Format implements formatting for a Sym.
@@ -84,26 +324,32 @@ const (
 //	%S	Short syntax: Name only, no matter what.
 //
 func (s *Sym) Format(f fmt.State, verb rune) {
-	mode := fmtGo
 	switch verb {
 	case 'v', 'S':
-		if verb == 'v' && f.Flag('+') {
-			mode = fmtDebug
-		}
-		fmt.Fprint(f, sconv(s, verb, mode))
-
+		s.format(f, verb, nil)
 	default:
-		fmt.Fprintf(f, "%%!%c(*types.Sym=%p)", verb, s)
+		base.BadVerb(f, verb, fmt.Sprintf("%%!%c(*types.Sym=%p)", verb, s))
 	}
 }
 
+// format implements Format for its valid verbs, qualifying packages by
+// mq, if any.
+func (s *Sym) format(f fmt.State, verb rune, mq *msgQualifier) {
+	mode := fmtGo
+	if verb == 'v' && f.Flag('+') {
+		mode = fmtDebug
+	}
+	fmt.Fprint(f, sconv(s, verb, mode, mq))
+}
+
 func (s *Sym) String() string {
-	return sconv(s, 0, fmtGo)
+	return sconv(s, 0, fmtGo, nil)
 }
 
 // See #16897 for details about performance implications
 // before changing the implementation of sconv.
-func sconv(s *Sym, verb rune, mode fmtMode) string {
+// Packages are qualified by the message qualifier mq, if any.
+func sconv(s *Sym, verb rune, mode fmtMode, mq *msgQualifier) string {
 	if verb == 'L' {
 		panic("linksymfmt")
 	}
@@ -112,45 +358,51 @@ func sconv(s *Sym, verb rune, mode fmtMode) string {
 		return "<S>"
 	}
 
-	q := pkgqual(s.Pkg, verb, mode)
-	if q == "" {
+	q := pkgqual(s.Pkg, verb, mode, mq)
+	if q == "" && (mode != fmtGo || !strings.Contains(s.Name, objabi.ShapePrefix)) {
 		return s.Name
 	}
 
-	buf := fmtBufferPool.Get().(*bytes.Buffer)
-	buf.Reset()
-	defer fmtBufferPool.Put(buf)
-
-	buf.WriteString(q)
-	buf.WriteByte('.')
-	buf.WriteString(s.Name)
-	return InternString(buf.Bytes())
+	var buf [64]byte
+	b := symfmt(buf[:0], s, verb, mode, mq)
+	if mq != nil {
+		// Don't intern placeholders.
+		return string(b)
+	}
+	return InternString(b)
 }
 
-func sconv2(b *bytes.Buffer, s *Sym, verb rune, mode fmtMode) {
+func sconv2(b []byte, s *Sym, verb rune, mode fmtMode, mq *msgQualifier) []byte {
 	if verb == 'L' {
 		panic("linksymfmt")
 	}
 	if s == nil {
-		b.WriteString("<S>")
-		return
+		return append(b, "<S>"...)
 	}
 
-	symfmt(b, s, verb, mode)
+	return symfmt(b, s, verb, mode, mq)
 }
 
-func symfmt(b *bytes.Buffer, s *Sym, verb rune, mode fmtMode) {
-	if q := pkgqual(s.Pkg, verb, mode); q != "" {
-		b.WriteString(q)
-		b.WriteByte('.')
+func symfmt(b []byte, s *Sym, verb rune, mode fmtMode, mq *msgQualifier) []byte {
+	if q := pkgqual(s.Pkg, verb, mode, mq); q != "" {
+		b = append(b, q...)
+		b = append(b, '.')
+	}
+	if mode == fmtGo && strings.Contains(s.Name, objabi.ShapePrefix) {
+		// The name of a shape-based instantiation embeds the
+		// LinkStrings of its shapes, as in F[go.shape.int_0];
+		// show the user their readable form, F[shape:int].
+		return append(b, objabi.ReadableShapeName(s.Name)...)
 	}
-	b.WriteString(s.Name)
+	return append(b, s.Name...)
 }
 
 // pkgqual returns the qualifier that should be used for printing
 // symbols from the given package in the given mode.
 // If it returns the empty string, no qualification is needed.
-func pkgqual(pkg *Pkg, verb rune, mode fmtMode) string {
+// In fmtGo mode, the qualifier is a placeholder of the message
+// qualifier mq, if any.
+func pkgqual(pkg *Pkg, verb rune, mode fmtMode, mq *msgQualifier) string {
 	if verb != 'S' {
 		switch mode {
 		case fmtGo: // This is for the user
@@ -158,9 +410,20 @@ func pkgqual(pkg *Pkg, verb rune, mode fmtMode) string {
 				return ""
 			}
 
-			// If the name was used by multiple packages, display the full path,
-			if pkg.Name != "" && NumImport[pkg.Name] > 1 {
-				return strconv.Quote(pkg.Path)
+			if pkg.Name == "" {
+				return ""
+			}
+
+			// If the user asked for it with -d=fullpaths, display
+			// the full path, as the user wrote it in the import
+			// declaration. So does the message being formatted, if
+			// it refers to another package by the same name; see
+			// msgQualifier.
+			if base.Debug.FullPaths != 0 {
+				return strconv.Quote(userPkgPath(pkg.Path))
+			}
+			if mq != nil {
+				return mq.placeholder(pkg)
 			}
 			return pkg.Name
 
@@ -174,12 +437,49 @@ func pkgqual(pkg *Pkg, verb rune, mode fmtMode) string {
 		case fmtTypeID:
 			// (methodsym), typesym, weaksym
 			return pkg.Prefix
+
+		case fmtTypeIDStable:
+			// Neither `"".` nor a prefix rewritten by -pkgpathmap.
+			if pkg == LocalPkg {
+				if base.Ctxt == nil || base.Ctxt.Pkgpath == "" {
+					return pkg.Prefix
+				}
+				return objabi.PathToPrefix(base.Ctxt.Pkgpath)
+			}
+			if strings.HasPrefix(pkg.Path, "go.") {
+				return pkg.Path
+			}
+			return objabi.PathToPrefix(pkg.Path)
 		}
 	}
 
 	return ""
 }
 
+// userPkgPath returns the import path by which users refer to the
+// package with the given path: without the vendor directory prefix of
+// vendored packages, and without the version suffix of paths into the
+// module cache.
+func userPkgPath(path string) string {
+	if i := strings.LastIndex(path, "/vendor/"); i >= 0 {
+		path = path[i+len("/vendor/"):]
+	} else if strings.HasPrefix(path, "vendor/") {
+		path = path[len("vendor/"):]
+	}
+
+	// Strip "@version" from the module path element, e.g.
+	// "example.com/mod@v1.2.3/pkg" becomes "example.com/mod/pkg".
+	if i := strings.IndexByte(path, '@'); i >= 0 {
+		j := strings.IndexByte(path[i:], '/')
+		if j < 0 {
+			path = path[:i]
+		} else {
+			path = path[:i] + path[i+j:]
+		}
+	}
+	return path
+}
+
 // Type
 
 var BasicTypeNames = []string{
@@ -206,34 +506,48 @@ var BasicTypeNames = []string{
 	TBLANK:      "blank",
 }
 
-var fmtBufferPool = sync.Pool{
-	New: func() interface{} {
-		return new(bytes.Buffer)
-	},
-}
-
 // Format implements formatting for a Type.
 // The valid formats are:
 //
 //	%v	Go syntax
 //	%+v	Debug syntax: Go syntax with a KIND- prefix for all but builtins.
+//		Structs whose size has been calculated also show each field's
+//		offset (as off=N) and the struct's size and alignment.
 //	%L	Go syntax for underlying type if t is named
 //	%S	short Go syntax: drop leading "func" in function type
 //	%-S	special case for method receiver symbol
 //
 func (t *Type) Format(s fmt.State, verb rune) {
-	mode := fmtGo
 	switch verb {
 	case 'v', 'S', 'L':
-		if verb == 'v' && s.Flag('+') { // %+v is debug format
-			mode = fmtDebug
-		}
-		if verb == 'S' && s.Flag('-') { // %-S is special case for receiver - short typeid format
-			mode = fmtTypeID
+		t.format(s, verb, fmtState{})
+	default:
+		base.BadVerb(s, verb, fmt.Sprintf("%%!%c(*Type=%p)", verb, t))
+	}
+}
+
+// format implements Format for its valid verbs, in state st.
+func (t *Type) format(s fmt.State, verb rune, st fmtState) {
+	mode := fmtGo
+	if verb == 'v' && s.Flag('+') { // %+v is debug format
+		mode = fmtDebug
+	}
+	if verb == 'S' && s.Flag('-') { // %-S is special case for receiver - short typeid format
+		mode = fmtTypeID
+	}
+	switch {
+	case st.bestEffort:
+		b := bestEffortTconv(nil, t, verb, mode, st)
+		if st.qual != nil && verb == 'v' && mode == fmtGo {
+			b = st.mentions.mention(b, t, st)
+			b = msgAbbrev(b, t)
 		}
-		fmt.Fprint(s, tconv(t, verb, mode))
+		s.Write(b)
+	case st.qual != nil:
+		// Don't intern placeholders.
+		s.Write(tconv2(nil, t, verb, mode, st))
 	default:
-		fmt.Fprintf(s, "%%!%c(*Type=%p)", verb, t)
+		fmt.Fprint(s, tconv(t, verb, mode))
 	}
 }
 
@@ -259,82 +573,277 @@ func (t *Type) String() string {
 // use as a map key to implement a type-identity-keyed map. However,
 // make sure all LinkString calls used for this purpose happen within
 // the same compile process; the string keys are not stable across
-// multiple processes.
+// multiple processes. Use StableLinkString for that.
 func (t *Type) LinkString() string {
 	return tconv(t, 0, fmtTypeID)
 }
 
+// StableLinkString returns the expanded form of t's LinkString: it
+// qualifies identifiers from the current package by the package's
+// path, and identifiers from all packages by their import paths even
+// under -pkgpathmap. Identical types thus have the same description in
+// every compile process, which makes it suitable as a key for build
+// caches and indexers that record type identity across packages.
+//
+// Without a package path (-p), the current package is still written
+// as `"".`.
+func (t *Type) StableLinkString() string {
+	return tconv(t, 0, fmtTypeIDStable)
+}
+
 // NameString generates a user-readable, mostly unique string
 // description of t. NameString always returns the same description
 // for identical types, even across compilation units.
 //
 // NameString qualifies identifiers by package name, so it has
 // collisions when different packages share the same names and
-// identifiers. It also does not distinguish function-scope defined
-// types from package-scoped defined types or from each other.
+// identifiers. Unless NameVargen is set, it also does not distinguish
+// function-scope defined types from package-scoped defined types or
+// from each other.
 func (t *Type) NameString() string {
 	return tconv(t, 0, fmtTypeIDName)
 }
 
+// minAbbrevName is the smallest name budget abbrevName honors.
+const minAbbrevName = 32
+
+// abbrevName limits the length of the description b of t to max bytes
+// (or minAbbrevName, if that is larger), and reports whether it had to.
+// Longer descriptions are abbreviated to a prefix followed by "...#"
+// and the type's TypeHash, so that they remain mostly unique. The
+// prefix ends neither within a rune nor within a msgQualifier
+// placeholder. If max <= 0, the description is never abbreviated.
+func abbrevName(b []byte, t *Type, max int) ([]byte, bool) {
+	if max <= 0 {
+		return b, false
+	}
+	if max < minAbbrevName {
+		max = minAbbrevName
+	}
+	if len(b) <= max {
+		return b, false
+	}
+
+	suffix := fmt.Sprintf("...#%08x", TypeHash(t))
+	n := max - len(suffix)
+	for n > 0 && !utf8.RuneStart(b[n]) {
+		n--
+	}
+	if strings.Count(string(b[:n]), string(msgQualMark))%2 != 0 {
+		n = strings.LastIndexByte(string(b[:n]), msgQualMark)
+	}
+	return append(b[:n:n], suffix...), true
+}
+
+// msgAbbrev abbreviates the description b of t in a diagnostic to the
+// budget that -d=namebudget sets, and indexes t by its hash, so that
+// -d=findtype finds it from the abbreviation. If t is too malformed
+// to hash, msgAbbrev leaves b alone.
+func msgAbbrev(b []byte, t *Type) (out []byte) {
+	if base.Debug.NameBudget <= 0 {
+		return b
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			out = b
+		}
+	}()
+	out, ok := abbrevName(b, t, base.Debug.NameBudget)
+	if ok {
+		indexTypeHash(t)
+	}
+	return out
+}
+
+// A Mode selects one of the string representations of a type.
+type Mode uint8
+
+const (
+	GoMode         Mode = iota // Go syntax, as returned by String
+	DebugMode                  // debug syntax, as printed by %+v
+	LinkMode                   // as returned by LinkString
+	NameMode                   // as returned by NameString
+	StableLinkMode             // as returned by StableLinkString
+)
+
+var modeFmt = [...]fmtMode{
+	GoMode:         fmtGo,
+	DebugMode:      fmtDebug,
+	LinkMode:       fmtTypeID,
+	NameMode:       fmtTypeIDName,
+	StableLinkMode: fmtTypeIDStable,
+}
+
+// AppendString appends the string representation of t selected by
+// mode to buf and returns the extended buffer. Unlike String and its
+// relatives, it neither allocates a string nor interns the result,
+// so it suits callers that only need the text transiently, e.g. to
+// look up a symbol with Pkg.LookupBytes. Callers should reuse buf
+// across calls: the formatter is recursive, so buf escapes.
+func (t *Type) AppendString(buf []byte, mode Mode) []byte {
+	return tconv2(buf, t, 0, modeFmt[mode], fmtState{})
+}
+
+// fmtBufferPool holds buffers for tconv. The formatter's recursion
+// makes its buffer escape, so reusing buffers avoids allocating one
+// on every call.
+var fmtBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new([]byte)
+	},
+}
+
 func tconv(t *Type, verb rune, mode fmtMode) string {
-	buf := fmtBufferPool.Get().(*bytes.Buffer)
-	buf.Reset()
-	defer fmtBufferPool.Put(buf)
+	buf := fmtBufferPool.Get().(*[]byte)
+	b := tconv2((*buf)[:0], t, verb, mode, fmtState{})
+	s := InternString(b)
+	*buf = b
+	fmtBufferPool.Put(buf)
+	return s
+}
 
-	tconv2(buf, t, verb, mode, nil)
-	return InternString(buf.Bytes())
+// JoinTypes returns the representations of the types ts, separated by
+// sep. The verb selects the representation as for (*Type).Format:
+// 'v', 'S' or 'L', or '+' for the debug syntax printed by %+v.
+//
+// JoinTypes formats all of ts into one buffer, sharing the
+// formatter's state between them, so it is cheaper than joining the
+// types' Strings. Unlike String, it does not intern its result.
+func JoinTypes(ts []*Type, sep string, verb rune) string {
+	mode := fmtGo
+	switch verb {
+	case 'v', 'S', 'L':
+	case '+':
+		verb, mode = 'v', fmtDebug
+	default:
+		base.Fatalf("JoinTypes: bad verb %q", verb)
+	}
+	buf := fmtBufferPool.Get().(*[]byte)
+	b := joinTypes((*buf)[:0], ts, sep, verb, mode, fmtState{})
+	s := string(b)
+	*buf = b
+	fmtBufferPool.Put(buf)
+	return s
+}
+
+// joinTypes appends the representations of ts, separated by sep, to b
+// and returns the extended buffer.
+func joinTypes(b []byte, ts []*Type, sep string, verb rune, mode fmtMode, st fmtState) []byte {
+	top := st.visited == nil
+	if top && len(ts) > 1 {
+		// Share one map between the types. tconv2 removes each
+		// type from it when it is done, so it is empty between them.
+		st.visited = map[*Type]int{}
+	}
+	for i, t := range ts {
+		if i > 0 {
+			b = append(b, sep...)
+		}
+		if top {
+			st.start = len(b)
+		}
+		b = tconv2(b, t, verb, mode, st)
+	}
+	return b
 }
 
-// tconv2 writes a string representation of t to b.
+// A fmtState holds the state of a call of tconv2, which it passes on
+// to the calls it makes recursively.
+type fmtState struct {
+	visited    map[*Type]int // types being printed; see tconv2
+	qual       *msgQualifier // qualifier of the message being formatted, if any
+	bestEffort bool          // format malformed types; see bestEffortTconv
+	mentions   *Mentions     // types mentioned in the message being formatted, if any
+	start      int           // offset in the buffer of the outermost type; see backref
+}
+
+// shapeNameString appends the representation in mode (fmtTypeIDName
+// for NameString, or fmtGo for the user) of the named type t, which is
+// or is instantiated with a shape type, to b and returns the extended
+// buffer. Shape types are written in the readable form that tracebacks
+// use for them, as in shape:int (see objabi.CutShape), and so are the
+// shape type arguments of instantiated types, whose symbol names embed
+// the shapes' LinkStrings, as in L[shape:int].
+func shapeNameString(b []byte, t *Type, verb rune, mode fmtMode, st fmtState) []byte {
+	if t.IsShape() {
+		b = append(b, objabi.ShapeReadablePrefix...)
+		return tconv2(b, t.Underlying(), 0, mode, st)
+	}
+	sym := t.Sym()
+	i := strings.IndexByte(sym.Name, '[')
+	if i < 0 || len(t.RParams()) == 0 {
+		return sconv2(b, sym, verb, mode, st.qual)
+	}
+	b = sconv2(b, &Sym{Pkg: sym.Pkg, Name: sym.Name[:i]}, verb, mode, st.qual)
+	b = append(b, '[')
+	b = joinTypes(b, t.RParams(), ",", 0, mode, st)
+	return append(b, ']')
+}
+
+// bestEffortTconv is tconv2 on a best-effort basis, as st asks for.
+// If t turns out to be malformed, for example because the compiler is
+// reporting an internal error about a half-built type, it writes a
+// placeholder instead of crashing: for the part of t that it knows to
+// be malformed, or else for all of t.
+func bestEffortTconv(b []byte, t *Type, verb rune, mode fmtMode, st fmtState) (out []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = malformed(b, t, r)
+		}
+	}()
+	return tconv2(b, t, verb, mode, st)
+}
+
+// malformed appends a placeholder for the malformed type t, which
+// could not be formatted because of err, to b and returns the extended
+// buffer.
+func malformed(b []byte, t *Type, err interface{}) []byte {
+	b = append(b, "<malformed "...)
+	b = append(b, t.kind.String()...)
+	b = append(b, ": "...)
+	b = append(b, fmt.Sprint(err)...)
+	return append(b, '>')
+}
+
+// tconv2 appends a string representation of t to b and returns the
+// extended buffer.
 // flag and mode control exactly what is printed.
-// Any types x that are already in the visited map get printed as @%d where %d=visited[x].
+// Any types x that are already in st.visited are printed as a
+// back-reference to st.visited[x] instead; see backref.
 // See #16897 before changing the implementation of tconv.
-func tconv2(b *bytes.Buffer, t *Type, verb rune, mode fmtMode, visited map[*Type]int) {
-	if off, ok := visited[t]; ok {
+func tconv2(b []byte, t *Type, verb rune, mode fmtMode, st fmtState) []byte {
+	if ref, ok := st.visited[t]; ok {
 		// We've seen this type before, so we're trying to print it recursively.
 		// Print a reference to it instead.
-		fmt.Fprintf(b, "@%d", off)
-		return
+		return backref(b, ref, mode)
 	}
 	if t == nil {
-		b.WriteString("<T>")
-		return
+		return append(b, "<T>"...)
 	}
 	if t.Kind() == TSSA {
-		b.WriteString(t.extra.(string))
-		return
+		return append(b, t.extra.(string)...)
 	}
 	if t.Kind() == TTUPLE {
-		b.WriteString(t.FieldType(0).String())
-		b.WriteByte(',')
-		b.WriteString(t.FieldType(1).String())
-		return
+		st.visited = nil
+		return joinTypes(b, []*Type{t.FieldType(0), t.FieldType(1)}, ",", 0, fmtGo, st)
 	}
 
 	if t.Kind() == TRESULTS {
-		tys := t.extra.(*Results).Types
-		for i, et := range tys {
-			if i > 0 {
-				b.WriteByte(',')
-			}
-			b.WriteString(et.String())
-		}
-		return
+		st.visited = nil
+		return joinTypes(b, t.extra.(*Results).Types, ",", 0, fmtGo, st)
 	}
 
 	if t == ByteType || t == RuneType {
 		// in %-T mode collapse rune and byte with their originals.
 		switch mode {
-		case fmtTypeIDName, fmtTypeID:
+		case fmtTypeIDName, fmtTypeID, fmtTypeIDStable:
 			t = Types[t.Kind()]
 		default:
-			sconv2(b, t.Sym(), 'S', mode)
-			return
+			return sconv2(b, t.Sym(), 'S', mode, st.qual)
 		}
 	}
 	if t == ErrorType {
-		b.WriteString("error")
-		return
+		return append(b, "error"...)
 	}
 
 	// Unless the 'L' flag was specified, if the type has a name, just print that name.
@@ -344,11 +853,15 @@ func tconv2(b *bytes.Buffer, t *Type, verb rune, mode fmtMode, visited map[*Type
 			verb = 'v'
 		}
 
+		if (mode == fmtTypeIDName || mode == fmtGo) && t.HasShape() {
+			return shapeNameString(b, t, verb, mode, st)
+		}
+
 		// In unified IR, function-scope defined types will have a ·N
 		// suffix embedded directly in their Name. Trim this off for
-		// non-fmtTypeID modes.
+		// modes that don't write it.
 		sym := t.Sym()
-		if mode != fmtTypeID {
+		if !mode.hasVargen() {
 			i := len(sym.Name)
 			for i > 0 && sym.Name[i-1] >= '0' && sym.Name[i-1] <= '9' {
 				i--
@@ -358,16 +871,16 @@ func tconv2(b *bytes.Buffer, t *Type, verb rune, mode fmtMode, visited map[*Type
 				sym = &Sym{Pkg: sym.Pkg, Name: sym.Name[:i-len(dot)]}
 			}
 		}
-		sconv2(b, sym, verb, mode)
-
-		// TODO(mdempsky): Investigate including Vargen in fmtTypeIDName
-		// output too. It seems like it should, but that mode is currently
-		// used in string representation used by reflection, which is
-		// user-visible and doesn't expect this.
-		if mode == fmtTypeID && t.vargen != 0 {
-			fmt.Fprintf(b, "·%d", t.vargen)
+		b = sconv2(b, sym, verb, mode, st.qual)
+
+		// fmtTypeIDName includes Vargen only under NameVargen, as
+		// that mode is used in the string representation used by
+		// reflection, which is user-visible and doesn't expect this.
+		if mode.hasVargen() && t.vargen != 0 {
+			b = append(b, "·"...)
+			b = strconv.AppendInt(b, int64(t.vargen), 10)
 		}
-		return
+		return b
 	}
 
 	if int(t.Kind()) < len(BasicTypeNames) && BasicTypeNames[t.Kind()] != "" {
@@ -388,135 +901,153 @@ func tconv2(b *bytes.Buffer, t *Type, verb rune, mode fmtMode, visited map[*Type
 		default:
 			name = BasicTypeNames[t.Kind()]
 		}
-		b.WriteString(name)
-		return
+		return append(b, name...)
 	}
 
 	if mode == fmtDebug {
-		b.WriteString(t.Kind().String())
-		b.WriteByte('-')
-		tconv2(b, t, 'v', fmtGo, visited)
-		return
+		b = append(b, t.Kind().String()...)
+		b = append(b, '-')
+		if !t.IsStruct() || t.StructType().Funarg != FunargNone || t.StructType().Map != nil {
+			return tconv2(b, t, 'v', fmtGo, st)
+		}
+		// Plain structs are printed below, annotated with their layout.
 	}
 
 	// At this point, we might call tconv2 recursively. Add the current type to the visited list so we don't
 	// try to print it recursively.
-	// We record the offset in the result buffer where the type's text starts. This offset serves as a reference
-	// point for any later references to the same type.
+	// In fmtTypeID mode, we record the offset where the type's text starts, relative to st.start.
+	// This offset serves as a reference point for any later references to the same type.
+	// In all other modes, we record the type's nesting depth instead, so that references
+	// don't change whenever the text printed before them does.
 	// Note that we remove the type from the visited map as soon as the recursive call is done.
-	// This prevents encoding types like map[*int]*int as map[*int]@4. (That encoding would work,
-	// but I'd like to use the @ notation only when strictly necessary.)
-	if visited == nil {
-		visited = map[*Type]int{}
+	// This prevents encoding types like map[*int]*int as map[*int]#2. (That encoding would work,
+	// but I'd like to use back-references only when strictly necessary.)
+	if st.visited == nil {
+		st.visited = map[*Type]int{}
+		st.start = len(b)
+	}
+	if mode.isTypeID() {
+		st.visited[t] = len(b) - st.start
+	} else {
+		st.visited[t] = len(st.visited) + 1
 	}
-	visited[t] = b.Len()
-	defer delete(visited, t)
+	defer delete(st.visited, t)
 
 	switch t.Kind() {
 	case TPTR:
-		b.WriteByte('*')
+		b = append(b, '*')
 		switch mode {
-		case fmtTypeID, fmtTypeIDName:
+		case fmtTypeID, fmtTypeIDName, fmtTypeIDStable:
 			if verb == 'S' {
-				tconv2(b, t.Elem(), 'S', mode, visited)
-				return
+				return tconv2(b, t.Elem(), 'S', mode, st)
 			}
 		}
-		tconv2(b, t.Elem(), 'v', mode, visited)
+		b = tconv2(b, t.Elem(), 'v', mode, st)
 
 	case TARRAY:
-		b.WriteByte('[')
-		b.WriteString(strconv.FormatInt(t.NumElem(), 10))
-		b.WriteByte(']')
-		tconv2(b, t.Elem(), 0, mode, visited)
+		b = append(b, '[')
+		b = strconv.AppendInt(b, t.NumElem(), 10)
+		b = append(b, ']')
+		b = tconv2(b, t.Elem(), 0, mode, st)
 
 	case TSLICE:
-		b.WriteString("[]")
-		tconv2(b, t.Elem(), 0, mode, visited)
+		b = append(b, "[]"...)
+		b = tconv2(b, t.Elem(), 0, mode, st)
 
 	case TCHAN:
 		switch t.ChanDir() {
 		case Crecv:
-			b.WriteString("<-chan ")
-			tconv2(b, t.Elem(), 0, mode, visited)
+			b = append(b, "<-chan "...)
+			b = tconv2(b, t.Elem(), 0, mode, st)
 		case Csend:
-			b.WriteString("chan<- ")
-			tconv2(b, t.Elem(), 0, mode, visited)
+			b = append(b, "chan<- "...)
+			b = tconv2(b, t.Elem(), 0, mode, st)
 		default:
-			b.WriteString("chan ")
+			b = append(b, "chan "...)
 			if t.Elem() != nil && t.Elem().IsChan() && t.Elem().Sym() == nil && t.Elem().ChanDir() == Crecv {
-				b.WriteByte('(')
-				tconv2(b, t.Elem(), 0, mode, visited)
-				b.WriteByte(')')
+				b = append(b, '(')
+				b = tconv2(b, t.Elem(), 0, mode, st)
+				b = append(b, ')')
 			} else {
-				tconv2(b, t.Elem(), 0, mode, visited)
+				b = tconv2(b, t.Elem(), 0, mode, st)
 			}
 		}
 
 	case TMAP:
-		b.WriteString("map[")
-		tconv2(b, t.Key(), 0, mode, visited)
-		b.WriteByte(']')
-		tconv2(b, t.Elem(), 0, mode, visited)
+		b = append(b, "map["...)
+		b = tconv2(b, t.Key(), 0, mode, st)
+		b = append(b, ']')
+		b = tconv2(b, t.Elem(), 0, mode, st)
 
 	case TINTER:
-		if t.IsEmptyInterface() {
-			b.WriteString("interface {}")
-			break
-		}
-		b.WriteString("interface {")
-		for i, f := range t.AllMethods().Slice() {
-			if i != 0 {
-				b.WriteByte(';')
+		methods, elems := fmtAllMethods(t, st).Slice(), typeSetElems(t)
+		typefmt.Composite(typefmt.Compiler, "interface", len(methods)+len(elems), func(s string) { b = append(b, s...) }, func(i int) {
+			if i >= len(methods) {
+				b = tconv2(b, elems[i-len(methods)].Type, 0, mode, st)
+				return
 			}
-			b.WriteByte(' ')
+			f := methods[i]
 			switch {
 			case f.Sym == nil:
 				// Check first that a symbol is defined for this type.
 				// Wrong interface definitions may have types lacking a symbol.
 				break
 			case IsExported(f.Sym.Name):
-				sconv2(b, f.Sym, 'S', mode)
+				b = sconv2(b, f.Sym, 'S', mode, st.qual)
 			default:
-				if mode != fmtTypeIDName {
+				if mode != fmtTypeIDName && mode != fmtTypeIDStable {
 					mode = fmtTypeID
 				}
-				sconv2(b, f.Sym, 'v', mode)
+				b = sconv2(b, f.Sym, 'v', mode, st.qual)
 			}
-			tconv2(b, f.Type, 'S', mode, visited)
-		}
-		if t.AllMethods().Len() != 0 {
-			b.WriteByte(' ')
-		}
-		b.WriteByte('}')
+			b = tconv2(b, f.Type, 'S', mode, st)
+		})
 
 	case TFUNC:
 		if verb == 'S' {
 			// no leading func
 		} else {
 			if t.Recv() != nil {
-				b.WriteString("method")
-				tconv2(b, t.Recvs(), 0, mode, visited)
-				b.WriteByte(' ')
+				b = append(b, "method"...)
+				b = tconv2(b, t.Recvs(), 0, mode, st)
+				b = append(b, ' ')
 			}
-			b.WriteString("func")
+			b = append(b, "func"...)
 		}
+		// The type parameters of a generic method (see
+		// GOEXPERIMENT=genericmethods) follow its receiver, as in
+		// "method(T) func[P any](P)". They are numbered in fmtTypeID
+		// mode like those of a function; the receiver's type
+		// parameters are not.
 		if t.NumTParams() > 0 {
-			tconv2(b, t.TParams(), 0, mode, visited)
+			if mode.isTypeID() {
+				// Number the type parameters for the rest of
+				// the signature; see tparamList.
+				for i, f := range t.TParams().FieldSlice() {
+					st.visited[f.Type] = tparamRef(i)
+				}
+			}
+			b = tparamList(b, t.TParams(), mode, st)
 		}
-		tconv2(b, t.Params(), 0, mode, visited)
+		b = tconv2(b, t.Params(), 0, mode, st)
 
 		switch t.NumResults() {
 		case 0:
 			// nothing to do
 
 		case 1:
-			b.WriteByte(' ')
-			tconv2(b, t.Results().Field(0).Type, 0, mode, visited) // struct->field->field's type
+			b = append(b, ' ')
+			b = tconv2(b, t.Results().Field(0).Type, 0, mode, st) // struct->field->field's type
 
 		default:
-			b.WriteByte(' ')
-			tconv2(b, t.Results(), 0, mode, visited)
+			b = append(b, ' ')
+			b = tconv2(b, t.Results(), 0, mode, st)
+		}
+
+		if t.NumTParams() > 0 && mode.isTypeID() {
+			for _, f := range t.TParams().FieldSlice() {
+				delete(st.visited, f.Type)
+			}
 		}
 
 	case TSTRUCT:
@@ -526,17 +1057,20 @@ func tconv2(b *bytes.Buffer, t *Type, verb rune, mode fmtMode, visited map[*Type
 			// This avoids a recursive print that generates very long names.
 			switch t {
 			case mt.Bucket:
-				b.WriteString("map.bucket[")
+				b = append(b, "map.bucket["...)
 			case mt.Hmap:
-				b.WriteString("map.hdr[")
+				b = append(b, "map.hdr["...)
 			case mt.Hiter:
-				b.WriteString("map.iter[")
+				b = append(b, "map.iter["...)
 			default:
+				if st.bestEffort {
+					return malformed(b, t, "unknown internal map type")
+				}
 				base.Fatalf("unknown internal map type")
 			}
-			tconv2(b, m.Key(), 0, mode, visited)
-			b.WriteByte(']')
-			tconv2(b, m.Elem(), 0, mode, visited)
+			b = tconv2(b, m.Key(), 0, mode, st)
+			b = append(b, ']')
+			b = tconv2(b, m.Elem(), 0, mode, st)
 			break
 		}
 
@@ -545,83 +1079,174 @@ func tconv2(b *bytes.Buffer, t *Type, verb rune, mode fmtMode, visited map[*Type
 			if funarg == FunargTparams {
 				open, close = '[', ']'
 			}
-			b.WriteByte(byte(open))
+			b = append(b, byte(open))
 			fieldVerb := 'v'
 			switch mode {
-			case fmtTypeID, fmtTypeIDName, fmtGo:
+			case fmtTypeID, fmtTypeIDName, fmtTypeIDStable, fmtGo:
 				// no argument names on function signature, and no "noescape"/"nosplit" tags
 				fieldVerb = 'S'
 			}
 			for i, f := range t.Fields().Slice() {
 				if i != 0 {
-					b.WriteString(", ")
+					b = append(b, ", "...)
 				}
-				fldconv(b, f, fieldVerb, mode, visited, funarg)
+				b = fldconv(b, f, fieldVerb, mode, st, funarg)
 			}
-			b.WriteByte(byte(close))
+			b = append(b, byte(close))
 		} else {
-			b.WriteString("struct {")
-			for i, f := range t.Fields().Slice() {
-				if i != 0 {
-					b.WriteByte(';')
-				}
-				b.WriteByte(' ')
-				fldconv(b, f, 'L', mode, visited, funarg)
+			// In debug mode, fields are printed as in Go syntax, but
+			// annotated with their offsets once the struct's size has
+			// been calculated.
+			layout := mode == fmtDebug && t.widthCalculated()
+			fmode := mode
+			if mode == fmtDebug {
+				fmode = fmtGo
 			}
-			if t.NumFields() != 0 {
-				b.WriteByte(' ')
+			fields := t.Fields().Slice()
+			typefmt.Composite(typefmt.Compiler, "struct", len(fields), func(s string) { b = append(b, s...) }, func(i int) {
+				b = fldconv(b, fields[i], 'L', fmode, st, funarg)
+				if layout {
+					b = append(b, " off="...)
+					b = strconv.AppendInt(b, fields[i].Offset, 10)
+				}
+			})
+			if layout {
+				b = append(b, " size="...)
+				b = strconv.AppendInt(b, t.width, 10)
+				b = append(b, " align="...)
+				b = strconv.AppendInt(b, int64(t.align), 10)
 			}
-			b.WriteByte('}')
 		}
 
 	case TFORW:
-		b.WriteString("undefined")
+		b = append(b, "undefined"...)
 		if t.Sym() != nil {
-			b.WriteByte(' ')
-			sconv2(b, t.Sym(), 'v', mode)
+			b = append(b, ' ')
+			b = sconv2(b, t.Sym(), 'v', mode, st.qual)
 		}
 
 	case TUNSAFEPTR:
-		b.WriteString("unsafe.Pointer")
+		b = append(b, "unsafe.Pointer"...)
 
 	case TTYPEPARAM:
 		if t.Sym() != nil {
-			sconv2(b, t.Sym(), 'v', mode)
+			b = sconv2(b, t.Sym(), 'v', mode, st.qual)
 		} else {
-			b.WriteString("tp")
+			b = append(b, "tp"...)
 			// Print out the pointer value for now to disambiguate type params
-			b.WriteString(fmt.Sprintf("%p", t))
+			b = append(b, fmt.Sprintf("%p", t)...)
 		}
 
 	case TUNION:
 		for i := 0; i < t.NumTerms(); i++ {
 			if i > 0 {
-				b.WriteString("|")
+				b = append(b, '|')
 			}
 			elem, tilde := t.Term(i)
 			if tilde {
-				b.WriteString("~")
+				b = append(b, '~')
 			}
-			tconv2(b, elem, 0, mode, visited)
+			b = tconv2(b, elem, 0, mode, st)
 		}
 
 	case Txxx:
-		b.WriteString("Txxx")
+		b = append(b, "Txxx"...)
 
 	default:
 		// Don't know how to handle - fall back to detailed prints
-		b.WriteString(t.Kind().String())
-		b.WriteString(" <")
-		sconv2(b, t.Sym(), 'v', mode)
-		b.WriteString(">")
+		b = append(b, t.Kind().String()...)
+		b = append(b, " <"...)
+		b = sconv2(b, t.Sym(), 'v', mode, st.qual)
+		b = append(b, '>')
 
 	}
+	return b
 }
 
-func fldconv(b *bytes.Buffer, f *Field, verb rune, mode fmtMode, visited map[*Type]int, funarg Funarg) {
+// typeSetElems returns the embedded elements of the interface t that
+// are not interfaces, such as unions and the core types of
+// constraints. They restrict t's type set, but are not part of its
+// method set.
+func typeSetElems(t *Type) []*Field {
+	var elems []*Field
+	for _, f := range t.Methods().Slice() {
+		// A broken element is already among t's methods; see expandiface.
+		if f.Sym == nil && f.Type != nil && !f.Type.IsInterface() && !f.Broke() {
+			elems = append(elems, f)
+		}
+	}
+	return elems
+}
+
+// tparamList appends the type parameter list of a generic function
+// type to b, given the function's type parameter struct.
+//
+// In fmtTypeID mode, the list is enclosed in braces rather than
+// brackets, which symbol names reserve for the type arguments of
+// instantiations, and the type parameters are numbered by position
+// ($0, $1, ...) rather than named, both in the list and in the rest of
+// the signature. Thus generic signatures that differ only in the names
+// of their type parameters have the same encoding, for example
+// "func{$0 any, $1 comparable}($0) $1".
+//
+// In all other modes, the list is written as in Go syntax, with each
+// type parameter's constraint, for example "func[T any, U comparable](T) U".
+func tparamList(b []byte, tparams *Type, mode fmtMode, st fmtState) []byte {
+	open, close := byte('['), byte(']')
+	if mode.isTypeID() {
+		open, close = '{', '}'
+	}
+	b = append(b, open)
+	for i, f := range tparams.FieldSlice() {
+		if i != 0 {
+			b = append(b, ", "...)
+		}
+		b = tconv2(b, f.Type, 0, mode, st)
+		if f.Type.IsTypeParam() && f.Type.Bound() != nil {
+			b = append(b, ' ')
+			b = tconv2(b, f.Type.Bound(), 0, mode, st)
+		}
+	}
+	return append(b, close)
+}
+
+// tparamRef returns the visited map entry by which tconv2 refers to
+// the i'th type parameter of a signature in fmtTypeID mode.
+// Such references are negative, unlike the offsets of enclosing types.
+func tparamRef(i int) int {
+	return -1 - i
+}
+
+// backref writes a reference to a type that is already being printed
+// further up the stack, as recorded in tconv2's st.visited.
+//
+// In fmtTypeID mode, ref is the type's byte offset in the output,
+// relative to the start of the outermost type, and the reference is
+// written as @%d. This matches the historical encoding used in link
+// symbols and must not change; in particular, it must not depend on
+// text the caller has already put in the buffer, such as the prefix
+// of TypeSymName.
+//
+// In all other modes, ref is the nesting depth of the enclosing type
+// (1 for the outermost type) and the reference is written as #%d,
+// e.g. "struct { next *#1 }".
+func backref(b []byte, ref int, mode fmtMode) []byte {
+	if ref < 0 {
+		// A numbered type parameter; see tparamList.
+		b = append(b, '$')
+		return strconv.AppendInt(b, int64(-1-ref), 10)
+	}
+	if mode.isTypeID() {
+		b = append(b, '@')
+	} else {
+		b = append(b, '#')
+	}
+	return strconv.AppendInt(b, int64(ref), 10)
+}
+
+func fldconv(b []byte, f *Field, verb rune, mode fmtMode, st fmtState, funarg Funarg) []byte {
 	if f == nil {
-		b.WriteString("<T>")
-		return
+		return append(b, "<T>"...)
 	}
 
 	var name string
@@ -642,17 +1267,17 @@ func fldconv(b *bytes.Buffer, f *Field, verb rune, mode fmtMode, visited map[*Ty
 					name = "F" // Hack for toolstash -cmp.
 				}
 				if !IsExported(name) && mode != fmtTypeIDName {
-					name = sconv(s, 0, mode) // qualify non-exported names (used on structs, not on funarg)
+					name = sconv(s, 0, mode, st.qual) // qualify non-exported names (used on structs, not on funarg)
 				}
 			} else {
-				name = sconv(s, 0, mode)
+				name = sconv(s, 0, mode, st.qual)
 			}
 		}
 	}
 
 	if name != "" {
-		b.WriteString(name)
-		b.WriteString(" ")
+		b = append(b, name...)
+		b = append(b, ' ')
 	}
 
 	if f.IsDDD() {
@@ -660,21 +1285,37 @@ func fldconv(b *bytes.Buffer, f *Field, verb rune, mode fmtMode, visited map[*Ty
 		if f.Type != nil {
 			et = f.Type.Elem()
 		}
-		b.WriteString("...")
-		tconv2(b, et, 0, mode, visited)
+		b = append(b, "..."...)
+		b = tconv2(b, et, 0, mode, st)
 	} else {
-		tconv2(b, f.Type, 0, mode, visited)
+		b = tconv2(b, f.Type, 0, mode, st)
 	}
 
 	if verb != 'S' && funarg == FunargNone && f.Note != "" {
-		b.WriteString(" ")
-		b.WriteString(strconv.Quote(f.Note))
+		b = append(b, ' ')
+		b = strconv.AppendQuote(b, f.Note)
 	}
+	return b
 }
 
 // Val
 
+// FmtConst returns the Go syntax for the constant value v.
+//
+// If sharp is set (as for %#v and in debug dumps), floating-point
+// values, including both parts of complex values, are printed exactly
+// instead of rounded: as a fraction if v is represented as a rational
+// number, and as a hexadecimal float otherwise.
 func FmtConst(v constant.Value, sharp bool) string {
+	if sharp {
+		switch v.Kind() {
+		case constant.Float:
+			return exactFloat(v)
+		case constant.Complex:
+			return fmt.Sprintf("(%s + %si)", exactFloat(constant.Real(v)), exactFloat(constant.Imag(v)))
+		}
+	}
+
 	if !sharp && v.Kind() == constant.Complex {
 		real, imag := constant.Real(v), constant.Imag(v)
 
@@ -707,6 +1348,17 @@ func FmtConst(v constant.Value, sharp bool) string {
 	return v.String()
 }
 
+// exactFloat returns an exact representation of the float constant v.
+func exactFloat(v constant.Value) string {
+	switch x := constant.Val(v).(type) {
+	case *big.Rat:
+		return x.RatString()
+	case *big.Float:
+		return x.Text('x', -1)
+	}
+	return v.String()
+}
+
 // TypeHash computes a hash value for type t to use in type switch statements.
 func TypeHash(t *Type) uint32 {
 	p := t.NameString()
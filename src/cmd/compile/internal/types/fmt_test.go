@@ -0,0 +1,470 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/typecheck"
+	"cmd/compile/internal/types"
+	"cmd/internal/obj"
+	"cmd/internal/src"
+)
+
+func init() {
+	types.PtrSize = 8
+	types.RegSize = 8
+	types.MaxWidth = 1 << 50
+
+	types.LocalPkg = types.NewPkg("", "")
+	typecheck.InitUniverse()
+}
+
+func TestDebugStructLayout(t *testing.T) {
+	pkg := types.NewPkg("p", "p")
+	st := types.NewStruct(pkg, []*types.Field{
+		types.NewField(src.NoXPos, pkg.Lookup("A"), types.Types[types.TUINT8]),
+		types.NewField(src.NoXPos, pkg.Lookup("B"), types.Types[types.TINT64]),
+		types.NewField(src.NoXPos, pkg.Lookup("C"), types.Types[types.TUINT16]),
+	})
+
+	if got, want := fmt.Sprintf("%+v", st), "STRUCT-struct { A uint8; B int64; C uint16 }"; got != want {
+		t.Errorf("before CalcSize: got %q, want %q", got, want)
+	}
+
+	types.CalcSize(st)
+	if got, want := fmt.Sprintf("%+v", st), "STRUCT-struct { A uint8 off=0; B int64 off=8; C uint16 off=16 } size=24 align=8"; got != want {
+		t.Errorf("after CalcSize: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", st), "struct { A uint8; B int64; C uint16 }"; got != want {
+		t.Errorf("%%v: got %q, want %q", got, want)
+	}
+}
+
+func TestFmtConstExact(t *testing.T) {
+	third := constant.BinaryOp(constant.MakeInt64(1), token.QUO, constant.MakeInt64(3))
+	huge := constant.MakeFromLiteral("0x1p5000", token.FLOAT, 0)
+	tests := []struct {
+		v            constant.Value
+		plain, exact string
+	}{
+		{constant.MakeInt64(42), "42", "42"},
+		{constant.MakeFloat64(0.5), "0.5", "1/2"},
+		{constant.MakeFloat64(3), "3", "3"},
+		{constant.ToFloat(third), "0.333333", "1/3"},
+		{huge, "1.41247e+1505", "0x1p+5000"},
+		{constant.MakeImag(constant.MakeFloat64(0.25)), "0.25i", "(0 + 1/4i)"},
+	}
+	for _, test := range tests {
+		if got := types.FmtConst(test.v, false); got != test.plain {
+			t.Errorf("FmtConst(%v, false) = %q, want %q", test.v, got, test.plain)
+		}
+		if got := types.FmtConst(test.v, true); got != test.exact {
+			t.Errorf("FmtConst(%v, true) = %q, want %q", test.v, got, test.exact)
+		}
+	}
+}
+
+func TestQualifiedPkgPath(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"example.com/foo", `"example.com/foo".X`},
+		{"vendor/example.com/foo", `"example.com/foo".X`},
+		{"example.com/app/vendor/example.com/foo", `"example.com/foo".X`},
+		{"example.com/foo@v1.2.3", `"example.com/foo".X`},
+		{"example.com/mod@v1.2.3/foo", `"example.com/mod/foo".X`},
+	}
+
+	other := types.NewPkg("other/foo", "foo").Lookup("Y")
+	for _, test := range tests {
+		sym := types.NewPkg(test.path, "foo").Lookup("X")
+		if got, want := types.FormatMessage(src.NoXPos, "%v and %v", sym, other), test.want+` and "other/foo".Y`; got != want {
+			t.Errorf("path %q: got %s, want %s", test.path, got, want)
+		}
+	}
+}
+
+func TestFormatMessage(t *testing.T) {
+	foo1 := types.NewPkg("example.com/one/foo", "foo")
+	foo2 := types.NewPkg("example.com/two/foo", "foo")
+	bar := types.NewPkg("example.com/bar", "bar")
+	x1, y1, x2, b := foo1.Lookup("X"), foo1.Lookup("Y"), foo2.Lookup("X"), bar.Lookup("B")
+	namedType := func(s *types.Sym) *types.Type {
+		obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, s)
+		t := types.NewNamed(obj)
+		obj.SetType(t)
+		t.SetUnderlying(types.Types[types.TINT])
+		return t
+	}
+
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		// Only one package named foo: names suffice.
+		{types.FormatMessage(src.NoXPos, "%v, %v, %v", x1, y1, b), "foo.X, foo.Y, bar.B"},
+		// Two packages named foo: their paths disambiguate them,
+		// including in the mentions before the clash.
+		{types.FormatMessage(src.NoXPos, "%v, %v, %v", x1, b, x2), `"example.com/one/foo".X, bar.B, "example.com/two/foo".X`},
+		// Each message is considered separately.
+		{types.FormatMessage(src.NoXPos, "%v", x2), "foo.X"},
+		// Types refer to packages too.
+		{types.FormatMessage(src.NoXPos, "%v vs %v", types.NewSlice(namedType(x1)), namedType(x2)), `[]"example.com/one/foo".X vs "example.com/two/foo".X`},
+		// So do IR nodes.
+		{types.FormatMessage(src.NoXPos, "%v, %v", ir.NewNameAt(src.NoXPos, x1), ir.NewNameAt(src.NoXPos, x2)), `"example.com/one/foo".X, "example.com/two/foo".X`},
+		{types.FormatMessage(src.NoXPos, "%v, %v", ir.NewNameAt(src.NoXPos, x1), ir.NewNameAt(src.NoXPos, b)), "foo.X, bar.B"},
+		// Messages formatted elsewhere, like those of types2,
+		// qualify packages with Pkg.Qualifier.
+		{types.FormatMessage(src.NoXPos, "%s, %v", foo1.Qualifier()+".X", x2), `"example.com/one/foo".X, "example.com/two/foo".X`},
+		{types.FormatMessage(src.NoXPos, "%s", foo1.Qualifier()+".X"), "foo.X"},
+		// Other arguments are formatted as usual.
+		{types.FormatMessage(src.NoXPos, "%s%v %d", "\x001\x00", x1, 2), "\x001\x00foo.X 2"},
+	}
+	for _, test := range tests {
+		if test.msg != test.want {
+			t.Errorf("got %s, want %s", test.msg, test.want)
+		}
+	}
+	if got, want := fmt.Sprintf("%v %v", x1, x2), "foo.X foo.X"; got != want {
+		t.Errorf("outside FormatMessage: got %s, want %s", got, want)
+	}
+}
+
+func TestFormatBestEffort(t *testing.T) {
+	// A struct that claims to be internal to a map, but is none of
+	// its buckets, header or iterator.
+	bad := types.NewStruct(types.NoPkg, nil)
+	bad.StructType().Map = types.NewMap(types.Types[types.TINT], types.Types[types.TINT])
+
+	want := "[]<malformed STRUCT: unknown internal map type>"
+	if got := types.FormatBestEffort("%v", types.NewSlice(bad)); got != want {
+		t.Errorf("FormatBestEffort: got %s, want %s", got, want)
+	}
+	if got := types.FormatMessage(src.NoXPos, "%v", types.NewSlice(bad)); got != want {
+		t.Errorf("FormatMessage: got %s, want %s", got, want)
+	}
+}
+
+func TestPkgAliases(t *testing.T) {
+	defer func(saved *obj.Link) { base.Ctxt = saved }(base.Ctxt)
+	base.Ctxt = new(obj.Link)
+	posIn := func(file string) src.XPos {
+		return base.Ctxt.PosTable.XPos(src.MakePos(src.NewFileBase(file, file), 1, 1))
+	}
+
+	tmpl := types.NewPkg("text/template", "template")
+	html := types.NewPkg("html/template", "template")
+	tmpl.AddAlias("a.go", "ttmpl")
+	tmpl.AddAlias("b.go", "_")
+	html.AddAlias("a.go", "htmpl")
+	html.AddAlias("c.go", "ttmpl")
+	x, y := tmpl.Lookup("X"), html.Lookup("Y")
+
+	for _, test := range []struct {
+		pkg        *types.Pkg
+		file, want string
+	}{
+		{tmpl, "a.go", "ttmpl"},
+		{tmpl, "b.go", "template"},
+		{tmpl, "c.go", "template"},
+		{html, "a.go", "htmpl"},
+		{html, "c.go", "ttmpl"},
+	} {
+		if got := test.pkg.AliasIn(test.file); got != test.want {
+			t.Errorf("%s.AliasIn(%q) = %q, want %q", test.pkg.Path, test.file, got, test.want)
+		}
+	}
+
+	for _, test := range []struct {
+		pos  src.XPos
+		want string
+	}{
+		// Each file refers to the packages by its own names.
+		{posIn("a.go"), "ttmpl.X, htmpl.Y"},
+		// Blank imports do not name the package.
+		{posIn("b.go"), `"text/template".X, "html/template".Y`},
+		// Aliases can make the names unambiguous.
+		{posIn("c.go"), "template.X, ttmpl.Y"},
+		// Without a position, packages are referred to by name.
+		{src.NoXPos, `"text/template".X, "html/template".Y`},
+	} {
+		if got := types.FormatMessage(test.pos, "%v, %v", x, y); got != test.want {
+			t.Errorf("in %s: got %s, want %s", base.FmtPos(test.pos), got, test.want)
+		}
+	}
+}
+
+func TestFullPaths(t *testing.T) {
+	sym := types.NewPkg("example.com/internal/bar", "bar").Lookup("X")
+	if got, want := sym.String(), "bar.X"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	defer func(saved int) { base.Debug.FullPaths = saved }(base.Debug.FullPaths)
+	base.Debug.FullPaths = 1
+	if got, want := sym.String(), `"example.com/internal/bar".X`; got != want {
+		t.Errorf("with -d=fullpaths: got %s, want %s", got, want)
+	}
+}
+
+func TestNameBudget(t *testing.T) {
+	defer func(saved int) { base.Debug.NameBudget = saved }(base.Debug.NameBudget)
+
+	typ := types.Types[types.TINT]
+	for i := 0; i < 8; i++ {
+		typ = types.NewMap(types.Types[types.TSTRING], typ)
+	}
+	full := typ.String()
+	suffix := fmt.Sprintf("...#%08x", types.TypeHash(typ))
+
+	for _, max := range []int{0, len(full)} {
+		base.Debug.NameBudget = max
+		if got := types.FormatMessage(src.NoXPos, "%v", typ); got != full {
+			t.Errorf("-d=namebudget=%d: got %q, want %q", max, got, full)
+		}
+	}
+	base.Debug.NameBudget = 1
+	if got, want := types.FormatMessage(src.NoXPos, "%v", types.NewSlice(types.Types[types.TINT])), "[]int"; got != want {
+		t.Errorf("-d=namebudget=1 with short name: got %q, want %q", got, want)
+	}
+	for _, max := range []int{1, 40, 64} {
+		base.Debug.NameBudget = max
+		got := types.FormatMessage(src.NoXPos, "%v", typ)
+		want := max
+		if want < 32 {
+			want = 32
+		}
+		if len(got) != want || !strings.HasSuffix(got, suffix) || !strings.HasPrefix(full, strings.TrimSuffix(got, suffix)) {
+			t.Errorf("-d=namebudget=%d: got %q, want %d-byte abbreviation of %q", max, got, want, full)
+		}
+		// Only diagnostics are abbreviated; names in the
+		// binary are not.
+		if got := typ.String(); got != full {
+			t.Errorf("-d=namebudget=%d: String = %q, want %q", max, got, full)
+		}
+		if got, want := typ.NameString(), strings.ReplaceAll(full, " ", ""); got != want {
+			t.Errorf("-d=namebudget=%d: NameString = %q, want %q", max, got, want)
+		}
+	}
+
+	// Abbreviations do not cut package qualifiers short.
+	pkg := types.NewPkg("example.com/foo", "foo")
+	obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup("X"))
+	x := types.NewNamed(obj)
+	obj.SetType(x)
+	x.SetUnderlying(types.Types[types.TINT])
+	typ = x
+	for i := 0; i < 8; i++ {
+		typ = types.NewMap(x, typ)
+	}
+	full = typ.String()
+	suffix = fmt.Sprintf("...#%08x", types.TypeHash(typ))
+	for max := 32; max < 48; max++ {
+		base.Debug.NameBudget = max
+		got := types.FormatMessage(src.NoXPos, "%v", typ)
+		if !strings.HasSuffix(got, suffix) || !strings.HasPrefix(full, strings.TrimSuffix(got, suffix)) {
+			t.Errorf("-d=namebudget=%d: got %q, want abbreviation of %q", max, got, full)
+		}
+	}
+}
+
+func TestAppendString(t *testing.T) {
+	pkg := types.NewPkg("r", "r")
+	st := types.NewStruct(pkg, []*types.Field{
+		types.NewField(src.NoXPos, pkg.Lookup("a"), types.NewSlice(types.Types[types.TINT])),
+		types.NewField(src.NoXPos, pkg.Lookup("B"), types.NewMap(types.Types[types.TSTRING], types.NewPtr(types.Types[types.TUINT8]))),
+	})
+	tests := []struct {
+		mode types.Mode
+		want string
+	}{
+		{types.GoMode, st.String()},
+		{types.DebugMode, fmt.Sprintf("%+v", st)},
+		{types.LinkMode, st.LinkString()},
+		{types.NameMode, st.NameString()},
+		{types.StableLinkMode, st.StableLinkString()},
+	}
+	for _, test := range tests {
+		if got := string(st.AppendString([]byte("x:"), test.mode)); got != "x:"+test.want {
+			t.Errorf("AppendString(%d) = %q, want %q", test.mode, got, "x:"+test.want)
+		}
+	}
+
+	buf := st.AppendString(nil, types.LinkMode)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = st.AppendString(buf[:0], types.LinkMode)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendString into a reused buffer: %v allocs, want 0", allocs)
+	}
+}
+
+func TestStableLinkString(t *testing.T) {
+	defer func(saved *obj.Link) { base.Ctxt = saved }(base.Ctxt)
+	base.Ctxt = &obj.Link{Pkgpath: "example.com/main"}
+	defer func(saved func(string) string) { types.PkgPathHook = saved }(types.PkgPathHook)
+	types.PkgPathHook = func(path string) string { return "example.org/renamed" }
+	defer func(saved string) { types.LocalPkg.Prefix = saved }(types.LocalPkg.Prefix)
+	types.LocalPkg.Prefix = `""` // as set by gc.Main
+
+	dep := types.NewPkg("example.com/dep.v2", "dep")
+	typ := types.NewStruct(types.NoPkg, []*types.Field{
+		types.NewField(src.NoXPos, types.LocalPkg.Lookup("x"), types.Types[types.TINT]),
+		types.NewField(src.NoXPos, dep.Lookup("y"), types.Types[types.TSTRING]),
+	})
+	if got, want := typ.LinkString(), `struct { "".x int; example.org/renamed.y string }`; got != want {
+		t.Errorf("LinkString() = %s, want %s", got, want)
+	}
+	if got, want := typ.StableLinkString(), `struct { example.com/main.x int; example.com/dep%2ev2.y string }`; got != want {
+		t.Errorf("StableLinkString() = %s, want %s", got, want)
+	}
+}
+
+// TestBackref checks the references to the enclosing types that a
+// recursive type's description contains: #depth in most modes, and
+// @offset in LinkString.
+func TestBackref(t *testing.T) {
+	pkg := types.NewPkg("r", "r")
+	next := types.NewField(src.NoXPos, pkg.Lookup("next"), nil)
+	list := types.NewStruct(pkg, []*types.Field{
+		types.NewField(src.NoXPos, pkg.Lookup("x"), types.Types[types.TINT]),
+		next,
+	})
+	next.Type = types.NewPtr(list)
+	m := types.NewMap(types.Types[types.TSTRING], list)
+
+	tests := []struct {
+		typ                *types.Type
+		str, link, nameStr string
+	}{
+		{list, "struct { r.x int; r.next *#1 }", "struct { r.x int; r.next *@0 }", "struct { x int; next *#1 }"},
+		{m, "map[string]struct { r.x int; r.next *#2 }", "map[string]struct { r.x int; r.next *@11 }", "map[string]struct { x int; next *#2 }"},
+		{types.NewSlice(m), "[]map[string]struct { r.x int; r.next *#3 }", "[]map[string]struct { r.x int; r.next *@13 }", "[]map[string]struct { x int; next *#3 }"},
+	}
+	for _, test := range tests {
+		if got := test.typ.String(); got != test.str {
+			t.Errorf("String() = %s, want %s", got, test.str)
+		}
+		if got := test.typ.LinkString(); got != test.link {
+			t.Errorf("LinkString() = %s, want %s", got, test.link)
+		}
+		// Back-references do not depend on what precedes the
+		// type in the buffer, as in TypeSymName.
+		if got := string(test.typ.AppendString([]byte("noalg.type."), types.LinkMode)); got != "noalg.type."+test.link {
+			t.Errorf("AppendString(\"noalg.type.\") = %s, want noalg.type.%s", got, test.link)
+		}
+		if got := test.typ.NameString(); got != test.nameStr {
+			t.Errorf("NameString() = %s, want %s", got, test.nameStr)
+		}
+	}
+}
+
+func TestJoinTypes(t *testing.T) {
+	pkg := types.NewPkg("j", "j")
+	st := types.NewStruct(pkg, []*types.Field{
+		types.NewField(src.NoXPos, pkg.Lookup("a"), types.NewSlice(types.Types[types.TINT])),
+	})
+	ts := []*types.Type{types.Types[types.TINT], st, types.NewMap(types.Types[types.TSTRING], st), nil}
+	tests := []struct {
+		verb rune
+		want []string
+	}{
+		{'v', []string{"int", "struct { j.a []int }", "map[string]struct { j.a []int }", "<T>"}},
+		{'+', []string{"int", fmt.Sprintf("%+v", st), fmt.Sprintf("%+v", ts[2]), "<T>"}},
+	}
+	for _, test := range tests {
+		if got, want := types.JoinTypes(ts, ", ", test.verb), strings.Join(test.want, ", "); got != want {
+			t.Errorf("JoinTypes(%c) = %q, want %q", test.verb, got, want)
+		}
+	}
+	if got := types.JoinTypes(nil, ", ", 'v'); got != "" {
+		t.Errorf("JoinTypes(nil) = %q, want empty", got)
+	}
+}
+
+func TestShapeNameString(t *testing.T) {
+	shape := typecheck.Shapify(types.Types[types.TINT], 0)
+	ptrShape := typecheck.Shapify(types.NewPtr(types.Types[types.TSTRING]), 1)
+
+	pkg := types.NewPkg("example.com/l", "l")
+	obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup(typecheck.InstTypeName("L", []*types.Type{shape, ptrShape})))
+	inst := types.NewNamed(obj)
+	obj.SetType(inst)
+	inst.SetUnderlying(types.NewSlice(shape))
+	inst.SetRParams([]*types.Type{shape, ptrShape})
+
+	for _, test := range []struct {
+		typ        *types.Type
+		name, link string
+	}{
+		{shape, "shape:int", "go.shape.int_0"},
+		{ptrShape, "shape:*uint8", "go.shape.*uint8_1"},
+		{types.NewSlice(shape), "[]shape:int", "[]go.shape.int_0"},
+		{inst, "l.L[shape:int,shape:*uint8]", "example.com/l.L[go.shape.int_0,go.shape.*uint8_1]"},
+	} {
+		if got := test.typ.NameString(); got != test.name {
+			t.Errorf("NameString() = %q, want %q", got, test.name)
+		}
+		if got := test.typ.LinkString(); got != test.link {
+			t.Errorf("LinkString() = %q, want %q", got, test.link)
+		}
+		// Messages for the user show shapes the same way.
+		if got := test.typ.String(); got != test.name {
+			t.Errorf("String() = %q, want %q", got, test.name)
+		}
+	}
+
+	// So do the names of shape-based instantiations.
+	fn := pkg.Lookup("(*L[go.shape.int_0]).M")
+	if got, want := fn.String(), "l.(*L[shape:int]).M"; got != want {
+		t.Errorf("Sym.String() = %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%S", fn), "(*L[shape:int]).M"; got != want {
+		t.Errorf("%%S of Sym = %q, want %q", got, want)
+	}
+}
+
+func TestNameVargen(t *testing.T) {
+	pkg := types.NewPkg("example.com/v", "v")
+	local := func(name string) *types.Type {
+		obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup(name))
+		typ := types.NewNamed(obj)
+		obj.SetType(typ)
+		typ.SetUnderlying(types.Types[types.TINT])
+		typ.SetVargen()
+		return typ
+	}
+	t1, t2 := local("T"), local("T")
+	ptr := types.NewPtr(t1)
+
+	defer func(old bool) { types.NameVargen = old }(types.NameVargen)
+
+	types.NameVargen = false
+	if n1, n2 := t1.NameString(), t2.NameString(); n1 != "v.T" || n2 != "v.T" {
+		t.Errorf("NameString() = %q, %q, want %q for both", n1, n2, "v.T")
+	}
+
+	types.NameVargen = true
+	n1, n2 := t1.NameString(), t2.NameString()
+	if n1 == n2 || !strings.HasPrefix(n1, "v.T·") || !strings.HasPrefix(n2, "v.T·") {
+		t.Errorf("NameString() = %q, %q, want distinct v.T·N", n1, n2)
+	}
+	if got, want := n1, strings.TrimPrefix(t1.LinkString(), "example.com/"); got != want {
+		t.Errorf("NameString() = %q, want %q to match LinkString", got, want)
+	}
+	if got, want := ptr.NameString(), "*"+n1; got != want {
+		t.Errorf("NameString() = %q, want %q", got, want)
+	}
+	if types.TypeHash(t1) == types.TypeHash(t2) {
+		t.Errorf("TypeHash(%v) == TypeHash(%v)", n1, n2)
+	}
+}
@@ -4,9 +4,15 @@
 
 package types
 
+import (
+	"fmt"
+	"strings"
+)
+
 const (
 	identIgnoreTags = 1 << iota
 	identStrict
+	identKeepAliases // byte and rune are distinct from uint8 and int32
 )
 
 // Identical reports whether t1 and t2 are identical types, following the spec rules.
@@ -15,19 +21,61 @@ const (
 // type. Also, a type containing a shape type is considered identical to another type
 // (shape or not) if their underlying types are the same, or they are both pointers.
 func Identical(t1, t2 *Type) bool {
-	return identical(t1, t2, 0, nil)
+	return identical(t1, t2, 0, nil, nil)
 }
 
 // IdenticalIgnoreTags is like Identical, but it ignores struct tags
 // for struct identity.
 func IdenticalIgnoreTags(t1, t2 *Type) bool {
-	return identical(t1, t2, identIgnoreTags, nil)
+	return identical(t1, t2, identIgnoreTags, nil, nil)
 }
 
 // IdenticalStrict is like Identical, but matches types exactly, without the
 // exception for shapes.
 func IdenticalStrict(t1, t2 *Type) bool {
-	return identical(t1, t2, identStrict, nil)
+	return identical(t1, t2, identStrict, nil, nil)
+}
+
+// IdenticalReason is like Identical, but if t1 and t2 are not
+// identical, it also returns a description of the first difference
+// found, prefixed by the path leading to it; for example,
+// "field 2 (X): tag mismatch `json:"a"` vs `json:"b"`".
+func IdenticalReason(t1, t2 *Type) (bool, string) {
+	var r identReason
+	if identical(t1, t2, 0, nil, &r) {
+		return true, ""
+	}
+	return false, r.String()
+}
+
+// An identReason records why identical reported that two types
+// differ. Identical and its variants pass a nil *identReason, so
+// identical tests for nil before recording anything; this keeps the
+// common path from formatting or allocating.
+type identReason struct {
+	path []string // innermost first
+	msg  string
+}
+
+// fail records the difference described by format and args.
+func (r *identReason) fail(format string, args ...interface{}) {
+	r.msg = fmt.Sprintf(format, args...)
+}
+
+// within records that the difference recorded by a nested call lies
+// within the part of the type described by format and args.
+func (r *identReason) within(format string, args ...interface{}) {
+	r.path = append(r.path, fmt.Sprintf(format, args...))
+}
+
+func (r *identReason) String() string {
+	var b strings.Builder
+	for i := len(r.path) - 1; i >= 0; i-- {
+		b.WriteString(r.path[i])
+		b.WriteString(": ")
+	}
+	b.WriteString(r.msg)
+	return b.String()
 }
 
 type typePair struct {
@@ -35,11 +83,21 @@ type typePair struct {
 	t2 *Type
 }
 
-func identical(t1, t2 *Type, flags int, assumedEqual map[typePair]struct{}) bool {
+func identical(t1, t2 *Type, flags int, assumedEqual map[typePair]struct{}, r *identReason) bool {
 	if t1 == t2 {
 		return true
 	}
 	if t1 == nil || t2 == nil || t1.kind != t2.kind || t1.Broke() || t2.Broke() {
+		if r != nil {
+			switch {
+			case t1 == nil || t2 == nil:
+				r.fail("%v vs %v", t1, t2)
+			case t1.kind != t2.kind:
+				r.fail("%v vs %v: different kinds %v and %v", t1, t2, t1.kind, t2.kind)
+			default:
+				r.fail("%v vs %v: broken type", t1, t2)
+			}
+		}
 		return false
 	}
 	if t1.sym != nil || t2.sym != nil {
@@ -52,15 +110,24 @@ func identical(t1, t2 *Type, flags int, assumedEqual map[typePair]struct{}) bool
 			goto cont
 		}
 		// Special case: we keep byte/uint8 and rune/int32
-		// separate for error messages. Treat them as equal.
-		switch t1.kind {
-		case TUINT8:
-			return (t1 == Types[TUINT8] || t1 == ByteType) && (t2 == Types[TUINT8] || t2 == ByteType)
-		case TINT32:
-			return (t1 == Types[TINT32] || t1 == RuneType) && (t2 == Types[TINT32] || t2 == RuneType)
-		default:
-			return false
+		// separate for error messages. Treat them as equal,
+		// unless asked to keep them apart.
+		if flags&identKeepAliases == 0 {
+			switch t1.kind {
+			case TUINT8:
+				if (t1 == Types[TUINT8] || t1 == ByteType) && (t2 == Types[TUINT8] || t2 == ByteType) {
+					return true
+				}
+			case TINT32:
+				if (t1 == Types[TINT32] || t1 == RuneType) && (t2 == Types[TINT32] || t2 == RuneType) {
+					return true
+				}
+			}
 		}
+		if r != nil {
+			r.fail("%L vs %L: different defined types", t1, t2)
+		}
+		return false
 	}
 cont:
 
@@ -85,11 +152,23 @@ cont:
 
 	case TINTER:
 		if t1.AllMethods().Len() != t2.AllMethods().Len() {
+			if r != nil {
+				r.fail("method count %d vs %d", t1.AllMethods().Len(), t2.AllMethods().Len())
+			}
 			return false
 		}
 		for i, f1 := range t1.AllMethods().Slice() {
 			f2 := t2.AllMethods().Index(i)
-			if f1.Sym != f2.Sym || !identical(f1.Type, f2.Type, flags, assumedEqual) {
+			if f1.Sym != f2.Sym {
+				if r != nil {
+					r.fail("method %d: %v vs %v", i, f1.Sym, f2.Sym)
+				}
+				return false
+			}
+			if !identical(f1.Type, f2.Type, flags, assumedEqual, r) {
+				if r != nil {
+					r.within("method %S", f1.Sym)
+				}
 				return false
 			}
 		}
@@ -97,14 +176,35 @@ cont:
 
 	case TSTRUCT:
 		if t1.NumFields() != t2.NumFields() {
+			if r != nil {
+				r.fail("field count %d vs %d", t1.NumFields(), t2.NumFields())
+			}
 			return false
 		}
 		for i, f1 := range t1.FieldSlice() {
 			f2 := t2.Field(i)
-			if f1.Sym != f2.Sym || f1.Embedded != f2.Embedded || !identical(f1.Type, f2.Type, flags, assumedEqual) {
+			if f1.Sym != f2.Sym {
+				if r != nil {
+					r.fail("field %d: name %v vs %v", i, f1.Sym, f2.Sym)
+				}
+				return false
+			}
+			if f1.Embedded != f2.Embedded {
+				if r != nil {
+					r.fail("field %d (%S): embedded vs not embedded", i, f1.Sym)
+				}
+				return false
+			}
+			if !identical(f1.Type, f2.Type, flags, assumedEqual, r) {
+				if r != nil {
+					r.within("field %d (%S)", i, f1.Sym)
+				}
 				return false
 			}
 			if (flags&identIgnoreTags) == 0 && f1.Note != f2.Note {
+				if r != nil {
+					r.fail("field %d (%S): tag mismatch %#q vs %#q", i, f1.Sym, f1.Note, f2.Note)
+				}
 				return false
 			}
 		}
@@ -114,15 +214,31 @@ cont:
 		// Check parameters and result parameters for type equality.
 		// We intentionally ignore receiver parameters for type
 		// equality, because they're never relevant.
-		for _, f := range ParamsResults {
+		for j, f := range ParamsResults {
+			what := "parameter"
+			if j == 1 {
+				what = "result"
+			}
 			// Loop over fields in structs, ignoring argument names.
 			fs1, fs2 := f(t1).FieldSlice(), f(t2).FieldSlice()
 			if len(fs1) != len(fs2) {
+				if r != nil {
+					r.fail("%s count %d vs %d", what, len(fs1), len(fs2))
+				}
 				return false
 			}
 			for i, f1 := range fs1 {
 				f2 := fs2[i]
-				if f1.IsDDD() != f2.IsDDD() || !identical(f1.Type, f2.Type, flags, assumedEqual) {
+				if f1.IsDDD() != f2.IsDDD() {
+					if r != nil {
+						r.fail("%s %d: variadic vs not variadic", what, i)
+					}
+					return false
+				}
+				if !identical(f1.Type, f2.Type, flags, assumedEqual, r) {
+					if r != nil {
+						r.within("%s %d", what, i)
+					}
 					return false
 				}
 			}
@@ -131,19 +247,65 @@ cont:
 
 	case TARRAY:
 		if t1.NumElem() != t2.NumElem() {
+			if r != nil {
+				r.fail("array length %d vs %d", t1.NumElem(), t2.NumElem())
+			}
 			return false
 		}
 
 	case TCHAN:
 		if t1.ChanDir() != t2.ChanDir() {
+			if r != nil {
+				r.fail("channel direction %v vs %v", t1, t2)
+			}
 			return false
 		}
 
+	case TUNION:
+		// Union terms are in canonical order (see NewUnion), so
+		// identical unions have identical terms in the same order.
+		if t1.NumTerms() != t2.NumTerms() {
+			if r != nil {
+				r.fail("term count %d vs %d", t1.NumTerms(), t2.NumTerms())
+			}
+			return false
+		}
+		for i := 0; i < t1.NumTerms(); i++ {
+			term1, tilde1 := t1.Term(i)
+			term2, tilde2 := t2.Term(i)
+			if tilde1 != tilde2 {
+				if r != nil {
+					if tilde1 {
+						r.fail("term %d: ~%v vs %v", i, term1, term2)
+					} else {
+						r.fail("term %d: %v vs ~%v", i, term1, term2)
+					}
+				}
+				return false
+			}
+			if !identical(term1, term2, flags, assumedEqual, r) {
+				if r != nil {
+					r.within("term %d", i)
+				}
+				return false
+			}
+		}
+		return true
+
 	case TMAP:
-		if !identical(t1.Key(), t2.Key(), flags, assumedEqual) {
+		if !identical(t1.Key(), t2.Key(), flags, assumedEqual, r) {
+			if r != nil {
+				r.within("map key")
+			}
 			return false
 		}
 	}
 
-	return identical(t1.Elem(), t2.Elem(), flags, assumedEqual)
+	if !identical(t1.Elem(), t2.Elem(), flags, assumedEqual, r) {
+		if r != nil {
+			r.within("element")
+		}
+		return false
+	}
+	return true
 }
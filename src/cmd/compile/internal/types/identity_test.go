@@ -0,0 +1,104 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"testing"
+
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestIdenticalReason(t *testing.T) {
+	pkg := types.NewPkg("p", "p")
+	field := func(name string, typ *types.Type, tag string) *types.Field {
+		f := types.NewField(src.NoXPos, pkg.Lookup(name), typ)
+		f.Note = tag
+		return f
+	}
+	tagged := func(tag string) *types.Type {
+		return types.NewStruct(pkg, []*types.Field{
+			field("a", types.Types[types.TINT], ""),
+			field("b", types.Types[types.TINT], ""),
+			field("c", types.Types[types.TSTRING], tag),
+		})
+	}
+	sig := func(params ...*types.Type) *types.Type {
+		var fs []*types.Field
+		for _, p := range params {
+			fs = append(fs, types.NewField(src.NoXPos, nil, p))
+		}
+		return types.NewSignature(pkg, nil, nil, fs, nil)
+	}
+	intT, strT := types.Types[types.TINT], types.Types[types.TSTRING]
+
+	tests := []struct {
+		t1, t2 *types.Type
+		want   string
+	}{
+		{types.NewSlice(intT), types.NewSlice(intT), ""},
+		{types.NewSlice(types.ByteType), types.NewSlice(types.Types[types.TUINT8]), ""},
+		{intT, strT, "int vs string: different kinds INT and STRING"},
+		{tagged(`json:"a"`), tagged(`json:"b"`), "field 2 (c): tag mismatch `json:\"a\"` vs `json:\"b\"`"},
+		{types.NewArray(intT, 3), types.NewArray(intT, 4), "array length 3 vs 4"},
+		{types.NewMap(intT, types.NewSlice(intT)), types.NewMap(intT, types.NewSlice(strT)),
+			"element: element: int vs string: different kinds INT and STRING"},
+		{types.NewMap(intT, intT), types.NewMap(strT, intT), "map key: int vs string: different kinds INT and STRING"},
+		{types.NewChan(intT, types.Csend), types.NewChan(intT, types.Crecv), "channel direction chan<- int vs <-chan int"},
+		{sig(intT, types.NewPtr(tagged(""))), sig(intT, types.NewPtr(tagged("x"))),
+			"parameter 1: element: field 2 (c): tag mismatch `` vs `x`"},
+		{sig(intT), sig(intT, intT), "parameter count 1 vs 2"},
+		{types.NewUnion([]*types.Type{intT, strT}, []bool{true, false}),
+			types.NewUnion([]*types.Type{strT, intT}, []bool{false, true}), ""},
+		{types.NewUnion([]*types.Type{intT, strT}, []bool{true, false}),
+			types.NewUnion([]*types.Type{strT, intT}, []bool{false, false}), "term 0: ~int vs int"},
+		{types.NewUnion([]*types.Type{intT}, []bool{false}),
+			types.NewUnion([]*types.Type{strT, intT}, []bool{false, false}), "term count 1 vs 2"},
+	}
+	for _, test := range tests {
+		ok, why := types.IdenticalReason(test.t1, test.t2)
+		if ok != (test.want == "") || why != test.want {
+			t.Errorf("IdenticalReason(%v, %v) = %v, %q; want %q", test.t1, test.t2, ok, why, test.want)
+		}
+		if ok != types.Identical(test.t1, test.t2) {
+			t.Errorf("IdenticalReason(%v, %v) = %v, but Identical disagrees", test.t1, test.t2, ok)
+		}
+	}
+}
+
+func TestUnionTerms(t *testing.T) {
+	intT, strT := types.Types[types.TINT], types.Types[types.TSTRING]
+	sl := types.NewSlice(intT)
+
+	tests := []struct {
+		terms  []*types.Type
+		tildes []bool
+		want   string
+	}{
+		{[]*types.Type{strT, intT}, []bool{false, true}, "~int|string"},
+		{[]*types.Type{intT, strT}, []bool{true, false}, "~int|string"},
+		{[]*types.Type{sl, strT, intT}, []bool{false, false, false}, "[]int|int|string"},
+		{[]*types.Type{intT, strT, intT}, []bool{false, false, true}, "~int|string"},
+		{[]*types.Type{types.ByteType, types.Types[types.TUINT8]}, []bool{false, false}, "byte"},
+	}
+	for _, test := range tests {
+		u := types.NewUnion(test.terms, test.tildes)
+		if got := u.String(); got != test.want {
+			t.Errorf("NewUnion(%v, %v) = %s, want %s", test.terms, test.tildes, got, test.want)
+		}
+	}
+
+	u1 := types.NewUnion([]*types.Type{sl, strT, intT}, []bool{false, true, false})
+	u2 := types.NewUnion([]*types.Type{intT, sl, strT}, []bool{false, false, true})
+	if u1.LinkString() != u2.LinkString() {
+		t.Errorf("LinkString: %s vs %s", u1.LinkString(), u2.LinkString())
+	}
+	if types.TypeHash(u1) != types.TypeHash(u2) {
+		t.Errorf("TypeHash(%v) != TypeHash(%v)", u1, u2)
+	}
+	if !types.IdenticalStrict(u1, u2) {
+		t.Errorf("%v and %v are not identical", u1, u2)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultInternLimit is the default limit on the total length of the
+// strings retained by InternString.
+const DefaultInternLimit = 64 << 20
+
+// The intern table is a cache: once the strings it retains exceed the
+// limit, the least recently used ones are evicted, and are interned
+// anew if they are needed again. Evicted strings remain valid; only
+// the sharing of their bytes with later copies is lost.
+var (
+	internMu    sync.Mutex // protects the variables below
+	internTable map[string]*internEntry
+	internLRU   internEntry // list sentinel; internLRU.next is the most recently used
+	internLimit int64       = DefaultInternLimit
+	internStats InternStats
+)
+
+// An internEntry is an interned string, in the intern table's list
+// of strings from most to least recently used.
+type internEntry struct {
+	s          string
+	prev, next *internEntry
+}
+
+func (e *internEntry) unlink() {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+}
+
+// pushFront moves e to the front of the list of interned strings.
+func (e *internEntry) pushFront() {
+	if internLRU.next == nil {
+		internLRU.prev, internLRU.next = &internLRU, &internLRU
+	}
+	e.prev, e.next = &internLRU, internLRU.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+// InternString returns a string equal to b, shared with the earlier
+// results of InternString for equal byte slices unless those have
+// been evicted from the intern table; see SetInternLimit. It may be
+// called concurrently.
+func InternString(b []byte) string {
+	internMu.Lock()
+	internStats.Lookups++
+	if e, ok := internTable[string(b)]; ok { // string(b) here doesn't allocate
+		internStats.Hits++
+		e.unlink()
+		e.pushFront()
+		internMu.Unlock()
+		return e.s
+	}
+
+	if internTable == nil {
+		internTable = make(map[string]*internEntry)
+	}
+	e := &internEntry{s: string(b)}
+	internTable[e.s] = e
+	e.pushFront()
+	internStats.Strings++
+	internStats.Bytes += int64(len(e.s))
+	internStats.Live++
+	internStats.LiveBytes += int64(len(e.s))
+	evictInterned(e)
+	internMu.Unlock()
+	return e.s
+}
+
+// evictInterned evicts the least recently used strings other than
+// keep until the intern table is within its limit.
+func evictInterned(keep *internEntry) {
+	if internLimit <= 0 {
+		return
+	}
+	for internStats.LiveBytes > internLimit && internLRU.prev != &internLRU && internLRU.prev != keep {
+		e := internLRU.prev
+		e.unlink()
+		delete(internTable, e.s)
+		internStats.Live--
+		internStats.LiveBytes -= int64(len(e.s))
+		internStats.Evicted++
+	}
+}
+
+// SetInternLimit sets the limit on the total length of the strings
+// retained by InternString, evicting strings as needed, and returns
+// the previous limit. A limit of zero or less means no limit.
+func SetInternLimit(limit int64) int64 {
+	internMu.Lock()
+	defer internMu.Unlock()
+	old := internLimit
+	internLimit = limit
+	evictInterned(nil)
+	return old
+}
+
+// InternStats holds statistics about InternString, which interns the
+// names of symbols and the strings produced by formatting types.
+type InternStats struct {
+	Lookups int64 // calls to InternString
+	Hits    int64 // calls that found the string already interned
+	Strings int64 // strings interned, counting each time a string is interned anew
+	Bytes   int64 // total length of the strings interned
+
+	Live      int64 // strings currently retained
+	LiveBytes int64 // total length of the strings currently retained
+	Evicted   int64 // strings evicted to stay within the limit
+
+	Syms int64 // symbols in all packages
+}
+
+// Misses returns the number of lookups that interned a new string.
+func (st InternStats) Misses() int64 {
+	return st.Lookups - st.Hits
+}
+
+// HitRate returns the fraction of lookups that found the string
+// already interned.
+func (st InternStats) HitRate() float64 {
+	if st.Lookups == 0 {
+		return 0
+	}
+	return float64(st.Hits) / float64(st.Lookups)
+}
+
+// ReadInternStats returns the statistics about InternString so far,
+// and the number of symbols created.
+func ReadInternStats() InternStats {
+	internMu.Lock()
+	st := internStats
+	internMu.Unlock()
+	pkgMapMu.Lock()
+	defer pkgMapMu.Unlock()
+	for _, pkg := range pkgMap {
+		pkg.symsMu.Lock()
+		st.Syms += int64(len(pkg.Syms))
+		pkg.symsMu.Unlock()
+	}
+	return st
+}
+
+// DumpInternStats prints the interning statistics, for -d=internstats.
+func DumpInternStats() {
+	st := ReadInternStats()
+	internMu.Lock()
+	limit := internLimit
+	internMu.Unlock()
+	fmt.Printf("lookups: %d (%d hits, %d misses, %.2f%% hit rate)\n", st.Lookups, st.Hits, st.Misses(), st.HitRate()*100)
+	fmt.Printf("interned: %d strings (%d bytes)\n", st.Strings, st.Bytes)
+	fmt.Printf("retained: %d strings (%d bytes, limit %d)\n", st.Live, st.LiveBytes, limit)
+	fmt.Printf("evicted: %d strings\n", st.Evicted)
+	fmt.Printf("syms: %d\n", st.Syms)
+}
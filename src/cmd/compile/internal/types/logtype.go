@@ -0,0 +1,47 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cmd/compile/internal/base"
+)
+
+// A LogType refers to a type from an optimization diagnostic
+// (see logopt.Msgf). Rather than being formatted into the diagnostic's
+// text when it is logged, the type is rendered when the diagnostic is
+// written out: as Go syntax in -m text, and additionally as a
+// structured record in -json output.
+type LogType struct {
+	T *Type
+}
+
+// Format formats lt.T, as for (*Type).Format.
+func (lt LogType) Format(s fmt.State, verb rune) {
+	lt.T.Format(s, verb)
+}
+
+// MarshalJSON encodes lt.T as an object holding its Go syntax, its
+// kind and, for defined types, the full path of the declaring package,
+// so that tools need not guess at how names were qualified.
+func (lt LogType) MarshalJSON() ([]byte, error) {
+	var rec struct {
+		Type    string `json:"type"`
+		Kind    string `json:"kind"`
+		PkgPath string `json:"pkgpath,omitempty"`
+	}
+	t := lt.T
+	rec.Type = t.String()
+	rec.Kind = t.Kind().String()
+	if sym := t.Sym(); sym != nil && sym.Pkg != nil {
+		rec.PkgPath = sym.Pkg.Path
+		if sym.Pkg == LocalPkg {
+			rec.PkgPath = base.Ctxt.Pkgpath
+		}
+	}
+	return json.Marshal(rec)
+}
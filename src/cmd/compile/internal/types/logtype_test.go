@@ -0,0 +1,47 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestLogType(t *testing.T) {
+	pkg := types.NewPkg("example.com/logtype", "logtype")
+	obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup("T"))
+	named := types.NewNamed(obj)
+	obj.SetType(named)
+	named.SetUnderlying(types.NewStruct(types.NoPkg, nil))
+
+	tests := []struct {
+		typ  *types.Type
+		text string
+		json string
+	}{
+		{types.Types[types.TINT], "int", `{"type":"int","kind":"INT"}`},
+		{types.NewSlice(named), "[]logtype.T", `{"type":"[]logtype.T","kind":"SLICE"}`},
+		{named, "logtype.T", `{"type":"logtype.T","kind":"STRUCT","pkgpath":"example.com/logtype"}`},
+	}
+	for _, test := range tests {
+		lt := types.LogType{T: test.typ}
+		if got := fmt.Sprintf("%v", lt); got != test.text {
+			t.Errorf("%v: formatted as %q, want %q", test.typ, got, test.text)
+		}
+		b, err := json.Marshal(lt)
+		if err != nil {
+			t.Errorf("%v: %v", test.typ, err)
+			continue
+		}
+		if got := string(b); got != test.json {
+			t.Errorf("%v: marshaled as %s, want %s", test.typ, got, test.json)
+		}
+	}
+}
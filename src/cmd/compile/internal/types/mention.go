@@ -0,0 +1,115 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// A Mentions renders the types mentioned in one diagnostic, so that
+// a large type mentioned several times is spelled out only once.
+// The first mention of a type is printed in full; later mentions of
+// an identical type print its short form, in which the contents of
+// unnamed struct, interface and function types are elided (for
+// example, "[]struct{...}"). Types whose full form is short are
+// always printed in full.
+//
+// FormatMessage uses a new Mentions for each diagnostic, for the types
+// among its arguments and those that the IR nodes among them print.
+// Other messages can use one the same way:
+//
+//	var m types.Mentions
+//	fmt.Printf("cannot use %v as %v in %v", m.Type(t1), m.Type(t2), m.Type(t3))
+//
+// The zero Mentions is ready to use.
+type Mentions struct {
+	seen []*Type
+}
+
+// minMentionElide is the length of the full form above which later
+// mentions of a type use its short form.
+const minMentionElide = 24
+
+// Type returns a value that formats t, with the same verbs as
+// (*Type).Format, as a mention of t in m's message. Whether the
+// mention is the first is decided when the value is formatted, so
+// mentions count in the order in which they appear in the message.
+func (m *Mentions) Type(t *Type) fmt.Formatter {
+	return mention{m, t}
+}
+
+type mention struct {
+	m *Mentions
+	t *Type
+}
+
+func (x mention) Format(s fmt.State, verb rune) {
+	if verb != 'v' || s.Flag('+') || x.t == nil {
+		x.t.Format(s, verb)
+		return
+	}
+	s.Write(x.m.mention(tconv2(nil, x.t, verb, fmtGo, fmtState{}), x.t, fmtState{}))
+}
+
+// mention returns the form of t to print for a mention of t in m's
+// message, given t's full form, which st formatted. A nil m prints
+// every mention in full.
+func (m *Mentions) mention(full []byte, t *Type, st fmtState) []byte {
+	if m == nil {
+		return full
+	}
+	for _, prev := range m.seen {
+		if Identical(prev, t) {
+			if len(full) > minMentionElide {
+				return shortString(nil, t, st)
+			}
+			return full
+		}
+	}
+	m.seen = append(m.seen, t)
+	return full
+}
+
+// shortString appends the short form of t described at Mentions,
+// with its named types formatted in st, to b.
+func shortString(b []byte, t *Type, st fmtState) []byte {
+	if t.Sym() != nil {
+		return tconv2(b, t, 'v', fmtGo, st)
+	}
+	switch t.Kind() {
+	case TPTR:
+		return shortString(append(b, '*'), t.Elem(), st)
+	case TSLICE:
+		return shortString(append(b, "[]"...), t.Elem(), st)
+	case TARRAY:
+		b = append(b, '[')
+		b = strconv.AppendInt(b, t.NumElem(), 10)
+		return shortString(append(b, ']'), t.Elem(), st)
+	case TCHAN:
+		switch t.ChanDir() {
+		case Crecv:
+			b = append(b, "<-chan "...)
+		case Csend:
+			b = append(b, "chan<- "...)
+		default:
+			b = append(b, "chan "...)
+		}
+		return shortString(b, t.Elem(), st)
+	case TMAP:
+		b = shortString(append(b, "map["...), t.Key(), st)
+		return shortString(append(b, ']'), t.Elem(), st)
+	case TSTRUCT:
+		return append(b, "struct{...}"...)
+	case TINTER:
+		if t.IsEmptyInterface() {
+			return append(b, "interface{}"...)
+		}
+		return append(b, "interface{...}"...)
+	case TFUNC:
+		return append(b, "func(...)"...)
+	}
+	return tconv2(b, t, 'v', fmtGo, st)
+}
@@ -0,0 +1,38 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"fmt"
+	"testing"
+
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestMentions(t *testing.T) {
+	pkg := types.NewPkg("m", "m")
+	big := func() *types.Type {
+		return types.NewStruct(pkg, []*types.Field{
+			types.NewField(src.NoXPos, pkg.Lookup("Name"), types.Types[types.TSTRING]),
+			types.NewField(src.NoXPos, pkg.Lookup("Count"), types.Types[types.TINT]),
+		})
+	}
+	small := types.NewSlice(types.Types[types.TINT])
+	m1, m2 := types.NewMap(types.Types[types.TSTRING], types.NewSlice(big())), types.NewMap(types.Types[types.TSTRING], types.NewSlice(big()))
+
+	var m types.Mentions
+	got := fmt.Sprintf("%v, %v, %v, %v, %+v", m.Type(m1), m.Type(small), m.Type(m2), m.Type(small), m.Type(m2))
+	want := "map[string][]struct { Name string; Count int }, []int, map[string][]struct{...}, []int, MAP-map[string][]struct { Name string; Count int }"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	// A new Mentions starts over.
+	var m3 types.Mentions
+	if got, want := fmt.Sprint(m3.Type(m2)), m2.String(); got != want {
+		t.Errorf("first mention in new message: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import "sort"
+
+// ComputeMethodSet returns the method set of t, sorted by name.
+//
+// For an interface type, it is the interface's methods. Otherwise, it
+// is the methods declared on t's receiver base type (see
+// ReceiverBaseType) together with the methods promoted from its
+// embedded fields, restricted to those that apply to t: methods with
+// pointer receivers are only in the method set of a non-pointer type
+// if they are promoted through an embedded pointer. Methods marked
+// go:nointerface are included; Implements skips them.
+//
+// The full method list of the receiver base type is computed once, by
+// CalcMethods, and cached as its AllMethods. The result must not be
+// modified.
+func ComputeMethodSet(t *Type) []*Field {
+	if t == nil {
+		return nil
+	}
+	if t.IsInterface() {
+		return t.AllMethods().Slice()
+	}
+	mt := ReceiverBaseType(t)
+	if mt == nil {
+		return nil
+	}
+	CalcMethods(mt)
+	all := mt.AllMethods().Slice()
+
+	for i, m := range all {
+		if !IsMethodApplicable(t, m) {
+			// Copy the applicable methods, starting with those
+			// before the first one that is not.
+			ms := append([]*Field(nil), all[:i]...)
+			for _, m := range all[i+1:] {
+				if IsMethodApplicable(t, m) {
+					ms = append(ms, m)
+				}
+			}
+			return ms
+		}
+	}
+	return all
+}
+
+// CalcMethods computes the methods of the named or struct type t,
+// including the methods promoted from its embedded fields, and records
+// them as t's AllMethods. It does nothing if t's AllMethods are
+// already known, or if t is an interface, whose methods are computed
+// by CalcSize.
+//
+// A promoted method is a copy of the method it promotes, with Embedded
+// set to 1, or to 2 if it is promoted through an embedded pointer and
+// so applies to non-pointer values even if it has a pointer receiver.
+// Following the spec, a method is only promoted from the shallowest
+// depth at which its name occurs among t's fields and methods, and
+// only if it occurs exactly once at that depth and is a method there.
+//
+// Generic methods (see Field.IsGenericMethod) are not in the method
+// set, and so are left out, though their names still count.
+func CalcMethods(t *Type) {
+	if t == nil || t.AllMethods().Len() != 0 || t.IsInterface() {
+		return
+	}
+
+	// An embedding is a type reached through a path of embedded
+	// fields, together with whether the path goes through a pointer.
+	type embedding struct {
+		typ *Type
+		ptr bool
+	}
+
+	// shadowed holds the names found at shallower depths.
+	shadowed := make(map[*Sym]bool)
+	seen := map[*Type]bool{t: true}
+	var ms []*Field
+
+	// Depth 0: t's own fields and methods.
+	var next []embedding
+	for _, f := range embeddingFields(t) {
+		shadowed[f.Sym] = true
+		if f.Embedded != 0 && f.Sym != nil {
+			next = append(next, embedding{f.Type, f.Type.IsPtr()})
+		}
+	}
+	for _, f := range t.Methods().Slice() {
+		shadowed[f.Sym] = true
+	}
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		// count records how many times each name occurs at
+		// this depth; found records the method, if any, and
+		// whether it was reached through a pointer.
+		count := make(map[*Sym]int)
+		type method struct {
+			f   *Field
+			ptr bool
+		}
+		found := make(map[*Sym]method)
+		var names []*Sym
+		note := func(f *Field, e embedding, isMethod bool) {
+			if shadowed[f.Sym] {
+				return
+			}
+			if count[f.Sym] == 0 {
+				names = append(names, f.Sym)
+			}
+			count[f.Sym]++
+			if isMethod && !f.IsGenericMethod() {
+				found[f.Sym] = method{f, e.ptr}
+			} else {
+				delete(found, f.Sym)
+			}
+		}
+
+		for _, e := range current {
+			for _, f := range embeddingFields(e.typ) {
+				if f.Sym == nil {
+					continue
+				}
+				u := e.typ
+				if u.IsPtr() {
+					u = u.Elem()
+				}
+				note(f, e, u.IsInterface())
+				if f.Embedded != 0 && !seen[ptrBase(f.Type)] {
+					next = append(next, embedding{f.Type, e.ptr || f.Type.IsPtr()})
+				}
+			}
+			if rt := ReceiverBaseType(e.typ); rt != nil {
+				for _, f := range rt.Methods().Slice() {
+					if f.Embedded == 0 {
+						note(f, e, true)
+					}
+				}
+			}
+		}
+		for _, e := range current {
+			seen[ptrBase(e.typ)] = true
+		}
+
+		for _, s := range names {
+			shadowed[s] = true
+			m, ok := found[s]
+			if !ok || count[s] != 1 {
+				continue
+			}
+			f := m.f.Copy()
+			f.Embedded = 1 // needs a trampoline
+			if m.ptr {
+				f.Embedded = 2
+			}
+			ms = append(ms, f)
+		}
+	}
+
+	for _, f := range t.Methods().Slice() {
+		if !f.IsGenericMethod() {
+			ms = append(ms, f)
+		}
+	}
+	sort.Sort(MethodsByName(ms))
+	t.SetAllMethods(ms)
+}
+
+// embeddingFields returns the fields of t, or of *t if t is an
+// unnamed pointer: the fields of a struct, or the methods and embedded
+// types of an interface.
+func embeddingFields(t *Type) []*Field {
+	if t.IsPtr() {
+		t = t.Elem()
+	}
+	switch {
+	case t.IsStruct():
+		return t.FieldSlice()
+	case t.IsInterface():
+		return t.AllMethods().Slice()
+	}
+	return nil
+}
+
+// ptrBase returns t's element type if t is a pointer, and t otherwise.
+func ptrBase(t *Type) *Type {
+	if t.IsPtr() {
+		return t.Elem()
+	}
+	return t
+}
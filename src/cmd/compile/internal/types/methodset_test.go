@@ -0,0 +1,91 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestComputeMethodSet(t *testing.T) {
+	pkg := types.NewPkg("ms", "ms")
+	named := func(name string, underlying *types.Type) *types.Type {
+		obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup(name))
+		typ := types.NewNamed(obj)
+		obj.SetType(typ)
+		typ.SetUnderlying(underlying)
+		return typ
+	}
+	method := func(recv *types.Type, name string) *types.Field {
+		return types.NewField(src.NoXPos, pkg.Lookup(name),
+			types.NewSignature(pkg, types.NewField(src.NoXPos, nil, recv), nil, nil, nil))
+	}
+	field := func(name string, typ *types.Type, embedded bool) *types.Field {
+		f := types.NewField(src.NoXPos, pkg.Lookup(name), typ)
+		if embedded {
+			f.Embedded = 1
+		}
+		return f
+	}
+	names := func(fs []*types.Field) string {
+		var s []string
+		for _, f := range fs {
+			s = append(s, f.Sym.Name)
+		}
+		return strings.Join(s, " ")
+	}
+
+	// type A int; func (A) Val(); func (*A) Ptr(); func (A) Both()
+	a := named("A", types.Types[types.TINT])
+	a.Methods().Set([]*types.Field{method(a, "Val"), method(types.NewPtr(a), "Ptr"), method(a, "Both")})
+
+	// type B int; func (B) Both(); func (B) Shadowed()
+	b := named("B", types.Types[types.TINT])
+	b.Methods().Set([]*types.Field{method(b, "Both"), method(b, "Shadowed")})
+
+	// type S struct { A; *B; Shadowed int }
+	s := named("S", types.NewStruct(pkg, []*types.Field{
+		field("A", a, true),
+		field("B", types.NewPtr(b), true),
+		field("Shadowed", types.Types[types.TINT], false),
+	}))
+
+	// type W struct { S }; func (W) Own()
+	w := named("W", types.NewStruct(pkg, []*types.Field{field("S", s, true)}))
+	w.Methods().Set([]*types.Field{method(w, "Own")})
+
+	// type U struct { *A }
+	u := named("U", types.NewStruct(pkg, []*types.Field{field("A", types.NewPtr(a), true)}))
+
+	tests := []struct {
+		typ  *types.Type
+		want string
+	}{
+		{a, "Both Val"},
+		{types.NewPtr(a), "Both Ptr Val"},
+		// Both is ambiguous between A and *B; Shadowed is a field.
+		{s, "Val"},
+		{types.NewPtr(s), "Ptr Val"},
+		{w, "Own Val"},
+		{types.NewPtr(w), "Own Ptr Val"},
+		// Methods promoted through an embedded pointer apply to U.
+		{u, "Both Ptr Val"},
+	}
+	for _, test := range tests {
+		if got := names(types.ComputeMethodSet(test.typ)); got != test.want {
+			t.Errorf("ComputeMethodSet(%v) = %s, want %s", test.typ, got, test.want)
+		}
+	}
+
+	for _, m := range types.ComputeMethodSet(types.NewPtr(w)) {
+		if want := uint8(1); m.Sym.Name == "Val" && m.Embedded != want {
+			t.Errorf("promoted method %v has Embedded %d, want %d", m.Sym, m.Embedded, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"bytes"
+	"testing"
+
+	"cmd/compile/internal/types"
+)
+
+func TestNameData(t *testing.T) {
+	tests := []struct {
+		name, tag     string
+		exported, pkg bool
+		want          []byte
+	}{
+		{"", "", false, false, []byte{0, 0}},
+		{"X", "", true, false, []byte{1, 1, 'X'}},
+		{"x", `json:"x"`, false, true, append([]byte{6, 1, 'x', 8}, `json:"x"`...)},
+	}
+	for _, test := range tests {
+		got := types.NameData(test.name, test.tag, test.exported, test.pkg)
+		if !bytes.Equal(got, test.want) {
+			t.Errorf("NameData(%q, %q, %v, %v) = %v, want %v", test.name, test.tag, test.exported, test.pkg, got, test.want)
+		}
+	}
+
+	a := types.NameData("Field", "", true, false)
+	b := types.NameData("Field", "", false, false)
+	if types.NameDataHash(a) != types.NameDataHash(append([]byte(nil), a...)) {
+		t.Errorf("NameDataHash differs for equal data")
+	}
+	if types.NameDataHash(a) == types.NameDataHash(b) {
+		t.Errorf("NameDataHash(%v) == NameDataHash(%v)", a, b)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// An origScheme is a family of compiler-generated names, registered
+// with RegisterOrigSym.
+type origScheme struct {
+	prefix string
+	orig   func(*Sym) *Sym
+}
+
+// origSchemes holds the registered naming schemes.
+var origSchemes []origScheme
+
+func init() {
+	unnamed := func(*Sym) *Sym { return nil }
+	RegisterOrigSym(paramPrefix[ParamAnon], unnamed)
+	RegisterOrigSym(paramPrefix[ParamResult], unnamed)
+	RegisterOrigSym(paramPrefix[ParamBlank], func(*Sym) *Sym {
+		// TODO(mdempsky): Does s.Pkg matter here?
+		return BlankSym
+	})
+}
+
+// RegisterOrigSym declares that the compiler generates names starting
+// with prefix, and that OrigSym(s) is orig(s) for a symbol s so named.
+// orig returns nil if the user left the entity unnamed.
+//
+// The prefix must start with a character that cannot start a Go
+// identifier, so that it cannot clash with names written by the user,
+// and must neither be a prefix of a registered prefix nor have one as
+// a prefix. RegisterOrigSym must be called during initialization; it
+// panics if the prefix is invalid.
+func RegisterOrigSym(prefix string, orig func(*Sym) *Sym) {
+	r, _ := utf8.DecodeRuneInString(prefix)
+	if prefix == "" || r == '_' || unicode.IsLetter(r) {
+		panic(fmt.Sprintf("RegisterOrigSym: invalid prefix %q", prefix))
+	}
+	for _, scheme := range origSchemes {
+		if strings.HasPrefix(prefix, scheme.prefix) || strings.HasPrefix(scheme.prefix, prefix) {
+			panic(fmt.Sprintf("RegisterOrigSym: prefix %q overlaps %q", prefix, scheme.prefix))
+		}
+	}
+	origSchemes = append(origSchemes, origScheme{prefix, orig})
+}
+
+// OrigSym returns the original symbol written by the user.
+func OrigSym(s *Sym) *Sym {
+	if s == nil {
+		return nil
+	}
+	for _, scheme := range origSchemes {
+		if strings.HasPrefix(s.Name, scheme.prefix) {
+			return scheme.orig(s)
+		}
+	}
+	return s
+}
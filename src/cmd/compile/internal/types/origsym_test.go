@@ -0,0 +1,38 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterOrigSym(t *testing.T) {
+	// Registrations are global; undo this test's.
+	defer func(saved []origScheme) { origSchemes = saved }(origSchemes[:len(origSchemes):len(origSchemes)])
+
+	pkg := NewPkg("origsym", "origsym")
+	RegisterOrigSym("%tmp.", func(s *Sym) *Sym {
+		return s.Pkg.Lookup(strings.TrimPrefix(s.Name, "%tmp."))
+	})
+
+	if got, want := OrigSym(pkg.Lookup("%tmp.x")), pkg.Lookup("x"); got != want {
+		t.Errorf("OrigSym(%%tmp.x) = %v, want %v", got, want)
+	}
+	if s := pkg.Lookup("tmp.x"); OrigSym(s) != s {
+		t.Errorf("OrigSym(tmp.x) = %v, want itself", OrigSym(s))
+	}
+
+	for _, prefix := range []string{"", "x", "_x", "é", "%tmp", "%tmp.y", "~"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterOrigSym(%q) did not panic", prefix)
+				}
+			}()
+			RegisterOrigSym(prefix, func(*Sym) *Sym { return nil })
+		}()
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A ParamKind classifies the name of a function parameter.
+//
+// The compiler names parameters that the user left unnamed or blank,
+// so that the backend can refer to them. Such names start with a
+// character that cannot appear in Go identifiers, and are created by
+// ParamSym so that every producer agrees on them. Consumers recover
+// the kind with ParamKindOf instead of inspecting names themselves.
+//
+// The names are shown differently depending on the purpose:
+//
+//	kind            Go syntax (OrigSym)  debug prints  DWARF
+//	ParamNamed      name                 name          name
+//	ParamAnon       unnamed              .anonN        .anonN
+//	ParamResult     unnamed              ~rN           synthesized
+//	ParamBlank      _                    _             synthesized
+//	ParamInlResult  ~RN                  ~RN           ~RN
+//
+// Export data always records the internal names.
+type ParamKind uint8
+
+const (
+	ParamNamed     ParamKind = iota // a name written by the user
+	ParamAnon                       // .anonN: an unnamed or blank parameter of a generated wrapper
+	ParamResult                     // ~rN: an unnamed result
+	ParamBlank                      // ~bN: a blank result
+	ParamInlResult                  // ~RN: a result of an inlined call
+)
+
+var paramPrefix = [...]string{
+	ParamAnon:      ".anon",
+	ParamResult:    "~r",
+	ParamBlank:     "~b",
+	ParamInlResult: "~R",
+}
+
+// ParamSym returns the local symbol naming the i'th parameter of the
+// given kind. It panics if kind is ParamNamed.
+func ParamSym(kind ParamKind, i int) *Sym {
+	if kind == ParamNamed {
+		panic("ParamSym of named parameter")
+	}
+	var buf [20]byte
+	b := strconv.AppendInt(append(buf[:0], paramPrefix[kind]...), int64(i), 10)
+	return LocalPkg.LookupBytes(b)
+}
+
+// ParamKindOf returns the kind of the parameter named name.
+func ParamKindOf(name string) ParamKind {
+	for kind, prefix := range paramPrefix {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return ParamKind(kind)
+		}
+	}
+	return ParamNamed
+}
+
+// IsSynthesized reports whether parameters of kind k are described
+// in DWARF as synthesized by the compiler rather than declared by the
+// user.
+func (k ParamKind) IsSynthesized() bool {
+	return k == ParamResult || k == ParamBlank
+}
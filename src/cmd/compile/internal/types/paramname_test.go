@@ -0,0 +1,43 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"testing"
+
+	"cmd/compile/internal/types"
+)
+
+func TestParamSym(t *testing.T) {
+	tests := []struct {
+		kind types.ParamKind
+		name string
+		orig *types.Sym
+	}{
+		{types.ParamAnon, ".anon3", nil},
+		{types.ParamResult, "~r3", nil},
+		{types.ParamBlank, "~b3", types.BlankSym},
+	}
+	for _, test := range tests {
+		s := types.ParamSym(test.kind, 3)
+		if s.Name != test.name {
+			t.Errorf("ParamSym(%v, 3) = %s, want %s", test.kind, s.Name, test.name)
+		}
+		if got := types.ParamKindOf(s.Name); got != test.kind {
+			t.Errorf("ParamKindOf(%s) = %v, want %v", s.Name, got, test.kind)
+		}
+		if got := types.OrigSym(s); got != test.orig {
+			t.Errorf("OrigSym(%s) = %v, want %v", s.Name, got, test.orig)
+		}
+	}
+
+	s := types.ParamSym(types.ParamInlResult, 3)
+	if types.ParamKindOf(s.Name) != types.ParamInlResult || types.OrigSym(s) != s {
+		t.Errorf("%s: kind %v, OrigSym %v", s.Name, types.ParamKindOf(s.Name), types.OrigSym(s))
+	}
+	if got := types.ParamKindOf("x"); got != types.ParamNamed {
+		t.Errorf("ParamKindOf(x) = %v, want ParamNamed", got)
+	}
+}
@@ -5,6 +5,7 @@
 package types
 
 import (
+	"cmd/compile/internal/base"
 	"cmd/internal/obj"
 	"cmd/internal/objabi"
 	"fmt"
@@ -14,7 +15,10 @@ import (
 )
 
 // pkgMap maps a package path to a package.
-var pkgMap = make(map[string]*Pkg)
+var (
+	pkgMapMu sync.Mutex // protects pkgMap
+	pkgMap   = make(map[string]*Pkg)
+)
 
 // MaxPkgHeight is a height greater than any likely package height.
 const MaxPkgHeight = 1e9
@@ -33,12 +37,27 @@ type Pkg struct {
 	Height int
 
 	Direct bool // imported directly
+
+	// Aliases maps the names of the source files that import the
+	// package under a name of their own, as in
+	//
+	//	import alias "path"
+	//
+	// to that name. It is populated by the noder; see AddAlias
+	// and AliasIn.
+	Aliases map[string]string
+
+	symsMu  sync.Mutex // protects Syms in Lookup and friends, which may be called concurrently
+	defMu   sync.Mutex // serializes DefineOnce
+	aliasMu sync.Mutex // protects Aliases
 }
 
 // NewPkg returns a new Pkg for the given package path and name.
 // Unless name is the empty string, if the package exists already,
 // the existing package name and the provided name must match.
 func NewPkg(path, name string) *Pkg {
+	pkgMapMu.Lock()
+	defer pkgMapMu.Unlock()
 	if p := pkgMap[path]; p != nil {
 		if name != "" && p.Name != name {
 			panic(fmt.Sprintf("conflicting package names %s and %s for path %q", p.Name, name, path))
@@ -54,7 +73,10 @@ func NewPkg(path, name string) *Pkg {
 		// This particularly helps with the go.shape package.
 		p.Prefix = path
 	} else {
-		p.Prefix = objabi.PathToPrefix(path)
+		p.Prefix = objabi.PathToPrefix(LinkPkgPath(path))
+		if prefix := objabi.PathToPrefix(path); p.Prefix != prefix {
+			base.Ctxt.SetPkgLinkPrefix(prefix, p.Prefix)
+		}
 	}
 	p.Syms = make(map[string]*Sym)
 	pkgMap[path] = p
@@ -62,9 +84,117 @@ func NewPkg(path, name string) *Pkg {
 	return p
 }
 
+// PkgPathHook, if non-nil, maps the import path of a package to the
+// path by which the compiled output names the package: in the
+// prefixes of its symbol names, and thus in the names of functions
+// and types that tracebacks and DWARF derive from them, and in the
+// package paths recorded in runtime type data. All packages linked
+// into a binary must be compiled with the same hook, or their symbols
+// will not resolve. The compiler sets it for -pkgpathmap.
+//
+// The hook does not apply to packages, like those of the standard
+// library and main, whose paths do not start with a domain name: the
+// linker and runtime refer to their symbols by name. Symbols named
+// explicitly, by //go:linkname directives or assembly, are not
+// rewritten either.
+var PkgPathHook func(path string) string
+
+// LinkPkgPath returns the path by which the compiled output names the
+// package with the given import path. See PkgPathHook.
+func LinkPkgPath(path string) string {
+	if PkgPathHook == nil || !hasDomain(path) {
+		return path
+	}
+	return PkgPathHook(path)
+}
+
+// hasDomain reports whether the first element of path looks like a
+// domain name.
+func hasDomain(path string) bool {
+	if strings.HasPrefix(path, "go.") {
+		return false // compiler-internal package
+	}
+	elem := path
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		elem = path[:i]
+	}
+	return strings.Contains(elem, ".")
+}
+
+// ParsePkgPathMap parses the argument of -pkgpathmap, a ;-separated
+// list of rules of the form prefix=>replacement, and returns a
+// PkgPathHook that applies them. A rule applies to the paths equal to
+// prefix or starting with prefix and a slash, and replaces prefix in
+// them; the first rule that applies wins. Each replacement must also
+// start with a domain name, so that rewritten paths cannot collide
+// with those of the standard library.
+func ParsePkgPathMap(rules string) (func(path string) string, error) {
+	type rule struct{ prefix, replace string }
+	var list []rule
+	for _, r := range strings.Split(rules, ";") {
+		i := strings.Index(r, "=>")
+		if i < 0 {
+			return nil, fmt.Errorf("rule %q is not of the form prefix=>replacement", r)
+		}
+		prefix, replace := r[:i], r[i+len("=>"):]
+		if !hasDomain(prefix) || !hasDomain(replace) {
+			return nil, fmt.Errorf("rule %q: paths must start with a domain name", r)
+		}
+		list = append(list, rule{prefix, replace})
+	}
+	return func(path string) string {
+		for _, r := range list {
+			if path == r.prefix {
+				return r.replace
+			}
+			if strings.HasPrefix(path, r.prefix) && path[len(r.prefix)] == '/' {
+				return r.replace + path[len(r.prefix):]
+			}
+		}
+		return path
+	}, nil
+}
+
+// AddAlias records that the source file named file imports pkg under
+// the name alias. Blank and dot imports do not name the package and
+// are not recorded.
+func (pkg *Pkg) AddAlias(file, alias string) {
+	if alias == "_" || alias == "." {
+		return
+	}
+	pkg.aliasMu.Lock()
+	defer pkg.aliasMu.Unlock()
+	if pkg.Aliases == nil {
+		pkg.Aliases = make(map[string]string)
+	}
+	pkg.Aliases[file] = alias
+}
+
+// AliasIn returns the name by which the source file named file refers
+// to pkg: the name given in its import declaration, if any, and
+// otherwise the package name.
+func (pkg *Pkg) AliasIn(file string) string {
+	pkg.aliasMu.Lock()
+	defer pkg.aliasMu.Unlock()
+	if alias, ok := pkg.Aliases[file]; ok {
+		return alias
+	}
+	return pkg.Name
+}
+
+// existingPkg returns the package with the given path, or nil if
+// there is none.
+func existingPkg(path string) *Pkg {
+	pkgMapMu.Lock()
+	defer pkgMapMu.Unlock()
+	return pkgMap[path]
+}
+
 // ImportedPkgList returns the list of directly imported packages.
 // The list is sorted by package path.
 func ImportedPkgList() []*Pkg {
+	pkgMapMu.Lock()
+	defer pkgMapMu.Unlock()
 	var list []*Pkg
 	for _, p := range pkgMap {
 		if p.Direct {
@@ -96,44 +226,75 @@ func (pkg *Pkg) LookupOK(name string) (s *Sym, existed bool) {
 	if pkg == nil {
 		pkg = nopkg
 	}
+	pkg.symsMu.Lock()
+	defer pkg.symsMu.Unlock()
 	if s := pkg.Syms[name]; s != nil {
 		return s, true
 	}
 
-	s = &Sym{
-		Name: name,
-		Pkg:  pkg,
-	}
+	s = allocSym()
+	s.Name = name
+	s.Pkg = pkg
 	pkg.Syms[name] = s
 	return s, false
 }
 
+// SortedSyms returns the symbols of pkg sorted by name, so that code
+// that visits them, like debug dumps, behaves the same from run to run.
+func (pkg *Pkg) SortedSyms() []*Sym {
+	pkg.symsMu.Lock()
+	syms := make([]*Sym, 0, len(pkg.Syms))
+	for _, s := range pkg.Syms {
+		syms = append(syms, s)
+	}
+	pkg.symsMu.Unlock()
+	sort.Sort(symsByName(syms))
+	return syms
+}
+
+type symsByName []*Sym
+
+func (a symsByName) Len() int           { return len(a) }
+func (a symsByName) Less(i, j int) bool { return a[i].Name < a[j].Name }
+func (a symsByName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
 func (pkg *Pkg) LookupBytes(name []byte) *Sym {
 	// TODO(gri) remove this check in favor of specialized lookup
 	if pkg == nil {
 		pkg = nopkg
 	}
-	if s := pkg.Syms[string(name)]; s != nil {
+	pkg.symsMu.Lock()
+	s := pkg.Syms[string(name)]
+	pkg.symsMu.Unlock()
+	if s != nil {
 		return s
 	}
 	str := InternString(name)
 	return pkg.Lookup(str)
 }
 
-var (
-	internedStringsmu sync.Mutex // protects internedStrings
-	internedStrings   = map[string]string{}
-)
-
-func InternString(b []byte) string {
-	internedStringsmu.Lock()
-	s, ok := internedStrings[string(b)] // string(b) here doesn't allocate
-	if !ok {
-		s = string(b)
-		internedStrings[s] = s
+// DefineOnce looks up name in pkg and, if the symbol has no
+// definition yet, calls define to give it one, which define must
+// record in the symbol's Def field. It reports whether it called
+// define. Concurrent calls for the same name call define at most
+// once, so goroutines that instantiate the same type or function
+// agree on a single definition.
+//
+// The definition may not be complete when DefineOnce returns to a
+// caller that did not define it; see for example how recursive
+// generic types are instantiated.
+func (pkg *Pkg) DefineOnce(name string, define func(*Sym)) (*Sym, bool) {
+	s := pkg.Lookup(name)
+	if pkg == nil {
+		pkg = nopkg
 	}
-	internedStringsmu.Unlock()
-	return s
+	pkg.defMu.Lock()
+	defer pkg.defMu.Unlock()
+	if s.Def != nil {
+		return s, false
+	}
+	define(s)
+	return s, true
 }
 
 // CleanroomDo invokes f in an environment with no preexisting packages.
@@ -0,0 +1,63 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/types"
+)
+
+func TestParsePkgPathMap(t *testing.T) {
+	hook, err := types.ParsePkgPathMap("example.com/a=>x.invalid/1;example.com=>x.invalid/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(saved func(string) string) { types.PkgPathHook = saved }(types.PkgPathHook)
+	types.PkgPathHook = hook
+
+	for _, test := range []struct {
+		path, want string
+	}{
+		{"example.com/a", "x.invalid/1"},
+		{"example.com/a/b", "x.invalid/1/b"},
+		{"example.com/ab", "x.invalid/2/ab"},
+		{"example.com", "x.invalid/2"},
+		{"example.org/a", "example.org/a"},
+		{"EXAMPLE.com/a", "EXAMPLE.com/a"},
+		// Not rewritten: no domain name.
+		{"fmt", "fmt"},
+		{"main", "main"},
+		{"go.shape", "go.shape"},
+	} {
+		if got := types.LinkPkgPath(test.path); got != test.want {
+			t.Errorf("LinkPkgPath(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+
+	for _, rules := range []string{"", "example.com/a", "example.com/a=>x;", "fmt=>x.invalid", "example.com=>fmt"} {
+		if _, err := types.ParsePkgPathMap(rules); err == nil {
+			t.Errorf("ParsePkgPathMap(%q) succeeded, want error", rules)
+		}
+	}
+}
+
+func TestPkgSyms(t *testing.T) {
+	pkg := types.NewPkg("TestPkgSyms", "p")
+	for _, name := range []string{"c", "a", "B", "b"} {
+		pkg.Lookup(name)
+	}
+	var names []string
+	for _, s := range pkg.SortedSyms() {
+		if s.Pkg != pkg {
+			t.Errorf("%v: Pkg = %v, want %v", s, s.Pkg, pkg)
+		}
+		names = append(names, s.Name)
+	}
+	if got, want := strings.Join(names, " "), "B a b c"; got != want {
+		t.Errorf("SortedSyms() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import "fmt"
+
+// This file implements the type predicates of the Go spec on *Type,
+// mirroring their namesakes in go/types. Unlike the typechecker's
+// Assignop and Convertop, they only report whether an operation is
+// valid, not how to implement it, so they may be used by any pass
+// after typechecking.
+//
+// The predicates that consult method sets (Implements, and hence
+// AssignableTo and ConvertibleTo for interface types) expect the
+// method sets of interfaces to already have been computed by CalcSize.
+
+// Comparable reports whether values of type t are comparable with
+// == and !=.
+func (t *Type) Comparable() bool {
+	return comparable(t, nil)
+}
+
+func comparable(t *Type, seen map[*Type]bool) bool {
+	if t == nil || t.Broke() {
+		return true
+	}
+	switch t.Kind() {
+	case TFUNC, TMAP, TSLICE, TANY, TFORW:
+		return false
+	case TARRAY:
+		return comparable(t.Elem(), seen)
+	case TSTRUCT:
+		for _, f := range t.FieldSlice() {
+			if !comparable(f.Type, seen) {
+				return false
+			}
+		}
+		return true
+	case TTYPEPARAM:
+		if t.Underlying() != t {
+			return comparable(t.Underlying(), seen)
+		}
+		if seen[t] {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[*Type]bool)
+		}
+		seen[t] = true
+		return boundComparable(t.Bound(), seen)
+	}
+	return true
+}
+
+// boundComparable reports whether every type in the type set of the
+// constraint bound is comparable.
+func boundComparable(bound *Type, seen map[*Type]bool) bool {
+	if bound == nil {
+		return false
+	}
+	if bound == ComparableType {
+		return true
+	}
+	for _, m := range bound.Methods().Slice() {
+		if m.Sym != nil || m.Type == nil {
+			continue
+		}
+		et := m.Type
+		switch {
+		case et == ComparableType:
+			return true
+		case et.IsUnion():
+			all := true
+			for i := 0; i < et.NumTerms(); i++ {
+				term, _ := et.Term(i)
+				if !comparable(term, seen) {
+					all = false
+					break
+				}
+			}
+			if all {
+				return true
+			}
+		case et.IsInterface():
+			if boundComparable(et, seen) {
+				return true
+			}
+		default:
+			if comparable(et, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Implements reports whether type t implements interface iface.
+func (t *Type) Implements(iface *Type) bool {
+	return implements(t, iface, nil)
+}
+
+// implements reports whether t implements iface. If not and why is
+// not nil, it sets *why to the reason.
+func implements(t, iface *Type, why *string) bool {
+	if t == nil || iface == nil || !iface.IsInterface() {
+		return false
+	}
+
+	if t.IsInterface() || t.IsTypeParam() {
+		tt := t
+		if t.IsTypeParam() && t.Underlying() == t {
+			// A type parameter satisfies an interface if its
+			// bound has all the methods of that interface.
+			tt = t.Bound()
+		}
+		tms := tt.AllMethods().Slice()
+		i := 0
+		for _, im := range iface.AllMethods().Slice() {
+			for i < len(tms) && tms[i].Sym != im.Sym {
+				i++
+			}
+			if i == len(tms) {
+				if why != nil {
+					*why = fmt.Sprintf("%v does not implement %v (missing %v method)", t, iface, im.Sym)
+				}
+				return false
+			}
+			if !Identical(tms[i].Type, im.Type) {
+				if why != nil {
+					_, diff := IdenticalReason(tms[i].Type, im.Type)
+					*why = fmt.Sprintf("%v does not implement %v (wrong type for %v method)\n\thave %v%S\n\twant %v%S\n\t%s",
+						t, iface, im.Sym, tms[i].Sym, tms[i].Type, im.Sym, im.Type, diff)
+				}
+				return false
+			}
+		}
+		return true
+	}
+
+	rt := ReceiverBaseType(t)
+	var tms []*Field
+	if rt != nil {
+		CalcMethods(rt)
+		tms = rt.AllMethods().Slice()
+	}
+	i := 0
+	for _, im := range iface.AllMethods().Slice() {
+		if im.Broke() {
+			continue
+		}
+		for i < len(tms) && tms[i].Sym != im.Sym {
+			i++
+		}
+		if i == len(tms) {
+			if why != nil {
+				*why = fmt.Sprintf("%v does not implement %v (missing %v method)", t, iface, im.Sym)
+			}
+			return false
+		}
+		tm := tms[i]
+		if tm.Nointerface() {
+			if why != nil {
+				*why = fmt.Sprintf("%v does not implement %v (%v method is marked 'nointerface')", t, iface, im.Sym)
+			}
+			return false
+		}
+		if !Identical(tm.Type, im.Type) {
+			if why != nil {
+				_, diff := IdenticalReason(tm.Type, im.Type)
+				*why = fmt.Sprintf("%v does not implement %v (wrong type for %v method)\n\thave %v%S\n\twant %v%S\n\t%s",
+					t, iface, im.Sym, tm.Sym, tm.Type, im.Sym, im.Type, diff)
+			}
+			return false
+		}
+		// A method with a pointer receiver is not in the method set
+		// of the value type, unless it is promoted through an
+		// embedded pointer.
+		followptr := tm.Embedded == 2
+		if tm.Type.Recv().Type.IsPtr() && !t.IsPtr() && !followptr && !IsInterfaceMethod(tm.Type) {
+			if why != nil {
+				*why = fmt.Sprintf("%v does not implement %v (%v method has pointer receiver)", t, iface, im.Sym)
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// AssignableTo reports whether a value of type t is assignable to a
+// variable of type dst.
+func (t *Type) AssignableTo(dst *Type) bool {
+	return assignableTo(t, dst, nil)
+}
+
+// AssignableTo reports whether a value of type src is assignable to a
+// variable of type dst. If not, it may also return a reason, such as
+// a method that src is missing; if the types alone explain the
+// failure, the reason is empty.
+func AssignableTo(src, dst *Type) (bool, string) {
+	var why string
+	ok := assignableTo(src, dst, &why)
+	return ok, why
+}
+
+// assignableTo reports whether src is assignable to dst. If not and
+// why is not nil, it sets *why to the reason, if there is one.
+func assignableTo(t, dst *Type, why *string) bool {
+	if t == dst {
+		return true
+	}
+	if t == nil || dst == nil || t.Kind() == TFORW || dst.Kind() == TFORW || t.Underlying() == nil || dst.Underlying() == nil {
+		return false
+	}
+
+	// t is identical to dst.
+	if Identical(t, dst) {
+		return true
+	}
+
+	// t and dst have identical underlying types and either is not a
+	// named type, or at least one is a shape type.
+	if Identical(t.Underlying(), dst.Underlying()) {
+		if t.Sym() == nil || dst.Sym() == nil || t.IsShape() || dst.IsShape() {
+			return true
+		}
+	}
+
+	// dst is an interface type and t implements dst.
+	if dst.IsInterface() && t.Kind() != TNIL {
+		if t.IsShape() {
+			return true
+		}
+		if implements(t, dst, why) {
+			return true
+		}
+		if why != nil && t.IsPtr() && t.Elem().IsInterface() {
+			*why = fmt.Sprintf("%v is pointer to interface, not interface", t)
+		}
+		return false
+	}
+
+	if dst.IsPtr() && dst.Elem().IsInterface() && t.Kind() != TNIL {
+		if why != nil {
+			*why = fmt.Sprintf("%v is pointer to interface, not interface", dst)
+		}
+		return false
+	}
+
+	if t.IsInterface() && dst.Kind() != TBLANK {
+		if why != nil && implements(dst, t, nil) {
+			*why = "need type assertion"
+		}
+		return false
+	}
+
+	// t is a bidirectional channel value, dst is a channel type, they
+	// have identical element types, and either is not a named type.
+	if t.IsChan() && t.ChanDir() == Cboth && dst.IsChan() {
+		if Identical(t.Elem(), dst.Elem()) && (t.Sym() == nil || dst.Sym() == nil) {
+			return true
+		}
+	}
+
+	// t is the predeclared identifier nil and dst is a nillable type.
+	if t.Kind() == TNIL {
+		switch dst.Kind() {
+		case TPTR, TFUNC, TMAP, TCHAN, TINTER, TSLICE:
+			return true
+		}
+	}
+
+	// Any typed value can be assigned to the blank identifier.
+	return dst.Kind() == TBLANK
+}
+
+// ConvertibleTo reports whether a non-constant value of type t is
+// convertible to type dst.
+func (t *Type) ConvertibleTo(dst *Type) bool {
+	return convertibleTo(t, dst, nil)
+}
+
+// ConvertibleTo reports whether a non-constant value of type src is
+// convertible to type dst. If not, it may also return a reason, as
+// AssignableTo does.
+func ConvertibleTo(src, dst *Type) (bool, string) {
+	var why string
+	ok := convertibleTo(src, dst, &why)
+	return ok, why
+}
+
+// convertibleTo reports whether src is convertible to dst. If not and
+// why is not nil, it sets *why to the reason, if there is one.
+func convertibleTo(t, dst *Type, why *string) bool {
+	if t == dst {
+		return true
+	}
+	if t == nil || dst == nil {
+		return false
+	}
+
+	// Conversions from regular to go:notinheap are not allowed
+	// (unless it's unsafe.Pointer).
+	if t.IsPtr() && dst.IsPtr() && dst.Elem().NotInHeap() && !t.Elem().NotInHeap() {
+		if why != nil {
+			*why = fmt.Sprintf("%v is incomplete (or unallocatable), but %v is not", dst.Elem(), t.Elem())
+		}
+		return false
+	}
+	if t.IsString() && dst.IsSlice() && dst.Elem().NotInHeap() && (dst.Elem().Kind() == ByteType.Kind() || dst.Elem().Kind() == RuneType.Kind()) {
+		if why != nil {
+			*why = fmt.Sprintf("%v is incomplete (or unallocatable)", dst.Elem())
+		}
+		return false
+	}
+
+	if assignableTo(t, dst, why) {
+		return true
+	}
+
+	// The rules for interfaces are no different in conversions
+	// than assignments, so keep the reason assignableTo gave.
+	if t.IsInterface() || dst.IsInterface() {
+		return false
+	}
+	if why != nil {
+		*why = ""
+	}
+
+	// Ignoring struct tags, t and dst have identical underlying types.
+	if IdenticalIgnoreTags(t.Underlying(), dst.Underlying()) {
+		return true
+	}
+
+	// t and dst are unnamed pointer types and, ignoring struct tags,
+	// their base types have identical underlying types.
+	if t.IsPtr() && dst.IsPtr() && t.Sym() == nil && dst.Sym() == nil {
+		if IdenticalIgnoreTags(t.Elem().Underlying(), dst.Elem().Underlying()) {
+			return true
+		}
+	}
+
+	// t and dst are both integer or floating point types, or both
+	// complex types.
+	if (t.IsInteger() || t.IsFloat()) && (dst.IsInteger() || dst.IsFloat()) {
+		return true
+	}
+	if t.IsComplex() && dst.IsComplex() {
+		return true
+	}
+
+	// t is an integer or has type []byte or []rune and dst is a
+	// string type, or vice versa.
+	if t.IsInteger() && dst.IsString() {
+		return true
+	}
+	if t.IsSlice() && dst.IsString() || t.IsString() && dst.IsSlice() {
+		s := t
+		if t.IsString() {
+			s = dst
+		}
+		if k := s.Elem().Kind(); k == ByteType.Kind() || k == RuneType.Kind() {
+			return true
+		}
+	}
+
+	// t is a pointer or uintptr and dst is unsafe.Pointer, or vice versa.
+	if (t.IsPtr() || t.IsUintptr()) && dst.IsUnsafePtr() {
+		return true
+	}
+	if t.IsUnsafePtr() && (dst.IsPtr() || dst.IsUintptr()) {
+		return true
+	}
+
+	// t is a map and dst is a pointer to the corresponding hmap.
+	// This is an implementation detail of gc maps.
+	if t.IsMap() && dst.IsPtr() && t.MapType().Hmap == dst.Elem() {
+		return true
+	}
+
+	// t is a slice and dst is a pointer to an array with the same
+	// element type.
+	if t.IsSlice() && dst.IsPtr() && dst.Elem().IsArray() && Identical(t.Elem(), dst.Elem().Elem()) {
+		return true
+	}
+
+	return false
+}
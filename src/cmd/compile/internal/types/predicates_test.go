@@ -0,0 +1,126 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"testing"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestPredicates(t *testing.T) {
+	var (
+		i     = types.Types[types.TINT]
+		f     = types.Types[types.TFLOAT64]
+		s     = types.Types[types.TSTRING]
+		bytes = types.NewSlice(types.ByteType)
+		m     = types.NewMap(s, i)
+		empty = types.Types[types.TINTER]
+		obj   = ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, types.NewPkg("p", "p").Lookup("T"))
+		named = types.NewNamed(obj)
+	)
+	obj.SetType(named)
+	named.SetUnderlying(i)
+
+	comparable := []struct {
+		t    *types.Type
+		want bool
+	}{
+		{i, true},
+		{s, true},
+		{empty, true},
+		{types.NewArray(s, 3), true},
+		{bytes, false},
+		{m, false},
+		{types.NewArray(bytes, 1), false},
+	}
+	for _, test := range comparable {
+		if got := test.t.Comparable(); got != test.want {
+			t.Errorf("%v.Comparable() = %v, want %v", test.t, got, test.want)
+		}
+	}
+
+	pairs := []struct {
+		src, dst                *types.Type
+		assignable, convertible bool
+	}{
+		{i, i, true, true},
+		{i, f, false, true},
+		{i, named, false, true},
+		{named, i, false, true},
+		{i, empty, true, true},
+		{bytes, s, false, true},
+		{s, bytes, false, true},
+		{m, s, false, false},
+		{types.Types[types.TNIL], m, true, true},
+		{types.NewPtr(i), types.Types[types.TUNSAFEPTR], false, true},
+	}
+	for _, test := range pairs {
+		if got := test.src.AssignableTo(test.dst); got != test.assignable {
+			t.Errorf("%v.AssignableTo(%v) = %v, want %v", test.src, test.dst, got, test.assignable)
+		}
+		if got := test.src.ConvertibleTo(test.dst); got != test.convertible {
+			t.Errorf("%v.ConvertibleTo(%v) = %v, want %v", test.src, test.dst, got, test.convertible)
+		}
+	}
+}
+
+func TestPredicateReasons(t *testing.T) {
+	pkg := types.NewPkg("r", "r")
+	sig := func(recv *types.Type) *types.Type {
+		return types.NewSignature(pkg, types.NewField(src.NoXPos, nil, recv), nil, nil, nil)
+	}
+	iface := func(names ...string) *types.Type {
+		var ms []*types.Field
+		for _, name := range names {
+			ms = append(ms, types.NewField(src.NoXPos, pkg.Lookup(name), sig(types.FakeRecvType())))
+		}
+		typ := types.NewInterface(pkg, ms, false)
+		types.CalcSize(typ)
+		return typ
+	}
+
+	// type T int; func (T) M()
+	obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup("T"))
+	named := types.NewNamed(obj)
+	obj.SetType(named)
+	named.SetUnderlying(types.Types[types.TINT])
+	m := types.NewField(src.NoXPos, pkg.Lookup("M"), sig(named))
+	named.Methods().Set([]*types.Field{m})
+	named.SetAllMethods([]*types.Field{m})
+
+	im, imn := iface("M"), iface("M", "N")
+
+	// interface{ M(int) }
+	params := []*types.Field{types.NewField(src.NoXPos, nil, types.Types[types.TINT])}
+	mInt := types.NewField(src.NoXPos, pkg.Lookup("M"), types.NewSignature(pkg, types.NewField(src.NoXPos, nil, types.FakeRecvType()), nil, params, nil))
+	imInt := types.NewInterface(pkg, []*types.Field{mInt}, false)
+	types.CalcSize(imInt)
+
+	tests := []struct {
+		f        func(src, dst *types.Type) (bool, string)
+		src, dst *types.Type
+		ok       bool
+		why      string
+	}{
+		{types.AssignableTo, named, im, true, ""},
+		{types.AssignableTo, named, imn, false, "r.T does not implement interface { M(); N() } (missing r.N method)"},
+		{types.AssignableTo, named, imInt, false, "r.T does not implement interface { M(int) } (wrong type for r.M method)\n\thave r.M()\n\twant r.M(int)\n\tparameter count 0 vs 1"},
+		{types.AssignableTo, types.NewPtr(im), types.Types[types.TINTER], true, ""},
+		{types.AssignableTo, types.NewPtr(im), im, false, "*interface { M() } is pointer to interface, not interface"},
+		{types.AssignableTo, im, named, false, "need type assertion"},
+		{types.AssignableTo, types.Types[types.TINT], types.Types[types.TSTRING], false, ""},
+		{types.ConvertibleTo, types.Types[types.TINT], types.Types[types.TSTRING], true, ""},
+		{types.ConvertibleTo, named, imn, false, "r.T does not implement interface { M(); N() } (missing r.N method)"},
+	}
+	for _, test := range tests {
+		ok, why := test.f(test.src, test.dst)
+		if ok != test.ok || why != test.why {
+			t.Errorf("%v -> %v: got %v, %q; want %v, %q", test.src, test.dst, ok, why, test.ok, test.why)
+		}
+	}
+}
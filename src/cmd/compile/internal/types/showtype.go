@@ -0,0 +1,96 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"cmd/compile/internal/base"
+	"cmd/internal/objabi"
+)
+
+// shownTypes holds the named types selected by -d=showtype, in the
+// order they were constructed.
+var shownTypes []*Type
+
+// noteShowType records t for ShowTypes if it is selected by
+// -d=showtype.
+func noteShowType(t *Type) {
+	if base.Debug.ShowType != "" && showTypeMatch(t.sym, base.Debug.ShowType) {
+		shownTypes = append(shownTypes, t)
+	}
+}
+
+// showTypeMatch reports whether sym names the type selected by query,
+// which is of the form pkg.Name. Pkg may be the package's name or its
+// import path. Instances of a generic type match the query naming the
+// generic type.
+func showTypeMatch(sym *Sym, query string) bool {
+	end := len(query)
+	if j := strings.IndexByte(query, '['); j >= 0 {
+		end = j
+	}
+	i := strings.LastIndex(query[:end], ".")
+	if i < 0 {
+		return false
+	}
+	pkg, name := query[:i], query[i+1:]
+
+	sname := sym.Name
+	if j := strings.IndexByte(sname, '['); j >= 0 && !strings.Contains(name, "[") {
+		sname = sname[:j]
+	}
+	if sname != name {
+		return false
+	}
+
+	path := sym.Pkg.Path
+	if sym.Pkg == LocalPkg {
+		path = base.Ctxt.Pkgpath
+	}
+	return pkg == sym.Pkg.Name || pkg == path
+}
+
+// ShowTypes prints every representation of each type selected by
+// -d=showtype that has been constructed so far: its Go syntax, debug
+// syntax, link and name strings, hash, size and alignment, and the
+// symbols of its methods. Each type is printed only once.
+func ShowTypes() {
+	var buf bytes.Buffer
+	for _, t := range shownTypes {
+		if t.Kind() == TFORW || t.Broke() {
+			continue
+		}
+		if !t.HasTParam() {
+			CalcSize(t)
+		}
+		fmt.Fprintf(&buf, "%v: showtype %v\n", base.FmtPos(t.Pos()), t)
+		fmt.Fprintf(&buf, "\tgo:         %v\n", t)
+		fmt.Fprintf(&buf, "\tunderlying: %L\n", t)
+		fmt.Fprintf(&buf, "\tdebug:      %+v\n", t.Underlying())
+		fmt.Fprintf(&buf, "\tlink:       %s\n", t.LinkString())
+		fmt.Fprintf(&buf, "\tname:       %s\n", t.NameString())
+		fmt.Fprintf(&buf, "\thash:       %#08x\n", TypeHash(t))
+		if t.widthCalculated() {
+			fmt.Fprintf(&buf, "\tsize:       %d align %d\n", t.Size(), t.Alignment())
+		}
+		for _, m := range t.Methods().Slice() {
+			if m.Nname == nil {
+				continue
+			}
+			ms := m.Nname.Sym()
+			prefix := ms.Pkg.Prefix
+			if ms.Pkg == LocalPkg && base.Ctxt.Pkgpath != "" {
+				prefix = objabi.PathToPrefix(LinkPkgPath(base.Ctxt.Pkgpath))
+			}
+			fmt.Fprintf(&buf, "\tmethod:     %s.%s\n", prefix, ms.Name)
+		}
+	}
+	shownTypes = nil
+	os.Stdout.Write(buf.Bytes())
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import "testing"
+
+func TestShowTypeMatch(t *testing.T) {
+	pkg := NewPkg("example.com/x/y", "y")
+	tests := []struct {
+		name, query string
+		want        bool
+	}{
+		{"T", "y.T", true},
+		{"T", "example.com/x/y.T", true},
+		{"T", "x.T", false},
+		{"T", "y.U", false},
+		{"T", "T", false},
+		{"List[int]", "y.List", true},
+		{"List[int]", "y.List[int]", true},
+		{"List[int]", "y.List[string]", false},
+		{"List[a.T]", "y.List[a.T]", true},
+	}
+	for _, test := range tests {
+		if got := showTypeMatch(pkg.Lookup(test.name), test.query); got != test.want {
+			t.Errorf("showTypeMatch(%s, %q) = %v, want %v", test.name, test.query, got, test.want)
+		}
+	}
+}
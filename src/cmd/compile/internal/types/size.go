@@ -10,6 +10,7 @@ import (
 	"sort"
 
 	"cmd/compile/internal/base"
+	"cmd/compile/internal/errcode"
 	"cmd/internal/src"
 )
 
@@ -85,7 +86,8 @@ func expandiface(t *Type) {
 		case AllowsGoVersion(t.Pkg(), 1, 14) && !explicit && Identical(m.Type, prev.Type):
 			return
 		default:
-			base.ErrorfAt(m.Pos, "duplicate method %s", m.Sym.Name)
+			related := []base.Related{{Pos: prev.Pos, Msg: "other declaration of " + m.Sym.Name}}
+			base.ErrorfAtDetails(m.Pos, errcode.DuplicateMethod, base.ErrorDetails{Related: related}, "duplicate method %s", m.Sym.Name)
 		}
 		methods = append(methods, m)
 	}
@@ -134,7 +136,7 @@ func expandiface(t *Type) {
 			if AllowsGoVersion(t.Pkg(), 1, 18) {
 				continue
 			}
-			base.ErrorfAt(m.Pos, "interface contains embedded non-interface, non-union %v", m.Type)
+			base.ErrorfAt(m.Pos, 0, "interface contains embedded non-interface, non-union %v", m.Type)
 			m.SetBroke(true)
 			t.SetBroke(true)
 			// Add to fields so that error messages
@@ -163,7 +165,7 @@ func expandiface(t *Type) {
 	sort.Sort(MethodsByName(methods))
 
 	if int64(len(methods)) >= MaxWidth/int64(PtrSize) {
-		base.ErrorfAt(typePos(t), "interface too large")
+		base.ErrorfAt(typePos(t), errcode.TypeTooLarge, "interface too large")
 	}
 	for i, m := range methods {
 		m.Offset = int64(i) * int64(PtrSize)
@@ -214,7 +216,7 @@ func calcStructOffset(errtype *Type, t *Type, o int64, flag int) int64 {
 			maxwidth = 1<<31 - 1
 		}
 		if o >= maxwidth {
-			base.ErrorfAt(typePos(errtype), "type %L too large", errtype)
+			base.ErrorfAt(typePos(errtype), errcode.TypeTooLarge, "type %L too large", errtype)
 			o = 8 // small but nonzero
 		}
 	}
@@ -328,7 +330,7 @@ func reportTypeLoop(t *Type) {
 		t.SetBroke(true)
 	}
 	fmt.Fprintf(&msg, "\t%v: %v", base.FmtPos(typePos(l[0])), l[0])
-	base.ErrorfAt(typePos(l[0]), msg.String())
+	base.ErrorfAt(typePos(l[0]), errcode.InvalidDeclCycle, msg.String())
 }
 
 // CalcSize calculates and stores the size and alignment for t.
@@ -459,7 +461,7 @@ func CalcSize(t *Type) {
 		t1 := t.ChanArgs()
 		CalcSize(t1) // just in case
 		if t1.Elem().width >= 1<<16 {
-			base.ErrorfAt(typePos(t1), "channel element type too large (>64kB)")
+			base.ErrorfAt(typePos(t1), errcode.TypeTooLarge, "channel element type too large (>64kB)")
 		}
 		w = 1 // anything will do
 
@@ -492,7 +494,7 @@ func CalcSize(t *Type) {
 		if t.Elem().width != 0 {
 			cap := (uint64(MaxWidth) - 1) / uint64(t.Elem().width)
 			if uint64(t.NumElem()) > cap {
-				base.ErrorfAt(typePos(t), "type %L larger than address space", t)
+				base.ErrorfAt(typePos(t), errcode.TypeTooLarge, "type %L larger than address space", t)
 			}
 		}
 		w = t.NumElem() * t.Elem().width
@@ -539,7 +541,7 @@ func CalcSize(t *Type) {
 	}
 
 	if PtrSize == 4 && w != int64(int32(w)) {
-		base.ErrorfAt(typePos(t), "type %v too large", t)
+		base.ErrorfAt(typePos(t), errcode.TypeTooLarge, "type %v too large", t)
 	}
 
 	t.width = w
@@ -0,0 +1,60 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"fmt"
+	"internal/testenv"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/types"
+)
+
+func TestStrictFmt(t *testing.T) {
+	typ := types.Types[types.TINT]
+
+	if verb := os.Getenv("GO_STRICTFMT_TEST_VERB"); verb != "" {
+		// In the child process: format with verb under
+		// -d=strictfmt, which exits the process.
+		base.Debug.StrictFmt = 1
+		switch verb {
+		case "Type":
+			_ = fmt.Sprintf("%d", typ)
+		case "Sym":
+			_ = fmt.Sprintf("%d", types.LocalPkg.Lookup("x"))
+		case "ok":
+			fmt.Printf("%v %S %L\n", typ, typ, typ)
+		}
+		os.Exit(0)
+	}
+
+	// Without -d=strictfmt, a bad verb is reported in the output.
+	if got := fmt.Sprintf("%d", typ); !strings.HasPrefix(got, "%!d(*Type=") {
+		t.Errorf("Sprintf(%%d, int) = %q, want %%!d(*Type=...)", got)
+	}
+
+	testenv.MustHaveExec(t)
+	run := func(verb string) (string, error) {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestStrictFmt$")
+		cmd.Env = append(os.Environ(), "GO_STRICTFMT_TEST_VERB="+verb)
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+	for _, verb := range []string{"Type", "Sym"} {
+		out, err := run(verb)
+		if err == nil || !strings.Contains(out, "internal compiler error: bad verb %d") || !strings.Contains(out, "strictfmt_test.go:") {
+			t.Errorf("%s: got %v, output:\n%s\nwant internal compiler error: bad verb %%d at strictfmt_test.go", verb, err, out)
+		}
+	}
+
+	// Supported verbs are unaffected.
+	if out, err := run("ok"); err != nil || out != "int int int\n" {
+		t.Errorf("supported verbs: got %v, output:\n%s", err, out)
+	}
+}
@@ -57,3 +57,62 @@ func TestSymLess(t *testing.T) {
 		t.Errorf("sorting failed")
 	}
 }
+
+func TestInternStats(t *testing.T) {
+	name := []byte("TestInternStats.name")
+	before := types.ReadInternStats()
+	types.InternString(name)
+	types.InternString(name)
+	after := types.ReadInternStats()
+
+	want := types.InternStats{
+		Lookups: before.Lookups + 2,
+		Hits:    before.Hits + 1,
+		Strings: before.Strings + 1,
+		Bytes:   before.Bytes + int64(len(name)),
+
+		Live:      before.Live + 1,
+		LiveBytes: before.LiveBytes + int64(len(name)),
+		Evicted:   before.Evicted,
+
+		Syms: after.Syms,
+	}
+	if after != want {
+		t.Errorf("after interning a new string twice, stats = %+v, want %+v", after, want)
+	}
+	if after.Syms == 0 {
+		t.Errorf("Syms = 0, want the symbols of the universe")
+	}
+	if r := after.HitRate(); r <= 0 || r >= 1 {
+		t.Errorf("HitRate() = %v, want in (0, 1)", r)
+	}
+}
+
+func TestInternLimit(t *testing.T) {
+	defer types.SetInternLimit(types.SetInternLimit(-1))
+
+	a, b := []byte("TestInternLimit.a"), []byte("TestInternLimit.b")
+	types.InternString(a)
+	types.InternString(b)
+	types.InternString(a) // a is now more recently used than b
+
+	// Shrinking the table to one of the strings evicts the least
+	// recently used one.
+	before := types.ReadInternStats()
+	types.SetInternLimit(int64(len(a)))
+	st := types.ReadInternStats()
+	if st.LiveBytes > int64(len(a)) || st.Evicted <= before.Evicted {
+		t.Fatalf("after SetInternLimit(%d): %d bytes retained, %d evicted", len(a), st.LiveBytes, st.Evicted-before.Evicted)
+	}
+
+	before = types.ReadInternStats()
+	types.InternString(a)
+	types.InternString(b)
+	after := types.ReadInternStats()
+	if got := after.Hits - before.Hits; got != 1 {
+		t.Errorf("got %d hits, want 1 (for a, not for evicted b)", got)
+	}
+	if after.LiveBytes != int64(len(b)) || after.Live != 1 {
+		t.Errorf("retained %d strings, %d bytes; want just b", after.Live, after.LiveBytes)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"strconv"
+	"sync"
+)
+
+// Tag returns the value associated with key in the struct tag of the
+// struct field f, following the conventions of reflect.StructTag: a
+// tag is a list of space-separated key:"value" pairs. If key is not
+// present, or the tag is not in the conventional format, Tag returns
+// "", false.
+//
+// The tag is parsed once, and the result shared between all fields
+// with the same tag. Tag is not meaningful for function parameters,
+// whose Note holds escape analysis tags.
+func (f *Field) Tag(key string) (string, bool) {
+	for _, kv := range parsedTag(f.Note) {
+		if kv.key == key {
+			return kv.value, true
+		}
+	}
+	return "", false
+}
+
+// A tagPair is a key:"value" pair of a struct tag, with the value
+// unquoted.
+type tagPair struct {
+	key, value string
+}
+
+// tagCache maps struct tags to their parsed forms.
+var tagCache struct {
+	sync.Mutex
+	m map[string][]tagPair
+}
+
+// parsedTag returns the key:"value" pairs of the struct tag note.
+func parsedTag(note string) []tagPair {
+	if note == "" {
+		return nil
+	}
+	tagCache.Lock()
+	defer tagCache.Unlock()
+	kvs, ok := tagCache.m[note]
+	if !ok {
+		if tagCache.m == nil {
+			tagCache.m = make(map[string][]tagPair)
+		}
+		kvs = parseTag(note)
+		tagCache.m[note] = kvs
+	}
+	return kvs
+}
+
+// parseTag parses the struct tag note like reflect.StructTag.Lookup.
+// Parsing stops at the first malformed pair. If a key occurs more
+// than once, the first occurrence is returned, so that lookups agree
+// with reflect.
+func parseTag(note string) []tagPair {
+	var kvs []tagPair
+	tag := note
+	for tag != "" {
+		// Skip leading space.
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon. A space, a quote or a control character is a
+		// syntax error.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		qvalue := tag[:i+1]
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			break
+		}
+		if !hasTagKey(kvs, key) {
+			kvs = append(kvs, tagPair{key, value})
+		}
+	}
+	return kvs
+}
+
+func hasTagKey(kvs []tagPair, key string) bool {
+	for _, kv := range kvs {
+		if kv.key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"reflect"
+	"testing"
+
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestFieldTag(t *testing.T) {
+	tags := []string{
+		``,
+		`json:"name"`,
+		`json:"name,omitempty" xml:"n"`,
+		`  go:"track"  `,
+		`json:"a" json:"b"`,
+		`json:"esc\"aped\\"`,
+		`json:"a" bad xml:"b"`,
+		`json:"unterminated`,
+		`json:name`,
+		`:"novalue"`,
+	}
+	keys := []string{"json", "xml", "go", "bad", ""}
+	for _, tag := range tags {
+		f := types.NewField(src.NoXPos, nil, types.Types[types.TINT])
+		f.Note = tag
+		for _, key := range keys {
+			v, ok := f.Tag(key)
+			wv, wok := reflect.StructTag(tag).Lookup(key)
+			if v != wv || ok != wok {
+				t.Errorf("Tag(%q) of `%s` = %q, %v; want %q, %v", key, tag, v, ok, wv, wok)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// TestGenericSignatures checks the rendering of generic function
+// types in each mode against testdata/tparams.golden.
+func TestGenericSignatures(t *testing.T) {
+	pkg := types.NewPkg("g", "g")
+	tparam := func(name string, index int, bound *types.Type) *types.Type {
+		tp := types.NewTypeParam(pkg.Lookup(name), index)
+		tp.SetBound(bound)
+		return tp
+	}
+	field := func(name string, typ *types.Type) *types.Field {
+		var s *types.Sym
+		if name != "" {
+			s = pkg.Lookup(name)
+		}
+		return types.NewField(src.NoXPos, s, typ)
+	}
+	tfields := func(tps ...*types.Type) []*types.Field {
+		var fs []*types.Field
+		for _, tp := range tps {
+			fs = append(fs, field(tp.Sym().Name, tp))
+		}
+		return fs
+	}
+	any := types.Types[types.TINTER]
+
+	// func F[T any, U comparable](x T, y ...U) (T, error)
+	t0, u0 := tparam("T", 0, any), tparam("U", 1, types.ComparableType)
+	y := field("y", types.NewSlice(u0))
+	y.SetIsDDD(true)
+	f := types.NewSignature(pkg, nil, tfields(t0, u0),
+		[]*types.Field{field("x", t0), y},
+		[]*types.Field{field("", t0), field("", types.ErrorType)})
+
+	// func G[A any, B comparable](a A, b ...B) (A, error), which
+	// differs from F only in the names of its type parameters.
+	t1, u1 := tparam("A", 0, any), tparam("B", 1, types.ComparableType)
+	b := field("b", types.NewSlice(u1))
+	b.SetIsDDD(true)
+	g := types.NewSignature(pkg, nil, tfields(t1, u1),
+		[]*types.Field{field("a", t1), b},
+		[]*types.Field{field("", t1), field("", types.ErrorType)})
+
+	// func H[P any, Q interface{ *P }](P) Q, whose second constraint
+	// refers to the first type parameter.
+	p := tparam("P", 0, any)
+	q := tparam("Q", 1, types.NewInterface(pkg, []*types.Field{
+		types.NewField(src.NoXPos, nil, types.NewPtr(p)),
+	}, false))
+	types.CalcSize(q.Bound())
+	h := types.NewSignature(pkg, nil, tfields(p, q),
+		[]*types.Field{field("", p)},
+		[]*types.Field{field("", q)})
+
+	// func (Box) M[V any](V) V, a method with a type parameter of its
+	// own (see GOEXPERIMENT=genericmethods).
+	obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup("Box"))
+	box := types.NewNamed(obj)
+	obj.SetType(box)
+	box.SetUnderlying(types.Types[types.TINT])
+	v := tparam("V", 0, any)
+	m := types.NewSignature(pkg, field("", box), tfields(v),
+		[]*types.Field{field("", v)},
+		[]*types.Field{field("", v)})
+
+	var buf bytes.Buffer
+	for _, test := range []struct {
+		name string
+		typ  *types.Type
+	}{{"F", f}, {"G", g}, {"H", h}, {"[]H", types.NewSlice(h)}, {"M", m}} {
+		fmt.Fprintf(&buf, "%s\n", test.name)
+		fmt.Fprintf(&buf, "\tgo:    %v\n", test.typ)
+		fmt.Fprintf(&buf, "\tshort: %S\n", test.typ)
+		fmt.Fprintf(&buf, "\tdebug: %+v\n", test.typ)
+		fmt.Fprintf(&buf, "\tlink:  %s\n", test.typ.LinkString())
+		fmt.Fprintf(&buf, "\tname:  %s\n", test.typ.NameString())
+	}
+	got := buf.Bytes()
+
+	golden := filepath.Join("testdata", "tparams.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0666); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if f.LinkString() != g.LinkString() {
+		t.Errorf("LinkString of F and G differ: %s vs %s", f.LinkString(), g.LinkString())
+	}
+}
@@ -6,10 +6,13 @@ package types
 
 import (
 	"cmd/compile/internal/base"
+	"cmd/internal/objabi"
 	"cmd/internal/src"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"unsafe"
 )
 
 // Object represents an ir.Node, but without needing to import cmd/compile/internal/ir,
@@ -431,6 +434,12 @@ type Typeparam struct {
 type Union struct {
 	terms  []*Type
 	tildes []bool // whether terms[i] is of form ~T
+
+	// The terms as written, before NewUnion put them in canonical
+	// order. Export data keeps this order, as other importers of it
+	// compare unions by their terms as written.
+	srcTerms  []*Type
+	srcTildes []bool
 }
 
 // Ptr contains Type fields specific to pointer types.
@@ -531,6 +540,18 @@ func (f *Field) IsMethod() bool {
 	return f.Type.kind == TFUNC && f.Type.Recv() != nil
 }
 
+// IsGenericMethod reports whether f represents a method with type
+// parameters of its own, as in func (T) M[P any]() (see
+// GOEXPERIMENT=genericmethods). A method that only uses the type
+// parameters of its generic receiver type is not one. Generic methods
+// are in the Methods of their receiver type, so that they can be
+// selected, but not in its method set: CalcMethods leaves them out of
+// AllMethods, so they neither implement interface methods nor get
+// wrappers or method table entries.
+func (f *Field) IsGenericMethod() bool {
+	return f.Type.kind == TFUNC && f.Type.NumTParams() > 0
+}
+
 // Fields is a pointer to a slice of *Field.
 // This saves space in Types that do not have fields or methods
 // compared to a simple slice of *Field.
@@ -584,10 +605,9 @@ func (f *Fields) Append(s ...*Field) {
 
 // New returns a new Type of the specified kind.
 func newType(et Kind) *Type {
-	t := &Type{
-		kind:  et,
-		width: BADWIDTH,
-	}
+	t := allocType()
+	t.kind = et
+	t.width = BADWIDTH
 	t.underlying = t
 	// TODO(josharian): lazily initialize some of these?
 	switch t.kind {
@@ -638,8 +658,24 @@ func NewArray(elem *Type, bound int64) *Type {
 	return t
 }
 
-// NewSlice returns the slice Type with element type elem.
+// typeCacheLocks protect the cache fields of types, so that NewPtr and
+// NewSlice may be called concurrently. The lock for a type is chosen
+// by its address.
+var typeCacheLocks [64]sync.Mutex
+
+// cacheLock returns the lock that protects t.cache.
+func (t *Type) cacheLock() *sync.Mutex {
+	return &typeCacheLocks[uintptr(unsafe.Pointer(t))>>4%uintptr(len(typeCacheLocks))]
+}
+
+// NewSlice returns the slice Type with element type elem, or with the
+// canonical type identical to elem; see Canonical. It may be called
+// concurrently.
 func NewSlice(elem *Type) *Type {
+	elem = Canonical(elem)
+	mu := elem.cacheLock()
+	mu.Lock()
+	defer mu.Unlock()
 	if t := elem.cache.slice; t != nil {
 		if t.Elem() != elem {
 			base.Fatalf("elem mismatch")
@@ -677,7 +713,13 @@ func NewChan(elem *Type, dir ChanDir) *Type {
 	return t
 }
 
+// NewTuple returns the tuple type of t1 and t2. Tuples of identical
+// types are the same type.
 func NewTuple(t1, t2 *Type) *Type {
+	return canonicalTuple(t1, t2)
+}
+
+func newTuple(t1, t2 *Type) *Type {
 	t := newType(TTUPLE)
 	t.extra.(*Tuple).first = t1
 	t.extra.(*Tuple).second = t2
@@ -729,12 +771,16 @@ func NewMap(k, v *Type) *Type {
 // This allows the backend to run concurrently.
 var NewPtrCacheEnabled = true
 
-// NewPtr returns the pointer type pointing to t.
+// NewPtr returns the pointer type pointing to t. It may be called
+// concurrently.
 func NewPtr(elem *Type) *Type {
 	if elem == nil {
 		base.Fatalf("NewPtr: pointer to elem Type is nil")
 	}
 
+	mu := elem.cacheLock()
+	mu.Lock()
+	defer mu.Unlock()
 	if t := elem.cache.ptr; t != nil {
 		if t.Elem() != elem {
 			base.Fatalf("NewPtr: elem mismatch")
@@ -776,7 +822,8 @@ func NewFuncArgs(f *Type) *Type {
 }
 
 func NewField(pos src.XPos, sym *Sym, typ *Type) *Field {
-	f := &Field{
+	f := allocField()
+	*f = Field{
 		Pos:    pos,
 		Sym:    sym,
 		Type:   typ,
@@ -877,7 +924,8 @@ func (t *Type) copy() *Type {
 	if t == nil {
 		return nil
 	}
-	nt := *t
+	nt := allocType()
+	*nt = *t
 	// copy any *T Extra fields, to avoid aliasing
 	switch t.kind {
 	case TMAP:
@@ -908,14 +956,15 @@ func (t *Type) copy() *Type {
 	}
 	// TODO(mdempsky): Find out why this is necessary and explain.
 	if t.underlying == t {
-		nt.underlying = &nt
+		nt.underlying = nt
 	}
-	return &nt
+	return nt
 }
 
 func (f *Field) Copy() *Field {
-	nf := *f
-	return &nf
+	nf := allocField()
+	*nf = *f
+	return nf
 }
 
 func (t *Type) wantEtype(et Kind) {
@@ -1450,6 +1499,9 @@ func (t *Type) IsPtr() bool {
 
 // IsPtrElem reports whether t is the element of a pointer (to t).
 func (t *Type) IsPtrElem() bool {
+	mu := t.cacheLock()
+	mu.Lock()
+	defer mu.Unlock()
 	return t.cache.ptr != nil
 }
 
@@ -1711,6 +1763,7 @@ func NewNamed(obj TypeObject) *Type {
 		t.SetIsShape(true)
 		t.SetHasShape(true)
 	}
+	noteShowType(t)
 	return t
 }
 
@@ -1788,7 +1841,7 @@ func (t *Type) SetUnderlying(underlying *Type) {
 	// Double-check use of type as embedded type.
 	if ft.Embedlineno.IsKnown() {
 		if t.IsPtr() || t.IsUnsafePtr() {
-			base.ErrorfAt(ft.Embedlineno, "embedded type cannot be a pointer")
+			base.ErrorfAt(ft.Embedlineno, 0, "embedded type cannot be a pointer")
 		}
 	}
 }
@@ -1892,11 +1945,19 @@ func (t *Type) MarkImplicit() {
 
 // NewUnion returns a new union with the specified set of terms (types). If
 // tildes[i] is true, then terms[i] represents ~T, rather than just T.
+//
+// The terms of the union are kept in a canonical order, sorted by their
+// LinkStrings, with identical terms folded into one, and T folded into
+// ~T if both are present. So unions that differ only in the order of
+// their terms are printed, hashed and compared alike.
 func NewUnion(terms []*Type, tildes []bool) *Type {
 	t := newType(TUNION)
 	if len(terms) != len(tildes) {
 		base.Fatalf("Mismatched terms and tildes for NewUnion")
 	}
+	t.extra.(*Union).srcTerms = terms
+	t.extra.(*Union).srcTildes = tildes
+	terms, tildes = canonUnionTerms(terms, tildes)
 	t.extra.(*Union).terms = terms
 	t.extra.(*Union).tildes = tildes
 	nt := len(terms)
@@ -1911,6 +1972,42 @@ func NewUnion(terms []*Type, tildes []bool) *Type {
 	return t
 }
 
+// canonUnionTerms returns the terms of a union, and their tildes, in
+// the canonical order described at NewUnion.
+func canonUnionTerms(terms []*Type, tildes []bool) ([]*Type, []bool) {
+	type term struct {
+		key   string
+		typ   *Type
+		tilde bool
+	}
+	ts := make([]term, len(terms))
+	for i, typ := range terms {
+		ts[i] = term{typ.LinkString(), typ, tildes[i]}
+	}
+	sort.SliceStable(ts, func(i, j int) bool { return ts[i].key < ts[j].key })
+
+	terms = make([]*Type, 0, len(ts))
+	tildes = make([]bool, 0, len(ts))
+	for i, t := range ts {
+		if i > 0 && t.key == ts[i-1].key {
+			n := len(tildes) - 1
+			tildes[n] = tildes[n] || t.tilde
+			continue
+		}
+		terms = append(terms, t.typ)
+		tildes = append(tildes, t.tilde)
+	}
+	return terms, tildes
+}
+
+// SourceTerms returns the terms of a union type, and whether each is of
+// the form ~T, in the order they were given to NewUnion.
+func (t *Type) SourceTerms() ([]*Type, []bool) {
+	t.wantEtype(TUNION)
+	u := t.extra.(*Union)
+	return u.srcTerms, u.srcTildes
+}
+
 // NumTerms returns the number of terms in a union type.
 func (t *Type) NumTerms() int {
 	t.wantEtype(TUNION)
@@ -1945,6 +2042,9 @@ func NewSignature(pkg *Pkg, recv *Field, tparams, params, results []*Field) *Typ
 	ft := t.FuncType()
 
 	funargs := func(fields []*Field, funarg Funarg) *Type {
+		for _, f := range fields {
+			f.Type = Canonical(f.Type)
+		}
 		s := NewStruct(NoPkg, fields)
 		s.StructType().Funarg = funarg
 		if s.Broke() {
@@ -2174,7 +2274,15 @@ func ComplexForFloat(t *Type) *Type {
 }
 
 func TypeSym(t *Type) *Sym {
-	return TypeSymLookup(TypeSymName(t))
+	buf := fmtBufferPool.Get().(*[]byte)
+	b := appendTypeSymName((*buf)[:0], t)
+	s := TypeSymLookupBytes(b)
+	*buf = b
+	fmtBufferPool.Put(buf)
+	if IndexingTypes() {
+		indexTypeSym(t, s)
+	}
+	return s
 }
 
 func TypeSymLookup(name string) *Sym {
@@ -2184,8 +2292,20 @@ func TypeSymLookup(name string) *Sym {
 	return s
 }
 
+// TypeSymLookupBytes is like TypeSymLookup, but takes the name as a
+// byte slice, which it does not retain.
+func TypeSymLookupBytes(name []byte) *Sym {
+	typepkgmu.Lock()
+	s := typepkg.LookupBytes(name)
+	typepkgmu.Unlock()
+	return s
+}
+
 func TypeSymName(t *Type) string {
 	name := t.LinkString()
+	if base.Debug.MangleNames != 0 {
+		name = objabi.MangleTypeName(name)
+	}
 	// Use a separate symbol name for Noalg types for #17752.
 	if TypeHasNoAlg(t) {
 		name = "noalg." + name
@@ -2193,6 +2313,26 @@ func TypeSymName(t *Type) string {
 	return name
 }
 
+// appendTypeSymName appends TypeSymName(t) to b.
+func appendTypeSymName(b []byte, t *Type) []byte {
+	if TypeHasNoAlg(t) {
+		b = append(b, "noalg."...)
+	}
+	return AppendLinkName(b, t)
+}
+
+// AppendLinkName appends to b the description of t used in the names
+// of link symbols: t.LinkString(), mangled with objabi.MangleTypeName
+// if -d=manglenames is set.
+func AppendLinkName(b []byte, t *Type) []byte {
+	start := len(b)
+	b = t.AppendString(b, LinkMode)
+	if base.Debug.MangleNames != 0 {
+		b = objabi.AppendMangledTypeName(b[:start], string(b[start:]))
+	}
+	return b
+}
+
 // Fake package for runtime type info (headers)
 // Don't access directly, use typeLookup below.
 var (
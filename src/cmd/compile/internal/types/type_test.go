@@ -5,6 +5,8 @@
 package types
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -25,3 +27,27 @@ func TestSSACompare(t *testing.T) {
 		}
 	}
 }
+
+func TestBestEffortPanic(t *testing.T) {
+	// A function type without its signature.
+	bad := NewPtr(&Type{kind: TFUNC})
+
+	want := "<malformed PTR: interface conversion: interface {} is nil, not *types.Func>"
+	if got := FormatBestEffort("%v", bad); got != want {
+		t.Errorf("FormatBestEffort: got %s, want %s", got, want)
+	}
+
+	// Other formatting is unaffected, even while formatting on a
+	// best-effort basis.
+	got := FormatBestEffort("%v", formatter(func() string { return fmt.Sprint(bad) }))
+	if !strings.Contains(got, "PANIC=") {
+		t.Errorf("fmt.Sprint in FormatBestEffort: got %s, want a panic", got)
+	}
+}
+
+// A formatter formats as the string that it returns.
+type formatter func() string
+
+func (f formatter) Format(s fmt.State, verb rune) {
+	fmt.Fprint(s, f())
+}
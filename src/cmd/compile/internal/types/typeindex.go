@@ -0,0 +1,151 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cmd/compile/internal/base"
+	"cmd/internal/objabi"
+)
+
+// typeIndex maps the names under which types appear in compiler
+// output (runtime type names; type hashes, as at the end of the type
+// names that -d=namebudget abbreviates in diagnostics; and type
+// descriptor symbols) back to the types. It is only maintained when
+// -d=findtype is set.
+var typeIndex struct {
+	sync.Mutex
+	byName map[string][]*Type
+	byHash map[uint32][]*Type
+	bySym  map[string]*Type
+}
+
+// IndexingTypes reports whether the type index is being maintained.
+func IndexingTypes() bool {
+	return base.Debug.FindType != ""
+}
+
+// indexTypeHash records that the compiler refers to t by its hash,
+// because it abbreviated t's name in a diagnostic.
+func indexTypeHash(t *Type) {
+	if !IndexingTypes() {
+		return
+	}
+	typeIndex.Lock()
+	defer typeIndex.Unlock()
+	addTypeHash(t)
+}
+
+// indexTypeSym records that s is the type descriptor symbol of t, and
+// indexes t by its name and hash.
+func indexTypeSym(t *Type, s *Sym) {
+	typeIndex.Lock()
+	defer typeIndex.Unlock()
+	if typeIndex.bySym == nil {
+		typeIndex.bySym = make(map[string]*Type)
+	}
+	name := s.Pkg.Prefix + "." + s.Name
+	if typeIndex.bySym[name] != nil {
+		return
+	}
+	typeIndex.bySym[name] = t
+	if strings.Contains(name, `"".`) && base.Ctxt.Pkgpath != "" {
+		// Also index the name the linker will give the symbol.
+		local := objabi.PathToPrefix(LinkPkgPath(base.Ctxt.Pkgpath)) + "."
+		typeIndex.bySym[strings.ReplaceAll(name, `"".`, local)] = t
+	}
+	addTypeName(t, t.NameString())
+	addTypeHash(t)
+}
+
+func addTypeName(t *Type, name string) {
+	if typeIndex.byName == nil {
+		typeIndex.byName = make(map[string][]*Type)
+	}
+	typeIndex.byName[name] = appendType(typeIndex.byName[name], t)
+}
+
+func addTypeHash(t *Type) {
+	if typeIndex.byHash == nil {
+		typeIndex.byHash = make(map[uint32][]*Type)
+	}
+	h := TypeHash(t)
+	typeIndex.byHash[h] = appendType(typeIndex.byHash[h], t)
+}
+
+// appendType appends t to list unless it is already there.
+func appendType(list []*Type, t *Type) []*Type {
+	for _, t1 := range list {
+		if t1 == t {
+			return list
+		}
+	}
+	return append(list, t)
+}
+
+// LookupTypeName returns the indexed types referred to by query, which
+// may be a type descriptor symbol name (with or without its "type."
+// prefix), a runtime type name, or a type hash written as an
+// unsigned integer (such as 0x1234abcd) or as the suffix of an
+// abbreviated name (such as #1234abcd).
+func LookupTypeName(query string) []*Type {
+	typeIndex.Lock()
+	defer typeIndex.Unlock()
+
+	var list []*Type
+	for _, name := range []string{query, "type." + query} {
+		if t := typeIndex.bySym[name]; t != nil {
+			list = appendType(list, t)
+		}
+	}
+	for _, t := range typeIndex.byName[query] {
+		list = appendType(list, t)
+	}
+	if h, ok := parseTypeHash(query); ok {
+		for _, t := range typeIndex.byHash[uint32(h)] {
+			list = appendType(list, t)
+		}
+	}
+	return list
+}
+
+// parseTypeHash parses s as a type hash, written either as an unsigned
+// integer or as the "#%08x" suffix of an abbreviated name.
+func parseTypeHash(s string) (uint32, bool) {
+	base := 0
+	if strings.HasPrefix(s, "#") {
+		s, base = s[1:], 16
+	}
+	h, err := strconv.ParseUint(s, base, 32)
+	return uint32(h), err == nil
+}
+
+// FindTypes prints the types referred to by the name given to
+// -d=findtype, along with the symbol and hash of each.
+func FindTypes() {
+	query := base.Debug.FindType
+	list := LookupTypeName(query)
+
+	var buf strings.Builder
+	if len(list) == 0 {
+		fmt.Fprintf(&buf, "findtype %s: no types found\n", query)
+	}
+	var lines []string
+	for _, t := range list {
+		lines = append(lines, fmt.Sprintf("findtype %s: %v\n\tsymbol: %s\n\tname:   %s\n\thash:   %#08x\n",
+			query, t, "type."+TypeSymName(t), t.NameString(), TypeHash(t)))
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		buf.WriteString(line)
+	}
+	os.Stdout.WriteString(buf.String())
+}
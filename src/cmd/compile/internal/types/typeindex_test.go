@@ -0,0 +1,59 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestLookupTypeName(t *testing.T) {
+	defer func(old string) { base.Debug.FindType = old }(base.Debug.FindType)
+	base.Debug.FindType = "x"
+
+	elem := types.NewArray(types.NewSlice(types.Types[types.TINT16]), 100)
+	typ := types.NewMap(types.Types[types.TSTRING], types.NewMap(elem, elem))
+	types.TypeSym(typ)
+
+	for _, query := range []string{
+		"type.map[string]map[[100][]int16][100][]int16",
+		"map[string]map[[100][]int16][100][]int16",
+		fmt.Sprint(types.TypeHash(typ)),
+		fmt.Sprintf("%#x", types.TypeHash(typ)),
+		fmt.Sprintf("#%08x", types.TypeHash(typ)),
+	} {
+		if got := types.LookupTypeName(query); len(got) != 1 || got[0] != typ {
+			t.Errorf("LookupTypeName(%q) = %v, want [%v]", query, got, typ)
+		}
+	}
+	if got := types.LookupTypeName("map[string][]int32"); len(got) != 0 {
+		t.Errorf("LookupTypeName of unindexed type = %v, want none", got)
+	}
+}
+
+func TestLookupAbbrevTypeName(t *testing.T) {
+	defer func(old string) { base.Debug.FindType = old }(base.Debug.FindType)
+	defer func(saved int) { base.Debug.NameBudget = saved }(base.Debug.NameBudget)
+	base.Debug.FindType = "x"
+	base.Debug.NameBudget = 1
+
+	// A type named in a diagnostic, which has no type descriptor,
+	// is found by the hash at the end of its abbreviated name.
+	typ := types.NewMap(types.Types[types.TSTRING], types.NewArray(types.NewSlice(types.Types[types.TUINT16]), 100))
+	typ = types.NewMap(typ, typ)
+	msg := types.FormatMessage(src.NoXPos, "%v", typ)
+	i := strings.LastIndex(msg, "#")
+	if i < 0 {
+		t.Fatalf("%v: name not abbreviated: %s", typ, msg)
+	}
+	if got := types.LookupTypeName(msg[i:]); len(got) != 1 || got[0] != typ {
+		t.Errorf("LookupTypeName(%q) = %v, want [%v]", msg[i:], got, typ)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+// Walk calls fn for t and, in depth-first order, for each type that
+// makes up t: the element type of pointers, arrays, slices and
+// channels; the key and element types of maps; the types of struct
+// fields; the receiver, type parameter, parameter and result types of
+// functions; the types of interface methods and embedded elements;
+// and the terms of unions. If fn returns false, Walk does not visit
+// the types that make up the type passed to fn.
+//
+// Walk visits each type at most once, so it terminates on recursive
+// types. Named types are visited like their underlying type; their
+// type arguments are not visited.
+func Walk(t *Type, fn func(*Type) bool) {
+	w := walker{fn: fn, seen: make(map[*Type]bool)}
+	w.walk(t)
+}
+
+type walker struct {
+	fn   func(*Type) bool
+	seen map[*Type]bool
+}
+
+func (w *walker) walk(t *Type) {
+	if t == nil || w.seen[t] {
+		return
+	}
+	w.seen[t] = true
+	if !w.fn(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case TPTR, TARRAY, TSLICE, TCHAN:
+		w.walk(t.Elem())
+
+	case TMAP:
+		w.walk(t.Key())
+		w.walk(t.Elem())
+
+	case TSTRUCT:
+		w.fields(t)
+
+	case TFUNC:
+		w.fields(t.Recvs())
+		w.fields(t.TParams())
+		w.fields(t.Params())
+		w.fields(t.Results())
+
+	case TINTER:
+		// t.Methods holds the methods and embedded elements as
+		// declared, so this does not force the interface's method
+		// set to be computed.
+		for _, f := range t.Methods().Slice() {
+			w.walk(f.Type)
+		}
+
+	case TUNION:
+		for i := 0; i < t.NumTerms(); i++ {
+			term, _ := t.Term(i)
+			w.walk(term)
+		}
+	}
+}
+
+// fields walks the types of the fields of the struct type t, without
+// visiting t itself. t may be a function argument struct.
+func (w *walker) fields(t *Type) {
+	for _, f := range t.FieldSlice() {
+		w.walk(f.Type)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types_test
+
+import (
+	"testing"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/types"
+	"cmd/internal/src"
+)
+
+func TestWalk(t *testing.T) {
+	pkg := types.NewPkg("w", "w")
+	i8, i16, str := types.Types[types.TINT8], types.Types[types.TINT16], types.Types[types.TSTRING]
+
+	// type Node struct { next *Node; m map[string]int8 }
+	obj := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, pkg.Lookup("Node"))
+	node := types.NewNamed(obj)
+	obj.SetType(node)
+	ptr := types.NewPtr(node)
+	m := types.NewMap(str, i8)
+	node.SetUnderlying(types.NewStruct(pkg, []*types.Field{
+		types.NewField(src.NoXPos, pkg.Lookup("next"), ptr),
+		types.NewField(src.NoXPos, pkg.Lookup("m"), m),
+	}))
+
+	// func(Node, []int16) chan int8
+	slice := types.NewSlice(i16)
+	ch := types.NewChan(i8, types.Cboth)
+	fn := types.NewSignature(pkg, nil, nil, []*types.Field{
+		types.NewField(src.NoXPos, nil, node),
+		types.NewField(src.NoXPos, nil, slice),
+	}, []*types.Field{
+		types.NewField(src.NoXPos, nil, ch),
+	})
+
+	var got []*types.Type
+	types.Walk(fn, func(t *types.Type) bool {
+		got = append(got, t)
+		return true
+	})
+	want := []*types.Type{fn, node, ptr, m, str, i8, slice, i16, ch}
+	if len(got) != len(want) {
+		t.Fatalf("visited %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("visited %v, want %v", got, want)
+		}
+	}
+
+	// Pruning at the struct skips its fields.
+	got = got[:0]
+	types.Walk(fn, func(t *types.Type) bool {
+		got = append(got, t)
+		return t != node
+	})
+	if len(got) != 6 || got[1] != node || got[2] != slice {
+		t.Errorf("visited %v with node pruned", got)
+	}
+}
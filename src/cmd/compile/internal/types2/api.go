@@ -26,6 +26,7 @@ package types2
 
 import (
 	"bytes"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"fmt"
 	"go/constant"
@@ -40,6 +41,44 @@ type Error struct {
 	Msg  string     // default error message, user-friendly
 	Full string     // full error message, for debugging (may contain internal details)
 	Soft bool       // if set, error is "soft"
+
+	// Code identifies the class of the error, or is 0 if the
+	// error belongs to no class in the errcode catalog.
+	Code errcode.Code
+
+	// Details, if not nil, describes the error further.
+	Details *ErrorDetails
+}
+
+// ErrorDetails describes an error further, for tools that suggest
+// fixes for it or point at related declarations. Which fields are set
+// depends on the class of the error.
+type ErrorDetails struct {
+	// Operand is the operand that an assignability error is about.
+	Operand syntax.Expr
+
+	// Types are, for an assignability error, the type of Operand and
+	// the type it cannot be used as, and, for an error of mismatched
+	// types, the types of the two operands.
+	Types []Type
+
+	// Suggestions are the names that the error message for an
+	// undefined name or selector suggests instead.
+	Suggestions []string
+
+	// Lines identifies, for each line of the error message, the
+	// message template of the compiler that renders it, if any, so
+	// that the compiler can translate the message.
+	Lines []MsgLine
+}
+
+// A MsgLine identifies a line of an error message as one that a
+// message template of the compiler renders: ID is the ID of the
+// template, and Args holds the text of the values of its parameters,
+// by name. The ID of a line that no template renders is empty.
+type MsgLine struct {
+	ID   string
+	Args map[string]string
 }
 
 // Error returns an error string formatted as follows:
@@ -141,6 +180,18 @@ type Config struct {
 	// TODO(gri) Consolidate error messages and remove this flag.
 	CompilerErrorMessages bool
 
+	// If ErrorQualifier is set, it qualifies the objects of packages
+	// other than the one being checked in error messages, in place of
+	// their package names or, if several imported packages share a
+	// name, their paths.
+	ErrorQualifier Qualifier
+
+	// If MethodTypeParams is set, methods of defined types may declare
+	// type parameters of their own, as in func (T) M[P any](). Such
+	// methods do not implement interface methods. Interface methods
+	// may not declare type parameters.
+	MethodTypeParams bool
+
 	// If go115UsesCgo is set, the type checker expects the
 	// _cgo_gotypes.go file generated by running cmd/cgo to be
 	// provided as a package source file. Qualified identifiers
@@ -153,6 +204,15 @@ type Config struct {
 	// If Trace is set, a debug trace is printed to stdout.
 	Trace bool
 
+	// If InferenceTrace != nil, it is called with a description of
+	// each step of the inference of the type arguments of a generic
+	// function: the type parameters and their constraints, each
+	// unification of a parameter type with an argument type or of a
+	// type parameter with its constraint's structural type, the type
+	// arguments inferred after each step, and the outcome. pos is the
+	// position of the call or instantiation.
+	InferenceTrace func(pos syntax.Pos, msg string)
+
 	// If Error != nil, it is called with each error found
 	// during type checking; err has dynamic type Error.
 	// Secondary errors (for instance, to enumerate all types
@@ -2033,3 +2033,45 @@ func TestInstanceIdentity(t *testing.T) {
 		t.Errorf("mismatching types: a.A: %s, b.B: %s", a.Type(), b.Type())
 	}
 }
+
+func TestInferenceTrace(t *testing.T) {
+	const src = `package p
+
+func f[P any, Q ~[]P](P, Q) {}
+
+func g[T any](x, y T) {}
+
+func _() {
+	f(1, []int{})
+	g(1, 2)
+}
+`
+	f, err := parseSrc("p.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	conf := Config{
+		InferenceTrace: func(pos syntax.Pos, msg string) {
+			got = append(got, fmt.Sprintf("%s: %s", pos, msg))
+		},
+	}
+	if _, err := conf.Check(f.PkgName.Value, []*syntax.File{f}, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"p.go:8:3: infer type arguments for [P interface{}, Q ~[]P]",
+		"p.go:8:3: unify parameter type Q with argument type []int of []int{}: P = ?, Q = []int",
+		"p.go:8:3: unify type parameter Q with structural type []P: P = int, Q = []int",
+		"p.go:8:3: after constraint type inference: P = int, Q = []int",
+		"p.go:8:3: inferred P = int, Q = []int",
+		"p.go:9:3: infer type arguments for [T interface{}]",
+		"p.go:9:3: after constraint type inference: T = ?",
+		"p.go:9:3: unify parameter type T with default type int of 1: T = int",
+		"p.go:9:3: unify parameter type T with default type int of 2: T = int",
+		"p.go:9:3: inferred T = int",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got trace\n\t%s\nwant\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+}
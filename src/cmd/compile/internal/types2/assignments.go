@@ -7,6 +7,7 @@
 package types2
 
 import (
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"fmt"
 )
@@ -26,7 +27,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 		// ok
 	default:
 		// we may get here because of other problems (issue #39634, crash 12)
-		check.errorf(x, "cannot assign %s to %s in %s", x, T, context)
+		check.errorf(x, 0, "cannot assign %s to %s in %s", x, T, context)
 		return
 	}
 
@@ -39,7 +40,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 		// complex, or string constant."
 		if x.isNil() {
 			if T == nil {
-				check.errorf(x, "use of untyped nil in %s", context)
+				check.errorf(x, errcode.UntypedNil, "use of untyped nil in %s", context)
 				x.mode = invalid
 				return
 			}
@@ -50,12 +51,13 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 		if code != 0 {
 			msg := check.sprintf("cannot use %s as %s value in %s", x, target, context)
 			switch code {
-			case _TruncatedFloat:
-				msg += " (truncated)"
-			case _NumericOverflow:
-				msg += " (overflows)"
+			case errcode.TruncatedFloat:
+				check.error(x, code, msg+withNote(" (truncated", check.rangeNote(x.val, target))+")")
+			case errcode.NumericOverflow:
+				check.error(x, code, msg+withNote(" (overflows", check.rangeNote(x.val, target))+")")
+			default:
+				check.errorfMsg(x, errcode.IncompatibleAssign, false, "CannotUseAsValueIn", "cannot use %s as %s value in %s", x, target, context)
 			}
-			check.error(x, msg)
 			x.mode = invalid
 			return
 		}
@@ -72,7 +74,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 
 	// A generic (non-instantiated) function value cannot be assigned to a variable.
 	if sig, _ := under(x.typ).(*Signature); sig != nil && sig.TypeParams().Len() > 0 {
-		check.errorf(x, "cannot use generic function %s without instantiation in %s", x, context)
+		check.errorf(x, errcode.NotInstantiated, "cannot use generic function %s without instantiation in %s", x, context)
 	}
 
 	// spec: "If a left-hand side is the blank identifier, any typed or
@@ -83,14 +85,19 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 	}
 
 	reason := ""
-	if ok, _ := x.assignableTo(check, T, &reason); !ok {
+	if ok, code := x.assignableTo(check, T, &reason); !ok {
+		details := &ErrorDetails{Operand: x.expr, Types: []Type{x.typ, T}}
 		if check.conf.CompilerErrorMessages {
-			check.errorf(x, "incompatible type: cannot use %s as %s value", x, T)
+			if reason != "" {
+				check.errorfDetails(x, code, details, "incompatible type: cannot use %s as %s value:\n\t%s", x, T, reason)
+			} else {
+				check.errorfDetails(x, code, details, "incompatible type: cannot use %s as %s value", x, T)
+			}
 		} else {
 			if reason != "" {
-				check.errorf(x, "cannot use %s as %s value in %s: %s", x, T, context, reason)
+				check.errorfDetails(x, code, details, "cannot use %s as %s value in %s: %s", x, T, context, reason)
 			} else {
-				check.errorf(x, "cannot use %s as %s value in %s", x, T, context)
+				check.errorfDetails(x, code, details, "cannot use %s as %s value in %s", x, T, context)
 			}
 		}
 		x.mode = invalid
@@ -107,7 +114,7 @@ func (check *Checker) initConst(lhs *Const, x *operand) {
 
 	// rhs must be a constant
 	if x.mode != constant_ {
-		check.errorf(x, "%s is not constant", x)
+		check.errorf(x, errcode.InvalidConstInit, "%s is not constant", x)
 		if lhs.typ == nil {
 			lhs.typ = Typ[Invalid]
 		}
@@ -145,7 +152,7 @@ func (check *Checker) initVar(lhs *Var, x *operand, context string) Type {
 		if isUntyped(typ) {
 			// convert untyped types to default types
 			if typ == Typ[UntypedNil] {
-				check.errorf(x, "use of untyped nil in %s", context)
+				check.errorf(x, errcode.UntypedNil, "use of untyped nil in %s", context)
 				lhs.typ = Typ[Invalid]
 				return nil
 			}
@@ -217,18 +224,18 @@ func (check *Checker) assignVar(lhs syntax.Expr, x *operand) Type {
 	case variable, mapindex:
 		// ok
 	case nilvalue:
-		check.error(&z, "cannot assign to nil") // default would print "untyped nil"
+		check.error(&z, errcode.UnassignableOperand, "cannot assign to nil") // default would print "untyped nil"
 		return nil
 	default:
 		if sel, ok := z.expr.(*syntax.SelectorExpr); ok {
 			var op operand
 			check.expr(&op, sel.X)
 			if op.mode == mapindex {
-				check.errorf(&z, "cannot assign to struct field %s in map", syntax.String(z.expr))
+				check.errorf(&z, errcode.UnassignableOperand, "cannot assign to struct field %s in map", syntax.String(z.expr))
 				return nil
 			}
 		}
-		check.errorf(&z, "cannot assign to %s", &z)
+		check.errorf(&z, errcode.UnassignableOperand, "cannot assign to %s", &z)
 		return nil
 	}
 
@@ -255,11 +262,11 @@ func (check *Checker) assignError(rhs []syntax.Expr, nvars, nvals int) {
 
 	if len(rhs) == 1 {
 		if call, _ := unparen(rhs0).(*syntax.CallExpr); call != nil {
-			check.errorf(rhs0, "assignment mismatch: %s but %s returns %s", vars, call.Fun, vals)
+			check.errorf(rhs0, errcode.WrongAssignCount, "assignment mismatch: %s but %s returns %s", vars, call.Fun, vals)
 			return
 		}
 	}
-	check.errorf(rhs0, "assignment mismatch: %s but %s", vars, vals)
+	check.errorf(rhs0, errcode.WrongAssignCount, "assignment mismatch: %s but %s", vars, vals)
 }
 
 // If returnPos is valid, initVars is called to type-check the assignment of
@@ -282,13 +289,13 @@ func (check *Checker) initVars(lhs []*Var, orig_rhs []syntax.Expr, returnPos syn
 			}
 		}
 		if returnPos.IsKnown() {
-			check.errorf(returnPos, "wrong number of return values (want %d, got %d)", len(lhs), len(rhs))
+			check.errorf(returnPos, errcode.WrongResultCount, "wrong number of return values (want %d, got %d)", len(lhs), len(rhs))
 			return
 		}
 		if check.conf.CompilerErrorMessages {
 			check.assignError(orig_rhs, len(lhs), len(rhs))
 		} else {
-			check.errorf(rhs[0], "cannot initialize %d variables with %d values", len(lhs), len(rhs))
+			check.errorf(rhs[0], errcode.WrongAssignCount, "cannot initialize %d variables with %d values", len(lhs), len(rhs))
 		}
 		return
 	}
@@ -336,7 +343,7 @@ func (check *Checker) assignVars(lhs, orig_rhs []syntax.Expr) {
 		if check.conf.CompilerErrorMessages {
 			check.assignError(orig_rhs, len(lhs), len(rhs))
 		} else {
-			check.errorf(rhs[0], "cannot assign %d values to %d variables", len(rhs), len(lhs))
+			check.errorf(rhs[0], errcode.WrongAssignCount, "cannot assign %d values to %d variables", len(rhs), len(lhs))
 		}
 		return
 	}
@@ -401,7 +408,7 @@ func (check *Checker) shortVarDecl(pos syntax.Pos, lhs, rhs []syntax.Expr) {
 		ident, _ := lhs.(*syntax.Name)
 		if ident == nil {
 			check.use(lhs)
-			check.errorf(lhs, "non-name %s on left side of :=", lhs)
+			check.errorf(lhs, 0, "non-name %s on left side of :=", lhs)
 			hasErr = true
 			continue
 		}
@@ -409,7 +416,7 @@ func (check *Checker) shortVarDecl(pos syntax.Pos, lhs, rhs []syntax.Expr) {
 		name := ident.Value
 		if name != "_" {
 			if seen[name] {
-				check.errorf(lhs, "%s repeated on left side of :=", lhs)
+				check.errorf(lhs, errcode.RepeatedDecl, "%s repeated on left side of :=", lhs)
 				hasErr = true
 				continue
 			}
@@ -426,7 +433,7 @@ func (check *Checker) shortVarDecl(pos syntax.Pos, lhs, rhs []syntax.Expr) {
 			if obj, _ := alt.(*Var); obj != nil {
 				lhsVars[i] = obj
 			} else {
-				check.errorf(lhs, "cannot assign to %s", lhs)
+				check.errorf(lhs, errcode.UnassignableOperand, "cannot assign to %s", lhs)
 				hasErr = true
 			}
 			continue
@@ -454,7 +461,7 @@ func (check *Checker) shortVarDecl(pos syntax.Pos, lhs, rhs []syntax.Expr) {
 	check.processDelayed(top)
 
 	if len(newVars) == 0 && !hasErr {
-		check.softErrorf(pos, "no new variables on left side of :=")
+		check.softErrorf(pos, errcode.NoNewVar, "no new variables on left side of :=")
 		return
 	}
 
@@ -7,6 +7,7 @@
 package types2
 
 import (
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"strings"
 	"unicode"
@@ -32,7 +33,7 @@ func (check *Checker) funcInst(x *operand, inst *syntax.IndexExpr) {
 	sig := x.typ.(*Signature)
 	got, want := len(targs), sig.TypeParams().Len()
 	if !useConstraintTypeInference && got != want || got > want {
-		check.errorf(xlist[got-1], "got %d type arguments but want %d", got, want)
+		check.errorf(xlist[got-1], errcode.WrongTypeArgCount, "got %d type arguments but want %d", got, want)
 		x.mode = invalid
 		x.expr = inst
 		return
@@ -87,7 +88,7 @@ func (check *Checker) instantiateSignature(pos syntax.Pos, typ *Signature, targs
 		if i < len(posList) {
 			pos = posList[i]
 		}
-		check.softErrorf(pos, err.Error())
+		check.softErrorf(pos, errcode.InvalidIfaceAssign, err.Error())
 	} else {
 		check.mono.recordInstance(check.pkg, pos, tparams, targs, posList)
 	}
@@ -128,25 +129,25 @@ func (check *Checker) callExpr(x *operand, call *syntax.CallExpr) exprKind {
 		x.mode = invalid
 		switch n := len(call.ArgList); n {
 		case 0:
-			check.errorf(call, "missing argument in conversion to %s", T)
+			check.errorf(call, errcode.WrongArgCount, "missing argument in conversion to %s", T)
 		case 1:
 			check.expr(x, call.ArgList[0])
 			if x.mode != invalid {
 				if t, _ := under(T).(*Interface); t != nil {
 					if !t.IsMethodSet() {
-						check.errorf(call, "cannot use interface %s in conversion (contains specific type constraints or is comparable)", T)
+						check.errorf(call, 0, "cannot use interface %s in conversion (contains specific type constraints or is comparable)", T)
 						break
 					}
 				}
 				if call.HasDots {
-					check.errorf(call.ArgList[0], "invalid use of ... in type conversion to %s", T)
+					check.errorf(call.ArgList[0], errcode.MisplacedDotDotDot, "invalid use of ... in type conversion to %s", T)
 					break
 				}
 				check.conversion(x, T)
 			}
 		default:
 			check.use(call.ArgList...)
-			check.errorf(call.ArgList[n-1], "too many arguments in conversion to %s", T)
+			check.errorf(call.ArgList[n-1], errcode.WrongArgCount, "too many arguments in conversion to %s", T)
 		}
 		x.expr = call
 		return conversion
@@ -172,7 +173,7 @@ func (check *Checker) callExpr(x *operand, call *syntax.CallExpr) exprKind {
 	// a type parameter may be "called" if all types have the same signature
 	sig, _ := structure(x.typ).(*Signature)
 	if sig == nil {
-		check.errorf(x, invalidOp+"cannot call non-function %s", x)
+		check.errorf(x, errcode.InvalidArgument, invalidOp+"cannot call non-function %s", x)
 		x.mode = invalid
 		x.expr = call
 		return statement
@@ -194,7 +195,7 @@ func (check *Checker) callExpr(x *operand, call *syntax.CallExpr) exprKind {
 		// check number of type arguments (got) vs number of type parameters (want)
 		got, want := len(targs), sig.TypeParams().Len()
 		if got > want {
-			check.errorf(xlist[want], "got %d type arguments but want %d", got, want)
+			check.errorf(xlist[want], errcode.WrongTypeArgCount, "got %d type arguments but want %d", got, want)
 			check.use(call.ArgList...)
 			x.mode = invalid
 			x.expr = call
@@ -286,7 +287,7 @@ func (check *Checker) arguments(call *syntax.CallExpr, sig *Signature, targs []T
 	for _, a := range args {
 		switch a.mode {
 		case typexpr:
-			check.errorf(a, "%s used as value", a)
+			check.errorf(a, errcode.NotAnExpr, "%s used as value", a)
 			return
 		case invalid:
 			return
@@ -315,7 +316,7 @@ func (check *Checker) arguments(call *syntax.CallExpr, sig *Signature, targs []T
 			if len(call.ArgList) == 1 && nargs > 1 {
 				// f()... is not permitted if f() is multi-valued
 				//check.errorf(call.Ellipsis, "cannot use ... with %d-valued %s", nargs, call.ArgList[0])
-				check.errorf(call, "cannot use ... with %d-valued %s", nargs, call.ArgList[0])
+				check.errorf(call, errcode.MisplacedDotDotDot, "cannot use ... with %d-valued %s", nargs, call.ArgList[0])
 				return
 			}
 		} else {
@@ -343,7 +344,7 @@ func (check *Checker) arguments(call *syntax.CallExpr, sig *Signature, targs []T
 		if ddd {
 			// standard_func(a, b, c...)
 			//check.errorf(call.Ellipsis, "cannot use ... in call to non-variadic %s", call.Fun)
-			check.errorf(call, "cannot use ... in call to non-variadic %s", call.Fun)
+			check.errorf(call, errcode.MisplacedDotDotDot, "cannot use ... in call to non-variadic %s", call.Fun)
 			return
 		}
 		// standard_func(a, b, c)
@@ -352,10 +353,10 @@ func (check *Checker) arguments(call *syntax.CallExpr, sig *Signature, targs []T
 	// check argument count
 	switch {
 	case nargs < npars:
-		check.errorf(call, "not enough arguments in call to %s", call.Fun)
+		check.errorfMsg(call, errcode.WrongArgCount, false, "NotEnoughArguments", "not enough arguments in call to %s", call.Fun)
 		return
 	case nargs > npars:
-		check.errorf(args[npars], "too many arguments in call to %s", call.Fun) // report at first extra argument
+		check.errorfMsg(args[npars], errcode.WrongArgCount, false, "TooManyArguments", "too many arguments in call to %s", call.Fun) // report at first extra argument
 		return
 	}
 
@@ -453,7 +454,7 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 					}
 				}
 				if exp == nil {
-					check.errorf(e.Sel, "%s not declared by package C", sel)
+					check.errorf(e.Sel, errcode.UndeclaredImportedName, "%s not declared by package C", sel)
 					goto Error
 				}
 				check.objDecl(exp, nil)
@@ -462,15 +463,20 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 				if exp == nil {
 					if !pkg.fake {
 						if check.conf.CompilerErrorMessages {
-							check.errorf(e.Sel, "undefined: %s.%s", pkg.name, sel)
+							if names := check.packageSuggestion(pkg, sel); names != nil {
+								details := &ErrorDetails{Suggestions: names}
+								check.errorfDetails(e.Sel, errcode.UndeclaredImportedName, details, "undefined: %s.%s (%s)", pkg.name, sel, suggestion(names, pkg.name+"."))
+							} else {
+								check.errorf(e.Sel, errcode.UndeclaredImportedName, "undefined: %s.%s", pkg.name, sel)
+							}
 						} else {
-							check.errorf(e.Sel, "%s not declared by package %s", sel, pkg.name)
+							check.errorf(e.Sel, errcode.UndeclaredImportedName, "%s not declared by package %s", sel, pkg.name)
 						}
 					}
 					goto Error
 				}
 				if !exp.Exported() {
-					check.errorf(e.Sel, "%s not exported by package %s", sel, pkg.name)
+					check.errorf(e.Sel, errcode.UndeclaredImportedName, "%s not exported by package %s", sel, pkg.name)
 					// ok to continue
 				}
 			}
@@ -523,11 +529,13 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 		switch {
 		case index != nil:
 			// TODO(gri) should provide actual type where the conflict happens
-			check.errorf(e.Sel, "ambiguous selector %s.%s", x.expr, sel)
+			check.errorf(e.Sel, errcode.AmbiguousSelector, "ambiguous selector %s.%s", x.expr, sel)
 		case indirect:
-			check.errorf(e.Sel, "cannot call pointer method %s on %s", sel, x.typ)
+			check.errorf(e.Sel, 0, "cannot call pointer method %s on %s", sel, x.typ)
 		default:
 			var why string
+			var plain bool // why is just that x.typ has no sel
+			var details *ErrorDetails
 			if tpar := asTypeParam(x.typ); tpar != nil {
 				// Type parameter bounds don't specify fields, so don't mention "field".
 				if tname := tpar.iface().obj; tname != nil {
@@ -537,6 +545,7 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 				}
 			} else {
 				why = check.sprintf("type %s has no field or method %s", x.typ, sel)
+				plain = true
 			}
 
 			// Check if capitalization of sel matters and provide better error message in that case.
@@ -549,10 +558,23 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 				}
 				if obj, _, _ = LookupFieldOrMethod(x.typ, x.mode == variable, check.pkg, changeCase); obj != nil {
 					why += ", but does have " + changeCase
+					plain = false
+				} else if check.conf.CompilerErrorMessages {
+					if names := check.selectorSuggestion(x.typ, x.mode == variable, false, sel); names != nil {
+						why += "; " + suggestion(names, "")
+						details = &ErrorDetails{Suggestions: names}
+						plain = false
+					}
 				}
 			}
 
-			check.errorf(e.Sel, "%s.%s undefined (%s)", x.expr, sel, why)
+			err := error_{code: errcode.MissingFieldOrMethod, details: details}
+			if plain {
+				err.errorfMsg(e.Sel, "NoFieldOrMethod", "%s undefined (type %s has no field or method %s)", e, x.typ, sel)
+			} else {
+				err.errorf(e.Sel, "%s.%s undefined (%s)", x.expr, sel, why)
+			}
+			check.report(&err)
 
 		}
 		goto Error
@@ -568,7 +590,15 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 		m, _ := obj.(*Func)
 		if m == nil {
 			// TODO(gri) should check if capitalization of sel matters and provide better error message in that case
-			check.errorf(e.Sel, "%s.%s undefined (type %s has no method %s)", x.expr, sel, x.typ, sel)
+			why := check.sprintf("type %s has no method %s", x.typ, sel)
+			var details *ErrorDetails
+			if check.conf.CompilerErrorMessages {
+				if names := check.selectorSuggestion(x.typ, false, true, sel); names != nil {
+					why += "; " + suggestion(names, "")
+					details = &ErrorDetails{Suggestions: names}
+				}
+			}
+			check.errorfDetails(e.Sel, errcode.MissingFieldOrMethod, details, "%s.%s undefined (%s)", x.expr, sel, why)
 			goto Error
 		}
 
@@ -576,7 +606,7 @@ func (check *Checker) selector(x *operand, e *syntax.SelectorExpr) {
 
 		sig := m.typ.(*Signature)
 		if sig.recv == nil {
-			check.error(e, "illegal cycle in method declaration")
+			check.error(e, errcode.InvalidDeclCycle, "illegal cycle in method declaration")
 			goto Error
 		}
 
@@ -0,0 +1,176 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements the suppression of errors that cascade from an
+// error in the declaration of a type. In compiler mode, an error in the
+// declaration of a type marks the type as broken. If the error leaves
+// the type without an underlying type, as in "type T Undefined", errors
+// outside type declarations that involve the type are left out: they
+// are about a type that does not exist. Instead, a note after the first
+// error in the declaration says how many errors were left out. A type
+// whose declaration is in error but that has an underlying type, like a
+// struct with a field of an undefined type, is used as declared, and
+// errors about its uses are reported.
+
+package types2
+
+import (
+	"cmd/compile/internal/errcode"
+	"cmd/compile/internal/syntax"
+	"fmt"
+)
+
+// A typeDeclSpan is the extent of the declaration of a type.
+type typeDeclSpan struct {
+	obj        *TypeName
+	start, end syntax.Pos
+}
+
+// A brokenType records the errors of a type whose declaration is in
+// error.
+type brokenType struct {
+	obj        *TypeName
+	errPos     syntax.Pos // position of the first error in the declaration
+	suppressed int        // number of errors involving obj left out
+}
+
+// recordTypeDecl records the extent of the declaration of obj, so that
+// errors in it mark obj as broken.
+func (check *Checker) recordTypeDecl(obj *TypeName, tdecl *syntax.TypeDecl) {
+	if check.conf.CompilerErrorMessages {
+		check.typeDecls = append(check.typeDecls, typeDeclSpan{obj, syntax.StartPos(tdecl), syntax.EndPos(tdecl)})
+	}
+}
+
+// cascades reports whether an error at pos with the message arguments
+// args cascades from an error in the declaration of a type, and should
+// be left out. Errors in type declarations do not cascade: they are
+// errors in the declaration of a type, which they mark as broken,
+// unless they are soft.
+func (check *Checker) cascades(at poser, soft bool, args []interface{}) bool {
+	if !check.conf.CompilerErrorMessages {
+		return false
+	}
+	pos := posFor(at)
+	decl := check.typeDeclAt(pos)
+	if decl == nil {
+		for _, arg := range args {
+			if b := check.brokenIn(arg); b != nil {
+				b.suppressed++
+				return true
+			}
+		}
+		return false
+	}
+	if !soft && check.broken[decl] == nil {
+		if check.broken == nil {
+			check.broken = make(map[*TypeName]*brokenType)
+		}
+		b := &brokenType{obj: decl, errPos: pos}
+		check.broken[decl] = b
+		check.brokenList = append(check.brokenList, b)
+	}
+	return false
+}
+
+// typeDeclAt returns the type whose declaration is the innermost one
+// containing pos, or nil.
+func (check *Checker) typeDeclAt(pos syntax.Pos) *TypeName {
+	var obj *TypeName
+	var start syntax.Pos
+	for _, d := range check.typeDecls {
+		if d.start.Cmp(pos) <= 0 && pos.Cmp(d.end) <= 0 && (obj == nil || start.Cmp(d.start) < 0) {
+			obj, start = d.obj, d.start
+		}
+	}
+	return obj
+}
+
+// brokenIn returns a broken type without an underlying type that the
+// error message argument arg involves, or nil.
+func (check *Checker) brokenIn(arg interface{}) *brokenType {
+	if check.broken == nil {
+		return nil
+	}
+	switch a := arg.(type) {
+	case *operand:
+		return check.brokenType(a.typ)
+	case Object:
+		return check.brokenType(a.Type())
+	case Type:
+		return check.brokenType(a)
+	case syntax.Expr:
+		// Messages often show an operand by its expression.
+		if tv, ok := check.Types[a]; ok {
+			return check.brokenType(tv.Type)
+		}
+	}
+	return nil
+}
+
+// brokenType returns a broken type without an underlying type that T
+// is or is composed of, or nil. It does not look at the underlying
+// types of defined types.
+func (check *Checker) brokenType(T Type) *brokenType {
+	switch t := T.(type) {
+	case *Named:
+		if b := check.broken[t.orig.obj]; b != nil && t.orig.underlying == Typ[Invalid] {
+			return b
+		}
+		for _, targ := range t.TypeArgs().list() {
+			if b := check.brokenType(targ); b != nil {
+				return b
+			}
+		}
+	case *Pointer:
+		return check.brokenType(t.base)
+	case *Slice:
+		return check.brokenType(t.elem)
+	case *Array:
+		return check.brokenType(t.elem)
+	case *Map:
+		if b := check.brokenType(t.key); b != nil {
+			return b
+		}
+		return check.brokenType(t.elem)
+	case *Chan:
+		return check.brokenType(t.elem)
+	case *Struct:
+		for _, f := range t.fields {
+			if b := check.brokenType(f.typ); b != nil {
+				return b
+			}
+		}
+	case *Tuple:
+		if t != nil {
+			for _, v := range t.vars {
+				if b := check.brokenType(v.typ); b != nil {
+					return b
+				}
+			}
+		}
+	case *Signature:
+		if b := check.brokenType(t.params); b != nil {
+			return b
+		}
+		return check.brokenType(t.results)
+	}
+	return nil
+}
+
+// reportCascades reports, after the first error in the declaration of
+// each broken type, how many errors involving the type were left out.
+// It reports the count as a soft error with the code CascadingErrors,
+// which the compiler prints as a note rather than an error.
+func (check *Checker) reportCascades() {
+	for _, b := range check.brokenList {
+		if b.suppressed > 0 {
+			errors := "errors"
+			if b.suppressed == 1 {
+				errors = "error"
+			}
+			check.err(b.errPos, errcode.CascadingErrors, nil, fmt.Sprintf("%d more %s involving %s not shown", b.suppressed, errors, b.obj.name), true)
+		}
+	}
+}
@@ -7,6 +7,7 @@
 package types2
 
 import (
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"errors"
 	"fmt"
@@ -136,6 +137,11 @@ type Checker struct {
 	objPath  []Object                 // path of object dependencies during type inference (for cycle reporting)
 	defTypes []*Named                 // defined types created during type checking, for final validation.
 
+	// cascading errors (see cascade.go; compiler mode only)
+	typeDecls  []typeDeclSpan            // extents of the type declarations checked so far
+	broken     map[*TypeName]*brokenType // types whose declaration is in error
+	brokenList []*brokenType             // broken, in the order the types broke
+
 	// context within which the current object is type-checked
 	// (valid only for the duration of type-checking a specific object)
 	context
@@ -246,7 +252,7 @@ func (check *Checker) initFiles(files []*syntax.File) {
 			if name != "_" {
 				pkg.name = name
 			} else {
-				check.error(file.PkgName, "invalid package name _")
+				check.error(file.PkgName, errcode.BlankPkgName, "invalid package name _")
 			}
 			fallthrough
 
@@ -254,7 +260,7 @@ func (check *Checker) initFiles(files []*syntax.File) {
 			check.files = append(check.files, file)
 
 		default:
-			check.errorf(file, "package %s; expected %s", name, pkg.name)
+			check.errorf(file, errcode.MismatchedPkgName, "package %s; expected %s", name, pkg.name)
 			// ignore this file
 		}
 	}
@@ -319,6 +325,8 @@ func (check *Checker) checkFiles(files []*syntax.File) (err error) {
 	print("== recordUntyped ==")
 	check.recordUntyped()
 
+	check.reportCascades()
+
 	if check.firstErr == nil {
 		// TODO(mdempsky): Ensure monomorph is safe when errors exist.
 		check.monomorph()
@@ -7,6 +7,7 @@
 package types2
 
 import (
+	"cmd/compile/internal/errcode"
 	"fmt"
 	"go/constant"
 	"unicode"
@@ -68,8 +69,22 @@ func (check *Checker) conversion(x *operand, T Type) {
 	}
 
 	if !ok {
-		var err error_
-		err.errorf(x, "cannot convert %s to %s", x, T)
+		err := error_{code: errcode.InvalidConversion}
+		msg := "cannot convert %s to %s"
+		if constArg && isConstType(T) && isNumeric(x.typ) && isNumeric(T) {
+			// The constant is out of range or, converted to an
+			// integer type, not an integer.
+			what := " (overflows"
+			err.code = errcode.NumericOverflow
+			if isInteger(T) && constant.ToInt(x.val).Kind() != constant.Int {
+				what = " (truncated"
+				err.code = errcode.TruncatedFloat
+			}
+			if note := check.rangeNote(x.val, T); note != "" {
+				msg += withNote(what, note) + ")"
+			}
+		}
+		err.errorf(x, msg, x, T)
 		if cause != "" {
 			err.errorf(nopos, cause)
 		}
@@ -5,6 +5,7 @@
 package types2
 
 import (
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"fmt"
 	"go/constant"
@@ -15,7 +16,7 @@ func (err *error_) recordAltDecl(obj Object) {
 		// We use "other" rather than "previous" here because
 		// the first declaration seen may not be textually
 		// earlier in the source.
-		err.errorf(pos, "other declaration of %s", obj.Name())
+		err.errorfMsg(pos, "OtherDeclaration", "other declaration of %s", obj.Name())
 	}
 }
 
@@ -26,8 +27,8 @@ func (check *Checker) declare(scope *Scope, id *syntax.Name, obj Object, pos syn
 	// binding."
 	if obj.Name() != "_" {
 		if alt := scope.Insert(obj); alt != nil {
-			var err error_
-			err.errorf(obj, "%s redeclared in this block", obj.Name())
+			err := error_{code: errcode.DuplicateDecl}
+			err.errorfMsg(obj, "Redeclared", "%s redeclared in this block", obj.Name())
 			err.recordAltDecl(alt)
 			check.report(&err)
 			return
@@ -402,7 +403,7 @@ func (check *Checker) cycleError(cycle []Object) {
 	//           cycle? That would be more consistent with other error messages.
 	i := firstInSrc(cycle)
 	obj := cycle[i]
-	var err error_
+	err := error_{code: errcode.InvalidDeclCycle}
 	if check.conf.CompilerErrorMessages {
 		err.errorf(obj, "invalid recursive type %s", obj.Name())
 	} else {
@@ -453,7 +454,7 @@ func (check *Checker) constDecl(obj *Const, typ, init syntax.Expr, inherited boo
 			// don't report an error if the type is an invalid C (defined) type
 			// (issue #22090)
 			if under(t) != Typ[Invalid] {
-				check.errorf(typ, "invalid constant type %s", t)
+				check.errorf(typ, errcode.InvalidConstType, "invalid constant type %s", t)
 			}
 			obj.typ = Typ[Invalid]
 			return
@@ -564,6 +565,7 @@ func (check *Checker) isImportedConstraint(typ Type) bool {
 
 func (check *Checker) typeDecl(obj *TypeName, tdecl *syntax.TypeDecl, def *Named) {
 	assert(obj.typ == nil)
+	check.recordTypeDecl(obj, tdecl)
 
 	var rhs Type
 	check.later(func() {
@@ -578,7 +580,7 @@ func (check *Checker) typeDecl(obj *TypeName, tdecl *syntax.TypeDecl, def *Named
 	if alias && tdecl.TParamList != nil {
 		// The parser will ensure this but we may still get an invalid AST.
 		// Complain and continue as regular type definition.
-		check.error(tdecl, "generic type cannot be alias")
+		check.error(tdecl, 0, "generic type cannot be alias")
 		alias = false
 	}
 
@@ -620,7 +622,7 @@ func (check *Checker) typeDecl(obj *TypeName, tdecl *syntax.TypeDecl, def *Named
 	// type (underlying not fully resolved yet) it cannot become a type parameter due
 	// to this very restriction.
 	if tpar, _ := named.underlying.(*TypeParam); tpar != nil {
-		check.error(tdecl.Type, "cannot use a type parameter as RHS in type declaration")
+		check.error(tdecl.Type, 0, "cannot use a type parameter as RHS in type declaration")
 		named.underlying = Typ[Invalid]
 	}
 }
@@ -672,7 +674,7 @@ func (check *Checker) collectTypeParams(dst **TypeParamList, list []*syntax.Fiel
 	check.later(func() {
 		for i, bound := range bounds {
 			if _, ok := under(bound).(*TypeParam); ok {
-				check.error(posers[i], "cannot use a type parameter as constraint")
+				check.error(posers[i], 0, "cannot use a type parameter as constraint")
 			}
 		}
 		for _, tpar := range tparams {
@@ -755,10 +757,12 @@ func (check *Checker) collectMethods(obj *TypeName) {
 			var err error_
 			switch alt.(type) {
 			case *Var:
+				err.code = errcode.DuplicateDecl
 				err.errorf(m.pos, "field and method with the same name %s", m.name)
 			case *Func:
+				err.code = errcode.DuplicateMethod
 				if check.conf.CompilerErrorMessages {
-					err.errorf(m.pos, "%s.%s redeclared in this block", obj.Name(), m.name)
+					err.errorfMsg(m.pos, "Redeclared", "%s redeclared in this block", obj.Name()+"."+m.name)
 				} else {
 					err.errorf(m.pos, "method %s already declared for %s", m.name, obj)
 				}
@@ -799,7 +803,7 @@ func (check *Checker) funcDecl(obj *Func, decl *declInfo) {
 	obj.color_ = saved
 
 	if len(fdecl.TParamList) > 0 && fdecl.Body == nil {
-		check.softErrorf(fdecl, "parameterized function is missing function body")
+		check.softErrorf(fdecl, errcode.MissingFuncBody, "parameterized function is missing function body")
 	}
 
 	// function body must be type-checked after global declarations
@@ -944,7 +948,7 @@ func (check *Checker) declStmt(list []syntax.Decl) {
 			check.pop().setColor(black)
 
 		default:
-			check.errorf(s, invalidAST+"unknown syntax.Decl node %T", s)
+			check.errorf(s, 0, invalidAST+"unknown syntax.Decl node %T", s)
 		}
 	}
 }
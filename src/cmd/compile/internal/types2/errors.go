@@ -8,6 +8,7 @@ package types2
 
 import (
 	"bytes"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"fmt"
 	"strconv"
@@ -31,8 +32,10 @@ func unreachable() {
 // An error_ represents a type-checking error.
 // To report an error_, call Checker.report.
 type error_ struct {
-	desc []errorDesc
-	soft bool // TODO(gri) eventually determine this from an error code
+	code    errcode.Code
+	details *ErrorDetails
+	desc    []errorDesc
+	soft    bool // TODO(gri) eventually determine this from an error code
 }
 
 // An errorDesc describes part of a type-checking error.
@@ -40,6 +43,25 @@ type errorDesc struct {
 	pos    syntax.Pos
 	format string
 	args   []interface{}
+	msgID  string // ID of the compiler's message template of the part, if any
+}
+
+// msgParams maps the IDs of the compiler's message templates that
+// render messages of the type checker (see the messages.go file of
+// package cmd/compile/internal/base) to the names of their parameters,
+// in the order in which the formats of the messages use them.
+var msgParams = map[string][]string{
+	"Undefined":          {"name"},
+	"DeclaredNotUsed":    {"name"},
+	"ImportedNotUsed":    {"path"},
+	"ImportedNotUsedAs":  {"path", "name"},
+	"MissingReturn":      nil,
+	"Redeclared":         {"name"},
+	"OtherDeclaration":   {"name"},
+	"CannotUseAsValueIn": {"x", "type", "context"},
+	"NoFieldOrMethod":    {"x", "type", "sel"},
+	"NotEnoughArguments": {"call"},
+	"TooManyArguments":   {"call"},
 }
 
 func (err *error_) empty() bool {
@@ -82,7 +104,41 @@ func (err *error_) String() string {
 // errorf adds formatted error information to err.
 // It may be called multiple times to provide additional information.
 func (err *error_) errorf(at poser, format string, args ...interface{}) {
-	err.desc = append(err.desc, errorDesc{posFor(at), format, args})
+	err.desc = append(err.desc, errorDesc{posFor(at), format, args, ""})
+}
+
+// errorfMsg is like errorf, but the information is the message that
+// the compiler's message template id renders with args (see msgParams).
+func (err *error_) errorfMsg(at poser, id string, format string, args ...interface{}) {
+	assert(len(args) == len(msgParams[id]))
+	err.desc = append(err.desc, errorDesc{posFor(at), format, args, id})
+}
+
+// lines returns the templates of the lines of err's message, as
+// ErrorDetails.Lines holds them, or nil if no template renders any.
+func (err *error_) lines(qf Qualifier) []MsgLine {
+	var lines []MsgLine
+	for i := range err.desc {
+		p := &err.desc[i]
+		line := MsgLine{}
+		if p.msgID != "" {
+			line = MsgLine{ID: p.msgID, Args: make(map[string]string)}
+			for j, name := range msgParams[p.msgID] {
+				line.Args[name] = sprintf(qf, false, "%s", p.args[j])
+			}
+		}
+		lines = append(lines, line)
+		// A part may span lines.
+		for n := strings.Count(p.format, "\n\t"); n > 0; n-- {
+			lines = append(lines, MsgLine{})
+		}
+	}
+	for _, l := range lines {
+		if l.ID != "" {
+			return lines
+		}
+	}
+	return nil
 }
 
 func sprintf(qf Qualifier, debug bool, format string, args ...interface{}) string {
@@ -111,6 +167,9 @@ func sprintf(qf Qualifier, debug bool, format string, args ...interface{}) strin
 func (check *Checker) qualifier(pkg *Package) string {
 	// Qualify the package unless it's the package being type-checked.
 	if pkg != check.pkg {
+		if check.conf.ErrorQualifier != nil {
+			return check.conf.ErrorQualifier(pkg)
+		}
 		if check.pkgPathMap == nil {
 			check.pkgPathMap = make(map[string]map[string]bool)
 			check.seenPkgMap = make(map[*Package]bool)
@@ -153,7 +212,21 @@ func (check *Checker) report(err *error_) {
 	if err.empty() {
 		panic("no error to report")
 	}
-	check.err(err.pos(), err.msg(check.qualifier), err.soft)
+	for _, d := range err.desc {
+		if check.cascades(err.pos(), err.soft, d.args) {
+			return
+		}
+	}
+	details := err.details
+	if lines := err.lines(check.qualifier); lines != nil {
+		d := new(ErrorDetails)
+		if details != nil {
+			*d = *details
+		}
+		d.Lines = lines
+		details = d
+	}
+	check.err(err.pos(), err.code, details, err.msg(check.qualifier), err.soft)
 }
 
 func (check *Checker) trace(pos syntax.Pos, format string, args ...interface{}) {
@@ -169,7 +242,7 @@ func (check *Checker) dump(format string, args ...interface{}) {
 	fmt.Println(sprintf(check.qualifier, true, format, args...))
 }
 
-func (check *Checker) err(at poser, msg string, soft bool) {
+func (check *Checker) err(at poser, code errcode.Code, details *ErrorDetails, msg string, soft bool) {
 	// Cheap trick: Don't report errors with messages containing
 	// "invalid operand" or "invalid type" as those tend to be
 	// follow-on errors which don't add useful information. Only
@@ -192,7 +265,7 @@ func (check *Checker) err(at poser, msg string, soft bool) {
 		pos = check.errpos
 	}
 
-	err := Error{pos, stripAnnotations(msg), msg, soft}
+	err := Error{pos, stripAnnotations(msg), msg, soft, code, details}
 	if check.firstErr == nil {
 		check.firstErr = err
 	}
@@ -218,16 +291,38 @@ type poser interface {
 	Pos() syntax.Pos
 }
 
-func (check *Checker) error(at poser, msg string) {
-	check.err(at, msg, false)
+func (check *Checker) error(at poser, code errcode.Code, msg string) {
+	check.err(at, code, nil, msg, false)
 }
 
-func (check *Checker) errorf(at poser, format string, args ...interface{}) {
-	check.err(at, check.sprintf(format, args...), false)
+func (check *Checker) errorf(at poser, code errcode.Code, format string, args ...interface{}) {
+	if check.cascades(at, false, args) {
+		return
+	}
+	check.err(at, code, nil, check.sprintf(format, args...), false)
 }
 
-func (check *Checker) softErrorf(at poser, format string, args ...interface{}) {
-	check.err(at, check.sprintf(format, args...), true)
+// errorfDetails is like errorf, but the error also carries details.
+func (check *Checker) errorfDetails(at poser, code errcode.Code, details *ErrorDetails, format string, args ...interface{}) {
+	err := error_{code: code, details: details}
+	err.errorf(at, format, args...)
+	check.report(&err)
+}
+
+// errorfMsg is like errorf, or softErrorf if soft is set, but the
+// message is the one that the compiler's message template id renders
+// with args (see msgParams).
+func (check *Checker) errorfMsg(at poser, code errcode.Code, soft bool, id string, format string, args ...interface{}) {
+	err := error_{code: code, soft: soft}
+	err.errorfMsg(at, id, format, args...)
+	check.report(&err)
+}
+
+func (check *Checker) softErrorf(at poser, code errcode.Code, format string, args ...interface{}) {
+	if check.cascades(at, true, args) {
+		return
+	}
+	check.err(at, code, nil, check.sprintf(format, args...), true)
 }
 
 func (check *Checker) versionErrorf(at poser, goVersion string, format string, args ...interface{}) {
@@ -237,7 +332,7 @@ func (check *Checker) versionErrorf(at poser, goVersion string, format string, a
 	} else {
 		msg = fmt.Sprintf("%s requires %s or later", msg, goVersion)
 	}
-	check.err(at, msg, true)
+	check.err(at, errcode.UnsupportedVersion, nil, msg, true)
 }
 
 // posFor reports the left (= start) position of at.
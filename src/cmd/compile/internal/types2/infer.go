@@ -8,6 +8,7 @@ package types2
 
 import (
 	"bytes"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"fmt"
 )
@@ -53,6 +54,18 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 	}
 	// len(targs) < n
 
+	if check.conf.InferenceTrace != nil {
+		check.inferTracef(pos, "infer type arguments for %s", tparamsString(check.qualifier, tparams))
+		if len(targs) > 0 {
+			check.inferTracef(pos, "given %s", check.inferredString(tparams, targs))
+		}
+		defer func() {
+			if result != nil {
+				check.inferTracef(pos, "inferred %s", check.inferredString(tparams, result))
+			}
+		}()
+	}
+
 	// --- 1 ---
 	// Explicitly provided type arguments take precedence over any inferred types;
 	// and types inferred via constraint type inference take precedence over types
@@ -60,7 +73,7 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 	// If we have type arguments, see how far we get with constraint type inference.
 	if len(targs) > 0 && useConstraintTypeInference {
 		var index int
-		targs, index = check.inferB(tparams, targs)
+		targs, index = check.inferB(pos, tparams, targs)
 		if targs == nil || index < 0 {
 			return targs
 		}
@@ -119,16 +132,16 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 				}
 			}
 			if allFailed {
-				check.errorf(arg, "%s %s of %s does not match %s (cannot infer %s)", kind, targ, arg.expr, tpar, typeParamsString(tparams))
+				check.errorf(arg, errcode.CannotInferTypeArgs, "%s %s of %s does not match %s (cannot infer %s)", kind, targ, arg.expr, tpar, typeParamsString(tparams))
 				return
 			}
 		}
 		smap := makeSubstMap(tparams, targs)
 		inferred := check.subst(arg.Pos(), tpar, smap, nil)
 		if inferred != tpar {
-			check.errorf(arg, "%s %s of %s does not match inferred type %s for %s", kind, targ, arg.expr, inferred, tpar)
+			check.errorf(arg, errcode.CannotInferTypeArgs, "%s %s of %s does not match inferred type %s for %s", kind, targ, arg.expr, inferred, tpar)
 		} else {
-			check.errorf(arg, "%s %s of %s does not match %s", kind, targ, arg.expr, tpar)
+			check.errorf(arg, errcode.CannotInferTypeArgs, "%s %s of %s does not match %s", kind, targ, arg.expr, tpar)
 		}
 	}
 
@@ -150,7 +163,9 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 				// If we permit bidirectional unification, and targ is
 				// a generic function, we need to initialize u.y with
 				// the respective type parameters of targ.
-				if !u.unify(par.typ, targ) {
+				ok := u.unify(par.typ, targ)
+				check.traceUnify(pos, "parameter type", par.typ, "argument type", targ, arg, ok, tparams, u)
+				if !ok {
 					errorf("type", par.typ, targ, arg)
 					return nil
 				}
@@ -171,7 +186,7 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 	// Note that even if we don't have any type arguments, constraint type inference
 	// may produce results for constraints that explicitly specify a type.
 	if useConstraintTypeInference {
-		targs, index = check.inferB(tparams, targs)
+		targs, index = check.inferB(pos, tparams, targs)
 		if targs == nil || index < 0 {
 			return targs
 		}
@@ -194,9 +209,13 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 			// The default type for an untyped nil is untyped nil. We must not
 			// infer an untyped nil type as type parameter type. Ignore untyped
 			// nil by making sure all default argument types are typed.
-			if isTyped(targ) && !u.unify(par.typ, targ) {
-				errorf("default type", par.typ, targ, arg)
-				return nil
+			if isTyped(targ) {
+				ok := u.unify(par.typ, targ)
+				check.traceUnify(pos, "parameter type", par.typ, "default type", targ, arg, ok, tparams, u)
+				if !ok {
+					errorf("default type", par.typ, targ, arg)
+					return nil
+				}
 			}
 		}
 	}
@@ -209,7 +228,7 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 
 	// Again, follow up with constraint type inference.
 	if useConstraintTypeInference {
-		targs, index = check.inferB(tparams, targs)
+		targs, index = check.inferB(pos, tparams, targs)
 		if targs == nil || index < 0 {
 			return targs
 		}
@@ -218,10 +237,76 @@ func (check *Checker) infer(pos syntax.Pos, tparams []*TypeParam, targs []Type,
 	// At least one type argument couldn't be inferred.
 	assert(targs != nil && index >= 0 && targs[index] == nil)
 	tpar := tparams[index]
-	check.errorf(pos, "cannot infer %s (%s)", tpar.obj.name, tpar.obj.pos)
+	check.inferTracef(pos, "cannot infer %s from %s", tpar.obj.name, check.inferredString(tparams, targs))
+	check.errorf(pos, errcode.CannotInferTypeArgs, "cannot infer %s (%s)", tpar.obj.name, tpar.obj.pos)
 	return nil
 }
 
+// inferTracef reports a step of type inference at pos to
+// Config.InferenceTrace, if set.
+func (check *Checker) inferTracef(pos syntax.Pos, format string, args ...interface{}) {
+	if check.conf.InferenceTrace != nil {
+		check.conf.InferenceTrace(pos, check.sprintf(format, args...))
+	}
+}
+
+// traceUnify reports the unification of the type x, described by xdesc,
+// with the type y, described by ydesc, which was successful if ok, and
+// the type arguments for tparams inferred by u. If arg is not nil, y is
+// the type of arg.
+func (check *Checker) traceUnify(pos syntax.Pos, xdesc string, x Type, ydesc string, y Type, arg *operand, ok bool, tparams []*TypeParam, u *unifier) {
+	if check.conf.InferenceTrace == nil {
+		return
+	}
+	of := ""
+	if arg != nil {
+		of = " of " + syntax.String(arg.expr)
+	}
+	if !ok {
+		check.inferTracef(pos, "unify %s %s with %s %s%s: mismatch", xdesc, x, ydesc, y, of)
+		return
+	}
+	targs, _ := u.x.types()
+	check.inferTracef(pos, "unify %s %s with %s %s%s: %s", xdesc, x, ydesc, y, of, check.inferredString(tparams, targs))
+}
+
+// inferredString returns a description of the type arguments targs
+// inferred so far for tparams, as in "T = int, U = ?", where ? marks
+// type parameters that have not been inferred.
+func (check *Checker) inferredString(tparams []*TypeParam, targs []Type) string {
+	var b bytes.Buffer
+	for i, tpar := range tparams {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(tpar.obj.name)
+		b.WriteString(" = ")
+		if i < len(targs) && targs[i] != nil {
+			WriteType(&b, targs[i], check.qualifier)
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// tparamsString returns the type parameter list tparams with their
+// constraints, as in "[T any, U ~[]T]".
+func tparamsString(qf Qualifier, tparams []*TypeParam) string {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i, tpar := range tparams {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(tpar.obj.name)
+		b.WriteByte(' ')
+		WriteType(&b, tpar.bound, qf)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
 // typeParamsString produces a string of the type parameter names
 // in list suitable for human consumption.
 func typeParamsString(list []*TypeParam) string {
@@ -360,7 +445,7 @@ func (w *tpWalker) isParameterizedTypeList(list []Type) bool {
 // first type argument in that list that couldn't be inferred (and thus is nil). If all
 // type arguments were inferred successfully, index is < 0. The number of type arguments
 // provided may be less than the number of type parameters, but there must be at least one.
-func (check *Checker) inferB(tparams []*TypeParam, targs []Type) (types []Type, index int) {
+func (check *Checker) inferB(pos syntax.Pos, tparams []*TypeParam, targs []Type) (types []Type, index int) {
 	assert(len(tparams) >= len(targs) && len(targs) > 0)
 
 	// Setup bidirectional unification between constraints
@@ -385,10 +470,12 @@ func (check *Checker) inferB(tparams []*TypeParam, targs []Type) (types []Type,
 			if named, _ := tpar.singleType().(*Named); named != nil {
 				sbound = named
 			}
-			if !u.unify(tpar, sbound) {
+			ok := u.unify(tpar, sbound)
+			check.traceUnify(pos, "type parameter", tpar, "structural type", sbound, nil, ok, tparams, u)
+			if !ok {
 				// TODO(gri) improve error message by providing the type arguments
 				//           which we know already
-				check.errorf(tpar.obj, "%s does not match %s", tpar, sbound)
+				check.errorf(tpar.obj, 0, "%s does not match %s", tpar, sbound)
 				return nil, 0
 			}
 		}
@@ -482,6 +569,7 @@ func (check *Checker) inferB(tparams []*TypeParam, targs []Type) (types []Type,
 		}
 	}
 
+	check.inferTracef(pos, "after constraint type inference: %s", check.inferredString(tparams, types))
 	return
 }
 
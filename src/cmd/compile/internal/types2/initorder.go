@@ -5,6 +5,7 @@
 package types2
 
 import (
+	"cmd/compile/internal/errcode"
 	"container/heap"
 	"fmt"
 )
@@ -151,7 +152,7 @@ func findPath(objMap map[Object]*declInfo, from, to Object, seen map[Object]bool
 // reportCycle reports an error for the given cycle.
 func (check *Checker) reportCycle(cycle []Object) {
 	obj := cycle[0]
-	var err error_
+	err := error_{code: errcode.InvalidInitCycle}
 	if check.conf.CompilerErrorMessages {
 		err.errorf(obj, "initialization loop for %s", obj.Name())
 	} else {
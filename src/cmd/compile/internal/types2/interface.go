@@ -120,9 +120,9 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 		name := f.Name.Value
 		if name == "_" {
 			if check.conf.CompilerErrorMessages {
-				check.error(f.Name, "methods must have a unique non-blank name")
+				check.error(f.Name, 0, "methods must have a unique non-blank name")
 			} else {
-				check.error(f.Name, "invalid method name _")
+				check.error(f.Name, 0, "invalid method name _")
 			}
 			continue // ignore
 		}
@@ -131,7 +131,7 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 		sig, _ := typ.(*Signature)
 		if sig == nil {
 			if typ != Typ[Invalid] {
-				check.errorf(f.Type, invalidAST+"%s is not a method signature", typ)
+				check.errorf(f.Type, 0, invalidAST+"%s is not a method signature", typ)
 			}
 			continue // ignore
 		}
@@ -140,7 +140,7 @@ func (check *Checker) interfaceType(ityp *Interface, iface *syntax.InterfaceType
 		// (This extra check is needed here because interface method signatures don't have
 		// a receiver specification.)
 		if sig.tparams != nil && !acceptMethodTypeParams {
-			check.error(f.Type, "methods cannot have type parameters")
+			check.error(f.Type, 0, "methods cannot have type parameters")
 		}
 
 		// use named receiver type if available (for better error messages)
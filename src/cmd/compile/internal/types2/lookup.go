@@ -6,6 +6,11 @@
 
 package types2
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Internal use of LookupFieldOrMethod: If the obj result is a method
 // associated with a concrete (non-interface) type, the method's signature
 // may not be fully set up. Call Checker.objDecl(obj, nil) before accessing
@@ -405,6 +410,52 @@ func (check *Checker) missingMethod(V Type, T *Interface, static bool) (method,
 	return
 }
 
+// missingMethodReason returns an explanation, in the style of the
+// compiler's error messages, of why V does not implement T, given the
+// method m and wrongType that check.missingMethod(V, T, true) returned:
+// which method V is missing, that V has it only with a pointer
+// receiver, or how its signature differs from the one T wants.
+func (check *Checker) missingMethodReason(V, T Type, m, wrongType *Func) string {
+	if wrongType == nil {
+		return check.sprintf("%s does not implement %s (missing %s method)", V, T, m.name)
+	}
+	if Identical(m.typ, wrongType.typ) {
+		// missingMethod found the method on *V.
+		return check.sprintf("%s does not implement %s (%s method has pointer receiver)", V, T, m.name)
+	}
+	have, want := wrongType.typ.(*Signature), m.typ.(*Signature)
+	return check.sprintf("%s does not implement %s (wrong type for %s method)\n\t\thave %s%s\n\t\twant %s%s\n\t\t%s", V, T, m.name,
+		wrongType.name, strings.TrimPrefix(check.sprintf("%s", have), "func"),
+		m.name, strings.TrimPrefix(check.sprintf("%s", want), "func"),
+		check.signatureDiff(have, want))
+}
+
+// signatureDiff describes the first difference between the signatures
+// x and y, ignoring receivers, in the form types.IdenticalReason in
+// the compiler uses for function types; for example,
+// "parameter 0: string vs int".
+func (check *Checker) signatureDiff(x, y *Signature) string {
+	for i, what := range []string{"parameter", "result"} {
+		xt, yt := x.params, y.params
+		if i == 1 {
+			xt, yt = x.results, y.results
+		}
+		if xt.Len() != yt.Len() {
+			return fmt.Sprintf("%s count %d vs %d", what, xt.Len(), yt.Len())
+		}
+		for j := 0; j < xt.Len(); j++ {
+			xp, yp := xt.At(j).typ, yt.At(j).typ
+			if i == 0 && j == xt.Len()-1 && x.variadic != y.variadic {
+				return fmt.Sprintf("%s %d: variadic vs not variadic", what, j)
+			}
+			if !Identical(xp, yp) {
+				return check.sprintf("%s %d: %s vs %s", what, j, xp, yp)
+			}
+		}
+	}
+	return "type parameters differ"
+}
+
 // assertableTo reports whether a value of type V can be asserted to have type T.
 // It returns (nil, false) as affirmative answer. Otherwise it returns a missing
 // method required by V and whether it is missing or just has the wrong type.
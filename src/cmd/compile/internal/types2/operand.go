@@ -8,6 +8,7 @@ package types2
 
 import (
 	"bytes"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"fmt"
 	"go/constant"
@@ -242,7 +243,7 @@ func (x *operand) isNil() bool { return x.mode == nilvalue }
 // is only valid if the (first) result is false. The check parameter may be nil
 // if assignableTo is invoked through an exported API call, i.e., when all
 // methods have been type-checked.
-func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, errorCode) {
+func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, errcode.Code) {
 	if x.mode == invalid || T == Typ[Invalid] {
 		return true, 0 // avoid spurious errors
 	}
@@ -274,10 +275,10 @@ func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, er
 				// don't need to do anything special.
 				newType, _, _ := check.implicitTypeAndValue(x, t.typ)
 				return newType != nil
-			}), _IncompatibleAssign
+			}), errcode.IncompatibleAssign
 		}
 		newType, _, _ := check.implicitTypeAndValue(x, T)
-		return newType != nil, _IncompatibleAssign
+		return newType != nil, errcode.IncompatibleAssign
 	}
 	// Vu is typed
 
@@ -290,7 +291,9 @@ func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, er
 	// T is an interface type and x implements T and T is not a type parameter
 	if Ti, ok := Tu.(*Interface); ok {
 		if m, wrongType := check.missingMethod(V, Ti, true); m != nil /* Implements(V, Ti) */ {
-			if reason != nil {
+			if reason != nil && check != nil && check.conf.CompilerErrorMessages {
+				*reason = check.missingMethodReason(V, T, m, wrongType)
+			} else if reason != nil {
 				// TODO(gri) the error messages here should follow the style in Checker.typeAssertion (factor!)
 				if wrongType != nil {
 					if Identical(m.typ, wrongType.typ) {
@@ -303,7 +306,7 @@ func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, er
 					*reason = "missing method " + m.Name()
 				}
 			}
-			return false, _InvalidIfaceAssign
+			return false, errcode.InvalidIfaceAssign
 		}
 		return true, 0
 	}
@@ -313,13 +316,13 @@ func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, er
 	// and at least one of V or T is not a named type.
 	if Vc, ok := Vu.(*Chan); ok && Vc.dir == SendRecv {
 		if Tc, ok := Tu.(*Chan); ok && Identical(Vc.elem, Tc.elem) {
-			return !hasName(V) || !hasName(T), _InvalidChanAssign
+			return !hasName(V) || !hasName(T), errcode.InvalidChanAssign
 		}
 	}
 
 	// optimization: if we don't have type parameters, we're done
 	if Vp == nil && Tp == nil {
-		return false, _IncompatibleAssign
+		return false, errcode.IncompatibleAssign
 	}
 
 	errorf := func(format string, args ...interface{}) {
@@ -336,7 +339,7 @@ func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, er
 	// x is assignable to each specific type in T's type set.
 	if !hasName(V) && Tp != nil {
 		ok := false
-		code := _IncompatibleAssign
+		code := errcode.IncompatibleAssign
 		Tp.is(func(T *term) bool {
 			if T == nil {
 				return false // no specific types
@@ -357,7 +360,7 @@ func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, er
 	if Vp != nil && !hasName(T) {
 		x := *x // don't clobber outer x
 		ok := false
-		code := _IncompatibleAssign
+		code := errcode.IncompatibleAssign
 		Vp.is(func(V *term) bool {
 			if V == nil {
 				return false // no specific types
@@ -373,7 +376,7 @@ func (x *operand) assignableTo(check *Checker, T Type, reason *string) (bool, er
 		return ok, code
 	}
 
-	return false, _IncompatibleAssign
+	return false, errcode.IncompatibleAssign
 }
 
 // kind2tok translates syntax.LitKinds into token.Tokens.
@@ -0,0 +1,74 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements the notes on the range of a numeric type that
+// errors about constants the type cannot represent carry in compiler
+// mode, so that they show how far off the constant is.
+
+package types2
+
+import (
+	"go/constant"
+	"go/token"
+	"math"
+	"strconv"
+)
+
+// rangeNote returns a note for an error about the constant x, which
+// the type T cannot represent, as in "valid range is -128 to 127". If
+// T is a floating-point type, or x is not an integer, the note also
+// gives the value of T nearest to x. It returns "" if T is not an
+// integer or floating-point type, or outside compiler mode.
+func (check *Checker) rangeNote(x constant.Value, T Type) string {
+	if !check.conf.CompilerErrorMessages || x == nil {
+		return ""
+	}
+	t, _ := under(T).(*Basic)
+	if t == nil || t.info&IsUntyped != 0 {
+		return ""
+	}
+	switch {
+	case isInteger(t):
+		bits := uint(8 * check.conf.sizeof(t))
+		min := constant.MakeInt64(0)
+		max := constant.Shift(constant.MakeInt64(1), token.SHL, bits)
+		if !isUnsigned(t) {
+			max = constant.Shift(max, token.SHR, 1)
+			min = constant.UnaryOp(token.SUB, max, 0)
+		}
+		max = constant.BinaryOp(max, token.SUB, constant.MakeInt64(1))
+		note := "valid range is " + min.ExactString() + " to " + max.ExactString()
+		if x.Kind() == constant.Float {
+			f, _ := constant.Float64Val(x)
+			near := constant.ToInt(constant.MakeFloat64(math.Round(f)))
+			if constant.Compare(near, token.LSS, min) {
+				near = min
+			} else if constant.Compare(near, token.GTR, max) {
+				near = max
+			}
+			note += ", nearest value is " + near.ExactString()
+		}
+		return note
+	case isFloat(t):
+		max := strconv.FormatFloat(math.MaxFloat64, 'g', -1, 64)
+		if check.conf.sizeof(t) == 4 {
+			max = strconv.FormatFloat(math.MaxFloat32, 'g', -1, 32)
+		}
+		near := max
+		if constant.Sign(x) < 0 {
+			near = "-" + max
+		}
+		return "valid range is -" + max + " to " + max + ", nearest value is " + near
+	}
+	return ""
+}
+
+// withNote returns what followed by "; " and note, or what alone if
+// note is "".
+func withNote(what, note string) string {
+	if note == "" {
+		return what
+	}
+	return what + "; " + note
+}
@@ -4,7 +4,10 @@
 
 package types2
 
-import "cmd/compile/internal/syntax"
+import (
+	"cmd/compile/internal/errcode"
+	"cmd/compile/internal/syntax"
+)
 
 // ----------------------------------------------------------------------------
 // API
@@ -31,6 +34,24 @@ type Signature struct {
 // parameter must be of unnamed slice type. If recv is non-nil, typeParams must
 // be empty. If recvTypeParams is non-empty, recv must be non-nil.
 func NewSignatureType(recv *Var, recvTypeParams, typeParams []*TypeParam, params, results *Tuple, variadic bool) *Signature {
+	if recv != nil && len(typeParams) != 0 {
+		panic("function with type parameters cannot have a receiver")
+	}
+	return newSignatureType(recv, recvTypeParams, typeParams, params, results, variadic)
+}
+
+// NewGenericMethodType is like NewSignatureType, but for methods with type
+// parameters of their own, which only packages checked with
+// Config.MethodTypeParams may declare: recv must be non-nil, and typeParams
+// non-empty.
+func NewGenericMethodType(recv *Var, recvTypeParams, typeParams []*TypeParam, params, results *Tuple, variadic bool) *Signature {
+	if recv == nil || len(typeParams) == 0 {
+		panic("generic method must have a receiver and type parameters")
+	}
+	return newSignatureType(recv, recvTypeParams, typeParams, params, results, variadic)
+}
+
+func newSignatureType(recv *Var, recvTypeParams, typeParams []*TypeParam, params, results *Tuple, variadic bool) *Signature {
 	if variadic {
 		n := params.Len()
 		if n == 0 {
@@ -48,9 +69,6 @@ func NewSignatureType(recv *Var, recvTypeParams, typeParams []*TypeParam, params
 		sig.rparams = bindTParams(recvTypeParams)
 	}
 	if len(typeParams) != 0 {
-		if recv != nil {
-			panic("function with type parameters cannot have a receiver")
-		}
 		sig.tparams = bindTParams(typeParams)
 	}
 	return sig
@@ -167,8 +185,8 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 		// Always type-check method type parameters but complain if they are not enabled.
 		// (A separate check is needed when type-checking interface method signatures because
 		// they don't have a receiver specification.)
-		if recvPar != nil && !acceptMethodTypeParams {
-			check.error(ftyp, "methods cannot have type parameters")
+		if recvPar != nil && !acceptMethodTypeParams && !check.conf.MethodTypeParams {
+			check.error(ftyp, 0, "methods cannot have type parameters")
 		}
 	}
 
@@ -183,8 +201,8 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 	params, variadic := check.collectParams(scope, ftyp.ParamList, true)
 	results, _ := check.collectParams(scope, ftyp.ResultList, false)
 	scope.Squash(func(obj, alt Object) {
-		var err error_
-		err.errorf(obj, "%s redeclared in this block", obj.Name())
+		err := error_{code: errcode.DuplicateDecl}
+		err.errorfMsg(obj, "Redeclared", "%s redeclared in this block", obj.Name())
 		err.recordAltDecl(alt)
 		check.report(&err)
 	})
@@ -200,7 +218,7 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 			recv = NewParam(nopos, nil, "", Typ[Invalid]) // ignore recv below
 		default:
 			// more than one receiver
-			check.error(recvList[len(recvList)-1].Pos(), "method must have exactly one receiver")
+			check.error(recvList[len(recvList)-1].Pos(), errcode.BadRecv, "method must have exactly one receiver")
 			fallthrough // continue with first receiver
 		case 1:
 			recv = recvList[0]
@@ -220,7 +238,7 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 				// The receiver type may be an instantiated type referred to
 				// by an alias (which cannot have receiver parameters for now).
 				if T.TypeArgs() != nil && sig.RecvTypeParams() == nil {
-					check.errorf(recv.pos, "cannot define methods on instantiated type %s", recv.typ)
+					check.errorf(recv.pos, 0, "cannot define methods on instantiated type %s", recv.typ)
 					break
 				}
 				// spec: "The type denoted by T is called the receiver base type; it must not
@@ -229,7 +247,7 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 				if T.obj.pkg != check.pkg {
 					err = "type not defined in this package"
 					if check.conf.CompilerErrorMessages {
-						check.errorf(recv.pos, "cannot define new methods on non-local type %s", recv.typ)
+						check.errorf(recv.pos, errcode.InvalidRecv, "cannot define new methods on non-local type %s", recv.typ)
 						err = ""
 					}
 				} else {
@@ -253,14 +271,14 @@ func (check *Checker) funcType(sig *Signature, recvPar *syntax.Field, tparams []
 			case *Basic:
 				err = "basic or unnamed type"
 				if check.conf.CompilerErrorMessages {
-					check.errorf(recv.pos, "cannot define new methods on non-local type %s", recv.typ)
+					check.errorf(recv.pos, errcode.InvalidRecv, "cannot define new methods on non-local type %s", recv.typ)
 					err = ""
 				}
 			default:
-				check.errorf(recv.pos, "invalid receiver type %s", recv.typ)
+				check.errorf(recv.pos, errcode.InvalidRecv, "invalid receiver type %s", recv.typ)
 			}
 			if err != "" {
-				check.errorf(recv.pos, "invalid receiver type %s (%s)", recv.typ, err)
+				check.errorf(recv.pos, errcode.InvalidRecv, "invalid receiver type %s (%s)", recv.typ, err)
 				// ok to continue
 			}
 		}
@@ -293,7 +311,7 @@ func (check *Checker) collectParams(scope *Scope, list []*syntax.Field, variadic
 				if variadicOk && i == len(list)-1 {
 					variadic = true
 				} else {
-					check.softErrorf(t, "can only use ... with final parameter in list")
+					check.softErrorf(t, errcode.MisplacedDotDotDot, "can only use ... with final parameter in list")
 					// ignore ... and continue
 				}
 			}
@@ -305,7 +323,7 @@ func (check *Checker) collectParams(scope *Scope, list []*syntax.Field, variadic
 			// named parameter
 			name := field.Name.Value
 			if name == "" {
-				check.error(field.Name, invalidAST+"anonymous parameter")
+				check.error(field.Name, 0, invalidAST+"anonymous parameter")
 				// ok to continue
 			}
 			par := NewParam(field.Name.Pos(), check.pkg, name, typ)
@@ -322,7 +340,7 @@ func (check *Checker) collectParams(scope *Scope, list []*syntax.Field, variadic
 	}
 
 	if named && anonymous {
-		check.error(list[0], invalidAST+"list contains both named and anonymous parameters")
+		check.error(list[0], 0, invalidAST+"list contains both named and anonymous parameters")
 		// ok to continue
 	}
 
@@ -163,11 +163,16 @@ func TestStdTest(t *testing.T) {
 	}
 
 	testTestDir(t, filepath.Join(runtime.GOROOT(), "test"),
-		"cmplxdivide.go", // also needs file cmplxdivide1.go - ignore
-		"directive.go",   // tests compiler rejection of bad directive placement - ignore
-		"embedfunc.go",   // tests //go:embed
-		"embedvers.go",   // tests //go:embed
-		"linkname2.go",   // types2 doesn't check validity of //go:xxx directives
+		"cmplxdivide.go",   // also needs file cmplxdivide1.go - ignore
+		"directive.go",     // tests compiler rejection of bad directive placement - ignore
+		"embedfunc.go",     // tests //go:embed
+		"embedvers.go",     // tests //go:embed
+		"inlinebudget2.go", // types2 doesn't check validity of //go:xxx directives
+		"inlinebudget3.go", // types2 doesn't check validity of //go:xxx directives
+		"likely.go",        // types2 doesn't check validity of //go:xxx directives
+		"likely2.go",       // types2 doesn't check validity of //go:xxx directives
+		"linkname2.go",     // types2 doesn't check validity of //go:xxx directives
+		"unroll2.go",       // types2 doesn't check validity of //go:xxx directives
 	)
 }
 
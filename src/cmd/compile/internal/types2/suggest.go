@@ -0,0 +1,193 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements the "did you mean" suggestions that the compiler's
+// error messages for undefined names and selectors carry.
+
+package types2
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the maximum number of names a suggestion lists.
+const maxSuggestions = 3
+
+// didYouMean returns the names among candidates that are closest to
+// name, sorted, or nil if none is close enough. A candidate is close if
+// it differs from name only in case, or if its edit distance from name
+// is at most a third of the length of name.
+func didYouMean(name string, candidates []string) []string {
+	best := -1
+	var names []string
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		if c == name || c == "_" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		d := 0
+		if !strings.EqualFold(c, name) {
+			d = editDistance(name, c)
+			if d > len(name)/3 {
+				continue
+			}
+		}
+		switch {
+		case best < 0 || d < best:
+			best, names = d, []string{c}
+		case d == best:
+			names = append(names, c)
+		}
+	}
+	sort.Strings(names)
+	if len(names) > maxSuggestions {
+		names = names[:maxSuggestions]
+	}
+	return names
+}
+
+// suggestion returns the suggestion of names, like "did you mean
+// Println?", with each name prefixed with qual.
+func suggestion(names []string, qual string) string {
+	q := make([]string, len(names))
+	for i, n := range names {
+		q[i] = qual + n
+	}
+	s := q[len(q)-1]
+	if len(q) > 1 {
+		s = strings.Join(q[:len(q)-1], ", ") + " or " + s
+	}
+	return "did you mean " + s + "?"
+}
+
+// editDistance returns the edit distance between a and b: the number
+// of runes to insert, delete or replace, and of pairs of adjacent runes
+// to swap, to turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// d[i][j] is the distance between ra[:i] and rb[:j].
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			n := d[i-1][j-1] + cost
+			if d[i-1][j]+1 < n {
+				n = d[i-1][j] + 1
+			}
+			if d[i][j-1]+1 < n {
+				n = d[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < n {
+				n = d[i-2][j-2] + 1
+			}
+			d[i][j] = n
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// scopeSuggestion returns the names to suggest for the undefined name
+// at the current position, from the names in scope there. If wantType is set,
+// the name is used as a type and only type names qualify.
+func (check *Checker) scopeSuggestion(name string, wantType bool) []string {
+	var candidates []string
+	for s := check.scope; s != nil; s = s.parent {
+		for _, n := range s.Names() {
+			// Objects in function scopes are only in scope after
+			// their declaration.
+			_, obj := check.scope.LookupParent(n, check.pos)
+			if obj == nil {
+				continue
+			}
+			if _, ok := obj.(*TypeName); wantType && !ok {
+				continue
+			}
+			candidates = append(candidates, n)
+		}
+	}
+	return didYouMean(name, candidates)
+}
+
+// packageSuggestion returns the names to suggest for the undefined
+// name sel of the imported package pkg, from its exported names.
+func (check *Checker) packageSuggestion(pkg *Package, sel string) []string {
+	var candidates []string
+	for _, n := range pkg.scope.Names() {
+		if isExported(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	return didYouMean(sel, candidates)
+}
+
+// selectorSuggestion returns the names to suggest for the undefined
+// selector sel of an operand of type T, from the fields and methods that the
+// operand has. If addressable is set, the operand is addressable, so
+// the methods of *T qualify. If methodsOnly is set, the operand is a
+// type, as in a method expression, and only methods qualify.
+func (check *Checker) selectorSuggestion(T Type, addressable, methodsOnly bool, sel string) []string {
+	var candidates []string
+	for _, n := range fieldAndMethodNames(T) {
+		obj, _, _ := lookupFieldOrMethod(T, addressable, check.pkg, n)
+		if obj == nil {
+			continue // ambiguous, or a method of *T
+		}
+		if _, ok := obj.(*Func); methodsOnly && !ok {
+			continue
+		}
+		candidates = append(candidates, n)
+	}
+	return didYouMean(sel, candidates)
+}
+
+// fieldAndMethodNames returns the names of the fields and methods of
+// T, including promoted ones, and of the methods of *T. It may return
+// names more than once, and names that are not accessible from the
+// package being checked.
+func fieldAndMethodNames(T Type) []string {
+	var names []string
+	seen := make(map[Type]bool)
+	var walk func(T Type)
+	walk = func(T Type) {
+		if p, _ := T.(*Pointer); p != nil {
+			T = p.base
+		}
+		if seen[T] {
+			return
+		}
+		seen[T] = true
+		if n := asNamed(T); n != nil {
+			for i := 0; i < n.NumMethods(); i++ {
+				names = append(names, n.Method(i).name)
+			}
+		}
+		switch u := under(T).(type) {
+		case *Struct:
+			for _, f := range u.fields {
+				names = append(names, f.name)
+				if f.embedded {
+					walk(f.typ)
+				}
+			}
+		case *Interface:
+			for _, m := range u.typeSet().methods {
+				names = append(names, m.name)
+			}
+		}
+	}
+	walk(T)
+	return names
+}
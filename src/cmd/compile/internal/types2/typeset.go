@@ -6,6 +6,7 @@ package types2
 
 import (
 	"bytes"
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"fmt"
 	"sort"
@@ -224,9 +225,9 @@ func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *_
 				panic(fmt.Sprintf("%s: duplicate method %s", m.pos, m.name))
 			}
 			// check != nil
-			var err error_
+			err := error_{code: errcode.DuplicateMethod}
 			err.errorf(pos, "duplicate method %s", m.name)
-			err.errorf(mpos[other.(*Func)], "other declaration of %s", m.name)
+			err.errorfMsg(mpos[other.(*Func)], "OtherDeclaration", "other declaration of %s", m.name)
 			check.report(&err)
 		default:
 			// We have a duplicate method name in an embedded (not explicitly declared) method.
@@ -242,9 +243,9 @@ func computeInterfaceTypeSet(check *Checker, pos syntax.Pos, ityp *Interface) *_
 			// check != nil
 			check.later(func() {
 				if !check.allowVersion(m.pkg, 1, 14) || !Identical(m.typ, other.Type()) {
-					var err error_
+					err := error_{code: errcode.DuplicateMethod}
 					err.errorf(pos, "duplicate method %s", m.name)
-					err.errorf(mpos[other.(*Func)], "other declaration of %s", m.name)
+					err.errorfMsg(mpos[other.(*Func)], "OtherDeclaration", "other declaration of %s", m.name)
 					check.report(&err)
 				}
 			})
@@ -387,7 +388,7 @@ func computeUnionTypeSet(check *Checker, pos syntax.Pos, utyp *Union) *_TypeSet
 		allTerms = allTerms.union(terms)
 		if len(allTerms) > maxTermCount {
 			if check != nil {
-				check.errorf(pos, "cannot handle more than %d union terms (implementation limitation)", maxTermCount)
+				check.errorf(pos, 0, "cannot handle more than %d union terms (implementation limitation)", maxTermCount)
 			}
 			utyp.tset = &invalidTypeSet
 			return utyp.tset
@@ -8,6 +8,7 @@ package types2
 
 import (
 	"bytes"
+	"cmd/compile/internal/typefmt"
 	"strconv"
 	"unicode/utf8"
 )
@@ -100,6 +101,15 @@ func (w *typeWriter) byte(b byte) {
 	}
 }
 
+// style returns the layout of struct and interface types. Type hashes
+// separate elements without a blank, as byte does.
+func (w *typeWriter) style() typefmt.Style {
+	if w.ctxt != nil {
+		return typefmt.Style{Sep: ";"}
+	}
+	return typefmt.Go
+}
+
 func (w *typeWriter) string(s string) {
 	w.buf.WriteString(s)
 }
@@ -145,11 +155,8 @@ func (w *typeWriter) typ(typ Type) {
 		w.typ(t.elem)
 
 	case *Struct:
-		w.string("struct{")
-		for i, f := range t.fields {
-			if i > 0 {
-				w.byte(';')
-			}
+		typefmt.Composite(w.style(), "struct", len(t.fields), w.string, func(i int) {
+			f := t.fields[i]
 			// This doesn't do the right thing for embedded type
 			// aliases where we should print the alias name, not
 			// the aliased type (see issue #44410).
@@ -165,8 +172,7 @@ func (w *typeWriter) typ(typ Type) {
 				//           accidentally.
 				w.string(strconv.Quote(tag))
 			}
-		}
-		w.byte('}')
+		})
 
 	case *Pointer:
 		w.byte('*')
@@ -206,24 +212,15 @@ func (w *typeWriter) typ(typ Type) {
 			// Print it as such and continue.
 			w.string("/* implicit */ ")
 		}
-		w.string("interface{")
-		first := true
-		for _, m := range t.methods {
-			if !first {
-				w.byte(';')
-			}
-			first = false
-			w.string(m.name)
-			w.signature(m.typ.(*Signature))
-		}
-		for _, typ := range t.embeddeds {
-			if !first {
-				w.byte(';')
+		typefmt.Composite(w.style(), "interface", len(t.methods)+len(t.embeddeds), w.string, func(i int) {
+			if i < len(t.methods) {
+				m := t.methods[i]
+				w.string(m.name)
+				w.signature(m.typ.(*Signature))
+				return
 			}
-			first = false
-			w.typ(typ)
-		}
-		w.byte('}')
+			w.typ(t.embeddeds[i-len(t.methods)])
+		})
 
 	case *Map:
 		w.string("map[")
@@ -7,6 +7,7 @@
 package types2
 
 import (
+	"cmd/compile/internal/errcode"
 	"cmd/compile/internal/syntax"
 	"fmt"
 	"go/constant"
@@ -35,19 +36,24 @@ func (check *Checker) ident(x *operand, e *syntax.Name, def *Named, wantType boo
 				x.mode = typexpr
 				x.typ = tpar
 			} else {
-				check.error(e, "cannot use _ as value or type")
+				check.error(e, errcode.InvalidBlank, "cannot use _ as value or type")
 			}
 		} else {
 			if check.conf.CompilerErrorMessages {
-				check.errorf(e, "undefined: %s", e.Value)
+				if names := check.scopeSuggestion(e.Value, wantType); names != nil {
+					details := &ErrorDetails{Suggestions: names}
+					check.errorfDetails(e, errcode.UndeclaredName, details, "undefined: %s (%s)", e.Value, suggestion(names, ""))
+				} else {
+					check.errorfMsg(e, errcode.UndeclaredName, false, "Undefined", "undefined: %s", e.Value)
+				}
 			} else {
-				check.errorf(e, "undeclared name: %s", e.Value)
+				check.errorf(e, errcode.UndeclaredName, "undeclared name: %s", e.Value)
 			}
 		}
 		return
 	case universeAny, universeComparable:
 		if !check.allowVersion(check.pkg, 1, 18) {
-			check.errorf(e, "undeclared name: %s (requires version go1.18 or later)", e.Value)
+			check.errorf(e, errcode.UnsupportedVersion, "undeclared name: %s (requires version go1.18 or later)", e.Value)
 			return // avoid follow-on errors
 		}
 	}
@@ -78,7 +84,7 @@ func (check *Checker) ident(x *operand, e *syntax.Name, def *Named, wantType boo
 
 	switch obj := obj.(type) {
 	case *PkgName:
-		check.errorf(e, "use of package %s not in selector", obj.name)
+		check.errorf(e, errcode.InvalidPkgUse, "use of package %s not in selector", obj.name)
 		return
 
 	case *Const:
@@ -88,7 +94,7 @@ func (check *Checker) ident(x *operand, e *syntax.Name, def *Named, wantType boo
 		}
 		if obj == universeIota {
 			if check.iota == nil {
-				check.error(e, "cannot use iota outside constant declaration")
+				check.error(e, errcode.InvalidIota, "cannot use iota outside constant declaration")
 				return
 			}
 			x.val = check.iota
@@ -153,9 +159,9 @@ func (check *Checker) varType(e syntax.Expr) Type {
 			tset := computeInterfaceTypeSet(check, pos, t) // TODO(gri) is this the correct position?
 			if !tset.IsMethodSet() {
 				if tset.comparable {
-					check.softErrorf(pos, "interface is (or embeds) comparable")
+					check.softErrorf(pos, 0, "interface is (or embeds) comparable")
 				} else {
-					check.softErrorf(pos, "interface contains type constraints")
+					check.softErrorf(pos, 0, "interface contains type constraints")
 				}
 			}
 		}
@@ -173,7 +179,7 @@ func (check *Checker) definedType(e syntax.Expr, def *Named) Type {
 	typ := check.typInternal(e, def)
 	assert(isTyped(typ))
 	if isGeneric(typ) {
-		check.errorf(e, "cannot use generic type %s without instantiation", typ)
+		check.errorf(e, errcode.NotInstantiated, "cannot use generic type %s without instantiation", typ)
 		typ = Typ[Invalid]
 	}
 	check.recordTypeAndValue(e, typexpr, typ, nil)
@@ -186,7 +192,7 @@ func (check *Checker) genericType(e syntax.Expr, reportErr bool) Type {
 	assert(isTyped(typ))
 	if typ != Typ[Invalid] && !isGeneric(typ) {
 		if reportErr {
-			check.errorf(e, "%s is not a generic type", typ)
+			check.errorf(e, 0, "%s is not a generic type", typ)
 		}
 		typ = Typ[Invalid]
 	}
@@ -240,9 +246,9 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 		case invalid:
 			// ignore - error reported before
 		case novalue:
-			check.errorf(&x, "%s used as type", &x)
+			check.errorf(&x, errcode.NotAType, "%s used as type", &x)
 		default:
-			check.errorf(&x, "%s is not a type", &x)
+			check.errorf(&x, errcode.NotAType, "%s is not a type", &x)
 		}
 
 	case *syntax.SelectorExpr:
@@ -257,9 +263,9 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 		case invalid:
 			// ignore - error reported before
 		case novalue:
-			check.errorf(&x, "%s used as type", &x)
+			check.errorf(&x, errcode.NotAType, "%s used as type", &x)
 		default:
-			check.errorf(&x, "%s is not a type", &x)
+			check.errorf(&x, errcode.NotAType, "%s is not a type", &x)
 		}
 
 	case *syntax.IndexExpr:
@@ -280,7 +286,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 			typ.len = check.arrayLength(e.Len)
 		} else {
 			// [...]array
-			check.error(e, "invalid use of [...] array (outside a composite literal)")
+			check.error(e, errcode.MisplacedDotDotDot, "invalid use of [...] array (outside a composite literal)")
 			typ.len = -1
 		}
 		typ.elem = check.varType(e.Elem)
@@ -298,7 +304,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 	case *syntax.DotsType:
 		// dots are handled explicitly where they are legal
 		// (array composite literals and parameter lists)
-		check.error(e, "invalid use of '...'")
+		check.error(e, errcode.MisplacedDotDotDot, "invalid use of '...'")
 		check.use(e.Elem)
 
 	case *syntax.StructType:
@@ -322,7 +328,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 			return typ
 		}
 
-		check.errorf(e0, "%s is not a type", e0)
+		check.errorf(e0, errcode.NotAType, "%s is not a type", e0)
 		check.use(e0)
 
 	case *syntax.FuncType:
@@ -359,7 +365,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 				if asTypeParam(typ.key) != nil {
 					why = " (missing comparable constraint)"
 				}
-				check.errorf(e.Key, "invalid map key type %s%s", typ.key, why)
+				check.errorf(e.Key, errcode.IncomparableMapKey, "invalid map key type %s%s", typ.key, why)
 			}
 		})
 
@@ -378,7 +384,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 		case syntax.RecvOnly:
 			dir = RecvOnly
 		default:
-			check.errorf(e, invalidAST+"unknown channel direction %d", e.Dir)
+			check.errorf(e, 0, invalidAST+"unknown channel direction %d", e.Dir)
 			// ok to continue
 		}
 
@@ -387,7 +393,7 @@ func (check *Checker) typInternal(e0 syntax.Expr, def *Named) (T Type) {
 		return typ
 
 	default:
-		check.errorf(e0, "%s is not a type", e0)
+		check.errorf(e0, errcode.NotAType, "%s is not a type", e0)
 		check.use(e0)
 	}
 
@@ -480,7 +486,7 @@ func (check *Checker) instantiatedType(x syntax.Expr, targsx []syntax.Expr, def
 				if i < len(posList) {
 					pos = posList[i]
 				}
-				check.softErrorf(pos, err.Error())
+				check.softErrorf(pos, errcode.InvalidIfaceAssign, err.Error())
 			} else {
 				check.mono.recordInstance(check.pkg, x.Pos(), inst.tparams.list(), inst.targs.list(), posList)
 			}
@@ -500,7 +506,7 @@ func (check *Checker) arrayLength(e syntax.Expr) int64 {
 	// attempt at a parameterized type declaration with missing constraint.
 	// Provide a better error message than just "undeclared name: X".
 	if name, _ := e.(*syntax.Name); name != nil && check.lookup(name.Value) == nil {
-		check.errorf(name, "undeclared name %s for array length", name.Value)
+		check.errorf(name, errcode.InvalidArrayLen, "undeclared name %s for array length", name.Value)
 		return -1
 	}
 
@@ -508,7 +514,7 @@ func (check *Checker) arrayLength(e syntax.Expr) int64 {
 	check.expr(&x, e)
 	if x.mode != constant_ {
 		if x.mode != invalid {
-			check.errorf(&x, "array length %s must be constant", &x)
+			check.errorf(&x, errcode.InvalidArrayLen, "array length %s must be constant", &x)
 		}
 		return -1
 	}
@@ -519,13 +525,13 @@ func (check *Checker) arrayLength(e syntax.Expr) int64 {
 				if n, ok := constant.Int64Val(val); ok && n >= 0 {
 					return n
 				}
-				check.errorf(&x, "invalid array length %s", &x)
+				check.errorf(&x, errcode.InvalidArrayLen, "invalid array length %s", &x)
 				return -1
 			}
 		}
 	}
 
-	check.errorf(&x, "array length %s must be integer", &x)
+	check.errorf(&x, errcode.InvalidArrayLen, "array length %s must be integer", &x)
 	return -1
 }
 
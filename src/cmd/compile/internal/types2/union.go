@@ -60,7 +60,7 @@ func parseUnion(check *Checker, tlist []syntax.Expr) Type {
 			return typ
 		}
 		if len(terms) >= maxTermCount {
-			check.errorf(x, "cannot handle more than %d union terms (implementation limitation)", maxTermCount)
+			check.errorf(x, 0, "cannot handle more than %d union terms (implementation limitation)", maxTermCount)
 			return Typ[Invalid]
 		}
 		terms = append(terms, NewTerm(tilde, typ))
@@ -79,12 +79,12 @@ func parseUnion(check *Checker, tlist []syntax.Expr) Type {
 			f, _ := u.(*Interface)
 			if t.tilde {
 				if f != nil {
-					check.errorf(tlist[i], "invalid use of ~ (%s is an interface)", t.typ)
+					check.errorf(tlist[i], 0, "invalid use of ~ (%s is an interface)", t.typ)
 					continue // don't report another error for t
 				}
 
 				if !Identical(u, t.typ) {
-					check.errorf(tlist[i], "invalid use of ~ (underlying type of %s is %s)", t.typ, u)
+					check.errorf(tlist[i], 0, "invalid use of ~ (underlying type of %s is %s)", t.typ, u)
 					continue // don't report another error for t
 				}
 			}
@@ -93,14 +93,14 @@ func parseUnion(check *Checker, tlist []syntax.Expr) Type {
 			// in the beginning. Embedded interfaces with tilde are excluded above. If we reach
 			// here, we must have at least two terms in the union.
 			if f != nil && !f.typeSet().IsTypeSet() {
-				check.errorf(tlist[i], "cannot use %s in union (interface contains methods)", t)
+				check.errorf(tlist[i], 0, "cannot use %s in union (interface contains methods)", t)
 				continue // don't report another error for t
 			}
 
 			// Report overlapping (non-disjoint) terms such as
 			// a|a, a|~a, ~a|~a, and ~a|A (where under(A) == a).
 			if j := overlappingTerm(terms[:i], t); j >= 0 {
-				check.softErrorf(tlist[i], "overlapping terms %s and %s", t, terms[j])
+				check.softErrorf(tlist[i], 0, "overlapping terms %s and %s", t, terms[j])
 			}
 		}
 	})
@@ -121,7 +121,7 @@ func parseTilde(check *Checker, x syntax.Expr) (tilde bool, typ Type) {
 	//       check to later and could return Typ[Invalid] instead.
 	check.later(func() {
 		if _, ok := under(typ).(*TypeParam); ok {
-			check.error(x, "cannot embed a type parameter")
+			check.error(x, 0, "cannot embed a type parameter")
 		}
 	})
 	return
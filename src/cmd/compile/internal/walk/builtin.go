@@ -9,6 +9,7 @@ import (
 	"go/constant"
 	"go/token"
 	"strings"
+	"unicode/utf8"
 
 	"cmd/compile/internal/base"
 	"cmd/compile/internal/escape"
@@ -220,6 +221,19 @@ func walkDelete(init *ir.Nodes, n *ir.CallExpr) ir.Node {
 
 // walkLenCap walks an OLEN or OCAP node.
 func walkLenCap(n *ir.UnaryExpr, init *ir.Nodes) ir.Node {
+	if n.Op() == ir.OLEN && (n.X.Op() == ir.OSTR2BYTES || n.X.Op() == ir.OSTR2RUNES) {
+		// Replace len([]byte(c)) and len([]rune(c)) of a constant
+		// string c with the number of bytes or runes of c.
+		if x := n.X.(*ir.ConvExpr).X; ir.IsConst(x, constant.String) {
+			c := len(ir.StringVal(x))
+			if n.X.Op() == ir.OSTR2RUNES {
+				c = utf8.RuneCountInString(ir.StringVal(x))
+			}
+			con := typecheck.OrigInt(n, int64(c))
+			con.SetTypecheck(1)
+			return con
+		}
+	}
 	if isRuneCount(n) {
 		// Replace len([]rune(string)) with runtime.countrunes(string).
 		return mkcall("countrunes", n.Type(), init, typecheck.Conv(n.X.(*ir.ConvExpr).X, types.Types[types.TSTRING]))
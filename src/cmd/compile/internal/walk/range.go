@@ -48,6 +48,7 @@ func walkRange(nrange *ir.RangeStmt) ir.Node {
 	nfor := ir.NewForStmt(nrange.Pos(), nil, nil, nil, nil)
 	nfor.SetInit(nrange.Init())
 	nfor.Label = nrange.Label
+	nfor.Unroll = nrange.Unroll
 
 	// variable name conventions:
 	//	ohv1, hv1, hv2: hidden (old) val 1, 2
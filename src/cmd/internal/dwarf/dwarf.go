@@ -97,11 +97,22 @@ type FnState struct {
 	External      bool
 	Scopes        []Scope
 	InlCalls      InlCalls
+	TypeParams    []TypeParam
 	UseBASEntries bool
 
 	dictIndexToOffset []int64
 }
 
+// A TypeParam describes a type parameter of a shape-based
+// instantiation of a generic function. The type parameter's type
+// argument for a call is the type that the DictIndex'th entry of the
+// dictionary passed to the call describes.
+type TypeParam struct {
+	Name      string
+	Type      Sym // the shape type argument
+	DictIndex uint16
+}
+
 func EnableLogging(doit bool) {
 	logDwarf = doit
 }
@@ -367,6 +378,7 @@ const (
 	DW_ABRV_STRUCTTYPE
 	DW_ABRV_TYPEDECL
 	DW_ABRV_DICT_INDEX
+	DW_ABRV_TYPEPARAM
 	DW_NABRV
 )
 
@@ -898,6 +910,17 @@ var abbrevs = [DW_NABRV]dwAbbrev{
 			{DW_AT_go_dict_index, DW_FORM_udata},
 		},
 	},
+
+	/* TYPEPARAM */
+	{
+		DW_TAG_template_type_parameter,
+		DW_CHILDREN_no,
+		[]dwAttrForm{
+			{DW_AT_name, DW_FORM_string},
+			{DW_AT_type, DW_FORM_ref_addr},
+			{DW_AT_go_dict_index, DW_FORM_udata},
+		},
+	},
 }
 
 // GetAbbrev returns the contents of the .debug_abbrev section.
@@ -1260,6 +1283,8 @@ func PutAbstractFunc(ctxt Context, s *FnState) error {
 	}
 	putattr(ctxt, s.Absfn, abbrev, DW_FORM_flag, DW_CLS_FLAG, ev, 0)
 
+	puttypeparams(ctxt, s.Absfn, s.TypeParams)
+
 	// Child variables (may be empty)
 	var flattened []*Var
 
@@ -1453,6 +1478,8 @@ func PutDefaultFunc(ctxt Context, s *FnState, isWrapper bool) error {
 		putattr(ctxt, s.Info, abbrev, DW_FORM_flag, DW_CLS_FLAG, ev, 0)
 	}
 
+	puttypeparams(ctxt, s.Info, s.TypeParams)
+
 	// Scopes
 	if err := putPrunedScopes(ctxt, s, abbrev); err != nil {
 		return err
@@ -1470,6 +1497,18 @@ func PutDefaultFunc(ctxt Context, s *FnState, isWrapper bool) error {
 	return nil
 }
 
+// puttypeparams writes template type parameter DIEs for the type
+// parameters tparams of a shape-based instantiation to info. A concrete
+// subprogram gets them from its abstract origin.
+func puttypeparams(ctxt Context, info Sym, tparams []TypeParam) {
+	for _, tp := range tparams {
+		Uleb128put(ctxt, info, int64(DW_ABRV_TYPEPARAM))
+		putattr(ctxt, info, DW_ABRV_TYPEPARAM, DW_FORM_string, DW_CLS_STRING, int64(len(tp.Name)), tp.Name)
+		putattr(ctxt, info, DW_ABRV_TYPEPARAM, DW_FORM_ref_addr, DW_CLS_REFERENCE, 0, tp.Type)
+		putattr(ctxt, info, DW_ABRV_TYPEPARAM, DW_FORM_udata, DW_CLS_CONSTANT, int64(tp.DictIndex), nil)
+	}
+}
+
 // putparamtypes writes typedef DIEs for any parametric types that are used by this function.
 func putparamtypes(ctxt Context, s *FnState, scopes []Scope, fnabbrev int) []int64 {
 	if fnabbrev == DW_ABRV_FUNCTION_CONCRETE {
@@ -704,6 +704,10 @@ const (
 	// IsPcdata indicates this is a pcdata symbol.
 	AttrPcdata
 
+	// Cold is set for text symbols of functions that are rarely
+	// called. The linker places them after the other functions.
+	AttrCold
+
 	// attrABIBase is the value at which the ABI is encoded in
 	// Attribute. This must be last; all bits after this are
 	// assumed to be an ABI value.
@@ -732,6 +736,7 @@ func (a *Attribute) UsedInIface() bool        { return a.load()&AttrUsedInIface
 func (a *Attribute) ContentAddressable() bool { return a.load()&AttrContentAddressable != 0 }
 func (a *Attribute) ABIWrapper() bool         { return a.load()&AttrABIWrapper != 0 }
 func (a *Attribute) IsPcdata() bool           { return a.load()&AttrPcdata != 0 }
+func (a *Attribute) Cold() bool               { return a.load()&AttrCold != 0 }
 
 func (a *Attribute) Set(flag Attribute, value bool) {
 	for {
@@ -780,6 +785,7 @@ var textAttrStrings = [...]struct {
 	{bit: AttrIndexed, s: ""},
 	{bit: AttrContentAddressable, s: ""},
 	{bit: AttrABIWrapper, s: "ABIWRAPPER"},
+	{bit: AttrCold, s: "COLD"},
 }
 
 // String formats a for printing in as part of a TEXT prog.
@@ -885,6 +891,7 @@ type Link struct {
 	Bso                *bufio.Writer
 	Pathname           string
 	Pkgpath            string           // the current package's import path, "" if unknown
+	LinkPkgpath        string           // the current package's import path in symbol names, if it differs from Pkgpath
 	hashmu             sync.Mutex       // protects hash, funchash
 	hash               map[string]*LSym // name -> sym mapping
 	funchash           map[string]*LSym // name -> sym mapping for ABIInternal syms
@@ -895,7 +902,7 @@ type Link struct {
 	Imports            []goobj.ImportedPkg
 	DiagFunc           func(string, ...interface{})
 	DiagFlush          func()
-	DebugInfo          func(fn *LSym, info *LSym, curfn interface{}) ([]dwarf.Scope, dwarf.InlCalls) // if non-nil, curfn is a *gc.Node
+	DebugInfo          func(fn *LSym, info *LSym, curfn interface{}) ([]dwarf.Scope, dwarf.InlCalls, []dwarf.TypeParam) // if non-nil, curfn is a *gc.Node
 	GenAbstractFunc    func(fn *LSym)
 	Errors             int
 
@@ -917,6 +924,11 @@ type Link struct {
 	// symbol reference in the object file.
 	pkgIdx map[string]int32
 
+	// pkgByLinkPrefix maps the prefixes of the symbol names of
+	// packages whose import paths are rewritten in symbol names to
+	// their escaped import paths, by which the linker finds them.
+	pkgByLinkPrefix map[string]string
+
 	defs         []*LSym // list of defined symbols in the current package
 	hashed64defs []*LSym // list of defined short (64-bit or less) hashed (content-addressable) symbols
 	hasheddefs   []*LSym // list of defined hashed (content-addressable) symbols
@@ -924,6 +936,26 @@ type Link struct {
 	nonpkgrefs   []*LSym // list of referenced non-package symbols
 
 	Fingerprint goobj.FingerprintType // fingerprint of symbol indices, to catch index mismatch
+
+	asmSource map[string][]string // source lines by file name, for -S=3
+}
+
+// linkPkgpath returns the current package's import path as used in
+// symbol names.
+func (ctxt *Link) linkPkgpath() string {
+	if ctxt.LinkPkgpath != "" {
+		return ctxt.LinkPkgpath
+	}
+	return ctxt.Pkgpath
+}
+
+// SetPkgLinkPrefix records that the symbols of the package with the
+// escaped import path pkg are named with the prefix linkPrefix.
+func (ctxt *Link) SetPkgLinkPrefix(pkg, linkPrefix string) {
+	if ctxt.pkgByLinkPrefix == nil {
+		ctxt.pkgByLinkPrefix = make(map[string]string)
+	}
+	ctxt.pkgByLinkPrefix[linkPrefix] = pkg
 }
 
 func (ctxt *Link) Diag(format string, args ...interface{}) {
@@ -16,6 +16,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
@@ -33,7 +34,7 @@ func WriteObjFile(ctxt *Link, b *bio.Writer) {
 	w := writer{
 		Writer:  goobj.NewWriter(b),
 		ctxt:    ctxt,
-		pkgpath: objabi.PathToPrefix(ctxt.Pkgpath),
+		pkgpath: objabi.PathToPrefix(ctxt.linkPkgpath()),
 	}
 
 	start := b.Offset()
@@ -321,9 +322,12 @@ func (w *writer) Sym(s *LSym) {
 	if strings.HasPrefix(s.Name, "go.itab.") && s.Type == objabi.SRODATA {
 		flag2 |= goobj.SymFlagItab
 	}
-	if strings.HasPrefix(s.Name, w.ctxt.Pkgpath) && strings.HasPrefix(s.Name[len(w.ctxt.Pkgpath):], ".") && strings.HasPrefix(s.Name[len(w.ctxt.Pkgpath)+1:], objabi.GlobalDictPrefix) {
+	if pkgpath := w.ctxt.linkPkgpath(); strings.HasPrefix(s.Name, pkgpath) && strings.HasPrefix(s.Name[len(pkgpath):], ".") && strings.HasPrefix(s.Name[len(pkgpath)+1:], objabi.GlobalDictPrefix) {
 		flag2 |= goobj.SymFlagDict
 	}
+	if s.Cold() {
+		flag2 |= goobj.SymFlagCold
+	}
 	name := s.Name
 	if strings.HasPrefix(name, "gofile..") {
 		name = filepath.ToSlash(name)
@@ -812,6 +816,10 @@ func (ctxt *Link) writeSymDebugNamed(s *LSym, name string) {
 		}
 	}
 	fmt.Fprintf(ctxt.Bso, "\n")
+	if s.Type == objabi.STEXT && ctxt.Debugasm > 2 {
+		ctxt.writeTextDebug(s)
+		return
+	}
 	if s.Type == objabi.STEXT {
 		for p := s.Func().Text; p != nil; p = p.Link {
 			fmt.Fprintf(ctxt.Bso, "\t%#04x ", uint(int(p.Pc)))
@@ -847,22 +855,94 @@ func (ctxt *Link) writeSymDebugNamed(s *LSym, name string) {
 
 	sort.Sort(relocByOff(s.R)) // generate stable output
 	for _, r := range s.R {
-		name := ""
-		ver := ""
-		if r.Sym != nil {
-			name = r.Sym.Name
-			if ctxt.Debugasm > 1 {
-				ver = fmt.Sprintf("<%d>", r.Sym.ABI())
-			}
-		} else if r.Type == objabi.R_TLS_LE {
-			name = "TLS"
+		ctxt.writeRelocDebug("\t", r)
+	}
+}
+
+// writeRelocDebug writes the relocation r, indented by indent, to the
+// assembly listing.
+func (ctxt *Link) writeRelocDebug(indent string, r Reloc) {
+	name := ""
+	ver := ""
+	if r.Sym != nil {
+		name = r.Sym.Name
+		if ctxt.Debugasm > 1 {
+			ver = fmt.Sprintf("<%d>", r.Sym.ABI())
 		}
-		if ctxt.Arch.InFamily(sys.ARM, sys.PPC64) {
-			fmt.Fprintf(ctxt.Bso, "\trel %d+%d t=%d %s%s+%x\n", int(r.Off), r.Siz, r.Type, name, ver, uint64(r.Add))
-		} else {
-			fmt.Fprintf(ctxt.Bso, "\trel %d+%d t=%d %s%s+%d\n", int(r.Off), r.Siz, r.Type, name, ver, r.Add)
+	} else if r.Type == objabi.R_TLS_LE {
+		name = "TLS"
+	}
+	if ctxt.Arch.InFamily(sys.ARM, sys.PPC64) {
+		fmt.Fprintf(ctxt.Bso, "%srel %d+%d t=%d %s%s+%x\n", indent, int(r.Off), r.Siz, r.Type, name, ver, uint64(r.Add))
+	} else {
+		fmt.Fprintf(ctxt.Bso, "%srel %d+%d t=%d %s%s+%d\n", indent, int(r.Off), r.Siz, r.Type, name, ver, r.Add)
+	}
+}
+
+// writeTextDebug writes the instructions of the text symbol s to the
+// assembly listing for -S=3, each with its machine encoding and the
+// relocations that apply to it, preceded by the source line it came
+// from whenever that changes, like objdump -S.
+func (ctxt *Link) writeTextDebug(s *LSym) {
+	sort.Sort(relocByOff(s.R)) // generate stable output
+	r := s.R
+	var file string
+	line := -1
+	for p := s.Func().Text; p != nil; p = p.Link {
+		pos := ctxt.InnermostPos(p.Pos)
+		if pos.IsKnown() && (pos.Filename() != file || int(pos.Line()) != line) {
+			file, line = pos.Filename(), int(pos.Line())
+			fmt.Fprintf(ctxt.Bso, "\t%s:%d\t%s\n", filepath.Base(file), line, ctxt.sourceLine(pos.Filename(), pos.AbsFilename(), line))
 		}
+
+		// An instruction's encoding runs to the start of the next
+		// one. Pseudo-instructions such as PCDATA take no space.
+		end := int64(len(s.P))
+		if p.Link != nil {
+			end = p.Link.Pc
+		}
+		if end < p.Pc || end > int64(len(s.P)) {
+			end = p.Pc
+		}
+		var enc strings.Builder
+		for i := p.Pc; i < end; i++ {
+			fmt.Fprintf(&enc, " %02x", s.P[i])
+		}
+		fmt.Fprintf(ctxt.Bso, "\t%#04x%-24s\t", uint(p.Pc), enc.String())
+		p.WriteInstructionString(ctxt.Bso)
+		fmt.Fprintln(ctxt.Bso)
+		for len(r) > 0 && int64(r[0].Off) < end {
+			ctxt.writeRelocDebug("\t\t", r[0])
+			r = r[1:]
+		}
+	}
+	for _, r := range r {
+		ctxt.writeRelocDebug("\t", r)
+	}
+}
+
+// sourceLine returns the text of line n of the source file named
+// name, or abs if name cannot be read, for the -S=3 assembly listing.
+// It returns "" if neither can be read.
+func (ctxt *Link) sourceLine(name, abs string, n int) string {
+	lines, ok := ctxt.asmSource[name]
+	if !ok {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			data, err = ioutil.ReadFile(abs)
+		}
+		if err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		if ctxt.asmSource == nil {
+			ctxt.asmSource = make(map[string][]string)
+		}
+		ctxt.asmSource[name] = lines
+	}
+	if n < 1 || n > len(lines) {
+		return ""
 	}
+	return strings.TrimSpace(lines[n-1])
 }
 
 // relocByOff sorts relocations by their offsets.
@@ -287,6 +287,9 @@ func (ctxt *Link) NumberSyms() {
 			nonpkgidx++
 			return
 		}
+		if p, ok := ctxt.pkgByLinkPrefix[pkg]; ok {
+			pkg = p
+		}
 		if k, ok := ctxt.pkgIdx[pkg]; ok {
 			rs.PkgIdx = k
 			return
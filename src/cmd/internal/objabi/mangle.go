@@ -0,0 +1,108 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package objabi
+
+import "strings"
+
+// The names of type symbols, such as "type.[]int", and of the
+// functions generated for types, such as "type..eq.[2]string", embed
+// the compiler's description of the type. The description may contain
+// characters that external tools handle poorly in symbol names:
+// spaces, quotes, backquotes, brackets, and the middle dot (U+00B7)
+// with which the compiler numbers function-local types.
+//
+// When the compiler is run with -d=manglenames, it mangles the
+// descriptions as follows. ASCII letters and digits and the characters
+// '_', '.', '*' and '/' are kept, as is the qualifier `"".`, which is
+// replaced with the package's path when the object file is written or
+// linked. Every other byte, including each byte of the UTF-8 encoding
+// of a non-ASCII character, is written as '$' followed by two
+// lowercase hexadecimal digits. For example,
+//
+//	map["".T·1][]int
+//
+// is mangled as
+//
+//	map$5b"".T$c2$b71$5d$5b$5dint
+//
+// The scheme is injective, so mangled names are as unique as the
+// descriptions they encode. Since type symbols are deduplicated by
+// name, all packages linked into a binary must agree on whether names
+// are mangled: use -gcflags=all=-d=manglenames=1.
+//
+// MangleTypeName and DemangleTypeName convert between the forms.
+
+// localPrefix is the qualifier of the package being compiled.
+const localPrefix = `"".`
+
+func keepMangled(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '_' || c == '.' || c == '*' || c == '/'
+}
+
+// MangleTypeName returns the mangled form of the type description
+// name.
+func MangleTypeName(name string) string {
+	for i := 0; i < len(name); i++ {
+		if !keepMangled(name[i]) {
+			return string(AppendMangledTypeName(make([]byte, 0, len(name)+16), name))
+		}
+	}
+	return name
+}
+
+// AppendMangledTypeName appends the mangled form of the type
+// description name to b and returns the extended buffer.
+func AppendMangledTypeName(b []byte, name string) []byte {
+	const hex = "0123456789abcdef"
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case keepMangled(c):
+			b = append(b, c)
+		case strings.HasPrefix(name[i:], localPrefix):
+			b = append(b, localPrefix...)
+			i += len(localPrefix) - 1
+		default:
+			b = append(b, '$', hex[c>>4], hex[c&0xF])
+		}
+	}
+	return b
+}
+
+// DemangleTypeName returns the type description that name is the
+// mangled form of. A '$' not followed by two lowercase hexadecimal
+// digits is left as is.
+func DemangleTypeName(name string) string {
+	i := strings.IndexByte(name, '$')
+	if i < 0 {
+		return name
+	}
+	b := make([]byte, 0, len(name))
+	b = append(b, name[:i]...)
+	for ; i < len(name); i++ {
+		if name[i] == '$' && i+2 < len(name) {
+			hi, ok1 := unhex(name[i+1])
+			lo, ok2 := unhex(name[i+2])
+			if ok1 && ok2 {
+				b = append(b, hi<<4|lo)
+				i += 2
+				continue
+			}
+		}
+		b = append(b, name[i])
+	}
+	return string(b)
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	}
+	return 0, false
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package objabi
+
+import "testing"
+
+func TestMangleTypeName(t *testing.T) {
+	tests := []struct {
+		name, mangled string
+	}{
+		{"int", "int"},
+		{"*runtime._type", "*runtime._type"},
+		{`map["".T·1][]int`, `map$5b"".T$c2$b71$5d$5b$5dint`},
+		{"struct { F int \"json:\\\"f\\\"\" }", "struct$20$7b$20F$20int$20$22json$3a$5c$22f$5c$22$22$20$7d"},
+		{"func(...int) (int, error)", "func$28...int$29$20$28int$2c$20error$29"},
+		{"go.shape.func{$0 any}($0)", "go.shape.func$7b$240$20any$7d$28$240$29"},
+		{"chan<- example.com/a%2eb.T", "chan$3c$2d$20example.com/a$252eb.T"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := MangleTypeName(test.name); got != test.mangled {
+			t.Errorf("MangleTypeName(%q) = %q, want %q", test.name, got, test.mangled)
+		}
+		if got := DemangleTypeName(test.mangled); got != test.name {
+			t.Errorf("DemangleTypeName(%q) = %q, want %q", test.mangled, got, test.name)
+		}
+		if got := string(AppendMangledTypeName([]byte("type."), test.name)); got != "type."+test.mangled {
+			t.Errorf("AppendMangledTypeName(type., %q) = %q, want %q", test.name, got, "type."+test.mangled)
+		}
+	}
+
+	for _, name := range []string{"$", "a$2", "a$zz", "a$2A"} {
+		if got := DemangleTypeName(name); got != name {
+			t.Errorf("DemangleTypeName(%q) = %q, want it unchanged", name, got)
+		}
+	}
+}
+
+// TestMangleTypeNameInjective checks that DemangleTypeName inverts
+// MangleTypeName, which makes the mangling injective, for all short
+// strings over an alphabet of the bytes that the scheme treats
+// specially, and that distinct strings have distinct mangled forms.
+func TestMangleTypeNameInjective(t *testing.T) {
+	alphabet := []string{"a", "2", "f", ".", "$", `"`, " ", "\xc2", "\xb7", "\xff"}
+	names := []string{""}
+	for n := 0; n < 4; n++ {
+		for _, name := range names {
+			if len(name) != n {
+				continue
+			}
+			for _, c := range alphabet {
+				names = append(names, name+c)
+			}
+		}
+	}
+
+	seen := make(map[string]string)
+	for _, name := range names {
+		m := MangleTypeName(name)
+		if got := DemangleTypeName(m); got != name {
+			t.Errorf("DemangleTypeName(MangleTypeName(%q)) = %q", name, got)
+		}
+		if prev, ok := seen[m]; ok {
+			t.Errorf("MangleTypeName(%q) = MangleTypeName(%q) = %q", prev, name, m)
+		}
+		seen[m] = name
+		for i := 0; i < len(m); i++ {
+			if c := m[i]; !keepMangled(c) && c != '$' && c != '"' {
+				t.Errorf("MangleTypeName(%q) = %q contains %q", name, m, c)
+				break
+			}
+		}
+	}
+}
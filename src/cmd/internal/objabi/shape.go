@@ -0,0 +1,110 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package objabi
+
+import "strings"
+
+// The compiler implements the instantiations of a generic function or
+// type with type arguments of the same underlying type by one
+// shape-based instantiation, whose type arguments are shape types. The
+// symbol name of a shape type is ShapePrefix followed by the link
+// string of the underlying type and the index of the type parameter,
+// as in go.shape.int_0, and it is embedded in the symbol names of
+// shape-based instantiations, as in main.G[go.shape.int_0] or
+// main.(*L[go.shape.string_0]).Get.
+//
+// The readable form of a shape type name drops the index and replaces
+// ShapePrefix with ShapeReadablePrefix, as in shape:int. The linker
+// writes the type argument lists of functions into the runtime's
+// function name table in readable form, so that tracebacks show
+// main.G[shape:int] where they cannot show the type arguments of a
+// call. The compiler's NameString writes shape types the same way.
+
+// ShapePrefix is the prefix of the symbol names of shape types.
+const ShapePrefix = "go.shape."
+
+// ShapeReadablePrefix is the prefix of the readable form of shape type
+// names.
+const ShapeReadablePrefix = "shape:"
+
+// CutShape finds the first shape type name in the symbol name name and
+// returns the text before it, the underlying type of the shape and
+// the text after it. For example, for main.G[go.shape.int_0,bool] it
+// returns "main.G[", "int" and ",bool]". If name does not contain a
+// shape type name, CutShape returns name, "", "" and false.
+func CutShape(name string) (before, shape, after string, found bool) {
+	for i := 0; ; {
+		j := strings.Index(name[i:], ShapePrefix)
+		if j < 0 {
+			return name, "", "", false
+		}
+		i += j
+		start := i + len(ShapePrefix)
+		if end, next := shapeEnd(name, start); end >= 0 {
+			return name[:i], name[start:end], name[next:], true
+		}
+		i = start
+	}
+}
+
+// shapeEnd returns the end of the underlying type of the shape type
+// name whose underlying type starts at name[start:], and the end of
+// its index suffix. The index suffix is the first "_N" outside of
+// brackets, parentheses, braces and quoted strings that is followed by
+// ',', ']' or the end of name. If there is no index suffix, shapeEnd
+// returns -1, -1.
+func shapeEnd(name string, start int) (end, next int) {
+	depth := 0
+	for i := start; i < len(name); i++ {
+		switch c := name[i]; c {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			if depth == 0 {
+				return -1, -1
+			}
+			depth--
+		case '"':
+			// Skip a struct tag or the `"".` qualifier of the local
+			// package.
+			for i++; i < len(name) && name[i] != '"'; i++ {
+				if name[i] == '\\' {
+					i++
+				}
+			}
+		case '_':
+			if depth != 0 {
+				break
+			}
+			j := i + 1
+			for j < len(name) && '0' <= name[j] && name[j] <= '9' {
+				j++
+			}
+			if j > i+1 && (j == len(name) || name[j] == ',' || name[j] == ']') {
+				return i, j
+			}
+		}
+	}
+	return -1, -1
+}
+
+// ReadableShapeName returns name with each shape type name in it,
+// including those in the underlying types of shapes, written in
+// readable form, as in main.G[shape:int] for main.G[go.shape.int_0].
+func ReadableShapeName(name string) string {
+	before, shape, after, found := CutShape(name)
+	if !found {
+		return name
+	}
+	b := make([]byte, 0, len(name))
+	for found {
+		b = append(b, before...)
+		b = append(b, ShapeReadablePrefix...)
+		b = append(b, ReadableShapeName(shape)...)
+		before, shape, after, found = CutShape(after)
+	}
+	b = append(b, before...)
+	return string(b)
+}
@@ -0,0 +1,35 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package objabi
+
+import "testing"
+
+func TestReadableShapeName(t *testing.T) {
+	for _, test := range []struct {
+		name, want string
+	}{
+		{"main.F", "main.F"},
+		{"main.G[int]", "main.G[int]"},
+		{"main.G[go.shape.int_0]", "main.G[shape:int]"},
+		{"main.G[go.shape.int_0,go.shape.string_1]", "main.G[shape:int,shape:string]"},
+		{"main.(*L[go.shape.*uint8_0]).Get", "main.(*L[shape:*uint8]).Get"},
+		{"main.G[go.shape.map[int]string_0]", "main.G[shape:map[int]string]"},
+		{"main.G[go.shape.[2]int_0].func1", "main.G[shape:[2]int].func1"},
+		{`main.G[go.shape.struct { "".a_1 int; "".b int "x_2]" }_0]`, `main.G[shape:struct { "".a_1 int; "".b int "x_2]" }]`},
+		{"main.G[go.shape.func() main.T_1_0]", "main.G[shape:func() main.T_1]"},
+		{"main.G[go.shape.int_0].H[go.shape.bool_1]", "main.G[shape:int].H[shape:bool]"},
+		{"main.F[go.shape.map[go.shape.string_0]go.shape.[]int_1_0]", "main.F[shape:map[shape:string]shape:[]int]"},
+		{"go.shape.int_0", "shape:int"},
+
+		// Not shape type names.
+		{"main.G[go.shape.int]", "main.G[go.shape.int]"},
+		{"main.G[go.shape.int_x]", "main.G[go.shape.int_x]"},
+		{"main.go.shape", "main.go.shape"},
+	} {
+		if got := ReadableShapeName(test.name); got != test.want {
+			t.Errorf("ReadableShapeName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -77,6 +77,11 @@ Flags:
 		Set space-separated flags to pass to the external linker.
 	-f
 		Ignore version mismatch in the linked archives.
+	-foldinst
+		Fold the shape-based instantiations of a generic function whose
+		code and metadata are identical, such as those for int and int64,
+		into one. Folded instantiations share their name in tracebacks.
+		Instantiations of different functions are not folded.
 	-g
 		Disable Go package data checks.
 	-importcfg file
@@ -118,13 +118,14 @@ func trampoline(ctxt *Link, s loader.Sym) {
 		// which we can readily exceed in the same package. As such, we
 		// need to generate trampolines when the address is unknown.
 		if ldr.SymValue(rs) == 0 && !ctxt.Target.IsRISCV64() && ldr.SymType(rs) != sym.SDYNIMPORT && ldr.SymType(rs) != sym.SUNDEFEXT {
-			if ldr.SymPkg(s) != "" && ldr.SymPkg(rs) == ldr.SymPkg(s) {
+			if ldr.SymPkg(s) != "" && ldr.SymPkg(rs) == ldr.SymPkg(s) && !ldr.IsCold(rs) {
 				// Symbols in the same package are laid out together.
 				// Except that if SymPkg(s) == "", it is a host object symbol
-				// which may call an external symbol via PLT.
+				// which may call an external symbol via PLT. And cold
+				// functions are laid out after all others.
 				continue
 			}
-			if isRuntimeDepPkg(ldr.SymPkg(s)) && isRuntimeDepPkg(ldr.SymPkg(rs)) {
+			if isRuntimeDepPkg(ldr.SymPkg(s)) && isRuntimeDepPkg(ldr.SymPkg(rs)) && !ldr.IsCold(rs) {
 				continue // runtime packages are laid out together
 			}
 		}
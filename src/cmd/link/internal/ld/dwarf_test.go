@@ -1849,3 +1849,87 @@ func main() {
 		}
 	}
 }
+
+func TestTypeParams(t *testing.T) {
+	// Check that a shape-based instantiation has a template type
+	// parameter DIE for each type parameter, with the index of the
+	// type argument in its dictionary.
+	testenv.MustHaveGoBuild(t)
+
+	if runtime.GOOS == "plan9" {
+		t.Skip("skipping on plan9; no DWARF symbol table in executables")
+	}
+	if buildcfg.Experiment.Unified {
+		t.Skip("GOEXPERIMENT=unified does not emit dictionaries yet")
+	}
+	t.Parallel()
+
+	const prog = `
+package main
+
+import "fmt"
+
+type Map[K comparable, V any] struct{ m map[K]V }
+
+//go:noinline
+func (m *Map[K, V]) Get(k K) V { return m.m[k] }
+
+func main() {
+	m := &Map[string, int]{map[string]int{"a": 1}}
+	fmt.Println(m.Get("a"))
+}
+`
+
+	dir := t.TempDir()
+	f := gobuild(t, dir, prog, NoOpt)
+	defer f.Close()
+
+	d, err := f.DWARF()
+	if err != nil {
+		t.Fatalf("error reading DWARF: %v", err)
+	}
+
+	rdr := d.Reader()
+	found := false
+	for entry, err := rdr.Next(); entry != nil; entry, err = rdr.Next() {
+		if err != nil {
+			t.Fatalf("error reading DWARF: %v", err)
+		}
+		name, _ := entry.Val(dwarf.AttrName).(string)
+		if entry.Tag == dwarf.TagSubprogram && strings.HasPrefix(name, "main.(*Map[") && strings.HasSuffix(name, "]).Get") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("could not find main.(*Map[...]).Get")
+	}
+
+	var got []string
+	for entry, err := rdr.Next(); entry != nil; entry, err = rdr.Next() {
+		if err != nil {
+			t.Fatalf("error reading DWARF: %v", err)
+		}
+		if entry.Tag == 0 {
+			break
+		}
+		if entry.Tag != dwarf.TagTemplateTypeParameter {
+			if entry.Children {
+				rdr.SkipChildren()
+			}
+			continue
+		}
+		name, _ := entry.Val(dwarf.AttrName).(string)
+		idx, ok := entry.Val(intdwarf.DW_AT_go_dict_index).(int64)
+		if !ok {
+			t.Errorf("type parameter %s has no DW_AT_go_dict_index attribute", name)
+		}
+		if _, ok := entry.Val(dwarf.AttrType).(dwarf.Offset); !ok {
+			t.Errorf("type parameter %s has no type", name)
+		}
+		got = append(got, fmt.Sprintf("%s:%d", name, idx))
+	}
+	if want := "K:0 V:1"; strings.Join(got, " ") != want {
+		t.Errorf("got type parameters %q, want %q", strings.Join(got, " "), want)
+	}
+}
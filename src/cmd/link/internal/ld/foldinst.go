@@ -0,0 +1,232 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"cmd/internal/objabi"
+	"cmd/link/internal/loader"
+	"cmd/link/internal/sym"
+)
+
+// The compiler implements the instantiations of a generic function
+// with type arguments of the same underlying type by one shape-based
+// instantiation, such as G[go.shape.int_0], and the symbols of
+// shape-based instantiations compiled into several packages are
+// deduplicated by name. But instantiations with different shapes,
+// such as G[go.shape.int_0] and G[go.shape.int64_0], often compile to
+// the same code.
+//
+// With -foldinst, the linker folds such instantiations: it treats a
+// shape-based instantiation as addressed by its generic function and
+// its content, that is, its code, relocations and function metadata,
+// and redirects all references to instantiations of the same function
+// with the same content to one of them. The others are then
+// unreachable and dropped by deadcode. Folded instantiations share
+// their name in tracebacks and profiles. Instantiations of different
+// generic functions are never folded, even when their code is the
+// same, so that tracebacks and profiles name the right function.
+
+// isShapeInstantiation reports whether s is the text symbol of a
+// shape-based instantiation of a generic function.
+func isShapeInstantiation(ldr *loader.Loader, s loader.Sym) bool {
+	if ldr.SymType(s) != sym.STEXT || !ldr.AttrDuplicateOK(s) {
+		return false
+	}
+	name := ldr.SymName(s)
+	i := strings.IndexByte(name, '[')
+	return i >= 0 && strings.Contains(name[i:], objabi.ShapePrefix)
+}
+
+// genericName returns the name of the generic function of which the
+// symbol named name is an instantiation, that is, name without its
+// type arguments: p.(*T[go.shape.int_0]).M returns p.(*T).M.
+func genericName(name string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// foldInstantiations folds the shape-based instantiations of generic
+// functions with the same content; see above. It must be called
+// before deadcode.
+func (ctxt *Link) foldInstantiations() {
+	if ctxt.DynlinkingGo() || ctxt.BuildMode == BuildModePlugin {
+		// Instantiations may be referenced by other modules.
+		return
+	}
+	ldr := ctxt.loader
+	var cands []loader.Sym
+	for s := loader.Sym(1); s < loader.Sym(ldr.NSym()); s++ {
+		if ldr.IsExternal(s) || !isShapeInstantiation(ldr, s) {
+			continue
+		}
+		cands = append(cands, s)
+	}
+
+	// Folding an instantiation can make the instantiations that
+	// refer to it and to its replacement identical too, so repeat
+	// until nothing more folds.
+	folded := make(map[loader.Sym]loader.Sym)
+	var key []byte
+	for {
+		byKey := make(map[string]loader.Sym)
+		n := len(folded)
+		for _, s := range cands {
+			if _, ok := folded[s]; ok {
+				continue
+			}
+			key = instKey(ctxt, key[:0], s, folded)
+			if t, ok := byKey[string(key)]; ok {
+				folded[s] = t
+			} else {
+				byKey[string(key)] = s
+			}
+		}
+		if len(folded) == n {
+			break
+		}
+	}
+	if len(folded) == 0 {
+		return
+	}
+
+	// Redirect the references to folded instantiations.
+	var saved int64
+	for s, t := range folded {
+		// A replacement may have been folded in a later round.
+		for {
+			u, ok := folded[t]
+			if !ok {
+				break
+			}
+			t = u
+		}
+		folded[s] = t
+		saved += ldr.SymSize(s)
+	}
+	for s := loader.Sym(1); s < loader.Sym(ldr.NSym()); s++ {
+		if _, ok := folded[s]; ok {
+			continue
+		}
+		relocs := ldr.Relocs(s)
+		var su *loader.SymbolBuilder
+		for ri := 0; ri < relocs.Count(); ri++ {
+			t, ok := folded[relocs.At(ri).Sym()]
+			if !ok {
+				continue
+			}
+			if su == nil {
+				su = ldr.MakeSymbolUpdater(s)
+				relocs = su.Relocs()
+			}
+			relocs.At(ri).SetSym(t)
+		}
+	}
+	if ctxt.Debugvlog != 0 {
+		ctxt.Logf("foldinst: folded %d instantiations (%d bytes of code)\n", len(folded), saved)
+	}
+}
+
+// instKey appends to key the generic function and the content of the
+// shape-based instantiation s that two instantiations must share to be
+// folded, and returns the extended buffer. References to instantiations
+// that have already been folded are replaced by references to their
+// replacements.
+func instKey(ctxt *Link, key []byte, s loader.Sym, folded map[loader.Sym]loader.Sym) []byte {
+	ldr := ctxt.loader
+	target := func(t loader.Sym) uint64 {
+		if t == s {
+			return 0 // a recursive call
+		}
+		if u, ok := folded[t]; ok {
+			t = u
+		}
+		return uint64(t)
+	}
+	var buf [binary.MaxVarintLen64]byte
+	num := func(v uint64) {
+		key = append(key, buf[:binary.PutUvarint(buf[:], v)]...)
+	}
+	data := func(t loader.Sym) {
+		p := ldr.Data(t)
+		num(uint64(len(p)))
+		key = append(key, p...)
+		relocs := ldr.Relocs(t)
+		num(uint64(relocs.Count()))
+		for ri := 0; ri < relocs.Count(); ri++ {
+			r := relocs.At(ri)
+			num(uint64(r.Off()))
+			num(uint64(r.Siz()))
+			num(uint64(r.Type()))
+			num(uint64(r.Add()))
+			num(target(r.Sym()))
+		}
+	}
+	str := func(v string) {
+		num(uint64(len(v)))
+		key = append(key, v...)
+	}
+
+	str(genericName(ldr.SymName(s)))
+	num(uint64(ldr.SymVersion(s)))
+	data(s)
+
+	fi := ldr.FuncInfo(s)
+	if !fi.Valid() {
+		return key
+	}
+	fi.Preload()
+	num(uint64(fi.Args()))
+	num(uint64(fi.Locals()))
+	num(uint64(fi.FuncID()))
+	num(uint64(fi.FuncFlag()))
+
+	// File indexes are relative to the file table of the package
+	// that compiled s, so compare the file names.
+	cu := ldr.SymUnit(s)
+	for i, n := 0, int(fi.NumFile()); i < n; i++ {
+		str(cu.FileTable[fi.File(i)])
+	}
+	for i, n := 0, int(fi.NumInlTree()); i < n; i++ {
+		call := fi.InlTree(i)
+		num(uint64(call.Parent))
+		str(cu.FileTable[call.File])
+		num(uint64(call.Line))
+		num(target(call.Func))
+		num(uint64(call.ParentPC))
+	}
+
+	pcsp, pcfile, pcline, pcinline, pcdata := ldr.PcdataAuxs(s, nil)
+	for _, t := range append([]loader.Sym{pcsp, pcfile, pcline, pcinline}, pcdata...) {
+		var p []byte
+		if t != 0 {
+			p = ldr.Data(t)
+		}
+		num(uint64(len(p)))
+		key = append(key, p...)
+	}
+	for _, t := range ldr.Funcdata(s, nil) {
+		if t == 0 {
+			num(0)
+			continue
+		}
+		num(1)
+		data(t)
+	}
+	return key
+}
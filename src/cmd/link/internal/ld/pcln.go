@@ -298,15 +298,32 @@ func (state *pclntab) generateFuncnametab(ctxt *Link, funcs []loader.Sym) map[lo
 		return name[:i], "[...]", name[j+1:]
 	}
 
+	// For generic functions, the null terminated name is followed by
+	// the type argument list with the shape types in readable form,
+	// as in [shape:int] for main.G[go.shape.int_0] (see
+	// objabi.CutShape), also null terminated. Tracebacks print it in
+	// place of the "...".
+	typeArgs := func(name string) string {
+		a, b, c := nameParts(name)
+		if b == "" {
+			return ""
+		}
+		return objabi.ReadableShapeName(name[len(a) : len(name)-len(c)])
+	}
+
 	// Write the null terminated strings.
 	writeFuncNameTab := func(ctxt *Link, s loader.Sym) {
 		symtab := ctxt.loader.MakeSymbolUpdater(s)
 		for s, off := range nameOffsets {
-			a, b, c := nameParts(ctxt.loader.SymName(s))
+			name := ctxt.loader.SymName(s)
+			a, b, c := nameParts(name)
 			o := int64(off)
 			o = symtab.AddStringAt(o, a)
 			o = symtab.AddStringAt(o, b)
-			_ = symtab.AddCStringAt(o, c)
+			o = symtab.AddCStringAt(o, c)
+			if args := typeArgs(name); args != "" {
+				_ = symtab.AddCStringAt(o, args)
+			}
 		}
 	}
 
@@ -314,8 +331,12 @@ func (state *pclntab) generateFuncnametab(ctxt *Link, funcs []loader.Sym) map[lo
 	var size int64
 	walkFuncs(ctxt, funcs, func(s loader.Sym) {
 		nameOffsets[s] = uint32(size)
-		a, b, c := nameParts(ctxt.loader.SymName(s))
+		name := ctxt.loader.SymName(s)
+		a, b, c := nameParts(name)
 		size += int64(len(a) + len(b) + len(c) + 1) // NULL terminate
+		if args := typeArgs(name); args != "" {
+			size += int64(len(args) + 1)
+		}
 	})
 
 	state.funcnametab = state.addGeneratedSym(ctxt, "runtime.funcnametab", size, writeFuncNameTab)
@@ -307,6 +307,11 @@ type extSymPayload struct {
 const (
 	// Loader.flags
 	FlagStrictDups = 1 << iota
+	// NAux and Aux report the aux symbols of external symbols cloned
+	// from object symbols. -foldinst needs them: it clones the symbols
+	// that refer to folded instantiations before deadcode walks their
+	// aux symbols.
+	FlagCloneAuxs
 )
 
 func NewLoader(flags uint32, elfsetstring elfsetstringFunc, reporter *ErrorReporter) *Loader {
@@ -1201,6 +1206,15 @@ func (l *Loader) IsDict(i Sym) bool {
 	return r.Sym(li).IsDict()
 }
 
+// Returns whether this is the text symbol of a cold function.
+func (l *Loader) IsCold(i Sym) bool {
+	if l.IsExternal(i) {
+		return false
+	}
+	r, li := l.toLocal(i)
+	return r.Sym(li).IsCold()
+}
+
 // Return whether this is a trampoline of a deferreturn call.
 func (l *Loader) IsDeferReturnTramp(i Sym) bool {
 	return l.deferReturnTramp[i]
@@ -1601,9 +1615,14 @@ func (l *Loader) SetSymLocalentry(i Sym, value uint8) {
 }
 
 // Returns the number of aux symbols given a global index.
+// External symbols have none, unless FlagCloneAuxs is set and they
+// were cloned from object symbols.
 func (l *Loader) NAux(i Sym) int {
 	if l.IsExternal(i) {
-		return 0
+		if l.flags&FlagCloneAuxs == 0 {
+			return 0
+		}
+		return len(l.getPayload(i).auxs)
 	}
 	r, li := l.toLocal(i)
 	return r.NAux(li)
@@ -1612,7 +1631,11 @@ func (l *Loader) NAux(i Sym) int {
 // Returns the "handle" to the j-th aux symbol of the i-th symbol.
 func (l *Loader) Aux(i Sym, j int) Aux {
 	if l.IsExternal(i) {
-		return Aux{}
+		pp := l.getPayload(i)
+		if l.flags&FlagCloneAuxs == 0 || j >= len(pp.auxs) {
+			return Aux{}
+		}
+		return Aux{&pp.auxs[j], l.objs[pp.objidx].r, l}
 	}
 	r, li := l.toLocal(i)
 	if j >= r.NAux(li) {
@@ -2540,41 +2563,55 @@ func (l *Loader) AssignTextSymbolOrder(libs []*sym.Library, intlibs []bool, exts
 	}
 
 	// Now assemble global textp, and assign text symbols to units.
-	for _, doInternal := range [2]bool{true, false} {
-		for idx, lib := range libs {
-			if intlibs[idx] != doInternal {
-				continue
-			}
-			lists := [2][]sym.LoaderSym{lib.Textp, lib.DupTextSyms}
-			for i, list := range lists {
-				for _, s := range list {
-					sym := Sym(s)
-					if !assignedToUnit.Has(sym) {
-						textp = append(textp, sym)
-						unit := l.SymUnit(sym)
-						if unit != nil {
-							unit.Textp = append(unit.Textp, s)
-							assignedToUnit.Set(sym)
-						}
-						// Dupok symbols may be defined in multiple packages; the
-						// associated package for a dupok sym is chosen sort of
-						// arbitrarily (the first containing package that the linker
-						// loads). Canonicalizes its Pkg to the package with which
-						// it will be laid down in text.
-						if i == 1 /* DupTextSyms2 */ && l.SymPkg(sym) != lib.Pkg {
-							l.SetSymPkg(sym, lib.Pkg)
-						}
-					}
+	// Cold functions go last, in the same order.
+	for _, doCold := range [2]bool{false, true} {
+		for _, doInternal := range [2]bool{true, false} {
+			for idx, lib := range libs {
+				if intlibs[idx] != doInternal {
+					continue
 				}
+				l.assignTextSymbols(lib, doCold, assignedToUnit, &textp)
 			}
-			lib.Textp = nil
-			lib.DupTextSyms = nil
 		}
 	}
+	for _, lib := range libs {
+		lib.Textp = nil
+		lib.DupTextSyms = nil
+	}
 
 	return textp
 }
 
+// assignTextSymbols appends the text symbols of lib that are cold, or
+// not, to textp, and assigns them to their units.
+func (l *Loader) assignTextSymbols(lib *sym.Library, cold bool, assignedToUnit Bitmap, textp *[]Sym) {
+	lists := [2][]sym.LoaderSym{lib.Textp, lib.DupTextSyms}
+	for i, list := range lists {
+		for _, s := range list {
+			sym := Sym(s)
+			if l.IsCold(sym) != cold {
+				continue
+			}
+			if !assignedToUnit.Has(sym) {
+				*textp = append(*textp, sym)
+				unit := l.SymUnit(sym)
+				if unit != nil {
+					unit.Textp = append(unit.Textp, s)
+					assignedToUnit.Set(sym)
+				}
+				// Dupok symbols may be defined in multiple packages; the
+				// associated package for a dupok sym is chosen sort of
+				// arbitrarily (the first containing package that the linker
+				// loads). Canonicalizes its Pkg to the package with which
+				// it will be laid down in text.
+				if i == 1 /* DupTextSyms2 */ && l.SymPkg(sym) != lib.Pkg {
+					l.SetSymPkg(sym, lib.Pkg)
+				}
+			}
+		}
+	}
+}
+
 // ErrorReporter is a helper class for reporting errors.
 type ErrorReporter struct {
 	ldr              *Loader
@@ -1059,3 +1059,166 @@ func TestLargeReloc(t *testing.T) {
 		}
 	}
 }
+
+const testFoldInstSrc = `
+package main
+
+import "fmt"
+
+type Int int64
+
+//go:noinline
+//line gen.go:1
+func Sum[T int | int64 | Int | uint64](s []T) T {
+	var t T
+	for _, v := range s {
+		t += v
+	}
+	return t
+}
+
+// Total has the same code and positions as Sum, but is a different
+// function.
+
+//go:noinline
+//line gen.go:1
+func Total[T int | int64 | Int | uint64](s []T) T {
+	var t T
+	for _, v := range s {
+		t += v
+	}
+	return t
+}
+
+func main() {
+	fmt.Println(Sum([]int{1, 2}), Sum([]int64{3, 4}), Sum([]Int{5, 6}), Sum([]uint64{7, 8}))
+	fmt.Println(Total([]int{1, 2}), Total([]uint64{3, 4}))
+}
+`
+
+func TestFoldInst(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+
+	src := filepath.Join(tmpdir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(testFoldInstSrc), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	// insts returns the shape-based instantiations of Sum and Total
+	// in the binary built with ldflags, and checks that it runs
+	// correctly.
+	insts := func(ldflags string) (sums, totals []string) {
+		exe := filepath.Join(tmpdir, "x.exe")
+		cmd := exec.Command(testenv.GoToolPath(t), "build", "-ldflags="+ldflags, "-o", exe, src)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v: %v:\n%s", cmd.Args, err, out)
+		}
+		out, err := exec.Command(exe).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: %v:\n%s", exe, err, out)
+		}
+		if got, want := string(out), "3 7 11 15\n3 7\n"; got != want {
+			t.Errorf("%s printed %q, want %q", exe, got, want)
+		}
+		out, err = exec.Command(testenv.GoToolPath(t), "tool", "nm", exe).CombinedOutput()
+		if err != nil {
+			t.Fatalf("nm: %v:\n%s", err, out)
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			f := strings.Fields(line)
+			if len(f) != 3 {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(f[2], "main.Sum[go.shape."):
+				sums = append(sums, f[2])
+			case strings.HasPrefix(f[2], "main.Total[go.shape."):
+				totals = append(totals, f[2])
+			}
+		}
+		return sums, totals
+	}
+
+	// int64 and Int share a shape; int and uint64 have their own, but
+	// their code is the same. Total has the same code as Sum, but its
+	// instantiations must not be folded with those of Sum.
+	sums, totals := insts("")
+	if len(sums) != 3 || len(totals) != 2 {
+		t.Errorf("without -foldinst, got instantiations %v and %v, want 3 and 2", sums, totals)
+	}
+	sums, totals = insts("-foldinst")
+	if len(sums) != 1 || len(totals) != 1 {
+		t.Errorf("with -foldinst, got instantiations %v and %v, want 1 and 1", sums, totals)
+	}
+}
+
+const testColdSrc = `
+package main
+
+import "os"
+
+//go:cold
+func fail() {
+	println("fail")
+	os.Exit(1)
+}
+
+func main() {
+	if len(os.Args) > 5 {
+		fail()
+	}
+	hot()
+}
+
+//go:noinline
+func hot() {}
+`
+
+func TestColdFuncLast(t *testing.T) {
+	// Test that the linker places //go:cold functions after all
+	// others.
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+
+	src := filepath.Join(tmpdir, "x.go")
+	if err := ioutil.WriteFile(src, []byte(testColdSrc), 0666); err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(tmpdir, "x.exe")
+	cmd := exec.Command(testenv.GoToolPath(t), "build", "-o", exe, src)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v: %v:\n%s", cmd.Args, err, out)
+	}
+	out, err := exec.Command(testenv.GoToolPath(t), "tool", "nm", "-n", exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("nm: %v:\n%s", err, out)
+	}
+
+	// nm -n lists symbols by address, so fail must come after every
+	// other function of the program.
+	var text []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if f := strings.Fields(line); len(f) == 3 && (f[1] == "T" || f[1] == "t") {
+			text = append(text, f[2])
+		}
+	}
+	last := -1
+	for i, name := range text {
+		if name == "main.fail" {
+			last = i
+		}
+	}
+	if last < 0 {
+		t.Fatalf("main.fail not found in nm output:\n%s", out)
+	}
+	for _, name := range text[last+1:] {
+		if strings.HasPrefix(name, "main.") || strings.HasPrefix(name, "runtime.") && name != "runtime.etext" {
+			t.Errorf("%s placed after cold main.fail", name)
+		}
+	}
+}
@@ -27,6 +27,11 @@
 //
 // The options control the printed output:
 //
+//	-instantiations
+//		instead of the symbols, list the instantiations of generic
+//		functions and methods, grouped by the function or method they
+//		instantiate: the shape-based function bodies and their closures
+//		and wrappers, and the dictionaries, with their sizes in decimal
 //	-n
 //		an alias for -sort address (numeric),
 //		for compatibility with other nm commands
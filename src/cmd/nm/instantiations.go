@@ -0,0 +1,232 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"cmd/internal/objabi"
+	"cmd/internal/objfile"
+)
+
+// The compiler implements the instantiations of a generic function or
+// method by shape-based function bodies, named like main.G[go.shape.int_0]
+// or main.(*L[go.shape.string_0]).Get, and passes each of them a
+// dictionary describing the actual type arguments, named like
+// main..dict.G[int] or main..dict.(*L[string]).Get. Closures, method
+// wrappers and generated equality functions of instantiated types also
+// carry the type arguments in their names.
+//
+// With -instantiations, nm recognizes these symbols by their names and
+// lists them grouped by the generic function or method they
+// instantiate, with their sizes, so that the cost of the instantiations
+// can be audited after the fact.
+
+// dictInfix separates the package path from the function name in the
+// symbol name of a dictionary.
+const dictInfix = "..dict."
+
+// An instGroup is the set of instantiation symbols of one generic
+// function or method.
+type instGroup struct {
+	generic string // name with the type argument lists removed
+	syms    []objfile.Sym
+	size    int64 // total size of syms
+	code    int64 // total size of the function bodies
+	dicts   int64 // total size of the dictionaries
+	nbodies int
+	ndicts  int
+}
+
+func instantiations(file string) {
+	f, err := objfile.Open(file)
+	if err != nil {
+		errorf("%v", err)
+		return
+	}
+	defer f.Close()
+
+	groups := make(map[string]*instGroup)
+	var found bool
+	for _, e := range f.Entries() {
+		syms, err := e.Symbols()
+		if err != nil {
+			errorf("reading %s: %v", file, err)
+		}
+		if len(syms) > 0 {
+			found = true
+		}
+		for _, sym := range syms {
+			if sym.Code == 'U' {
+				continue
+			}
+			generic, isDict, ok := instantiated(sym.Name)
+			if !ok || !isDict && sym.Code != 'T' && sym.Code != 't' {
+				continue
+			}
+			g := groups[generic]
+			if g == nil {
+				g = &instGroup{generic: generic}
+				groups[generic] = g
+			}
+			g.syms = append(g.syms, sym)
+			if isDict {
+				g.dicts += sym.Size
+				g.ndicts++
+			} else {
+				g.code += sym.Size
+				g.nbodies++
+			}
+			g.size += sym.Size
+		}
+	}
+	if !found {
+		errorf("reading %s: no symbols", file)
+		return
+	}
+
+	list := make([]*instGroup, 0, len(groups))
+	for _, g := range groups {
+		list = append(list, g)
+	}
+	switch *sortOrder {
+	case "size":
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].size != list[j].size {
+				return list[i].size > list[j].size
+			}
+			return list[i].generic < list[j].generic
+		})
+	default:
+		sort.Slice(list, func(i, j int) bool { return list[i].generic < list[j].generic })
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	var total, nbodies, ndicts int64
+	for _, g := range list {
+		syms := g.syms
+		switch *sortOrder {
+		case "address":
+			sort.Slice(syms, func(i, j int) bool { return syms[i].Addr < syms[j].Addr })
+		case "name":
+			sort.Slice(syms, func(i, j int) bool { return syms[i].Name < syms[j].Name })
+		case "size":
+			sort.Slice(syms, func(i, j int) bool { return syms[i].Size > syms[j].Size })
+		}
+		if filePrefix {
+			fmt.Fprintf(w, "%s:\t", file)
+		}
+		fmt.Fprintf(w, "%s: %d bytes in %d bodies, %d bytes in %d dictionaries\n", g.generic, g.code, g.nbodies, g.dicts, g.ndicts)
+		for _, sym := range syms {
+			fmt.Fprintf(w, "\t%10d %c %s\n", sym.Size, sym.Code, objabi.ReadableShapeName(sym.Name))
+		}
+		total += g.size
+		nbodies += int64(g.nbodies)
+		ndicts += int64(g.ndicts)
+	}
+	if filePrefix {
+		fmt.Fprintf(w, "%s:\t", file)
+	}
+	fmt.Fprintf(w, "total: %d bytes in %d generic functions (%d bodies, %d dictionaries)\n", total, len(list), nbodies, ndicts)
+	w.Flush()
+}
+
+// instantiated reports whether the symbol name belongs to an
+// instantiation of a generic function or method. If so, it returns
+// the name of the generic function or method, and whether the symbol
+// is a dictionary.
+func instantiated(name string) (generic string, isDict bool, ok bool) {
+	if i := strings.Index(name, dictInfix); i >= 0 {
+		name = name[:i] + "." + name[i+len(dictInfix):]
+		isDict = true
+	}
+	generic, ok = stripTypeArgs(name)
+	return generic, isDict, ok
+}
+
+// stripTypeArgs returns name with the type argument lists removed, as
+// in main.(*L).Get for main.(*L[go.shape.string_0]).Get, and whether
+// name contained any. A type argument list is a bracketed list that
+// directly follows an identifier other than map; arrays, slices and map
+// types in other contexts are left alone.
+func stripTypeArgs(name string) (string, bool) {
+	var b strings.Builder
+	found := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c == '"' {
+			j := skipQuoted(name, i)
+			b.WriteString(name[i:j])
+			i = j - 1
+			continue
+		}
+		if c != '[' || !followsTypeName(name[:i]) {
+			b.WriteByte(c)
+			continue
+		}
+		end := closingBracket(name, i)
+		if end < 0 {
+			return name, false
+		}
+		found = true
+		i = end
+	}
+	if !found {
+		return name, false
+	}
+	return b.String(), true
+}
+
+// followsTypeName reports whether s ends in an identifier other than
+// the keyword map.
+func followsTypeName(s string) bool {
+	i := len(s)
+	for i > 0 && isIdentByte(s[i-1]) {
+		i--
+	}
+	return i < len(s) && s[i:] != "map"
+}
+
+func isIdentByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c >= 0x80
+}
+
+// closingBracket returns the index of the ']' matching the '[' at
+// name[open], or -1 if there is none.
+func closingBracket(name string, open int) int {
+	depth := 0
+	for i := open; i < len(name); i++ {
+		switch name[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '"':
+			i = skipQuoted(name, i) - 1
+		}
+	}
+	return -1
+}
+
+// skipQuoted returns the index just past the quoted string, such as a
+// struct tag, starting at name[open].
+func skipQuoted(name string, open int) int {
+	for i := open + 1; i < len(name); i++ {
+		switch name[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(name)
+}
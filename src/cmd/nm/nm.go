@@ -16,6 +16,9 @@ import (
 )
 
 const helpText = `usage: go tool nm [options] file...
+  -instantiations
+      list the instantiations of generic functions and methods,
+      grouped by the function or method they instantiate
   -n
       an alias for -sort address (numeric),
       for compatibility with other nm commands
@@ -35,6 +38,7 @@ func usage() {
 
 var (
 	sortOrder = flag.String("sort", "name", "")
+	listInsts = flag.Bool("instantiations", false, "")
 	printSize = flag.Bool("size", false, "")
 	printType = flag.Bool("type", false, "")
 
@@ -85,7 +89,11 @@ func main() {
 	}
 
 	for _, file := range args {
-		nm(file)
+		if *listInsts {
+			instantiations(file)
+		} else {
+			nm(file)
+		}
 	}
 
 	os.Exit(exitCode)
@@ -375,3 +375,95 @@ var Testdata uint32
 
 func Testfunc() {}
 `
+
+func TestInstantiated(t *testing.T) {
+	tests := []struct {
+		name    string
+		generic string
+		isDict  bool
+		ok      bool
+	}{
+		{"main.G[go.shape.int_0]", "main.G", false, true},
+		{"main.(*L[go.shape.string_0]).Get", "main.(*L).Get", false, true},
+		{"main..dict.(*L[string]).Get", "main.(*L).Get", true, true},
+		{"main..dict.G[map[string][]int,[2]int]", "main.G", true, true},
+		{`main.G[go.shape.struct { X int "json:\"[\"" }_0].func1`, "main.G.func1", false, true},
+		{"example.com/p.Map[go.shape.int_0,go.shape.string_1]", "example.com/p.Map", false, true},
+		{"main.f", "main.f", false, false},
+		{"type..eq.[2]runtime.Frame", "type..eq.[2]runtime.Frame", false, false},
+		{"runtime.f(map[string]int)", "runtime.f(map[string]int)", false, false},
+	}
+	for _, test := range tests {
+		generic, isDict, ok := instantiated(test.name)
+		if generic != test.generic || isDict != test.isDict || ok != test.ok {
+			t.Errorf("instantiated(%q) = %q, %v, %v; want %q, %v, %v", test.name, generic, isDict, ok, test.generic, test.isDict, test.ok)
+		}
+	}
+}
+
+func TestInstantiations(t *testing.T) {
+	t.Parallel()
+	tmpdir, err := os.MkdirTemp("", "TestInstantiations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	src := filepath.Join(tmpdir, "a.go")
+	if err := os.WriteFile(src, []byte(testinst), 0666); err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(tmpdir, "a.exe")
+	out, err := exec.Command(testenv.GoToolPath(t), "build", "-gcflags=-l", "-o", exe, src).CombinedOutput()
+	if err != nil {
+		t.Fatalf("building test executable failed: %s %s", err, out)
+	}
+
+	out, err = exec.Command(testnmpath, "-instantiations", exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go tool nm -instantiations: %v\n%s", err, string(out))
+	}
+	want := []string{
+		"main.(*List).Push: ",
+		" T main.(*List[shape:int]).Push\n",
+		" T main.(*List[shape:string]).Push\n",
+		" R main..dict.(*List[int]).Push\n",
+		" R main..dict.(*List[string]).Push\n",
+		"main.Map: ",
+		" R main..dict.Map[int,string]\n",
+		" T main.Map[shape:int,shape:string]\n",
+		"total: ",
+	}
+	got := string(out)
+	for _, w := range want {
+		i := strings.Index(got, w)
+		if i < 0 {
+			t.Fatalf("missing %q in output, or out of order:\n%s", w, out)
+		}
+		got = got[i+len(w):]
+	}
+}
+
+const testinst = `
+package main
+
+type List[T any] struct{ items []T }
+
+func (l *List[T]) Push(x T) { l.items = append(l.items, x) }
+
+func Map[T, U any](s []T, f func(T) U) []U {
+	var r []U
+	for _, x := range s {
+		r = append(r, f(x))
+	}
+	return r
+}
+
+func main() {
+	var a List[int]
+	a.Push(1)
+	var b List[string]
+	b.Push("x")
+	println(len(Map([]int{1}, func(i int) string { return "" })), len(a.items), len(b.items))
+}
+`
@@ -167,6 +167,8 @@ func TestImportTypeparamTests(t *testing.T) {
 	skip := map[string]string{
 		"equal.go":  "inconsistent embedded sorting", // TODO(rfindley): investigate this.
 		"nested.go": "fails to compile",              // TODO(rfindley): investigate this.
+
+		"genericmethods.go": "requires GOEXPERIMENT=genericmethods",
 	}
 
 	for _, entry := range list {
@@ -89,4 +89,21 @@ type Flags struct {
 	// Details regarding the new pacer may be found at
 	// https://golang.org/design/44167-gc-pacer-redesign
 	PacerRedesign bool
+
+	// GenericMethods enables methods with type parameters of their
+	// own on defined (non-interface) types. Such methods are not
+	// part of the method set of their receiver type: they do not
+	// satisfy interfaces and are not visible to reflection.
+	GenericMethods bool
+
+	// SSAHooks enables the analysis hooks registered with the
+	// compiler's SSA backend (see ssa.RegisterAnalysisHook), which
+	// observe functions after selected phases and report
+	// diagnostics.
+	SSAHooks bool
+
+	// Vectorize enables the compiler's vectorize pass, which makes
+	// simple loops over slices of integers use the vector registers
+	// on amd64 and arm64.
+	Vectorize bool
 }
@@ -404,9 +404,9 @@ func gentraceback(pc0, sp0, lr0 uintptr, gp *g, skip int, pcbuf *uintptr, max in
 					inlFunc.funcID = inltree[ix].funcID
 
 					if (flags&_TraceRuntimeFrames) != 0 || showframe(inlFuncInfo, gp, nprint == 0, inlFuncInfo.funcID, lastFuncID) {
-						name := funcname(inlFuncInfo)
 						file, line := funcline(f, tracepc)
-						print(name, "(...)\n")
+						printFuncName(inlFuncInfo, nil)
+						print("(...)\n")
 						print("\t", file, ":", line, "\n")
 						nprint++
 					}
@@ -420,13 +420,14 @@ func gentraceback(pc0, sp0, lr0 uintptr, gp *g, skip int, pcbuf *uintptr, max in
 				//	main(0x1, 0x2, 0x3)
 				//		/home/rsc/go/src/runtime/x.go:23 +0xf
 				//
-				name := funcname(f)
 				file, line := funcline(f, tracepc)
-				if name == "runtime.gopanic" {
-					name = "panic"
-				}
-				print(name, "(")
 				argp := unsafe.Pointer(frame.argp)
+				if funcname(f) == "runtime.gopanic" {
+					print("panic")
+				} else {
+					printFuncName(f, argp)
+				}
+				print("(")
 				printArgs(f, argp, tracepc)
 				print(")\n")
 				print("\t", file, ":", line)
@@ -551,12 +552,13 @@ func printArgs(f funcInfo, argp unsafe.Pointer, pc uintptr) {
 		_endAgg         = 0xfd
 		_dotdotdot      = 0xfc
 		_offsetTooLarge = 0xfb
+		_dictArg        = 0xfa
 	)
 
 	const (
-		limit    = 10                       // print no more than 10 args/components
-		maxDepth = 5                        // no more than 5 layers of nesting
-		maxLen   = (maxDepth*3+2)*limit + 1 // max length of _FUNCDATA_ArgInfo (see the compiler side for reasoning)
+		limit    = 10                           // print no more than 10 args/components
+		maxDepth = 5                            // no more than 5 layers of nesting
+		maxLen   = (maxDepth*3+2)*limit + 1 + 2 // max length of _FUNCDATA_ArgInfo (see the compiler side for reasoning)
 	)
 
 	p := (*[maxLen]uint8)(funcdata(f, _FUNCDATA_ArgInfo))
@@ -606,6 +608,9 @@ func printArgs(f funcInfo, argp unsafe.Pointer, pc uintptr) {
 		}
 	}
 	pi := 0
+	if p[0] == _dictArg {
+		pi = 2 // see frameDict
+	}
 	slotIdx := uint8(0) // register arg spill slot index
 printloop:
 	for {
@@ -640,6 +645,145 @@ printloop:
 	}
 }
 
+// printFuncName prints the name of the function f whose frame has
+// argument pointer argp, which is nil if there is no frame. If f is a
+// shape-based instantiation of a generic function, it prints the names
+// of the type arguments of the call, as in pkg.F[main.MyInt], which it
+// finds in the dictionary passed to f, or, if it cannot find them, the
+// shapes of the type arguments, as in pkg.F[shape:int].
+func printFuncName(f funcInfo, argp unsafe.Pointer) {
+	before, list, after := funcNamePieces(f)
+	if list == "" {
+		print(before)
+		return
+	}
+	lo, hi, n := typeArgList(list)
+	var dict uintptr
+	if argp != nil {
+		dict = frameDict(f, argp)
+	}
+	if n == 0 || typesModule(dict, uintptr(n)*goarch.PtrSize) == nil {
+		print(before, list, after)
+		return
+	}
+	// The dictionary starts with the runtime types of the type
+	// arguments. Check that they look like types before printing
+	// their names, in case the frame has no valid dictionary.
+	for i := 0; i < n; i++ {
+		t := *(*uintptr)(unsafe.Pointer(dict + uintptr(i)*goarch.PtrSize))
+		md := typesModule(t, unsafe.Sizeof(_type{}))
+		if md == nil || uintptr((*_type)(unsafe.Pointer(t)).str) >= md.etypes-md.types {
+			print(before, list, after)
+			return
+		}
+	}
+	print(before, list[:lo+1])
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			print(",")
+		}
+		t := *(**_type)(unsafe.Pointer(dict + uintptr(i)*goarch.PtrSize))
+		print(t.string())
+	}
+	print(list[hi:], after)
+}
+
+// funcNamePieces returns the name of f in three pieces: for a
+// shape-based instantiation of a generic function, the text before its
+// type argument list, the list, with the shapes of the type arguments
+// in readable form, as in [shape:int,shape:string], and the text after
+// it. For other functions, before is the name of f, and list and after
+// are empty. The function name table, and so funcname, elides the list,
+// as in pkg.F[...]; the linker writes the list after the name (see
+// cmd/link/internal/ld.generateFuncnametab).
+func funcNamePieces(f funcInfo) (before, list, after string) {
+	name := funcname(f)
+	const elided = "[...]"
+	for i := 0; i+len(elided) <= len(name); i++ {
+		if name[i:i+len(elided)] == elided {
+			list = gostringnocopy((*byte)(add(unsafe.Pointer(cfuncname(f)), uintptr(len(name)+1))))
+			if list == "" || list[0] != '[' {
+				break
+			}
+			return name[:i], list, name[i+len(elided):]
+		}
+	}
+	return name, "", ""
+}
+
+// frameDict returns the dictionary passed to the shape-based
+// instantiation f, whose frame has argument pointer argp, or 0 if f is
+// not one or its dictionary is not in its frame. The compiler records
+// the dictionary's offset at the start of _FUNCDATA_ArgInfo; see
+// cmd/compile/internal/ssagen.EmitArgInfo.
+func frameDict(f funcInfo, argp unsafe.Pointer) uintptr {
+	const _dictArg = 0xfa // needs to be in sync with the compiler and printArgs
+
+	p := (*[2]uint8)(funcdata(f, _FUNCDATA_ArgInfo))
+	if p == nil || p[0] != _dictArg {
+		return 0
+	}
+	return *(*uintptr)(add(argp, uintptr(p[1])))
+}
+
+// typeArgList returns the indexes of the brackets around the type
+// argument list in name, as in [int,string], pkg.F[int,string] or
+// pkg.(*T[int]).M, and the number of type arguments. It returns n == 0
+// if name has no type argument list.
+func typeArgList(name string) (lo, hi, n int) {
+	lo = -1
+	for i := 0; i < len(name); i++ {
+		if name[i] == '[' {
+			lo = i
+			break
+		}
+	}
+	if lo < 0 {
+		return 0, 0, 0
+	}
+	depth := 0
+	n = 1
+	for i := lo + 1; i < len(name); i++ {
+		switch name[i] {
+		case '[', '(', '{':
+			depth++
+		case ')', '}':
+			depth--
+		case ']':
+			if depth == 0 {
+				return lo, i, n
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				n++
+			}
+		case '"':
+			// A struct tag in a shape name.
+			for i++; i < len(name) && name[i] != '"'; i++ {
+				if name[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return 0, 0, 0
+}
+
+// typesModule returns the module whose type data contains the n bytes
+// at the pointer-aligned address p, or nil if there is none.
+func typesModule(p, n uintptr) *moduledata {
+	if p == 0 || p%goarch.PtrSize != 0 {
+		return nil
+	}
+	for datap := &firstmoduledata; datap != nil; datap = datap.next {
+		if datap.types <= p && p < datap.etypes && n <= datap.etypes-p {
+			return datap
+		}
+	}
+	return nil
+}
+
 // reflectMethodValue is a partial duplicate of reflect.makeFuncImpl
 // and reflect.methodValue.
 type reflectMethodValue struct {
@@ -751,7 +895,9 @@ func printcreatedby(gp *g) {
 }
 
 func printcreatedby1(f funcInfo, pc uintptr) {
-	print("created by ", funcname(f), "\n")
+	print("created by ")
+	printFuncName(f, nil)
+	print("\n")
 	tracepc := pc // back up to CALL instruction for funcline.
 	if pc > f.entry() {
 		tracepc -= sys.PCQuantum
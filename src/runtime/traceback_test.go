@@ -421,3 +421,73 @@ func testTracebackArgs11b(a, b, c, d int32) int {
 func poisonStack() [20]int {
 	return [20]int{-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}
 }
+
+// The functions below use their dictionaries after the calls, so that
+// the dictionaries are in their frames, where tracebacks read them.
+
+var tracebackShapeSink any
+
+type tracebackShapeT[E any] struct{ e E }
+
+//go:noinline
+func (l *tracebackShapeT[E]) stack() string {
+	n := runtime.Stack(testTracebackArgsBuf[:], false)
+	tracebackShapeSink = l.e
+	return string(testTracebackArgsBuf[:n])
+}
+
+//go:noinline
+func tracebackShapeF[X any](x X) string {
+	s := (&tracebackShapeT[X]{x}).stack()
+	tracebackShapeSink = x
+	return s
+}
+
+//go:noinline
+func tracebackShapeName[X any](x X) string {
+	pc, _, _, _ := runtime.Caller(0)
+	return runtime.FuncForPC(pc).Name()
+}
+
+type tracebackShapeInt int
+
+//go:noinline
+func tracebackShapeG[K comparable, V any](k K, v V) string {
+	s := tracebackShapeF(map[K]V{k: v})
+	tracebackShapeSink = k
+	return s
+}
+
+func TestTracebackShapeNames(t *testing.T) {
+	// The traceback shows the type arguments of each call, which it
+	// finds in the dictionaries, instead of the shapes by which the
+	// instantiations are named.
+	got := tracebackShapeF(tracebackShapeInt(1))
+	for _, want := range []string{
+		"runtime_test.(*tracebackShapeT[runtime_test.tracebackShapeInt]).stack(",
+		"runtime_test.tracebackShapeF[runtime_test.tracebackShapeInt](",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("traceback does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "go.shape.") || strings.Contains(got, "shape:") {
+		t.Errorf("traceback contains shape names:\n%s", got)
+	}
+
+	got = tracebackShapeG("a", []int{1})
+	for _, want := range []string{
+		"runtime_test.(*tracebackShapeT[map[string][]int]).stack(",
+		"runtime_test.tracebackShapeF[map[string][]int](",
+		"runtime_test.tracebackShapeG[string,[]int](",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("traceback does not contain %q:\n%s", want, got)
+		}
+	}
+
+	// Outside tracebacks, the type argument list is elided.
+	if got, want := tracebackShapeName(1), "runtime_test.tracebackShapeName[...]"; got != want {
+		t.Errorf("FuncForPC name is %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+// +build amd64,!gcflags_noopt
+// errorcheck -0 -d=bce
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test the reasons -d=bce gives for keeping or removing bounds checks.
+
+package p
+
+func sum(a []int) int {
+	s := 0
+	for i := 0; i < len(a); i++ {
+		s += a[i] // ERROR "index bounds check removed: induction variable proved in range$"
+	}
+	return s
+}
+
+func upto(a []int, n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += a[i] // ERROR "index bounds check kept: induction variable not provably < len$"
+	}
+	return s
+}
+
+func pick(a []int, i int) int {
+	return a[i] // ERROR "index bounds check kept: index not provably < len$"
+}
+
+func third(a []int) int {
+	return a[3] // ERROR "index bounds check kept: len unknown$"
+}
+
+func guard(a []int, i int) int {
+	if i >= 0 && i < len(a) {
+		return a[i] // ERROR "index bounds check removed: proved in range by dominating conditions$"
+	}
+	return 0
+}
+
+func twice(a []int, i int) int {
+	x := a[i] // ERROR "index bounds check kept: index not provably < len$"
+	y := a[i] // ERROR "index bounds check removed: same as another check$"
+	return x + y
+}
+
+func masked(a *[8]int, i int) int {
+	return a[i&7] // ERROR "index bounds check removed: known in range before SSA$"
+}
+
+func constant(a []int) int {
+	b := a[:4:4] // ERROR "slice bounds check kept: len unknown$" "slice bounds check removed: constant index in range$"
+	return b[2] // ERROR "index bounds check removed: constant index in range$"
+}
+
+func slice(a []int, i, j int) []int {
+	return a[i:j] // ERROR "slice bounds check kept: slice index not provably <= len or cap$"
+}
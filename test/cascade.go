@@ -0,0 +1,49 @@
+// errorcheck -G=3
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that errors involving a type whose declaration leaves it
+// without an underlying type are left out, and that the compiler says
+// how many. Compare fixedbugs/issue5172.go.
+
+package p
+
+type T Undefined // ERROR "undefined: Undefined|3 more errors involving T not shown"
+
+type R [-1]int // ERROR "invalid array length|1 more error involving R not shown"
+
+// A struct with a field in error is still a struct.
+type U struct {
+	a int
+	b Undefined // ERROR "undefined: Undefined"
+}
+
+type S struct {
+	T
+	c int
+}
+
+// Errors in type declarations are always reported.
+type M struct{ m map[M]int } // ERROR "invalid map key"
+type _ map[M]int             // ERROR "invalid map key"
+
+func f() {
+	var t T
+	_ = t.d
+	var x int = t
+	_ = x
+	_ = []T{t}[0].e
+
+	var r R
+	_ = r[0]
+
+	var u U
+	_ = u.a
+	_ = u.d // ERROR "u.d undefined"
+
+	var s S
+	_ = s.c
+	_ = s.d // ERROR "s.d undefined"
+}
@@ -0,0 +1,42 @@
+// +build amd64,!gcflags_noopt
+// errorcheck -0 -d=nilcheckreport,wbreport
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test the reasons -d=nilcheckreport and -d=wbreport give for keeping
+// nil checks and write barriers.
+
+package p
+
+type T struct {
+	p *int
+	x int
+}
+
+var g *T
+
+func load(t *T) int {
+	return t.x // folded into the load, so not reported
+}
+
+func param(t *T, q *int) {
+	t.p = q // ERROR "nil check kept: pointer is a parameter$" "write barrier for store kept: destination is a parameter$"
+}
+
+func global() {
+	g.p = nil // ERROR "nil check kept: pointer is loaded from memory$" "write barrier for store kept: destination is loaded from memory$"
+}
+
+func fresh(q *int) *T {
+	t := new(T)
+	t.p = q // ERROR "write barrier for store kept: destination is a new object, but the stored value may be a heap pointer$"
+	return t
+}
+
+func local(q *int) int {
+	var t T
+	t.p = q
+	return *t.p
+}
@@ -0,0 +1,49 @@
+// asmcheck
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package codegen
+
+// Test that //go:likely and //go:unlikely choose which side of an if
+// statement falls through.
+
+//go:noinline
+func dec(x int) int { return x - 1 }
+
+//go:noinline
+func inc(x int) int { return x + 1 }
+
+func likelyThen(x int) int {
+	//go:likely
+	if x == 0 { // amd64:"JNE"
+		x = inc(x)
+	} else {
+		x = dec(x)
+	}
+	return x * 3
+}
+
+func unlikelyThen(x int) int {
+	//go:unlikely
+	if x == 0 { // amd64:"JEQ"
+		x = inc(x)
+	} else {
+		x = dec(x)
+	}
+	return x * 3
+}
+
+//go:cold
+func fail(x int) {
+	panic(x)
+}
+
+func coldCall(x int) int {
+	// Without //go:cold, the call of fail would be the likely branch.
+	if x < 0 { // amd64:"JLT"
+		fail(x)
+	}
+	return x * 3
+}
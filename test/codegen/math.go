@@ -227,6 +227,21 @@ func constantConvertInt32(x uint32) uint32 {
 	return x
 }
 
+// Test that calls of pure math functions with constant arguments
+// are evaluated at compile-time
+
+func constantFloor() float64 {
+	// amd64:-".*x86HasSSE41",-"ROUNDSD"
+	// arm64:-"FRINTMD"
+	return math.Floor(2.5)
+}
+
+func constantFMA() float64 {
+	// amd64:-".*x86HasFMA",-"VFMADD231SD"
+	// arm64:-"FMADDD"
+	return math.FMA(2, 3, 4)
+}
+
 func nanGenerate64() float64 {
 	// Test to make sure we don't generate a NaN while constant propagating.
 	// See issue 36400.
@@ -751,3 +751,13 @@ func Div64degenerate(x uint64) (q, r uint64) {
 	// amd64:-"DIVQ"
 	return bits.Div64(0, x, 5)
 }
+
+// ------------------------- //
+//    bits.* of constants    //
+// ------------------------- //
+
+func ConstantBits() int {
+	// amd64:-".*x86HasPOPCNT",-"POPCNTQ",-"BSRQ"
+	// arm64:-"VCNT",-"CLZ"
+	return bits.OnesCount64(0xff) + bits.Len64(1000) + bits.TrailingZeros32(8)
+}
@@ -14,6 +14,11 @@ func CountRunes(s string) int { // Issue #24923
 	return len([]rune(s))
 }
 
+func CountConstRunes() int {
+	// amd64:-`.*countrunes`
+	return len([]rune("héllo"))
+}
+
 func ToByteSlice() []byte { // Issue #24698
 	// amd64:`LEAQ\ttype\.\[3\]uint8`
 	// amd64:`CALL\truntime\.newobject`
@@ -0,0 +1,21 @@
+// errorcheck -0 -m=2
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that //go:cold functions are not inlined.
+
+package p
+
+//go:cold
+func fail(s string) { // ERROR "cannot inline fail: marked go:cold" "s does not escape"
+	println(s)
+}
+
+func f(x int) int { // ERROR "can inline f with cost"
+	if x < 0 {
+		fail("negative")
+	}
+	return x
+}
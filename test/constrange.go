@@ -0,0 +1,22 @@
+// errorcheck
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that errors about constants a numeric type cannot represent
+// give the range of the type and, for floating-point types, the value
+// nearest the constant.
+
+package p
+
+var x int8
+
+var (
+	_ int8    = 300            // ERROR "overflows.*valid range is -128 to 127"
+	_         = uint16(70000)  // ERROR "valid range is 0 to 65535"
+	_         = x + 300        // ERROR "overflows.*valid range is -128 to 127"
+	_ float32 = 1e100          // ERROR "valid range is -3.4028235e\+38 to 3.4028235e\+38, nearest value is 3.4028235e\+38"
+	_         = float32(-1e50) // ERROR "nearest value is -3.4028235e\+38"
+	_ int     = 1.5            // ERROR "nearest value is 2|truncated to integer"
+)
@@ -0,0 +1,36 @@
+// errorcheck -0 -d=escreport
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test the chains of flows that -d=escreport gives for the values
+// that escape to the heap.
+
+package p
+
+var sink interface{}
+
+type T struct{ p *int }
+
+func ret() *int {
+	x := 1 // ERROR "x escapes to heap: &x flows to ~r0 via address-of at .*:18, return at .*:18$"
+	return &x
+}
+
+func iface(n int) {
+	s := make([]byte, n) // ERROR "s escapes to heap: &s flows to {heap} via address-of at .*:23, interface-converted at .*:23, assign at .*:23$" "make\(\[\]byte, n\) escapes to heap: &{storage for make\(\[\]byte, n\)} flows to {heap} via non-constant size at .*:22$"
+	sink = &s
+}
+
+func chain() *T {
+	z := 2     // ERROR "z escapes to heap: &z flows to t via address-of at .*:28, struct literal element at .*:28, assign at .*:28; &t flows to ~r0 via address-of at .*:29, return at .*:29$"
+	t := T{&z} // ERROR "t escapes to heap: &t flows to ~r0 via address-of at .*:29, return at .*:29$"
+	return &t
+}
+
+func local() int {
+	y := 3
+	p := &y
+	return *p
+}
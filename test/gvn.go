@@ -0,0 +1,50 @@
+// errorcheck -0 -d=gvn
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that global value numbering reuses loads across stores that
+// cannot change the loaded memory, and not across stores that can.
+
+package p
+
+type T struct{ a, b int }
+
+func f(p *T) int {
+	x := p.a
+	p.b = 1
+	return x + p.a // ERROR "gvn eliminated Load, same as line 15"
+}
+
+func g(p *T, i int) int {
+	x := p.a
+	if i > 0 {
+		var t T
+		t.a = i
+		p.b = t.a
+		return p.a + x // ERROR "gvn eliminated Load, same as line 21"
+	}
+	return x
+}
+
+func h(p, q *T) int {
+	x := p.a
+	q.b = 1 // may be p.a
+	return x + p.a
+}
+
+func k(p *T, s []int) int {
+	x := p.a
+	s[0] = 1 // may be p.a
+	return x + p.a
+}
+
+func loop(p *T, n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += p.a // the loop's memory phi stops gvn
+		p.b = i
+	}
+	return s + p.a
+}
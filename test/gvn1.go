@@ -0,0 +1,72 @@
+// run
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that global value numbering does not reuse loads across stores
+// that may change the loaded memory.
+
+package main
+
+import "unsafe"
+
+type T struct{ a, b int }
+
+//go:noinline
+func alias(p, q *T) int {
+	x := p.a
+	q.b = 5
+	return x + p.a
+}
+
+//go:noinline
+func slice(p *T, s []int) int {
+	x := p.a
+	s[0] = 7
+	return x + p.a
+}
+
+//go:noinline
+func loop(p *T, n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += p.a
+		p.a = i
+	}
+	return s + p.a
+}
+
+//go:noinline
+func local(p *T) int {
+	var t T
+	t.a = p.a
+	x := t.a
+	p.a++
+	return x + t.a + p.a
+}
+
+func main() {
+	var arr [3]int
+	p := (*T)(unsafe.Pointer(&arr[1]))
+	q := (*T)(unsafe.Pointer(&arr[0])) // q.b is p.a
+	p.a = 1
+	if got := alias(p, q); got != 6 {
+		panic(got)
+	}
+
+	p = &T{a: 1}
+	if got := slice(p, (*[1]int)(unsafe.Pointer(&p.a))[:]); got != 8 {
+		panic(got)
+	}
+
+	p = &T{a: 10}
+	if got := loop(p, 4); got != 10+0+1+2+3 {
+		panic(got)
+	}
+
+	p = &T{a: 3}
+	if got := local(p); got != 3+3+4 {
+		panic(got)
+	}
+}
@@ -0,0 +1,29 @@
+// errorcheck -0 -m=2
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that converting values larger than four words to interfaces
+// makes a function costlier to inline.
+
+package p
+
+type small struct{ a, b int }
+
+type large struct{ a, b, c, d, e int }
+
+func isSmall(x small) bool { // ERROR "can inline isSmall with cost 10 as:"
+	var i interface{} = x // ERROR "x does not escape"
+	return i == nil
+}
+
+func isLarge(x large) bool { // ERROR "can inline isLarge with cost 20 as:"
+	var i interface{} = x // ERROR "x does not escape"
+	return i == nil
+}
+
+func isPointer(x *large) bool { // ERROR "can inline isPointer with cost 10 as:" "x does not escape"
+	var i interface{} = x
+	return i == nil
+}
@@ -0,0 +1,49 @@
+// errorcheck -0 -m
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that //go:inlinebudget sets the inlining budget of a function,
+// and that -m reports when it has no effect.
+
+package foo
+
+//go:noinline
+func g(x int) int { return x }
+
+//go:inlinebudget 160
+func f(x, y int) int { // ERROR "can inline f"
+	x = g(x)
+	return x*y + x/y + x%y + x<<3 + y>>2
+}
+
+func callF(x int) int {
+	return f(x, 2) // ERROR "inlining call to f"
+}
+
+//go:inlinebudget 160
+func small(x int) int { // ERROR "can inline small" "//go:inlinebudget has no effect: cost 4 is within the default budget 80"
+	return x + 1
+}
+
+//go:inlinebudget 100
+func tooBig(x, y int) int { // ERROR "//go:inlinebudget has no effect: too expensive"
+	x = g(x)
+	y = g(y)
+	return x + y
+}
+
+//go:inlinebudget 160
+func hasDefer(x int) (r int) { // ERROR "//go:inlinebudget has no effect: unhandled op DEFER"
+	defer func() { r++ }() // ERROR "can inline hasDefer.func1" "func literal does not escape"
+	return x
+}
+
+// A lowered budget keeps lowered from being inlined.
+//
+//go:inlinebudget 60
+func lowered(x, y int) int {
+	x = g(x)
+	return x*y + x/y + x%y
+}
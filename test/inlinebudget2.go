@@ -0,0 +1,16 @@
+// errorcheck
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that //go:inlinebudget directives without a positive budget
+// are diagnosed while parsing.
+
+package p
+
+//go:inlinebudget // ERROR "usage: //go:inlinebudget n"
+func f1() {}
+
+//go:inlinebudget 0 // ERROR "usage: //go:inlinebudget n"
+func f2() {}
@@ -0,0 +1,21 @@
+// errorcheck
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that //go:inlinebudget directives that are too large, or
+// conflict with other directives, are diagnosed.
+
+package p
+
+//go:inlinebudget 1000
+func f1() {} // ERROR "//go:inlinebudget 1000 exceeds the maximum of 320"
+
+//go:noinline
+//go:inlinebudget 100
+func f2() {} // ERROR "go:inlinebudget cannot be combined with go:noinline or go:cold"
+
+//go:inlinebudget 100
+//go:cold
+func f3() {} // ERROR "go:inlinebudget cannot be combined with go:noinline or go:cold"
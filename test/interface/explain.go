@@ -0,0 +1,47 @@
+// errorcheck
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that the compiler explains why a type does not implement
+// an interface.
+// Does not compile.
+
+package p
+
+type I interface {
+	M(int) string
+	N()
+}
+
+type T struct{}
+
+func (T) M(string) string { return "" }
+func (T) N()              {}
+
+type P struct{}
+
+func (*P) M(int) string { return "" }
+func (*P) N()           {}
+
+type Q struct{}
+
+func (Q) M(int) string { return "" }
+
+type R struct{}
+
+func (R) M(int) (string, error) { return "", nil }
+func (R) N()                    {}
+
+var (
+	_ I = T{} // ERROR "T does not implement I \(wrong type for M method\)\n\t\thave M\(string\) string\n\t\twant M\(int\) string\n\t\tparameter 0: string vs int"
+	_ I = P{} // ERROR "P does not implement I \(M method has pointer receiver\)"
+	_ I = Q{} // ERROR "Q does not implement I \(missing N method\)"
+	_ I = R{} // ERROR "R does not implement I \(wrong type for M method\)\n\t\thave M\(int\) \(string, error\)\n\t\twant M\(int\) string\n\t\tresult count 2 vs 1"
+	_ I = &P{}
+)
+
+func f(i I) {
+	_ = i.(P) // ERROR "impossible type assertion: i.\(P\)\n\tP does not implement I \(M method has pointer receiver\)"
+}
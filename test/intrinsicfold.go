@@ -0,0 +1,80 @@
+// run
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that calls of pure intrinsics with constant arguments, which
+// the compiler evaluates, give the same results as at run time.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+//go:noinline
+func f(x float64) float64 { return x }
+
+//go:noinline
+func u(x uint64) uint64 { return x }
+
+func checkF(name string, folded, run float64) {
+	if math.Float64bits(folded) != math.Float64bits(run) {
+		panic(fmt.Sprintf("%s: folded %v, run %v", name, folded, run))
+	}
+}
+
+func checkI(name string, folded, run uint64) {
+	if folded != run {
+		panic(fmt.Sprintf("%s: folded %#x, run %#x", name, folded, run))
+	}
+}
+
+func main() {
+	checkF("Sqrt", math.Sqrt(2), math.Sqrt(f(2)))
+	checkF("Floor", math.Floor(-2.5), math.Floor(f(-2.5)))
+	checkF("Ceil", math.Ceil(-0.5), math.Ceil(f(-0.5)))
+	checkF("Trunc", math.Trunc(-2.5), math.Trunc(f(-2.5)))
+	checkF("Round", math.Round(2.5), math.Round(f(2.5)))
+	checkF("RoundToEven", math.RoundToEven(2.5), math.RoundToEven(f(2.5)))
+	checkF("Abs", math.Abs(-3), math.Abs(f(-3)))
+	checkF("Copysign", math.Copysign(0, -1), math.Copysign(f(0), f(-1)))
+	checkF("FMA", math.FMA(0.1, 10, -1), math.FMA(f(0.1), f(10), f(-1)))
+
+	checkI("TrailingZeros8", uint64(bits.TrailingZeros8(0)), uint64(bits.TrailingZeros8(uint8(u(0)))))
+	checkI("TrailingZeros16", uint64(bits.TrailingZeros16(0x100)), uint64(bits.TrailingZeros16(uint16(u(0x100)))))
+	checkI("TrailingZeros32", uint64(bits.TrailingZeros32(0)), uint64(bits.TrailingZeros32(uint32(u(0)))))
+	checkI("TrailingZeros64", uint64(bits.TrailingZeros64(1<<40)), uint64(bits.TrailingZeros64(u(1<<40))))
+	checkI("Len", uint64(bits.Len(^uint(0))), uint64(bits.Len(uint(u(uint64(^uint(0)))))))
+	checkI("Len8", uint64(bits.Len8(0x80)), uint64(bits.Len8(uint8(u(0x80)))))
+	checkI("Len16", uint64(bits.Len16(0)), uint64(bits.Len16(uint16(u(0)))))
+	checkI("Len32", uint64(bits.Len32(1<<31)), uint64(bits.Len32(uint32(u(1<<31)))))
+	checkI("Len64", uint64(bits.Len64(1<<63)), uint64(bits.Len64(u(1<<63))))
+	checkI("OnesCount", uint64(bits.OnesCount(^uint(0))), uint64(bits.OnesCount(uint(u(uint64(^uint(0)))))))
+	checkI("OnesCount8", uint64(bits.OnesCount8(0xf0)), uint64(bits.OnesCount8(uint8(u(0xf0)))))
+	checkI("OnesCount16", uint64(bits.OnesCount16(0xffff)), uint64(bits.OnesCount16(uint16(u(0xffff)))))
+	checkI("OnesCount32", uint64(bits.OnesCount32(0x12345678)), uint64(bits.OnesCount32(uint32(u(0x12345678)))))
+	checkI("OnesCount64", uint64(bits.OnesCount64(1<<63|1)), uint64(bits.OnesCount64(u(1<<63|1))))
+	checkI("Reverse", uint64(bits.Reverse(1)), uint64(bits.Reverse(uint(u(1)))))
+	checkI("Reverse8", uint64(bits.Reverse8(1)), uint64(bits.Reverse8(uint8(u(1)))))
+	checkI("Reverse16", uint64(bits.Reverse16(1)), uint64(bits.Reverse16(uint16(u(1)))))
+	checkI("Reverse32", uint64(bits.Reverse32(1)), uint64(bits.Reverse32(uint32(u(1)))))
+	checkI("Reverse64", bits.Reverse64(1), bits.Reverse64(u(1)))
+	checkI("ReverseBytes32", uint64(bits.ReverseBytes32(0x01020304)), uint64(bits.ReverseBytes32(uint32(u(0x01020304)))))
+	checkI("ReverseBytes64", bits.ReverseBytes64(0x0102030405060708), bits.ReverseBytes64(u(0x0102030405060708)))
+	checkI("RotateLeft", uint64(bits.RotateLeft(1, -1)), uint64(bits.RotateLeft(uint(u(1)), int(u(1))*-1)))
+	checkI("RotateLeft8", uint64(bits.RotateLeft8(0x81, 1)), uint64(bits.RotateLeft8(uint8(u(0x81)), int(u(1)))))
+	checkI("RotateLeft16", uint64(bits.RotateLeft16(0x8001, -1)), uint64(bits.RotateLeft16(uint16(u(0x8001)), int(u(1))*-1)))
+	checkI("RotateLeft32", uint64(bits.RotateLeft32(0x80000001, 3)), uint64(bits.RotateLeft32(uint32(u(0x80000001)), int(u(3)))))
+	checkI("RotateLeft64", bits.RotateLeft64(1<<63, 1), bits.RotateLeft64(u(1<<63), int(u(1))))
+
+	if n, r := len([]byte("héllo")), len([]byte(string(rune(u('é')))+"hllo")); n != r {
+		panic(fmt.Sprintf("len([]byte): folded %d, run %d", n, r))
+	}
+	if n, r := len([]rune("héllo")), len([]rune(string(rune(u('é')))+"hllo")); n != r {
+		panic(fmt.Sprintf("len([]rune): folded %d, run %d", n, r))
+	}
+}
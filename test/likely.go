@@ -0,0 +1,42 @@
+// errorcheck
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that //go:likely and //go:unlikely are only accepted on if
+// statements.
+
+package p
+
+//go:likely // ERROR "misplaced compiler directive"
+func f(x int) int {
+	//go:likely
+	if x > 0 {
+		x--
+	} else if x < -10 {
+		x++
+	}
+
+	//go:unlikely
+	if y := x * 2; y == 3 {
+		x = y
+	}
+
+	//go:noinline // ERROR "misplaced compiler directive"
+	if x > 1 {
+		x--
+	}
+
+	//go:likely // ERROR "misplaced compiler directive"
+	for x > 3 {
+		x--
+	}
+
+	//go:likely
+	//go:unlikely
+	if x > 2 { // ERROR "if statement cannot be both //go:likely and //go:unlikely"
+		x--
+	}
+	return x
+}
@@ -0,0 +1,17 @@
+// errorcheck
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that //go:likely and //go:unlikely are diagnosed on
+// statements that take no directives, while parsing.
+
+package p
+
+func g(x int) int {
+	//go:unlikely // ERROR "misplaced compiler directive"
+	switch x {
+	}
+	return x
+}
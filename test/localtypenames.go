@@ -0,0 +1,37 @@
+// run -gcflags=-d=localtypenames
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -d=localtypenames gives function-scope defined types
+// distinct names in reflection.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+)
+
+func f() interface{} {
+	type T int
+	return T(1)
+}
+
+func g() interface{} {
+	type T int
+	return T(2)
+}
+
+type T int
+
+func main() {
+	a, b := reflect.TypeOf(f()).Name(), reflect.TypeOf(g()).Name()
+	if a == b || !strings.HasPrefix(a, "T·") || !strings.HasPrefix(b, "T·") {
+		panic("bad local type names " + a + ", " + b)
+	}
+	if name := reflect.TypeOf(T(3)).Name(); name != "T" {
+		panic("bad type name " + name)
+	}
+}
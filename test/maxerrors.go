@@ -0,0 +1,17 @@
+// errorcheck -e=0 -maxerrors=2
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that the compiler stops once it has reported as many errors
+// as -maxerrors allows. -e=0 undoes the -e that errorcheck passes.
+
+package p
+
+var (
+	_ = a // ERROR "undefined: a"
+	_ = b // ERROR "undefined: b" "too many errors$"
+	_ = c
+	_ = d
+)
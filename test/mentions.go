@@ -0,0 +1,19 @@
+// errorcheck -0 -m -l
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that a diagnostic spells out a large type it mentions more
+// than once only the first time.
+
+package p
+
+var sink interface{}
+
+type T struct{ a, b, c int }
+
+func f(n int) {
+	sink = make([]struct{ a, b, c int }, len(make([]struct{ a, b, c int }, n))) // ERROR "make\(\[\]struct { a int; b int; c int }, len\(make\(\[\]struct{\.\.\.}, n\)\)\) escapes to heap" "make\(\[\]struct { a int; b int; c int }, n\) escapes to heap"
+	sink = make([]T, len(make([]T, n)))                                        // ERROR "make\(\[\]T, len\(make\(\[\]T, n\)\)\) escapes to heap" "make\(\[\]T, n\) escapes to heap"
+}
@@ -0,0 +1,42 @@
+// +build amd64 arm64
+// errorcheck -0 -m=2 -d=strengthreduce
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that multiplications of a loop index by a constant that is not
+// a power of two are strength-reduced, and reported with -m=2.
+
+package p
+
+type T struct{ a, b, c int64 }
+
+type U [7]int64
+
+func sumT(s []T) (r int64) { // ERROR "can inline sumT" "s does not escape"
+	for i := range s { // ERROR "strength-reduced loop index \* 24"
+		r += s[i].b
+	}
+	return
+}
+
+func setU(s []U, x int64) { // ERROR "can inline setU" "s does not escape"
+	for i := 0; i < len(s); i++ { // ERROR "strength-reduced loop index \* 56"
+		s[i][3] = x
+	}
+}
+
+func copyTU(s []T, u []U) { // ERROR "can inline copyTU" "s does not escape" "u does not escape"
+	u = u[:len(s)]
+	for i := range s { // ERROR "strength-reduced loop index \* 24" "strength-reduced loop index \* 56"
+		u[i][0] = s[i].a
+	}
+}
+
+func sum(s []int64) (r int64) { // ERROR "can inline sum" "s does not escape"
+	for i := range s {
+		r += s[i]
+	}
+	return
+}
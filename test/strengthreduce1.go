@@ -0,0 +1,77 @@
+// run -gcflags=-d=strengthreduce
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that strength-reduced loops address the same elements as the
+// original loops, for increasing and decreasing indexes with any start.
+
+package main
+
+import "fmt"
+
+type T struct{ a, b, c int64 }
+
+//go:noinline
+func fill(s []T, lo int) {
+	if lo < 0 {
+		return
+	}
+	for i := lo; i < len(s); i++ {
+		s[i].b = int64(i)
+	}
+}
+
+//go:noinline
+func fillDown(s []T, hi int) {
+	if hi > len(s) {
+		return
+	}
+	for i := hi - 1; i >= 0; i-- {
+		s[i].c = int64(i)
+	}
+}
+
+//go:noinline
+func fillOdd(s []T) {
+	for i := 1; i < len(s); i += 2 {
+		s[i].a = int64(i)
+	}
+}
+
+//go:noinline
+func scale(n int) (r int) {
+	for i := 0; i < n; i++ {
+		r += i * 12
+	}
+	return r
+}
+
+func main() {
+	for n := 0; n < 20; n++ {
+		for lo := 0; lo <= n; lo++ {
+			s := make([]T, n)
+			fill(s, lo)
+			fillDown(s, lo)
+			fillOdd(s)
+			for i, t := range s {
+				var want T
+				if i >= lo {
+					want.b = int64(i)
+				} else {
+					want.c = int64(i)
+				}
+				if i%2 == 1 {
+					want.a = int64(i)
+				}
+				if t != want {
+					panic(fmt.Sprintf("n=%d lo=%d: s[%d] = %v, want %v", n, lo, i, t, want))
+				}
+			}
+		}
+		if got, want := scale(n), 6*n*(n-1); got != want {
+			panic(fmt.Sprintf("scale(%d) = %d, want %d", n, got, want))
+		}
+	}
+}
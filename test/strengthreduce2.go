@@ -0,0 +1,26 @@
+// +build amd64 arm64
+// errorcheck -0 -m=2 -l -d=strengthreduce,ssa/prove/debug=1
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that prove removes the bounds checks of a loop index before
+// the index's multiplications are strength-reduced.
+
+package p
+
+type T struct{ a, b, c int64 }
+
+func sumT(s []T) (r int64) { // ERROR "s does not escape"
+	for i := range s { // ERROR "Induction variable: limits \[0,\?\), increment 1$" "strength-reduced loop index \* 24"
+		r += s[i].b // ERROR "Proved IsInBounds$"
+	}
+	return
+}
+
+func setT(s []T, x int64) { // ERROR "s does not escape"
+	for i := len(s) - 1; i >= 0; i-- { // ERROR "Induction variable: limits \[0,\?\], increment 1$" "strength-reduced loop index \* 24"
+		s[i].c = x // ERROR "Proved IsInBounds$"
+	}
+}
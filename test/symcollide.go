@@ -0,0 +1,34 @@
+// errorcheck -0 -Wsymcollide
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that -Wsymcollide warns about exported names that collide
+// when case and compatibility characters are ignored.
+
+package p
+
+func Foo() {}
+func FOO() {} // ERROR "FOO collides with Foo"
+
+var Ｘ1 int
+var X１ int // ERROR "X１ collides with Ｘ1"
+
+type ℍ int
+type H int // ERROR "H collides with ℍ"
+
+const Kelvin = 1
+const KELVIN = 2 // ERROR "KELVIN collides with Kelvin"
+
+type T struct{}
+
+func (T) Get()     {}
+func (T) GET()     {} // ERROR "T.GET collides with T.Get"
+func (*T) Office() {}
+func (*T) Oﬃce()   {} // ERROR "T.Oﬃce collides with T.Office"
+
+// Unexported names and names in different scopes do not collide.
+func foo()        {}
+func (T) Foo()    {}
+func (T) Kelvin() {}
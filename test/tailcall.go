@@ -0,0 +1,67 @@
+// errorcheck -0 -d=tailcall=2
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test which self tail calls -d=tailcall=2 turns into loops, and the
+// reasons it gives for those it does not.
+
+package p
+
+func sum(n, acc int) int {
+	if n == 0 {
+		return acc
+	}
+	return sum(n-1, acc+n) // ERROR "self tail call turned into a loop"
+}
+
+type T struct{ n int }
+
+func (t *T) count(n int) {
+	if n == 0 {
+		return
+	}
+	t.n++
+	t.count(n - 1) // ERROR "self tail call turned into a loop"
+}
+
+func down(n int) {
+	for n > 10 {
+		if n%2 == 0 {
+			down(n - 1) // ERROR "self tail call turned into a loop"
+			return
+		}
+		n--
+	}
+}
+
+func notTail(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return 1 + notTail(n-1)
+}
+
+func deferred(n int) int {
+	defer func() {}()
+	if n == 0 {
+		return 0
+	}
+	return deferred(n - 1) // ERROR "self tail call not turned into a loop: function has defers"
+}
+
+func addr(p *int, n int) int {
+	x := n
+	if n == 0 {
+		return *p
+	}
+	return addr(&x, n-1) // ERROR "self tail call not turned into a loop: address of x is taken"
+}
+
+func big(a [10]int, n int) int {
+	if n == 0 {
+		return a[0]
+	}
+	return big(a, n-1) // ERROR "self tail call not turned into a loop: parameter a is kept in memory"
+}
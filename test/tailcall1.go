@@ -0,0 +1,82 @@
+// run -gcflags=-d=tailcall
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that self tail calls turned into loops pass their arguments
+// and results correctly, and run in constant stack space.
+
+package main
+
+import "fmt"
+
+//go:noinline
+func sum(n, acc int) int {
+	if n == 0 {
+		return acc
+	}
+	return sum(n-1, acc+n)
+}
+
+// swap passes its parameters to itself in a different order.
+//
+//go:noinline
+func swap(a, b string, n int) string {
+	if n == 0 {
+		return a + b
+	}
+	return swap(b, a, n-1)
+}
+
+// named sets its result before the tail call, which must see it zeroed.
+//
+//go:noinline
+func named(n int) (r int) {
+	if n == 0 {
+		return
+	}
+	r = n
+	return named(n - 1)
+}
+
+type T struct{ n int }
+
+//go:noinline
+func (t *T) count(n int) {
+	if n == 0 {
+		return
+	}
+	t.n++
+	t.count(n - 1)
+}
+
+//go:noinline
+func blank(_ int, n int) int {
+	if n == 0 {
+		return 7
+	}
+	return blank(n, n-1)
+}
+
+func main() {
+	// Without the loops, 1e8 frames would overflow the maximum stack.
+	const deep = 100000000
+	if got, want := sum(deep, 0), deep*(deep+1)/2; got != want {
+		panic(fmt.Sprintf("sum = %d, want %d", got, want))
+	}
+	if got := swap("a", "b", 3); got != "ba" {
+		panic(fmt.Sprintf("swap = %q, want \"ba\"", got))
+	}
+	if got := named(5); got != 0 {
+		panic(fmt.Sprintf("named = %d, want 0", got))
+	}
+	var t T
+	t.count(deep)
+	if t.n != deep {
+		panic(fmt.Sprintf("count = %d, want %d", t.n, deep))
+	}
+	if got := blank(1, 3); got != 7 {
+		panic(fmt.Sprintf("blank = %d, want 7", got))
+	}
+}
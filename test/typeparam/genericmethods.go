@@ -0,0 +1,85 @@
+// run -goexperiment genericmethods -gcflags=-G=3
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test methods with type parameters of their own.
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+type Box[T any] struct{ v T }
+
+func (b *Box[T]) Map[U any](f func(T) U) *Box[U] { return &Box[U]{f(b.v)} }
+
+func (b Box[T]) Pair[U, V any](u U, v V) string { return fmt.Sprint(b.v, "/", u, "/", v) }
+
+func (b *Box[T]) Get() T { return b.v }
+
+type S struct{ n int }
+
+func (s S) Conv[T ~int | ~float64]() T { return T(s.n) }
+
+func (s *S) Twice[T any](x T) [2]T {
+	s.n++
+	return [2]T{x, x}
+}
+
+type E struct {
+	S
+	name string
+}
+
+func Generic[T any](x T) string {
+	b := &Box[T]{x}
+	s := b.Map(func(v T) string { return fmt.Sprint(v) })
+	return s.v + ":" + b.Pair[T, int](x, 1)
+}
+
+func check(got, want interface{}) {
+	if !reflect.DeepEqual(got, want) {
+		panic(fmt.Sprintf("got %v, want %v", got, want))
+	}
+}
+
+func main() {
+	b := &Box[int]{3}
+	check(b.Map[string](strconv.Itoa).v, "3")
+	check(b.Map(func(i int) float64 { return float64(i) / 2 }).v, 1.5)
+	check(b.Pair("a", true), "3/a/true")
+
+	// Method values.
+	f := b.Map[string]
+	g := b.Pair[int, int]
+	b.v = 4
+	check(f(strconv.Itoa).v, "4")
+	check(g(1, 2), "3/1/2")
+
+	// Calls in generic code.
+	check(Generic("a"), "a:a/a/1")
+	check(Generic(2.5), "2.5:2.5/2.5/1")
+
+	// Methods of non-generic types, promoted through embedding.
+	s := S{7}
+	check(s.Conv[float64](), 7.0)
+	check(s.Conv[int](), 7)
+	e := E{S{4}, "e"}
+	check(e.Conv[float64](), 4.0)
+	check(e.Twice("x"), [2]string{"x", "x"})
+	check(e.n, 5)
+
+	// Generic methods are not in the method set.
+	check(reflect.TypeOf(S{}).NumMethod(), 0)
+	check(reflect.TypeOf(&S{}).NumMethod(), 0)
+	check(reflect.TypeOf(b).NumMethod(), 1)
+	var i interface{} = b
+	if _, ok := i.(interface{ Get() int }); !ok {
+		panic("*Box[int] does not implement Get")
+	}
+}
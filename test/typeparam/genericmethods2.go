@@ -0,0 +1,22 @@
+// errorcheck -goexperiment genericmethods -G=3
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Methods with type parameters of their own do not implement
+// interface methods, and interface methods can't have them.
+
+package p
+
+type S struct{}
+
+func (S) M[T any]() {}
+
+type I interface{ M() }
+
+var _ I = S{} // ERROR "S does not implement I"
+
+type J interface {
+	N[T any]() // ERROR "interface method cannot have type parameters"
+}
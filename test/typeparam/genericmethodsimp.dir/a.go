@@ -0,0 +1,17 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package a
+
+type Box[T any] struct{ V T }
+
+func (b *Box[T]) Map[U any](f func(T) U) *Box[U] { return &Box[U]{f(b.V)} }
+
+func (b *Box[T]) Get() T { return b.V }
+
+type S struct{ N int }
+
+func (s S) Conv[T ~int | ~float64]() T { return T(s.N) }
+
+func (s S) Plain() int { return s.N }
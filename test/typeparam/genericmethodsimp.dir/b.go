@@ -0,0 +1,13 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package b
+
+import "a"
+
+func F() (float64, []int, int) {
+	b := &a.Box[int]{3}
+	s := a.S{N: 7}
+	return s.Conv[float64](), b.Map(func(i int) []int { return []int{i, i} }).V, b.Get() + s.Plain()
+}
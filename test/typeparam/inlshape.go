@@ -0,0 +1,30 @@
+// errorcheck -0 -m
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Check that -m shows shapes in readable form, and names the
+// instantiations it inlines by their type arguments.
+
+package p
+
+type MyInt int
+
+func Sum[T ~int | ~float64](xs []T) T { // ERROR "can inline Sum\[shape:int\]"
+	var s T
+	for _, x := range xs {
+		s += x
+	}
+	return s
+}
+
+type Box[T any] struct{ v T }
+
+func (b *Box[T]) Get() T { // ERROR "can inline \(\*Box\[shape:float64\]\).Get"
+	return b.v
+}
+
+func Use(b *Box[float64]) MyInt { // ERROR "can inline Use" "b does not escape"
+	return Sum([]MyInt{1, 2}) + MyInt(b.Get()) // ERROR "inlining call to Sum\[MyInt\]" "inlining call to \(\*Box\[float64\]\).Get" "\[\]MyInt{...} does not escape"
+}
@@ -0,0 +1,25 @@
+// errorcheck -G=3 -instlimit=20
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Check that the compiler reports an error showing the chain of
+// instantiations if a package instantiates generic functions more
+// times than -instlimit allows.
+
+package p
+
+type P[A, B any] struct{}
+
+// Each F doubles the number of instantiations of the next.
+func F0[T any]() { F1[T](); F1[*T]() }
+func F1[T any]() { F2[T](); F2[[]T]() }
+func F2[T any]() { F3[T](); F3[P[T, T]]() }
+func F3[T any]() { F4[T](); F4[map[string]T]() }
+func F4[T any]() { F5[T](); F5[chan T]() }
+func F5[T any]() {}
+
+func G() {
+	F0[int]() // ERROR "too many instantiations of generic functions and methods \(more than 20; see -instlimit\), while instantiating:\n\t.*: F0\[int\]\n\t.*: F1\[int\]\n\t.*: F2\[\[\]int\]\n"
+}
@@ -0,0 +1,76 @@
+// run -gcflags=-G=3
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that generic functions and methods marked //go:stencil work when
+// called with concrete type arguments and from other generic functions.
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type MyInt int
+
+func (m MyInt) String() string { return fmt.Sprintf("MyInt(%d)", int(m)) }
+
+type T struct{ x int }
+
+func (t *T) String() string { return fmt.Sprintf("T(%d)", t.x) }
+
+//go:stencil
+//go:noinline
+func Sum[E ~int](s []E) E {
+	var t E
+	for _, v := range s {
+		t += v
+	}
+	return t
+}
+
+//go:stencil
+func Str[E fmt.Stringer](e E) string { return e.String() }
+
+//go:stencil
+//go:noinline
+func Caller[E any](e E) uintptr {
+	pc, _, _, _ := runtime.Caller(0)
+	return runtime.FuncForPC(pc).Entry()
+}
+
+func Gen[E ~int](s []E) E { return Sum(s) }
+
+type L[E any] struct{ e E }
+
+//go:stencil
+func (l *L[E]) Get() E { return l.e }
+
+func check(got, want interface{}) {
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		panic(fmt.Sprintf("got %v, want %v", got, want))
+	}
+}
+
+func main() {
+	check(Sum([]int{1, 2}), 3)
+	check(Sum([]MyInt{3, 4}), MyInt(7))
+	check(Gen([]MyInt{5, 6}), MyInt(11))
+	check(Str(MyInt(1)), "MyInt(1)")
+	check(Str(&T{2}), "T(2)")
+
+	var g interface{ Get() *T } = &L[*T]{&T{3}}
+	check(g.Get(), &T{3})
+	check((&L[MyInt]{4}).Get(), MyInt(4))
+
+	// Each type argument has its own instantiation.
+	if a, b := Caller(1), Caller(MyInt(1)); a == b {
+		panic(fmt.Sprintf("Caller[int] and Caller[MyInt] both start at %#x", a))
+	}
+	if a, b := Caller(&T{}), Caller(new(int)); a == b {
+		panic(fmt.Sprintf("Caller[*T] and Caller[*int] both start at %#x", a))
+	}
+}
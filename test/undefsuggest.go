@@ -0,0 +1,40 @@
+// errorcheck -G=3
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test the suggestions in errors for undefined names and selectors.
+// Does not compile.
+
+package p
+
+import "fmt"
+
+type S struct {
+	Name  string
+	count int
+	E
+}
+
+type E struct{ inner int }
+
+func (S) Method()   {}
+func (*S) PMethod() {}
+
+func f(s S) {
+	var length int
+	_ = lenght       // ERROR "undefined: lenght \(did you mean length\?\)"
+	_ = lenn(nil)    // ERROR "undefined: lenn \(did you mean len\?\)"
+	var _ strng      // ERROR "undefined: strng \(did you mean string\?\)"
+	var _ Lenght     // ERROR "undefined: Lenght$"
+	_ = fmtt.Println // ERROR "undefined: fmtt \(did you mean fmt\?\)"
+	fmt.Prinln()     // ERROR "undefined: fmt.Prinln \(did you mean fmt.Println\?\)"
+	_ = s.Nmae       // ERROR "type S has no field or method Nmae; did you mean Name\?"
+	_ = s.cont       // ERROR "type S has no field or method cont; did you mean count\?"
+	_ = s.iner       // ERROR "type S has no field or method iner; did you mean inner\?"
+	_ = s.PMethd     // ERROR "type S has no field or method PMethd; did you mean PMethod\?"
+	_ = S{}.PMethd   // ERROR "type S has no field or method PMethd; did you mean Method\?"
+	_ = s.xyz        // ERROR "type S has no field or method xyz\)"
+	_ = length
+}
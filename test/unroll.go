@@ -0,0 +1,79 @@
+// +build amd64,!gcflags_noopt
+// errorcheck -0 -d=unroll
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test which loops -d=unroll unrolls, and the reasons it gives for
+// the loops it does not.
+
+package p
+
+func sum(a []int) int {
+	s := 0
+	for i := range a { // ERROR "loop unrolled by 4"
+		s += a[i]
+	}
+	return s
+}
+
+func dot(a, b []int64) int64 {
+	b = b[:len(a)]
+	var s int64
+	//go:unroll 8
+	for i := 0; i < len(a); i++ { // ERROR "loop unrolled by 8"
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+var g [8]int64
+
+func three() {
+	for i := 0; i < 3; i++ { // ERROR "loop unrolled completely, 3 iterations"
+		g[i] = g[i+1] + 1
+	}
+}
+
+func checked(dst, a []int32) {
+	for i := range dst { // ERROR "loop not unrolled: loop body has bounds checks"
+		dst[i] = a[i] - dst[i]
+	}
+}
+
+func never(a []int) int {
+	s := 0
+	//go:unroll 1
+	for i := range a { // ERROR "loop not unrolled: //go:unroll 1"
+		s += a[i]
+	}
+	return s
+}
+
+func f(int)
+
+func calls(a []int) {
+	for i := range a { // ERROR "loop not unrolled: loop body has calls"
+		f(a[i])
+	}
+}
+
+func big(a []uint64) uint64 {
+	var h uint64
+	for i := range a { // ERROR "loop not unrolled: loop body is too large"
+		x := a[i]
+		h ^= x*0x9e3779b97f4a7c15 + h<<5 + h>>3 + x>>7 + x<<9 + x>>11 + x<<13 + x>>17 + x<<19
+	}
+	return h
+}
+
+func bigUnrolled(a []uint64) uint64 {
+	var h uint64
+	//go:unroll 2
+	for i := range a { // ERROR "loop unrolled by 2"
+		x := a[i]
+		h ^= x*0x9e3779b97f4a7c15 + h<<5 + h>>3 + x>>7 + x<<9 + x>>11 + x<<13 + x>>17 + x<<19
+	}
+	return h
+}
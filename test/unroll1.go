@@ -0,0 +1,118 @@
+// run
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that unrolled loops compute the same results as the original
+// loops, for all lengths and for loops carrying several values.
+
+package main
+
+import "fmt"
+
+//go:noinline
+func sum(a []int) int {
+	s := 0
+	//go:unroll 4
+	for i := range a {
+		s += a[i]
+	}
+	return s
+}
+
+//go:noinline
+func sumRef(a []int) int {
+	s := 0
+	//go:unroll 1
+	for i := range a {
+		s += a[i]
+	}
+	return s
+}
+
+//go:noinline
+func fib(n int) (int, int) {
+	x, y := 0, 1
+	//go:unroll 3
+	for i := 0; i < n; i++ {
+		x, y = y, x+y
+	}
+	return x, y
+}
+
+//go:noinline
+func fibRef(n int) (int, int) {
+	x, y := 0, 1
+	//go:unroll 1
+	for i := 0; i < n; i++ {
+		x, y = y, x+y
+	}
+	return x, y
+}
+
+//go:noinline
+func fill(a []int, lo, hi int) {
+	if lo < 0 {
+		return
+	}
+	a = a[:hi]
+	//go:unroll 5
+	for i := lo; i < hi; i++ {
+		a[i] = i * i
+	}
+}
+
+var g [10]int
+
+//go:noinline
+func shift() {
+	//go:unroll 16
+	for i := 0; i < 9; i++ {
+		g[i] = g[i+1] + i
+	}
+}
+
+func main() {
+	a := make([]int, 40)
+	for i := range a {
+		a[i] = i*7 + 3
+	}
+	for n := 0; n <= len(a); n++ {
+		if got, want := sum(a[:n]), sumRef(a[:n]); got != want {
+			panic(fmt.Sprintf("sum(a[:%d]) = %d, want %d", n, got, want))
+		}
+		x, y := fib(n)
+		wx, wy := fibRef(n)
+		if x != wx || y != wy {
+			panic(fmt.Sprintf("fib(%d) = %d, %d, want %d, %d", n, x, y, wx, wy))
+		}
+		for lo := -1; lo <= n; lo++ {
+			b := make([]int, len(a))
+			fill(b, lo, n)
+			for i, v := range b {
+				want := 0
+				if lo >= 0 && lo <= i && i < n {
+					want = i * i
+				}
+				if v != want {
+					panic(fmt.Sprintf("fill(b, %d, %d): b[%d] = %d, want %d", lo, n, i, v, want))
+				}
+			}
+		}
+	}
+
+	for i := range g {
+		g[i] = i * 10
+	}
+	shift()
+	for i, v := range g {
+		want := (i+1)*10 + i
+		if i == len(g)-1 {
+			want = i * 10
+		}
+		if v != want {
+			panic(fmt.Sprintf("g[%d] = %d, want %d", i, v, want))
+		}
+	}
+}
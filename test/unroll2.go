@@ -0,0 +1,21 @@
+// errorcheck
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Verify that //go:unroll directives without a positive factor are
+// diagnosed while parsing.
+
+package p
+
+func f(a []int) {
+	//go:unroll // ERROR "usage: //go:unroll n"
+	for i := range a {
+		a[i] = 0
+	}
+	//go:unroll 0 // ERROR "usage: //go:unroll n"
+	for i := range a {
+		a[i] = 0
+	}
+}
@@ -0,0 +1,63 @@
+// +build amd64,!gcflags_noopt
+// errorcheck -0 -d=vectorize
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test which loops -d=vectorize vectorizes, and the reasons it gives
+// for the loops it does not.
+
+package p
+
+func add(dst, a, b []int64) {
+	a = a[:len(dst)]
+	b = b[:len(dst)]
+	for i := range dst { // ERROR "loop vectorized: 2 x int64"
+		dst[i] = a[i] + b[i]
+	}
+}
+
+func xor(dst, a []byte) {
+	a = a[:len(dst)]
+	for i := range dst { // ERROR "loop vectorized: 16 x byte"
+		dst[i] ^= a[i]
+	}
+}
+
+func mix(dst, a, b []uint32) {
+	a = a[:len(dst)]
+	b = b[:len(dst)]
+	for i := range dst { // ERROR "loop vectorized: 4 x uint32"
+		dst[i] = (a[i] | b[i]) - a[i]&b[i]
+	}
+}
+
+func sum(a []int) int {
+	s := 0
+	for i := range a { // ERROR "loop not vectorized: loop carries a value other than the index"
+		s += a[i]
+	}
+	return s
+}
+
+func checked(dst, a []int32) {
+	for i := range dst { // ERROR "loop not vectorized: loop body is not a single block"
+		dst[i] = a[i] - dst[i]
+	}
+}
+
+func mul(dst, a []int16) {
+	a = a[:len(dst)]
+	for i := range dst { // ERROR "loop not vectorized: loop body has Mul16"
+		dst[i] = a[i] * a[i]
+	}
+}
+
+var g [8]int64
+
+func short() {
+	for i := 0; i < 1; i++ { // ERROR "loop not vectorized: loop is too short"
+		g[i] = g[i] + g[i]
+	}
+}
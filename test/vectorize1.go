@@ -0,0 +1,113 @@
+// run -goexperiment vectorize
+
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that vectorized loops compute the same results as the
+// original loops, for all lengths and for overlapping slices.
+
+package main
+
+import "fmt"
+
+//go:noinline
+func add(dst, a, b []int64) {
+	a = a[:len(dst)]
+	b = b[:len(dst)]
+	for i := range dst {
+		dst[i] = a[i] + b[i]
+	}
+}
+
+//go:noinline
+func xor(dst, a []byte) {
+	a = a[:len(dst)]
+	for i := range dst {
+		dst[i] ^= a[i]
+	}
+}
+
+//go:noinline
+func mix(dst, a, b []uint32) {
+	a = a[:len(dst)]
+	b = b[:len(dst)]
+	for i := range dst {
+		dst[i] = (a[i] | b[i]) - a[i]&b[i]
+	}
+}
+
+//go:noinline
+func sub16(dst, a []int16) {
+	a = a[:len(dst)]
+	for i := range dst {
+		dst[i] = a[i] - dst[i]
+	}
+}
+
+func main() {
+	for n := 0; n < 40; n++ {
+		a := make([]int64, n)
+		b := make([]int64, n)
+		for i := range a {
+			a[i] = int64(i) * 0x123456789
+			b[i] = -int64(i) << 40
+		}
+		dst := make([]int64, n)
+		add(dst, a, b)
+		for i := range dst {
+			if want := a[i] + b[i]; dst[i] != want {
+				panic(fmt.Sprintf("add n=%d: dst[%d] = %d, want %d", n, i, dst[i], want))
+			}
+		}
+
+		x := make([]uint32, n)
+		y := make([]uint32, n)
+		for i := range x {
+			x[i] = uint32(i * 0x9e3779b9)
+			y[i] = uint32(i * 0x85ebca6b)
+		}
+		z := make([]uint32, n)
+		mix(z, x, y)
+		for i := range z {
+			if want := x[i] ^ y[i]; z[i] != want {
+				panic(fmt.Sprintf("mix n=%d: z[%d] = %#x, want %#x", n, i, z[i], want))
+			}
+		}
+
+		p := make([]int16, n)
+		q := make([]int16, n)
+		for i := range p {
+			p[i] = int16(i * 1000)
+			q[i] = int16(i * 7)
+		}
+		sub16(q, p)
+		for i := range q {
+			if want := int16(i*1000 - i*7); q[i] != want {
+				panic(fmt.Sprintf("sub16 n=%d: q[%d] = %d, want %d", n, i, q[i], want))
+			}
+		}
+	}
+
+	// Overlapping slices, shifted both ways, by less and more than a
+	// vector.
+	for shift := -20; shift <= 20; shift++ {
+		buf := make([]byte, 100)
+		want := make([]byte, 100)
+		for i := range buf {
+			buf[i] = byte(i*31 + 7)
+			want[i] = buf[i]
+		}
+		d, s := 20, 20+shift
+		dst, src := buf[d:d+60], buf[s:s+60]
+		for i := 0; i < 60; i++ {
+			want[d+i] ^= want[s+i]
+		}
+		xor(dst, src)
+		for i := range buf {
+			if buf[i] != want[i] {
+				panic(fmt.Sprintf("xor shift=%d: buf[%d] = %d, want %d", shift, i, buf[i], want[i]))
+			}
+		}
+	}
+}